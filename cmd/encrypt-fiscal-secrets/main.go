@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/config"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/respositories"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/secrets"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+// encrypt-fiscal-secrets roda uma vez, depois da migração 00030, e cifra com a mesma chave da API
+// a senha do certificado e o token CSC dos perfis fiscais gravados em texto puro
+func main() {
+	config.LoadEnvs()
+
+	secretCipher, err := secrets.NewAesGcmCipher(config.Env.SecretsEncryptionKey)
+	if err != nil {
+		log.Fatalf("❌ Failed to load secrets encryption key: %v", err)
+	}
+
+	db, err := database.New()
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("⚠️ Failed to close database connection: %v", err)
+		}
+	}()
+
+	encrypted, err := respositories.NewFiscalProfileRepository(db, secretCipher).EncryptLegacySecrets()
+	if err != nil {
+		log.Printf("❌ Encryption stopped: %v", err)
+	}
+
+	log.Printf("🔐 %d fiscal profiles encrypted", encrypted)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/config"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/addresses"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/geocoding"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/respositories"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+// geocode-backfill preenche as coordenadas dos endereços de restaurantes e clientes gravados em 0,0,
+// usando o mesmo provedor e o mesmo geocode_cache da API
+func main() {
+	limit := flag.Int("limit", 0, "maximum addresses to process, 0 for all")
+	batchSize := flag.Int("batch-size", 100, "addresses loaded per query")
+	// o Nominatim público aceita no máximo uma requisição por segundo
+	interval := flag.Duration("interval", time.Second, "pause between geocoding requests")
+	flag.Parse()
+
+	config.LoadEnvs()
+
+	db, err := database.New()
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("⚠️ Failed to close database connection: %v", err)
+		}
+	}()
+
+	geocoder, err := geocoding.New(geocoding.Config{
+		Provider: config.Env.GeocoderProvider,
+		ApiUrl:   config.Env.GeocoderUrl,
+		ApiKey:   config.Env.GeocoderApiKey,
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to configure geocoder: %v", err)
+	}
+
+	addressUseCase := usecase.NewAddressUseCase(
+		addresses.NewOfflineCepLookup(),
+		respositories.NewAddressRepository(db),
+		geocoding.NewCachedGeocoder(geocoder, respositories.NewGeocodeCacheRepository(db)),
+	)
+
+	result, err := addressUseCase.BackfillCoordinates(usecase.GeocodeBackfillPayload{
+		BatchSize: *batchSize,
+		Limit:     *limit,
+		Interval:  *interval,
+	})
+	if err != nil {
+		log.Printf("❌ Backfill stopped: %v", err)
+	}
+
+	log.Printf(
+		"📍 %d addresses processed: %d geocoded, %d not found, %d failed",
+		result.Processed, result.Geocoded, result.NotFound, result.Failed,
+	)
+}
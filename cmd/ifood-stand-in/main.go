@@ -0,0 +1,24 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/marketplace"
+)
+
+// ifood-stand-in sobe uma Merchant API do iFood local para desenvolvimento, apontada pela
+// variável IFOOD_API_URL; pedidos de teste são colocados com POST /stand-in/orders
+func main() {
+	addr := flag.String("addr", ":8090", "listen address")
+	clientId := flag.String("client-id", "stand-in", "client id accepted by the token endpoint")
+	clientSecret := flag.String("client-secret", "stand-in-secret", "client secret accepted by the token endpoint")
+	flag.Parse()
+
+	log.Printf("🛵 iFood stand-in listening on %s", *addr)
+
+	if err := http.ListenAndServe(*addr, marketplace.NewStandInIfood(*clientId, *clientSecret)); err != nil {
+		log.Fatalf("❌ Failed to start iFood stand-in: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/fiscal"
+)
+
+// sefaz-stand-in sobe um autorizador de NFC-e local para desenvolvimento,
+// apontado pela variável SEFAZ_NFCE_AUTHORIZATION_URL
+func main() {
+	addr := flag.String("addr", ":8089", "listen address")
+	flag.Parse()
+
+	log.Printf("🧾 SEFAZ stand-in listening on %s", *addr)
+
+	if err := http.ListenAndServe(*addr, fiscal.NewStandInSefaz()); err != nil {
+		log.Fatalf("❌ Failed to start SEFAZ stand-in: %v", err)
+	}
+}
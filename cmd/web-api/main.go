@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -11,9 +12,19 @@ import (
 	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
 	"github.com/PedroNetto404/marmitech-backend/internal/config"
 	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/addresses"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/events"
 	"github.com/PedroNetto404/marmitech-backend/internal/infra/files"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/fiscal"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/geocoding"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/marketplace"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/notifications"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/printing"
 	"github.com/PedroNetto404/marmitech-backend/internal/infra/respositories"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/secrets"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/webhooks"
 	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	notificationchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_channel"
 	"github.com/PedroNetto404/marmitech-backend/pkg/middleware"
 	"github.com/PedroNetto404/marmitech-backend/internal/infra/auth"
 )
@@ -56,7 +67,15 @@ func main() {
 	} else {
 		blockStorage = files.NewDiskStorage(config.Env.DiskStoragePath)
 	}
+	secretCipher, err := secrets.NewAesGcmCipher(config.Env.SecretsEncryptionKey)
+	if err != nil {
+		log.Fatalf("❌ Failed to load secrets encryption key: %v", err)
+	}
 	authService := auth.NewJwtService(config.Env.JwtSecretKey, config.Env.JwtIssuer, config.Env.JwtAudience, config.Env.JwtExpirationMinutes)
+	authentication := func(c *gin.Context) { c.Next() }
+	if config.Env.IsAuthEnabled() {
+		authentication = middleware.Authentication(authService)
+	}
 
 	// Repositories
 	db, err := database.New()
@@ -73,11 +92,99 @@ func main() {
 	categoryRepository := respositories.NewCategoryRepository(db)			
 	productRepository := respositories.NewProductRepository(db)
 	userRepository := respositories.NewUserRepository(db)
+	auditLogRepository := respositories.NewAuditLogRepository(db)
+	customerRepository := respositories.NewCustomerRepository(db)
+	idempotencyRepository := respositories.NewIdempotencyRepository(db)
+	menuRepository := respositories.NewMenuRepository(db)
+	customerTabRepository := respositories.NewCustomerTabRepository(db)
+	orderRepository := respositories.NewOrderRepository(db)
+	fiscalProfileRepository := respositories.NewFiscalProfileRepository(db, secretCipher)
+	fiscalDocumentRepository := respositories.NewFiscalDocumentRepository(db)
+	promotionRepository := respositories.NewPromotionRepository(db)
+	loyaltyProgramRepository := respositories.NewLoyaltyProgramRepository(db)
+	loyaltyAccountRepository := respositories.NewLoyaltyAccountRepository(db)
+	stockItemRepository := respositories.NewStockItemRepository(db)
+	recipeRepository := respositories.NewRecipeRepository(db)
+	reportRepository := respositories.NewReportRepository(db)
+	cashSessionRepository := respositories.NewCashSessionRepository(db)
+	supplierRepository := respositories.NewSupplierRepository(db)
+	billRepository := respositories.NewBillRepository(db)
+	notificationPreferencesRepository := respositories.NewNotificationPreferencesRepository(db)
+	notificationRepository := respositories.NewNotificationRepository(db)
+	webhookSubscriptionRepository := respositories.NewWebhookSubscriptionRepository(db)
+	webhookDeliveryRepository := respositories.NewWebhookDeliveryRepository(db)
+	marketplaceIntegrationRepository := respositories.NewMarketplaceIntegrationRepository(db)
+	marketplaceMappingRepository := respositories.NewMarketplaceMappingRepository(db)
+	marketplaceOrderRepository := respositories.NewMarketplaceOrderRepository(db)
+	marketplacePushRepository := respositories.NewMarketplacePushRepository(db)
+	addressRepository := respositories.NewAddressRepository(db)
+	geocodeCacheRepository := respositories.NewGeocodeCacheRepository(db)
+	deliveryZoneRepository := respositories.NewDeliveryZoneRepository(db)
+	driverRepository := respositories.NewDriverRepository(db)
+	driverLocationRepository := respositories.NewDriverLocationRepository(db)
+	deliveryRouteRepository := respositories.NewDeliveryRouteRepository(db)
+	eventOutboxRepository := respositories.NewEventOutboxRepository(db)
+
+	eventBus := events.NewOutboxEventBus(eventOutboxRepository, events.NewInMemoryEventBus())
 	// Use Cases
-	restaurantUseCase := usecase.NewRestaurantUseCase(restaurantRepository, blockStorage)
+	addressLookup := newAddressLookup()
+	geocoder, err := geocoding.New(geocoding.Config{
+		Provider: config.Env.GeocoderProvider,
+		ApiUrl:   config.Env.GeocoderUrl,
+		ApiKey:   config.Env.GeocoderApiKey,
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to configure geocoder: %v", err)
+	}
+	geocoder = geocoding.NewCachedGeocoder(geocoder, geocodeCacheRepository)
+	restaurantUseCase := usecase.NewRestaurantUseCase(restaurantRepository, blockStorage, addressLookup, geocoder)
 	dishUseCase := usecase.NewDishUseCase(dishRepository, restaurantRepository, blockStorage)
 	categoryUseCase := usecase.NewCategoryUseCase(categoryRepository, restaurantRepository, blockStorage)
-	productUseCase := usecase.NewProductUseCase(productRepository, categoryRepository, restaurantRepository, blockStorage)
+	productUseCase := usecase.NewProductUseCase(productRepository, categoryRepository, restaurantRepository, blockStorage, eventBus)
+	auditUseCase := usecase.NewAuditUseCase(auditLogRepository)
+	customerUseCase := usecase.NewCustomerUseCase(customerRepository, addressLookup, geocoder)
+	customerTabUseCase := usecase.NewCustomerTabUseCase(customerTabRepository, customerRepository, restaurantRepository, orderRepository)
+	orderUseCase := usecase.NewOrderUseCase(orderRepository, productRepository, customerRepository, customerTabRepository, restaurantRepository, menuRepository, promotionRepository, loyaltyProgramRepository, loyaltyAccountRepository, cashSessionRepository, addressLookup, geocoder, deliveryZoneRepository, eventBus)
+	kitchenUseCase := usecase.NewKitchenUseCase(orderRepository, eventBus)
+	ticketUseCase := usecase.NewTicketUseCase(orderRepository, restaurantRepository, printing.NewEscPosTicketRenderer(), printing.NewPdfReceiptRenderer(), blockStorage)
+	fiscalDocumentIssuer := fiscal.NewSefazFiscalDocumentIssuer(fiscal.SefazEndpoints{
+		AuthorizationUrl: config.Env.SefazNfceAuthorizationUrl,
+		QrCodeUrl:        config.Env.SefazNfceQrCodeUrl,
+		ConsultUrl:       config.Env.SefazNfceConsultUrl,
+	})
+	fiscalDocumentUseCase := usecase.NewFiscalDocumentUseCase(fiscalProfileRepository, fiscalDocumentRepository, orderRepository, productRepository, restaurantRepository, fiscalDocumentIssuer, blockStorage)
+	promotionUseCase := usecase.NewPromotionUseCase(promotionRepository, productRepository, restaurantRepository)
+	loyaltyUseCase := usecase.NewLoyaltyUseCase(loyaltyProgramRepository, loyaltyAccountRepository, orderRepository, productRepository, categoryRepository, restaurantRepository, eventBus)
+	stopLoyalty := loyaltyUseCase.Listen()
+	defer stopLoyalty()
+	inventoryUseCase := usecase.NewInventoryUseCase(stockItemRepository, recipeRepository, orderRepository, productRepository, dishRepository, restaurantRepository, eventBus)
+	stopInventory := inventoryUseCase.Listen()
+	defer stopInventory()
+	menuUseCase := usecase.NewMenuUseCase(menuRepository)
+	reportUseCase := usecase.NewReportUseCase(reportRepository, restaurantRepository)
+	marginUseCase := usecase.NewMarginUseCase(productRepository, categoryRepository, recipeRepository, stockItemRepository, reportRepository, restaurantRepository)
+	cashRegisterUseCase := usecase.NewCashRegisterUseCase(cashSessionRepository, restaurantRepository, eventBus)
+	supplierUseCase := usecase.NewSupplierUseCase(supplierRepository, restaurantRepository)
+	billUseCase := usecase.NewBillUseCase(billRepository, supplierRepository, restaurantRepository)
+	cashFlowUseCase := usecase.NewCashFlowUseCase(billRepository, customerTabRepository, orderRepository, restaurantRepository)
+	notificationUseCase := usecase.NewNotificationUseCase(notificationPreferencesRepository, notificationRepository, orderRepository, customerRepository, restaurantRepository, notifications.NewTemplateRenderer(), newNotifiers(), eventBus)
+	stopNotifications := notificationUseCase.Listen()
+	defer stopNotifications()
+	webhookUseCase := usecase.NewWebhookUseCase(webhookSubscriptionRepository, webhookDeliveryRepository, orderRepository, productRepository, stockItemRepository, cashSessionRepository, restaurantRepository, webhooks.NewHttpWebhookSender(), eventBus)
+	stopWebhooks := webhookUseCase.Listen()
+	defer stopWebhooks()
+	marketplaceUseCase := usecase.NewMarketplaceUseCase(marketplaceIntegrationRepository, marketplaceMappingRepository, marketplaceOrderRepository, marketplacePushRepository, orderRepository, productRepository, restaurantRepository, newMarketplaceProviders(), eventBus)
+	stopMarketplaces := marketplaceUseCase.Listen()
+	defer stopMarketplaces()
+	addressUseCase := usecase.NewAddressUseCase(addressLookup, addressRepository, geocoder)
+	deliveryZoneUseCase := usecase.NewDeliveryZoneUseCase(deliveryZoneRepository, restaurantRepository, addressLookup, geocoder)
+	driverUseCase := usecase.NewDriverUseCase(driverRepository, driverLocationRepository, deliveryRouteRepository, restaurantRepository)
+	deliveryRouteUseCase := usecase.NewDeliveryRouteUseCase(deliveryRouteRepository, driverRepository, driverLocationRepository, orderRepository, blockStorage, eventBus)
+	catalogUseCase := usecase.NewCatalogUseCase(restaurantRepository, categoryRepository, productRepository, menuRepository)
+
+	idempotencyTtl := time.Duration(config.Env.IdempotencyTtlMinutes) * time.Minute
+	idempotency := middleware.Idempotency(idempotencyRepository, idempotencyTtl)
+	go purgeExpiredIdempotencyKeys(idempotencyRepository)
 	userUseCase := usecase.NewUserUseCase(userRepository, authService)
 
 	// Routers
@@ -87,6 +194,33 @@ func main() {
 		productUseCase, 
 		dishUseCase, 
 		restaurantUseCase, 
+		auditUseCase,
+		customerUseCase,
+		customerTabUseCase,
+		orderUseCase,
+		kitchenUseCase,
+		ticketUseCase,
+		fiscalDocumentUseCase,
+		promotionUseCase,
+		loyaltyUseCase,
+		inventoryUseCase,
+		menuUseCase,
+		reportUseCase,
+		marginUseCase,
+		cashRegisterUseCase,
+		supplierUseCase,
+		billUseCase,
+		cashFlowUseCase,
+		notificationUseCase,
+		webhookUseCase,
+		marketplaceUseCase,
+		addressUseCase,
+		deliveryZoneUseCase,
+		driverUseCase,
+		deliveryRouteUseCase,
+		catalogUseCase,
+		authentication,
+		idempotency,
 		userUseCase,
 	)
 
@@ -98,3 +232,78 @@ func main() {
 		log.Fatalf("❌ Failed to start server: %v", err)
 	}
 }
+
+func purgeExpiredIdempotencyKeys(repository ports.IIdempotencyRepository) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := repository.DeleteExpired(time.Now()); err != nil {
+			log.Printf("⚠️ Failed to purge expired idempotency keys: %v", err)
+		}
+	}
+}
+
+// newNotifiers liga só os canais com credenciais no ambiente
+func newNotifiers() []ports.INotifier {
+	if config.Env.IsNotificationsFakeSink() {
+		return []ports.INotifier{
+			notifications.NewFakeNotifier(notificationchannel.EMAIL),
+			notifications.NewFakeNotifier(notificationchannel.WHATSAPP),
+			notifications.NewFakeNotifier(notificationchannel.SMS),
+		}
+	}
+
+	notifiers := make([]ports.INotifier, 0, 3)
+	if config.Env.SmtpHost != "" {
+		notifiers = append(notifiers, notifications.NewSmtpNotifier(notifications.SmtpConfig{
+			Host:     config.Env.SmtpHost,
+			Port:     config.Env.SmtpPort,
+			User:     config.Env.SmtpUser,
+			Password: config.Env.SmtpPass,
+			From:     config.Env.SmtpFrom,
+		}))
+	}
+	if config.Env.WhatsAppAccessToken != "" {
+		notifiers = append(notifiers, notifications.NewWhatsAppNotifier(notifications.WhatsAppConfig{
+			ApiUrl:        config.Env.WhatsAppApiUrl,
+			PhoneNumberId: config.Env.WhatsAppPhoneNumberId,
+			AccessToken:   config.Env.WhatsAppAccessToken,
+		}))
+	}
+	if config.Env.SmsApiUrl != "" {
+		notifiers = append(notifiers, notifications.NewSmsNotifier(notifications.SmsConfig{
+			ApiUrl:   config.Env.SmsApiUrl,
+			ApiToken: config.Env.SmsApiToken,
+			Sender:   config.Env.SmsSender,
+		}))
+	}
+
+	return notifiers
+}
+
+// newMarketplaceProviders liga só os marketplaces com credenciais no ambiente
+func newMarketplaceProviders() []ports.IMarketplaceProvider {
+	providers := make([]ports.IMarketplaceProvider, 0, 1)
+	if config.Env.IfoodClientId != "" {
+		providers = append(providers, marketplace.NewIfoodProvider(marketplace.IfoodConfig{
+			ApiUrl:       config.Env.IfoodApiUrl,
+			ClientId:     config.Env.IfoodClientId,
+			ClientSecret: config.Env.IfoodClientSecret,
+		}))
+	}
+
+	return providers
+}
+
+// newAddressLookup cai na faixa de CEP por UF quando o ViaCEP não responde
+func newAddressLookup() ports.IAddressLookup {
+	if config.Env.ViaCepUrl == "" {
+		return addresses.NewOfflineCepLookup()
+	}
+
+	return addresses.NewFallbackAddressLookup(
+		addresses.NewViaCepLookup(config.Env.ViaCepUrl),
+		addresses.NewOfflineCepLookup(),
+	)
+}
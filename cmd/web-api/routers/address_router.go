@@ -0,0 +1,37 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAddressRoutes fica também nas rotas públicas, para o checkout do catálogo completar o endereço
+func RegisterAddressRoutes(routerGroup *gin.RouterGroup, addressUseCase usecase.IAddressUseCase) {
+	group := routerGroup.Group("/addresses")
+	group.GET("/cep/:cep", lookupCep(addressUseCase))
+}
+
+// lookupCep responde sem cidade quando o serviço de CEP está fora e só a UF pôde ser resolvida
+func lookupCep(useCase usecase.IAddressUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address, err := useCase.LookupCep(c.Param("cep"))
+		if err != nil {
+			if errors.Is(err, ports.ErrInvalidCep) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, ports.ErrCepNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, address)
+	}
+}
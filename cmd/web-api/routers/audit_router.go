@@ -0,0 +1,78 @@
+package routers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAuditRoutes(
+	routerGroup *gin.RouterGroup,
+	auditUseCase usecase.IAuditUseCase,
+) {
+	group := routerGroup.Group("/audit")
+	group.GET("/", getAuditLogs(auditUseCase))
+}
+
+func getAuditLogs(useCase usecase.IAuditUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := ports.AuditLogQuery{
+			RestaurantId:  c.Param("restaurantId"),
+			AggregateType: c.Query("aggregate_type"),
+			AggregateId:   c.Query("aggregate_id"),
+			ActorEmail:    c.Query("actor"),
+			Action:        aggregates.AuditAction(c.Query("action")),
+			Limit:         50,
+			Offset:        0,
+		}
+
+		if limit := c.Query("limit"); limit != "" {
+			value, err := strconv.Atoi(limit)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+				return
+			}
+			query.Limit = value
+		}
+
+		if offset := c.Query("offset"); offset != "" {
+			value, err := strconv.Atoi(offset)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+				return
+			}
+			query.Offset = value
+		}
+
+		if from := c.Query("from"); from != "" {
+			value, err := time.Parse(time.RFC3339, from)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected RFC3339"})
+				return
+			}
+			query.From = &value
+		}
+
+		if to := c.Query("to"); to != "" {
+			value, err := time.Parse(time.RFC3339, to)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected RFC3339"})
+				return
+			}
+			query.To = &value
+		}
+
+		logs, err := useCase.Find(query)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, logs)
+	}
+}
@@ -0,0 +1,164 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	billstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_status"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterBillRoutes(
+	routerGroup *gin.RouterGroup,
+	billUseCase usecase.IBillUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/bills", requireManager)
+	group.POST("/", idempotency, createBill(billUseCase))
+	group.GET("/", getBills(billUseCase))
+	group.GET("/:id", getBillById(billUseCase))
+	group.PUT("/:id", updateBill(billUseCase))
+	group.POST("/:id/payments", idempotency, payBill(billUseCase))
+	group.POST("/:id/cancel", cancelBill(billUseCase))
+}
+
+func createBill(useCase usecase.IBillUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.BillPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		bill, err := useCase.Create(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondBillError(c, err)
+			return
+		}
+
+		setETag(c, bill.Version)
+		c.JSON(http.StatusCreated, bill)
+	}
+}
+
+func getBills(useCase usecase.IBillUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bills, err := useCase.Find(c.Param("restaurantId"), usecase.BillFilterPayload{
+			Status:     billstatus.BillStatus(c.Query("status")),
+			SupplierId: c.Query("supplier_id"),
+			From:       c.Query("from"),
+			To:         c.Query("to"),
+		})
+		if err != nil {
+			respondBillError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, bills)
+	}
+}
+
+func getBillById(useCase usecase.IBillUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bill, err := useCase.FindById(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondBillError(c, err)
+			return
+		}
+
+		setETag(c, bill.Version)
+		c.JSON(http.StatusOK, bill)
+	}
+}
+
+func updateBill(useCase usecase.IBillUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.BillPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		bill, err := useCase.Update(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondBillError(c, err)
+			return
+		}
+
+		setETag(c, bill.Version)
+		c.JSON(http.StatusOK, bill)
+	}
+}
+
+func payBill(useCase usecase.IBillUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.BillPaymentPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		bill, err := useCase.Pay(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondBillError(c, err)
+			return
+		}
+
+		setETag(c, bill.Version)
+		c.JSON(http.StatusCreated, bill)
+	}
+}
+
+func cancelBill(useCase usecase.IBillUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bill, err := useCase.Cancel(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondBillError(c, err)
+			return
+		}
+
+		setETag(c, bill.Version)
+		c.JSON(http.StatusOK, bill)
+	}
+}
+
+func respondBillError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrBillNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrBillNotOpen) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidBill) ||
+		errors.Is(err, usecase.ErrInvalidBillPayment) ||
+		errors.Is(err, usecase.ErrSupplierNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
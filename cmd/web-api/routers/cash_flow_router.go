@@ -0,0 +1,66 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCashFlowRoutes(
+	routerGroup *gin.RouterGroup,
+	cashFlowUseCase usecase.ICashFlowUseCase,
+) {
+	group := routerGroup.Group("/cash-flow", requireManager)
+	group.GET("/", getCashFlow(cashFlowUseCase))
+}
+
+// getCashFlow aceita ?days=, ?tz= e ?opening_balance=, o saldo em caixa hoje
+func getCashFlow(useCase usecase.ICashFlowUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload := usecase.CashFlowPayload{TimeZone: c.Query("tz")}
+
+		if value := c.Query("days"); value != "" {
+			days, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a number"})
+				return
+			}
+			payload.Days = days
+		}
+
+		if value := c.Query("opening_balance"); value != "" {
+			openingBalance, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "opening_balance must be a number"})
+				return
+			}
+			payload.OpeningBalance = openingBalance
+		}
+
+		cashFlow, err := useCase.Project(c.Param("restaurantId"), payload)
+		if err != nil {
+			respondCashFlowError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, cashFlow)
+	}
+}
+
+func respondCashFlowError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidCashFlowPeriod) ||
+		errors.Is(err, usecase.ErrInvalidTimeZone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,224 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCashRoutes(
+	routerGroup *gin.RouterGroup,
+	cashRegisterUseCase usecase.ICashRegisterUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/cash")
+	group.POST("/sessions", idempotency, openCashSession(cashRegisterUseCase))
+	group.GET("/sessions", getCashSessions(cashRegisterUseCase))
+	group.GET("/sessions/current", getCurrentCashSession(cashRegisterUseCase))
+	group.GET("/sessions/:id", getCashSessionById(cashRegisterUseCase))
+	group.POST("/sessions/:id/movements", idempotency, addCashMovement(cashRegisterUseCase))
+	group.POST("/sessions/:id/close", closeCashSession(cashRegisterUseCase))
+
+	// a conferência dos caixas é do gerente
+	reportGroup := group.Group("/reports", requireManager)
+	reportGroup.GET("/shifts", getCashShiftDiscrepancies(cashRegisterUseCase))
+	reportGroup.GET("/operators", getCashOperatorDiscrepancies(cashRegisterUseCase))
+}
+
+func openCashSession(useCase usecase.ICashRegisterUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.CashSessionPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		session, err := useCase.Open(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondCashError(c, err)
+			return
+		}
+
+		setETag(c, session.Version)
+		c.JSON(http.StatusCreated, session)
+	}
+}
+
+func getCashSessions(useCase usecase.ICashRegisterUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessions, err := useCase.Find(c.Param("restaurantId"), reportPeriodFromQuery(c), c.Query("operator"))
+		if err != nil {
+			respondCashError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, sessions)
+	}
+}
+
+func getCurrentCashSession(useCase usecase.ICashRegisterUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session, err := useCase.FindCurrent(actorFromContext(c), c.Param("restaurantId"))
+		if err != nil {
+			respondCashError(c, err)
+			return
+		}
+
+		setETag(c, session.Version)
+		c.JSON(http.StatusOK, session)
+	}
+}
+
+func getCashSessionById(useCase usecase.ICashRegisterUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session, err := useCase.FindById(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondCashError(c, err)
+			return
+		}
+
+		setETag(c, session.Version)
+		c.JSON(http.StatusOK, session)
+	}
+}
+
+func addCashMovement(useCase usecase.ICashRegisterUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.CashMovementPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		session, err := useCase.AddMovement(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondCashError(c, err)
+			return
+		}
+
+		setETag(c, session.Version)
+		c.JSON(http.StatusCreated, session)
+	}
+}
+
+func closeCashSession(useCase usecase.ICashRegisterUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.CashClosePayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		session, err := useCase.Close(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondCashError(c, err)
+			return
+		}
+
+		setETag(c, session.Version)
+		c.JSON(http.StatusOK, session)
+	}
+}
+
+func getCashShiftDiscrepancies(useCase usecase.ICashRegisterUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shifts, err := useCase.ShiftDiscrepancies(c.Param("restaurantId"), reportPeriodFromQuery(c), c.Query("operator"))
+		if err != nil {
+			respondCashError(c, err)
+			return
+		}
+
+		respondReport(c, "cash-shifts", shifts,
+			[]string{"session_id", "operator", "opened_at", "closed_at", "closed_by", "expected", "counted", "difference", "note"},
+			func(row dtos.CashShiftDto) []string {
+				return []string{
+					row.SessionId,
+					row.Operator,
+					row.OpenedAt.Format(time.RFC3339),
+					row.ClosedAt.Format(time.RFC3339),
+					row.ClosedBy,
+					formatDecimal(row.Expected),
+					formatDecimal(row.Counted),
+					formatDecimal(row.Difference),
+					row.Note,
+				}
+			})
+	}
+}
+
+func getCashOperatorDiscrepancies(useCase usecase.ICashRegisterUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operators, err := useCase.OperatorDiscrepancies(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondCashError(c, err)
+			return
+		}
+
+		respondReport(c, "cash-operators", operators,
+			[]string{"operator", "shifts", "shifts_off", "expected", "counted", "difference", "shortage", "overage"},
+			func(row dtos.CashOperatorDto) []string {
+				return []string{
+					row.Operator,
+					strconv.Itoa(row.Shifts),
+					strconv.Itoa(row.ShiftsOff),
+					formatDecimal(row.Expected),
+					formatDecimal(row.Counted),
+					formatDecimal(row.Difference),
+					formatDecimal(row.Shortage),
+					formatDecimal(row.Overage),
+				}
+			})
+	}
+}
+
+func respondCashError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrCashSessionNotFound) ||
+		errors.Is(err, usecase.ErrCashSessionNotOpen) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrCashSessionForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, ports.ErrCashSessionAlreadyOpen) ||
+		errors.Is(err, usecase.ErrCashSessionClosed) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidReportPeriod) ||
+		errors.Is(err, usecase.ErrInvalidTimeZone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidCashSession) ||
+		errors.Is(err, usecase.ErrInvalidCashMovement) ||
+		errors.Is(err, usecase.ErrInvalidCashCount) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,56 @@
+package routers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+const catalogCacheControl = "public, max-age=60"
+
+func RegisterCatalogRoutes(
+	routerGroup *gin.RouterGroup,
+	catalogUseCase usecase.ICatalogUseCase,
+) {
+	group := routerGroup.Group("/restaurants")
+	group.GET("/:slug/catalog", getCatalog(catalogUseCase))
+}
+
+func getCatalog(useCase usecase.ICatalogUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		catalog, err := useCase.GetBySlug(c.Param("slug"))
+		if err != nil {
+			if errors.Is(err, usecase.ErrRestaurantNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		body, err := json.Marshal(catalog)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		// ETag forte calculado sobre o corpo: muda sempre que qualquer item visível do catálogo mudar
+		hash := sha256.Sum256(body)
+		etag := `"` + hex.EncodeToString(hash[:]) + `"`
+
+		c.Header("ETag", etag)
+		c.Header("Cache-Control", catalogCacheControl)
+
+		if c.GetHeader("If-None-Match") == etag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
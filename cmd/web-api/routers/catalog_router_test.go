@@ -0,0 +1,73 @@
+package routers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/cmd/web-api/routers"
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCatalogUseCase struct {
+	catalog *dtos.CatalogDto
+}
+
+func (u *fakeCatalogUseCase) GetBySlug(slug string) (*dtos.CatalogDto, error) {
+	if u.catalog.Restaurant.Slug != slug {
+		return nil, usecase.ErrRestaurantNotFound
+	}
+	return u.catalog, nil
+}
+
+func newCatalogEngine(catalog *dtos.CatalogDto) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	routers.RegisterCatalogRoutes(engine.Group("/public"), &fakeCatalogUseCase{catalog: catalog})
+	return engine
+}
+
+func getCatalog(engine *gin.Engine, slug, ifNoneMatch string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/public/restaurants/"+slug+"/catalog", nil)
+	if ifNoneMatch != "" {
+		request.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	engine.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestGetCatalogIsCacheFriendly(t *testing.T) {
+	// arrange
+	catalog := &dtos.CatalogDto{Restaurant: dtos.CatalogRestaurantDto{Slug: "marmitaria-da-ana"}}
+	engine := newCatalogEngine(catalog)
+
+	// act
+	first := getCatalog(engine, "marmitaria-da-ana", "")
+	revalidated := getCatalog(engine, "marmitaria-da-ana", first.Header().Get("ETag"))
+	catalog.Restaurant.TradeName = "Marmitaria da Ana"
+	changed := getCatalog(engine, "marmitaria-da-ana", first.Header().Get("ETag"))
+
+	// assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.NotEmpty(t, first.Header().Get("ETag"))
+	assert.Equal(t, "public, max-age=60", first.Header().Get("Cache-Control"))
+	assert.Equal(t, http.StatusNotModified, revalidated.Code, "ETag igual revalida sem corpo")
+	assert.Empty(t, revalidated.Body.String())
+	assert.Equal(t, http.StatusOK, changed.Code, "catálogo alterado gera outro ETag")
+	assert.NotEqual(t, first.Header().Get("ETag"), changed.Header().Get("ETag"))
+}
+
+func TestGetCatalogReturnsNotFoundForUnknownSlug(t *testing.T) {
+	// arrange
+	engine := newCatalogEngine(&dtos.CatalogDto{Restaurant: dtos.CatalogRestaurantDto{Slug: "marmitaria-da-ana"}})
+
+	// act
+	response := getCatalog(engine, "outro-restaurante", "")
+
+	// assert
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
@@ -1,14 +1,13 @@
 package routers
 
 import (
+	"errors"
 	"io"
 	"net/http"
-	"strconv"
 
 	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
 	"github.com/PedroNetto404/marmitech-backend/pkg/types"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 func RegisterCategoryRoutes(
@@ -25,8 +24,7 @@ func RegisterCategoryRoutes(
 	group.DELETE("/:id/picture", deletecategoryImage(categoryUseCase))
 	group.POST("/:id/activate", activatecategory(categoryUseCase))
 	group.POST("/:id/deactivate", deactivatecategory(categoryUseCase))
-	group.POST("/:id/reorder/priority/:priority", reordercategory(categoryUseCase))
-	group.POST("/:id/reorder/swap/:swapId", reordercategory(categoryUseCase))
+	group.PUT("/order", reorderCategories(categoryUseCase))
 }
 
 func createcategory(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
@@ -37,7 +35,7 @@ func createcategory(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
 			return
 		}
 
-		category, err := useCase.Create(&payload)
+		category, err := useCase.Create(actorFromContext(c), &payload)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, err)
 			return
@@ -56,12 +54,23 @@ func updatecategory(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
 			return
 		}
 
-		category, err := useCase.Update(id, &payload)
+		expectedVersion, err := ifMatchVersion(c)
 		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		category, err := useCase.Update(actorFromContext(c), id, &payload)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
 
+		setETag(c, category.Version)
 		c.JSON(http.StatusOK, category)
 	}
 }
@@ -76,6 +85,7 @@ func getcategoryById(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
 			return
 		}
 
+		setETag(c, category.Version)
 		c.JSON(http.StatusOK, category)
 	}
 }
@@ -110,7 +120,7 @@ func deletecategory(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 
-		err := useCase.Delete(id)
+		err := useCase.Delete(actorFromContext(c), id)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, err)
 			return
@@ -146,12 +156,22 @@ func setcategoryImage(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
 			ContentType: fileHeader.Header.Get("Content-Type"),
 		}
 
-		category, err := useCase.SetPicture(id, picture)
+		expectedVersion, err := ifMatchVersion(c)
 		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+
+		category, err := useCase.SetPicture(actorFromContext(c), id, picture, expectedVersion)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
 
+		setETag(c, category.Version)
 		c.JSON(http.StatusOK, category)
 	}
 }
@@ -160,12 +180,22 @@ func deletecategoryImage(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 
-		category, err := useCase.DeletePicture(id)
+		expectedVersion, err := ifMatchVersion(c)
 		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+
+		category, err := useCase.DeletePicture(actorFromContext(c), id, expectedVersion)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
 
+		setETag(c, category.Version)
 		c.JSON(http.StatusOK, category)
 	}
 }
@@ -174,12 +204,22 @@ func activatecategory(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 
-		category, err := useCase.Activate(id)
+		expectedVersion, err := ifMatchVersion(c)
 		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+
+		category, err := useCase.Activate(actorFromContext(c), id, expectedVersion)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
 
+		setETag(c, category.Version)
 		c.JSON(http.StatusOK, category)
 	}
 }
@@ -188,44 +228,55 @@ func deactivatecategory(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 
-		category, err := useCase.Deactivate(id)
+		expectedVersion, err := ifMatchVersion(c)
 		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+
+		category, err := useCase.Deactivate(actorFromContext(c), id, expectedVersion)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
 
+		setETag(c, category.Version)
 		c.JSON(http.StatusOK, category)
 	}
 }
 
-func reordercategory(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
+type reorderPayload struct {
+	Ids []string `json:"ids" binding:"required"`
+}
+
+func reorderCategories(useCase usecase.ICategoryUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.Param("id")
-		if _, err := uuid.Parse(id); err != nil {
+		var payload reorderPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
 			c.JSON(http.StatusBadRequest, err)
 			return
 		}
 
-		swapId := c.Param("swapId")
-		if swapId != "" {
-			if _, err := uuid.Parse(swapId); err != nil {
-				c.JSON(http.StatusBadRequest, err)
+		categories, err := useCase.Reorder(actorFromContext(c), c.Param("restaurantId"), payload.Ids)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
+			if errors.Is(err, usecase.ErrRestaurantNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidCategoryOrder) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 				return
 			}
-		}
-
-		priority, err := strconv.Atoi(c.Param("priority"))
-		if err != nil {
-			c.JSON(http.StatusBadRequest, err)
-			return
-		}
-
-		category, err := useCase.Reorder(id, priority, swapId)
-		if err != nil {
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
 
-		c.JSON(http.StatusOK, category)
+		c.JSON(http.StatusOK, categories)
 	}
 }
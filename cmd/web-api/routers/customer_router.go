@@ -0,0 +1,129 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCustomerRoutes(
+	routerGroup *gin.RouterGroup,
+	customerUseCase usecase.ICustomerUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/customers")
+	group.POST("/", idempotency, createCustomer(customerUseCase))
+	group.PUT("/:id", updateCustomer(customerUseCase))
+	group.GET("/:id", getCustomerById(customerUseCase))
+	group.GET("/", getCustomers(customerUseCase))
+}
+
+func createCustomer(useCase usecase.ICustomerUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.CustomerPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		payload.Restaurant.Id = c.Param("restaurantId")
+
+		customer, err := useCase.Create(actorFromContext(c), &payload)
+		if err != nil {
+			if errors.Is(err, usecase.ErrCustomerAlreadyExists) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidAddress) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, customer)
+	}
+}
+
+func updateCustomer(useCase usecase.ICustomerUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		var payload usecase.CustomerPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		payload.Restaurant.Id = c.Param("restaurantId")
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		customer, err := useCase.Update(actorFromContext(c), id, &payload)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
+			if errors.Is(err, usecase.ErrCustomerNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidAddress) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		setETag(c, customer.Version)
+		c.JSON(http.StatusOK, customer)
+	}
+}
+
+func getCustomerById(useCase usecase.ICustomerUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+
+		customer, err := useCase.FindById(id)
+		if err != nil {
+			if errors.Is(err, usecase.ErrCustomerNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidAddress) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		setETag(c, customer.Version)
+		c.JSON(http.StatusOK, customer)
+	}
+}
+
+func getCustomers(useCase usecase.ICustomerUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		findArgs := types.NewDefaultFindArgs()
+		if err := c.ShouldBindQuery(&findArgs); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		customers, err := useCase.Find(findArgs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, customers)
+	}
+}
@@ -0,0 +1,223 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCustomerTabRoutes(
+	routerGroup *gin.RouterGroup,
+	customerTabUseCase usecase.ICustomerTabUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/tabs")
+	group.POST("/", openCustomerTab(customerTabUseCase))
+	group.GET("/", getOpenCustomerTabs(customerTabUseCase))
+	group.GET("/:id", getCustomerTabById(customerTabUseCase))
+	group.PUT("/:id", updateCustomerTab(customerTabUseCase))
+	group.POST("/:id/debits", idempotency, postCustomerTabDebit(customerTabUseCase))
+	group.POST("/:id/settlements", idempotency, settleCustomerTab(customerTabUseCase))
+	group.GET("/:id/entries", getCustomerTabEntries(customerTabUseCase))
+	group.GET("/:id/statements/:period", getCustomerTabStatement(customerTabUseCase))
+}
+
+func openCustomerTab(useCase usecase.ICustomerTabUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.CustomerTabPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		payload.Restaurant.Id = c.Param("restaurantId")
+
+		tab, err := useCase.Open(actorFromContext(c), &payload)
+		if err != nil {
+			respondCustomerTabError(c, err)
+			return
+		}
+
+		setETag(c, tab.Version)
+		c.JSON(http.StatusCreated, tab)
+	}
+}
+
+func getOpenCustomerTabs(useCase usecase.ICustomerTabUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		onlyOverdue := c.Query("overdue") == "true"
+
+		tabs, err := useCase.FindOpen(c.Param("restaurantId"), onlyOverdue)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, tabs)
+	}
+}
+
+func getCustomerTabById(useCase usecase.ICustomerTabUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tab, err := useCase.FindById(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondCustomerTabError(c, err)
+			return
+		}
+
+		setETag(c, tab.Version)
+		c.JSON(http.StatusOK, tab)
+	}
+}
+
+func updateCustomerTab(useCase usecase.ICustomerTabUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.CustomerTabPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		payload.Restaurant.Id = c.Param("restaurantId")
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		tab, err := useCase.Update(actorFromContext(c), c.Param("id"), &payload)
+		if err != nil {
+			respondCustomerTabError(c, err)
+			return
+		}
+
+		setETag(c, tab.Version)
+		c.JSON(http.StatusOK, tab)
+	}
+}
+
+func postCustomerTabDebit(useCase usecase.ICustomerTabUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.TabDebitPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		tab, err := useCase.PostDebit(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondCustomerTabError(c, err)
+			return
+		}
+
+		setETag(c, tab.Version)
+		c.JSON(http.StatusCreated, tab)
+	}
+}
+
+func settleCustomerTab(useCase usecase.ICustomerTabUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.TabSettlementPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		tab, err := useCase.Settle(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondCustomerTabError(c, err)
+			return
+		}
+
+		setETag(c, tab.Version)
+		c.JSON(http.StatusCreated, tab)
+	}
+}
+
+func getCustomerTabEntries(useCase usecase.ICustomerTabUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var from, to *time.Time
+
+		if value := c.Query("from"); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected RFC3339"})
+				return
+			}
+			from = &parsed
+		}
+
+		if value := c.Query("to"); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected RFC3339"})
+				return
+			}
+			to = &parsed
+		}
+
+		entries, err := useCase.FindEntries(c.Param("restaurantId"), c.Param("id"), from, to)
+		if err != nil {
+			respondCustomerTabError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, entries)
+	}
+}
+
+func getCustomerTabStatement(useCase usecase.ICustomerTabUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		period, err := time.ParseInLocation("2006-01", c.Param("period"), time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period, expected YYYY-MM"})
+			return
+		}
+
+		statement, err := useCase.Statement(c.Param("restaurantId"), c.Param("id"), period.Year(), period.Month())
+		if err != nil {
+			respondCustomerTabError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, statement)
+	}
+}
+
+func respondCustomerTabError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrCustomerTabNotFound) ||
+		errors.Is(err, usecase.ErrCustomerNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) ||
+		errors.Is(err, usecase.ErrOrderNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrCustomerTabAlreadyExists) ||
+		errors.Is(err, ports.ErrOrderAlreadyOnTab) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrPostPaidDisabled) ||
+		errors.Is(err, usecase.ErrBelowPostPaidMinimum) ||
+		errors.Is(err, usecase.ErrCreditLimitExceeded) ||
+		errors.Is(err, usecase.ErrSettlementExceedsBalance) ||
+		errors.Is(err, usecase.ErrInvalidTabAmount) ||
+		errors.Is(err, usecase.ErrInvalidDueDay) ||
+		errors.Is(err, usecase.ErrInvalidCreditLimit) ||
+		errors.Is(err, usecase.ErrOrderOfAnotherCustomer) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,204 @@
+package routers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	deliveryroutestatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_route_status"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterDeliveryRouteRoutes expõe o despacho para o balcão e as ações do app do entregador
+// (aceitar, retirar e entregar), que o use case restringe ao entregador da rota ou a um gerente
+func RegisterDeliveryRouteRoutes(
+	routerGroup *gin.RouterGroup,
+	deliveryRouteUseCase usecase.IDeliveryRouteUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/delivery-routes")
+	group.POST("/", idempotency, createDeliveryRoute(deliveryRouteUseCase))
+	group.GET("/", getDeliveryRoutes(deliveryRouteUseCase))
+	group.GET("/:id", getDeliveryRouteById(deliveryRouteUseCase))
+	group.GET("/:id/tracking", trackDeliveryRoute(deliveryRouteUseCase))
+	group.POST("/:id/cancel", cancelDeliveryRoute(deliveryRouteUseCase))
+	group.POST("/:id/accept", acceptDeliveryRoute(deliveryRouteUseCase))
+	group.POST("/:id/pickup", pickUpDeliveryRoute(deliveryRouteUseCase))
+	group.POST("/:id/stops/:stopId/deliver", deliverDeliveryStop(deliveryRouteUseCase))
+}
+
+func createDeliveryRoute(useCase usecase.IDeliveryRouteUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.DeliveryRoutePayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		route, err := useCase.Create(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondDeliveryRouteError(c, err)
+			return
+		}
+
+		setETag(c, route.Version)
+		c.JSON(http.StatusCreated, route)
+	}
+}
+
+func getDeliveryRoutes(useCase usecase.IDeliveryRouteUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := deliveryroutestatus.DeliveryRouteStatus(c.Query("status"))
+		routes, err := useCase.Find(c.Param("restaurantId"), c.Query("driver_id"), status)
+		if err != nil {
+			respondDeliveryRouteError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, routes)
+	}
+}
+
+func getDeliveryRouteById(useCase usecase.IDeliveryRouteUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route, err := useCase.FindById(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondDeliveryRouteError(c, err)
+			return
+		}
+
+		setETag(c, route.Version)
+		c.JSON(http.StatusOK, route)
+	}
+}
+
+func trackDeliveryRoute(useCase usecase.IDeliveryRouteUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tracking, err := useCase.Track(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondDeliveryRouteError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, tracking)
+	}
+}
+
+func cancelDeliveryRoute(useCase usecase.IDeliveryRouteUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route, err := useCase.Cancel(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondDeliveryRouteError(c, err)
+			return
+		}
+
+		setETag(c, route.Version)
+		c.JSON(http.StatusOK, route)
+	}
+}
+
+func acceptDeliveryRoute(useCase usecase.IDeliveryRouteUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route, err := useCase.Accept(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondDeliveryRouteError(c, err)
+			return
+		}
+
+		setETag(c, route.Version)
+		c.JSON(http.StatusOK, route)
+	}
+}
+
+func pickUpDeliveryRoute(useCase usecase.IDeliveryRouteUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route, err := useCase.PickUp(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondDeliveryRouteError(c, err)
+			return
+		}
+
+		setETag(c, route.Version)
+		c.JSON(http.StatusOK, route)
+	}
+}
+
+// deliverDeliveryStop recebe a foto no campo photo e o código do cliente no campo code
+func deliverDeliveryStop(useCase usecase.IDeliveryRouteUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload := usecase.DeliveryProofPayload{Code: c.PostForm("code")}
+
+		fileHeader, err := c.FormFile("photo")
+		if err != nil && !errors.Is(err, http.ErrMissingFile) {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		if fileHeader != nil {
+			file, err := fileHeader.Open()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, err)
+				return
+			}
+			defer file.Close()
+
+			content, err := io.ReadAll(file)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, err)
+				return
+			}
+			payload.Photo = &types.FilePayload{
+				Content:     content,
+				ContentType: fileHeader.Header.Get("Content-Type"),
+			}
+		}
+
+		route, err := useCase.Deliver(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), c.Param("stopId"), &payload)
+		if err != nil {
+			respondDeliveryRouteError(c, err)
+			return
+		}
+
+		setETag(c, route.Version)
+		c.JSON(http.StatusOK, route)
+	}
+}
+
+func respondDeliveryRouteError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrDeliveryRouteNotFound) ||
+		errors.Is(err, usecase.ErrDeliveryStopNotFound) ||
+		errors.Is(err, usecase.ErrDriverNotFound) ||
+		errors.Is(err, usecase.ErrOrderNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrDeliveryRouteForbidden) ||
+		errors.Is(err, usecase.ErrNotADriver) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrOrderAlreadyDispatched) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidDeliveryRoute) ||
+		errors.Is(err, usecase.ErrDriverUnavailable) ||
+		errors.Is(err, usecase.ErrInvalidDeliveryRouteTransition) ||
+		errors.Is(err, usecase.ErrOrderNotReadyForPickup) ||
+		errors.Is(err, usecase.ErrDeliveryProofRequired) ||
+		errors.Is(err, usecase.ErrInvalidDeliveryCode) ||
+		errors.Is(err, usecase.ErrInvalidDriverLocation) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
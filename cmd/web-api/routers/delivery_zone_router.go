@@ -0,0 +1,147 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterDeliveryZoneRoutes(
+	routerGroup *gin.RouterGroup,
+	deliveryZoneUseCase usecase.IDeliveryZoneUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/delivery-zones")
+	group.GET("/", getDeliveryZones(deliveryZoneUseCase))
+	// a cotação é usada no atendimento por telefone, não só pela gerência
+	group.POST("/quote", quoteDelivery(deliveryZoneUseCase))
+	group.GET("/:id", getDeliveryZoneById(deliveryZoneUseCase))
+	group.POST("/", requireManager, idempotency, createDeliveryZone(deliveryZoneUseCase))
+	group.PUT("/:id", requireManager, updateDeliveryZone(deliveryZoneUseCase))
+	group.DELETE("/:id", requireManager, deleteDeliveryZone(deliveryZoneUseCase))
+}
+
+func createDeliveryZone(useCase usecase.IDeliveryZoneUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.DeliveryZonePayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		zone, err := useCase.Create(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondDeliveryZoneError(c, err)
+			return
+		}
+
+		setETag(c, zone.Version)
+		c.JSON(http.StatusCreated, zone)
+	}
+}
+
+func getDeliveryZones(useCase usecase.IDeliveryZoneUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		zones, err := useCase.FindByRestaurantId(c.Param("restaurantId"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, zones)
+	}
+}
+
+func getDeliveryZoneById(useCase usecase.IDeliveryZoneUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		zone, err := useCase.FindById(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondDeliveryZoneError(c, err)
+			return
+		}
+
+		setETag(c, zone.Version)
+		c.JSON(http.StatusOK, zone)
+	}
+}
+
+func updateDeliveryZone(useCase usecase.IDeliveryZoneUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.DeliveryZonePayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		zone, err := useCase.Update(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondDeliveryZoneError(c, err)
+			return
+		}
+
+		setETag(c, zone.Version)
+		c.JSON(http.StatusOK, zone)
+	}
+}
+
+func deleteDeliveryZone(useCase usecase.IDeliveryZoneUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := useCase.Delete(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondDeliveryZoneError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusNoContent, nil)
+	}
+}
+
+func quoteDelivery(useCase usecase.IDeliveryZoneUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.DeliveryQuotePayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		quote, err := useCase.Quote(c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondDeliveryZoneError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, quote)
+	}
+}
+
+func respondDeliveryZoneError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrDeliveryZoneNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidDeliveryZone) ||
+		errors.Is(err, usecase.ErrDeliveryDisabled) ||
+		errors.Is(err, usecase.ErrInvalidAddress) ||
+		errors.Is(err, usecase.ErrOutsideDeliveryArea) ||
+		errors.Is(err, usecase.ErrDeliveryAddressNotLocated) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
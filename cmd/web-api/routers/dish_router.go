@@ -27,7 +27,7 @@ func createDish(useCase usecase.IDishUseCase) gin.HandlerFunc {
 			return
 		}
 
-		dish, err := useCase.Create(&payload)
+		dish, err := useCase.Create(actorFromContext(c), &payload)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, err)
 			return
@@ -46,12 +46,23 @@ func updateDish(useCase usecase.IDishUseCase) gin.HandlerFunc {
 			return
 		}
 
-		dish, err := useCase.Update(id, &payload)
+		expectedVersion, err := ifMatchVersion(c)
 		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		dish, err := useCase.Update(actorFromContext(c), id, &payload)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
 
+		setETag(c, dish.Version)
 		c.JSON(http.StatusOK, dish)
 	}
 }
@@ -71,6 +82,7 @@ func getDishById(useCase usecase.IDishUseCase) gin.HandlerFunc {
 			return
 		}
 
+		setETag(c, dish.Version)
 		c.JSON(http.StatusOK, dish)
 	}
 }
@@ -97,7 +109,7 @@ func deleteDish(useCase usecase.IDishUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 
-		err := useCase.Delete(id)
+		err := useCase.Delete(actorFromContext(c), id)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, err)
 			return
@@ -134,12 +146,22 @@ func setDishImage(useCase usecase.IDishUseCase) gin.HandlerFunc {
 			ContentType: fileHeader.Header.Get("Content-Type"),
 		}
 
-		dish, err := useCase.SetPicture(id, &payload)
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+
+		dish, err := useCase.SetPicture(actorFromContext(c), id, &payload, expectedVersion)
 		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
 
+		setETag(c, dish.Version)
 		c.JSON(http.StatusOK, dish)
 	}
 }
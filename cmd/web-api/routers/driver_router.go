@@ -0,0 +1,205 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterDriverRoutes junta o cadastro de entregadores e as rotas do app do entregador (/drivers/me)
+func RegisterDriverRoutes(
+	routerGroup *gin.RouterGroup,
+	driverUseCase usecase.IDriverUseCase,
+	deliveryRouteUseCase usecase.IDeliveryRouteUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/drivers")
+	group.GET("/", getDrivers(driverUseCase))
+	group.POST("/", requireManager, idempotency, createDriver(driverUseCase))
+	group.GET("/settlement", requireManager, getDriverSettlement(driverUseCase))
+	group.GET("/me/routes", getMyDeliveryRoutes(deliveryRouteUseCase))
+	group.POST("/me/location", pingDriverLocation(deliveryRouteUseCase))
+	group.GET("/:id", getDriverById(driverUseCase))
+	group.PUT("/:id", requireManager, updateDriver(driverUseCase))
+	group.DELETE("/:id", requireManager, deleteDriver(driverUseCase))
+	group.GET("/:id/location", getDriverLocation(driverUseCase))
+}
+
+func createDriver(useCase usecase.IDriverUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.DriverPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		driver, err := useCase.Create(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondDriverError(c, err)
+			return
+		}
+
+		setETag(c, driver.Version)
+		c.JSON(http.StatusCreated, driver)
+	}
+}
+
+func getDrivers(useCase usecase.IDriverUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		drivers, err := useCase.FindByRestaurantId(c.Param("restaurantId"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, drivers)
+	}
+}
+
+func getDriverById(useCase usecase.IDriverUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		driver, err := useCase.FindById(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondDriverError(c, err)
+			return
+		}
+
+		setETag(c, driver.Version)
+		c.JSON(http.StatusOK, driver)
+	}
+}
+
+func updateDriver(useCase usecase.IDriverUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.DriverPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		driver, err := useCase.Update(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondDriverError(c, err)
+			return
+		}
+
+		setETag(c, driver.Version)
+		c.JSON(http.StatusOK, driver)
+	}
+}
+
+func deleteDriver(useCase usecase.IDriverUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := useCase.Delete(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondDriverError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusNoContent, nil)
+	}
+}
+
+func getDriverLocation(useCase usecase.IDriverUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		location, err := useCase.LastLocation(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondDriverError(c, err)
+			return
+		}
+		if location == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Driver has not sent any location yet"})
+			return
+		}
+
+		c.JSON(http.StatusOK, location)
+	}
+}
+
+func getDriverSettlement(useCase usecase.IDriverUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		settlements, err := useCase.Settlement(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondReport(c, "driver-settlement", settlements,
+			[]string{"driver_id", "driver_name", "deliveries", "fees", "average_fee"},
+			func(row dtos.DriverSettlementDto) []string {
+				return []string{
+					row.DriverId,
+					row.DriverName,
+					strconv.Itoa(row.Deliveries),
+					formatDecimal(row.Fees),
+					formatDecimal(row.AverageFee),
+				}
+			})
+	}
+}
+
+func getMyDeliveryRoutes(useCase usecase.IDeliveryRouteUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		routes, err := useCase.MyRoutes(actorFromContext(c), c.Param("restaurantId"))
+		if err != nil {
+			respondDeliveryRouteError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, routes)
+	}
+}
+
+func pingDriverLocation(useCase usecase.IDeliveryRouteUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.DriverLocationPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		err := useCase.PingLocation(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondDeliveryRouteError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusNoContent, nil)
+	}
+}
+
+func respondDriverError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrDriverNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrDriverEmailAlreadyExists) ||
+		errors.Is(err, usecase.ErrDriverHasRoutes) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidDriver) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
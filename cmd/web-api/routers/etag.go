@@ -0,0 +1,42 @@
+package routers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/gin-gonic/gin"
+)
+
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// ifMatchVersion lê a versão esperada do cabeçalho If-Match; zero quando o cliente não enviou pré-condição.
+func ifMatchVersion(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	value := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	return strconv.Atoi(value)
+}
+
+func respondConcurrencyConflict(c *gin.Context, err error) bool {
+	var conflict *ports.ConcurrencyConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+
+	status := http.StatusConflict
+	if c.GetHeader("If-Match") != "" {
+		status = http.StatusPreconditionFailed
+	}
+
+	c.JSON(status, gin.H{"error": conflict.Error()})
+	return true
+}
@@ -0,0 +1,90 @@
+package routers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(ifMatch string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+	if ifMatch != "" {
+		c.Request.Header.Set("If-Match", ifMatch)
+	}
+
+	return c, recorder
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected int
+		invalid  bool
+	}{
+		{name: "sem cabeçalho", header: "", expected: 0},
+		{name: "curinga", header: "*", expected: 0},
+		{name: "etag forte", header: `"7"`, expected: 7},
+		{name: "etag fraca", header: `W/"7"`, expected: 7},
+		{name: "etag inválida", header: `"abc"`, invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange
+			c, _ := newContext(test.header)
+
+			// act
+			version, err := ifMatchVersion(c)
+
+			// assert
+			if test.invalid {
+				assert.Error(t, err, "If-Match inválido é recusado")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, version)
+		})
+	}
+}
+
+func TestRespondConcurrencyConflict(t *testing.T) {
+	conflict := fmt.Errorf("update: %w", &ports.ConcurrencyConflictError{AggregateType: "product", AggregateId: "product", Version: 2})
+
+	tests := []struct {
+		name     string
+		ifMatch  string
+		err      error
+		handled  bool
+		expected int
+	}{
+		{name: "pré-condição do cliente", ifMatch: `"2"`, err: conflict, handled: true, expected: http.StatusPreconditionFailed},
+		{name: "escrita concorrente sem If-Match", err: conflict, handled: true, expected: http.StatusConflict},
+		{name: "outro erro", ifMatch: `"2"`, err: errors.New("boom")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange
+			c, recorder := newContext(test.ifMatch)
+
+			// act
+			handled := respondConcurrencyConflict(c, test.err)
+
+			// assert
+			assert.Equal(t, test.handled, handled)
+			if test.handled {
+				assert.Equal(t, test.expected, recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package routers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterFiscalRoutes(
+	routerGroup *gin.RouterGroup,
+	fiscalDocumentUseCase usecase.IFiscalDocumentUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/fiscal")
+	group.GET("/profile", getFiscalProfile(fiscalDocumentUseCase))
+	group.PUT("/profile", saveFiscalProfile(fiscalDocumentUseCase))
+	group.PUT("/certificate", uploadFiscalCertificate(fiscalDocumentUseCase))
+
+	routerGroup.POST("/orders/:id/fiscal-document", idempotency, issueFiscalDocument(fiscalDocumentUseCase))
+	routerGroup.GET("/orders/:id/fiscal-document", getFiscalDocument(fiscalDocumentUseCase))
+	routerGroup.GET("/orders/:id/fiscal-document.xml", getFiscalDocumentXml(fiscalDocumentUseCase))
+}
+
+func getFiscalProfile(useCase usecase.IFiscalDocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		profile, err := useCase.FindProfile(c.Param("restaurantId"))
+		if err != nil {
+			respondFiscalError(c, err)
+			return
+		}
+
+		setETag(c, profile.Version)
+		c.JSON(http.StatusOK, profile)
+	}
+}
+
+func saveFiscalProfile(useCase usecase.IFiscalDocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.FiscalProfilePayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		profile, err := useCase.SaveProfile(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondFiscalError(c, err)
+			return
+		}
+
+		setETag(c, profile.Version)
+		c.JSON(http.StatusOK, profile)
+	}
+}
+
+// uploadFiscalCertificate recebe o .pfx no campo file e a senha no campo password
+func uploadFiscalCertificate(useCase usecase.IFiscalDocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing certificate file"})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		certificate := types.FilePayload{
+			Content:     content,
+			ContentType: fileHeader.Header.Get("Content-Type"),
+		}
+
+		profile, err := useCase.UploadCertificate(actorFromContext(c), c.Param("restaurantId"), &certificate, c.PostForm("password"))
+		if err != nil {
+			respondFiscalError(c, err)
+			return
+		}
+
+		setETag(c, profile.Version)
+		c.JSON(http.StatusOK, profile)
+	}
+}
+
+// issueFiscalDocument responde 201 quando a SEFAZ autoriza e 422 com o documento quando rejeita
+func issueFiscalDocument(useCase usecase.IFiscalDocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		document, err := useCase.Issue(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondFiscalError(c, err)
+			return
+		}
+
+		if !document.IsAuthorized() {
+			c.JSON(http.StatusUnprocessableEntity, document)
+			return
+		}
+
+		c.JSON(http.StatusCreated, document)
+	}
+}
+
+func getFiscalDocument(useCase usecase.IFiscalDocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		document, err := useCase.FindByOrderId(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondFiscalError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, document)
+	}
+}
+
+func getFiscalDocumentXml(useCase usecase.IFiscalDocumentUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		document, err := useCase.FindByOrderId(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondFiscalError(c, err)
+			return
+		}
+		if !document.IsAuthorized() {
+			c.JSON(http.StatusNotFound, gin.H{"error": usecase.ErrFiscalDocumentNotFound.Error()})
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-nfce.xml", document.AccessKey))
+		c.Data(http.StatusOK, "application/xml; charset=utf-8", []byte(document.Xml))
+	}
+}
+
+func respondFiscalError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrFiscalProfileNotFound) ||
+		errors.Is(err, usecase.ErrFiscalDocumentNotFound) ||
+		errors.Is(err, usecase.ErrOrderNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrFiscalDocumentAlreadyIssued) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidFiscalProfile) ||
+		errors.Is(err, usecase.ErrInvalidFiscalCertificate) ||
+		errors.Is(err, usecase.ErrFiscalCertificateMissing) ||
+		errors.Is(err, usecase.ErrIncompleteProductFiscalData) ||
+		errors.Is(err, usecase.ErrOrderNotPaid) ||
+		errors.Is(err, ports.ErrUnsupportedFiscalData) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, ports.ErrSefazUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,258 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterInventoryRoutes(
+	routerGroup *gin.RouterGroup,
+	inventoryUseCase usecase.IInventoryUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/stock")
+	group.GET("/items", getStock(inventoryUseCase, false))
+	group.POST("/items", createStockItem(inventoryUseCase))
+	group.GET("/items/:id", getStockItemById(inventoryUseCase))
+	group.PUT("/items/:id", updateStockItem(inventoryUseCase))
+	group.POST("/items/:id/movements", idempotency, addStockMovement(inventoryUseCase))
+	group.GET("/items/:id/movements", getStockMovements(inventoryUseCase))
+	group.GET("/alerts", getStock(inventoryUseCase, true))
+	group.GET("/recipes/products/:productId", getProductRecipe(inventoryUseCase))
+	group.PUT("/recipes/products/:productId", saveProductRecipe(inventoryUseCase))
+	group.GET("/recipes/dishes/:dishId", getDishRecipe(inventoryUseCase))
+	group.PUT("/recipes/dishes/:dishId", saveDishRecipe(inventoryUseCase))
+}
+
+// getStock lista o estoque atual; os alertas são os itens no estoque mínimo ou abaixo dele
+func getStock(useCase usecase.IInventoryUseCase, onlyLow bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := useCase.FindStock(c.Param("restaurantId"), onlyLow || c.Query("low") == "true")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+func createStockItem(useCase usecase.IInventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.StockItemPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		item, err := useCase.CreateStockItem(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondInventoryError(c, err)
+			return
+		}
+
+		setETag(c, item.Version)
+		c.JSON(http.StatusCreated, item)
+	}
+}
+
+func getStockItemById(useCase usecase.IInventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		item, err := useCase.FindStockItemById(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondInventoryError(c, err)
+			return
+		}
+
+		setETag(c, item.Version)
+		c.JSON(http.StatusOK, item)
+	}
+}
+
+func updateStockItem(useCase usecase.IInventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.StockItemPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		item, err := useCase.UpdateStockItem(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondInventoryError(c, err)
+			return
+		}
+
+		setETag(c, item.Version)
+		c.JSON(http.StatusOK, item)
+	}
+}
+
+func addStockMovement(useCase usecase.IInventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.StockMovementPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		item, err := useCase.AddMovement(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondInventoryError(c, err)
+			return
+		}
+
+		setETag(c, item.Version)
+		c.JSON(http.StatusCreated, item)
+	}
+}
+
+func getStockMovements(useCase usecase.IInventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var from, to *time.Time
+
+		if value := c.Query("from"); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected RFC3339"})
+				return
+			}
+			from = &parsed
+		}
+
+		if value := c.Query("to"); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected RFC3339"})
+				return
+			}
+			to = &parsed
+		}
+
+		movements, err := useCase.FindMovements(c.Param("restaurantId"), c.Param("id"), from, to)
+		if err != nil {
+			respondInventoryError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, movements)
+	}
+}
+
+func getProductRecipe(useCase usecase.IInventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		recipe, err := useCase.FindProductRecipe(c.Param("restaurantId"), c.Param("productId"))
+		if err != nil {
+			respondInventoryError(c, err)
+			return
+		}
+
+		setETag(c, recipe.Version)
+		c.JSON(http.StatusOK, recipe)
+	}
+}
+
+func saveProductRecipe(useCase usecase.IInventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.RecipePayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		recipe, err := useCase.SaveProductRecipe(actorFromContext(c), c.Param("restaurantId"), c.Param("productId"), &payload)
+		if err != nil {
+			respondInventoryError(c, err)
+			return
+		}
+
+		setETag(c, recipe.Version)
+		c.JSON(http.StatusOK, recipe)
+	}
+}
+
+func getDishRecipe(useCase usecase.IInventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		recipe, err := useCase.FindDishRecipe(c.Param("restaurantId"), c.Param("dishId"))
+		if err != nil {
+			respondInventoryError(c, err)
+			return
+		}
+
+		setETag(c, recipe.Version)
+		c.JSON(http.StatusOK, recipe)
+	}
+}
+
+func saveDishRecipe(useCase usecase.IInventoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.RecipePayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		recipe, err := useCase.SaveDishRecipe(actorFromContext(c), c.Param("restaurantId"), c.Param("dishId"), &payload)
+		if err != nil {
+			respondInventoryError(c, err)
+			return
+		}
+
+		setETag(c, recipe.Version)
+		c.JSON(http.StatusOK, recipe)
+	}
+}
+
+func respondInventoryError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrStockItemNotFound) ||
+		errors.Is(err, usecase.ErrRecipeNotFound) ||
+		errors.Is(err, usecase.ErrProductNotFound) ||
+		errors.Is(err, usecase.ErrDishNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrStockItemNameTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidStockItem) ||
+		errors.Is(err, usecase.ErrInvalidStockMovement) ||
+		errors.Is(err, usecase.ErrInvalidRecipe) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,126 @@
+package routers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	"github.com/gin-gonic/gin"
+)
+
+const kitchenHeartbeatInterval = 15 * time.Second
+
+func RegisterKitchenRoutes(
+	routerGroup *gin.RouterGroup,
+	kitchenUseCase usecase.IKitchenUseCase,
+) {
+	group := routerGroup.Group("/kitchen")
+	group.GET("/queue", getKitchenQueue(kitchenUseCase))
+	group.GET("/stream", streamKitchen(kitchenUseCase))
+	group.POST("/orders/:orderId/preparing", bumpKitchenOrder(kitchenUseCase, orderstatus.PREPARING))
+	group.POST("/orders/:orderId/ready", bumpKitchenOrder(kitchenUseCase, orderstatus.READY))
+	group.POST("/orders/:orderId/items/:itemId/preparing", bumpKitchenItem(kitchenUseCase, orderstatus.PREPARING))
+	group.POST("/orders/:orderId/items/:itemId/ready", bumpKitchenItem(kitchenUseCase, orderstatus.READY))
+}
+
+func getKitchenQueue(useCase usecase.IKitchenUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		queue, err := useCase.Queue(c.Param("restaurantId"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, queue)
+	}
+}
+
+// streamKitchen envia a fila atual como "snapshot" e depois cada pedido criado,
+// atualizado ou cancelado como um evento SSE com o nome do evento de domínio
+func streamKitchen(useCase usecase.IKitchenUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		restaurantId := c.Param("restaurantId")
+
+		// assina antes de ler a fila para não perder eventos entre as duas coisas
+		updates, stop := useCase.Subscribe(restaurantId)
+		defer stop()
+
+		queue, err := useCase.Queue(restaurantId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.SSEvent("snapshot", queue)
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(kitchenHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		c.Stream(func(w io.Writer) bool {
+			select {
+			case <-c.Request.Context().Done():
+				return false
+			case update, ok := <-updates:
+				if !ok {
+					return false
+				}
+				c.SSEvent(update.Type, update.Order)
+				return true
+			case <-heartbeat.C:
+				c.SSEvent("ping", time.Now().Unix())
+				return true
+			}
+		})
+	}
+}
+
+func bumpKitchenOrder(useCase usecase.IKitchenUseCase, status orderstatus.OrderStatus) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, err := useCase.SetOrderStatus(actorFromContext(c), c.Param("restaurantId"), c.Param("orderId"), status)
+		if err != nil {
+			respondKitchenError(c, err)
+			return
+		}
+
+		setETag(c, order.Version)
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+func bumpKitchenItem(useCase usecase.IKitchenUseCase, status orderstatus.OrderStatus) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, err := useCase.SetItemStatus(actorFromContext(c), c.Param("restaurantId"), c.Param("orderId"), c.Param("itemId"), status)
+		if err != nil {
+			respondKitchenError(c, err)
+			return
+		}
+
+		setETag(c, order.Version)
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+func respondKitchenError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrOrderNotFound) || errors.Is(err, usecase.ErrOrderItemNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidOrderTransition) || errors.Is(err, usecase.ErrInvalidKitchenStatus) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,105 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterLoyaltyRoutes(
+	routerGroup *gin.RouterGroup,
+	loyaltyUseCase usecase.ILoyaltyUseCase,
+) {
+	group := routerGroup.Group("/loyalty")
+	group.GET("/program", getLoyaltyProgram(loyaltyUseCase))
+	group.PUT("/program", saveLoyaltyProgram(loyaltyUseCase))
+	group.GET("/customers/:customerId", getLoyaltyAccount(loyaltyUseCase))
+	group.GET("/customers/:customerId/history", getLoyaltyHistory(loyaltyUseCase))
+}
+
+func getLoyaltyProgram(useCase usecase.ILoyaltyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		program, err := useCase.FindProgram(c.Param("restaurantId"))
+		if err != nil {
+			respondLoyaltyError(c, err)
+			return
+		}
+
+		setETag(c, program.Version)
+		c.JSON(http.StatusOK, program)
+	}
+}
+
+func saveLoyaltyProgram(useCase usecase.ILoyaltyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.LoyaltyProgramPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		program, err := useCase.SaveProgram(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondLoyaltyError(c, err)
+			return
+		}
+
+		setETag(c, program.Version)
+		c.JSON(http.StatusOK, program)
+	}
+}
+
+func getLoyaltyAccount(useCase usecase.ILoyaltyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, err := useCase.FindAccount(c.Param("restaurantId"), c.Param("customerId"))
+		if err != nil {
+			respondLoyaltyError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, account)
+	}
+}
+
+func getLoyaltyHistory(useCase usecase.ILoyaltyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entries, err := useCase.FindHistory(c.Param("restaurantId"), c.Param("customerId"))
+		if err != nil {
+			respondLoyaltyError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, entries)
+	}
+}
+
+func respondLoyaltyError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrLoyaltyProgramNotFound) ||
+		errors.Is(err, usecase.ErrLoyaltyAccountNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidLoyaltyProgram) ||
+		errors.Is(err, usecase.ErrProductNotFound) ||
+		errors.Is(err, usecase.ErrCategoryNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,88 @@
+package routers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterMarginRoutes(
+	routerGroup *gin.RouterGroup,
+	marginUseCase usecase.IMarginUseCase,
+) {
+	group := routerGroup.Group("/reports/margins", requireManager)
+	group.GET("/products", getProductMargins(marginUseCase))
+	group.GET("/categories", getCategoryMargins(marginUseCase))
+	group.GET("/alerts", getBelowCostAlerts(marginUseCase))
+}
+
+// requireManager barra quem não administra o restaurante: custos e margens não saem para os demais papéis
+func requireManager(c *gin.Context) {
+	if !actorFromContext(c).IsManager() {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Only managers can access this resource"})
+		return
+	}
+	c.Next()
+}
+
+func getProductMargins(useCase usecase.IMarginUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		margins, err := useCase.ProductMargins(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondProductMargins(c, "margins-products", margins)
+	}
+}
+
+func getBelowCostAlerts(useCase usecase.IMarginUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		alerts, err := useCase.BelowCostAlerts(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondProductMargins(c, "margins-alerts", alerts)
+	}
+}
+
+func getCategoryMargins(useCase usecase.IMarginUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		margins, err := useCase.CategoryMargins(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondReport(c, "margins-categories", margins,
+			[]string{"category_id", "category_name", "quantity_sold", "revenue", "cost", "contribution", "contribution_percent"},
+			func(row dtos.CategoryMarginDto) []string {
+				return []string{
+					row.CategoryId, row.CategoryName, strconv.Itoa(row.QuantitySold), formatDecimal(row.Revenue),
+					formatDecimal(row.Cost), formatDecimal(row.Contribution), formatDecimal(row.ContributionPercent),
+				}
+			})
+	}
+}
+
+func respondProductMargins(c *gin.Context, name string, margins []dtos.ProductMarginDto) {
+	respondReport(c, name, margins,
+		[]string{
+			"product_id", "product_name", "category_id", "category_name", "sales_price", "unit_cost", "unit_margin",
+			"unit_margin_percent", "below_cost", "quantity_sold", "revenue", "cost", "contribution", "contribution_percent",
+		},
+		func(row dtos.ProductMarginDto) []string {
+			return []string{
+				row.ProductId, row.ProductName, row.CategoryId, row.CategoryName, formatDecimal(row.Unit.SalesPrice),
+				formatDecimal(row.Unit.UnitCost), formatDecimal(row.Unit.Margin), formatDecimal(row.Unit.MarginPercent),
+				strconv.FormatBool(row.Unit.BelowCost), strconv.Itoa(row.QuantitySold), formatDecimal(row.Revenue),
+				formatDecimal(row.Cost), formatDecimal(row.Contribution), formatDecimal(row.ContributionPercent),
+			}
+		})
+}
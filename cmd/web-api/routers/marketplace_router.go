@@ -0,0 +1,267 @@
+package routers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	marketplaceorderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/marketplace_order_status"
+	orderchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_channel"
+	"github.com/gin-gonic/gin"
+)
+
+// cabeçalho em que cada marketplace envia a assinatura do webhook
+var marketplaceSignatureHeaders = map[orderchannel.OrderChannel]string{
+	orderchannel.IFOOD: "X-IFood-Signature",
+}
+
+func RegisterMarketplaceRoutes(
+	routerGroup *gin.RouterGroup,
+	marketplaceUseCase usecase.IMarketplaceUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/marketplace", requireManager)
+	group.GET("/", getMarketplaceIntegrations(marketplaceUseCase))
+	group.GET("/orders", getMarketplaceOrders(marketplaceUseCase))
+	group.POST("/orders/:id/retry", retryMarketplaceOrder(marketplaceUseCase))
+	group.PUT("/:channel", saveMarketplaceIntegration(marketplaceUseCase))
+	group.GET("/:channel/mappings", getMarketplaceMappings(marketplaceUseCase))
+	group.POST("/:channel/mappings", idempotency, createMarketplaceMapping(marketplaceUseCase))
+	group.PUT("/:channel/mappings/:id", updateMarketplaceMapping(marketplaceUseCase))
+	group.DELETE("/:channel/mappings/:id", deleteMarketplaceMapping(marketplaceUseCase))
+}
+
+// RegisterMarketplaceWebhookRoutes recebe os envios dos marketplaces, autenticados pela assinatura
+func RegisterMarketplaceWebhookRoutes(routerGroup *gin.RouterGroup, marketplaceUseCase usecase.IMarketplaceUseCase) {
+	routerGroup.POST("/marketplace/:channel/webhook", receiveMarketplaceWebhook(marketplaceUseCase))
+}
+
+func getMarketplaceIntegrations(useCase usecase.IMarketplaceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		integrations, err := useCase.FindIntegrations(c.Param("restaurantId"))
+		if err != nil {
+			respondMarketplaceError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, integrations)
+	}
+}
+
+func saveMarketplaceIntegration(useCase usecase.IMarketplaceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.MarketplaceIntegrationPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		integration, err := useCase.SaveIntegration(
+			actorFromContext(c),
+			c.Param("restaurantId"),
+			orderchannel.OrderChannel(c.Param("channel")),
+			&payload,
+		)
+		if err != nil {
+			respondMarketplaceError(c, err)
+			return
+		}
+
+		setETag(c, integration.Version)
+		c.JSON(http.StatusOK, integration)
+	}
+}
+
+func getMarketplaceMappings(useCase usecase.IMarketplaceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		mappings, err := useCase.FindMappings(c.Param("restaurantId"), orderchannel.OrderChannel(c.Param("channel")))
+		if err != nil {
+			respondMarketplaceError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, mappings)
+	}
+}
+
+func createMarketplaceMapping(useCase usecase.IMarketplaceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.MarketplaceMappingPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		mapping, err := useCase.CreateMapping(
+			actorFromContext(c),
+			c.Param("restaurantId"),
+			orderchannel.OrderChannel(c.Param("channel")),
+			&payload,
+		)
+		if err != nil {
+			respondMarketplaceError(c, err)
+			return
+		}
+
+		setETag(c, mapping.Version)
+		c.JSON(http.StatusCreated, mapping)
+	}
+}
+
+func updateMarketplaceMapping(useCase usecase.IMarketplaceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.MarketplaceMappingPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		mapping, err := useCase.UpdateMapping(
+			actorFromContext(c),
+			c.Param("restaurantId"),
+			orderchannel.OrderChannel(c.Param("channel")),
+			c.Param("id"),
+			&payload,
+		)
+		if err != nil {
+			respondMarketplaceError(c, err)
+			return
+		}
+
+		setETag(c, mapping.Version)
+		c.JSON(http.StatusOK, mapping)
+	}
+}
+
+func deleteMarketplaceMapping(useCase usecase.IMarketplaceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := useCase.DeleteMapping(
+			actorFromContext(c),
+			c.Param("restaurantId"),
+			orderchannel.OrderChannel(c.Param("channel")),
+			c.Param("id"),
+		)
+		if err != nil {
+			respondMarketplaceError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusNoContent, nil)
+	}
+}
+
+// getMarketplaceOrders aceita ?status= e ?limit=; ?status=failed são os pedidos com itens sem vínculo
+func getMarketplaceOrders(useCase usecase.IMarketplaceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := usecase.MarketplaceOrderFilterPayload{
+			Status: marketplaceorderstatus.MarketplaceOrderStatus(c.Query("status")),
+		}
+
+		if value := c.Query("limit"); value != "" {
+			limit, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		orders, err := useCase.FindOrders(c.Param("restaurantId"), filter)
+		if err != nil {
+			respondMarketplaceError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, orders)
+	}
+}
+
+// retryMarketplaceOrder responde com o registro, que continua failed se ainda faltar vínculo
+func retryMarketplaceOrder(useCase usecase.IMarketplaceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, err := useCase.RetryOrder(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondMarketplaceError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+func receiveMarketplaceWebhook(useCase usecase.IMarketplaceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		channel := orderchannel.OrderChannel(c.Param("channel"))
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid body"})
+			return
+		}
+
+		err = useCase.ReceiveWebhook(channel, body, c.GetHeader(marketplaceSignatureHeaders[channel]))
+		if err != nil {
+			if errors.Is(err, ports.ErrInvalidMarketplaceSignature) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			respondMarketplaceError(c, err)
+			return
+		}
+
+		c.Status(http.StatusAccepted)
+	}
+}
+
+func respondMarketplaceError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrMarketplaceMappingNotFound) ||
+		errors.Is(err, usecase.ErrMarketplaceOrderNotFound) ||
+		errors.Is(err, usecase.ErrMarketplaceNotConfigured) ||
+		errors.Is(err, usecase.ErrProductNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrMarketplaceOrderNotRetryable) ||
+		errors.Is(err, ports.ErrMarketplaceMerchantTaken) ||
+		errors.Is(err, ports.ErrMarketplaceMappingExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidMarketplaceChannel) ||
+		errors.Is(err, usecase.ErrInvalidMarketplaceIntegration) ||
+		errors.Is(err, usecase.ErrInvalidMarketplaceMapping) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, ports.ErrMarketplaceRejected) {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
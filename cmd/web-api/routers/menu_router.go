@@ -0,0 +1,75 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterMenuRoutes(
+	routerGroup *gin.RouterGroup,
+	menuUseCase usecase.IMenuUseCase,
+) {
+	group := routerGroup.Group("/menu")
+	group.GET("/", getMenu(menuUseCase))
+	group.PUT("/items/:menuItemId/availability", updateMenuItemAvailability(menuUseCase))
+}
+
+// getMenu devolve o cardápio do dia informado em ?date=YYYY-MM-DD, ou o de hoje
+func getMenu(useCase usecase.IMenuUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		offerDate := time.Now()
+		if value := c.Query("date"); value != "" {
+			parsed, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, expected YYYY-MM-DD"})
+				return
+			}
+			offerDate = parsed
+		}
+
+		menu, err := useCase.FindByOfferDate(c.Param("restaurantId"), offerDate)
+		if err != nil {
+			respondMenuError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, menu)
+	}
+}
+
+func updateMenuItemAvailability(useCase usecase.IMenuUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.MenuItemAvailabilityPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		menuItem, err := useCase.UpdateItemAvailability(actorFromContext(c), c.Param("restaurantId"), c.Param("menuItemId"), &payload)
+		if err != nil {
+			respondMenuError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, menuItem)
+	}
+}
+
+func respondMenuError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrMenuNotFound) ||
+		errors.Is(err, usecase.ErrMenuItemNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidMenuItemAvailability) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,84 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterNotificationRoutes(
+	routerGroup *gin.RouterGroup,
+	notificationUseCase usecase.INotificationUseCase,
+) {
+	group := routerGroup.Group("/notifications")
+	group.GET("/customers/:customerId/preferences", getNotificationPreferences(notificationUseCase))
+	group.PUT("/customers/:customerId/preferences", saveNotificationPreferences(notificationUseCase))
+	group.GET("/orders/:orderId", getOrderNotifications(notificationUseCase))
+}
+
+func getNotificationPreferences(useCase usecase.INotificationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		preferences, err := useCase.FindPreferences(c.Param("customerId"))
+		if err != nil {
+			respondNotificationError(c, err)
+			return
+		}
+
+		setETag(c, preferences.Version)
+		c.JSON(http.StatusOK, preferences)
+	}
+}
+
+func saveNotificationPreferences(useCase usecase.INotificationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.NotificationPreferencesPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		preferences, err := useCase.SavePreferences(actorFromContext(c), c.Param("restaurantId"), c.Param("customerId"), &payload)
+		if err != nil {
+			respondNotificationError(c, err)
+			return
+		}
+
+		setETag(c, preferences.Version)
+		c.JSON(http.StatusOK, preferences)
+	}
+}
+
+func getOrderNotifications(useCase usecase.INotificationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		notifications, err := useCase.FindByOrderId(c.Param("restaurantId"), c.Param("orderId"))
+		if err != nil {
+			respondNotificationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, notifications)
+	}
+}
+
+func respondNotificationError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrCustomerNotFound) ||
+		errors.Is(err, usecase.ErrOrderNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
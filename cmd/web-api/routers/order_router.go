@@ -0,0 +1,204 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/gin-gonic/gin"
+)
+
+type cancelOrderPayload struct {
+	Reason string `json:"reason"`
+}
+
+func RegisterOrderRoutes(
+	routerGroup *gin.RouterGroup,
+	orderUseCase usecase.IOrderUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/orders")
+	group.POST("/", idempotency, createOrder(orderUseCase))
+	group.GET("/", getOrders(orderUseCase))
+	group.GET("/:id", getOrderById(orderUseCase))
+	group.POST("/:id/complete", completeOrder(orderUseCase))
+	group.POST("/:id/cancel", cancelOrder(orderUseCase))
+	group.POST("/:id/payments", idempotency, addOrderPayment(orderUseCase))
+}
+
+func createOrder(useCase usecase.IOrderUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.OrderPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+		payload.Restaurant.Id = c.Param("restaurantId")
+
+		order, err := useCase.Create(actorFromContext(c), &payload)
+		if err != nil {
+			respondOrderError(c, err)
+			return
+		}
+
+		setETag(c, order.Version)
+		c.JSON(http.StatusCreated, order)
+	}
+}
+
+func getOrders(useCase usecase.IOrderUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		findArgs := types.NewDefaultFindArgs()
+		if err := c.ShouldBindQuery(&findArgs); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		orders, err := useCase.Find(findArgs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, orders)
+	}
+}
+
+func getOrderById(useCase usecase.IOrderUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, err := useCase.FindById(c.Param("id"))
+		if err != nil {
+			respondOrderError(c, err)
+			return
+		}
+
+		setETag(c, order.Version)
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+func completeOrder(useCase usecase.IOrderUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+
+		order, err := useCase.Complete(actorFromContext(c), c.Param("id"), expectedVersion)
+		if err != nil {
+			respondOrderError(c, err)
+			return
+		}
+
+		setETag(c, order.Version)
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+func cancelOrder(useCase usecase.IOrderUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload cancelOrderPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+
+		order, err := useCase.Cancel(actorFromContext(c), c.Param("id"), payload.Reason, expectedVersion)
+		if err != nil {
+			respondOrderError(c, err)
+			return
+		}
+
+		setETag(c, order.Version)
+		c.JSON(http.StatusOK, order)
+	}
+}
+
+func addOrderPayment(useCase usecase.IOrderUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.OrderPaymentPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		order, err := useCase.AddPayment(actorFromContext(c), c.Param("id"), &payload)
+		if err != nil {
+			respondOrderError(c, err)
+			return
+		}
+
+		setETag(c, order.Version)
+		c.JSON(http.StatusCreated, order)
+	}
+}
+
+func respondOrderError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrOrderNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) ||
+		errors.Is(err, usecase.ErrCustomerNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, ports.ErrOrderAlreadyOnTab) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrEmptyOrder) ||
+		errors.Is(err, usecase.ErrInvalidOrderItem) ||
+		errors.Is(err, usecase.ErrProductUnavailable) ||
+		errors.Is(err, usecase.ErrMenuNotAvailable) ||
+		errors.Is(err, usecase.ErrInvalidLunchbox) ||
+		errors.Is(err, usecase.ErrDeliveryDisabled) ||
+		errors.Is(err, usecase.ErrInvalidAddress) ||
+		errors.Is(err, usecase.ErrOutsideDeliveryArea) ||
+		errors.Is(err, usecase.ErrDeliveryAddressNotLocated) ||
+		errors.Is(err, usecase.ErrBelowDeliveryMinimum) ||
+		errors.Is(err, usecase.ErrInvalidOrderTransition) ||
+		errors.Is(err, usecase.ErrInvalidCoupon) ||
+		errors.Is(err, usecase.ErrCouponNotApplicable) ||
+		errors.Is(err, usecase.ErrCouponUsageLimitReached) ||
+		errors.Is(err, usecase.ErrCouponRequiresCustomer) ||
+		errors.Is(err, usecase.ErrCouponRestrictedToFirstOrder) ||
+		errors.Is(err, ports.ErrPromotionExhausted) ||
+		errors.Is(err, ports.ErrMenuItemSoldOut) ||
+		errors.Is(err, usecase.ErrLoyaltyProgramNotFound) ||
+		errors.Is(err, usecase.ErrLoyaltyRewardRequiresCustomer) ||
+		errors.Is(err, usecase.ErrInsufficientLoyaltyPoints) ||
+		errors.Is(err, usecase.ErrLoyaltyRewardNotApplicable) ||
+		errors.Is(err, usecase.ErrInvalidPayment) ||
+		errors.Is(err, usecase.ErrCashSessionNotOpen) ||
+		errors.Is(err, usecase.ErrPostPaidRequiresCustomer) ||
+		errors.Is(err, usecase.ErrPostPaidDisabled) ||
+		errors.Is(err, usecase.ErrCustomerTabNotFound) ||
+		errors.Is(err, usecase.ErrBelowPostPaidMinimum) ||
+		errors.Is(err, usecase.ErrCreditLimitExceeded) ||
+		errors.Is(err, usecase.ErrInvalidTabAmount) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
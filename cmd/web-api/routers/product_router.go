@@ -1,8 +1,10 @@
 package routers
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
 	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
 	"github.com/PedroNetto404/marmitech-backend/pkg/middleware"
 	"github.com/PedroNetto404/marmitech-backend/pkg/types"
@@ -12,16 +14,19 @@ import (
 func RegisterProductRoutes(
 	group *gin.RouterGroup,
 	productUseCase usecase.IProductUseCase,
+	marginUseCase usecase.IMarginUseCase,
 ) {
 	productGroup := group.Group("/products")
 	
 	productGroup.GET("/", createProduct(productUseCase))
-	productGroup.GET("/:id", getProduct(productUseCase))
+	productGroup.GET("/:id", getProduct(productUseCase, marginUseCase))
 	productGroup.POST("/", createProduct(productUseCase))
 	productGroup.PUT("/:id", updateProduct(productUseCase))
 	productGroup.DELETE("/:id", deleteProduct(productUseCase))
 	productGroup.PATCH("/:id/picture", updateProductPicture(productUseCase))
 	productGroup.DELETE("/:id/picture", deleteProductPicture(productUseCase))
+
+	group.PUT("/categories/:id/products/order", reorderProducts(productUseCase))
 }
 
 func createProduct(productUseCase usecase.IProductUseCase) gin.HandlerFunc {
@@ -32,8 +37,12 @@ func createProduct(productUseCase usecase.IProductUseCase) gin.HandlerFunc {
 			return
 		}
 
-		created, err := productUseCase.Create(&payload)
+		created, err := productUseCase.Create(actorFromContext(c), &payload)
 		if err != nil {
+			if errors.Is(err, usecase.ErrInvalidProductFiscalData) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create product"})
 			return
 		}
@@ -42,7 +51,7 @@ func createProduct(productUseCase usecase.IProductUseCase) gin.HandlerFunc {
 	}
 }
 
-func getProduct(productUseCase usecase.IProductUseCase) gin.HandlerFunc {
+func getProduct(productUseCase usecase.IProductUseCase, marginUseCase usecase.IMarginUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 		if id == "" {
@@ -56,7 +65,20 @@ func getProduct(productUseCase usecase.IProductUseCase) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, product)
+		setETag(c, product.Version)
+		if !actorFromContext(c).IsManager() {
+			c.JSON(http.StatusOK, product)
+			return
+		}
+
+		// a gestão recebe também a margem pelos custos atuais
+		margin, err := marginUseCase.ProductMargin(product)
+		if err != nil && !errors.Is(err, usecase.ErrProductNotFound) {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dtos.ManagedProductDto{Product: product, Margin: margin})
 	}
 }
 
@@ -74,12 +96,27 @@ func updateProduct(productUseCase usecase.IProductUseCase) gin.HandlerFunc {
 			return
 		}
 
-		product, err := productUseCase.Update(id, &payload)
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		product, err := productUseCase.Update(actorFromContext(c), id, &payload)
 		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidProductFiscalData) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product"})
 			return
 		}
 
+		setETag(c, product.Version)
 		c.JSON(http.StatusOK, product)
 	}
 }
@@ -92,7 +129,7 @@ func deleteProduct(productUseCase usecase.IProductUseCase) gin.HandlerFunc {
 			return
 		}
 
-		err := productUseCase.Delete(id)
+		err := productUseCase.Delete(actorFromContext(c), id)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete product"})
 			return
@@ -113,12 +150,22 @@ func updateProductPicture(productUseCase usecase.IProductUseCase) gin.HandlerFun
 				return
 			}
 
-			product, err := productUseCase.SetPicture(id, file)
+			expectedVersion, err := ifMatchVersion(c)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+				return
+			}
+
+			product, err := productUseCase.SetPicture(actorFromContext(c), id, file, expectedVersion)
 			if err != nil {
+				if respondConcurrencyConflict(c, err) {
+					return
+				}
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product picture"})
 				return
 			}
 
+			setETag(c, product.Version)
 			c.JSON(http.StatusOK, product)
 		},
 	)	
@@ -132,12 +179,51 @@ func deleteProductPicture(productUseCase usecase.IProductUseCase) gin.HandlerFun
 			return
 		}
 
-		product, err := productUseCase.DeletePicture(id)
+		expectedVersion, err := ifMatchVersion(c)
 		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+
+		product, err := productUseCase.DeletePicture(actorFromContext(c), id, expectedVersion)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete product picture"})
 			return
 		}
 
+		setETag(c, product.Version)
 		c.JSON(http.StatusOK, product)
 	}
 }
+
+func reorderProducts(productUseCase usecase.IProductUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload reorderPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		products, err := productUseCase.Reorder(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), payload.Ids)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
+			if errors.Is(err, usecase.ErrCategoryNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidProductOrder) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, products)
+	}
+}
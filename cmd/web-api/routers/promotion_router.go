@@ -0,0 +1,128 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPromotionRoutes(
+	routerGroup *gin.RouterGroup,
+	promotionUseCase usecase.IPromotionUseCase,
+) {
+	group := routerGroup.Group("/promotions")
+	group.POST("/", createPromotion(promotionUseCase))
+	group.GET("/", getPromotions(promotionUseCase))
+	group.GET("/:id", getPromotionById(promotionUseCase))
+	group.PUT("/:id", updatePromotion(promotionUseCase))
+	group.DELETE("/:id", deletePromotion(promotionUseCase))
+}
+
+func createPromotion(useCase usecase.IPromotionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.PromotionPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		promotion, err := useCase.Create(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondPromotionError(c, err)
+			return
+		}
+
+		setETag(c, promotion.Version)
+		c.JSON(http.StatusCreated, promotion)
+	}
+}
+
+func getPromotions(useCase usecase.IPromotionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		promotions, err := useCase.FindByRestaurantId(c.Param("restaurantId"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, promotions)
+	}
+}
+
+func getPromotionById(useCase usecase.IPromotionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		promotion, err := useCase.FindById(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondPromotionError(c, err)
+			return
+		}
+
+		setETag(c, promotion.Version)
+		c.JSON(http.StatusOK, promotion)
+	}
+}
+
+func updatePromotion(useCase usecase.IPromotionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.PromotionPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		promotion, err := useCase.Update(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondPromotionError(c, err)
+			return
+		}
+
+		setETag(c, promotion.Version)
+		c.JSON(http.StatusOK, promotion)
+	}
+}
+
+func deletePromotion(useCase usecase.IPromotionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := useCase.Delete(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondPromotionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusNoContent, nil)
+	}
+}
+
+func respondPromotionError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrPromotionNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrPromotionCodeAlreadyExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidPromotion) ||
+		errors.Is(err, usecase.ErrProductNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,215 @@
+package routers
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterReportRoutes(
+	routerGroup *gin.RouterGroup,
+	reportUseCase usecase.IReportUseCase,
+) {
+	group := routerGroup.Group("/reports")
+	group.GET("/summary", getSalesSummary(reportUseCase))
+	group.GET("/sales/daily", getDailySales(reportUseCase))
+	group.GET("/sales/hourly", getHourlySales(reportUseCase))
+	group.GET("/sales/products", getProductSales(reportUseCase))
+	group.GET("/sales/categories", getCategorySales(reportUseCase))
+	group.GET("/sales/payment-methods", getPaymentMethodSales(reportUseCase))
+	group.GET("/customers/top", getTopCustomers(reportUseCase))
+}
+
+func getSalesSummary(useCase usecase.IReportUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		summary, err := useCase.Summary(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondReport(c, "summary", []dtos.SalesSummaryDto{*summary},
+			[]string{"from", "to", "time_zone", "orders", "cancelled", "cancellation_rate_percent", "gross", "discount", "net", "average_ticket", "deliveries", "pickups", "delivery_share_percent"},
+			func(row dtos.SalesSummaryDto) []string {
+				return []string{
+					row.From, row.To, row.TimeZone, strconv.Itoa(row.Orders), strconv.Itoa(row.Cancelled),
+					formatDecimal(row.CancellationRatePercent), formatDecimal(row.Gross), formatDecimal(row.Discount),
+					formatDecimal(row.Net), formatDecimal(row.AverageTicket), strconv.Itoa(row.Deliveries),
+					strconv.Itoa(row.Pickups), formatDecimal(row.DeliverySharePercent),
+				}
+			})
+	}
+}
+
+func getDailySales(useCase usecase.IReportUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		days, err := useCase.SalesByDay(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondReport(c, "sales-daily", days,
+			[]string{"date", "orders", "gross", "discount", "net", "average_ticket"},
+			func(row dtos.DailySalesDto) []string {
+				return []string{
+					row.Date, strconv.Itoa(row.Orders), formatDecimal(row.Gross), formatDecimal(row.Discount),
+					formatDecimal(row.Net), formatDecimal(row.AverageTicket),
+				}
+			})
+	}
+}
+
+func getHourlySales(useCase usecase.IReportUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hours, err := useCase.SalesByHour(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondReport(c, "sales-hourly", hours,
+			[]string{"hour", "orders", "gross", "discount", "net", "average_ticket"},
+			func(row dtos.HourlySalesDto) []string {
+				return []string{
+					strconv.Itoa(row.Hour), strconv.Itoa(row.Orders), formatDecimal(row.Gross), formatDecimal(row.Discount),
+					formatDecimal(row.Net), formatDecimal(row.AverageTicket),
+				}
+			})
+	}
+}
+
+func getProductSales(useCase usecase.IReportUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		products, err := useCase.SalesByProduct(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondReport(c, "sales-products", products,
+			[]string{"product_id", "product_name", "quantity", "revenue", "share_percent"},
+			func(row dtos.ProductSalesDto) []string {
+				return []string{
+					row.ProductId, row.ProductName, strconv.Itoa(row.Quantity), formatDecimal(row.Revenue),
+					formatDecimal(row.SharePercent),
+				}
+			})
+	}
+}
+
+func getCategorySales(useCase usecase.IReportUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		categories, err := useCase.SalesByCategory(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondReport(c, "sales-categories", categories,
+			[]string{"category_id", "category_name", "quantity", "revenue", "share_percent"},
+			func(row dtos.CategorySalesDto) []string {
+				return []string{
+					row.CategoryId, row.CategoryName, strconv.Itoa(row.Quantity), formatDecimal(row.Revenue),
+					formatDecimal(row.SharePercent),
+				}
+			})
+	}
+}
+
+func getPaymentMethodSales(useCase usecase.IReportUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		methods, err := useCase.SalesByPaymentMethod(c.Param("restaurantId"), reportPeriodFromQuery(c))
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondReport(c, "sales-payment-methods", methods,
+			[]string{"payment_method", "payments", "amount", "share_percent"},
+			func(row dtos.PaymentMethodSalesDto) []string {
+				return []string{
+					row.PaymentMethod, strconv.Itoa(row.Payments), formatDecimal(row.Amount), formatDecimal(row.SharePercent),
+				}
+			})
+	}
+}
+
+func getTopCustomers(useCase usecase.IReportUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := 0
+		if value := c.Query("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+				return
+			}
+			limit = parsed
+		}
+
+		customers, err := useCase.TopCustomers(c.Param("restaurantId"), reportPeriodFromQuery(c), limit)
+		if err != nil {
+			respondReportError(c, err)
+			return
+		}
+
+		respondReport(c, "top-customers", customers,
+			[]string{"customer_id", "name", "orders", "spent", "average_ticket"},
+			func(row dtos.CustomerSalesDto) []string {
+				return []string{
+					row.CustomerId, row.Name, strconv.Itoa(row.Orders), formatDecimal(row.Spent), formatDecimal(row.AverageTicket),
+				}
+			})
+	}
+}
+
+func reportPeriodFromQuery(c *gin.Context) usecase.ReportPeriodPayload {
+	return usecase.ReportPeriodPayload{
+		From:     c.Query("from"),
+		To:       c.Query("to"),
+		TimeZone: c.Query("tz"),
+	}
+}
+
+// respondReport devolve JSON ou, com ?format=csv, o mesmo relatório como planilha para download
+func respondReport[T any](c *gin.Context, name string, rows []T, header []string, record func(T) []string) {
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, rows)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write(header)
+	for _, row := range rows {
+		_ = writer.Write(record(row))
+	}
+	writer.Flush()
+}
+
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func respondReportError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidReportPeriod) ||
+		errors.Is(err, usecase.ErrInvalidTimeZone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -1,6 +1,7 @@
 package routers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
@@ -26,8 +27,12 @@ func createRestaurant(useCase usecase.IRestaurantUseCase) gin.HandlerFunc {
 			return
 		}
 
-		restaurant, err := useCase.Create(&payload)
+		restaurant, err := useCase.Create(actorFromContext(c), &payload)
 		if err != nil {
+			if errors.Is(err, usecase.ErrInvalidAddress) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
@@ -45,12 +50,27 @@ func updateRestaurant(useCase usecase.IRestaurantUseCase) gin.HandlerFunc {
 			return
 		}
 
-		restaurant, err := useCase.Update(id, &payload)
+		expectedVersion, err := ifMatchVersion(c)
 		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		restaurant, err := useCase.Update(actorFromContext(c), id, &payload)
+		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
+			if errors.Is(err, usecase.ErrInvalidAddress) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
 
+		setETag(c, restaurant.Version)
 		c.JSON(http.StatusOK, restaurant)
 	}
 }
@@ -65,6 +85,7 @@ func getRestaurantById(useCase usecase.IRestaurantUseCase) gin.HandlerFunc {
 			return
 		}
 
+		setETag(c, restaurant.Version)
 		c.JSON(http.StatusOK, restaurant)
 	}
 }
@@ -91,7 +112,7 @@ func deleteRestaurant(useCase usecase.IRestaurantUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 
-		if err := useCase.Delete(id); err != nil {
+		if err := useCase.Delete(actorFromContext(c), id); err != nil {
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
@@ -109,12 +130,23 @@ func setRestaurantImages(useCase usecase.IRestaurantUseCase) gin.HandlerFunc {
 			return
 		}
 
-		restaurant, err := useCase.SetImages(id, &payload)
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		restaurant, err := useCase.SetImages(actorFromContext(c), id, &payload)
 		if err != nil {
+			if respondConcurrencyConflict(c, err) {
+				return
+			}
 			c.JSON(http.StatusInternalServerError, err)
 			return
 		}
 
+		setETag(c, restaurant.Version)
 		c.JSON(http.StatusOK, restaurant)
 	}
 }
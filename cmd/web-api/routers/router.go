@@ -2,6 +2,9 @@ package routers
 
 import (
 	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/middleware"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
 	"github.com/gin-gonic/gin"
 )
 
@@ -11,10 +14,43 @@ func RegisterRoutes(
 	productUseCase usecase.IProductUseCase,
 	dishUseCase usecase.IDishUseCase,
 	restaurantUseCase usecase.IRestaurantUseCase,
+	auditUseCase usecase.IAuditUseCase,
+	customerUseCase usecase.ICustomerUseCase,
+	customerTabUseCase usecase.ICustomerTabUseCase,
+	orderUseCase usecase.IOrderUseCase,
+	kitchenUseCase usecase.IKitchenUseCase,
+	ticketUseCase usecase.ITicketUseCase,
+	fiscalDocumentUseCase usecase.IFiscalDocumentUseCase,
+	promotionUseCase usecase.IPromotionUseCase,
+	loyaltyUseCase usecase.ILoyaltyUseCase,
+	inventoryUseCase usecase.IInventoryUseCase,
+	menuUseCase usecase.IMenuUseCase,
+	reportUseCase usecase.IReportUseCase,
+	marginUseCase usecase.IMarginUseCase,
+	cashRegisterUseCase usecase.ICashRegisterUseCase,
+	supplierUseCase usecase.ISupplierUseCase,
+	billUseCase usecase.IBillUseCase,
+	cashFlowUseCase usecase.ICashFlowUseCase,
+	notificationUseCase usecase.INotificationUseCase,
+	webhookUseCase usecase.IWebhookUseCase,
+	marketplaceUseCase usecase.IMarketplaceUseCase,
+	addressUseCase usecase.IAddressUseCase,
+	deliveryZoneUseCase usecase.IDeliveryZoneUseCase,
+	driverUseCase usecase.IDriverUseCase,
+	deliveryRouteUseCase usecase.IDeliveryRouteUseCase,
+	catalogUseCase usecase.ICatalogUseCase,
+	authentication gin.HandlerFunc,
+	idempotency gin.HandlerFunc,
 ) {
 	apiGroup := engine.Group("/api")
 
-	registerV1(apiGroup, categoryUseCase, productUseCase, dishUseCase, restaurantUseCase)
+	// rotas públicas ficam fora da autenticação
+	publicGroup := apiGroup.Group("/public")
+	RegisterCatalogRoutes(publicGroup, catalogUseCase)
+	RegisterMarketplaceWebhookRoutes(publicGroup, marketplaceUseCase)
+	RegisterAddressRoutes(publicGroup, addressUseCase)
+
+	registerV1(apiGroup, categoryUseCase, productUseCase, dishUseCase, restaurantUseCase, auditUseCase, customerUseCase, customerTabUseCase, orderUseCase, kitchenUseCase, ticketUseCase, fiscalDocumentUseCase, promotionUseCase, loyaltyUseCase, inventoryUseCase, menuUseCase, reportUseCase, marginUseCase, cashRegisterUseCase, supplierUseCase, billUseCase, cashFlowUseCase, notificationUseCase, webhookUseCase, marketplaceUseCase, addressUseCase, deliveryZoneUseCase, driverUseCase, deliveryRouteUseCase, authentication, idempotency)
 }
 
 func registerV1(
@@ -23,12 +59,76 @@ func registerV1(
 	productUseCase usecase.IProductUseCase,
 	dishUseCase usecase.IDishUseCase,
 	restaurantUseCase usecase.IRestaurantUseCase,
+	auditUseCase usecase.IAuditUseCase,
+	customerUseCase usecase.ICustomerUseCase,
+	customerTabUseCase usecase.ICustomerTabUseCase,
+	orderUseCase usecase.IOrderUseCase,
+	kitchenUseCase usecase.IKitchenUseCase,
+	ticketUseCase usecase.ITicketUseCase,
+	fiscalDocumentUseCase usecase.IFiscalDocumentUseCase,
+	promotionUseCase usecase.IPromotionUseCase,
+	loyaltyUseCase usecase.ILoyaltyUseCase,
+	inventoryUseCase usecase.IInventoryUseCase,
+	menuUseCase usecase.IMenuUseCase,
+	reportUseCase usecase.IReportUseCase,
+	marginUseCase usecase.IMarginUseCase,
+	cashRegisterUseCase usecase.ICashRegisterUseCase,
+	supplierUseCase usecase.ISupplierUseCase,
+	billUseCase usecase.IBillUseCase,
+	cashFlowUseCase usecase.ICashFlowUseCase,
+	notificationUseCase usecase.INotificationUseCase,
+	webhookUseCase usecase.IWebhookUseCase,
+	marketplaceUseCase usecase.IMarketplaceUseCase,
+	addressUseCase usecase.IAddressUseCase,
+	deliveryZoneUseCase usecase.IDeliveryZoneUseCase,
+	driverUseCase usecase.IDriverUseCase,
+	deliveryRouteUseCase usecase.IDeliveryRouteUseCase,
+	authentication gin.HandlerFunc,
+	idempotency gin.HandlerFunc,
 ) {
-	v1Group := apiGroup.Group("/v1/restaurants")
+	RegisterAddressRoutes(apiGroup.Group("/v1", authentication), addressUseCase)
+
+	v1Group := apiGroup.Group("/v1/restaurants", authentication)
 	RegisterRestaurantRoutes(v1Group, restaurantUseCase)
 	
 	restaurantGroup := v1Group.Group("/:restaurantId")
 	RegisterCategoryRoutes(restaurantGroup, categoryUseCase)
-	RegisterProductRoutes(restaurantGroup, productUseCase)
+	RegisterProductRoutes(restaurantGroup, productUseCase, marginUseCase)
 	RegisterDishRoutes(restaurantGroup, dishUseCase)
+	RegisterAuditRoutes(restaurantGroup, auditUseCase)
+	RegisterCustomerRoutes(restaurantGroup, customerUseCase, idempotency)
+	RegisterCustomerTabRoutes(restaurantGroup, customerTabUseCase, idempotency)
+	RegisterOrderRoutes(restaurantGroup, orderUseCase, idempotency)
+	RegisterKitchenRoutes(restaurantGroup, kitchenUseCase)
+	RegisterTicketRoutes(restaurantGroup, ticketUseCase)
+	RegisterFiscalRoutes(restaurantGroup, fiscalDocumentUseCase, idempotency)
+	RegisterPromotionRoutes(restaurantGroup, promotionUseCase)
+	RegisterLoyaltyRoutes(restaurantGroup, loyaltyUseCase)
+	RegisterInventoryRoutes(restaurantGroup, inventoryUseCase, idempotency)
+	RegisterMenuRoutes(restaurantGroup, menuUseCase)
+	RegisterReportRoutes(restaurantGroup, reportUseCase)
+	RegisterMarginRoutes(restaurantGroup, marginUseCase)
+	RegisterCashRoutes(restaurantGroup, cashRegisterUseCase, idempotency)
+	RegisterSupplierRoutes(restaurantGroup, supplierUseCase)
+	RegisterBillRoutes(restaurantGroup, billUseCase, idempotency)
+	RegisterCashFlowRoutes(restaurantGroup, cashFlowUseCase)
+	RegisterNotificationRoutes(restaurantGroup, notificationUseCase)
+	RegisterWebhookRoutes(restaurantGroup, webhookUseCase, idempotency)
+	RegisterMarketplaceRoutes(restaurantGroup, marketplaceUseCase, idempotency)
+	RegisterDeliveryZoneRoutes(restaurantGroup, deliveryZoneUseCase, idempotency)
+	RegisterDriverRoutes(restaurantGroup, driverUseCase, deliveryRouteUseCase, idempotency)
+	RegisterDeliveryRouteRoutes(restaurantGroup, deliveryRouteUseCase, idempotency)
+}
+
+func actorFromContext(c *gin.Context) types.Actor {
+	actor := types.Actor{Ip: c.ClientIP()}
+
+	if principal, ok := c.Get(middleware.PrincipalKey); ok {
+		if payload, ok := principal.(ports.AuthPayload); ok {
+			actor.Email = payload.Email
+			actor.Role = payload.Role
+		}
+	}
+
+	return actor
 }
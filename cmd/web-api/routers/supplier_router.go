@@ -0,0 +1,127 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSupplierRoutes(
+	routerGroup *gin.RouterGroup,
+	supplierUseCase usecase.ISupplierUseCase,
+) {
+	group := routerGroup.Group("/suppliers", requireManager)
+	group.POST("/", createSupplier(supplierUseCase))
+	group.GET("/", getSuppliers(supplierUseCase))
+	group.GET("/:id", getSupplierById(supplierUseCase))
+	group.PUT("/:id", updateSupplier(supplierUseCase))
+	group.DELETE("/:id", deleteSupplier(supplierUseCase))
+}
+
+func createSupplier(useCase usecase.ISupplierUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.SupplierPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		supplier, err := useCase.Create(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondSupplierError(c, err)
+			return
+		}
+
+		setETag(c, supplier.Version)
+		c.JSON(http.StatusCreated, supplier)
+	}
+}
+
+func getSuppliers(useCase usecase.ISupplierUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		suppliers, err := useCase.FindByRestaurantId(c.Param("restaurantId"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, suppliers)
+	}
+}
+
+func getSupplierById(useCase usecase.ISupplierUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		supplier, err := useCase.FindById(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondSupplierError(c, err)
+			return
+		}
+
+		setETag(c, supplier.Version)
+		c.JSON(http.StatusOK, supplier)
+	}
+}
+
+func updateSupplier(useCase usecase.ISupplierUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.SupplierPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		supplier, err := useCase.Update(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondSupplierError(c, err)
+			return
+		}
+
+		setETag(c, supplier.Version)
+		c.JSON(http.StatusOK, supplier)
+	}
+}
+
+func deleteSupplier(useCase usecase.ISupplierUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := useCase.Delete(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondSupplierError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusNoContent, nil)
+	}
+}
+
+func respondSupplierError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrSupplierNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrSupplierDocumentAlreadyExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidSupplier) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,78 @@
+package routers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/pkg/escpos"
+	"github.com/gin-gonic/gin"
+)
+
+type renderTicket func(restaurantId, orderId string, paper escpos.PaperWidth) (*escpos.Document, error)
+
+func RegisterTicketRoutes(
+	routerGroup *gin.RouterGroup,
+	ticketUseCase usecase.ITicketUseCase,
+) {
+	group := routerGroup.Group("/orders/:id/tickets")
+	group.GET("/kitchen", getTicket("kitchen", ticketUseCase.KitchenTicket))
+	group.GET("/receipt", getTicket("receipt", ticketUseCase.Receipt))
+
+	routerGroup.GET("/orders/:id/receipt.pdf", getReceiptPdf(ticketUseCase))
+}
+
+// getTicket devolve os bytes ESC/POS para a impressora ou, com ?format=text, a prévia em texto
+func getTicket(name string, render renderTicket) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		paper, ok := escpos.ParsePaperWidth(c.DefaultQuery("paper", "80"))
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid paper, expected 58 or 80"})
+			return
+		}
+
+		format := c.DefaultQuery("format", "escpos")
+		if format != "escpos" && format != "text" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected escpos or text"})
+			return
+		}
+
+		ticket, err := render(c.Param("restaurantId"), c.Param("id"), paper)
+		if err != nil {
+			respondTicketError(c, err)
+			return
+		}
+
+		if format == "text" {
+			c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(ticket.Text()))
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-%s.bin", name, c.Param("id")))
+		c.Data(http.StatusOK, "application/octet-stream", ticket.Bytes())
+	}
+}
+
+func getReceiptPdf(useCase usecase.ITicketUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		receipt, err := useCase.ReceiptPdf(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondTicketError(c, err)
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=recibo-%s.pdf", c.Param("id")))
+		c.Data(http.StatusOK, "application/pdf", receipt)
+	}
+}
+
+func respondTicketError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrOrderNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
@@ -0,0 +1,187 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	webhookdeliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/webhook_delivery_status"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterWebhookRoutes(
+	routerGroup *gin.RouterGroup,
+	webhookUseCase usecase.IWebhookUseCase,
+	idempotency gin.HandlerFunc,
+) {
+	group := routerGroup.Group("/webhooks", requireManager)
+	group.POST("/", idempotency, createWebhook(webhookUseCase))
+	group.GET("/", getWebhooks(webhookUseCase))
+	group.GET("/deliveries", getWebhookDeliveries(webhookUseCase))
+	group.POST("/deliveries/:deliveryId/redeliver", redeliverWebhook(webhookUseCase))
+	group.GET("/:id", getWebhookById(webhookUseCase))
+	group.PUT("/:id", updateWebhook(webhookUseCase))
+	group.DELETE("/:id", deleteWebhook(webhookUseCase))
+	group.POST("/:id/test", testWebhook(webhookUseCase))
+	group.GET("/:id/deliveries", getWebhookDeliveries(webhookUseCase))
+}
+
+func createWebhook(useCase usecase.IWebhookUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.WebhookSubscriptionPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		subscription, err := useCase.Create(actorFromContext(c), c.Param("restaurantId"), &payload)
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+
+		setETag(c, subscription.Version)
+		c.JSON(http.StatusCreated, subscription)
+	}
+}
+
+func getWebhooks(useCase usecase.IWebhookUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subscriptions, err := useCase.Find(c.Param("restaurantId"))
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, subscriptions)
+	}
+}
+
+func getWebhookById(useCase usecase.IWebhookUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subscription, err := useCase.FindById(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+
+		setETag(c, subscription.Version)
+		c.JSON(http.StatusOK, subscription)
+	}
+}
+
+func updateWebhook(useCase usecase.IWebhookUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload usecase.WebhookSubscriptionPayload
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, err)
+			return
+		}
+
+		expectedVersion, err := ifMatchVersion(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+			return
+		}
+		payload.ExpectedVersion = expectedVersion
+
+		subscription, err := useCase.Update(actorFromContext(c), c.Param("restaurantId"), c.Param("id"), &payload)
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+
+		setETag(c, subscription.Version)
+		c.JSON(http.StatusOK, subscription)
+	}
+}
+
+func deleteWebhook(useCase usecase.IWebhookUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := useCase.Delete(actorFromContext(c), c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusNoContent, nil)
+	}
+}
+
+// testWebhook responde com a entrega, inclusive quando o integrador recusou o teste
+func testWebhook(useCase usecase.IWebhookUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		delivery, err := useCase.SendTest(c.Param("restaurantId"), c.Param("id"))
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, delivery)
+	}
+}
+
+// getWebhookDeliveries aceita ?status= e ?limit=; ?status=dead é a lista de entregas mortas
+func getWebhookDeliveries(useCase usecase.IWebhookUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := usecase.WebhookDeliveryFilterPayload{
+			SubscriptionId: c.Param("id"),
+			Status:         webhookdeliverystatus.WebhookDeliveryStatus(c.Query("status")),
+		}
+
+		if value := c.Query("limit"); value != "" {
+			limit, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		deliveries, err := useCase.FindDeliveries(c.Param("restaurantId"), filter)
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, deliveries)
+	}
+}
+
+func redeliverWebhook(useCase usecase.IWebhookUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		delivery, err := useCase.Redeliver(actorFromContext(c), c.Param("restaurantId"), c.Param("deliveryId"))
+		if err != nil {
+			respondWebhookError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, delivery)
+	}
+}
+
+func respondWebhookError(c *gin.Context, err error) {
+	if respondConcurrencyConflict(c, err) {
+		return
+	}
+
+	if errors.Is(err, usecase.ErrWebhookSubscriptionNotFound) ||
+		errors.Is(err, usecase.ErrWebhookDeliveryNotFound) ||
+		errors.Is(err, usecase.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrWebhookDeliveryNotDead) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, usecase.ErrInvalidWebhookSubscription) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, err)
+}
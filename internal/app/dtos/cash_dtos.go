@@ -0,0 +1,29 @@
+package dtos
+
+import "time"
+
+type (
+	// CashShiftDto é a conferência de um turno fechado; diferença positiva é sobra, negativa é falta
+	CashShiftDto struct {
+		SessionId  string    `json:"session_id"`
+		Operator   string    `json:"operator"`
+		OpenedAt   time.Time `json:"opened_at"`
+		ClosedAt   time.Time `json:"closed_at"`
+		ClosedBy   string    `json:"closed_by"`
+		Expected   float64   `json:"expected"`
+		Counted    float64   `json:"counted"`
+		Difference float64   `json:"difference"`
+		Note       string    `json:"note,omitempty"`
+	}
+
+	CashOperatorDto struct {
+		Operator   string  `json:"operator"`
+		Shifts     int     `json:"shifts"`
+		ShiftsOff  int     `json:"shifts_off"`
+		Expected   float64 `json:"expected"`
+		Counted    float64 `json:"counted"`
+		Difference float64 `json:"difference"`
+		Shortage   float64 `json:"shortage"`
+		Overage    float64 `json:"overage"`
+	}
+)
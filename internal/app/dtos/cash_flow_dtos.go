@@ -0,0 +1,39 @@
+package dtos
+
+type (
+	// CashFlowEntryDto é um valor previsto; Source é tab (fiado), order (pedido com saldo) ou bill (conta a pagar)
+	CashFlowEntryDto struct {
+		Source      string  `json:"source"`
+		ReferenceId string  `json:"reference_id"`
+		Description string  `json:"description"`
+		DueDate     string  `json:"due_date"`
+		Amount      float64 `json:"amount"`
+		Overdue     bool    `json:"overdue"`
+		// ocorrência de conta recorrente que ainda não foi gerada
+		Projected bool `json:"projected,omitempty"`
+	}
+
+	// CashFlowDayDto soma os valores do dia; o que está vencido entra no primeiro dia
+	CashFlowDayDto struct {
+		Date        string  `json:"date"`
+		Receivables float64 `json:"receivables"`
+		Payables    float64 `json:"payables"`
+		Net         float64 `json:"net"`
+		Balance     float64 `json:"balance"`
+	}
+
+	CashFlowDto struct {
+		From               string             `json:"from"`
+		To                 string             `json:"to"`
+		TimeZone           string             `json:"time_zone"`
+		OpeningBalance     float64            `json:"opening_balance"`
+		Receivables        float64            `json:"receivables"`
+		Payables           float64            `json:"payables"`
+		OverdueReceivables float64            `json:"overdue_receivables"`
+		OverduePayables    float64            `json:"overdue_payables"`
+		ClosingBalance     float64            `json:"closing_balance"`
+		Days               []CashFlowDayDto   `json:"days"`
+		ReceivableEntries  []CashFlowEntryDto `json:"receivable_entries"`
+		PayableEntries     []CashFlowEntryDto `json:"payable_entries"`
+	}
+)
@@ -0,0 +1,112 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	dishtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/dish_type"
+)
+
+// DTOs do catálogo público: expõem apenas o que a vitrine precisa, sem dados fiscais ou de custo
+type (
+	CatalogRestaurantDto struct {
+		TradeName             string `json:"trade_name"`
+		Slug                  string `json:"slug"`
+		LogoUrl               string `json:"logo_url"`
+		BannerUrl             string `json:"banner_url"`
+		ContactPhone          string `json:"contact_phone"`
+		WhatsAppPhone         string `json:"whatsapp_phone"`
+		Address               string `json:"address"`
+		DeliveryEnabled       bool   `json:"delivery_enabled"`
+		DeliveryMinimumOrder  int    `json:"delivery_minimum_order_value"`
+		EcommerceMinimumOrder int    `json:"ecommerce_minimum_order_value"`
+	}
+
+	CatalogProductDto struct {
+		Id          string                 `json:"id"`
+		Name        string                 `json:"name"`
+		Description string                 `json:"description"`
+		SalesPrice  string                 `json:"sales_price"`
+		PictureUrl  string                 `json:"picture_url"`
+		DishTypeMap aggregates.DishTypeMap `json:"dish_type_map"`
+	}
+
+	CatalogCategoryDto struct {
+		Id         string              `json:"id"`
+		Name       string              `json:"name"`
+		PictureUrl string              `json:"picture_url"`
+		Priority   int                 `json:"priority"`
+		Products   []CatalogProductDto `json:"products"`
+	}
+
+	CatalogMenuItemDto struct {
+		Id                    string            `json:"id"`
+		DishName              string            `json:"dish_name"`
+		DishType              dishtype.DishType `json:"dish_type"`
+		PictureUrl            string            `json:"picture_url"`
+		AdditionalPrice       float64           `json:"additional_price"`
+		CanBeUsedAsAdditional bool              `json:"can_be_used_as_additional"`
+		SoldOut               bool              `json:"sold_out"`
+	}
+
+	CatalogMenuDto struct {
+		OfferDate  string               `json:"offer_date"`
+		PictureUrl string               `json:"picture_url"`
+		Items      []CatalogMenuItemDto `json:"items"`
+	}
+
+	CatalogDto struct {
+		Restaurant CatalogRestaurantDto `json:"restaurant"`
+		Categories []CatalogCategoryDto `json:"categories"`
+		Menu       *CatalogMenuDto      `json:"menu"`
+	}
+)
+
+func MapRestaurantToCatalogDto(restaurant *aggregates.Restaurant) CatalogRestaurantDto {
+	return CatalogRestaurantDto{
+		TradeName:             restaurant.TradeName,
+		Slug:                  restaurant.Slug,
+		LogoUrl:               restaurant.LogoUrl,
+		BannerUrl:             restaurant.BannerUrl,
+		ContactPhone:          restaurant.ContactPhone,
+		WhatsAppPhone:         restaurant.WhatsAppPhone,
+		Address:               restaurant.Address.String(),
+		DeliveryEnabled:       restaurant.Settings.Delivery.Enabled,
+		DeliveryMinimumOrder:  restaurant.Settings.Delivery.MinimumOrderValue,
+		EcommerceMinimumOrder: restaurant.Settings.Ecommerce.MinimumOrderValue,
+	}
+}
+
+func MapProductToCatalogDto(product *aggregates.Product) CatalogProductDto {
+	return CatalogProductDto{
+		Id:          product.Id,
+		Name:        product.Name,
+		Description: product.Description,
+		SalesPrice:  product.SalesPrice,
+		PictureUrl:  product.PictureUrl,
+		DishTypeMap: product.DishTypeMap,
+	}
+}
+
+func MapMenuToCatalogDto(menu *aggregates.Menu) *CatalogMenuDto {
+	enabled := menu.EnabledItems()
+	items := make([]CatalogMenuItemDto, 0, len(enabled))
+	// itens esgotados continuam na vitrine, marcados, para o cliente saber que acabaram
+	for _, item := range enabled {
+		items = append(items, CatalogMenuItemDto{
+			Id:                    item.Id,
+			DishName:              item.Dish.Name,
+			DishType:              item.Dish.Type,
+			PictureUrl:            item.DishPictureUrl,
+			AdditionalPrice:       item.AdditionalPrice,
+			CanBeUsedAsAdditional: item.CanBeUsedAsAdditional,
+			SoldOut:               item.SoldOut(),
+		})
+	}
+
+	return &CatalogMenuDto{
+		OfferDate:  menu.OfferDate.Format(time.DateOnly),
+		PictureUrl: menu.PictureUrl,
+		Items:      items,
+	}
+}
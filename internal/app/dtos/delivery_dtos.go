@@ -0,0 +1,21 @@
+package dtos
+
+import "github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+
+type (
+	// DriverSettlementDto é o acerto do entregador no período: as taxas das entregas concluídas
+	DriverSettlementDto struct {
+		DriverId   string  `json:"driver_id"`
+		DriverName string  `json:"driver_name"`
+		Deliveries int     `json:"deliveries"`
+		Fees       float64 `json:"fees"`
+		AverageFee float64 `json:"average_fee"`
+	}
+
+	// DeliveryTrackingDto é a rota com a última posição do entregador e o trajeto desde a retirada
+	DeliveryTrackingDto struct {
+		Route    *aggregates.DeliveryRoute   `json:"route"`
+		Location *aggregates.DriverLocation  `json:"location,omitempty"`
+		Trail    []aggregates.DriverLocation `json:"trail"`
+	}
+)
@@ -0,0 +1,94 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	orderchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_channel"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+)
+
+type (
+	KitchenItemDto struct {
+		Id          string                          `json:"id"`
+		ProductName string                          `json:"product_name"`
+		Quantity    int                             `json:"quantity"`
+		Observation string                          `json:"observation"`
+		Status      orderstatus.OrderStatus         `json:"status"`
+		Lunchbox    *aggregates.LunchboxComposition `json:"lunchbox,omitempty"`
+	}
+
+	KitchenOrderDto struct {
+		Id           string                    `json:"id"`
+		Status       orderstatus.OrderStatus   `json:"status"`
+		Channel      orderchannel.OrderChannel `json:"channel"`
+		ExternalCode string                    `json:"external_code,omitempty"`
+		CustomerName string                    `json:"customer_name"`
+		IsDelivery   bool                      `json:"is_delivery"`
+		Observation  string                    `json:"observation"`
+		Items        []KitchenItemDto          `json:"items"`
+		CreatedAt    time.Time                 `json:"created_at"`
+		Version      int                       `json:"version"`
+	}
+
+	// KitchenQueueDto é a fila da cozinha agrupada por status
+	KitchenQueueDto struct {
+		Pending   []KitchenOrderDto `json:"pending"`
+		Preparing []KitchenOrderDto `json:"preparing"`
+		Ready     []KitchenOrderDto `json:"ready"`
+	}
+
+	KitchenEventDto struct {
+		Type  string          `json:"type"`
+		Order KitchenOrderDto `json:"order"`
+	}
+)
+
+func MapOrderToKitchenDto(order *aggregates.Order) KitchenOrderDto {
+	items := make([]KitchenItemDto, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, KitchenItemDto{
+			Id:          item.Id,
+			ProductName: item.Product.Name,
+			Quantity:    item.Quantity,
+			Observation: item.Observation,
+			Status:      item.Status,
+			Lunchbox:    item.Lunchbox,
+		})
+	}
+
+	return KitchenOrderDto{
+		Id:           order.Id,
+		Status:       order.Status,
+		Channel:      order.Channel,
+		ExternalCode: order.ExternalCode,
+		CustomerName: order.Customer.FirstName,
+		IsDelivery:   order.Delivery != nil,
+		Observation:  order.Observation,
+		Items:        items,
+		CreatedAt:    order.CreatedAt,
+		Version:      order.Version,
+	}
+}
+
+func NewKitchenQueueDto(orders []aggregates.Order) *KitchenQueueDto {
+	queue := &KitchenQueueDto{
+		Pending:   make([]KitchenOrderDto, 0),
+		Preparing: make([]KitchenOrderDto, 0),
+		Ready:     make([]KitchenOrderDto, 0),
+	}
+
+	for i := range orders {
+		dto := MapOrderToKitchenDto(&orders[i])
+		switch orders[i].Status {
+		case orderstatus.PENDING:
+			queue.Pending = append(queue.Pending, dto)
+		case orderstatus.PREPARING:
+			queue.Preparing = append(queue.Preparing, dto)
+		case orderstatus.READY:
+			queue.Ready = append(queue.Ready, dto)
+		}
+	}
+
+	return queue
+}
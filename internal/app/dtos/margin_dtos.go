@@ -0,0 +1,36 @@
+package dtos
+
+import "github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+
+type (
+	// ProductMarginDto junta a margem unitária pelos custos atuais com a contribuição do período.
+	// Nas marmitas o custo unitário inclui a média dos pratos realmente servidos no período.
+	ProductMarginDto struct {
+		ProductId           string                   `json:"product_id"`
+		ProductName         string                   `json:"product_name"`
+		CategoryId          string                   `json:"category_id"`
+		CategoryName        string                   `json:"category_name"`
+		Unit                aggregates.ProductMargin `json:"unit"`
+		QuantitySold        int                      `json:"quantity_sold"`
+		Revenue             float64                  `json:"revenue"`
+		Cost                float64                  `json:"cost"`
+		Contribution        float64                  `json:"contribution"`
+		ContributionPercent float64                  `json:"contribution_percent"`
+	}
+
+	CategoryMarginDto struct {
+		CategoryId          string  `json:"category_id"`
+		CategoryName        string  `json:"category_name"`
+		QuantitySold        int     `json:"quantity_sold"`
+		Revenue             float64 `json:"revenue"`
+		Cost                float64 `json:"cost"`
+		Contribution        float64 `json:"contribution"`
+		ContributionPercent float64 `json:"contribution_percent"`
+	}
+
+	// ManagedProductDto é o produto como a gestão vê, com a margem
+	ManagedProductDto struct {
+		*aggregates.Product
+		Margin *aggregates.ProductMargin `json:"margin,omitempty"`
+	}
+)
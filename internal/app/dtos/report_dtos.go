@@ -0,0 +1,69 @@
+package dtos
+
+type (
+	// SalesSummaryDto resume o período; percentuais vão de 0 a 100
+	SalesSummaryDto struct {
+		From                    string  `json:"from"`
+		To                      string  `json:"to"`
+		TimeZone                string  `json:"time_zone"`
+		Orders                  int     `json:"orders"`
+		Cancelled               int     `json:"cancelled"`
+		CancellationRatePercent float64 `json:"cancellation_rate_percent"`
+		Gross                   float64 `json:"gross"`
+		Discount                float64 `json:"discount"`
+		Net                     float64 `json:"net"`
+		AverageTicket           float64 `json:"average_ticket"`
+		Deliveries              int     `json:"deliveries"`
+		Pickups                 int     `json:"pickups"`
+		DeliverySharePercent    float64 `json:"delivery_share_percent"`
+	}
+
+	DailySalesDto struct {
+		Date          string  `json:"date"`
+		Orders        int     `json:"orders"`
+		Gross         float64 `json:"gross"`
+		Discount      float64 `json:"discount"`
+		Net           float64 `json:"net"`
+		AverageTicket float64 `json:"average_ticket"`
+	}
+
+	HourlySalesDto struct {
+		Hour          int     `json:"hour"`
+		Orders        int     `json:"orders"`
+		Gross         float64 `json:"gross"`
+		Discount      float64 `json:"discount"`
+		Net           float64 `json:"net"`
+		AverageTicket float64 `json:"average_ticket"`
+	}
+
+	ProductSalesDto struct {
+		ProductId    string  `json:"product_id"`
+		ProductName  string  `json:"product_name"`
+		Quantity     int     `json:"quantity"`
+		Revenue      float64 `json:"revenue"`
+		SharePercent float64 `json:"share_percent"`
+	}
+
+	CategorySalesDto struct {
+		CategoryId   string  `json:"category_id"`
+		CategoryName string  `json:"category_name"`
+		Quantity     int     `json:"quantity"`
+		Revenue      float64 `json:"revenue"`
+		SharePercent float64 `json:"share_percent"`
+	}
+
+	PaymentMethodSalesDto struct {
+		PaymentMethod string  `json:"payment_method"`
+		Payments      int     `json:"payments"`
+		Amount        float64 `json:"amount"`
+		SharePercent  float64 `json:"share_percent"`
+	}
+
+	CustomerSalesDto struct {
+		CustomerId    string  `json:"customer_id"`
+		Name          string  `json:"name"`
+		Orders        int     `json:"orders"`
+		Spent         float64 `json:"spent"`
+		AverageTicket float64 `json:"average_ticket"`
+	}
+)
@@ -7,15 +7,16 @@ import (
 
 type (
 	RestaurantPayload struct {
-		TradeName     string                        `json:"trade_name"`
-		LegalName     string                        `json:"legal_name"`
-		Cnpj          string                        `json:"cnpj"`
-		ContactPhone  string                        `json:"contact_phone"`
-		WhatsAppPhone string                        `json:"whatsapp_phone"`
-		Email         string                        `json:"email"`
-		Slug          string                        `json:"slug"`
-		Address       types.Address                 `json:"address"`
-		Settings      aggregates.RestaurantSettings `json:"settings"`
+		TradeName       string                        `json:"trade_name"`
+		LegalName       string                        `json:"legal_name"`
+		Cnpj            string                        `json:"cnpj"`
+		ContactPhone    string                        `json:"contact_phone"`
+		WhatsAppPhone   string                        `json:"whatsapp_phone"`
+		Email           string                        `json:"email"`
+		Slug            string                        `json:"slug"`
+		Address         types.Address                 `json:"address"`
+		Settings        aggregates.RestaurantSettings `json:"settings"`
+		ExpectedVersion int                           `json:"-"`
 	}
 
 	RestaurantDto struct {
@@ -32,11 +33,13 @@ type (
 		Settings      aggregates.RestaurantSettings `json:"settings"`
 		CreatedAt     string                        `json:"created_at"`
 		UpdatedAt     string                        `json:"updated_at"`
+		Version       int                           `json:"version"`
 	}
 
 	SetRestaurantImagesPayload struct {
-		Logo   *types.FilePayload
-		Banner *types.FilePayload
+		Logo            *types.FilePayload
+		Banner          *types.FilePayload
+		ExpectedVersion int `json:"-"`
 	}
 )
 
@@ -53,5 +56,6 @@ func MapRestauntToDto(restaurant *aggregates.Restaurant) *RestaurantDto {
 		LogoUrl:       restaurant.LogoUrl,
 		BannerUrl:     restaurant.BannerUrl,
 		Settings:      restaurant.Settings,
+		Version:       restaurant.Version,
 	}
 }
@@ -0,0 +1,14 @@
+package dtos
+
+import "time"
+
+type (
+	// WebhookEventDto é o corpo enviado aos webhooks; Data é o estado do agregado quando a entrega foi criada
+	WebhookEventDto struct {
+		Id           string    `json:"id"`
+		Event        string    `json:"event"`
+		OccurredAt   time.Time `json:"occurred_at"`
+		RestaurantId string    `json:"restaurant_id"`
+		Data         any       `json:"data"`
+	}
+)
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/enums/uf"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+const defaultGeocodeBackfillBatchSize = 100
+
+type (
+	// GeocodeBackfillPayload limita a quantidade de endereços (0 é sem limite) e espaça as chamadas
+	// ao provedor, que costuma ter limite de requisições por segundo
+	GeocodeBackfillPayload struct {
+		BatchSize int
+		Limit     int
+		Interval  time.Duration
+	}
+
+	GeocodeBackfillResult struct {
+		Processed int `json:"processed"`
+		Geocoded  int `json:"geocoded"`
+		NotFound  int `json:"not_found"`
+		Failed    int `json:"failed"`
+	}
+
+	IAddressUseCase interface {
+		LookupCep(cep string) (*types.Address, error)
+		BackfillCoordinates(payload GeocodeBackfillPayload) (*GeocodeBackfillResult, error)
+	}
+
+	addressUseCase struct {
+		addressLookup     ports.IAddressLookup
+		addressRepository ports.IAddressRepository
+		geocoder          ports.IGeocoder
+	}
+)
+
+func NewAddressUseCase(
+	addressLookup ports.IAddressLookup,
+	addressRepository ports.IAddressRepository,
+	geocoder ports.IGeocoder,
+) IAddressUseCase {
+	return &addressUseCase{
+		addressLookup:     addressLookup,
+		addressRepository: addressRepository,
+		geocoder:          geocoder,
+	}
+}
+
+func (u *addressUseCase) LookupCep(cep string) (*types.Address, error) {
+	return u.addressLookup.LookupCep(cep)
+}
+
+// BackfillCoordinates geocodifica os endereços de restaurantes e clientes que ainda estão em 0,0.
+// Endereços que o provedor não encontra continuam sem coordenadas e voltam na próxima execução
+func (u *addressUseCase) BackfillCoordinates(payload GeocodeBackfillPayload) (*GeocodeBackfillResult, error) {
+	batchSize := payload.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultGeocodeBackfillBatchSize
+	}
+
+	result := &GeocodeBackfillResult{}
+	afterId := ""
+	for {
+		addresses, err := u.addressRepository.FindWithoutCoordinates(afterId, batchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(addresses) == 0 {
+			return result, nil
+		}
+
+		for _, address := range addresses {
+			if payload.Limit > 0 && result.Processed >= payload.Limit {
+				return result, nil
+			}
+			if result.Processed > 0 && payload.Interval > 0 {
+				time.Sleep(payload.Interval)
+			}
+			result.Processed++
+
+			point, err := u.geocoder.Geocode(address)
+			if errors.Is(err, ports.ErrAddressNotGeocoded) {
+				result.NotFound++
+				continue
+			}
+			if err != nil {
+				log.Printf("⚠️ failed to geocode address %s: %v", address.Id, err)
+				result.Failed++
+				continue
+			}
+
+			err = u.addressRepository.UpdateCoordinates(address.Id, point)
+			if err != nil {
+				return result, err
+			}
+			result.Geocoded++
+		}
+
+		afterId = addresses[len(addresses)-1].Id
+	}
+}
+
+// normalizeAddress prepara o endereço para ser gravado: a UF vira sigla, o CEP fica só com os
+// dígitos e cidade e UF precisam bater com o CEP. Campos em branco são completados pelo CEP
+func normalizeAddress(addressLookup ports.IAddressLookup, address *types.Address) error {
+	if address.State != "" {
+		state, ok := uf.Parse(string(address.State))
+		if !ok {
+			return fmt.Errorf("%w: unknown state %q", ErrInvalidAddress, address.State)
+		}
+		address.State = state
+	}
+
+	if strings.TrimSpace(address.ZipCode) == "" {
+		return nil
+	}
+
+	found, err := addressLookup.LookupCep(address.ZipCode)
+	if errors.Is(err, ports.ErrInvalidCep) || errors.Is(err, ports.ErrCepNotFound) {
+		return fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	address.ZipCode = found.ZipCode
+	if address.State == "" {
+		address.State = found.State
+	} else if address.State != found.State {
+		return fmt.Errorf("%w: cep %s belongs to %s, not %s", ErrInvalidAddress, found.ZipCode, found.State, address.State)
+	}
+
+	// sem o serviço de CEP a cidade não tem com o que ser conferida
+	if found.City != "" {
+		if strings.TrimSpace(address.City) == "" {
+			address.City = found.City
+		} else if types.FoldName(address.City) != types.FoldName(found.City) {
+			return fmt.Errorf("%w: cep %s belongs to %s, not %s", ErrInvalidAddress, found.ZipCode, found.City, address.City)
+		}
+	}
+
+	if address.Street == "" {
+		address.Street = found.Street
+	}
+	if address.Neighborhood == "" {
+		address.Neighborhood = found.Neighborhood
+	}
+	if address.Country == "" {
+		address.Country = found.Country
+	}
+
+	return nil
+}
+
+// geocodeAddress preenche Lat/Lng de endereços novos ou que mudaram de lugar. Coordenadas enviadas
+// pelo cliente (pino no mapa) são mantidas; se o provedor falhar o endereço é salvo em 0,0 e o
+// cmd/geocode-backfill completa depois
+func geocodeAddress(geocoder ports.IGeocoder, address *types.Address, previous *types.Address) {
+	if previous != nil && address.Point() == previous.Point() && !address.SameLocation(*previous) {
+		address.Lat, address.Lng = 0, 0
+	}
+	if address.HasCoordinates() || (address.Street == "" && address.ZipCode == "") {
+		return
+	}
+
+	point, err := geocoder.Geocode(*address)
+	if err != nil {
+		log.Printf("⚠️ failed to geocode address: %v", err)
+		return
+	}
+	address.Lat, address.Lng = point.Lat, point.Lng
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+type (
+	IAuditUseCase interface {
+		Find(query ports.AuditLogQuery) (*types.PagedSlice[aggregates.AuditLog], error)
+	}
+
+	auditUseCase struct {
+		auditLogRepository ports.IAuditLogRepository
+	}
+)
+
+func NewAuditUseCase(auditLogRepository ports.IAuditLogRepository) IAuditUseCase {
+	return &auditUseCase{
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+func (a *auditUseCase) Find(query ports.AuditLogQuery) (*types.PagedSlice[aggregates.AuditLog], error) {
+	logs, err := a.auditLogRepository.Find(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// recordAudit anexa ao agregado quem alterou o quê; o repositório grava o registro na mesma
+// transação do agregado, então recordAudit vem antes do Create/Update/Delete.
+// before é nil na criação e after é nil na remoção.
+func recordAudit(
+	aggregate abstractions.IAggreagateRoot,
+	actor types.Actor,
+	restaurantId string,
+	aggregateType string,
+	action aggregates.AuditAction,
+	before any,
+	after any,
+) error {
+	diff, err := types.NewJsonDiff(before, after)
+	if err != nil {
+		return err
+	}
+
+	if action == aggregates.AuditActionUpdate && len(diff) == 0 {
+		return nil
+	}
+
+	aggregate.RecordAudit(abstractions.AuditRecord{
+		RestaurantId:  restaurantId,
+		Actor:         actor,
+		AggregateType: aggregateType,
+		Action:        string(action),
+		Diff:          diff,
+	})
+	return nil
+}
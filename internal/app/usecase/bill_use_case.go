@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	billcategory "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_category"
+	billrecurrence "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_recurrence"
+	billstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_status"
+	paymentmethod "github.com/PedroNetto404/marmitech-backend/pkg/enums/payment_method"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrBillNotFound       = errors.New("bill not found")
+	ErrInvalidBill        = errors.New("invalid bill")
+	ErrBillNotOpen        = errors.New("bill is not open")
+	ErrInvalidBillPayment = errors.New("payment must be positive and not exceed the bill balance")
+)
+
+type (
+	// BillPayload traz due_date como YYYY-MM-DD
+	BillPayload struct {
+		SupplierId      string                        `json:"supplier_id"`
+		Description     string                        `json:"description"`
+		Category        billcategory.BillCategory     `json:"category"`
+		Amount          float64                       `json:"amount"`
+		DueDate         string                        `json:"due_date"`
+		Recurrence      billrecurrence.BillRecurrence `json:"recurrence"`
+		Note            string                        `json:"note"`
+		ExpectedVersion int                           `json:"-"`
+	}
+
+	// BillPaymentPayload paga a conta; Amount zero quita o saldo inteiro
+	BillPaymentPayload struct {
+		Amount          float64                     `json:"amount"`
+		PaymentMethod   paymentmethod.PaymentMethod `json:"payment_method"`
+		PaidAt          *time.Time                  `json:"paid_at"`
+		ExpectedVersion int                         `json:"-"`
+	}
+
+	// BillFilterPayload traz datas de vencimento YYYY-MM-DD inclusivas
+	BillFilterPayload struct {
+		Status     billstatus.BillStatus
+		SupplierId string
+		From       string
+		To         string
+	}
+
+	IBillUseCase interface {
+		Find(restaurantId string, filter BillFilterPayload) ([]aggregates.Bill, error)
+		FindById(restaurantId, id string) (*aggregates.Bill, error)
+		Create(actor types.Actor, restaurantId string, payload *BillPayload) (*aggregates.Bill, error)
+		Update(actor types.Actor, restaurantId, id string, payload *BillPayload) (*aggregates.Bill, error)
+		Pay(actor types.Actor, restaurantId, id string, payload *BillPaymentPayload) (*aggregates.Bill, error)
+		Cancel(actor types.Actor, restaurantId, id string) (*aggregates.Bill, error)
+	}
+
+	billUseCase struct {
+		billRepository       ports.IBillRepository
+		supplierRepository   ports.ISupplierRepository
+		restaurantRepository ports.IRestaurantRepository
+	}
+)
+
+func NewBillUseCase(
+	billRepository ports.IBillRepository,
+	supplierRepository ports.ISupplierRepository,
+	restaurantRepository ports.IRestaurantRepository,
+) IBillUseCase {
+	return &billUseCase{
+		billRepository:       billRepository,
+		supplierRepository:   supplierRepository,
+		restaurantRepository: restaurantRepository,
+	}
+}
+
+func (u *billUseCase) Find(restaurantId string, filter BillFilterPayload) ([]aggregates.Bill, error) {
+	query := ports.BillQuery{
+		RestaurantId: restaurantId,
+		SupplierId:   filter.SupplierId,
+		Status:       filter.Status,
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown status", ErrInvalidBill)
+	}
+	if filter.From != "" {
+		from, err := time.Parse(time.DateOnly, filter.From)
+		if err != nil {
+			return nil, fmt.Errorf("%w: from must be YYYY-MM-DD", ErrInvalidBill)
+		}
+		query.DueFrom = &from
+	}
+	if filter.To != "" {
+		to, err := time.Parse(time.DateOnly, filter.To)
+		if err != nil {
+			return nil, fmt.Errorf("%w: to must be YYYY-MM-DD", ErrInvalidBill)
+		}
+		query.DueTo = &to
+	}
+
+	return u.billRepository.Find(query)
+}
+
+func (u *billUseCase) FindById(restaurantId, id string) (*aggregates.Bill, error) {
+	bill, err := u.billRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if bill == nil || bill.Restaurant.Id != restaurantId {
+		return nil, ErrBillNotFound
+	}
+
+	return bill, nil
+}
+
+func (u *billUseCase) Create(actor types.Actor, restaurantId string, payload *BillPayload) (*aggregates.Bill, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	dueDate, supplier, err := u.validate(restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	bill := aggregates.NewBill(restaurantId, payload.Description, payload.Category, payload.Amount, dueDate, payload.Recurrence)
+	bill.Supplier = supplier
+	bill.Note = strings.TrimSpace(payload.Note)
+
+	err = u.audit(actor, aggregates.AuditActionCreate, nil, bill)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.billRepository.Create(bill)
+	if err != nil {
+		return nil, err
+	}
+
+	return bill, nil
+}
+
+// Update só vale para contas em aberto; o valor não pode ficar abaixo do que já foi pago
+func (u *billUseCase) Update(actor types.Actor, restaurantId, id string, payload *BillPayload) (*aggregates.Bill, error) {
+	bill, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.BillAggregateType, bill.Id, bill.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if bill.Status != billstatus.OPEN {
+		return nil, ErrBillNotOpen
+	}
+
+	dueDate, supplier, err := u.validate(restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Amount <= bill.PaidAmount {
+		return nil, fmt.Errorf("%w: amount must be greater than the %.2f already paid", ErrInvalidBill, bill.PaidAmount)
+	}
+
+	before := *bill
+	bill.Supplier = supplier
+	bill.Description = payload.Description
+	bill.Category = payload.Category
+	bill.Amount = roundMoney(payload.Amount)
+	bill.DueDate = dueDate
+	bill.DueDay = dueDate.Day()
+	bill.Recurrence = payload.Recurrence
+	bill.Note = strings.TrimSpace(payload.Note)
+	bill.UpdatedAt = time.Now()
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, bill)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.billRepository.Update(bill)
+	if err != nil {
+		return nil, err
+	}
+
+	return bill, nil
+}
+
+// Pay registra o pagamento; quando a conta recorrente é quitada, a próxima ocorrência é criada junto
+func (u *billUseCase) Pay(actor types.Actor, restaurantId, id string, payload *BillPaymentPayload) (*aggregates.Bill, error) {
+	bill, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.BillAggregateType, bill.Id, bill.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if bill.Status != billstatus.OPEN {
+		return nil, ErrBillNotOpen
+	}
+	if !payload.PaymentMethod.IsValid() {
+		return nil, fmt.Errorf("%w: unknown payment method %q", ErrInvalidBillPayment, payload.PaymentMethod)
+	}
+
+	amount := payload.Amount
+	if amount == 0 {
+		amount = bill.Balance()
+	}
+	paidAt := time.Now()
+	if payload.PaidAt != nil {
+		paidAt = *payload.PaidAt
+	}
+
+	before := *bill
+	before.Payments = append([]aggregates.BillPayment(nil), bill.Payments...)
+
+	payment := bill.Pay(amount, payload.PaymentMethod, paidAt, actor.Email)
+	if payment == nil {
+		return nil, ErrInvalidBillPayment
+	}
+
+	var next *aggregates.Bill
+	if bill.Status == billstatus.PAID {
+		next = bill.Next()
+	}
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, bill)
+	if err != nil {
+		return nil, err
+	}
+
+	if next != nil {
+		err = u.audit(actor, aggregates.AuditActionCreate, nil, next)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = u.billRepository.AddPayment(bill, payment, next)
+	if err != nil {
+		return nil, err
+	}
+
+	return bill, nil
+}
+
+func (u *billUseCase) Cancel(actor types.Actor, restaurantId, id string) (*aggregates.Bill, error) {
+	bill, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *bill
+	if !bill.Cancel() {
+		return nil, ErrBillNotOpen
+	}
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, bill)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.billRepository.Update(bill)
+	if err != nil {
+		return nil, err
+	}
+
+	return bill, nil
+}
+
+// validate confere o payload e devolve o vencimento e o fornecedor, que precisa ser do restaurante
+func (u *billUseCase) validate(restaurantId string, payload *BillPayload) (time.Time, *aggregates.PartialSupplier, error) {
+	payload.Description = strings.TrimSpace(payload.Description)
+	if payload.Recurrence == "" {
+		payload.Recurrence = billrecurrence.NONE
+	}
+
+	switch {
+	case payload.Description == "":
+		return time.Time{}, nil, fmt.Errorf("%w: description is required", ErrInvalidBill)
+	case !payload.Category.IsValid():
+		return time.Time{}, nil, fmt.Errorf("%w: unknown category", ErrInvalidBill)
+	case !payload.Recurrence.IsValid():
+		return time.Time{}, nil, fmt.Errorf("%w: recurrence must be none, weekly, monthly or yearly", ErrInvalidBill)
+	case payload.Amount <= 0:
+		return time.Time{}, nil, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidBill)
+	}
+
+	dueDate, err := time.Parse(time.DateOnly, payload.DueDate)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("%w: due_date must be YYYY-MM-DD", ErrInvalidBill)
+	}
+
+	if payload.SupplierId == "" {
+		return dueDate, nil, nil
+	}
+
+	supplier, err := u.supplierRepository.FindById(payload.SupplierId)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if supplier == nil || supplier.Restaurant.Id != restaurantId {
+		return time.Time{}, nil, ErrSupplierNotFound
+	}
+
+	return dueDate, &aggregates.PartialSupplier{Id: supplier.Id, Name: supplier.Name}, nil
+}
+
+func (u *billUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.Bill) error {
+	bill := after
+	if bill == nil {
+		bill = before
+	}
+
+	return recordAudit(
+		bill,
+		actor,
+		bill.Restaurant.Id,
+		aggregates.BillAggregateType,
+		action,
+		before,
+		after,
+	)
+}
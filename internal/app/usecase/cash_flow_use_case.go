@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	billstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_status"
+)
+
+var ErrInvalidCashFlowPeriod = errors.New("invalid cash flow period")
+
+const (
+	defaultCashFlowDays = 7
+	maxCashFlowDays     = 90
+	// pedidos mais antigos que isso sem pagamento registrado não entram como recebível
+	pendingOrderDays = 7
+)
+
+const (
+	cashFlowSourceTab   = "tab"
+	cashFlowSourceOrder = "order"
+	cashFlowSourceBill  = "bill"
+)
+
+type (
+	CashFlowPayload struct {
+		Days           int
+		TimeZone       string
+		OpeningBalance float64
+	}
+
+	ICashFlowUseCase interface {
+		// Project prevê as entradas e saídas dos próximos dias a partir de hoje, no fuso informado
+		Project(restaurantId string, payload CashFlowPayload) (*dtos.CashFlowDto, error)
+	}
+
+	cashFlowUseCase struct {
+		billRepository        ports.IBillRepository
+		customerTabRepository ports.ICustomerTabRepository
+		orderRepository       ports.IOrderRepository
+		restaurantRepository  ports.IRestaurantRepository
+	}
+)
+
+func NewCashFlowUseCase(
+	billRepository ports.IBillRepository,
+	customerTabRepository ports.ICustomerTabRepository,
+	orderRepository ports.IOrderRepository,
+	restaurantRepository ports.IRestaurantRepository,
+) ICashFlowUseCase {
+	return &cashFlowUseCase{
+		billRepository:        billRepository,
+		customerTabRepository: customerTabRepository,
+		orderRepository:       orderRepository,
+		restaurantRepository:  restaurantRepository,
+	}
+}
+
+func (u *cashFlowUseCase) Project(restaurantId string, payload CashFlowPayload) (*dtos.CashFlowDto, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	days := payload.Days
+	if days == 0 {
+		days = defaultCashFlowDays
+	}
+	if days < 1 || days > maxCashFlowDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidCashFlowPeriod, maxCashFlowDays)
+	}
+
+	timeZone := payload.TimeZone
+	if timeZone == "" {
+		timeZone = defaultReportTimeZone
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimeZone, timeZone)
+	}
+
+	now := time.Now()
+	today := calendarDate(now.In(location))
+	lastDay := today.AddDate(0, 0, days-1)
+
+	receivables, err := u.receivables(restaurant.Id, today, lastDay, now)
+	if err != nil {
+		return nil, err
+	}
+
+	payables, err := u.payables(restaurant.Id, today, lastDay)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCashFlow(today, days, timeZone, payload.OpeningBalance, receivables, payables), nil
+}
+
+func (u *cashFlowUseCase) receivables(restaurantId string, today, lastDay, now time.Time) ([]dtos.CashFlowEntryDto, error) {
+	entries := make([]dtos.CashFlowEntryDto, 0)
+
+	tabs, err := u.customerTabRepository.FindOpen(restaurantId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tabs {
+		tabEntries, err := u.customerTabRepository.FindEntries(tabs[i].Id, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		customer := strings.TrimSpace(tabs[i].Customer.FirstName + " " + tabs[i].Customer.LastName)
+		for _, debit := range tabs[i].OutstandingDebits(tabEntries) {
+			dueDate := today
+			if debit.DueDate != nil {
+				dueDate = calendarDate(*debit.DueDate)
+			}
+			if dueDate.After(lastDay) {
+				continue
+			}
+
+			entries = append(entries, dtos.CashFlowEntryDto{
+				Source:      cashFlowSourceTab,
+				ReferenceId: tabs[i].Id,
+				Description: fmt.Sprintf("Fiado de %s", customer),
+				DueDate:     dueDate.Format(time.DateOnly),
+				Amount:      roundMoney(float64(debit.Amount) / 100),
+				Overdue:     dueDate.Before(today),
+			})
+		}
+	}
+
+	balances, err := u.orderRepository.FindOutstandingBalances(restaurantId, now.AddDate(0, 0, -pendingOrderDays))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, balance := range balances {
+		entries = append(entries, dtos.CashFlowEntryDto{
+			Source:      cashFlowSourceOrder,
+			ReferenceId: balance.OrderId,
+			Description: fmt.Sprintf("Pedido %s", balance.OrderId),
+			DueDate:     today.Format(time.DateOnly),
+			Amount:      roundMoney(balance.Balance),
+		})
+	}
+
+	return entries, nil
+}
+
+func (u *cashFlowUseCase) payables(restaurantId string, today, lastDay time.Time) ([]dtos.CashFlowEntryDto, error) {
+	bills, err := u.billRepository.Find(ports.BillQuery{
+		RestaurantId: restaurantId,
+		Status:       billstatus.OPEN,
+		DueTo:        &lastDay,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]dtos.CashFlowEntryDto, 0, len(bills))
+	for i := range bills {
+		bill := &bills[i]
+		bill.DueDate = calendarDate(bill.DueDate)
+
+		entries = append(entries, dtos.CashFlowEntryDto{
+			Source:      cashFlowSourceBill,
+			ReferenceId: bill.Id,
+			Description: bill.Description,
+			DueDate:     bill.DueDate.Format(time.DateOnly),
+			Amount:      bill.Balance(),
+			Overdue:     bill.IsOverdue(today),
+		})
+
+		for _, dueDate := range bill.UpcomingDueDates(lastDay) {
+			entries = append(entries, dtos.CashFlowEntryDto{
+				Source:      cashFlowSourceBill,
+				ReferenceId: bill.SeriesId,
+				Description: bill.Description,
+				DueDate:     dueDate.Format(time.DateOnly),
+				Amount:      bill.Amount,
+				Overdue:     dueDate.Before(today),
+				Projected:   true,
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// newCashFlow distribui os valores pelos dias; o que venceu antes de hoje entra no primeiro dia
+func newCashFlow(
+	today time.Time,
+	days int,
+	timeZone string,
+	openingBalance float64,
+	receivables []dtos.CashFlowEntryDto,
+	payables []dtos.CashFlowEntryDto,
+) *dtos.CashFlowDto {
+	sortCashFlowEntries(receivables)
+	sortCashFlowEntries(payables)
+
+	cashFlow := &dtos.CashFlowDto{
+		From:              today.Format(time.DateOnly),
+		To:                today.AddDate(0, 0, days-1).Format(time.DateOnly),
+		TimeZone:          timeZone,
+		OpeningBalance:    roundMoney(openingBalance),
+		Days:              make([]dtos.CashFlowDayDto, days),
+		ReceivableEntries: receivables,
+		PayableEntries:    payables,
+	}
+
+	index := make(map[string]int, days)
+	for i := range cashFlow.Days {
+		date := today.AddDate(0, 0, i).Format(time.DateOnly)
+		cashFlow.Days[i].Date = date
+		index[date] = i
+	}
+	dayOf := func(entry dtos.CashFlowEntryDto) *dtos.CashFlowDayDto {
+		if i, ok := index[entry.DueDate]; ok {
+			return &cashFlow.Days[i]
+		}
+		return &cashFlow.Days[0]
+	}
+
+	for _, entry := range receivables {
+		dayOf(entry).Receivables += entry.Amount
+		cashFlow.Receivables += entry.Amount
+		if entry.Overdue {
+			cashFlow.OverdueReceivables += entry.Amount
+		}
+	}
+	for _, entry := range payables {
+		dayOf(entry).Payables += entry.Amount
+		cashFlow.Payables += entry.Amount
+		if entry.Overdue {
+			cashFlow.OverduePayables += entry.Amount
+		}
+	}
+
+	balance := cashFlow.OpeningBalance
+	for i := range cashFlow.Days {
+		day := &cashFlow.Days[i]
+		day.Receivables = roundMoney(day.Receivables)
+		day.Payables = roundMoney(day.Payables)
+		day.Net = roundMoney(day.Receivables - day.Payables)
+		balance = roundMoney(balance + day.Net)
+		day.Balance = balance
+	}
+
+	cashFlow.Receivables = roundMoney(cashFlow.Receivables)
+	cashFlow.Payables = roundMoney(cashFlow.Payables)
+	cashFlow.OverdueReceivables = roundMoney(cashFlow.OverdueReceivables)
+	cashFlow.OverduePayables = roundMoney(cashFlow.OverduePayables)
+	cashFlow.ClosingBalance = balance
+
+	return cashFlow
+}
+
+func sortCashFlowEntries(entries []dtos.CashFlowEntryDto) {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].DueDate < entries[j].DueDate })
+}
+
+// calendarDate descarta hora e fuso, para comparar datas de vencimento
+func calendarDate(value time.Time) time.Time {
+	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+}
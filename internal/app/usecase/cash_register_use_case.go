@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	paymentmethod "github.com/PedroNetto404/marmitech-backend/pkg/enums/payment_method"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrCashSessionNotFound  = errors.New("cash session not found")
+	ErrCashSessionNotOpen   = errors.New("there is no open cash session")
+	ErrCashSessionClosed    = errors.New("cash session is already closed")
+	ErrInvalidCashSession   = errors.New("invalid cash session")
+	ErrInvalidCashMovement  = errors.New("invalid cash movement")
+	ErrInvalidCashCount     = errors.New("invalid cash count")
+	ErrCashSessionForbidden = errors.New("only the session operator or a manager can change this cash session")
+)
+
+type (
+	CashSessionPayload struct {
+		OpeningFloat float64 `json:"opening_float"`
+	}
+
+	CashMovementPayload struct {
+		Type   aggregates.CashMovementType `json:"type"`
+		Amount float64                     `json:"amount"`
+		Reason string                      `json:"reason"`
+	}
+
+	CashCountPayload struct {
+		PaymentMethod paymentmethod.PaymentMethod `json:"payment_method"`
+		Counted       float64                     `json:"counted"`
+	}
+
+	// CashClosePayload é a contagem cega do operador: formas não informadas contam como zero
+	CashClosePayload struct {
+		Counts          []CashCountPayload `json:"counts"`
+		Note            string             `json:"note"`
+		ExpectedVersion int                `json:"-"`
+	}
+
+	ICashRegisterUseCase interface {
+		Open(actor types.Actor, restaurantId string, payload *CashSessionPayload) (*aggregates.CashSession, error)
+		FindCurrent(actor types.Actor, restaurantId string) (*aggregates.CashSession, error)
+		FindById(restaurantId, id string) (*aggregates.CashSession, error)
+		Find(restaurantId string, payload ReportPeriodPayload, operator string) ([]aggregates.CashSession, error)
+		AddMovement(actor types.Actor, restaurantId, id string, payload *CashMovementPayload) (*aggregates.CashSession, error)
+		Close(actor types.Actor, restaurantId, id string, payload *CashClosePayload) (*aggregates.CashSession, error)
+		ShiftDiscrepancies(restaurantId string, payload ReportPeriodPayload, operator string) ([]dtos.CashShiftDto, error)
+		OperatorDiscrepancies(restaurantId string, payload ReportPeriodPayload) ([]dtos.CashOperatorDto, error)
+	}
+
+	cashRegisterUseCase struct {
+		cashSessionRepository ports.ICashSessionRepository
+		restaurantRepository  ports.IRestaurantRepository
+		eventPublisher        ports.IEventPublisher
+	}
+)
+
+func NewCashRegisterUseCase(
+	cashSessionRepository ports.ICashSessionRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	eventPublisher ports.IEventPublisher,
+) ICashRegisterUseCase {
+	return &cashRegisterUseCase{
+		cashSessionRepository: cashSessionRepository,
+		restaurantRepository:  restaurantRepository,
+		eventPublisher:        eventPublisher,
+	}
+}
+
+func (u *cashRegisterUseCase) Open(actor types.Actor, restaurantId string, payload *CashSessionPayload) (*aggregates.CashSession, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	switch {
+	case actor.Email == "":
+		return nil, fmt.Errorf("%w: operator is required", ErrInvalidCashSession)
+	case payload.OpeningFloat < 0:
+		return nil, fmt.Errorf("%w: opening_float must not be negative", ErrInvalidCashSession)
+	}
+
+	session := aggregates.NewCashSession(restaurant.Id, actor.Email, payload.OpeningFloat, time.Now())
+
+	err = u.audit(actor, aggregates.AuditActionCreate, nil, session)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.cashSessionRepository.Create(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (u *cashRegisterUseCase) FindCurrent(actor types.Actor, restaurantId string) (*aggregates.CashSession, error) {
+	session, err := u.cashSessionRepository.FindOpen(restaurantId, actor.Email)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrCashSessionNotOpen
+	}
+
+	return session, nil
+}
+
+func (u *cashRegisterUseCase) FindById(restaurantId, id string) (*aggregates.CashSession, error) {
+	session, err := u.cashSessionRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.Restaurant.Id != restaurantId {
+		return nil, ErrCashSessionNotFound
+	}
+
+	return session, nil
+}
+
+func (u *cashRegisterUseCase) Find(restaurantId string, payload ReportPeriodPayload, operator string) ([]aggregates.CashSession, error) {
+	period, err := resolveReportPeriod(u.restaurantRepository, restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.cashSessionRepository.Find(ports.CashSessionQuery{
+		RestaurantId: period.query.RestaurantId,
+		Operator:     operator,
+		From:         period.query.From,
+		To:           period.query.To,
+	})
+}
+
+func (u *cashRegisterUseCase) AddMovement(actor types.Actor, restaurantId, id string, payload *CashMovementPayload) (*aggregates.CashSession, error) {
+	session, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canOperate(actor, session) {
+		return nil, ErrCashSessionForbidden
+	}
+
+	payload.Reason = strings.TrimSpace(payload.Reason)
+	switch {
+	case !payload.Type.IsValid():
+		return nil, fmt.Errorf("%w: type must be withdrawal or deposit", ErrInvalidCashMovement)
+	case payload.Amount <= 0:
+		return nil, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidCashMovement)
+	case payload.Reason == "":
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidCashMovement)
+	}
+
+	before := *session
+	before.Movements = append([]aggregates.CashMovement(nil), session.Movements...)
+
+	movement := session.Move(payload.Type, payload.Amount, payload.Reason, actor.Email, time.Now())
+	if movement == nil {
+		return nil, ErrCashSessionClosed
+	}
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, session)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.cashSessionRepository.AddMovement(session, movement)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (u *cashRegisterUseCase) Close(actor types.Actor, restaurantId, id string, payload *CashClosePayload) (*aggregates.CashSession, error) {
+	session, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canOperate(actor, session) {
+		return nil, ErrCashSessionForbidden
+	}
+
+	err = checkExpectedVersion(aggregates.CashSessionAggregateType, session.Id, session.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	counted := make(map[paymentmethod.PaymentMethod]float64, len(payload.Counts))
+	for _, count := range payload.Counts {
+		if !count.PaymentMethod.IsValid() {
+			return nil, fmt.Errorf("%w: unknown payment method %q", ErrInvalidCashCount, count.PaymentMethod)
+		}
+		if _, ok := counted[count.PaymentMethod]; ok {
+			return nil, fmt.Errorf("%w: %s counted twice", ErrInvalidCashCount, count.PaymentMethod)
+		}
+		if count.Counted < 0 {
+			return nil, fmt.Errorf("%w: counted amounts must not be negative", ErrInvalidCashCount)
+		}
+		counted[count.PaymentMethod] = count.Counted
+	}
+
+	sales, err := u.cashSessionRepository.SumPayments(session.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *session
+	if !session.Close(sales, counted, actor.Email, strings.TrimSpace(payload.Note), time.Now()) {
+		return nil, ErrCashSessionClosed
+	}
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, session)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.cashSessionRepository.Close(session)
+	if err != nil {
+		return nil, err
+	}
+
+	publishDomainEvents(u.eventPublisher, session)
+	return session, nil
+}
+
+func (u *cashRegisterUseCase) ShiftDiscrepancies(restaurantId string, payload ReportPeriodPayload, operator string) ([]dtos.CashShiftDto, error) {
+	sessions, err := u.Find(restaurantId, payload, operator)
+	if err != nil {
+		return nil, err
+	}
+
+	shifts := make([]dtos.CashShiftDto, 0, len(sessions))
+	for _, session := range sessions {
+		if session.IsOpen() {
+			continue
+		}
+
+		shift := dtos.CashShiftDto{
+			SessionId:  session.Id,
+			Operator:   session.Operator,
+			OpenedAt:   session.OpenedAt,
+			ClosedAt:   *session.ClosedAt,
+			ClosedBy:   session.ClosedBy,
+			Difference: session.Difference(),
+			Note:       session.Note,
+		}
+		for _, count := range session.Counts {
+			shift.Expected += count.Expected
+			shift.Counted += count.Counted
+		}
+		shift.Expected = roundMoney(shift.Expected)
+		shift.Counted = roundMoney(shift.Counted)
+		shifts = append(shifts, shift)
+	}
+
+	return shifts, nil
+}
+
+// OperatorDiscrepancies acumula as quebras dos turnos por operador; falta e sobra são
+// somadas à parte para que um turno não esconda o outro
+func (u *cashRegisterUseCase) OperatorDiscrepancies(restaurantId string, payload ReportPeriodPayload) ([]dtos.CashOperatorDto, error) {
+	shifts, err := u.ShiftDiscrepancies(restaurantId, payload, "")
+	if err != nil {
+		return nil, err
+	}
+
+	byOperator := make(map[string]*dtos.CashOperatorDto)
+	for _, shift := range shifts {
+		operator, ok := byOperator[shift.Operator]
+		if !ok {
+			operator = &dtos.CashOperatorDto{Operator: shift.Operator}
+			byOperator[shift.Operator] = operator
+		}
+
+		operator.Shifts++
+		operator.Expected += shift.Expected
+		operator.Counted += shift.Counted
+		operator.Difference += shift.Difference
+		switch {
+		case shift.Difference < 0:
+			operator.ShiftsOff++
+			operator.Shortage -= shift.Difference
+		case shift.Difference > 0:
+			operator.ShiftsOff++
+			operator.Overage += shift.Difference
+		}
+	}
+
+	operators := make([]dtos.CashOperatorDto, 0, len(byOperator))
+	for _, operator := range byOperator {
+		operator.Expected = roundMoney(operator.Expected)
+		operator.Counted = roundMoney(operator.Counted)
+		operator.Difference = roundMoney(operator.Difference)
+		operator.Shortage = roundMoney(operator.Shortage)
+		operator.Overage = roundMoney(operator.Overage)
+		operators = append(operators, *operator)
+	}
+	sort.Slice(operators, func(i, j int) bool { return operators[i].Operator < operators[j].Operator })
+
+	return operators, nil
+}
+
+func (u *cashRegisterUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.CashSession) error {
+	session := after
+	if session == nil {
+		session = before
+	}
+
+	return recordAudit(
+		session,
+		actor,
+		session.Restaurant.Id,
+		aggregates.CashSessionAggregateType,
+		action,
+		before,
+		after,
+	)
+}
+
+func canOperate(actor types.Actor, session *aggregates.CashSession) bool {
+	return actor.Email == session.Operator || actor.IsManager()
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+type (
+	ICatalogUseCase interface {
+		GetBySlug(slug string) (*dtos.CatalogDto, error)
+	}
+
+	catalogUseCase struct {
+		restaurantRepository ports.IRestaurantRepository
+		categoryRepository   ports.ICategoryRepository
+		productRepository    ports.IProductRepository
+		menuRepository       ports.IMenuRepository
+	}
+)
+
+func NewCatalogUseCase(
+	restaurantRepository ports.IRestaurantRepository,
+	categoryRepository ports.ICategoryRepository,
+	productRepository ports.IProductRepository,
+	menuRepository ports.IMenuRepository,
+) ICatalogUseCase {
+	return &catalogUseCase{
+		restaurantRepository: restaurantRepository,
+		categoryRepository:   categoryRepository,
+		productRepository:    productRepository,
+		menuRepository:       menuRepository,
+	}
+}
+
+func (u *catalogUseCase) GetBySlug(slug string) (*dtos.CatalogDto, error) {
+	restaurant, err := u.restaurantRepository.FindBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	categories, err := u.categoryRepository.FindByRestaurantId(restaurant.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := u.productRepository.FindByRestaurantId(restaurant.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	productsByCategory := make(map[string][]dtos.CatalogProductDto)
+	for i := range products {
+		if !products[i].Active {
+			continue
+		}
+		categoryId := products[i].Category.Id
+		productsByCategory[categoryId] = append(productsByCategory[categoryId], dtos.MapProductToCatalogDto(&products[i]))
+	}
+
+	// categorias já vêm ordenadas por prioridade; categorias sem produtos ativos não aparecem na vitrine
+	catalogCategories := make([]dtos.CatalogCategoryDto, 0, len(categories))
+	for _, category := range categories {
+		if !category.Active || len(productsByCategory[category.Id]) == 0 {
+			continue
+		}
+		catalogCategories = append(catalogCategories, dtos.CatalogCategoryDto{
+			Id:         category.Id,
+			Name:       category.Name,
+			PictureUrl: category.PictureUrl,
+			Priority:   category.Priority,
+			Products:   productsByCategory[category.Id],
+		})
+	}
+
+	catalog := &dtos.CatalogDto{
+		Restaurant: dtos.MapRestaurantToCatalogDto(restaurant),
+		Categories: catalogCategories,
+	}
+
+	// o cardápio do dia vira à meia-noite do restaurante, não do servidor
+	location, err := time.LoadLocation(defaultReportTimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	menu, err := u.menuRepository.FindByOfferDate(restaurant.Id, time.Now().In(location))
+	if err != nil {
+		return nil, err
+	}
+	if menu != nil {
+		catalog.Menu = dtos.MapMenuToCatalogDto(menu)
+	}
+
+	return catalog, nil
+}
@@ -0,0 +1,148 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCategoryRepository struct {
+	ports.ICategoryRepository
+	categories []aggregates.Category
+	updates    int
+}
+
+func (r *fakeCategoryRepository) FindByRestaurantId(restaurantId string) ([]aggregates.Category, error) {
+	return r.categories, nil
+}
+
+func (r *fakeCategoryRepository) FindById(id string) (*aggregates.Category, error) {
+	for i := range r.categories {
+		if r.categories[i].Id == id {
+			return &r.categories[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeCategoryRepository) Update(category *aggregates.Category) error {
+	r.updates++
+	return nil
+}
+
+type fakeProductRepository struct {
+	ports.IProductRepository
+	products []aggregates.Product
+}
+
+func (r *fakeProductRepository) FindByRestaurantId(restaurantId string) ([]aggregates.Product, error) {
+	return r.products, nil
+}
+
+type fakeMenuRepository struct {
+	ports.IMenuRepository
+	menu      *aggregates.Menu
+	offerDate time.Time
+}
+
+func (r *fakeMenuRepository) FindByOfferDate(restaurantId string, offerDate time.Time) (*aggregates.Menu, error) {
+	r.offerDate = offerDate
+	return r.menu, nil
+}
+
+func newCatalogCategory(id string, priority int, active bool) aggregates.Category {
+	category := aggregates.Category{Name: id, Priority: priority, Active: active}
+	category.Id = id
+	return category
+}
+
+func newCatalogProduct(id, categoryId string, active bool) aggregates.Product {
+	product := aggregates.Product{Name: id, SalesPrice: "25.00", Active: active}
+	product.Id = id
+	product.Category.Id = categoryId
+	return product
+}
+
+func newCatalogUseCase(categories []aggregates.Category, products []aggregates.Product) usecase.ICatalogUseCase {
+	restaurant := &aggregates.Restaurant{}
+	restaurant.Id = "restaurant"
+	restaurant.Slug = "marmitaria-da-ana"
+
+	return usecase.NewCatalogUseCase(
+		&fakeRestaurantRepository{restaurant: restaurant},
+		&fakeCategoryRepository{categories: categories},
+		&fakeProductRepository{products: products},
+		&fakeMenuRepository{},
+	)
+}
+
+func TestGetBySlugReturnsNotFoundForUnknownSlug(t *testing.T) {
+	// arrange
+	useCase := newCatalogUseCase(nil, nil)
+
+	// act
+	catalog, err := useCase.GetBySlug("outro-restaurante")
+
+	// assert
+	assert.ErrorIs(t, err, usecase.ErrRestaurantNotFound)
+	assert.Nil(t, catalog)
+}
+
+func TestGetBySlugExposesOnlyActiveItemsInPriorityOrder(t *testing.T) {
+	// arrange
+	useCase := newCatalogUseCase(
+		[]aggregates.Category{
+			newCatalogCategory("marmitas", 1, true),
+			newCatalogCategory("bebidas", 2, true),
+			newCatalogCategory("sobremesas", 3, true),
+			newCatalogCategory("arquivada", 4, false),
+		},
+		[]aggregates.Product{
+			newCatalogProduct("refrigerante", "bebidas", true),
+			newCatalogProduct("marmita-p", "marmitas", true),
+			newCatalogProduct("marmita-g", "marmitas", false),
+			newCatalogProduct("pudim", "sobremesas", false),
+			newCatalogProduct("antigo", "arquivada", true),
+		},
+	)
+
+	// act
+	catalog, err := useCase.GetBySlug("marmitaria-da-ana")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "marmitaria-da-ana", catalog.Restaurant.Slug)
+	if assert.Len(t, catalog.Categories, 2, "categorias inativas ou sem produtos ativos ficam fora") {
+		assert.Equal(t, "marmitas", catalog.Categories[0].Id, "ordem de prioridade preservada")
+		assert.Equal(t, "bebidas", catalog.Categories[1].Id)
+		assert.Len(t, catalog.Categories[0].Products, 1, "produto inativo fica fora")
+		assert.Equal(t, "marmita-p", catalog.Categories[0].Products[0].Id)
+	}
+	assert.Nil(t, catalog.Menu, "sem cardápio do dia")
+}
+
+func TestGetBySlugLooksUpTodaysMenuInRestaurantTimeZone(t *testing.T) {
+	// arrange
+	restaurant := &aggregates.Restaurant{}
+	restaurant.Id = "restaurant"
+	restaurant.Slug = "marmitaria-da-ana"
+
+	menuRepository := &fakeMenuRepository{}
+	useCase := usecase.NewCatalogUseCase(
+		&fakeRestaurantRepository{restaurant: restaurant},
+		&fakeCategoryRepository{},
+		&fakeProductRepository{},
+		menuRepository,
+	)
+
+	// act
+	_, err := useCase.GetBySlug("marmitaria-da-ana")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "America/Sao_Paulo", menuRepository.offerDate.Location().String(), "data do cardápio no fuso do restaurante")
+}
@@ -16,26 +16,27 @@ const (
 var (
 	ErrcategoryNotFound      = errors.New("product category not found")
 	ErrcategoryAlreadyExists = errors.New("product category already exists")
+	ErrInvalidCategoryOrder  = errors.New("category order must list every category of the restaurant exactly once")
 )
 
 type (
 	CategoryPayload struct {
-		Name       string                       `json:"name"`
-		Restaurant aggregates.PartialRestaurant `json:"restaurant"`
-		Priority   int                          `json:"priority"`
+		Name            string                       `json:"name"`
+		Restaurant      aggregates.PartialRestaurant `json:"restaurant"`
+		ExpectedVersion int                          `json:"-"`
 	}
 
 	ICategoryUseCase interface {
 		Find(args types.FindArgs) (*types.PagedSlice[aggregates.Category], error)
 		FindById(id string) (*aggregates.Category, error)
-		Create(category *CategoryPayload) (*aggregates.Category, error)
-		Update(id string, category *CategoryPayload) (*aggregates.Category, error)
-		Delete(id string) error
-		SetPicture(id string, picture *types.FilePayload) (*aggregates.Category, error)
-		DeletePicture(id string) (*aggregates.Category, error)
-		Activate(id string) (*aggregates.Category, error)
-		Deactivate(id string) (*aggregates.Category, error)
-		Reorder(id string, priority int, destinationId string) (*aggregates.Category, error)
+		Create(actor types.Actor, category *CategoryPayload) (*aggregates.Category, error)
+		Update(actor types.Actor, id string, category *CategoryPayload) (*aggregates.Category, error)
+		Delete(actor types.Actor, id string) error
+		SetPicture(actor types.Actor, id string, picture *types.FilePayload, expectedVersion int) (*aggregates.Category, error)
+		DeletePicture(actor types.Actor, id string, expectedVersion int) (*aggregates.Category, error)
+		Activate(actor types.Actor, id string, expectedVersion int) (*aggregates.Category, error)
+		Deactivate(actor types.Actor, id string, expectedVersion int) (*aggregates.Category, error)
+		Reorder(actor types.Actor, restaurantId string, orderedIds []string) ([]aggregates.Category, error)
 	}
 
 	categoryUseCase struct {
@@ -79,7 +80,7 @@ func (p *categoryUseCase) FindById(id string) (*aggregates.Category, error) {
 	return category, nil
 }
 
-func (p *categoryUseCase) Create(payload *CategoryPayload) (*aggregates.Category, error) {
+func (p *categoryUseCase) Create(actor types.Actor, payload *CategoryPayload) (*aggregates.Category, error) {
 	exists, err := p.categoryRepository.Exists(payload.Restaurant.Id, payload.Name)
 	if err != nil {
 		return nil, err
@@ -91,9 +92,13 @@ func (p *categoryUseCase) Create(payload *CategoryPayload) (*aggregates.Category
 	category := aggregates.Newcategory(
 		payload.Restaurant.Id,
 		payload.Name,
-		payload.Priority,
 	)
 
+	err = p.audit(actor, aggregates.AuditActionCreate, nil, category)
+	if err != nil {
+		return nil, err
+	}
+
 	err = p.categoryRepository.Create(category)
 	if err != nil {
 		return nil, err
@@ -102,7 +107,7 @@ func (p *categoryUseCase) Create(payload *CategoryPayload) (*aggregates.Category
 	return category, nil
 }
 
-func (p *categoryUseCase) Update(id string, payload *CategoryPayload) (*aggregates.Category, error) {
+func (p *categoryUseCase) Update(actor types.Actor, id string, payload *CategoryPayload) (*aggregates.Category, error) {
 	exists, err := p.categoryRepository.Exists(payload.Restaurant.Id, payload.Name)
 	if err != nil {
 		return nil, err
@@ -120,8 +125,18 @@ func (p *categoryUseCase) Update(id string, payload *CategoryPayload) (*aggregat
 		return nil, ErrcategoryNotFound
 	}
 
+	err = checkExpectedVersion(aggregates.CategoryAggregateType, category.Id, category.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *category
 	category.Name = payload.Name
-	category.Priority = payload.Priority
+
+	err = p.audit(actor, aggregates.AuditActionUpdate, &before, category)
+	if err != nil {
+		return nil, err
+	}
 
 	err = p.categoryRepository.Update(category)
 	if err != nil {
@@ -131,7 +146,7 @@ func (p *categoryUseCase) Update(id string, payload *CategoryPayload) (*aggregat
 	return category, nil
 }
 
-func (p *categoryUseCase) Delete(id string) error {
+func (p *categoryUseCase) Delete(actor types.Actor, id string) error {
 	category, err := p.categoryRepository.FindById(id)
 	if err != nil {
 		return err
@@ -140,14 +155,14 @@ func (p *categoryUseCase) Delete(id string) error {
 	if category == nil {
 		return ErrcategoryNotFound
 	}
-	err = p.categoryRepository.Delete(id)
+	err = p.audit(actor, aggregates.AuditActionDelete, category, nil)
 	if err != nil {
 		return err
 	}
-	return nil
+	return p.categoryRepository.Delete(category)
 }
 
-func (p *categoryUseCase) SetPicture(id string, picture *types.FilePayload) (*aggregates.Category, error) {
+func (p *categoryUseCase) SetPicture(actor types.Actor, id string, picture *types.FilePayload, expectedVersion int) (*aggregates.Category, error) {
 	category, err := p.categoryRepository.FindById(id)
 	if err != nil {
 		return nil, err
@@ -156,13 +171,24 @@ func (p *categoryUseCase) SetPicture(id string, picture *types.FilePayload) (*ag
 		return nil, ErrcategoryNotFound
 	}
 
+	err = checkExpectedVersion(aggregates.CategoryAggregateType, category.Id, category.Version, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	key := fmt.Sprintf("product_category_picture_%s", category.Id)
 	url, err := p.blockStorage.Save(key, categoryBucket, picture.Content)
 	if err != nil {
 		return nil, err
 	}
 
+	before := *category
 	category.PictureUrl = url
+	err = p.audit(actor, aggregates.AuditActionUpdate, &before, category)
+	if err != nil {
+		return nil, err
+	}
+
 	err = p.categoryRepository.Update(category)
 	if err != nil {
 		return nil, err
@@ -170,7 +196,7 @@ func (p *categoryUseCase) SetPicture(id string, picture *types.FilePayload) (*ag
 	return category, nil
 }
 
-func (p *categoryUseCase) DeletePicture(id string) (*aggregates.Category, error) {
+func (p *categoryUseCase) DeletePicture(actor types.Actor, id string, expectedVersion int) (*aggregates.Category, error) {
 	category, err := p.categoryRepository.FindById(id)
 	if err != nil {
 		return nil, err
@@ -180,8 +206,13 @@ func (p *categoryUseCase) DeletePicture(id string) (*aggregates.Category, error)
 		return nil, ErrcategoryNotFound
 	}
 
+	err = checkExpectedVersion(aggregates.CategoryAggregateType, category.Id, category.Version, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	if category.PictureUrl == "" {
-		return nil, nil
+		return category, nil
 	}
 
 	key := fmt.Sprintf("product_category_picture_%s", category.Id)
@@ -189,7 +220,13 @@ func (p *categoryUseCase) DeletePicture(id string) (*aggregates.Category, error)
 	if err != nil {
 		return nil, err
 	}
+	before := *category
 	category.PictureUrl = ""
+	err = p.audit(actor, aggregates.AuditActionUpdate, &before, category)
+	if err != nil {
+		return nil, err
+	}
+
 	err = p.categoryRepository.Update(category)
 	if err != nil {
 		return nil, err
@@ -197,38 +234,51 @@ func (p *categoryUseCase) DeletePicture(id string) (*aggregates.Category, error)
 	return category, nil
 }
 
-func (p *categoryUseCase) Activate(id string) (*aggregates.Category, error) {
-	return p.setActiveStatus(id, true)
+func (p *categoryUseCase) Activate(actor types.Actor, id string, expectedVersion int) (*aggregates.Category, error) {
+	return p.setActiveStatus(actor, id, true, expectedVersion)
 }
 
-func (p *categoryUseCase) Deactivate(id string) (*aggregates.Category, error) {
-	return p.setActiveStatus(id, false)
+func (p *categoryUseCase) Deactivate(actor types.Actor, id string, expectedVersion int) (*aggregates.Category, error) {
+	return p.setActiveStatus(actor, id, false, expectedVersion)
 }
 
-func (p *categoryUseCase) Reorder(id string, priority int, swapId string) (*aggregates.Category, error) {
-	category, err := p.categoryRepository.FindById(id)
+func (p *categoryUseCase) Reorder(actor types.Actor, restaurantId string, orderedIds []string) ([]aggregates.Category, error) {
+	restaurant, err := p.restaurantRepository.FindById(restaurantId)
 	if err != nil {
 		return nil, err
 	}
-	if category == nil {
-		return nil, ErrcategoryNotFound
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
 	}
 
-	if swapId == "" {
-		return p.swapPriorities(category, swapId)
-	} else {
-		category.Priority = priority
+	current, err := p.categoryRepository.FindByRestaurantId(restaurantId)
+	if err != nil {
+		return nil, err
 	}
 
-	err = p.categoryRepository.Update(category)
+	categories, ok := arrangeByIds(current, func(c *aggregates.Category) string { return c.Id }, orderedIds)
+	if !ok {
+		return nil, ErrInvalidCategoryOrder
+	}
+
+	for i := range categories {
+		before := categories[i]
+		categories[i].Priority = i + 1
+		err = p.audit(actor, aggregates.AuditActionUpdate, &before, &categories[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.categoryRepository.Reorder(restaurantId, categories)
 	if err != nil {
 		return nil, err
 	}
 
-	return category, nil
+	return categories, nil
 }
 
-func (p *categoryUseCase) setActiveStatus(id string, status bool) (*aggregates.Category, error) {
+func (p *categoryUseCase) setActiveStatus(actor types.Actor, id string, status bool, expectedVersion int) (*aggregates.Category, error) {
 	category, err := p.categoryRepository.FindById(id)
 	if err != nil {
 		return nil, err
@@ -237,35 +287,39 @@ func (p *categoryUseCase) setActiveStatus(id string, status bool) (*aggregates.C
 		return nil, ErrcategoryNotFound
 	}
 
-	category.Active = status
-
-	err = p.categoryRepository.Update(category)
+	err = checkExpectedVersion(aggregates.CategoryAggregateType, category.Id, category.Version, expectedVersion)
 	if err != nil {
 		return nil, err
 	}
-	return category, nil
-}
 
-func (p *categoryUseCase) swapPriorities(category *aggregates.Category, swapId string) (*aggregates.Category, error) {
-	swapcategory, err := p.categoryRepository.FindById(fmt.Sprintf("%d", swapId))
+	before := *category
+	category.Active = status
+
+	err = p.audit(actor, aggregates.AuditActionUpdate, &before, category)
 	if err != nil {
 		return nil, err
 	}
-	if swapcategory == nil {
-		return nil, ErrcategoryNotFound
-	}
-
-	category.Priority, swapcategory.Priority = swapcategory.Priority, category.Priority
 
 	err = p.categoryRepository.Update(category)
 	if err != nil {
 		return nil, err
 	}
+	return category, nil
+}
 
-	err = p.categoryRepository.Update(swapcategory)
-	if err != nil {
-		return nil, err
+func (p *categoryUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.Category) error {
+	category := after
+	if category == nil {
+		category = before
 	}
 
-	return category, nil
+	return recordAudit(
+		category,
+		actor,
+		category.Restaurant.Id,
+		aggregates.CategoryAggregateType,
+		action,
+		before,
+		after,
+	)
 }
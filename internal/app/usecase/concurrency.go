@@ -0,0 +1,17 @@
+package usecase
+
+import "github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+
+// checkExpectedVersion compara a versão enviada pelo cliente (If-Match) com a versão carregada.
+// expected igual a zero significa que o cliente não enviou pré-condição.
+func checkExpectedVersion(aggregateType, id string, current, expected int) error {
+	if expected != 0 && expected != current {
+		return &ports.ConcurrencyConflictError{
+			AggregateType: aggregateType,
+			AggregateId:   id,
+			Version:       expected,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateChecksExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		expectedVersion int
+		conflict        bool
+	}{
+		{name: "sem If-Match", expectedVersion: 0},
+		{name: "versão atual", expectedVersion: 3},
+		{name: "versão desatualizada", expectedVersion: 2, conflict: true},
+		{name: "versão à frente", expectedVersion: 4, conflict: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange
+			tab := aggregates.NewCustomerTab("restaurant", "customer", 100, 10)
+			tab.Version = 3
+			tabRepository := &fakeCustomerTabRepository{tab: tab}
+			useCase := usecase.NewCustomerTabUseCase(tabRepository, nil, &fakeRestaurantRepository{}, nil)
+			payload := &usecase.CustomerTabPayload{
+				Restaurant:      aggregates.PartialRestaurant{Id: "restaurant"},
+				CreditLimit:     500,
+				DueDay:          5,
+				ExpectedVersion: test.expectedVersion,
+			}
+
+			// act
+			_, err := useCase.Update(types.Actor{Email: "caixa@marmitech.com"}, tab.Id, payload)
+
+			// assert
+			if test.conflict {
+				var conflict *ports.ConcurrencyConflictError
+				assert.ErrorAs(t, err, &conflict, "versão divergente vira conflito")
+				assert.Equal(t, tab.Id, conflict.AggregateId)
+				assert.Equal(t, test.expectedVersion, conflict.Version, "conflito informa a versão enviada pelo cliente")
+				assert.Zero(t, tabRepository.updates, "conflito não chega ao repositório")
+				assert.Equal(t, 100, tab.CreditLimit, "conflito não altera o agregado")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, tabRepository.updates)
+			assert.Equal(t, 500, tab.CreditLimit)
+		})
+	}
+}
+
+func TestActivateCategoryChecksExpectedVersion(t *testing.T) {
+	// arrange
+	category := newCatalogCategory("marmitas", 1, false)
+	category.Version = 3
+	categoryRepository := &fakeCategoryRepository{categories: []aggregates.Category{category}}
+	useCase := usecase.NewCategoryUseCase(categoryRepository, &fakeRestaurantRepository{}, nil)
+
+	// act
+	_, err := useCase.Activate(types.Actor{Email: "gerente@marmitech.com"}, "marmitas", 2)
+
+	// assert
+	var conflict *ports.ConcurrencyConflictError
+	assert.ErrorAs(t, err, &conflict, "If-Match desatualizado também vale para as sub-rotas")
+	assert.Zero(t, categoryRepository.updates, "conflito não chega ao repositório")
+	assert.False(t, categoryRepository.categories[0].Active, "conflito não altera o agregado")
+}
@@ -0,0 +1,350 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrCustomerTabNotFound      = errors.New("customer tab not found")
+	ErrCustomerTabAlreadyExists = errors.New("customer already has a tab in this restaurant")
+	ErrPostPaidDisabled         = errors.New("post-paid orders are disabled for this restaurant")
+	ErrBelowPostPaidMinimum     = errors.New("amount is below the post-paid minimum order value")
+	ErrCreditLimitExceeded      = errors.New("credit limit exceeded")
+	ErrSettlementExceedsBalance = errors.New("settlement amount exceeds the tab balance")
+	ErrInvalidTabAmount         = errors.New("amount must be greater than zero")
+	ErrInvalidDueDay            = errors.New("due day must be between 1 and 28")
+	ErrInvalidCreditLimit       = errors.New("credit limit must not be negative")
+	ErrPostPaidRequiresCustomer = errors.New("post-paid orders require a customer")
+	ErrOrderOfAnotherCustomer   = errors.New("order belongs to another customer")
+)
+
+type (
+	CustomerTabPayload struct {
+		Customer        aggregates.PartialCustomer   `json:"customer"`
+		Restaurant      aggregates.PartialRestaurant `json:"restaurant"`
+		CreditLimit     int                          `json:"credit_limit"`
+		DueDay          int                          `json:"due_day"`
+		ExpectedVersion int                          `json:"-"`
+	}
+
+	TabDebitPayload struct {
+		Amount      int    `json:"amount"`
+		OrderId     string `json:"order_id"`
+		Description string `json:"description"`
+	}
+
+	// TabSettlementPayload quita a conta; Amount zero quita o saldo inteiro
+	TabSettlementPayload struct {
+		Amount        int    `json:"amount"`
+		PaymentMethod string `json:"payment_method"`
+		Description   string `json:"description"`
+	}
+
+	ICustomerTabUseCase interface {
+		FindById(restaurantId, id string) (*aggregates.CustomerTab, error)
+		FindOpen(restaurantId string, onlyOverdue bool) ([]aggregates.CustomerTab, error)
+		FindEntries(restaurantId, id string, from, to *time.Time) ([]aggregates.TabEntry, error)
+		Statement(restaurantId, id string, year int, month time.Month) (*aggregates.TabStatement, error)
+		Open(actor types.Actor, payload *CustomerTabPayload) (*aggregates.CustomerTab, error)
+		Update(actor types.Actor, id string, payload *CustomerTabPayload) (*aggregates.CustomerTab, error)
+		PostDebit(actor types.Actor, restaurantId, id string, payload *TabDebitPayload) (*aggregates.CustomerTab, error)
+		Settle(actor types.Actor, restaurantId, id string, payload *TabSettlementPayload) (*aggregates.CustomerTab, error)
+	}
+
+	customerTabUseCase struct {
+		customerTabRepository ports.ICustomerTabRepository
+		customerRepository    ports.ICustomerRepository
+		restaurantRepository  ports.IRestaurantRepository
+		orderRepository       ports.IOrderRepository
+	}
+)
+
+func NewCustomerTabUseCase(
+	customerTabRepository ports.ICustomerTabRepository,
+	customerRepository ports.ICustomerRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	orderRepository ports.IOrderRepository,
+) ICustomerTabUseCase {
+	return &customerTabUseCase{
+		customerTabRepository: customerTabRepository,
+		customerRepository:    customerRepository,
+		restaurantRepository:  restaurantRepository,
+		orderRepository:       orderRepository,
+	}
+}
+
+func (u *customerTabUseCase) FindById(restaurantId, id string) (*aggregates.CustomerTab, error) {
+	tab, err := u.customerTabRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if tab == nil || tab.Restaurant.Id != restaurantId {
+		return nil, ErrCustomerTabNotFound
+	}
+
+	return tab, nil
+}
+
+func (u *customerTabUseCase) FindOpen(restaurantId string, onlyOverdue bool) ([]aggregates.CustomerTab, error) {
+	tabs, err := u.customerTabRepository.FindOpen(restaurantId, onlyOverdue)
+	if err != nil {
+		return nil, err
+	}
+
+	return tabs, nil
+}
+
+func (u *customerTabUseCase) FindEntries(restaurantId, id string, from, to *time.Time) ([]aggregates.TabEntry, error) {
+	tab, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.customerTabRepository.FindEntries(tab.Id, from, to)
+}
+
+func (u *customerTabUseCase) Statement(restaurantId, id string, year int, month time.Month) (*aggregates.TabStatement, error) {
+	tab, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// o saldo de abertura depende de todos os lançamentos anteriores ao mês
+	end := time.Date(year, month+1, 1, 0, 0, 0, 0, time.Local)
+	entries, err := u.customerTabRepository.FindEntries(tab.Id, nil, &end)
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregates.NewTabStatement(tab, entries, year, month), nil
+}
+
+func (u *customerTabUseCase) Open(actor types.Actor, payload *CustomerTabPayload) (*aggregates.CustomerTab, error) {
+	if err := validateTabTerms(payload); err != nil {
+		return nil, err
+	}
+
+	if _, err := postPaidRestaurant(u.restaurantRepository, payload.Restaurant.Id); err != nil {
+		return nil, err
+	}
+
+	customer, err := u.customerRepository.FindById(payload.Customer.Id)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, ErrCustomerNotFound
+	}
+
+	existing, err := u.customerTabRepository.FindByCustomerId(payload.Restaurant.Id, customer.Id)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrCustomerTabAlreadyExists
+	}
+
+	tab := aggregates.NewCustomerTab(payload.Restaurant.Id, customer.Id, payload.CreditLimit, payload.DueDay)
+	tab.Customer = aggregates.PartialCustomer{
+		Id:        customer.Id,
+		FirstName: customer.FirstName,
+		LastName:  customer.LastName,
+		Email:     customer.ContactEmail,
+	}
+
+	err = u.audit(actor, aggregates.AuditActionCreate, nil, tab)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.customerTabRepository.Create(tab)
+	if err != nil {
+		return nil, err
+	}
+
+	return tab, nil
+}
+
+func (u *customerTabUseCase) Update(actor types.Actor, id string, payload *CustomerTabPayload) (*aggregates.CustomerTab, error) {
+	if err := validateTabTerms(payload); err != nil {
+		return nil, err
+	}
+
+	tab, err := u.FindById(payload.Restaurant.Id, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.CustomerTabAggregateType, tab.Id, tab.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *tab
+	tab.CreditLimit = payload.CreditLimit
+	tab.DueDay = payload.DueDay
+	tab.UpdatedAt = time.Now()
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, tab)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.customerTabRepository.Update(tab)
+	if err != nil {
+		return nil, err
+	}
+
+	return tab, nil
+}
+
+// PostDebit lança um débito avulso; com OrderId, o pedido precisa ser do cliente da conta
+// e entra uma vez só no fiado
+func (u *customerTabUseCase) PostDebit(actor types.Actor, restaurantId, id string, payload *TabDebitPayload) (*aggregates.CustomerTab, error) {
+	restaurant, err := postPaidRestaurant(u.restaurantRepository, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	tab, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.OrderId != "" {
+		order, err := u.orderRepository.FindById(payload.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		if order == nil || order.Restaurant.Id != restaurantId {
+			return nil, ErrOrderNotFound
+		}
+		if order.Customer.Id != tab.Customer.Id {
+			return nil, ErrOrderOfAnotherCustomer
+		}
+	}
+
+	before := *tab
+	entry, err := debitTab(restaurant, tab, payload.Amount, payload.OrderId, payload.Description, actor.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, tab)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.customerTabRepository.AddEntry(tab, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return tab, nil
+}
+
+func (u *customerTabUseCase) Settle(actor types.Actor, restaurantId, id string, payload *TabSettlementPayload) (*aggregates.CustomerTab, error) {
+	if payload.Amount < 0 {
+		return nil, ErrInvalidTabAmount
+	}
+
+	tab, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := payload.Amount
+	if amount == 0 {
+		amount = tab.Balance
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidTabAmount
+	}
+	if amount > tab.Balance {
+		return nil, ErrSettlementExceedsBalance
+	}
+
+	before := *tab
+	entry := tab.Credit(amount, payload.PaymentMethod, payload.Description, actor.Email, time.Now())
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, tab)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.customerTabRepository.AddEntry(tab, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return tab, nil
+}
+
+func postPaidRestaurant(restaurantRepository ports.IRestaurantRepository, restaurantId string) (*aggregates.Restaurant, error) {
+	restaurant, err := restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+	if !restaurant.Settings.CustomerPostPaidOrders.Enabled {
+		return nil, ErrPostPaidDisabled
+	}
+
+	return restaurant, nil
+}
+
+// debitTab confere o mínimo do pós-pago e o limite da conta e lança o débito só em memória;
+// é usado pelo débito avulso e pelo pagamento pós-pago do pedido
+func debitTab(restaurant *aggregates.Restaurant, tab *aggregates.CustomerTab, amount int, orderId, description, createdBy string) (*aggregates.TabEntry, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidTabAmount
+	}
+
+	// o mínimo é configurado em reais no restaurante; os lançamentos do fiado são em centavos
+	minimum := restaurant.Settings.CustomerPostPaidOrders.MinimumOrderValue * 100
+	if minimum > 0 && amount < minimum {
+		return nil, ErrBelowPostPaidMinimum
+	}
+
+	if !tab.CanDebit(amount) {
+		return nil, ErrCreditLimitExceeded
+	}
+
+	return tab.Debit(amount, orderId, description, createdBy, time.Now()), nil
+}
+
+// o vencimento fica limitado a 28 para existir em todos os meses
+func validateTabTerms(payload *CustomerTabPayload) error {
+	if payload.DueDay < 1 || payload.DueDay > 28 {
+		return ErrInvalidDueDay
+	}
+	if payload.CreditLimit < 0 {
+		return ErrInvalidCreditLimit
+	}
+
+	return nil
+}
+
+func (u *customerTabUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.CustomerTab) error {
+	tab := after
+	if tab == nil {
+		tab = before
+	}
+
+	return recordAudit(
+		tab,
+		actor,
+		tab.Restaurant.Id,
+		aggregates.CustomerTabAggregateType,
+		action,
+		before,
+		after,
+	)
+}
@@ -0,0 +1,165 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRestaurantRepository struct {
+	ports.IRestaurantRepository
+	restaurant *aggregates.Restaurant
+}
+
+func (r *fakeRestaurantRepository) FindById(id string) (*aggregates.Restaurant, error) {
+	return r.restaurant, nil
+}
+
+func (r *fakeRestaurantRepository) FindBySlug(slug string) (*aggregates.Restaurant, error) {
+	if r.restaurant == nil || r.restaurant.Slug != slug {
+		return nil, nil
+	}
+	return r.restaurant, nil
+}
+
+type fakeCustomerTabRepository struct {
+	ports.ICustomerTabRepository
+	tab     *aggregates.CustomerTab
+	updates int
+	audits  int
+}
+
+func (r *fakeCustomerTabRepository) FindById(id string) (*aggregates.CustomerTab, error) {
+	return r.tab, nil
+}
+
+func (r *fakeCustomerTabRepository) Update(tab *aggregates.CustomerTab) error {
+	r.updates++
+	return nil
+}
+
+func (r *fakeCustomerTabRepository) AddEntry(tab *aggregates.CustomerTab, entry *aggregates.TabEntry) error {
+	r.audits = len(tab.AuditRecords())
+	return nil
+}
+
+type fakeOrderRepository struct {
+	ports.IOrderRepository
+	order *aggregates.Order
+}
+
+func (r *fakeOrderRepository) FindById(id string) (*aggregates.Order, error) {
+	return r.order, nil
+}
+
+func TestPostDebitComparesMinimumInReaisWithAmountInCents(t *testing.T) {
+	tests := []struct {
+		name          string
+		minimumReais  int
+		amountCents   int
+		expectedError error
+	}{
+		{name: "abaixo do mínimo", minimumReais: 20, amountCents: 1500, expectedError: usecase.ErrBelowPostPaidMinimum},
+		{name: "um centavo abaixo do mínimo", minimumReais: 20, amountCents: 1999, expectedError: usecase.ErrBelowPostPaidMinimum},
+		{name: "exatamente o mínimo", minimumReais: 20, amountCents: 2000},
+		{name: "sem mínimo configurado", minimumReais: 0, amountCents: 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange
+			restaurant := &aggregates.Restaurant{}
+			restaurant.Id = "restaurant"
+			restaurant.Settings.CustomerPostPaidOrders.Enabled = true
+			restaurant.Settings.CustomerPostPaidOrders.MinimumOrderValue = test.minimumReais
+
+			tab := aggregates.NewCustomerTab("restaurant", "customer", 0, 10)
+			tabRepository := &fakeCustomerTabRepository{tab: tab}
+			useCase := usecase.NewCustomerTabUseCase(
+				tabRepository,
+				nil,
+				&fakeRestaurantRepository{restaurant: restaurant},
+				nil,
+			)
+
+			// act
+			_, err := useCase.PostDebit(types.Actor{Email: "caixa@marmitech.com"}, "restaurant", tab.Id, &usecase.TabDebitPayload{Amount: test.amountCents})
+
+			// assert
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError, "mínimo em reais convertido para centavos")
+				assert.Zero(t, tab.Balance, "débito recusado não entra no saldo")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.amountCents, tab.Balance)
+			assert.Equal(t, 1, tabRepository.audits, "auditoria gravada junto com o lançamento")
+		})
+	}
+}
+
+func TestPostDebitValidatesOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		order         *aggregates.Order
+		expectedError error
+	}{
+		{name: "pedido inexistente", order: nil, expectedError: usecase.ErrOrderNotFound},
+		{
+			name: "pedido de outro restaurante",
+			order: &aggregates.Order{
+				Restaurant: aggregates.PartialRestaurant{Id: "other"},
+				Customer:   aggregates.PartialCustomer{Id: "customer"},
+			},
+			expectedError: usecase.ErrOrderNotFound,
+		},
+		{
+			name: "pedido de outro cliente",
+			order: &aggregates.Order{
+				Restaurant: aggregates.PartialRestaurant{Id: "restaurant"},
+				Customer:   aggregates.PartialCustomer{Id: "other"},
+			},
+			expectedError: usecase.ErrOrderOfAnotherCustomer,
+		},
+		{
+			name: "pedido do cliente da conta",
+			order: &aggregates.Order{
+				Restaurant: aggregates.PartialRestaurant{Id: "restaurant"},
+				Customer:   aggregates.PartialCustomer{Id: "customer"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange
+			restaurant := &aggregates.Restaurant{}
+			restaurant.Id = "restaurant"
+			restaurant.Settings.CustomerPostPaidOrders.Enabled = true
+
+			tab := aggregates.NewCustomerTab("restaurant", "customer", 0, 10)
+			useCase := usecase.NewCustomerTabUseCase(
+				&fakeCustomerTabRepository{tab: tab},
+				nil,
+				&fakeRestaurantRepository{restaurant: restaurant},
+				&fakeOrderRepository{order: test.order},
+			)
+
+			// act
+			_, err := useCase.PostDebit(types.Actor{Email: "caixa@marmitech.com"}, "restaurant", tab.Id, &usecase.TabDebitPayload{Amount: 1000, OrderId: "order"})
+
+			// assert
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				assert.Zero(t, tab.Balance, "débito recusado não entra no saldo")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1000, tab.Balance)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrCustomerNotFound      = errors.New("customer not found")
+	ErrCustomerAlreadyExists = errors.New("customer already exists")
+)
+
+type (
+	CustomerPayload struct {
+		FirstName       string                       `json:"first_name"`
+		LastName        string                       `json:"last_name"`
+		ContactEmail    string                       `json:"contact_email"`
+		ContactPhone    string                       `json:"contact_phone"`
+		Address         types.Address                `json:"address"`
+		Restaurant      aggregates.PartialRestaurant `json:"restaurant"`
+		ExpectedVersion int                          `json:"-"`
+	}
+
+	ICustomerUseCase interface {
+		Find(args types.FindArgs) (*types.PagedSlice[aggregates.Customer], error)
+		FindById(id string) (*aggregates.Customer, error)
+		Create(actor types.Actor, payload *CustomerPayload) (*aggregates.Customer, error)
+		Update(actor types.Actor, id string, payload *CustomerPayload) (*aggregates.Customer, error)
+	}
+
+	customerUseCase struct {
+		customerRepository ports.ICustomerRepository
+		addressLookup      ports.IAddressLookup
+		geocoder           ports.IGeocoder
+	}
+)
+
+func NewCustomerUseCase(
+	customerRepository ports.ICustomerRepository,
+	addressLookup ports.IAddressLookup,
+	geocoder ports.IGeocoder,
+) ICustomerUseCase {
+	return &customerUseCase{
+		customerRepository: customerRepository,
+		addressLookup:      addressLookup,
+		geocoder:           geocoder,
+	}
+}
+
+func (u *customerUseCase) Find(args types.FindArgs) (*types.PagedSlice[aggregates.Customer], error) {
+	customers, err := u.customerRepository.Find(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return customers, nil
+}
+
+func (u *customerUseCase) FindById(id string) (*aggregates.Customer, error) {
+	customer, err := u.customerRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, ErrCustomerNotFound
+	}
+
+	return customer, nil
+}
+
+func (u *customerUseCase) Create(actor types.Actor, payload *CustomerPayload) (*aggregates.Customer, error) {
+	err := normalizeAddress(u.addressLookup, &payload.Address)
+	if err != nil {
+		return nil, err
+	}
+	geocodeAddress(u.geocoder, &payload.Address, nil)
+
+	if payload.ContactEmail != "" {
+		exists, err := u.customerRepository.Exists(payload.ContactEmail)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrCustomerAlreadyExists
+		}
+	}
+
+	customer := aggregates.NewCustomer(
+		payload.FirstName,
+		payload.LastName,
+		payload.ContactEmail,
+		payload.ContactPhone,
+		payload.Address,
+	)
+
+	err = recordAudit(
+		customer,
+		actor,
+		payload.Restaurant.Id,
+		aggregates.CustomerAggregateType,
+		aggregates.AuditActionCreate,
+		nil,
+		customer,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.customerRepository.Create(customer)
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+func (u *customerUseCase) Update(actor types.Actor, id string, payload *CustomerPayload) (*aggregates.Customer, error) {
+	customer, err := u.customerRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, ErrCustomerNotFound
+	}
+
+	err = checkExpectedVersion(aggregates.CustomerAggregateType, customer.Id, customer.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = normalizeAddress(u.addressLookup, &payload.Address)
+	if err != nil {
+		return nil, err
+	}
+	geocodeAddress(u.geocoder, &payload.Address, &customer.Address)
+
+	before := *customer
+	customer.FirstName = payload.FirstName
+	customer.LastName = payload.LastName
+	customer.ContactEmail = payload.ContactEmail
+	customer.ContactPhone = payload.ContactPhone
+	payload.Address.Id = customer.Address.Id
+	customer.Address = payload.Address
+
+	err = recordAudit(
+		customer,
+		actor,
+		payload.Restaurant.Id,
+		aggregates.CustomerAggregateType,
+		aggregates.AuditActionUpdate,
+		&before,
+		customer,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.customerRepository.Update(customer)
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
@@ -0,0 +1,576 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	deliveryroutestatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_route_status"
+	deliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_status"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/google/uuid"
+)
+
+const (
+	deliveryProofBucket = "delivery-proofs"
+	// posições com data muito à frente do relógio do servidor vêm de aparelho desregulado
+	maxDriverClockSkew = 5 * time.Minute
+)
+
+var (
+	ErrDeliveryRouteNotFound          = errors.New("delivery route not found")
+	ErrInvalidDeliveryRoute           = errors.New("invalid delivery route")
+	ErrDriverUnavailable              = errors.New("driver is inactive")
+	ErrOrderAlreadyDispatched         = errors.New("order is already in another delivery route")
+	ErrInvalidDeliveryRouteTransition = errors.New("delivery route cannot move to the requested status")
+	ErrOrderNotReadyForPickup         = errors.New("every order must be ready before pickup")
+	ErrDeliveryStopNotFound           = errors.New("delivery stop not found")
+	ErrDeliveryProofRequired          = errors.New("delivery requires a photo or the customer code")
+	ErrInvalidDeliveryCode            = errors.New("customer code does not match")
+	ErrDeliveryRouteForbidden         = errors.New("only the route driver or a manager can change this delivery route")
+	ErrNotADriver                     = errors.New("user is not a driver of this restaurant")
+	ErrInvalidDriverLocation          = errors.New("invalid driver location")
+)
+
+type (
+	// DeliveryRoutePayload atribui os pedidos ao entregador; a ordem dos pedidos é a ordem das paradas
+	DeliveryRoutePayload struct {
+		DriverId string   `json:"driver_id"`
+		OrderIds []string `json:"order_ids"`
+	}
+
+	// DeliveryProofPayload traz a foto do pedido entregue, o código informado pelo cliente ou os dois
+	DeliveryProofPayload struct {
+		Code  string
+		Photo *types.FilePayload
+	}
+
+	DriverLocationPayload struct {
+		RouteId string  `json:"route_id"`
+		Lat     float64 `json:"lat"`
+		Lng     float64 `json:"lng"`
+		// RecordedAt é a hora da leitura no aparelho; pings enviados em lote depois de ficar sem sinal a trazem
+		RecordedAt *time.Time `json:"recorded_at"`
+	}
+
+	IDeliveryRouteUseCase interface {
+		Find(restaurantId, driverId string, status deliveryroutestatus.DeliveryRouteStatus) ([]aggregates.DeliveryRoute, error)
+		FindById(restaurantId, id string) (*aggregates.DeliveryRoute, error)
+		Create(actor types.Actor, restaurantId string, payload *DeliveryRoutePayload) (*aggregates.DeliveryRoute, error)
+		Cancel(actor types.Actor, restaurantId, id string) (*aggregates.DeliveryRoute, error)
+		Track(restaurantId, id string) (*dtos.DeliveryTrackingDto, error)
+		// rotas do app do entregador, identificado pelo e-mail do usuário autenticado
+		MyRoutes(actor types.Actor, restaurantId string) ([]aggregates.DeliveryRoute, error)
+		Accept(actor types.Actor, restaurantId, id string) (*aggregates.DeliveryRoute, error)
+		PickUp(actor types.Actor, restaurantId, id string) (*aggregates.DeliveryRoute, error)
+		Deliver(actor types.Actor, restaurantId, id, stopId string, payload *DeliveryProofPayload) (*aggregates.DeliveryRoute, error)
+		PingLocation(actor types.Actor, restaurantId string, payload *DriverLocationPayload) error
+	}
+
+	deliveryRouteUseCase struct {
+		deliveryRouteRepository  ports.IDeliveryRouteRepository
+		driverRepository         ports.IDriverRepository
+		driverLocationRepository ports.IDriverLocationRepository
+		orderRepository          ports.IOrderRepository
+		blockStorage             ports.IBlockStorage
+		eventPublisher           ports.IEventPublisher
+	}
+)
+
+func NewDeliveryRouteUseCase(
+	deliveryRouteRepository ports.IDeliveryRouteRepository,
+	driverRepository ports.IDriverRepository,
+	driverLocationRepository ports.IDriverLocationRepository,
+	orderRepository ports.IOrderRepository,
+	blockStorage ports.IBlockStorage,
+	eventPublisher ports.IEventPublisher,
+) IDeliveryRouteUseCase {
+	return &deliveryRouteUseCase{
+		deliveryRouteRepository:  deliveryRouteRepository,
+		driverRepository:         driverRepository,
+		driverLocationRepository: driverLocationRepository,
+		orderRepository:          orderRepository,
+		blockStorage:             blockStorage,
+		eventPublisher:           eventPublisher,
+	}
+}
+
+func (u *deliveryRouteUseCase) Find(restaurantId, driverId string, status deliveryroutestatus.DeliveryRouteStatus) ([]aggregates.DeliveryRoute, error) {
+	query := ports.DeliveryRouteQuery{RestaurantId: restaurantId, DriverId: driverId}
+	if status != "" {
+		if !status.IsValid() {
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidDeliveryRoute, status)
+		}
+		query.Statuses = []deliveryroutestatus.DeliveryRouteStatus{status}
+	}
+
+	return u.deliveryRouteRepository.Find(query)
+}
+
+func (u *deliveryRouteUseCase) FindById(restaurantId, id string) (*aggregates.DeliveryRoute, error) {
+	route, err := u.deliveryRouteRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if route == nil || route.Restaurant.Id != restaurantId {
+		return nil, ErrDeliveryRouteNotFound
+	}
+
+	return route, nil
+}
+
+func (u *deliveryRouteUseCase) Create(actor types.Actor, restaurantId string, payload *DeliveryRoutePayload) (*aggregates.DeliveryRoute, error) {
+	if len(payload.OrderIds) == 0 {
+		return nil, fmt.Errorf("%w: at least one order is required", ErrInvalidDeliveryRoute)
+	}
+
+	driver, err := u.driverRepository.FindById(payload.DriverId)
+	if err != nil {
+		return nil, err
+	}
+	if driver == nil || driver.Restaurant.Id != restaurantId {
+		return nil, ErrDriverNotFound
+	}
+	if !driver.Active {
+		return nil, ErrDriverUnavailable
+	}
+
+	// falha cedo no caso comum; a conferência que vale é refeita com os pedidos travados na gravação
+	dispatched, err := u.deliveryRouteRepository.FindActiveByOrderIds(payload.OrderIds)
+	if err != nil {
+		return nil, err
+	}
+	if len(dispatched) > 0 {
+		return nil, ErrOrderAlreadyDispatched
+	}
+
+	route := aggregates.NewDeliveryRoute(restaurantId, driver.Partial(), actor.Email)
+	orders := make([]*aggregates.Order, 0, len(payload.OrderIds))
+	for _, orderId := range payload.OrderIds {
+		order, err := u.orderRepository.FindById(orderId)
+		if err != nil {
+			return nil, err
+		}
+		if order == nil || order.Restaurant.Id != restaurantId {
+			return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, orderId)
+		}
+		if !route.AddStop(order) {
+			return nil, fmt.Errorf("%w: order %s has no pending delivery or is repeated", ErrInvalidDeliveryRoute, orderId)
+		}
+		orders = append(orders, order)
+	}
+
+	changed, ordersBefore := applyToOrders(orders, func(order *aggregates.Order) bool {
+		return order.SetDeliveryStatus(deliverystatus.ASSIGNED)
+	})
+
+	err = u.audit(actor, aggregates.AuditActionCreate, nil, route)
+	if err != nil {
+		return nil, err
+	}
+
+	err = auditOrders(actor, ordersBefore, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.deliveryRouteRepository.Create(route, changed)
+	if errors.Is(err, ports.ErrOrderInActiveRoute) {
+		return nil, ErrOrderAlreadyDispatched
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u.publishOrders(changed)
+
+	return route, nil
+}
+
+// Cancel devolve os pedidos para a fila de entregas, para serem atribuídos a outra rota
+func (u *deliveryRouteUseCase) Cancel(actor types.Actor, restaurantId, id string) (*aggregates.DeliveryRoute, error) {
+	route, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.transition(actor, route, func(route *aggregates.DeliveryRoute) error {
+		if !route.Cancel(time.Now()) {
+			return ErrInvalidDeliveryRouteTransition
+		}
+		return nil
+	}, deliverystatus.PENDING)
+}
+
+func (u *deliveryRouteUseCase) Track(restaurantId, id string) (*dtos.DeliveryTrackingDto, error) {
+	route, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tracking := &dtos.DeliveryTrackingDto{Route: route, Trail: make([]aggregates.DriverLocation, 0)}
+	if route.PickedUpAt == nil {
+		return tracking, nil
+	}
+
+	tracking.Trail, err = u.driverLocationRepository.FindByRoute(route.Id, *route.PickedUpAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracking.Trail) > 0 {
+		tracking.Location = &tracking.Trail[len(tracking.Trail)-1]
+	}
+
+	return tracking, nil
+}
+
+func (u *deliveryRouteUseCase) MyRoutes(actor types.Actor, restaurantId string) ([]aggregates.DeliveryRoute, error) {
+	driver, err := u.driverOf(actor, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.deliveryRouteRepository.Find(ports.DeliveryRouteQuery{
+		RestaurantId: restaurantId,
+		DriverId:     driver.Id,
+		Statuses: []deliveryroutestatus.DeliveryRouteStatus{
+			deliveryroutestatus.ASSIGNED,
+			deliveryroutestatus.ACCEPTED,
+			deliveryroutestatus.IN_TRANSIT,
+		},
+	})
+}
+
+func (u *deliveryRouteUseCase) Accept(actor types.Actor, restaurantId, id string) (*aggregates.DeliveryRoute, error) {
+	route, err := u.driverRoute(actor, restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.transition(actor, route, func(route *aggregates.DeliveryRoute) error {
+		if !route.Accept(time.Now()) {
+			return ErrInvalidDeliveryRouteTransition
+		}
+		return nil
+	}, "")
+}
+
+// PickUp só libera a saída com todos os pedidos da rota prontos na cozinha
+func (u *deliveryRouteUseCase) PickUp(actor types.Actor, restaurantId, id string) (*aggregates.DeliveryRoute, error) {
+	route, err := u.driverRoute(actor, restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stop := range route.Stops {
+		order, err := u.orderRepository.FindById(stop.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		if order == nil || order.Status != orderstatus.READY {
+			return nil, fmt.Errorf("%w: order %s", ErrOrderNotReadyForPickup, stop.OrderId)
+		}
+	}
+
+	return u.transition(actor, route, func(route *aggregates.DeliveryRoute) error {
+		if !route.PickUp(time.Now()) {
+			return ErrInvalidDeliveryRouteTransition
+		}
+		return nil
+	}, deliverystatus.IN_TRANSIT)
+}
+
+// Deliver confere o código do cliente, guarda a foto e conclui o pedido entregue; a parada e o pedido
+// são gravados na mesma transação
+func (u *deliveryRouteUseCase) Deliver(actor types.Actor, restaurantId, id, stopId string, payload *DeliveryProofPayload) (*aggregates.DeliveryRoute, error) {
+	route, err := u.driverRoute(actor, restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stop := route.FindStop(stopId)
+	if stop == nil {
+		return nil, ErrDeliveryStopNotFound
+	}
+
+	payload.Code = strings.TrimSpace(payload.Code)
+	hasPhoto := payload.Photo != nil && len(payload.Photo.Content) > 0
+	if payload.Code == "" && !hasPhoto {
+		return nil, ErrDeliveryProofRequired
+	}
+
+	order, err := u.orderRepository.FindById(stop.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.Delivery == nil {
+		return nil, ErrOrderNotFound
+	}
+	if payload.Code != "" && (order.Delivery.Code == "" || payload.Code != order.Delivery.Code) {
+		return nil, ErrInvalidDeliveryCode
+	}
+
+	before := cloneDeliveryRoute(route)
+	delivered := route.Deliver(stop.Id, "", payload.Code != "", time.Now())
+	if delivered == nil {
+		return nil, ErrInvalidDeliveryRouteTransition
+	}
+
+	changed, ordersBefore := applyToOrders([]*aggregates.Order{order}, func(order *aggregates.Order) bool {
+		if !order.SetDeliveryStatus(deliverystatus.DELIVERED) {
+			return false
+		}
+		order.Complete()
+		return true
+	})
+
+	// a chave é única por tentativa: se a gravação falhar, só a foto desta tentativa é apagada,
+	// nunca a de uma entrega concorrente que tenha vencido
+	var photoKey string
+	if hasPhoto {
+		photoKey = fmt.Sprintf("delivery_proof_%s_%s", delivered.Id, uuid.NewString())
+		url, err := u.blockStorage.Save(photoKey, deliveryProofBucket, payload.Photo.Content)
+		if err != nil {
+			return nil, err
+		}
+		delivered.ProofPhotoUrl = url
+	}
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, before, route)
+	if err != nil {
+		return nil, err
+	}
+
+	err = auditOrders(actor, ordersBefore, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.deliveryRouteRepository.Update(route, changed)
+	if err != nil {
+		if photoKey != "" {
+			if err := u.blockStorage.Delete(photoKey, deliveryProofBucket); err != nil {
+				log.Printf("⚠️ failed to delete delivery proof %s: %v", photoKey, err)
+			}
+		}
+		return nil, err
+	}
+
+	u.publishOrders(changed)
+
+	return route, nil
+}
+
+func (u *deliveryRouteUseCase) PingLocation(actor types.Actor, restaurantId string, payload *DriverLocationPayload) error {
+	driver, err := u.driverOf(actor, restaurantId)
+	if err != nil {
+		return err
+	}
+
+	point := types.GeoPoint{Lat: payload.Lat, Lng: payload.Lng}
+	if point.Lat < -90 || point.Lat > 90 || point.Lng < -180 || point.Lng > 180 || point == (types.GeoPoint{}) {
+		return fmt.Errorf("%w: coordinates out of range", ErrInvalidDriverLocation)
+	}
+
+	now := time.Now()
+	recordedAt := now
+	if payload.RecordedAt != nil {
+		if payload.RecordedAt.After(now.Add(maxDriverClockSkew)) {
+			return fmt.Errorf("%w: recorded_at is in the future", ErrInvalidDriverLocation)
+		}
+		recordedAt = *payload.RecordedAt
+	}
+
+	if payload.RouteId != "" {
+		route, err := u.FindById(restaurantId, payload.RouteId)
+		if err != nil {
+			return err
+		}
+		if route.Driver.Id != driver.Id {
+			return ErrDeliveryRouteForbidden
+		}
+	}
+
+	return u.driverLocationRepository.Save(&aggregates.DriverLocation{
+		DriverId:   driver.Id,
+		RouteId:    payload.RouteId,
+		Lat:        point.Lat,
+		Lng:        point.Lng,
+		RecordedAt: recordedAt,
+	})
+}
+
+// driverOf encontra o cadastro de entregador do usuário autenticado
+func (u *deliveryRouteUseCase) driverOf(actor types.Actor, restaurantId string) (*aggregates.Driver, error) {
+	driver, err := u.driverRepository.FindByEmail(restaurantId, strings.ToLower(actor.Email))
+	if err != nil {
+		return nil, err
+	}
+	if driver == nil || !driver.Active {
+		return nil, ErrNotADriver
+	}
+
+	return driver, nil
+}
+
+// driverRoute carrega a rota para uma ação do app; gerentes podem agir no lugar do entregador
+func (u *deliveryRouteUseCase) driverRoute(actor types.Actor, restaurantId, id string) (*aggregates.DeliveryRoute, error) {
+	route, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+	if actor.IsManager() {
+		return route, nil
+	}
+
+	driver, err := u.driverOf(actor, restaurantId)
+	if errors.Is(err, ErrNotADriver) {
+		return nil, ErrDeliveryRouteForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+	if route.Driver.Id != driver.Id {
+		return nil, ErrDeliveryRouteForbidden
+	}
+
+	return route, nil
+}
+
+// transition grava a mudança da rota e leva o novo status de entrega para os pedidos dela;
+// orderStatus vazio deixa os pedidos como estão
+func (u *deliveryRouteUseCase) transition(
+	actor types.Actor,
+	route *aggregates.DeliveryRoute,
+	apply func(route *aggregates.DeliveryRoute) error,
+	orderStatus deliverystatus.DeliveryStatus,
+) (*aggregates.DeliveryRoute, error) {
+	before := cloneDeliveryRoute(route)
+	if err := apply(route); err != nil {
+		return nil, err
+	}
+
+	orders := make([]*aggregates.Order, 0, len(route.Stops))
+	if orderStatus != "" {
+		for _, stop := range route.Stops {
+			order, err := u.orderRepository.FindById(stop.OrderId)
+			if err != nil {
+				return nil, err
+			}
+			if order != nil {
+				orders = append(orders, order)
+			}
+		}
+	}
+	changed, ordersBefore := applyToOrders(orders, func(order *aggregates.Order) bool {
+		return order.SetDeliveryStatus(orderStatus)
+	})
+
+	err := u.audit(actor, aggregates.AuditActionUpdate, before, route)
+	if err != nil {
+		return nil, err
+	}
+
+	err = auditOrders(actor, ordersBefore, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.deliveryRouteRepository.Update(route, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	u.publishOrders(changed)
+
+	return route, nil
+}
+
+// applyToOrders aplica a mudança de entrega em memória e devolve só os pedidos que mudaram, junto com
+// a cópia de antes para a auditoria; pedidos cancelados no meio do caminho ficam como estão
+func applyToOrders(orders []*aggregates.Order, apply func(order *aggregates.Order) bool) ([]*aggregates.Order, []*aggregates.Order) {
+	changed := make([]*aggregates.Order, 0, len(orders))
+	before := make([]*aggregates.Order, 0, len(orders))
+	for _, order := range orders {
+		snapshot := *order
+		if order.Delivery != nil {
+			delivery := *order.Delivery
+			snapshot.Delivery = &delivery
+		}
+		if !apply(order) {
+			continue
+		}
+		changed = append(changed, order)
+		before = append(before, &snapshot)
+	}
+
+	return changed, before
+}
+
+// auditOrders registra a auditoria dos pedidos que vão ser gravados junto com a rota
+func auditOrders(actor types.Actor, before, changed []*aggregates.Order) error {
+	for i, order := range changed {
+		err := recordAudit(
+			order,
+			actor,
+			order.Restaurant.Id,
+			aggregates.OrderAggregateType,
+			aggregates.AuditActionUpdate,
+			before[i],
+			order,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishOrders publica os eventos dos pedidos que já foram gravados junto com a rota
+func (u *deliveryRouteUseCase) publishOrders(changed []*aggregates.Order) {
+	for _, order := range changed {
+		publishDomainEvents(u.eventPublisher, order)
+	}
+}
+
+func (u *deliveryRouteUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.DeliveryRoute) error {
+	route := after
+	if route == nil {
+		route = before
+	}
+
+	return recordAudit(
+		route,
+		actor,
+		route.Restaurant.Id,
+		aggregates.DeliveryRouteAggregateType,
+		action,
+		before,
+		after,
+	)
+}
+
+func cloneDeliveryRoute(route *aggregates.DeliveryRoute) *aggregates.DeliveryRoute {
+	clone := *route
+	clone.Stops = slices.Clone(route.Stops)
+	return &clone
+}
+
+// newDeliveryCode sorteia o código de quatro dígitos que o cliente informa ao receber o pedido
+func newDeliveryCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(10000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%04d", n.Int64()), nil
+}
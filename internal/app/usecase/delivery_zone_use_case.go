@@ -0,0 +1,310 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	deliveryzonekind "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_zone_kind"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrDeliveryZoneNotFound      = errors.New("delivery zone not found")
+	ErrInvalidDeliveryZone       = errors.New("invalid delivery zone")
+	ErrOutsideDeliveryArea       = errors.New("address is outside the delivery area")
+	ErrDeliveryAddressNotLocated = errors.New("delivery address could not be located")
+	ErrBelowDeliveryMinimum      = errors.New("order is below the delivery minimum")
+)
+
+type (
+	DeliveryZonePayload struct {
+		Name                 string                            `json:"name"`
+		Kind                 deliveryzonekind.DeliveryZoneKind `json:"kind"`
+		Polygon              *types.GeoPolygon                 `json:"polygon"`
+		Neighborhoods        []string                          `json:"neighborhoods"`
+		City                 string                            `json:"city"`
+		Fee                  float64                           `json:"fee"`
+		MinimumOrderValue    float64                           `json:"minimum_order_value"`
+		EstimatedTimeMinutes int                               `json:"estimated_time_minutes"`
+		Priority             int                               `json:"priority"`
+		// Active ausente mantém a zona como está (novas zonas nascem ativas)
+		Active          *bool `json:"active"`
+		ExpectedVersion int   `json:"-"`
+	}
+
+	DeliveryQuotePayload struct {
+		Address types.Address `json:"address"`
+	}
+
+	IDeliveryZoneUseCase interface {
+		FindByRestaurantId(restaurantId string) ([]aggregates.DeliveryZone, error)
+		FindById(restaurantId, id string) (*aggregates.DeliveryZone, error)
+		Create(actor types.Actor, restaurantId string, payload *DeliveryZonePayload) (*aggregates.DeliveryZone, error)
+		Update(actor types.Actor, restaurantId, id string, payload *DeliveryZonePayload) (*aggregates.DeliveryZone, error)
+		Delete(actor types.Actor, restaurantId, id string) error
+		Quote(restaurantId string, payload *DeliveryQuotePayload) (*aggregates.DeliveryQuote, error)
+	}
+
+	deliveryZoneUseCase struct {
+		deliveryZoneRepository ports.IDeliveryZoneRepository
+		restaurantRepository   ports.IRestaurantRepository
+		addressLookup          ports.IAddressLookup
+		geocoder               ports.IGeocoder
+	}
+)
+
+func NewDeliveryZoneUseCase(
+	deliveryZoneRepository ports.IDeliveryZoneRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	addressLookup ports.IAddressLookup,
+	geocoder ports.IGeocoder,
+) IDeliveryZoneUseCase {
+	return &deliveryZoneUseCase{
+		deliveryZoneRepository: deliveryZoneRepository,
+		restaurantRepository:   restaurantRepository,
+		addressLookup:          addressLookup,
+		geocoder:               geocoder,
+	}
+}
+
+func (u *deliveryZoneUseCase) FindByRestaurantId(restaurantId string) ([]aggregates.DeliveryZone, error) {
+	return u.deliveryZoneRepository.FindByRestaurantId(restaurantId)
+}
+
+func (u *deliveryZoneUseCase) FindById(restaurantId, id string) (*aggregates.DeliveryZone, error) {
+	zone, err := u.deliveryZoneRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil || zone.Restaurant.Id != restaurantId {
+		return nil, ErrDeliveryZoneNotFound
+	}
+
+	return zone, nil
+}
+
+func (u *deliveryZoneUseCase) Create(actor types.Actor, restaurantId string, payload *DeliveryZonePayload) (*aggregates.DeliveryZone, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	if err := validateDeliveryZone(payload); err != nil {
+		return nil, err
+	}
+
+	zone := aggregates.NewDeliveryZone(restaurantId, payload.Name, payload.Kind)
+	applyDeliveryZone(zone, payload)
+
+	err = u.audit(actor, aggregates.AuditActionCreate, nil, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.deliveryZoneRepository.Create(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+func (u *deliveryZoneUseCase) Update(actor types.Actor, restaurantId, id string, payload *DeliveryZonePayload) (*aggregates.DeliveryZone, error) {
+	zone, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.DeliveryZoneAggregateType, zone.Id, zone.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDeliveryZone(payload); err != nil {
+		return nil, err
+	}
+
+	before := *zone
+	applyDeliveryZone(zone, payload)
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.deliveryZoneRepository.Update(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+func (u *deliveryZoneUseCase) Delete(actor types.Actor, restaurantId, id string) error {
+	zone, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return err
+	}
+
+	err = u.audit(actor, aggregates.AuditActionDelete, zone, nil)
+	if err != nil {
+		return err
+	}
+
+	return u.deliveryZoneRepository.Delete(zone)
+}
+
+// Quote simula a entrega em um endereço com as mesmas regras da criação do pedido
+func (u *deliveryZoneUseCase) Quote(restaurantId string, payload *DeliveryQuotePayload) (*aggregates.DeliveryQuote, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+	if !restaurant.Settings.Delivery.Enabled {
+		return nil, ErrDeliveryDisabled
+	}
+
+	err = normalizeAddress(u.addressLookup, &payload.Address)
+	if err != nil {
+		return nil, err
+	}
+	geocodeAddress(u.geocoder, &payload.Address, nil)
+
+	quote, err := quoteDelivery(u.deliveryZoneRepository, restaurant, payload.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+func (u *deliveryZoneUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.DeliveryZone) error {
+	zone := after
+	if zone == nil {
+		zone = before
+	}
+
+	return recordAudit(
+		zone,
+		actor,
+		zone.Restaurant.Id,
+		aggregates.DeliveryZoneAggregateType,
+		action,
+		before,
+		after,
+	)
+}
+
+// quoteDelivery procura a zona que cobre o endereço; sem zona, vale a regra por km da configuração
+// de entrega. Restaurantes sem zonas e sem regra por km entregam sem taxa, como antes das zonas
+func quoteDelivery(
+	deliveryZoneRepository ports.IDeliveryZoneRepository,
+	restaurant *aggregates.Restaurant,
+	address types.Address,
+) (aggregates.DeliveryQuote, error) {
+	distanceKm := 0.0
+	if restaurant.Address.HasCoordinates() && address.HasCoordinates() {
+		distanceKm = math.Round(restaurant.Address.Point().DistanceKm(address.Point())*100) / 100
+	}
+
+	zones, err := deliveryZoneRepository.FindByRestaurantId(restaurant.Id)
+	if err != nil {
+		return aggregates.DeliveryQuote{}, err
+	}
+
+	if zone := aggregates.MatchDeliveryZone(zones, address); zone != nil {
+		return zone.Quote(distanceKm), nil
+	}
+
+	config := restaurant.Settings.Delivery
+	if config.UsesDistance() {
+		if !restaurant.Address.HasCoordinates() || !address.HasCoordinates() {
+			return aggregates.DeliveryQuote{}, ErrDeliveryAddressNotLocated
+		}
+		quote, ok := config.Quote(distanceKm)
+		if !ok {
+			return aggregates.DeliveryQuote{}, ErrOutsideDeliveryArea
+		}
+		return quote, nil
+	}
+
+	for _, zone := range zones {
+		if zone.Active {
+			return aggregates.DeliveryQuote{}, ErrOutsideDeliveryArea
+		}
+	}
+
+	return aggregates.DeliveryQuote{
+		MinimumOrderValue:    float64(config.MinimumOrderValue),
+		EstimatedTimeMinutes: config.AverageTimeMinutes,
+		DistanceKm:           distanceKm,
+	}, nil
+}
+
+func validateDeliveryZone(payload *DeliveryZonePayload) error {
+	payload.Name = strings.TrimSpace(payload.Name)
+	payload.City = strings.TrimSpace(payload.City)
+
+	neighborhoods := make([]string, 0, len(payload.Neighborhoods))
+	for _, neighborhood := range payload.Neighborhoods {
+		if neighborhood = strings.TrimSpace(neighborhood); neighborhood != "" {
+			neighborhoods = append(neighborhoods, neighborhood)
+		}
+	}
+	payload.Neighborhoods = neighborhoods
+
+	switch {
+	case payload.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidDeliveryZone)
+	case !payload.Kind.IsValid():
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidDeliveryZone, payload.Kind)
+	case payload.Fee < 0 || payload.MinimumOrderValue < 0 || payload.EstimatedTimeMinutes < 0:
+		return fmt.Errorf("%w: fee, minimum order value and estimated time cannot be negative", ErrInvalidDeliveryZone)
+	}
+
+	switch payload.Kind {
+	case deliveryzonekind.POLYGON:
+		if payload.Polygon == nil {
+			return fmt.Errorf("%w: polygon is required", ErrInvalidDeliveryZone)
+		}
+		if err := payload.Polygon.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidDeliveryZone, err)
+		}
+		payload.Neighborhoods = nil
+		payload.City = ""
+	case deliveryzonekind.NEIGHBORHOODS:
+		if len(payload.Neighborhoods) == 0 {
+			return fmt.Errorf("%w: at least one neighborhood is required", ErrInvalidDeliveryZone)
+		}
+		payload.Polygon = nil
+	}
+
+	return nil
+}
+
+func applyDeliveryZone(zone *aggregates.DeliveryZone, payload *DeliveryZonePayload) {
+	zone.Name = payload.Name
+	zone.Kind = payload.Kind
+	zone.Polygon = payload.Polygon
+	zone.Neighborhoods = payload.Neighborhoods
+	zone.City = payload.City
+	zone.Fee = roundMoney(payload.Fee)
+	zone.MinimumOrderValue = roundMoney(payload.MinimumOrderValue)
+	zone.EstimatedTimeMinutes = payload.EstimatedTimeMinutes
+	zone.Priority = payload.Priority
+	if payload.Active != nil {
+		zone.Active = *payload.Active
+	}
+	zone.UpdatedAt = time.Now()
+}
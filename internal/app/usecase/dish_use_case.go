@@ -21,20 +21,21 @@ var (
 
 type (
 	DishPayload struct {
-		Name                 string                   `json:"name"`
-		Type                 dishtype.DishType        `json:"type"`
-		PriceWhenUsedAsAddOn float64                  `json:"price_when_used_as_add_on"`
+		Name                 string                       `json:"name"`
+		Type                 dishtype.DishType            `json:"type"`
+		PriceWhenUsedAsAddOn float64                      `json:"price_when_used_as_add_on"`
 		Restaurant           aggregates.PartialRestaurant `json:"restaurant"`
+		ExpectedVersion      int                          `json:"-"`
 	}
 
 	IDishUseCase interface {
 		Find(args types.FindArgs) (*types.PagedSlice[aggregates.Dish], error)
 		FindById(id string) (*aggregates.Dish, error)
-		Create(dish *DishPayload) (*aggregates.Dish, error)
-		Update(id string, dish *DishPayload) (*aggregates.Dish, error)
-		Delete(id string) error
-		SetPicture(id string, picture *types.FilePayload) (*aggregates.Dish, error)
-		DeletePicture(id string) (*aggregates.Dish, error)
+		Create(actor types.Actor, dish *DishPayload) (*aggregates.Dish, error)
+		Update(actor types.Actor, id string, dish *DishPayload) (*aggregates.Dish, error)
+		Delete(actor types.Actor, id string) error
+		SetPicture(actor types.Actor, id string, picture *types.FilePayload, expectedVersion int) (*aggregates.Dish, error)
+		DeletePicture(actor types.Actor, id string, expectedVersion int) (*aggregates.Dish, error)
 	}
 
 	dishUseCase struct {
@@ -78,7 +79,7 @@ func (d *dishUseCase) FindById(id string) (*aggregates.Dish, error) {
 	return dish, nil
 }
 
-func (d *dishUseCase) Create(dishPayload *DishPayload) (*aggregates.Dish, error) {
+func (d *dishUseCase) Create(actor types.Actor, dishPayload *DishPayload) (*aggregates.Dish, error) {
 	exists, err := d.Exists(dishPayload.Name, dishPayload.Restaurant.Id)
 	if err != nil {
 		return nil, err
@@ -93,15 +94,20 @@ func (d *dishUseCase) Create(dishPayload *DishPayload) (*aggregates.Dish, error)
 		dishPayload.Type,
 	)
 
+	err = d.audit(actor, aggregates.AuditActionCreate, nil, dish)
+	if err != nil {
+		return nil, err
+	}
+
 	err = d.dishRepository.Create(dish)
 	if err != nil {
 		return nil, err
-		}
+	}
 
 	return dish, nil
 }
 
-func (d *dishUseCase) Update(id string, dishPayload *DishPayload) (*aggregates.Dish, error) {
+func (d *dishUseCase) Update(actor types.Actor, id string, dishPayload *DishPayload) (*aggregates.Dish, error) {
 	exists, err := d.Exists(dishPayload.Name, dishPayload.Restaurant.Id)
 	if err != nil {
 		return nil, err
@@ -120,10 +126,21 @@ func (d *dishUseCase) Update(id string, dishPayload *DishPayload) (*aggregates.D
 		return nil, ErrDishNotFound
 	}
 
+	err = checkExpectedVersion(aggregates.DishAggregateType, dish.Id, dish.Version, dishPayload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *dish
 	dish.Name = dishPayload.Name
 	dish.Type = dishPayload.Type
 	dish.Restaurant.Id = dishPayload.Restaurant.Id
 
+	err = d.audit(actor, aggregates.AuditActionUpdate, &before, dish)
+	if err != nil {
+		return nil, err
+	}
+
 	err = d.dishRepository.Update(dish)
 	if err != nil {
 		return nil, err
@@ -132,7 +149,7 @@ func (d *dishUseCase) Update(id string, dishPayload *DishPayload) (*aggregates.D
 	return dish, nil
 }
 
-func (d *dishUseCase) Delete(id string) error {
+func (d *dishUseCase) Delete(actor types.Actor, id string) error {
 	dish, err := d.dishRepository.FindById(id)
 	if err != nil {
 		return err
@@ -142,15 +159,15 @@ func (d *dishUseCase) Delete(id string) error {
 		return ErrDishNotFound
 	}
 
-	err = d.dishRepository.Delete(id)
+	err = d.audit(actor, aggregates.AuditActionDelete, dish, nil)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return d.dishRepository.Delete(dish)
 }
 
-func (d *dishUseCase) SetPicture(id string, picture *types.FilePayload) (*aggregates.Dish, error) {
+func (d *dishUseCase) SetPicture(actor types.Actor, id string, picture *types.FilePayload, expectedVersion int) (*aggregates.Dish, error) {
 	dish, err := d.dishRepository.FindById(id)
 	if err != nil {
 		return nil, err
@@ -160,14 +177,25 @@ func (d *dishUseCase) SetPicture(id string, picture *types.FilePayload) (*aggreg
 		return nil, ErrDishNotFound
 	}
 
+	err = checkExpectedVersion(aggregates.DishAggregateType, dish.Id, dish.Version, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	key := fmt.Sprintf("dish_picture_%s", dish.Id)
 	url, err := d.blockStorage.Save(key, dishBucket, picture.Content)
 	if err != nil {
 		return nil, err
 	}
 
+	before := *dish
 	dish.PictureUrl = url
 
+	err = d.audit(actor, aggregates.AuditActionUpdate, &before, dish)
+	if err != nil {
+		return nil, err
+	}
+
 	err = d.dishRepository.Update(dish)
 	if err != nil {
 		return nil, err
@@ -194,7 +222,7 @@ func (d *dishUseCase) Exists(name, restaurantId string) (bool, error) {
 	return false, nil
 }
 
-func (d *dishUseCase) DeletePicture(id string) (*aggregates.Dish, error) {
+func (d *dishUseCase) DeletePicture(actor types.Actor, id string, expectedVersion int) (*aggregates.Dish, error) {
 	dish, err := d.dishRepository.FindById(id)
 	if err != nil {
 		return nil, err
@@ -204,18 +232,46 @@ func (d *dishUseCase) DeletePicture(id string) (*aggregates.Dish, error) {
 		return nil, ErrDishNotFound
 	}
 
+	err = checkExpectedVersion(aggregates.DishAggregateType, dish.Id, dish.Version, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	key := fmt.Sprintf("dish_picture_%s", dish.Id)
 	err = d.blockStorage.Delete(key, dishBucket)
 	if err != nil {
 		return nil, err
 	}
 
+	before := *dish
 	dish.PictureUrl = ""
 
+	err = d.audit(actor, aggregates.AuditActionUpdate, &before, dish)
+	if err != nil {
+		return nil, err
+	}
+
 	err = d.dishRepository.Update(dish)
 	if err != nil {
 		return nil, err
 	}
 
 	return dish, nil
-}
\ No newline at end of file
+}
+
+func (d *dishUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.Dish) error {
+	dish := after
+	if dish == nil {
+		dish = before
+	}
+
+	return recordAudit(
+		dish,
+		actor,
+		dish.Restaurant.Id,
+		aggregates.DishAggregateType,
+		action,
+		before,
+		after,
+	)
+}
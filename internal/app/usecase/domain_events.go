@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+// publishDomainEvents avisa o barramento depois que o agregado foi persistido; o repositório
+// já gravou os eventos na outbox na mesma transação, então aqui eles só são limpos do agregado
+func publishDomainEvents(publisher ports.IEventPublisher, aggregate abstractions.IAggreagateRoot) {
+	events := aggregate.DomainEvents()
+	if len(events) == 0 {
+		return
+	}
+
+	publisher.Publish(events...)
+	aggregate.ClearDomainEvents()
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	driverkind "github.com/PedroNetto404/marmitech-backend/pkg/enums/driver_kind"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrDriverNotFound           = errors.New("driver not found")
+	ErrInvalidDriver            = errors.New("invalid driver")
+	ErrDriverEmailAlreadyExists = errors.New("driver email already exists in this restaurant")
+	// o histórico de rotas e o acerto dependem do entregador; desative em vez de excluir
+	ErrDriverHasRoutes = errors.New("driver has delivery routes and can only be deactivated")
+)
+
+type (
+	DriverPayload struct {
+		Name    string                `json:"name"`
+		Kind    driverkind.DriverKind `json:"kind"`
+		Phone   string                `json:"phone"`
+		Email   string                `json:"email"`
+		Vehicle string                `json:"vehicle"`
+		// Active ausente mantém o entregador como está (novos entregadores nascem ativos)
+		Active          *bool `json:"active"`
+		ExpectedVersion int   `json:"-"`
+	}
+
+	IDriverUseCase interface {
+		FindByRestaurantId(restaurantId string) ([]aggregates.Driver, error)
+		FindById(restaurantId, id string) (*aggregates.Driver, error)
+		Create(actor types.Actor, restaurantId string, payload *DriverPayload) (*aggregates.Driver, error)
+		Update(actor types.Actor, restaurantId, id string, payload *DriverPayload) (*aggregates.Driver, error)
+		Delete(actor types.Actor, restaurantId, id string) error
+		LastLocation(restaurantId, id string) (*aggregates.DriverLocation, error)
+		Settlement(restaurantId string, payload ReportPeriodPayload) ([]dtos.DriverSettlementDto, error)
+	}
+
+	driverUseCase struct {
+		driverRepository         ports.IDriverRepository
+		driverLocationRepository ports.IDriverLocationRepository
+		deliveryRouteRepository  ports.IDeliveryRouteRepository
+		restaurantRepository     ports.IRestaurantRepository
+	}
+)
+
+func NewDriverUseCase(
+	driverRepository ports.IDriverRepository,
+	driverLocationRepository ports.IDriverLocationRepository,
+	deliveryRouteRepository ports.IDeliveryRouteRepository,
+	restaurantRepository ports.IRestaurantRepository,
+) IDriverUseCase {
+	return &driverUseCase{
+		driverRepository:         driverRepository,
+		driverLocationRepository: driverLocationRepository,
+		deliveryRouteRepository:  deliveryRouteRepository,
+		restaurantRepository:     restaurantRepository,
+	}
+}
+
+func (u *driverUseCase) FindByRestaurantId(restaurantId string) ([]aggregates.Driver, error) {
+	return u.driverRepository.FindByRestaurantId(restaurantId)
+}
+
+func (u *driverUseCase) FindById(restaurantId, id string) (*aggregates.Driver, error) {
+	driver, err := u.driverRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if driver == nil || driver.Restaurant.Id != restaurantId {
+		return nil, ErrDriverNotFound
+	}
+
+	return driver, nil
+}
+
+func (u *driverUseCase) Create(actor types.Actor, restaurantId string, payload *DriverPayload) (*aggregates.Driver, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	if err := u.validate(restaurantId, "", payload); err != nil {
+		return nil, err
+	}
+
+	driver := aggregates.NewDriver(restaurantId, payload.Name, payload.Kind)
+	applyDriver(driver, payload)
+
+	err = u.audit(actor, aggregates.AuditActionCreate, nil, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.driverRepository.Create(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+}
+
+func (u *driverUseCase) Update(actor types.Actor, restaurantId, id string, payload *DriverPayload) (*aggregates.Driver, error) {
+	driver, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.DriverAggregateType, driver.Id, driver.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.validate(restaurantId, driver.Id, payload); err != nil {
+		return nil, err
+	}
+
+	before := *driver
+	applyDriver(driver, payload)
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.driverRepository.Update(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+}
+
+func (u *driverUseCase) Delete(actor types.Actor, restaurantId, id string) error {
+	driver, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return err
+	}
+
+	routes, err := u.deliveryRouteRepository.Find(ports.DeliveryRouteQuery{
+		RestaurantId: restaurantId,
+		DriverId:     driver.Id,
+		Limit:        1,
+	})
+	if err != nil {
+		return err
+	}
+	if len(routes) > 0 {
+		return ErrDriverHasRoutes
+	}
+
+	err = u.audit(actor, aggregates.AuditActionDelete, driver, nil)
+	if err != nil {
+		return err
+	}
+
+	return u.driverRepository.Delete(driver)
+}
+
+// LastLocation devolve nil quando o entregador ainda não enviou nenhuma posição
+func (u *driverUseCase) LastLocation(restaurantId, id string) (*aggregates.DriverLocation, error) {
+	driver, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.driverLocationRepository.FindLatest(driver.Id)
+}
+
+func (u *driverUseCase) Settlement(restaurantId string, payload ReportPeriodPayload) ([]dtos.DriverSettlementDto, error) {
+	period, err := resolveReportPeriod(u.restaurantRepository, restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := u.deliveryRouteRepository.Settlement(period.query)
+	if err != nil {
+		return nil, err
+	}
+
+	settlements := make([]dtos.DriverSettlementDto, 0, len(rows))
+	for _, row := range rows {
+		settlements = append(settlements, dtos.DriverSettlementDto{
+			DriverId:   row.DriverId,
+			DriverName: row.DriverName,
+			Deliveries: row.Deliveries,
+			Fees:       roundMoney(row.Fees),
+			AverageFee: averageOf(row.Fees, row.Deliveries),
+		})
+	}
+
+	return settlements, nil
+}
+
+// validate normaliza o e-mail, que é o login do entregador no app, e garante que ele não se repete no restaurante
+func (u *driverUseCase) validate(restaurantId, driverId string, payload *DriverPayload) error {
+	payload.Name = strings.TrimSpace(payload.Name)
+	payload.Email = strings.ToLower(strings.TrimSpace(payload.Email))
+
+	switch {
+	case payload.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidDriver)
+	case !payload.Kind.IsValid():
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidDriver, payload.Kind)
+	case payload.Email != "" && !strings.Contains(payload.Email, "@"):
+		return fmt.Errorf("%w: invalid email", ErrInvalidDriver)
+	}
+
+	if payload.Email == "" {
+		return nil
+	}
+
+	existing, err := u.driverRepository.FindByEmail(restaurantId, payload.Email)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Id != driverId {
+		return ErrDriverEmailAlreadyExists
+	}
+
+	return nil
+}
+
+func (u *driverUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.Driver) error {
+	driver := after
+	if driver == nil {
+		driver = before
+	}
+
+	return recordAudit(
+		driver,
+		actor,
+		driver.Restaurant.Id,
+		aggregates.DriverAggregateType,
+		action,
+		before,
+		after,
+	)
+}
+
+func applyDriver(driver *aggregates.Driver, payload *DriverPayload) {
+	driver.Name = payload.Name
+	driver.Kind = payload.Kind
+	driver.Phone = strings.TrimSpace(payload.Phone)
+	driver.Email = payload.Email
+	driver.Vehicle = strings.TrimSpace(payload.Vehicle)
+	if payload.Active != nil {
+		driver.Active = *payload.Active
+	}
+	driver.UpdatedAt = time.Now()
+}
@@ -0,0 +1,398 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	taxregime "github.com/PedroNetto404/marmitech-backend/pkg/enums/tax_regime"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+const fiscalBucket = "fiscal"
+
+var (
+	ErrFiscalProfileNotFound       = errors.New("fiscal profile not found")
+	ErrInvalidFiscalProfile        = errors.New("invalid fiscal profile")
+	ErrInvalidFiscalCertificate    = errors.New("invalid or expired A1 certificate")
+	ErrFiscalCertificateMissing    = errors.New("A1 certificate not uploaded")
+	ErrFiscalDocumentNotFound      = errors.New("fiscal document not found")
+	ErrFiscalDocumentAlreadyIssued = errors.New("order already has an authorized fiscal document")
+	ErrIncompleteProductFiscalData = errors.New("order has products without fiscal data")
+	ErrOrderNotPaid                = errors.New("order is not fully paid")
+)
+
+var (
+	cityCodeRegex = regexp.MustCompile(`^\d{7}$`)
+	cscIdRegex    = regexp.MustCompile(`^\d{1,6}$`)
+)
+
+type (
+	// FiscalProfilePayload atualiza o emitente; CscToken vazio mantém o token atual
+	FiscalProfilePayload struct {
+		TaxRegime         taxregime.TaxRegime          `json:"tax_regime"`
+		StateRegistration string                       `json:"state_registration"`
+		CityCode          string                       `json:"city_code"`
+		Environment       aggregates.FiscalEnvironment `json:"environment"`
+		Series            int                          `json:"series"`
+		NextNumber        int                          `json:"next_number"`
+		CscId             string                       `json:"csc_id"`
+		CscToken          string                       `json:"csc_token"`
+		ExpectedVersion   int                          `json:"-"`
+	}
+
+	IFiscalDocumentUseCase interface {
+		FindProfile(restaurantId string) (*aggregates.FiscalProfile, error)
+		SaveProfile(actor types.Actor, restaurantId string, payload *FiscalProfilePayload) (*aggregates.FiscalProfile, error)
+		UploadCertificate(actor types.Actor, restaurantId string, certificate *types.FilePayload, password string) (*aggregates.FiscalProfile, error)
+		FindByOrderId(restaurantId, orderId string) (*aggregates.FiscalDocument, error)
+		Issue(actor types.Actor, restaurantId, orderId string) (*aggregates.FiscalDocument, error)
+	}
+
+	fiscalDocumentUseCase struct {
+		fiscalProfileRepository  ports.IFiscalProfileRepository
+		fiscalDocumentRepository ports.IFiscalDocumentRepository
+		orderRepository          ports.IOrderRepository
+		productRepository        ports.IProductRepository
+		restaurantRepository     ports.IRestaurantRepository
+		fiscalDocumentIssuer     ports.IFiscalDocumentIssuer
+		blockStorage             ports.IBlockStorage
+	}
+)
+
+func NewFiscalDocumentUseCase(
+	fiscalProfileRepository ports.IFiscalProfileRepository,
+	fiscalDocumentRepository ports.IFiscalDocumentRepository,
+	orderRepository ports.IOrderRepository,
+	productRepository ports.IProductRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	fiscalDocumentIssuer ports.IFiscalDocumentIssuer,
+	blockStorage ports.IBlockStorage,
+) IFiscalDocumentUseCase {
+	return &fiscalDocumentUseCase{
+		fiscalProfileRepository:  fiscalProfileRepository,
+		fiscalDocumentRepository: fiscalDocumentRepository,
+		orderRepository:          orderRepository,
+		productRepository:        productRepository,
+		restaurantRepository:     restaurantRepository,
+		fiscalDocumentIssuer:     fiscalDocumentIssuer,
+		blockStorage:             blockStorage,
+	}
+}
+
+func (u *fiscalDocumentUseCase) FindProfile(restaurantId string) (*aggregates.FiscalProfile, error) {
+	profile, err := u.fiscalProfileRepository.FindByRestaurantId(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrFiscalProfileNotFound
+	}
+
+	return profile, nil
+}
+
+func (u *fiscalDocumentUseCase) SaveProfile(actor types.Actor, restaurantId string, payload *FiscalProfilePayload) (*aggregates.FiscalProfile, error) {
+	if err := validateFiscalProfile(payload); err != nil {
+		return nil, err
+	}
+
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	profile, err := u.fiscalProfileRepository.FindByRestaurantId(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == nil {
+		if payload.CscToken == "" {
+			return nil, fmt.Errorf("%w: csc token is required", ErrInvalidFiscalProfile)
+		}
+
+		profile = aggregates.NewFiscalProfile(restaurantId)
+		applyFiscalProfile(profile, payload)
+
+		err = u.auditProfile(actor, aggregates.AuditActionCreate, nil, profile)
+		if err != nil {
+			return nil, err
+		}
+
+		err = u.fiscalProfileRepository.Create(profile)
+		if err != nil {
+			return nil, err
+		}
+
+		return profile, nil
+	}
+
+	err = checkExpectedVersion(aggregates.FiscalProfileAggregateType, profile.Id, profile.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *profile
+	applyFiscalProfile(profile, payload)
+
+	err = u.auditProfile(actor, aggregates.AuditActionUpdate, &before, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.fiscalProfileRepository.Update(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (u *fiscalDocumentUseCase) UploadCertificate(actor types.Actor, restaurantId string, certificate *types.FilePayload, password string) (*aggregates.FiscalProfile, error) {
+	profile, err := u.FindProfile(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	inspected, err := u.fiscalDocumentIssuer.InspectCertificate(certificate.Content, password)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFiscalCertificate, err)
+	}
+	if inspected.NotAfter.Before(time.Now()) {
+		return nil, ErrInvalidFiscalCertificate
+	}
+
+	_, err = u.blockStorage.Save(fiscalCertificateKey(restaurantId), fiscalBucket, certificate.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *profile
+	profile.CertificatePassword = password
+	profile.CertificateSubject = inspected.Subject
+	profile.CertificateExpiresAt = &inspected.NotAfter
+	profile.UpdatedAt = time.Now()
+
+	err = u.auditProfile(actor, aggregates.AuditActionUpdate, &before, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.fiscalProfileRepository.Update(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (u *fiscalDocumentUseCase) FindByOrderId(restaurantId, orderId string) (*aggregates.FiscalDocument, error) {
+	document, err := u.fiscalDocumentRepository.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+	if document == nil || document.Restaurant.Id != restaurantId {
+		return nil, ErrFiscalDocumentNotFound
+	}
+
+	return document, nil
+}
+
+// Issue emite a NFC-e do pedido. Uma nota pendente ou rejeitada é reenviada com o
+// mesmo número; a rejeição volta como documento, não como erro, para expor o motivo.
+func (u *fiscalDocumentUseCase) Issue(actor types.Actor, restaurantId, orderId string) (*aggregates.FiscalDocument, error) {
+	order, err := u.orderRepository.FindById(orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.Restaurant.Id != restaurantId {
+		return nil, ErrOrderNotFound
+	}
+	if !order.HasBeenFullyPaidVirtual() {
+		return nil, ErrOrderNotPaid
+	}
+
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	profile, err := u.FindProfile(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if !profile.HasCertificate() {
+		return nil, ErrFiscalCertificateMissing
+	}
+
+	products := make(map[string]aggregates.Product, len(order.Items))
+	for _, item := range order.Items {
+		product, err := u.productRepository.FindById(item.Product.Id)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil || !product.Fiscal.IsValid() {
+			return nil, fmt.Errorf("%w: %s", ErrIncompleteProductFiscalData, item.Product.Name)
+		}
+		products[product.Id] = *product
+	}
+
+	certificate, err := u.blockStorage.Get(fiscalCertificateKey(restaurantId), fiscalBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := u.fiscalDocumentRepository.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+	if document != nil && document.IsAuthorized() {
+		return nil, ErrFiscalDocumentAlreadyIssued
+	}
+
+	action := aggregates.AuditActionUpdate
+	var before *aggregates.FiscalDocument
+	if document == nil {
+		action = aggregates.AuditActionCreate
+		document, err = u.reserveDocument(profile, orderId)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		previous := *document
+		before = &previous
+		// a SEFAZ rejeita emissão com data muito antiga, então o reenvio usa a hora atual
+		document.IssuedAt = time.Now()
+	}
+
+	authorization, err := u.fiscalDocumentIssuer.Issue(ports.FiscalIssueRequest{
+		Restaurant:  restaurant,
+		Profile:     profile,
+		Order:       order,
+		Products:    products,
+		Document:    document,
+		Certificate: certificate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if authorization.Authorized {
+		document.Authorize(
+			authorization.AccessKey,
+			authorization.Protocol,
+			authorization.StatusCode,
+			authorization.StatusReason,
+			authorization.Xml,
+			authorization.QrCodeUrl,
+			authorization.AuthorizedAt,
+		)
+	} else {
+		document.Reject(authorization.AccessKey, authorization.StatusCode, authorization.StatusReason)
+	}
+
+	err = recordAudit(
+		document,
+		actor,
+		restaurantId,
+		aggregates.FiscalDocumentAggregateType,
+		action,
+		before,
+		document,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.fiscalDocumentRepository.Update(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// reserveDocument consome o próximo número da série; a atualização versionada do
+// perfil impede que duas emissões concorrentes recebam o mesmo número
+func (u *fiscalDocumentUseCase) reserveDocument(profile *aggregates.FiscalProfile, orderId string) (*aggregates.FiscalDocument, error) {
+	number := profile.ReserveNumber()
+
+	err := u.fiscalProfileRepository.Update(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	document := aggregates.NewFiscalDocument(profile.Restaurant.Id, orderId, profile.Series, number, profile.Environment)
+
+	err = u.fiscalDocumentRepository.Create(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (u *fiscalDocumentUseCase) auditProfile(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.FiscalProfile) error {
+	profile := after
+	if profile == nil {
+		profile = before
+	}
+
+	return recordAudit(
+		profile,
+		actor,
+		profile.Restaurant.Id,
+		aggregates.FiscalProfileAggregateType,
+		action,
+		before,
+		after,
+	)
+}
+
+func validateFiscalProfile(payload *FiscalProfilePayload) error {
+	switch {
+	case !payload.TaxRegime.IsValid():
+		return fmt.Errorf("%w: unknown tax regime", ErrInvalidFiscalProfile)
+	case !cityCodeRegex.MatchString(payload.CityCode):
+		return fmt.Errorf("%w: city code must be the 7-digit IBGE code", ErrInvalidFiscalProfile)
+	case payload.Environment != aggregates.FiscalEnvironmentProduction && payload.Environment != aggregates.FiscalEnvironmentHomologation:
+		return fmt.Errorf("%w: unknown environment", ErrInvalidFiscalProfile)
+	// séries 890 a 999 são reservadas para a nota avulsa
+	case payload.Series < 0 || payload.Series > 889:
+		return fmt.Errorf("%w: series must be between 0 and 889", ErrInvalidFiscalProfile)
+	case payload.NextNumber < 1 || payload.NextNumber > 999999999:
+		return fmt.Errorf("%w: next number must be between 1 and 999999999", ErrInvalidFiscalProfile)
+	case !cscIdRegex.MatchString(payload.CscId):
+		return fmt.Errorf("%w: csc id must have up to 6 digits", ErrInvalidFiscalProfile)
+	}
+
+	return nil
+}
+
+func applyFiscalProfile(profile *aggregates.FiscalProfile, payload *FiscalProfilePayload) {
+	profile.TaxRegime = payload.TaxRegime
+	profile.StateRegistration = payload.StateRegistration
+	profile.CityCode = payload.CityCode
+	profile.Environment = payload.Environment
+	profile.Series = payload.Series
+	profile.NextNumber = payload.NextNumber
+	profile.CscId = payload.CscId
+	if payload.CscToken != "" {
+		profile.CscToken = payload.CscToken
+	}
+	profile.UpdatedAt = time.Now()
+}
+
+func fiscalCertificateKey(restaurantId string) string {
+	return fmt.Sprintf("restaurants_%s_certificate", restaurantId)
+}
@@ -0,0 +1,575 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	stockitemkind "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_item_kind"
+	stockmovementtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_movement_type"
+	stockunit "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_unit"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrStockItemNotFound    = errors.New("stock item not found")
+	ErrInvalidStockItem     = errors.New("invalid stock item")
+	ErrStockItemNameTaken   = errors.New("stock item name already exists in this restaurant")
+	ErrInvalidStockMovement = errors.New("invalid stock movement")
+	ErrRecipeNotFound       = errors.New("recipe not found")
+	ErrInvalidRecipe        = errors.New("invalid recipe")
+)
+
+const (
+	// a varredura baixa o que ficou sem baixa, como pedidos criados antes da outbox existir
+	inventoryReconcileInterval = 15 * time.Minute
+	inventoryReconcileWindow   = 48 * time.Hour
+	// pedidos mais novos que isso ainda estão com o consumidor da outbox
+	inventoryReconcileGrace = 5 * time.Minute
+	inventoryReconcileBatch = 200
+)
+
+type (
+	StockItemPayload struct {
+		Name            string                      `json:"name"`
+		Kind            stockitemkind.StockItemKind `json:"kind"`
+		Unit            stockunit.StockUnit         `json:"unit"`
+		MinimumQuantity float64                     `json:"minimum_quantity"`
+		Active          bool                        `json:"active"`
+		ExpectedVersion int                         `json:"-"`
+	}
+
+	// StockMovementPayload lança uma movimentação manual; Quantity é positiva,
+	// exceto no ajuste, que leva o sinal da correção
+	StockMovementPayload struct {
+		Type     stockmovementtype.StockMovementType `json:"type"`
+		Quantity float64                             `json:"quantity"`
+		UnitCost float64                             `json:"unit_cost"`
+		Note     string                              `json:"note"`
+	}
+
+	RecipePayload struct {
+		Items           []aggregates.RecipeItem `json:"items"`
+		ExpectedVersion int                     `json:"-"`
+	}
+
+	IInventoryUseCase interface {
+		FindStock(restaurantId string, onlyLow bool) ([]aggregates.StockItem, error)
+		FindStockItemById(restaurantId, id string) (*aggregates.StockItem, error)
+		CreateStockItem(actor types.Actor, restaurantId string, payload *StockItemPayload) (*aggregates.StockItem, error)
+		UpdateStockItem(actor types.Actor, restaurantId, id string, payload *StockItemPayload) (*aggregates.StockItem, error)
+		AddMovement(actor types.Actor, restaurantId, id string, payload *StockMovementPayload) (*aggregates.StockItem, error)
+		FindMovements(restaurantId, id string, from, to *time.Time) ([]aggregates.StockMovement, error)
+		FindProductRecipe(restaurantId, productId string) (*aggregates.Recipe, error)
+		FindDishRecipe(restaurantId, dishId string) (*aggregates.Recipe, error)
+		SaveProductRecipe(actor types.Actor, restaurantId, productId string, payload *RecipePayload) (*aggregates.Recipe, error)
+		SaveDishRecipe(actor types.Actor, restaurantId, dishId string, payload *RecipePayload) (*aggregates.Recipe, error)
+		// Listen baixa o estoque dos pedidos criados e estorna o que não foi preparado nos cancelados;
+		// a função devolvida encerra a assinatura
+		Listen() func()
+	}
+
+	inventoryUseCase struct {
+		stockItemRepository  ports.IStockItemRepository
+		recipeRepository     ports.IRecipeRepository
+		orderRepository      ports.IOrderRepository
+		productRepository    ports.IProductRepository
+		dishRepository       ports.IDishRepository
+		restaurantRepository ports.IRestaurantRepository
+		eventBus             ports.IEventBus
+	}
+)
+
+func NewInventoryUseCase(
+	stockItemRepository ports.IStockItemRepository,
+	recipeRepository ports.IRecipeRepository,
+	orderRepository ports.IOrderRepository,
+	productRepository ports.IProductRepository,
+	dishRepository ports.IDishRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	eventBus ports.IEventBus,
+) IInventoryUseCase {
+	return &inventoryUseCase{
+		stockItemRepository:  stockItemRepository,
+		recipeRepository:     recipeRepository,
+		orderRepository:      orderRepository,
+		productRepository:    productRepository,
+		dishRepository:       dishRepository,
+		restaurantRepository: restaurantRepository,
+		eventBus:             eventBus,
+	}
+}
+
+func (u *inventoryUseCase) FindStock(restaurantId string, onlyLow bool) ([]aggregates.StockItem, error) {
+	return u.stockItemRepository.FindByRestaurantId(restaurantId, onlyLow)
+}
+
+func (u *inventoryUseCase) FindStockItemById(restaurantId, id string) (*aggregates.StockItem, error) {
+	item, err := u.stockItemRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.Restaurant.Id != restaurantId {
+		return nil, ErrStockItemNotFound
+	}
+
+	return item, nil
+}
+
+func (u *inventoryUseCase) CreateStockItem(actor types.Actor, restaurantId string, payload *StockItemPayload) (*aggregates.StockItem, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	if err := u.validateStockItem(restaurantId, "", payload); err != nil {
+		return nil, err
+	}
+
+	item := aggregates.NewStockItem(restaurantId, payload.Name, payload.Kind, payload.Unit)
+	item.MinimumQuantity = payload.MinimumQuantity
+	item.Active = payload.Active
+
+	err = u.auditStockItem(actor, aggregates.AuditActionCreate, nil, item)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.stockItemRepository.Create(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (u *inventoryUseCase) UpdateStockItem(actor types.Actor, restaurantId, id string, payload *StockItemPayload) (*aggregates.StockItem, error) {
+	item, err := u.FindStockItemById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.validateStockItem(restaurantId, item.Id, payload); err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.StockItemAggregateType, item.Id, item.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *item
+	item.Name = payload.Name
+	item.Kind = payload.Kind
+	item.Unit = payload.Unit
+	item.MinimumQuantity = payload.MinimumQuantity
+	item.Active = payload.Active
+	item.UpdatedAt = time.Now()
+
+	err = u.auditStockItem(actor, aggregates.AuditActionUpdate, &before, item)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.stockItemRepository.Update(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (u *inventoryUseCase) AddMovement(actor types.Actor, restaurantId, id string, payload *StockMovementPayload) (*aggregates.StockItem, error) {
+	switch {
+	case !payload.Type.IsValid():
+		return nil, fmt.Errorf("%w: unknown movement type", ErrInvalidStockMovement)
+	case payload.Type == stockmovementtype.ADJUSTMENT && payload.Quantity == 0:
+		return nil, fmt.Errorf("%w: adjustment must not be zero", ErrInvalidStockMovement)
+	case payload.Type != stockmovementtype.ADJUSTMENT && payload.Quantity <= 0:
+		return nil, fmt.Errorf("%w: quantity must be greater than zero", ErrInvalidStockMovement)
+	case payload.UnitCost < 0:
+		return nil, fmt.Errorf("%w: unit cost must not be negative", ErrInvalidStockMovement)
+	}
+
+	item, err := u.FindStockItemById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *item
+	movement := item.Move(payload.Type, payload.Quantity, payload.UnitCost, "", payload.Note, actor.Email, time.Now())
+
+	err = u.auditStockItem(actor, aggregates.AuditActionUpdate, &before, item)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.stockItemRepository.AddMovement(item, movement)
+	if err != nil {
+		return nil, err
+	}
+
+	publishDomainEvents(u.eventBus, item)
+	return item, nil
+}
+
+func (u *inventoryUseCase) FindMovements(restaurantId, id string, from, to *time.Time) ([]aggregates.StockMovement, error) {
+	item, err := u.FindStockItemById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.stockItemRepository.FindMovements(item.Id, from, to)
+}
+
+func (u *inventoryUseCase) FindProductRecipe(restaurantId, productId string) (*aggregates.Recipe, error) {
+	recipe, err := u.recipeRepository.FindByProductId(productId)
+	if err != nil {
+		return nil, err
+	}
+	if recipe == nil || recipe.Restaurant.Id != restaurantId {
+		return nil, ErrRecipeNotFound
+	}
+
+	return recipe, nil
+}
+
+func (u *inventoryUseCase) FindDishRecipe(restaurantId, dishId string) (*aggregates.Recipe, error) {
+	recipe, err := u.recipeRepository.FindByDishId(dishId)
+	if err != nil {
+		return nil, err
+	}
+	if recipe == nil || recipe.Restaurant.Id != restaurantId {
+		return nil, ErrRecipeNotFound
+	}
+
+	return recipe, nil
+}
+
+func (u *inventoryUseCase) SaveProductRecipe(actor types.Actor, restaurantId, productId string, payload *RecipePayload) (*aggregates.Recipe, error) {
+	product, err := u.productRepository.FindById(productId)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil || product.Restaurant.Id != restaurantId {
+		return nil, ErrProductNotFound
+	}
+
+	recipe, err := u.recipeRepository.FindByProductId(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.saveRecipe(actor, restaurantId, recipe, aggregates.NewRecipe(restaurantId, productId, ""), payload)
+}
+
+func (u *inventoryUseCase) SaveDishRecipe(actor types.Actor, restaurantId, dishId string, payload *RecipePayload) (*aggregates.Recipe, error) {
+	dish, err := u.dishRepository.FindById(dishId)
+	if err != nil {
+		return nil, err
+	}
+	if dish == nil || dish.Restaurant.Id != restaurantId {
+		return nil, ErrDishNotFound
+	}
+
+	recipe, err := u.recipeRepository.FindByDishId(dishId)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.saveRecipe(actor, restaurantId, recipe, aggregates.NewRecipe(restaurantId, "", dishId), payload)
+}
+
+// saveRecipe atualiza a ficha existente ou cria a nova quando o alvo ainda não tem ficha
+func (u *inventoryUseCase) saveRecipe(
+	actor types.Actor,
+	restaurantId string,
+	recipe *aggregates.Recipe,
+	newRecipe *aggregates.Recipe,
+	payload *RecipePayload,
+) (*aggregates.Recipe, error) {
+	if err := u.validateRecipe(restaurantId, payload); err != nil {
+		return nil, err
+	}
+
+	if recipe == nil {
+		newRecipe.Items = orEmpty(payload.Items)
+
+		err := u.auditRecipe(actor, aggregates.AuditActionCreate, nil, newRecipe)
+		if err != nil {
+			return nil, err
+		}
+
+		err = u.recipeRepository.Create(newRecipe)
+		if err != nil {
+			return nil, err
+		}
+
+		return newRecipe, nil
+	}
+
+	err := checkExpectedVersion(aggregates.RecipeAggregateType, recipe.Id, recipe.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *recipe
+	recipe.Items = orEmpty(payload.Items)
+	recipe.UpdatedAt = time.Now()
+
+	err = u.auditRecipe(actor, aggregates.AuditActionUpdate, &before, recipe)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.recipeRepository.Update(recipe)
+	if err != nil {
+		return nil, err
+	}
+
+	return recipe, nil
+}
+
+func (u *inventoryUseCase) Listen() func() {
+	stopConsuming := u.eventBus.Consume("inventory", u.handle)
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(inventoryReconcileInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := u.reconcile(time.Now()); err != nil {
+				log.Printf("⚠️ failed to reconcile stock consumption: %v", err)
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		stopConsuming()
+	}
+}
+
+func (u *inventoryUseCase) handle(event abstractions.DomainEvent) error {
+	switch event.Name {
+	case aggregates.OrderCreatedEvent:
+		return u.consume(event.AggregateId)
+	case aggregates.OrderCancelledEvent:
+		return u.restock(event.AggregateId)
+	}
+
+	return nil
+}
+
+// reconcile baixa os pedidos recentes que têm ficha técnica e ainda não têm consumo lançado;
+// a paginação por id evita que pedidos sem nada a baixar ocupem sempre o mesmo lote
+func (u *inventoryUseCase) reconcile(now time.Time) error {
+	afterId := ""
+	for {
+		orderIds, err := u.stockItemRepository.FindOrdersPendingConsumption(
+			now.Add(-inventoryReconcileWindow),
+			now.Add(-inventoryReconcileGrace),
+			afterId,
+			inventoryReconcileBatch,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, orderId := range orderIds {
+			if err := u.consume(orderId); err != nil {
+				log.Printf("⚠️ failed to move stock for order %s: %v", orderId, err)
+			}
+		}
+
+		if len(orderIds) < inventoryReconcileBatch {
+			return nil
+		}
+		afterId = orderIds[len(orderIds)-1]
+	}
+}
+
+// consume baixa pela ficha técnica tudo o que o pedido vai usar. Não existe uma etapa de
+// confirmação separada: o pedido entra na fila da cozinha ao ser criado, e os do marketplace
+// só são criados depois de aceitos, então a criação é o momento em que ele está confirmado
+func (u *inventoryUseCase) consume(orderId string) error {
+	order, err := u.orderRepository.FindById(orderId)
+	if err != nil || order == nil {
+		return err
+	}
+
+	consumed, err := u.stockItemRepository.HasOrderMovements(order.Id, stockmovementtype.CONSUMPTION)
+	if err != nil || consumed {
+		return err
+	}
+
+	return u.moveForOrder(order, order.Items, stockmovementtype.CONSUMPTION, fmt.Sprintf("Pedido %s", order.Id))
+}
+
+// restock devolve ao estoque o que o pedido cancelado baixou e a cozinha não chegou a preparar
+func (u *inventoryUseCase) restock(orderId string) error {
+	order, err := u.orderRepository.FindById(orderId)
+	if err != nil || order == nil {
+		return err
+	}
+
+	consumed, err := u.stockItemRepository.HasOrderMovements(order.Id, stockmovementtype.CONSUMPTION)
+	if err != nil || !consumed {
+		return err
+	}
+
+	restocked, err := u.stockItemRepository.HasOrderMovements(order.Id, stockmovementtype.ADJUSTMENT)
+	if err != nil || restocked {
+		return err
+	}
+
+	pending := make([]aggregates.OrderItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.Status == orderstatus.PENDING {
+			pending = append(pending, item)
+		}
+	}
+
+	return u.moveForOrder(order, pending, stockmovementtype.ADJUSTMENT, fmt.Sprintf("Estorno do pedido cancelado %s", order.Id))
+}
+
+func (u *inventoryUseCase) moveForOrder(
+	order *aggregates.Order,
+	items []aggregates.OrderItem,
+	movementType stockmovementtype.StockMovementType,
+	note string,
+) error {
+	recipes, err := u.recipeRepository.FindByRestaurantId(order.Restaurant.Id)
+	if err != nil {
+		return err
+	}
+
+	consumption := aggregates.ConsumptionOf(items, recipes)
+	if len(consumption) == 0 {
+		return nil
+	}
+
+	stock, err := u.stockItemRepository.FindByRestaurantId(order.Restaurant.Id, false)
+	if err != nil {
+		return err
+	}
+
+	// a ordem fixa dos itens evita deadlock entre pedidos simultâneos
+	slices.SortFunc(stock, func(a, b aggregates.StockItem) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	now := time.Now()
+	touched := make([]*aggregates.StockItem, 0, len(consumption))
+	movements := make([]*aggregates.StockMovement, 0, len(consumption))
+	for i := range stock {
+		quantity, ok := consumption[stock[i].Id]
+		if !ok || quantity == 0 {
+			continue
+		}
+
+		movements = append(movements, stock[i].Move(movementType, quantity, 0, order.Id, note, "", now))
+		touched = append(touched, &stock[i])
+	}
+	if len(movements) == 0 {
+		return nil
+	}
+
+	err = u.stockItemRepository.AddOrderMovements(touched, movements)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range touched {
+		publishDomainEvents(u.eventBus, item)
+	}
+	return nil
+}
+
+func (u *inventoryUseCase) validateRecipe(restaurantId string, payload *RecipePayload) error {
+	seen := make([]string, 0, len(payload.Items))
+	for _, item := range payload.Items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("%w: quantities must be greater than zero", ErrInvalidRecipe)
+		}
+		if slices.Contains(seen, item.StockItemId) {
+			return fmt.Errorf("%w: stock item %s repeated", ErrInvalidRecipe, item.StockItemId)
+		}
+		seen = append(seen, item.StockItemId)
+
+		if _, err := u.FindStockItemById(restaurantId, item.StockItemId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *inventoryUseCase) validateStockItem(restaurantId, stockItemId string, payload *StockItemPayload) error {
+	payload.Name = strings.TrimSpace(payload.Name)
+
+	switch {
+	case payload.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidStockItem)
+	case !payload.Kind.IsValid():
+		return fmt.Errorf("%w: unknown kind", ErrInvalidStockItem)
+	case !payload.Unit.IsValid():
+		return fmt.Errorf("%w: unknown unit", ErrInvalidStockItem)
+	case payload.MinimumQuantity < 0:
+		return fmt.Errorf("%w: minimum quantity must not be negative", ErrInvalidStockItem)
+	}
+
+	stock, err := u.stockItemRepository.FindByRestaurantId(restaurantId, false)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range stock {
+		if item.Id != stockItemId && strings.EqualFold(item.Name, payload.Name) {
+			return ErrStockItemNameTaken
+		}
+	}
+
+	return nil
+}
+
+func (u *inventoryUseCase) auditStockItem(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.StockItem) error {
+	return recordAudit(
+		after,
+		actor,
+		after.Restaurant.Id,
+		aggregates.StockItemAggregateType,
+		action,
+		before,
+		after,
+	)
+}
+
+func (u *inventoryUseCase) auditRecipe(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.Recipe) error {
+	return recordAudit(
+		after,
+		actor,
+		after.Restaurant.Id,
+		aggregates.RecipeAggregateType,
+		action,
+		before,
+		after,
+	)
+}
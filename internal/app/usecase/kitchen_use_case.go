@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrOrderItemNotFound    = errors.New("order item not found")
+	ErrInvalidKitchenStatus = errors.New("kitchen can only move items to preparing or ready")
+)
+
+type (
+	IKitchenUseCase interface {
+		Queue(restaurantId string) (*dtos.KitchenQueueDto, error)
+		// Subscribe acompanha os pedidos do restaurante; a função devolvida encerra a assinatura
+		Subscribe(restaurantId string) (<-chan dtos.KitchenEventDto, func())
+		SetOrderStatus(actor types.Actor, restaurantId, orderId string, status orderstatus.OrderStatus) (*dtos.KitchenOrderDto, error)
+		SetItemStatus(actor types.Actor, restaurantId, orderId, itemId string, status orderstatus.OrderStatus) (*dtos.KitchenOrderDto, error)
+	}
+
+	kitchenUseCase struct {
+		orderRepository ports.IOrderRepository
+		eventBus        ports.IEventBus
+	}
+)
+
+func NewKitchenUseCase(
+	orderRepository ports.IOrderRepository,
+	eventBus ports.IEventBus,
+) IKitchenUseCase {
+	return &kitchenUseCase{
+		orderRepository: orderRepository,
+		eventBus:        eventBus,
+	}
+}
+
+func (u *kitchenUseCase) Queue(restaurantId string) (*dtos.KitchenQueueDto, error) {
+	orders, err := u.orderRepository.FindInKitchen(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	return dtos.NewKitchenQueueDto(orders), nil
+}
+
+func (u *kitchenUseCase) Subscribe(restaurantId string) (<-chan dtos.KitchenEventDto, func()) {
+	events, unsubscribe := u.eventBus.Subscribe()
+	updates := make(chan dtos.KitchenEventDto)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(updates)
+
+		for event := range events {
+			switch event.Name {
+			case aggregates.OrderCreatedEvent, aggregates.OrderUpdatedEvent, aggregates.OrderCancelledEvent:
+			default:
+				continue
+			}
+
+			// o evento só carrega o id: o pedido é relido para enviar o estado atual
+			order, err := u.orderRepository.FindById(event.AggregateId)
+			if err != nil {
+				log.Printf("⚠️ failed to load order %s for kitchen stream: %v", event.AggregateId, err)
+				continue
+			}
+			if order == nil || order.Restaurant.Id != restaurantId {
+				continue
+			}
+
+			select {
+			case updates <- dtos.KitchenEventDto{Type: string(event.Name), Order: dtos.MapOrderToKitchenDto(order)}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	stop := func() {
+		close(done)
+		unsubscribe()
+	}
+
+	return updates, stop
+}
+
+func (u *kitchenUseCase) SetOrderStatus(actor types.Actor, restaurantId, orderId string, status orderstatus.OrderStatus) (*dtos.KitchenOrderDto, error) {
+	return u.bump(actor, restaurantId, orderId, status, func(order *aggregates.Order) error {
+		if !order.SetAllItemsStatus(status) {
+			return ErrInvalidOrderTransition
+		}
+		return nil
+	})
+}
+
+func (u *kitchenUseCase) SetItemStatus(actor types.Actor, restaurantId, orderId, itemId string, status orderstatus.OrderStatus) (*dtos.KitchenOrderDto, error) {
+	return u.bump(actor, restaurantId, orderId, status, func(order *aggregates.Order) error {
+		if order.FindItem(itemId) == nil {
+			return ErrOrderItemNotFound
+		}
+		if !order.SetItemStatus(itemId, status) {
+			return ErrInvalidOrderTransition
+		}
+		return nil
+	})
+}
+
+func (u *kitchenUseCase) bump(
+	actor types.Actor,
+	restaurantId string,
+	orderId string,
+	status orderstatus.OrderStatus,
+	apply func(order *aggregates.Order) error,
+) (*dtos.KitchenOrderDto, error) {
+	if status != orderstatus.PREPARING && status != orderstatus.READY {
+		return nil, ErrInvalidKitchenStatus
+	}
+
+	order, err := u.orderRepository.FindById(orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.Restaurant.Id != restaurantId {
+		return nil, ErrOrderNotFound
+	}
+
+	before := *order
+	before.Items = slices.Clone(order.Items)
+
+	if err := apply(order); err != nil {
+		return nil, err
+	}
+
+	err = recordAudit(
+		order,
+		actor,
+		order.Restaurant.Id,
+		aggregates.OrderAggregateType,
+		aggregates.AuditActionUpdate,
+		&before,
+		order,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.orderRepository.Update(order)
+	if err != nil {
+		return nil, err
+	}
+
+	publishDomainEvents(u.eventBus, order)
+
+	dto := dtos.MapOrderToKitchenDto(order)
+	return &dto, nil
+}
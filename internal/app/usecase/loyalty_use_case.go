@@ -0,0 +1,476 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	loyaltymode "github.com/PedroNetto404/marmitech-backend/pkg/enums/loyalty_mode"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrLoyaltyProgramNotFound        = errors.New("loyalty program not found")
+	ErrInvalidLoyaltyProgram         = errors.New("invalid loyalty program")
+	ErrLoyaltyAccountNotFound        = errors.New("customer has no loyalty account in this restaurant")
+	ErrLoyaltyRewardRequiresCustomer = errors.New("loyalty reward requires an identified customer")
+	ErrInsufficientLoyaltyPoints     = errors.New("not enough loyalty points for a reward")
+	ErrLoyaltyRewardNotApplicable    = errors.New("loyalty reward does not apply to this order")
+)
+
+const (
+	// tentativas de pontuar um pedido quando a conta muda no meio do caminho
+	loyaltyEarnAttempts = 3
+	// a varredura pontua pedidos concluídos que ficaram sem lançamento de pontos
+	loyaltyReconcileInterval = 15 * time.Minute
+	loyaltyReconcileWindow   = 7 * 24 * time.Hour
+	// pedidos mais novos que isso ainda estão com o consumidor da outbox
+	loyaltyReconcileGrace = 5 * time.Minute
+	loyaltyReconcileBatch = 200
+)
+
+type (
+	LoyaltyProgramPayload struct {
+		Name            string                       `json:"name"`
+		Mode            loyaltymode.LoyaltyMode      `json:"mode"`
+		EarnRules       []aggregates.LoyaltyEarnRule `json:"earn_rules"`
+		RewardThreshold int                          `json:"reward_threshold"`
+		RewardProductId string                       `json:"reward_product_id"`
+		RewardValue     float64                      `json:"reward_value"`
+		ExpirationDays  int                          `json:"expiration_days"`
+		Active          bool                         `json:"active"`
+		ExpectedVersion int                          `json:"-"`
+	}
+
+	ILoyaltyUseCase interface {
+		FindProgram(restaurantId string) (*aggregates.LoyaltyProgram, error)
+		SaveProgram(actor types.Actor, restaurantId string, payload *LoyaltyProgramPayload) (*aggregates.LoyaltyProgram, error)
+		FindAccount(restaurantId, customerId string) (*aggregates.LoyaltyAccount, error)
+		FindHistory(restaurantId, customerId string) ([]aggregates.LoyaltyEntry, error)
+		// Listen pontua os pedidos concluídos publicados no barramento; a função devolvida encerra a assinatura
+		Listen() func()
+	}
+
+	loyaltyUseCase struct {
+		loyaltyProgramRepository ports.ILoyaltyProgramRepository
+		ledger                   loyaltyLedger
+		orderRepository          ports.IOrderRepository
+		productRepository        ports.IProductRepository
+		categoryRepository       ports.ICategoryRepository
+		restaurantRepository     ports.IRestaurantRepository
+		eventBus                 ports.IEventBus
+	}
+
+	// loyaltyLedger movimenta as contas do cartão fidelidade; os pedidos também o usam
+	// para resgatar e estornar recompensas
+	loyaltyLedger struct {
+		loyaltyAccountRepository ports.ILoyaltyAccountRepository
+	}
+)
+
+func NewLoyaltyUseCase(
+	loyaltyProgramRepository ports.ILoyaltyProgramRepository,
+	loyaltyAccountRepository ports.ILoyaltyAccountRepository,
+	orderRepository ports.IOrderRepository,
+	productRepository ports.IProductRepository,
+	categoryRepository ports.ICategoryRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	eventBus ports.IEventBus,
+) ILoyaltyUseCase {
+	return &loyaltyUseCase{
+		loyaltyProgramRepository: loyaltyProgramRepository,
+		ledger:                   loyaltyLedger{loyaltyAccountRepository: loyaltyAccountRepository},
+		orderRepository:          orderRepository,
+		productRepository:        productRepository,
+		categoryRepository:       categoryRepository,
+		restaurantRepository:     restaurantRepository,
+		eventBus:                 eventBus,
+	}
+}
+
+func (u *loyaltyUseCase) FindProgram(restaurantId string) (*aggregates.LoyaltyProgram, error) {
+	program, err := u.loyaltyProgramRepository.FindByRestaurantId(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if program == nil {
+		return nil, ErrLoyaltyProgramNotFound
+	}
+
+	return program, nil
+}
+
+func (u *loyaltyUseCase) SaveProgram(actor types.Actor, restaurantId string, payload *LoyaltyProgramPayload) (*aggregates.LoyaltyProgram, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	if err := u.validate(restaurantId, payload); err != nil {
+		return nil, err
+	}
+
+	program, err := u.loyaltyProgramRepository.FindByRestaurantId(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	if program == nil {
+		program = aggregates.NewLoyaltyProgram(restaurantId, payload.Name, payload.Mode)
+		applyLoyaltyProgram(program, payload)
+
+		err = u.audit(actor, aggregates.AuditActionCreate, nil, program)
+		if err != nil {
+			return nil, err
+		}
+
+		err = u.loyaltyProgramRepository.Create(program)
+		if err != nil {
+			return nil, err
+		}
+
+		return program, nil
+	}
+
+	err = checkExpectedVersion(aggregates.LoyaltyProgramAggregateType, program.Id, program.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *program
+	applyLoyaltyProgram(program, payload)
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, program)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.loyaltyProgramRepository.Update(program)
+	if err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+func (u *loyaltyUseCase) FindAccount(restaurantId, customerId string) (*aggregates.LoyaltyAccount, error) {
+	account, _, err := u.ledger.settle(restaurantId, customerId, false, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrLoyaltyAccountNotFound
+	}
+
+	return account, nil
+}
+
+func (u *loyaltyUseCase) FindHistory(restaurantId, customerId string) ([]aggregates.LoyaltyEntry, error) {
+	account, entries, err := u.ledger.settle(restaurantId, customerId, false, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrLoyaltyAccountNotFound
+	}
+
+	return entries, nil
+}
+
+func (u *loyaltyUseCase) Listen() func() {
+	stopConsuming := u.eventBus.Consume("loyalty", func(event abstractions.DomainEvent) error {
+		if event.Name != aggregates.OrderCompletedEvent {
+			return nil
+		}
+		return u.earnWithRetry(event.AggregateId)
+	})
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(loyaltyReconcileInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := u.reconcile(time.Now()); err != nil {
+				log.Printf("⚠️ failed to reconcile loyalty points: %v", err)
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		stopConsuming()
+	}
+}
+
+// reconcile pontua os pedidos concluídos recentes de programas ativos que ainda não pontuaram;
+// a paginação por id evita que pedidos sem pontos a ganhar ocupem sempre o mesmo lote
+func (u *loyaltyUseCase) reconcile(now time.Time) error {
+	afterId := ""
+	for {
+		orderIds, err := u.ledger.loyaltyAccountRepository.FindOrdersPendingEarn(
+			now.Add(-loyaltyReconcileWindow),
+			now.Add(-loyaltyReconcileGrace),
+			afterId,
+			loyaltyReconcileBatch,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, orderId := range orderIds {
+			if err := u.earnWithRetry(orderId); err != nil {
+				log.Printf("⚠️ failed to credit loyalty points for order %s: %v", orderId, err)
+			}
+		}
+
+		if len(orderIds) < loyaltyReconcileBatch {
+			return nil
+		}
+		afterId = orderIds[len(orderIds)-1]
+	}
+}
+
+func (u *loyaltyUseCase) earnWithRetry(orderId string) error {
+	var err error
+	for attempt := 0; attempt < loyaltyEarnAttempts; attempt++ {
+		err = u.earn(orderId)
+		var conflict *ports.ConcurrencyConflictError
+		if !errors.As(err, &conflict) {
+			break
+		}
+	}
+
+	return err
+}
+
+// earn credita os pontos do pedido concluído; o mesmo pedido nunca pontua duas vezes
+func (u *loyaltyUseCase) earn(orderId string) error {
+	order, err := u.orderRepository.FindById(orderId)
+	if err != nil {
+		return err
+	}
+	if order == nil || order.Customer.Id == "" {
+		return nil
+	}
+
+	program, err := u.loyaltyProgramRepository.FindByRestaurantId(order.Restaurant.Id)
+	if err != nil {
+		return err
+	}
+	if program == nil || !program.Active {
+		return nil
+	}
+
+	categories := make(map[string]string, len(order.Items))
+	for _, item := range order.Items {
+		if _, ok := categories[item.Product.Id]; ok {
+			continue
+		}
+
+		product, err := u.productRepository.FindById(item.Product.Id)
+		if err != nil {
+			return err
+		}
+		if product != nil {
+			categories[item.Product.Id] = product.Category.Id
+		}
+	}
+
+	points := program.PointsFor(order, categories)
+	if points <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	account, entries, err := u.ledger.settle(order.Restaurant.Id, order.Customer.Id, true, now)
+	if err != nil {
+		return err
+	}
+	if aggregates.FindLoyaltyEntry(entries, aggregates.LoyaltyEntryEarn, order.Id) != nil {
+		return nil
+	}
+
+	entry := account.Earn(points, order.Id, fmt.Sprintf("Pedido %s", order.Id), program.ExpirationFor(now), now)
+	return u.ledger.loyaltyAccountRepository.AddEntries(account, entry)
+}
+
+// validate confere o payload e garante que produtos e categorias citados são do restaurante
+func (u *loyaltyUseCase) validate(restaurantId string, payload *LoyaltyProgramPayload) error {
+	payload.Name = strings.TrimSpace(payload.Name)
+
+	switch {
+	case payload.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidLoyaltyProgram)
+	case !payload.Mode.IsValid():
+		return fmt.Errorf("%w: unknown mode", ErrInvalidLoyaltyProgram)
+	case len(payload.EarnRules) == 0:
+		return fmt.Errorf("%w: at least one earn rule is required", ErrInvalidLoyaltyProgram)
+	case payload.RewardThreshold <= 0:
+		return fmt.Errorf("%w: reward threshold must be greater than zero", ErrInvalidLoyaltyProgram)
+	case payload.RewardValue < 0 || payload.ExpirationDays < 0:
+		return fmt.Errorf("%w: reward value and expiration must not be negative", ErrInvalidLoyaltyProgram)
+	case payload.RewardProductId == "" && payload.RewardValue == 0:
+		return fmt.Errorf("%w: reward needs a product or a value", ErrInvalidLoyaltyProgram)
+	}
+
+	productIds := make([]string, 0, len(payload.EarnRules)+1)
+	for _, rule := range payload.EarnRules {
+		if rule.Points <= 0 {
+			return fmt.Errorf("%w: rule points must be greater than zero", ErrInvalidLoyaltyProgram)
+		}
+		if (rule.ProductId == "") == (rule.CategoryId == "") {
+			return fmt.Errorf("%w: each rule targets either a product or a category", ErrInvalidLoyaltyProgram)
+		}
+
+		if rule.ProductId != "" {
+			productIds = append(productIds, rule.ProductId)
+			continue
+		}
+
+		category, err := u.categoryRepository.FindById(rule.CategoryId)
+		if err != nil {
+			return err
+		}
+		if category == nil || category.Restaurant.Id != restaurantId {
+			return fmt.Errorf("%w: %s", ErrCategoryNotFound, rule.CategoryId)
+		}
+	}
+
+	if payload.RewardProductId != "" {
+		productIds = append(productIds, payload.RewardProductId)
+	}
+
+	for _, productId := range productIds {
+		product, err := u.productRepository.FindById(productId)
+		if err != nil {
+			return err
+		}
+		if product == nil || product.Restaurant.Id != restaurantId {
+			return fmt.Errorf("%w: %s", ErrProductNotFound, productId)
+		}
+	}
+
+	return nil
+}
+
+func (u *loyaltyUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.LoyaltyProgram) error {
+	return recordAudit(
+		after,
+		actor,
+		after.Restaurant.Id,
+		aggregates.LoyaltyProgramAggregateType,
+		action,
+		before,
+		after,
+	)
+}
+
+func applyLoyaltyProgram(program *aggregates.LoyaltyProgram, payload *LoyaltyProgramPayload) {
+	program.Name = payload.Name
+	program.Mode = payload.Mode
+	program.EarnRules = orEmpty(payload.EarnRules)
+	program.RewardThreshold = payload.RewardThreshold
+	program.RewardProductId = payload.RewardProductId
+	program.RewardValue = payload.RewardValue
+	program.ExpirationDays = payload.ExpirationDays
+	program.Active = payload.Active
+	program.UpdatedAt = time.Now()
+}
+
+// settle carrega a conta do cliente e já lança os pontos vencidos, para o saldo refletir
+// só o que ainda vale; com create a conta é aberta quando não existe
+func (l loyaltyLedger) settle(restaurantId, customerId string, create bool, at time.Time) (*aggregates.LoyaltyAccount, []aggregates.LoyaltyEntry, error) {
+	account, err := l.loyaltyAccountRepository.FindByCustomerId(restaurantId, customerId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if account == nil {
+		if !create {
+			return nil, nil, nil
+		}
+
+		account = aggregates.NewLoyaltyAccount(restaurantId, customerId)
+		err = l.loyaltyAccountRepository.Create(account)
+		if err != nil {
+			return nil, nil, err
+		}
+		return account, make([]aggregates.LoyaltyEntry, 0), nil
+	}
+
+	entries, err := l.loyaltyAccountRepository.FindEntries(account.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if expired := account.Expire(entries, at); expired != nil {
+		err = l.loyaltyAccountRepository.AddEntries(account, expired)
+		if err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, *expired)
+	}
+
+	return account, entries, nil
+}
+
+// redeemEntry aplica a recompensa ao pedido e lança o resgate só na conta em memória; o pedido
+// grava o resgate na mesma transação da criação
+func (l loyaltyLedger) redeemEntry(order *aggregates.Order, program *aggregates.LoyaltyProgram) (*aggregates.LoyaltyAccount, *aggregates.LoyaltyEntry, error) {
+	if order.Customer.Id == "" {
+		return nil, nil, ErrLoyaltyRewardRequiresCustomer
+	}
+
+	now := time.Now()
+	account, _, err := l.settle(order.Restaurant.Id, order.Customer.Id, false, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if account == nil || !account.CanRedeem(program.RewardThreshold) {
+		return nil, nil, ErrInsufficientLoyaltyPoints
+	}
+
+	if order.ApplyLoyaltyReward(program) == 0 {
+		return nil, nil, ErrLoyaltyRewardNotApplicable
+	}
+
+	entry := account.Redeem(program.RewardThreshold, order.Id, fmt.Sprintf("Recompensa no pedido %s", order.Id), now)
+	return account, entry, nil
+}
+
+// refundEntry lança o estorno só na conta em memória, para quem grava junto com outra mudança;
+// entry nil quando não há o que estornar
+func (l loyaltyLedger) refundEntry(order *aggregates.Order) (*aggregates.LoyaltyAccount, *aggregates.LoyaltyEntry, error) {
+	if order.Customer.Id == "" {
+		return nil, nil, nil
+	}
+
+	now := time.Now()
+	account, entries, err := l.settle(order.Restaurant.Id, order.Customer.Id, false, now)
+	if err != nil || account == nil {
+		return nil, nil, err
+	}
+
+	redeemed := aggregates.FindLoyaltyEntry(entries, aggregates.LoyaltyEntryRedeem, order.Id)
+	if redeemed == nil || aggregates.FindLoyaltyEntry(entries, aggregates.LoyaltyEntryRefund, order.Id) != nil {
+		return nil, nil, nil
+	}
+
+	entry := account.Refund(redeemed.Points, order.Id, fmt.Sprintf("Estorno do pedido %s", order.Id), now)
+	return account, entry, nil
+}
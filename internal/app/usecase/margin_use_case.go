@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"sort"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+type (
+	IMarginUseCase interface {
+		ProductMargins(restaurantId string, payload ReportPeriodPayload) ([]dtos.ProductMarginDto, error)
+		CategoryMargins(restaurantId string, payload ReportPeriodPayload) ([]dtos.CategoryMarginDto, error)
+		// BelowCostAlerts lista os produtos vendidos abaixo do custo, pelo preço atual ou no período
+		BelowCostAlerts(restaurantId string, payload ReportPeriodPayload) ([]dtos.ProductMarginDto, error)
+		// ProductMargin calcula a margem do produto com as vendas dos últimos 30 dias
+		ProductMargin(product *aggregates.Product) (*aggregates.ProductMargin, error)
+	}
+
+	marginUseCase struct {
+		productRepository    ports.IProductRepository
+		categoryRepository   ports.ICategoryRepository
+		recipeRepository     ports.IRecipeRepository
+		stockItemRepository  ports.IStockItemRepository
+		reportRepository     ports.IReportRepository
+		restaurantRepository ports.IRestaurantRepository
+	}
+)
+
+func NewMarginUseCase(
+	productRepository ports.IProductRepository,
+	categoryRepository ports.ICategoryRepository,
+	recipeRepository ports.IRecipeRepository,
+	stockItemRepository ports.IStockItemRepository,
+	reportRepository ports.IReportRepository,
+	restaurantRepository ports.IRestaurantRepository,
+) IMarginUseCase {
+	return &marginUseCase{
+		productRepository:    productRepository,
+		categoryRepository:   categoryRepository,
+		recipeRepository:     recipeRepository,
+		stockItemRepository:  stockItemRepository,
+		reportRepository:     reportRepository,
+		restaurantRepository: restaurantRepository,
+	}
+}
+
+func (u *marginUseCase) ProductMargins(restaurantId string, payload ReportPeriodPayload) ([]dtos.ProductMarginDto, error) {
+	period, err := resolveReportPeriod(u.restaurantRepository, restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.margins(period)
+}
+
+func (u *marginUseCase) CategoryMargins(restaurantId string, payload ReportPeriodPayload) ([]dtos.CategoryMarginDto, error) {
+	products, err := u.ProductMargins(restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int)
+	categories := make([]dtos.CategoryMarginDto, 0)
+	for _, product := range products {
+		i, ok := index[product.CategoryId]
+		if !ok {
+			i = len(categories)
+			index[product.CategoryId] = i
+			categories = append(categories, dtos.CategoryMarginDto{
+				CategoryId:   product.CategoryId,
+				CategoryName: product.CategoryName,
+			})
+		}
+		categories[i].QuantitySold += product.QuantitySold
+		categories[i].Revenue += product.Revenue
+		categories[i].Cost += product.Cost
+	}
+
+	for i := range categories {
+		categories[i].Revenue = roundMoney(categories[i].Revenue)
+		categories[i].Cost = roundMoney(categories[i].Cost)
+		categories[i].Contribution = roundMoney(categories[i].Revenue - categories[i].Cost)
+		categories[i].ContributionPercent = percentOf(categories[i].Contribution, categories[i].Revenue)
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].Contribution > categories[j].Contribution
+	})
+
+	return categories, nil
+}
+
+func (u *marginUseCase) BelowCostAlerts(restaurantId string, payload ReportPeriodPayload) ([]dtos.ProductMarginDto, error) {
+	products, err := u.ProductMargins(restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]dtos.ProductMarginDto, 0)
+	for _, product := range products {
+		if product.Unit.BelowCost || product.Contribution < 0 {
+			alerts = append(alerts, product)
+		}
+	}
+
+	return alerts, nil
+}
+
+func (u *marginUseCase) ProductMargin(product *aggregates.Product) (*aggregates.ProductMargin, error) {
+	period, err := resolveReportPeriod(u.restaurantRepository, product.Restaurant.Id, ReportPeriodPayload{})
+	if err != nil {
+		return nil, err
+	}
+
+	margins, err := u.margins(period)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, margin := range margins {
+		if margin.ProductId == product.Id {
+			return &margin.Unit, nil
+		}
+	}
+
+	return nil, ErrProductNotFound
+}
+
+// margins valoriza cada produto pelos custos atuais: ficha técnica (ou cost_price) mais, nas marmitas,
+// as porções de cada prato que de fato saíram no período, pela ficha técnica do prato.
+// Produtos removidos do cardápio ficam fora, já que não há mais custo para comparar.
+func (u *marginUseCase) margins(period *reportPeriod) ([]dtos.ProductMarginDto, error) {
+	restaurantId := period.query.RestaurantId
+
+	products, err := u.productRepository.FindByRestaurantId(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := u.categoryRepository.FindByRestaurantId(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes, err := u.recipeRepository.FindByRestaurantId(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	stock, err := u.stockItemRepository.FindByRestaurantId(restaurantId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	sales, err := u.reportRepository.SalesByProduct(period.query)
+	if err != nil {
+		return nil, err
+	}
+
+	portions, err := u.reportRepository.SoldDishPortions(period.query)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryNames := make(map[string]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.Id] = category.Name
+	}
+
+	unitCosts := make(map[string]float64, len(stock))
+	for _, item := range stock {
+		unitCosts[item.Id] = item.UnitCost
+	}
+
+	productRecipes := make(map[string]*aggregates.Recipe)
+	dishCosts := make(map[string]float64)
+	for i := range recipes {
+		if recipes[i].ProductId != "" {
+			productRecipes[recipes[i].ProductId] = &recipes[i]
+		} else {
+			dishCosts[recipes[i].DishId] = recipes[i].Cost(unitCosts)
+		}
+	}
+
+	sold := make(map[string]ports.ProductSales, len(sales))
+	for _, row := range sales {
+		sold[row.ProductId] = row
+	}
+
+	servedCosts := make(map[string]float64)
+	for _, row := range portions {
+		servedCosts[row.ProductId] += float64(row.Portions) * dishCosts[row.DishId]
+	}
+
+	margins := make([]dtos.ProductMarginDto, 0, len(products))
+	for i := range products {
+		product := &products[i]
+		row, wasSold := sold[product.Id]
+		if !product.Active && !wasSold {
+			continue
+		}
+
+		salesPrice, err := product.SalesPriceValue()
+		if err != nil {
+			return nil, err
+		}
+		baseCost, err := product.BaseUnitCost(productRecipes[product.Id], unitCosts)
+		if err != nil {
+			return nil, err
+		}
+
+		unitCost := baseCost
+		if row.Quantity > 0 {
+			unitCost += servedCosts[product.Id] / float64(row.Quantity)
+		}
+		cost := baseCost*float64(row.Quantity) + servedCosts[product.Id]
+
+		margins = append(margins, dtos.ProductMarginDto{
+			ProductId:           product.Id,
+			ProductName:         product.Name,
+			CategoryId:          product.Category.Id,
+			CategoryName:        categoryNames[product.Category.Id],
+			Unit:                aggregates.NewProductMargin(salesPrice, unitCost),
+			QuantitySold:        row.Quantity,
+			Revenue:             roundMoney(row.Revenue),
+			Cost:                roundMoney(cost),
+			Contribution:        roundMoney(row.Revenue - cost),
+			ContributionPercent: percentOf(row.Revenue-cost, row.Revenue),
+		})
+	}
+
+	sort.SliceStable(margins, func(i, j int) bool {
+		return margins[i].Contribution > margins[j].Contribution
+	})
+
+	return margins, nil
+}
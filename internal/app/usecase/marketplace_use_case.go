@@ -0,0 +1,820 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	deliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_status"
+	marketplaceorderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/marketplace_order_status"
+	marketplacepushstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/marketplace_push_status"
+	orderchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_channel"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	paymentmethod "github.com/PedroNetto404/marmitech-backend/pkg/enums/payment_method"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidMarketplaceChannel     = errors.New("channel is not a marketplace")
+	ErrMarketplaceNotConfigured      = errors.New("marketplace is not configured")
+	ErrInvalidMarketplaceIntegration = errors.New("invalid marketplace integration")
+	ErrInvalidMarketplaceMapping     = errors.New("invalid marketplace mapping")
+	ErrMarketplaceMappingNotFound    = errors.New("marketplace mapping not found")
+	ErrMarketplaceOrderNotFound      = errors.New("marketplace order not found")
+	ErrMarketplaceOrderNotRetryable  = errors.New("only failed marketplace orders can be imported again")
+)
+
+const (
+	// o iFood pede uma busca a cada 30 segundos; é ela que mantém a loja aberta no aplicativo
+	marketplacePollInterval = 30 * time.Second
+	// envios com tentativa vencida são varridos nesse intervalo; mudanças novas acordam o despacho na hora
+	marketplaceDispatchInterval = 15 * time.Second
+	marketplaceDispatchBatch    = 50
+)
+
+type (
+	// MarketplaceIntegrationPayload liga ou atualiza a loja do restaurante no marketplace
+	MarketplaceIntegrationPayload struct {
+		MerchantId      string `json:"merchant_id"`
+		Active          *bool  `json:"active"`
+		ExpectedVersion int    `json:"-"`
+	}
+
+	MarketplaceMappingPayload struct {
+		ExternalCode    string `json:"external_code"`
+		ProductId       string `json:"product_id"`
+		ExpectedVersion int    `json:"-"`
+	}
+
+	MarketplaceOrderFilterPayload struct {
+		Status marketplaceorderstatus.MarketplaceOrderStatus
+		Limit  int
+	}
+
+	IMarketplaceUseCase interface {
+		FindIntegrations(restaurantId string) ([]aggregates.MarketplaceIntegration, error)
+		SaveIntegration(actor types.Actor, restaurantId string, channel orderchannel.OrderChannel, payload *MarketplaceIntegrationPayload) (*aggregates.MarketplaceIntegration, error)
+		FindMappings(restaurantId string, channel orderchannel.OrderChannel) ([]aggregates.MarketplaceMapping, error)
+		CreateMapping(actor types.Actor, restaurantId string, channel orderchannel.OrderChannel, payload *MarketplaceMappingPayload) (*aggregates.MarketplaceMapping, error)
+		UpdateMapping(actor types.Actor, restaurantId string, channel orderchannel.OrderChannel, id string, payload *MarketplaceMappingPayload) (*aggregates.MarketplaceMapping, error)
+		DeleteMapping(actor types.Actor, restaurantId string, channel orderchannel.OrderChannel, id string) error
+		// FindOrders lista os pedidos recebidos; com status failed são os que esperam vínculo de itens
+		FindOrders(restaurantId string, filter MarketplaceOrderFilterPayload) ([]aggregates.MarketplaceOrder, error)
+		// RetryOrder importa de novo um pedido que falhou, depois que os itens foram vinculados
+		RetryOrder(actor types.Actor, restaurantId, id string) (*aggregates.MarketplaceOrder, error)
+		// ReceiveWebhook trata os eventos que o marketplace envia por webhook, no lugar da busca periódica
+		ReceiveWebhook(channel orderchannel.OrderChannel, body []byte, signature string) error
+		// Listen busca os pedidos dos marketplaces e devolve a eles as mudanças de status; a função devolvida encerra os dois
+		Listen() func()
+	}
+
+	marketplaceUseCase struct {
+		marketplaceIntegrationRepository ports.IMarketplaceIntegrationRepository
+		marketplaceMappingRepository     ports.IMarketplaceMappingRepository
+		marketplaceOrderRepository       ports.IMarketplaceOrderRepository
+		marketplacePushRepository        ports.IMarketplacePushRepository
+		orderRepository                  ports.IOrderRepository
+		productRepository                ports.IProductRepository
+		restaurantRepository             ports.IRestaurantRepository
+		providers                        map[orderchannel.OrderChannel]ports.IMarketplaceProvider
+		eventBus                         ports.IEventBus
+		wake                             chan struct{}
+	}
+)
+
+// NewMarketplaceUseCase recebe só os marketplaces configurados; lojas dos demais não são buscadas
+func NewMarketplaceUseCase(
+	marketplaceIntegrationRepository ports.IMarketplaceIntegrationRepository,
+	marketplaceMappingRepository ports.IMarketplaceMappingRepository,
+	marketplaceOrderRepository ports.IMarketplaceOrderRepository,
+	marketplacePushRepository ports.IMarketplacePushRepository,
+	orderRepository ports.IOrderRepository,
+	productRepository ports.IProductRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	providers []ports.IMarketplaceProvider,
+	eventBus ports.IEventBus,
+) IMarketplaceUseCase {
+	byChannel := make(map[orderchannel.OrderChannel]ports.IMarketplaceProvider, len(providers))
+	for _, provider := range providers {
+		byChannel[provider.Channel()] = provider
+	}
+
+	return &marketplaceUseCase{
+		marketplaceIntegrationRepository: marketplaceIntegrationRepository,
+		marketplaceMappingRepository:     marketplaceMappingRepository,
+		marketplaceOrderRepository:       marketplaceOrderRepository,
+		marketplacePushRepository:        marketplacePushRepository,
+		orderRepository:                  orderRepository,
+		productRepository:                productRepository,
+		restaurantRepository:             restaurantRepository,
+		providers:                        byChannel,
+		eventBus:                         eventBus,
+		wake:                             make(chan struct{}, 1),
+	}
+}
+
+func (u *marketplaceUseCase) FindIntegrations(restaurantId string) ([]aggregates.MarketplaceIntegration, error) {
+	return u.marketplaceIntegrationRepository.FindByRestaurantId(restaurantId)
+}
+
+func (u *marketplaceUseCase) SaveIntegration(
+	actor types.Actor,
+	restaurantId string,
+	channel orderchannel.OrderChannel,
+	payload *MarketplaceIntegrationPayload,
+) (*aggregates.MarketplaceIntegration, error) {
+	if !channel.IsMarketplace() {
+		return nil, ErrInvalidMarketplaceChannel
+	}
+
+	merchantId := strings.TrimSpace(payload.MerchantId)
+	if merchantId == "" {
+		return nil, fmt.Errorf("%w: merchant_id is required", ErrInvalidMarketplaceIntegration)
+	}
+
+	integration, err := u.findIntegration(restaurantId, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	if integration == nil {
+		restaurant, err := u.restaurantRepository.FindById(restaurantId)
+		if err != nil {
+			return nil, err
+		}
+		if restaurant == nil {
+			return nil, ErrRestaurantNotFound
+		}
+
+		integration = aggregates.NewMarketplaceIntegration(restaurant.Id, channel, merchantId)
+		if payload.Active != nil {
+			integration.Active = *payload.Active
+		}
+
+		err = recordAudit(integration, actor, restaurantId, aggregates.MarketplaceIntegrationAggregateType, aggregates.AuditActionCreate, nil, integration)
+		if err != nil {
+			return nil, err
+		}
+
+		err = u.marketplaceIntegrationRepository.Create(integration)
+		if err != nil {
+			return nil, err
+		}
+
+		return integration, nil
+	}
+
+	err = checkExpectedVersion(aggregates.MarketplaceIntegrationAggregateType, integration.Id, integration.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *integration
+	integration.MerchantId = merchantId
+	if payload.Active != nil {
+		integration.Active = *payload.Active
+	}
+	integration.UpdatedAt = time.Now()
+
+	err = recordAudit(integration, actor, restaurantId, aggregates.MarketplaceIntegrationAggregateType, aggregates.AuditActionUpdate, &before, integration)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.marketplaceIntegrationRepository.Update(integration)
+	if err != nil {
+		return nil, err
+	}
+
+	return integration, nil
+}
+
+func (u *marketplaceUseCase) FindMappings(restaurantId string, channel orderchannel.OrderChannel) ([]aggregates.MarketplaceMapping, error) {
+	if !channel.IsMarketplace() {
+		return nil, ErrInvalidMarketplaceChannel
+	}
+
+	return u.marketplaceMappingRepository.FindByRestaurantId(restaurantId, channel)
+}
+
+func (u *marketplaceUseCase) CreateMapping(
+	actor types.Actor,
+	restaurantId string,
+	channel orderchannel.OrderChannel,
+	payload *MarketplaceMappingPayload,
+) (*aggregates.MarketplaceMapping, error) {
+	if !channel.IsMarketplace() {
+		return nil, ErrInvalidMarketplaceChannel
+	}
+
+	externalCode, product, err := u.validateMapping(restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := aggregates.NewMarketplaceMapping(restaurantId, channel, externalCode, product)
+
+	err = recordAudit(mapping, actor, restaurantId, aggregates.MarketplaceMappingAggregateType, aggregates.AuditActionCreate, nil, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.marketplaceMappingRepository.Create(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+func (u *marketplaceUseCase) UpdateMapping(
+	actor types.Actor,
+	restaurantId string,
+	channel orderchannel.OrderChannel,
+	id string,
+	payload *MarketplaceMappingPayload,
+) (*aggregates.MarketplaceMapping, error) {
+	mapping, err := u.findMapping(restaurantId, channel, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.MarketplaceMappingAggregateType, mapping.Id, mapping.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	externalCode, product, err := u.validateMapping(restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *mapping
+	mapping.ExternalCode = externalCode
+	mapping.Product = product
+	mapping.UpdatedAt = time.Now()
+
+	err = recordAudit(mapping, actor, restaurantId, aggregates.MarketplaceMappingAggregateType, aggregates.AuditActionUpdate, &before, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.marketplaceMappingRepository.Update(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+func (u *marketplaceUseCase) DeleteMapping(actor types.Actor, restaurantId string, channel orderchannel.OrderChannel, id string) error {
+	mapping, err := u.findMapping(restaurantId, channel, id)
+	if err != nil {
+		return err
+	}
+
+	err = recordAudit(mapping, actor, restaurantId, aggregates.MarketplaceMappingAggregateType, aggregates.AuditActionDelete, mapping, nil)
+	if err != nil {
+		return err
+	}
+
+	return u.marketplaceMappingRepository.Delete(mapping)
+}
+
+func (u *marketplaceUseCase) FindOrders(restaurantId string, filter MarketplaceOrderFilterPayload) ([]aggregates.MarketplaceOrder, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, fmt.Errorf("%w: status must be imported, failed or cancelled", ErrInvalidMarketplaceIntegration)
+	}
+
+	return u.marketplaceOrderRepository.FindByRestaurantId(restaurantId, filter.Status, filter.Limit)
+}
+
+func (u *marketplaceUseCase) RetryOrder(actor types.Actor, restaurantId, id string) (*aggregates.MarketplaceOrder, error) {
+	record, err := u.marketplaceOrderRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.RestaurantId != restaurantId {
+		return nil, ErrMarketplaceOrderNotFound
+	}
+	if !record.CanRetry() {
+		return nil, ErrMarketplaceOrderNotRetryable
+	}
+
+	provider, ok := u.providers[record.Channel]
+	if !ok {
+		return nil, ErrMarketplaceNotConfigured
+	}
+
+	details, err := provider.FetchOrder(record.ExternalId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.importOrder(record, details, &actor)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (u *marketplaceUseCase) ReceiveWebhook(channel orderchannel.OrderChannel, body []byte, signature string) error {
+	provider, ok := u.providers[channel]
+	if !ok {
+		return ErrMarketplaceNotConfigured
+	}
+
+	events, err := provider.ParseWebhook(body, signature)
+	if err != nil {
+		return err
+	}
+
+	handled := make([]ports.MarketplaceEvent, 0, len(events))
+	for _, event := range events {
+		if err := u.handle(provider, event); err != nil {
+			return err
+		}
+		handled = append(handled, event)
+	}
+
+	// o mesmo evento também apareceria na busca periódica
+	return provider.Acknowledge(handled)
+}
+
+func (u *marketplaceUseCase) Listen() func() {
+	stopConsuming := u.eventBus.Consume("marketplace", func(event abstractions.DomainEvent) error {
+		if event.Name != aggregates.OrderStatusChangedEvent {
+			return nil
+		}
+		return u.pushStatus(event)
+	})
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(marketplacePollInterval)
+		defer ticker.Stop()
+
+		for {
+			u.pollAll()
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(marketplaceDispatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			case <-u.wake:
+			}
+			u.dispatchDue()
+		}
+	}()
+
+	return func() {
+		close(done)
+		stopConsuming()
+	}
+}
+
+func (u *marketplaceUseCase) pollAll() {
+	for channel, provider := range u.providers {
+		if err := u.poll(provider); err != nil {
+			log.Printf("⚠️ failed to poll %s orders: %v", channel, err)
+		}
+	}
+}
+
+// poll só reconhece os eventos tratados; os que falharam voltam na próxima busca
+func (u *marketplaceUseCase) poll(provider ports.IMarketplaceProvider) error {
+	integrations, err := u.marketplaceIntegrationRepository.FindActive(provider.Channel())
+	if err != nil {
+		return err
+	}
+	if len(integrations) == 0 {
+		return nil
+	}
+
+	merchantIds := make([]string, 0, len(integrations))
+	for _, integration := range integrations {
+		merchantIds = append(merchantIds, integration.MerchantId)
+	}
+
+	events, err := provider.Poll(merchantIds)
+	if err != nil {
+		return err
+	}
+
+	handled := make([]ports.MarketplaceEvent, 0, len(events))
+	for _, event := range events {
+		if err := u.handle(provider, event); err != nil {
+			log.Printf("⚠️ failed to handle %s event %s of order %s: %v", provider.Channel(), event.Code, event.OrderId, err)
+			continue
+		}
+		handled = append(handled, event)
+	}
+
+	return provider.Acknowledge(handled)
+}
+
+func (u *marketplaceUseCase) handle(provider ports.IMarketplaceProvider, event ports.MarketplaceEvent) error {
+	switch event.Code {
+	case ports.MarketplaceOrderPlaced:
+		return u.receive(provider, event)
+	case ports.MarketplaceOrderCancelled:
+		return u.cancel(provider, event)
+	}
+	return nil
+}
+
+// receive registra o pedido antes de importar; pedido já registrado, pela busca ou pelo webhook, é ignorado
+func (u *marketplaceUseCase) receive(provider ports.IMarketplaceProvider, event ports.MarketplaceEvent) error {
+	integration, err := u.marketplaceIntegrationRepository.FindByMerchantId(provider.Channel(), event.MerchantId)
+	if err != nil {
+		return err
+	}
+	if integration == nil || !integration.Active {
+		log.Printf("⚠️ ignoring %s order %s of unknown or inactive merchant %s", provider.Channel(), event.OrderId, event.MerchantId)
+		return nil
+	}
+
+	details, err := provider.FetchOrder(event.OrderId)
+	if err != nil {
+		return err
+	}
+
+	record := aggregates.NewMarketplaceOrder(integration.RestaurantId, provider.Channel(), details.Id, details.Code)
+	reserved, err := u.marketplaceOrderRepository.Reserve(record)
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		return nil
+	}
+
+	return u.importOrder(record, details, nil)
+}
+
+// importOrder cria o pedido na fila da cozinha e o confirma no marketplace; item sem vínculo
+// deixa o registro como falho, à espera do vínculo e de uma nova importação.
+// actor só vem na retentativa manual, que é auditada junto com o registro
+func (u *marketplaceUseCase) importOrder(
+	record *aggregates.MarketplaceOrder,
+	details *ports.MarketplaceOrderDetails,
+	actor *types.Actor,
+) error {
+	before := *record
+
+	order, unmapped, err := u.buildOrder(record, details)
+	if err != nil {
+		record.Fail(err.Error(), nil)
+		return u.saveRecord(actor, &before, record)
+	}
+	if len(unmapped) > 0 {
+		record.Fail("items without a product mapping", unmapped)
+		return u.saveRecord(actor, &before, record)
+	}
+
+	err = recordAudit(order, marketplaceActor(record.Channel), order.Restaurant.Id, aggregates.OrderAggregateType, aggregates.AuditActionCreate, nil, order)
+	if err != nil {
+		return err
+	}
+
+	err = u.orderRepository.Create(order, ports.OrderRedemption{})
+	if err != nil {
+		record.Fail(err.Error(), nil)
+		if updateErr := u.saveRecord(actor, &before, record); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+
+	record.MarkImported(order.Id)
+	err = u.saveRecord(actor, &before, record)
+	if err != nil {
+		return err
+	}
+
+	publishDomainEvents(u.eventBus, order)
+
+	// sem a confirmação o marketplace cancela o pedido sozinho depois de alguns minutos
+	return u.schedulePush(order, ports.MarketplaceConfirm, "")
+}
+
+// saveRecord grava o registro do pedido, com a auditoria quando há quem tenha pedido a retentativa
+func (u *marketplaceUseCase) saveRecord(actor *types.Actor, before, record *aggregates.MarketplaceOrder) error {
+	if actor != nil {
+		err := recordAudit(record, *actor, record.RestaurantId, aggregates.MarketplaceOrderAggregateType, aggregates.AuditActionUpdate, before, record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return u.marketplaceOrderRepository.Update(record)
+}
+
+func (u *marketplaceUseCase) buildOrder(
+	record *aggregates.MarketplaceOrder,
+	details *ports.MarketplaceOrderDetails,
+) (*aggregates.Order, []string, error) {
+	if len(details.Items) == 0 {
+		return nil, nil, ErrEmptyOrder
+	}
+
+	restaurant, err := u.restaurantRepository.FindById(record.RestaurantId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if restaurant == nil {
+		return nil, nil, ErrRestaurantNotFound
+	}
+
+	codes := make([]string, 0, len(details.Items))
+	for _, item := range details.Items {
+		if item.ExternalCode != "" && !slices.Contains(codes, item.ExternalCode) {
+			codes = append(codes, item.ExternalCode)
+		}
+	}
+
+	mappings, err := u.marketplaceMappingRepository.FindByExternalCodes(record.RestaurantId, record.Channel, codes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]aggregates.OrderItem, 0, len(details.Items))
+	unmapped := make([]string, 0)
+	for _, item := range details.Items {
+		mapping, ok := mappings[item.ExternalCode]
+		if !ok {
+			// item sem código externo no cardápio do marketplace aparece pelo nome
+			missing := item.ExternalCode
+			if missing == "" {
+				missing = item.Name
+			}
+			if !slices.Contains(unmapped, missing) {
+				unmapped = append(unmapped, missing)
+			}
+			continue
+		}
+
+		items = append(items, aggregates.NewOrderItem(mapping.Product, roundMoney(item.UnitPrice), item.Quantity, item.Observation, nil))
+	}
+	if len(unmapped) > 0 {
+		return nil, unmapped, nil
+	}
+
+	var delivery *aggregates.OrderDelivery
+	if details.Delivery != nil {
+		delivery = &aggregates.OrderDelivery{
+			Id:                 uuid.NewString(),
+			Address:            *details.Delivery,
+			Fee:                roundMoney(details.DeliveryFee),
+			AverageTimeMinutes: restaurant.Settings.Delivery.AverageTimeMinutes,
+			Status:             deliverystatus.PENDING,
+		}
+	}
+
+	order := aggregates.NewOrderFromMarketplace(
+		restaurant.Id,
+		record.Channel,
+		details.Id,
+		details.Code,
+		items,
+		delivery,
+		details.Discount,
+		details.Observation,
+	)
+
+	// o que foi pago no aplicativo é repassado pelo marketplace; o restante é cobrado na entrega
+	if prepaid := min(roundMoney(details.Prepaid), order.Balance()); prepaid > 0 {
+		order.RegisterPayment(string(paymentmethod.MARKETPLACE), prepaid, "", "", details.CreatedAt)
+	}
+
+	return order, nil, nil
+}
+
+// cancel atende o cancelamento feito no marketplace; o registro é marcado antes,
+// para que o cancelamento não seja devolvido ao marketplace
+func (u *marketplaceUseCase) cancel(provider ports.IMarketplaceProvider, event ports.MarketplaceEvent) error {
+	record, err := u.marketplaceOrderRepository.FindByExternalId(provider.Channel(), event.OrderId)
+	if err != nil {
+		return err
+	}
+	if record == nil || !record.MarkCancelled() {
+		return nil
+	}
+
+	err = u.marketplaceOrderRepository.Update(record)
+	if err != nil {
+		return err
+	}
+	if record.OrderId == "" {
+		return nil
+	}
+
+	order, err := u.orderRepository.FindById(record.OrderId)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return nil
+	}
+
+	before := *order
+	if !order.Cancel("cancelled on " + string(record.Channel)) {
+		return nil
+	}
+
+	err = recordAudit(order, marketplaceActor(record.Channel), order.Restaurant.Id, aggregates.OrderAggregateType, aggregates.AuditActionUpdate, &before, order)
+	if err != nil {
+		return err
+	}
+
+	err = u.orderRepository.Update(order)
+	if err != nil {
+		return err
+	}
+
+	publishDomainEvents(u.eventBus, order)
+	return nil
+}
+
+// pushStatus devolve ao marketplace o andamento do pedido na cozinha
+func (u *marketplaceUseCase) pushStatus(event abstractions.DomainEvent) error {
+	order, err := u.orderRepository.FindById(event.AggregateId)
+	if err != nil {
+		return err
+	}
+	if order == nil || !order.Channel.IsMarketplace() {
+		return nil
+	}
+
+	if _, ok := u.providers[order.Channel]; !ok {
+		return nil
+	}
+
+	record, err := u.marketplaceOrderRepository.FindByOrderId(order.Id)
+	if err != nil {
+		return err
+	}
+	// cancelado pelo próprio marketplace, não há o que devolver
+	if record == nil || record.Status == marketplaceorderstatus.CANCELLED {
+		return nil
+	}
+
+	var action ports.MarketplaceAction
+	switch order.Status {
+	case orderstatus.PREPARING:
+		action = ports.MarketplaceStartPreparation
+	case orderstatus.READY:
+		action = ports.MarketplaceReadyForPickup
+		if order.Delivery != nil {
+			action = ports.MarketplaceDispatch
+		}
+	case orderstatus.CANCELLED:
+		action = ports.MarketplaceCancel
+		record.MarkCancelled()
+		if err := u.marketplaceOrderRepository.Update(record); err != nil {
+			return err
+		}
+	default:
+		// a conclusão é registrada pelo próprio marketplace quando o pedido é entregue ou retirado
+		return nil
+	}
+
+	return u.schedulePush(order, action, order.CancelReason)
+}
+
+// schedulePush grava o envio para o despacho; um erro aqui faz o evento voltar da outbox
+func (u *marketplaceUseCase) schedulePush(order *aggregates.Order, action ports.MarketplaceAction, reason string) error {
+	push := aggregates.NewMarketplacePush(order, string(action), reason)
+	if err := u.marketplacePushRepository.Create(push); err != nil {
+		return err
+	}
+
+	u.wakeDispatcher()
+	return nil
+}
+
+// dispatchDue envia os envios vencidos um a um, lote a lote, até esvaziar a fila; o repositório
+// só devolve o próximo envio de cada pedido, então a ordem das ações é mantida
+func (u *marketplaceUseCase) dispatchDue() {
+	for {
+		pushes, err := u.marketplacePushRepository.FindDue(time.Now(), marketplaceDispatchBatch)
+		if err != nil {
+			log.Printf("⚠️ failed to load pending marketplace pushes: %v", err)
+			return
+		}
+
+		for i := range pushes {
+			if err := u.send(&pushes[i]); err != nil {
+				log.Printf("⚠️ failed to dispatch marketplace push %s: %v", pushes[i].Id, err)
+			}
+		}
+
+		if len(pushes) < marketplaceDispatchBatch {
+			return
+		}
+	}
+}
+
+// send registra a tentativa; recusa do marketplace não é repetida
+func (u *marketplaceUseCase) send(push *aggregates.MarketplacePush) error {
+	provider, ok := u.providers[push.Channel]
+	if !ok {
+		push.RecordAttempt(ErrMarketplaceNotConfigured, true, time.Now())
+		return u.recordPush(push)
+	}
+
+	err := provider.SendAction(push.ExternalId, ports.MarketplaceAction(push.Action), push.Reason)
+	push.RecordAttempt(err, errors.Is(err, ports.ErrMarketplaceRejected), time.Now())
+
+	if push.Status == marketplacepushstatus.FAILED {
+		log.Printf("⚠️ failed to send %s of %s order %s after %d attempts: %s", push.Action, push.Channel, push.ExternalId, push.Attempts, push.LastError)
+	}
+
+	return u.recordPush(push)
+}
+
+// recordPush grava o resultado; conflito de versão quer dizer que outra instância já tratou o envio
+func (u *marketplaceUseCase) recordPush(push *aggregates.MarketplacePush) error {
+	err := u.marketplacePushRepository.Update(push)
+
+	var conflict *ports.ConcurrencyConflictError
+	if errors.As(err, &conflict) {
+		return nil
+	}
+	return err
+}
+
+func (u *marketplaceUseCase) wakeDispatcher() {
+	select {
+	case u.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (u *marketplaceUseCase) findIntegration(restaurantId string, channel orderchannel.OrderChannel) (*aggregates.MarketplaceIntegration, error) {
+	integrations, err := u.marketplaceIntegrationRepository.FindByRestaurantId(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range integrations {
+		if integrations[i].Channel == channel {
+			return &integrations[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (u *marketplaceUseCase) findMapping(restaurantId string, channel orderchannel.OrderChannel, id string) (*aggregates.MarketplaceMapping, error) {
+	mapping, err := u.marketplaceMappingRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if mapping == nil || mapping.RestaurantId != restaurantId || mapping.Channel != channel {
+		return nil, ErrMarketplaceMappingNotFound
+	}
+
+	return mapping, nil
+}
+
+func (u *marketplaceUseCase) validateMapping(restaurantId string, payload *MarketplaceMappingPayload) (string, aggregates.PartialProduct, error) {
+	externalCode := strings.TrimSpace(payload.ExternalCode)
+	if externalCode == "" {
+		return "", aggregates.PartialProduct{}, fmt.Errorf("%w: external_code is required", ErrInvalidMarketplaceMapping)
+	}
+
+	product, err := u.productRepository.FindById(payload.ProductId)
+	if err != nil {
+		return "", aggregates.PartialProduct{}, err
+	}
+	if product == nil || product.Restaurant.Id != restaurantId {
+		return "", aggregates.PartialProduct{}, ErrProductNotFound
+	}
+
+	return externalCode, aggregates.PartialProduct{Id: product.Id, Name: product.Name}, nil
+}
+
+// marketplaceActor assina na auditoria as alterações feitas pela integração, sem usuário por trás
+func marketplaceActor(channel orderchannel.OrderChannel) types.Actor {
+	return types.Actor{Email: string(channel)}
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrMenuNotFound                = errors.New("menu not found")
+	ErrMenuItemNotFound            = errors.New("menu item not found")
+	ErrInvalidMenuItemAvailability = errors.New("invalid menu item availability")
+)
+
+type (
+	// MenuItemAvailabilityPayload substitui a disponibilidade do item: available_portions nulo tira o controle de quantidade
+	MenuItemAvailabilityPayload struct {
+		Enabled           bool `json:"enabled"`
+		AvailablePortions *int `json:"available_portions"`
+	}
+
+	IMenuUseCase interface {
+		FindByOfferDate(restaurantId string, offerDate time.Time) (*aggregates.Menu, error)
+		UpdateItemAvailability(actor types.Actor, restaurantId, menuItemId string, payload *MenuItemAvailabilityPayload) (*aggregates.MenuItem, error)
+	}
+
+	menuUseCase struct {
+		menuRepository ports.IMenuRepository
+	}
+)
+
+func NewMenuUseCase(
+	menuRepository ports.IMenuRepository,
+) IMenuUseCase {
+	return &menuUseCase{
+		menuRepository: menuRepository,
+	}
+}
+
+func (u *menuUseCase) FindByOfferDate(restaurantId string, offerDate time.Time) (*aggregates.Menu, error) {
+	menu, err := u.menuRepository.FindByOfferDate(restaurantId, offerDate)
+	if err != nil {
+		return nil, err
+	}
+	if menu == nil {
+		return nil, ErrMenuNotFound
+	}
+
+	return menu, nil
+}
+
+func (u *menuUseCase) UpdateItemAvailability(
+	actor types.Actor,
+	restaurantId string,
+	menuItemId string,
+	payload *MenuItemAvailabilityPayload,
+) (*aggregates.MenuItem, error) {
+	if payload.AvailablePortions != nil && *payload.AvailablePortions < 0 {
+		return nil, fmt.Errorf("%w: available_portions must not be negative", ErrInvalidMenuItemAvailability)
+	}
+
+	menu, err := u.menuRepository.FindByItemId(menuItemId)
+	if err != nil {
+		return nil, err
+	}
+	if menu == nil || menu.Restaurant.Id != restaurantId {
+		return nil, ErrMenuItemNotFound
+	}
+
+	menuItem := menu.FindItem(menuItemId)
+	if menuItem == nil {
+		return nil, ErrMenuItemNotFound
+	}
+
+	before := *menuItem
+	menuItem.Enabled = payload.Enabled
+	menuItem.AvailablePortions = payload.AvailablePortions
+
+	err = recordAudit(
+		menu,
+		actor,
+		restaurantId,
+		aggregates.MenuAggregateType,
+		aggregates.AuditActionUpdate,
+		&before,
+		menuItem,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.menuRepository.UpdateItem(menu, menuItem)
+	if err != nil {
+		return nil, err
+	}
+
+	return menuItem, nil
+}
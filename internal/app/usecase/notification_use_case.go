@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	notificationchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_channel"
+	notificationstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_status"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+// o canal foi configurado quando a notificação foi reservada e deixou de ser depois
+var ErrNotificationChannelNotConfigured = errors.New("notification channel is not configured")
+
+const (
+	// notificações com tentativa vencida são varridas nesse intervalo; reservas novas acordam o despacho na hora
+	notificationDispatchInterval = 15 * time.Second
+	notificationDispatchBatch    = 50
+)
+
+type (
+	// NotificationPreferencesPayload substitui as preferências inteiras; false recusa o canal
+	NotificationPreferencesPayload struct {
+		Email           bool `json:"email"`
+		WhatsApp        bool `json:"whatsapp"`
+		Sms             bool `json:"sms"`
+		ExpectedVersion int  `json:"-"`
+	}
+
+	INotificationUseCase interface {
+		FindPreferences(customerId string) (*aggregates.NotificationPreferences, error)
+		SavePreferences(actor types.Actor, restaurantId, customerId string, payload *NotificationPreferencesPayload) (*aggregates.NotificationPreferences, error)
+		FindByOrderId(restaurantId, orderId string) ([]aggregates.Notification, error)
+		// Listen reserva as notificações das mudanças dos pedidos e despacha as pendentes; a função devolvida encerra os dois
+		Listen() func()
+	}
+
+	notificationUseCase struct {
+		notificationPreferencesRepository ports.INotificationPreferencesRepository
+		notificationRepository            ports.INotificationRepository
+		orderRepository                   ports.IOrderRepository
+		customerRepository                ports.ICustomerRepository
+		restaurantRepository              ports.IRestaurantRepository
+		renderer                          ports.INotificationRenderer
+		notifiers                         map[notificationchannel.NotificationChannel]ports.INotifier
+		eventBus                          ports.IEventBus
+		wake                              chan struct{}
+	}
+)
+
+// NewNotificationUseCase recebe só os canais configurados; mensagens para os demais não são enviadas
+func NewNotificationUseCase(
+	notificationPreferencesRepository ports.INotificationPreferencesRepository,
+	notificationRepository ports.INotificationRepository,
+	orderRepository ports.IOrderRepository,
+	customerRepository ports.ICustomerRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	renderer ports.INotificationRenderer,
+	notifiers []ports.INotifier,
+	eventBus ports.IEventBus,
+) INotificationUseCase {
+	byChannel := make(map[notificationchannel.NotificationChannel]ports.INotifier, len(notifiers))
+	for _, notifier := range notifiers {
+		byChannel[notifier.Channel()] = notifier
+	}
+
+	return &notificationUseCase{
+		notificationPreferencesRepository: notificationPreferencesRepository,
+		notificationRepository:            notificationRepository,
+		orderRepository:                   orderRepository,
+		customerRepository:                customerRepository,
+		restaurantRepository:              restaurantRepository,
+		renderer:                          renderer,
+		notifiers:                         byChannel,
+		eventBus:                          eventBus,
+		wake:                              make(chan struct{}, 1),
+	}
+}
+
+// FindPreferences devolve os padrões, com todos os canais liberados, quando o cliente nunca os alterou
+func (u *notificationUseCase) FindPreferences(customerId string) (*aggregates.NotificationPreferences, error) {
+	customer, err := u.customerRepository.FindById(customerId)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, ErrCustomerNotFound
+	}
+
+	return u.preferences(customer.Id)
+}
+
+func (u *notificationUseCase) SavePreferences(
+	actor types.Actor,
+	restaurantId string,
+	customerId string,
+	payload *NotificationPreferencesPayload,
+) (*aggregates.NotificationPreferences, error) {
+	customer, err := u.customerRepository.FindById(customerId)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, ErrCustomerNotFound
+	}
+
+	preferences, err := u.notificationPreferencesRepository.FindByCustomerId(customer.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if preferences == nil {
+		preferences = aggregates.NewNotificationPreferences(customer.Id)
+		applyNotificationPreferences(preferences, payload)
+
+		err = recordAudit(preferences, actor, restaurantId, aggregates.NotificationPreferencesAggregateType, aggregates.AuditActionCreate, nil, preferences)
+		if err != nil {
+			return nil, err
+		}
+
+		err = u.notificationPreferencesRepository.Create(preferences)
+		if err != nil {
+			return nil, err
+		}
+
+		return preferences, nil
+	}
+
+	err = checkExpectedVersion(aggregates.NotificationPreferencesAggregateType, preferences.Id, preferences.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *preferences
+	applyNotificationPreferences(preferences, payload)
+
+	err = recordAudit(preferences, actor, restaurantId, aggregates.NotificationPreferencesAggregateType, aggregates.AuditActionUpdate, &before, preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.notificationPreferencesRepository.Update(preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+func (u *notificationUseCase) FindByOrderId(restaurantId, orderId string) ([]aggregates.Notification, error) {
+	order, err := u.orderRepository.FindById(orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.Restaurant.Id != restaurantId {
+		return nil, ErrOrderNotFound
+	}
+
+	return u.notificationRepository.FindByOrderId(order.Id)
+}
+
+// Listen consome a outbox: um erro ao reservar as notificações faz o evento voltar,
+// e a reserva por pedido, modelo e canal impede o envio em dobro
+func (u *notificationUseCase) Listen() func() {
+	stopConsuming := u.eventBus.Consume("notifications", u.notify)
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(notificationDispatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			case <-u.wake:
+			}
+			u.dispatchDue()
+		}
+	}()
+
+	return func() {
+		close(done)
+		stopConsuming()
+	}
+}
+
+// notify reserva uma notificação por canal com o texto já montado; o envio fica com o despacho
+func (u *notificationUseCase) notify(event abstractions.DomainEvent) error {
+	switch event.Name {
+	case aggregates.OrderCreatedEvent,
+		aggregates.OrderUpdatedEvent,
+		aggregates.OrderCompletedEvent,
+		aggregates.OrderCancelledEvent,
+		aggregates.OrderPaidEvent:
+	default:
+		return nil
+	}
+
+	order, err := u.orderRepository.FindById(event.AggregateId)
+	if err != nil {
+		return err
+	}
+	// pedido de balcão sem cliente identificado não tem para quem avisar
+	if order == nil || order.Customer.Id == "" {
+		return nil
+	}
+
+	template, ok := aggregates.NotificationTemplateFor(event.Name, order)
+	if !ok {
+		return nil
+	}
+
+	customer, err := u.customerRepository.FindById(order.Customer.Id)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return nil
+	}
+
+	restaurant, err := u.restaurantRepository.FindById(order.Restaurant.Id)
+	if err != nil {
+		return err
+	}
+	if restaurant == nil {
+		return nil
+	}
+
+	preferences, err := u.preferences(customer.Id)
+	if err != nil {
+		return err
+	}
+
+	recipients := u.recipients(customer, preferences)
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	subject, body, err := u.renderer.Render(template, ports.NotificationData{
+		Restaurant: restaurant,
+		Order:      order,
+		Customer:   customer,
+	})
+	if err != nil {
+		return err
+	}
+
+	for channel, recipient := range recipients {
+		notification := aggregates.NewNotification(order, template, channel, recipient, subject, body)
+		if _, err := u.notificationRepository.Reserve(notification); err != nil {
+			return err
+		}
+	}
+
+	u.wakeDispatcher()
+	return nil
+}
+
+// recipients escolhe os canais do cliente: e-mail e um canal de telefone, o WhatsApp
+// quando disponível e o SMS no lugar dele, para não repetir a mesma mensagem no celular
+func (u *notificationUseCase) recipients(
+	customer *aggregates.Customer,
+	preferences *aggregates.NotificationPreferences,
+) map[notificationchannel.NotificationChannel]string {
+	recipients := make(map[notificationchannel.NotificationChannel]string, 2)
+
+	if customer.ContactEmail != "" && u.available(notificationchannel.EMAIL, preferences) {
+		recipients[notificationchannel.EMAIL] = customer.ContactEmail
+	}
+
+	if customer.ContactPhone != "" {
+		for _, channel := range []notificationchannel.NotificationChannel{notificationchannel.WHATSAPP, notificationchannel.SMS} {
+			if u.available(channel, preferences) {
+				recipients[channel] = customer.ContactPhone
+				break
+			}
+		}
+	}
+
+	return recipients
+}
+
+func (u *notificationUseCase) available(channel notificationchannel.NotificationChannel, preferences *aggregates.NotificationPreferences) bool {
+	_, configured := u.notifiers[channel]
+	return configured && preferences.Allows(channel)
+}
+
+// dispatchDue envia as notificações vencidas em paralelo, lote a lote, até esvaziar a fila
+func (u *notificationUseCase) dispatchDue() {
+	for {
+		notifications, err := u.notificationRepository.FindDue(time.Now(), notificationDispatchBatch)
+		if err != nil {
+			log.Printf("⚠️ failed to load pending notifications: %v", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := range notifications {
+			wg.Add(1)
+			go func(notification *aggregates.Notification) {
+				defer wg.Done()
+				if err := u.deliver(notification); err != nil {
+					log.Printf("⚠️ failed to dispatch notification %s: %v", notification.Id, err)
+				}
+			}(&notifications[i])
+		}
+		wg.Wait()
+
+		if len(notifications) < notificationDispatchBatch {
+			return
+		}
+	}
+}
+
+// deliver registra a tentativa; recusa do provedor e canal que deixou de ser configurado não são reenviados
+func (u *notificationUseCase) deliver(notification *aggregates.Notification) error {
+	notifier, ok := u.notifiers[notification.Channel]
+	if !ok {
+		notification.RecordAttempt(ErrNotificationChannelNotConfigured, true, time.Now())
+		return u.record(notification)
+	}
+
+	err := notifier.Send(ports.NotificationMessage{
+		Channel:   notification.Channel,
+		Recipient: notification.Recipient,
+		Subject:   notification.Subject,
+		Body:      notification.Body,
+	})
+	notification.RecordAttempt(err, errors.Is(err, ports.ErrNotificationRejected), time.Now())
+
+	if notification.Status == notificationstatus.FAILED {
+		log.Printf("⚠️ failed to send %s notification %s of order %s after %d attempts: %s", notification.Channel, notification.Template, notification.OrderId, notification.Attempts, notification.LastError)
+	}
+
+	return u.record(notification)
+}
+
+// record grava o resultado; conflito de versão quer dizer que outra instância já tratou a notificação
+func (u *notificationUseCase) record(notification *aggregates.Notification) error {
+	err := u.notificationRepository.Update(notification)
+
+	var conflict *ports.ConcurrencyConflictError
+	if errors.As(err, &conflict) {
+		return nil
+	}
+	return err
+}
+
+func (u *notificationUseCase) wakeDispatcher() {
+	select {
+	case u.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (u *notificationUseCase) preferences(customerId string) (*aggregates.NotificationPreferences, error) {
+	preferences, err := u.notificationPreferencesRepository.FindByCustomerId(customerId)
+	if err != nil {
+		return nil, err
+	}
+	if preferences == nil {
+		return aggregates.NewNotificationPreferences(customerId), nil
+	}
+
+	return preferences, nil
+}
+
+func applyNotificationPreferences(preferences *aggregates.NotificationPreferences, payload *NotificationPreferencesPayload) {
+	preferences.Email = payload.Email
+	preferences.WhatsApp = payload.WhatsApp
+	preferences.Sms = payload.Sms
+	preferences.UpdatedAt = time.Now()
+}
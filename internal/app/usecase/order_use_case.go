@@ -0,0 +1,745 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	deliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_status"
+	dishtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/dish_type"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	paymentmethod "github.com/PedroNetto404/marmitech-backend/pkg/enums/payment_method"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrEmptyOrder             = errors.New("order must have at least one item")
+	ErrInvalidOrderItem       = errors.New("order item quantity must be greater than zero")
+	ErrProductUnavailable     = errors.New("product is not available")
+	ErrMenuNotAvailable       = errors.New("there is no menu for today")
+	ErrInvalidLunchbox        = errors.New("lunchbox composition does not match the product or today's menu")
+	ErrDeliveryDisabled       = errors.New("delivery is disabled for this restaurant")
+	ErrInvalidOrderTransition = errors.New("order cannot move to the requested status")
+	ErrInvalidPayment         = errors.New("payment must be positive and not exceed the order balance")
+)
+
+// promotionRejection é o motivo pelo qual o pedido não pode usar uma promoção
+type promotionRejection int
+
+const (
+	promotionEligible promotionRejection = iota
+	promotionUnavailable
+	promotionUsageLimitReached
+	promotionRequiresCustomer
+	promotionFirstOrderOnly
+)
+
+type (
+	LunchboxSelectionPayload struct {
+		MenuItemId   string `json:"menu_item_id"`
+		Quantity     int    `json:"quantity"`
+		IsAdditional bool   `json:"is_additional"`
+	}
+
+	LunchboxPayload struct {
+		WantsFlatware bool                       `json:"wants_flatware"`
+		Selections    []LunchboxSelectionPayload `json:"selections"`
+	}
+
+	OrderItemPayload struct {
+		ProductId   string           `json:"product_id"`
+		Quantity    int              `json:"quantity"`
+		Observation string           `json:"observation"`
+		Lunchbox    *LunchboxPayload `json:"lunchbox"`
+	}
+
+	OrderDeliveryPayload struct {
+		Address types.Address `json:"address"`
+	}
+
+	OrderPayload struct {
+		Restaurant  aggregates.PartialRestaurant `json:"restaurant"`
+		CustomerId  string                       `json:"customer_id"`
+		Items       []OrderItemPayload           `json:"items"`
+		Delivery    *OrderDeliveryPayload        `json:"delivery"`
+		Observation string                       `json:"observation"`
+		CouponCode  string                       `json:"coupon_code"`
+		// troca pontos do cartão fidelidade do cliente pela recompensa do programa
+		RedeemLoyaltyReward bool `json:"redeem_loyalty_reward"`
+	}
+
+	// OrderPaymentPayload é um recebimento no balcão, lançado no caixa aberto do operador;
+	// o pós-pago vai para o fiado do cliente do pedido
+	OrderPaymentPayload struct {
+		PaymentMethod   paymentmethod.PaymentMethod `json:"payment_method"`
+		Amount          float64                     `json:"amount"`
+		PixKey          string                      `json:"pix_key"`
+		ExpectedVersion int                         `json:"-"`
+	}
+
+	IOrderUseCase interface {
+		Find(args types.FindArgs) (*types.PagedSlice[aggregates.Order], error)
+		FindById(id string) (*aggregates.Order, error)
+		Create(actor types.Actor, payload *OrderPayload) (*aggregates.Order, error)
+		Complete(actor types.Actor, id string, expectedVersion int) (*aggregates.Order, error)
+		Cancel(actor types.Actor, id string, reason string, expectedVersion int) (*aggregates.Order, error)
+		AddPayment(actor types.Actor, id string, payload *OrderPaymentPayload) (*aggregates.Order, error)
+	}
+
+	orderUseCase struct {
+		orderRepository          ports.IOrderRepository
+		productRepository        ports.IProductRepository
+		customerRepository       ports.ICustomerRepository
+		customerTabRepository    ports.ICustomerTabRepository
+		restaurantRepository     ports.IRestaurantRepository
+		menuRepository           ports.IMenuRepository
+		promotionRepository      ports.IPromotionRepository
+		loyaltyProgramRepository ports.ILoyaltyProgramRepository
+		loyaltyLedger            loyaltyLedger
+		cashSessionRepository    ports.ICashSessionRepository
+		addressLookup            ports.IAddressLookup
+		geocoder                 ports.IGeocoder
+		deliveryZoneRepository   ports.IDeliveryZoneRepository
+		eventPublisher           ports.IEventPublisher
+	}
+)
+
+func NewOrderUseCase(
+	orderRepository ports.IOrderRepository,
+	productRepository ports.IProductRepository,
+	customerRepository ports.ICustomerRepository,
+	customerTabRepository ports.ICustomerTabRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	menuRepository ports.IMenuRepository,
+	promotionRepository ports.IPromotionRepository,
+	loyaltyProgramRepository ports.ILoyaltyProgramRepository,
+	loyaltyAccountRepository ports.ILoyaltyAccountRepository,
+	cashSessionRepository ports.ICashSessionRepository,
+	addressLookup ports.IAddressLookup,
+	geocoder ports.IGeocoder,
+	deliveryZoneRepository ports.IDeliveryZoneRepository,
+	eventPublisher ports.IEventPublisher,
+) IOrderUseCase {
+	return &orderUseCase{
+		orderRepository:          orderRepository,
+		productRepository:        productRepository,
+		customerRepository:       customerRepository,
+		customerTabRepository:    customerTabRepository,
+		restaurantRepository:     restaurantRepository,
+		menuRepository:           menuRepository,
+		promotionRepository:      promotionRepository,
+		loyaltyProgramRepository: loyaltyProgramRepository,
+		loyaltyLedger:            loyaltyLedger{loyaltyAccountRepository: loyaltyAccountRepository},
+		cashSessionRepository:    cashSessionRepository,
+		addressLookup:            addressLookup,
+		geocoder:                 geocoder,
+		deliveryZoneRepository:   deliveryZoneRepository,
+		eventPublisher:           eventPublisher,
+	}
+}
+
+func (u *orderUseCase) Find(args types.FindArgs) (*types.PagedSlice[aggregates.Order], error) {
+	orders, err := u.orderRepository.Find(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (u *orderUseCase) FindById(id string) (*aggregates.Order, error) {
+	order, err := u.orderRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+func (u *orderUseCase) Create(actor types.Actor, payload *OrderPayload) (*aggregates.Order, error) {
+	if len(payload.Items) == 0 {
+		return nil, ErrEmptyOrder
+	}
+
+	restaurant, err := u.restaurantRepository.FindById(payload.Restaurant.Id)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	var customer aggregates.PartialCustomer
+	if payload.CustomerId != "" {
+		found, err := u.customerRepository.FindById(payload.CustomerId)
+		if err != nil {
+			return nil, err
+		}
+		if found == nil {
+			return nil, ErrCustomerNotFound
+		}
+		customer = aggregates.PartialCustomer{
+			Id:        found.Id,
+			FirstName: found.FirstName,
+			LastName:  found.LastName,
+			Email:     found.ContactEmail,
+		}
+	}
+
+	var delivery *aggregates.OrderDelivery
+	var quote aggregates.DeliveryQuote
+	if payload.Delivery != nil {
+		if !restaurant.Settings.Delivery.Enabled {
+			return nil, ErrDeliveryDisabled
+		}
+		err = normalizeAddress(u.addressLookup, &payload.Delivery.Address)
+		if err != nil {
+			return nil, err
+		}
+		geocodeAddress(u.geocoder, &payload.Delivery.Address, nil)
+
+		quote, err = quoteDelivery(u.deliveryZoneRepository, restaurant, payload.Delivery.Address)
+		if err != nil {
+			return nil, err
+		}
+		code, err := newDeliveryCode()
+		if err != nil {
+			return nil, err
+		}
+		delivery = &aggregates.OrderDelivery{
+			Id:                 uuid.NewString(),
+			Address:            payload.Delivery.Address,
+			Fee:                quote.Fee,
+			Distance:           quote.DistanceKm,
+			AverageTimeMinutes: quote.EstimatedTimeMinutes,
+			Status:             deliverystatus.PENDING,
+			Code:               code,
+		}
+	}
+
+	items, err := u.buildItems(restaurant.Id, payload.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	order := aggregates.NewOrder(restaurant.Id, customer, items, delivery, payload.Observation)
+	if delivery != nil && order.Subtotal < quote.MinimumOrderValue {
+		return nil, fmt.Errorf("%w: minimum is %.2f", ErrBelowDeliveryMinimum, quote.MinimumOrderValue)
+	}
+
+	err = u.applyPromotions(order, payload.CouponCode, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var program *aggregates.LoyaltyProgram
+	var redemption ports.OrderRedemption
+	if payload.RedeemLoyaltyReward {
+		program, redemption, err = u.redeemLoyaltyReward(order)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = u.createOrder(actor, order, payload.CouponCode, program, redemption)
+	if err != nil {
+		return nil, err
+	}
+
+	publishDomainEvents(u.eventPublisher, order)
+	return order, nil
+}
+
+func (u *orderUseCase) Complete(actor types.Actor, id string, expectedVersion int) (*aggregates.Order, error) {
+	return u.transition(actor, id, expectedVersion, func(order *aggregates.Order) bool {
+		return order.Complete()
+	})
+}
+
+// Cancel grava o cancelamento na mesma transação do que ele devolve: o uso das promoções,
+// as porções ainda não montadas e os pontos resgatados
+func (u *orderUseCase) Cancel(actor types.Actor, id string, reason string, expectedVersion int) (*aggregates.Order, error) {
+	order, err := u.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.OrderAggregateType, order.Id, order.Version, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *order
+	if !order.Cancel(reason) {
+		return nil, ErrInvalidOrderTransition
+	}
+
+	// só voltam ao cardápio as porções das marmitas que a cozinha ainda não montou
+	pending := make([]aggregates.OrderItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.Status == orderstatus.PENDING {
+			pending = append(pending, item)
+		}
+	}
+
+	cancellation := ports.OrderCancellation{
+		PromotionIds: order.PromotionIds(),
+		MenuPortions: aggregates.MenuPortionsOf(pending),
+	}
+	cancellation.LoyaltyAccount, cancellation.LoyaltyRefund, err = u.loyaltyLedger.refundEntry(order)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, order)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.orderRepository.Cancel(order, cancellation)
+	if err != nil {
+		return nil, err
+	}
+
+	publishDomainEvents(u.eventPublisher, order)
+	return order, nil
+}
+
+func (u *orderUseCase) AddPayment(actor types.Actor, id string, payload *OrderPaymentPayload) (*aggregates.Order, error) {
+	if !payload.PaymentMethod.IsValid() {
+		return nil, fmt.Errorf("%w: unknown payment method %q", ErrInvalidPayment, payload.PaymentMethod)
+	}
+	// o pagamento pelo marketplace só entra na importação do pedido
+	if payload.PaymentMethod == paymentmethod.MARKETPLACE {
+		return nil, fmt.Errorf("%w: %s payments are registered by the marketplace integration", ErrInvalidPayment, payload.PaymentMethod)
+	}
+
+	order, err := u.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.OrderAggregateType, order.Id, order.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// o pós-pago é cobrado pela conta do cliente e não entra na conferência do caixa
+	var sessionId string
+	if payload.PaymentMethod != paymentmethod.POST_PAID {
+		session, err := u.cashSessionRepository.FindOpen(order.Restaurant.Id, actor.Email)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			return nil, ErrCashSessionNotOpen
+		}
+		sessionId = session.Id
+	}
+
+	before := *order
+	before.Payments = append([]aggregates.OrderPayment(nil), order.Payments...)
+
+	payment := order.RegisterPayment(string(payload.PaymentMethod), payload.Amount, payload.PixKey, sessionId, time.Now())
+	if payment == nil {
+		return nil, ErrInvalidPayment
+	}
+
+	var tabDebit ports.OrderTabDebit
+	if payload.PaymentMethod == paymentmethod.POST_PAID {
+		tabDebit, err = u.postToTab(actor, order, payment)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, order)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.orderRepository.AddPayment(order, payment, tabDebit)
+	if err != nil {
+		return nil, err
+	}
+
+	publishDomainEvents(u.eventPublisher, order)
+	return order, nil
+}
+
+// postToTab lança o pagamento pós-pago no fiado do cliente do pedido, com as mesmas regras
+// do débito avulso; o lançamento é gravado junto com o pagamento
+func (u *orderUseCase) postToTab(actor types.Actor, order *aggregates.Order, payment *aggregates.OrderPayment) (ports.OrderTabDebit, error) {
+	if order.Customer.Id == "" {
+		return ports.OrderTabDebit{}, ErrPostPaidRequiresCustomer
+	}
+
+	restaurant, err := postPaidRestaurant(u.restaurantRepository, order.Restaurant.Id)
+	if err != nil {
+		return ports.OrderTabDebit{}, err
+	}
+
+	tab, err := u.customerTabRepository.FindByCustomerId(order.Restaurant.Id, order.Customer.Id)
+	if err != nil {
+		return ports.OrderTabDebit{}, err
+	}
+	if tab == nil {
+		return ports.OrderTabDebit{}, ErrCustomerTabNotFound
+	}
+
+	before := *tab
+	amount := int(math.Round(payment.Amount * 100))
+	entry, err := debitTab(restaurant, tab, amount, order.Id, fmt.Sprintf("Pedido %s", order.Id), actor.Email)
+	if err != nil {
+		return ports.OrderTabDebit{}, err
+	}
+
+	err = recordAudit(
+		tab,
+		actor,
+		tab.Restaurant.Id,
+		aggregates.CustomerTabAggregateType,
+		aggregates.AuditActionUpdate,
+		&before,
+		tab,
+	)
+	if err != nil {
+		return ports.OrderTabDebit{}, err
+	}
+
+	return ports.OrderTabDebit{Tab: tab, Entry: entry}, nil
+}
+
+func (u *orderUseCase) transition(actor types.Actor, id string, expectedVersion int, apply func(order *aggregates.Order) bool) (*aggregates.Order, error) {
+	order, err := u.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.OrderAggregateType, order.Id, order.Version, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *order
+	if !apply(order) {
+		return nil, ErrInvalidOrderTransition
+	}
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, order)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.orderRepository.Update(order)
+	if err != nil {
+		return nil, err
+	}
+
+	publishDomainEvents(u.eventPublisher, order)
+	return order, nil
+}
+
+func (u *orderUseCase) buildItems(restaurantId string, payloads []OrderItemPayload) ([]aggregates.OrderItem, error) {
+	var menu *aggregates.Menu
+	items := make([]aggregates.OrderItem, 0, len(payloads))
+
+	for _, payload := range payloads {
+		if payload.Quantity <= 0 {
+			return nil, ErrInvalidOrderItem
+		}
+
+		product, err := u.productRepository.FindById(payload.ProductId)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil || !product.Active || product.Restaurant.Id != restaurantId {
+			return nil, fmt.Errorf("%w: %s", ErrProductUnavailable, payload.ProductId)
+		}
+
+		unitPrice, err := strconv.ParseFloat(product.SalesPrice, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		var lunchbox *aggregates.LunchboxComposition
+		if len(product.DishTypeMap) > 0 {
+			if menu == nil {
+				menu, err = u.menuRepository.FindByOfferDate(restaurantId, time.Now())
+				if err != nil {
+					return nil, err
+				}
+				if menu == nil {
+					return nil, ErrMenuNotAvailable
+				}
+			}
+
+			lunchbox, err = composeLunchbox(product, menu, payload.Lunchbox)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		items = append(items, aggregates.NewOrderItem(
+			aggregates.PartialProduct{Id: product.Id, Name: product.Name},
+			unitPrice,
+			payload.Quantity,
+			payload.Observation,
+			lunchbox,
+		))
+	}
+
+	return items, nil
+}
+
+// createOrder grava o pedido. Uma regra automática que esgota entre a avaliação e a gravação
+// sai do pedido, que é precificado de novo sem ela; o cupom informado continua sendo exigido.
+// A auditoria é refeita a cada tentativa, com os valores que de fato vão ser gravados.
+func (u *orderUseCase) createOrder(
+	actor types.Actor,
+	order *aggregates.Order,
+	couponCode string,
+	program *aggregates.LoyaltyProgram,
+	redemption ports.OrderRedemption,
+) error {
+	exhausted := make([]string, 0)
+	for {
+		order.ClearAuditRecords()
+		err := u.audit(actor, aggregates.AuditActionCreate, nil, order)
+		if err != nil {
+			return err
+		}
+
+		err = u.orderRepository.Create(order, redemption)
+
+		var exhaustedErr *ports.PromotionExhaustedError
+		if !errors.As(err, &exhaustedErr) {
+			return err
+		}
+
+		promotionId := exhaustedErr.PromotionId
+		isCoupon := slices.ContainsFunc(order.Promotions, func(promotion aggregates.OrderPromotion) bool {
+			return promotion.PromotionId == promotionId && promotion.Code != ""
+		})
+		if isCoupon || slices.Contains(exhausted, promotionId) {
+			return err
+		}
+		exhausted = append(exhausted, promotionId)
+
+		order.ClearDiscounts()
+		if err := u.applyPromotions(order, couponCode, exhausted); err != nil {
+			return err
+		}
+		// o resgate continua o mesmo; a recompensa só volta a ser abatida do novo preço
+		if program != nil && order.ApplyLoyaltyReward(program) == 0 {
+			return ErrLoyaltyRewardNotApplicable
+		}
+	}
+}
+
+// applyPromotions aplica as regras automáticas, primeiro as de item e depois as de pedido,
+// e por último o cupom. Regras automáticas que não valem para o pedido são ignoradas, assim
+// como as de excluded; já o cupom informado precisa valer, senão o pedido é recusado com o motivo.
+func (u *orderUseCase) applyPromotions(order *aggregates.Order, couponCode string, excluded []string) error {
+	now := time.Now()
+
+	automatic, err := u.promotionRepository.FindAutomatic(order.Restaurant.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, targetsItems := range []bool{true, false} {
+		for i := range automatic {
+			promotion := &automatic[i]
+			if promotion.TargetsItems() != targetsItems || slices.Contains(excluded, promotion.Id) {
+				continue
+			}
+
+			rejection, err := u.checkPromotion(promotion, order, now)
+			if err != nil {
+				return err
+			}
+			if rejection == promotionEligible {
+				order.ApplyPromotion(promotion)
+			}
+		}
+	}
+
+	couponCode = strings.ToUpper(strings.TrimSpace(couponCode))
+	if couponCode == "" {
+		return nil
+	}
+
+	coupon, err := u.promotionRepository.FindByCode(order.Restaurant.Id, couponCode)
+	if err != nil {
+		return err
+	}
+	if coupon == nil {
+		return ErrInvalidCoupon
+	}
+
+	rejection, err := u.checkPromotion(coupon, order, now)
+	if err != nil {
+		return err
+	}
+	if rejection != promotionEligible {
+		return rejection.couponError()
+	}
+
+	if !order.ApplyPromotion(coupon) {
+		return ErrCouponNotApplicable
+	}
+
+	return nil
+}
+
+// redeemLoyaltyReward aplica a recompensa do cartão fidelidade depois das promoções e prepara o
+// débito dos pontos, que é gravado junto com o pedido
+func (u *orderUseCase) redeemLoyaltyReward(order *aggregates.Order) (*aggregates.LoyaltyProgram, ports.OrderRedemption, error) {
+	program, err := u.loyaltyProgramRepository.FindByRestaurantId(order.Restaurant.Id)
+	if err != nil {
+		return nil, ports.OrderRedemption{}, err
+	}
+	if program == nil || !program.Active {
+		return nil, ports.OrderRedemption{}, ErrLoyaltyProgramNotFound
+	}
+
+	account, entry, err := u.loyaltyLedger.redeemEntry(order, program)
+	if err != nil {
+		return nil, ports.OrderRedemption{}, err
+	}
+
+	return program, ports.OrderRedemption{LoyaltyAccount: account, LoyaltyRedeem: entry}, nil
+}
+
+// checkPromotion diz se o pedido pode usar a promoção e, se não pode, por quê
+func (u *orderUseCase) checkPromotion(promotion *aggregates.Promotion, order *aggregates.Order, now time.Time) (promotionRejection, error) {
+	if !promotion.IsAvailableAt(now) {
+		return promotionUnavailable, nil
+	}
+	if !promotion.HasUsesLeft() {
+		return promotionUsageLimitReached, nil
+	}
+	if !promotion.FirstOrderOnly && promotion.MaxUsesPerCustomer == 0 {
+		return promotionEligible, nil
+	}
+
+	customerId := order.Customer.Id
+	if customerId == "" {
+		return promotionRequiresCustomer, nil
+	}
+
+	if promotion.FirstOrderOnly {
+		count, err := u.orderRepository.CountByCustomerId(order.Restaurant.Id, customerId)
+		if err != nil {
+			return promotionEligible, err
+		}
+		if count > 0 {
+			return promotionFirstOrderOnly, nil
+		}
+	}
+
+	if promotion.MaxUsesPerCustomer > 0 {
+		count, err := u.promotionRepository.CountRedemptions(promotion.Id, customerId)
+		if err != nil {
+			return promotionEligible, err
+		}
+		if count >= promotion.MaxUsesPerCustomer {
+			return promotionUsageLimitReached, nil
+		}
+	}
+
+	return promotionEligible, nil
+}
+
+// couponError é o erro devolvido a quem informou o cupom
+func (r promotionRejection) couponError() error {
+	switch r {
+	case promotionUsageLimitReached:
+		return ErrCouponUsageLimitReached
+	case promotionRequiresCustomer:
+		return ErrCouponRequiresCustomer
+	case promotionFirstOrderOnly:
+		return ErrCouponRestrictedToFirstOrder
+	default:
+		return ErrInvalidCoupon
+	}
+}
+
+// composeLunchbox valida as escolhas contra o cardápio do dia: pratos habilitados e não esgotados e,
+// fora os adicionais, no máximo a quantidade por tipo prevista no DishTypeMap do produto.
+// As porções só são reservadas de fato na gravação do pedido.
+func composeLunchbox(product *aggregates.Product, menu *aggregates.Menu, payload *LunchboxPayload) (*aggregates.LunchboxComposition, error) {
+	if payload == nil || len(payload.Selections) == 0 {
+		return nil, ErrInvalidLunchbox
+	}
+
+	countByType := make(map[dishtype.DishType]int)
+	lunchbox := &aggregates.LunchboxComposition{
+		WantsFlatware: payload.WantsFlatware,
+		Selections:    make([]aggregates.LunchboxSelection, 0, len(payload.Selections)),
+	}
+
+	for _, selection := range payload.Selections {
+		menuItem := menu.FindItem(selection.MenuItemId)
+		if menuItem == nil || selection.Quantity <= 0 {
+			return nil, ErrInvalidLunchbox
+		}
+		if !menuItem.IsAvailable() {
+			return nil, fmt.Errorf("%w: %s", ports.ErrMenuItemSoldOut, menuItem.Dish.Name)
+		}
+
+		price := 0.0
+		if selection.IsAdditional {
+			if !menuItem.CanBeUsedAsAdditional {
+				return nil, ErrInvalidLunchbox
+			}
+			price = menuItem.AdditionalPrice
+		} else {
+			countByType[menuItem.Dish.Type] += selection.Quantity
+			if countByType[menuItem.Dish.Type] > product.DishTypeMap[menuItem.Dish.Type] {
+				return nil, ErrInvalidLunchbox
+			}
+		}
+
+		lunchbox.Selections = append(lunchbox.Selections, aggregates.LunchboxSelection{
+			MenuItemId:   menuItem.Id,
+			DishId:       menuItem.Dish.Id,
+			DishName:     menuItem.Dish.Name,
+			DishType:     menuItem.Dish.Type,
+			Quantity:     selection.Quantity,
+			IsAdditional: selection.IsAdditional,
+			Price:        price,
+		})
+	}
+
+	return lunchbox, nil
+}
+
+func (u *orderUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.Order) error {
+	order := after
+	if order == nil {
+		order = before
+	}
+
+	return recordAudit(
+		order,
+		actor,
+		order.Restaurant.Id,
+		aggregates.OrderAggregateType,
+		action,
+		before,
+		after,
+	)
+}
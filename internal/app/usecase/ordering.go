@@ -0,0 +1,26 @@
+package usecase
+
+// arrangeByIds devolve os itens na ordem de orderedIds; falha se a lista não contiver
+// exatamente cada item uma única vez, evitando reordenações parciais ou com ids de outro escopo.
+func arrangeByIds[T any](items []T, idOf func(*T) string, orderedIds []string) ([]T, bool) {
+	if len(items) != len(orderedIds) {
+		return nil, false
+	}
+
+	byId := make(map[string]*T, len(items))
+	for i := range items {
+		byId[idOf(&items[i])] = &items[i]
+	}
+
+	arranged := make([]T, 0, len(items))
+	for _, id := range orderedIds {
+		item, ok := byId[id]
+		if !ok {
+			return nil, false
+		}
+		delete(byId, id)
+		arranged = append(arranged, *item)
+	}
+
+	return arranged, true
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type orderedItem struct {
+	Id string
+}
+
+func TestArrangeByIds(t *testing.T) {
+	tests := []struct {
+		name       string
+		orderedIds []string
+		expected   []string
+		ok         bool
+	}{
+		{name: "reordena todos os itens", orderedIds: []string{"c", "a", "b"}, expected: []string{"c", "a", "b"}, ok: true},
+		{name: "mantém a ordem atual", orderedIds: []string{"a", "b", "c"}, expected: []string{"a", "b", "c"}, ok: true},
+		{name: "recusa id repetido", orderedIds: []string{"a", "a", "b"}},
+		{name: "recusa id de outro restaurante", orderedIds: []string{"a", "b", "outro-restaurante"}},
+		{name: "recusa lista sem todos os itens", orderedIds: []string{"b", "a"}},
+		{name: "recusa lista com itens a mais", orderedIds: []string{"a", "b", "c", "d"}},
+		{name: "recusa lista vazia", orderedIds: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange
+			items := []orderedItem{{Id: "a"}, {Id: "b"}, {Id: "c"}}
+
+			// act
+			arranged, ok := arrangeByIds(items, func(item *orderedItem) string { return item.Id }, test.orderedIds)
+
+			// assert
+			assert.Equal(t, test.ok, ok)
+			if !test.ok {
+				assert.Nil(t, arranged, "reordenação recusada não devolve itens")
+				return
+			}
+
+			ids := make([]string, 0, len(arranged))
+			for _, item := range arranged {
+				ids = append(ids, item.Id)
+			}
+			assert.Equal(t, test.expected, ids)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
 	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
 	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
 	"github.com/PedroNetto404/marmitech-backend/pkg/types"
@@ -14,31 +15,36 @@ const (
 )
 
 var (
-	ErrProductNotFound      = errors.New("product not found")
-	ErrCategoryNotFound     = errors.New("category not found")
-	ErrRestaurantNotFound   = errors.New("restaurant not found")
-	ErrProductAlreadyExists = errors.New("product already exists")
+	ErrProductNotFound          = errors.New("product not found")
+	ErrCategoryNotFound         = errors.New("category not found")
+	ErrRestaurantNotFound       = errors.New("restaurant not found")
+	ErrProductAlreadyExists     = errors.New("product already exists")
+	ErrInvalidProductOrder      = errors.New("product order must list every product of the category exactly once")
+	ErrInvalidProductFiscalData = errors.New("product fiscal data must have an 8-digit NCM, a 4-digit CFOP and a 3-digit CSOSN")
 )
 
 type (
 	ProductPayload struct {
-		Name        string                       `json:"name"`
-		Description string                       `json:"description"`
-		SalesPrice  string                       `json:"sales_price"`
-		CostPrice   string                       `json:"cost_price"`
-		DishTypeMap aggregates.DishTypeMap       `json:"dish_type_map"`
-		Category    aggregates.PartialCategory   `json:"category"`
-		Restaurant  aggregates.PartialRestaurant `json:"restaurant"`
+		Name            string                       `json:"name"`
+		Description     string                       `json:"description"`
+		SalesPrice      string                       `json:"sales_price"`
+		CostPrice       string                       `json:"cost_price"`
+		DishTypeMap     aggregates.DishTypeMap       `json:"dish_type_map"`
+		Fiscal          aggregates.ProductFiscalData `json:"fiscal"`
+		Category        aggregates.PartialCategory   `json:"category"`
+		Restaurant      aggregates.PartialRestaurant `json:"restaurant"`
+		ExpectedVersion int                          `json:"-"`
 	}
 
 	IProductUseCase interface {
-		Create(payload *ProductPayload) (*aggregates.Product, error)
+		Create(actor types.Actor, payload *ProductPayload) (*aggregates.Product, error)
 		Find(args types.FindArgs) (*types.PagedSlice[aggregates.Product], error)
 		FindById(id string) (*aggregates.Product, error)
-		Update(id string, payload *ProductPayload) (*aggregates.Product, error)
-		Delete(id string) error
-		SetPicture(id string, payload *types.FilePayload) (*aggregates.Product, error)
-		DeletePicture(id string) (*aggregates.Product, error)
+		Update(actor types.Actor, id string, payload *ProductPayload) (*aggregates.Product, error)
+		Delete(actor types.Actor, id string) error
+		SetPicture(actor types.Actor, id string, payload *types.FilePayload, expectedVersion int) (*aggregates.Product, error)
+		DeletePicture(actor types.Actor, id string, expectedVersion int) (*aggregates.Product, error)
+		Reorder(actor types.Actor, restaurantId string, categoryId string, orderedIds []string) ([]aggregates.Product, error)
 	}
 
 	productUseCase struct {
@@ -46,6 +52,7 @@ type (
 		categoryRepository   ports.ICategoryRepository
 		restaurantRepository ports.IRestaurantRepository
 		blockStorage         ports.IBlockStorage
+		eventPublisher       ports.IEventPublisher
 	}
 )
 
@@ -54,16 +61,22 @@ func NewProductUseCase(
 	categoryRepository ports.ICategoryRepository,
 	restaurantRepository ports.IRestaurantRepository,
 	blockStorage ports.IBlockStorage,
+	eventPublisher ports.IEventPublisher,
 ) IProductUseCase {
 	return &productUseCase{
-		productRepository:  productRepository,
-		categoryRepository: categoryRepository,
-		blockStorage:       blockStorage,
+		productRepository:    productRepository,
+		categoryRepository:   categoryRepository,
+		blockStorage:         blockStorage,
 		restaurantRepository: restaurantRepository,
+		eventPublisher:       eventPublisher,
 	}
 }
 
-func (u *productUseCase) Create(payload *ProductPayload) (*aggregates.Product, error) {
+func (u *productUseCase) Create(actor types.Actor, payload *ProductPayload) (*aggregates.Product, error) {
+	if !payload.Fiscal.IsEmpty() && !payload.Fiscal.IsValid() {
+		return nil, ErrInvalidProductFiscalData
+	}
+
 	restaurant, err := u.restaurantRepository.FindById(payload.Restaurant.Id)
 	if err != nil {
 		return nil, err
@@ -97,11 +110,21 @@ func (u *productUseCase) Create(payload *ProductPayload) (*aggregates.Product, e
 		payload.Category.Id,
 		payload.Restaurant.Id,
 	)
+	product.Fiscal = payload.Fiscal
+	product.RaiseDomainEvent(abstractions.NewDomainEvent(aggregates.ProductCreatedEvent, product.Id))
+
+	err = u.audit(actor, aggregates.AuditActionCreate, nil, product)
+	if err != nil {
+		return nil, err
+	}
+
 	err = u.productRepository.Create(product)
 	if err != nil {
 		return nil, err
 	}
 
+	publishDomainEvents(u.eventPublisher, product)
+
 	return product, nil
 }
 
@@ -125,7 +148,11 @@ func (u *productUseCase) FindById(id string) (*aggregates.Product, error) {
 	return product, nil
 }
 
-func (u *productUseCase) Update(id string, payload *ProductPayload) (*aggregates.Product, error) {
+func (u *productUseCase) Update(actor types.Actor, id string, payload *ProductPayload) (*aggregates.Product, error) {
+	if !payload.Fiscal.IsEmpty() && !payload.Fiscal.IsValid() {
+		return nil, ErrInvalidProductFiscalData
+	}
+
 	restaurant, err := u.restaurantRepository.FindById(payload.Restaurant.Id)
 	if err != nil {
 		return nil, err
@@ -150,23 +177,38 @@ func (u *productUseCase) Update(id string, payload *ProductPayload) (*aggregates
 		return nil, ErrProductNotFound
 	}
 
+	err = checkExpectedVersion(aggregates.ProductAggregateType, product.Id, product.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *product
 	product.Name = payload.Name
 	product.Description = payload.Description
 	product.SalesPrice = payload.SalesPrice
 	product.CostPrice = payload.CostPrice
 	product.DishTypeMap = payload.DishTypeMap
+	product.Fiscal = payload.Fiscal
 	product.Category.Id = payload.Category.Id
 	product.Restaurant.Id = payload.Restaurant.Id
+	product.RaiseDomainEvent(abstractions.NewDomainEvent(aggregates.ProductUpdatedEvent, product.Id))
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, product)
+	if err != nil {
+		return nil, err
+	}
 
 	err = u.productRepository.Update(product)
 	if err != nil {
 		return nil, err
 	}
 
+	publishDomainEvents(u.eventPublisher, product)
+
 	return product, nil
 }
 
-func (u *productUseCase) Delete(id string) error {
+func (u *productUseCase) Delete(actor types.Actor, id string) error {
 	product, err := u.productRepository.FindById(id)
 	if err != nil {
 		return err
@@ -176,10 +218,15 @@ func (u *productUseCase) Delete(id string) error {
 		return ErrProductNotFound
 	}
 
-	return u.productRepository.Delete(id)
+	err = u.audit(actor, aggregates.AuditActionDelete, product, nil)
+	if err != nil {
+		return err
+	}
+
+	return u.productRepository.Delete(product)
 }
 
-func (u *productUseCase) SetPicture(id string, payload *types.FilePayload) (*aggregates.Product, error) {
+func (u *productUseCase) SetPicture(actor types.Actor, id string, payload *types.FilePayload, expectedVersion int) (*aggregates.Product, error) {
 	product, err := u.productRepository.FindById(id)
 	if err != nil {
 		return nil, err
@@ -188,15 +235,26 @@ func (u *productUseCase) SetPicture(id string, payload *types.FilePayload) (*agg
 		return nil, ErrProductNotFound
 	}
 
+	err = checkExpectedVersion(aggregates.ProductAggregateType, product.Id, product.Version, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	key := fmt.Sprintf("product_%s_picture", id)
 	url, err := u.blockStorage.Save(key, productBucket, payload.Content)
 	if err != nil {
 		return nil, err
 	}
 
+	before := *product
 	product.PictureUrl = url
-	err = u.productRepository.Update(product)
 
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, product)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.productRepository.Update(product)
 	if err != nil {
 		return nil, err
 	}
@@ -204,7 +262,7 @@ func (u *productUseCase) SetPicture(id string, payload *types.FilePayload) (*agg
 	return product, nil
 }
 
-func (u *productUseCase) DeletePicture(id string) (*aggregates.Product, error) {
+func (u *productUseCase) DeletePicture(actor types.Actor, id string, expectedVersion int) (*aggregates.Product, error) {
 	product, err := u.productRepository.FindById(id)
 	if err != nil {
 		return nil, err
@@ -213,17 +271,28 @@ func (u *productUseCase) DeletePicture(id string) (*aggregates.Product, error) {
 		return nil, ErrProductNotFound
 	}
 
+	err = checkExpectedVersion(aggregates.ProductAggregateType, product.Id, product.Version, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	if product.PictureUrl == "" {
 		return product, nil
 	}
 
 	key := fmt.Sprintf("product_%s_picture", id)
 	err = u.blockStorage.Delete(key, productBucket)
-	if err != nil {	
+	if err != nil {
 		return nil, err
 	}
+	before := *product
 	product.PictureUrl = ""
 
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, product)
+	if err != nil {
+		return nil, err
+	}
+
 	err = u.productRepository.Update(product)
 	if err != nil {
 		return nil, err
@@ -231,3 +300,56 @@ func (u *productUseCase) DeletePicture(id string) (*aggregates.Product, error) {
 
 	return product, nil
 }
+
+func (u *productUseCase) Reorder(actor types.Actor, restaurantId string, categoryId string, orderedIds []string) ([]aggregates.Product, error) {
+	category, err := u.categoryRepository.FindById(categoryId)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil || category.Restaurant.Id != restaurantId {
+		return nil, ErrCategoryNotFound
+	}
+
+	current, err := u.productRepository.FindByCategoryId(categoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	products, ok := arrangeByIds(current, func(p *aggregates.Product) string { return p.Id }, orderedIds)
+	if !ok {
+		return nil, ErrInvalidProductOrder
+	}
+
+	for i := range products {
+		before := products[i]
+		products[i].Priority = i + 1
+		err = u.audit(actor, aggregates.AuditActionUpdate, &before, &products[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = u.productRepository.Reorder(categoryId, products)
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (u *productUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.Product) error {
+	product := after
+	if product == nil {
+		product = before
+	}
+
+	return recordAudit(
+		product,
+		actor,
+		product.Restaurant.Id,
+		aggregates.ProductAggregateType,
+		action,
+		before,
+		after,
+	)
+}
@@ -0,0 +1,272 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	discounttype "github.com/PedroNetto404/marmitech-backend/pkg/enums/discount_type"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrPromotionNotFound            = errors.New("promotion not found")
+	ErrInvalidPromotion             = errors.New("invalid promotion")
+	ErrPromotionCodeAlreadyExists   = errors.New("coupon code already exists in this restaurant")
+	ErrInvalidCoupon                = errors.New("coupon is invalid or expired")
+	ErrCouponNotApplicable          = errors.New("coupon does not apply to this order")
+	ErrCouponUsageLimitReached      = errors.New("coupon usage limit reached")
+	ErrCouponRequiresCustomer       = errors.New("coupon requires an identified customer")
+	ErrCouponRestrictedToFirstOrder = errors.New("coupon is valid only on the customer's first order")
+)
+
+var couponCodeRegex = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+type (
+	PromotionPayload struct {
+		Name               string                            `json:"name"`
+		Description        string                            `json:"description"`
+		Code               string                            `json:"code"`
+		DiscountType       discounttype.DiscountType         `json:"discount_type"`
+		Value              float64                           `json:"value"`
+		MinOrderValue      float64                           `json:"min_order_value"`
+		ProductIds         []string                          `json:"product_ids"`
+		Combo              []aggregates.PromotionRequirement `json:"combo"`
+		Weekdays           []time.Weekday                    `json:"weekdays"`
+		StartsAt           *time.Time                        `json:"starts_at"`
+		EndsAt             *time.Time                        `json:"ends_at"`
+		MaxUses            int                               `json:"max_uses"`
+		MaxUsesPerCustomer int                               `json:"max_uses_per_customer"`
+		FirstOrderOnly     bool                              `json:"first_order_only"`
+		Active             bool                              `json:"active"`
+		ExpectedVersion    int                               `json:"-"`
+	}
+
+	IPromotionUseCase interface {
+		FindByRestaurantId(restaurantId string) ([]aggregates.Promotion, error)
+		FindById(restaurantId, id string) (*aggregates.Promotion, error)
+		Create(actor types.Actor, restaurantId string, payload *PromotionPayload) (*aggregates.Promotion, error)
+		Update(actor types.Actor, restaurantId, id string, payload *PromotionPayload) (*aggregates.Promotion, error)
+		Delete(actor types.Actor, restaurantId, id string) error
+	}
+
+	promotionUseCase struct {
+		promotionRepository  ports.IPromotionRepository
+		productRepository    ports.IProductRepository
+		restaurantRepository ports.IRestaurantRepository
+	}
+)
+
+func NewPromotionUseCase(
+	promotionRepository ports.IPromotionRepository,
+	productRepository ports.IProductRepository,
+	restaurantRepository ports.IRestaurantRepository,
+) IPromotionUseCase {
+	return &promotionUseCase{
+		promotionRepository:  promotionRepository,
+		productRepository:    productRepository,
+		restaurantRepository: restaurantRepository,
+	}
+}
+
+func (u *promotionUseCase) FindByRestaurantId(restaurantId string) ([]aggregates.Promotion, error) {
+	return u.promotionRepository.FindByRestaurantId(restaurantId)
+}
+
+func (u *promotionUseCase) FindById(restaurantId, id string) (*aggregates.Promotion, error) {
+	promotion, err := u.promotionRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if promotion == nil || promotion.Restaurant.Id != restaurantId {
+		return nil, ErrPromotionNotFound
+	}
+
+	return promotion, nil
+}
+
+func (u *promotionUseCase) Create(actor types.Actor, restaurantId string, payload *PromotionPayload) (*aggregates.Promotion, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	if err := u.validate(restaurantId, "", payload); err != nil {
+		return nil, err
+	}
+
+	promotion := aggregates.NewPromotion(restaurantId, payload.Name, payload.DiscountType, payload.Value)
+	applyPromotion(promotion, payload)
+
+	err = u.audit(actor, aggregates.AuditActionCreate, nil, promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.promotionRepository.Create(promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (u *promotionUseCase) Update(actor types.Actor, restaurantId, id string, payload *PromotionPayload) (*aggregates.Promotion, error) {
+	promotion, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.PromotionAggregateType, promotion.Id, promotion.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.validate(restaurantId, promotion.Id, payload); err != nil {
+		return nil, err
+	}
+
+	before := *promotion
+	applyPromotion(promotion, payload)
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.promotionRepository.Update(promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (u *promotionUseCase) Delete(actor types.Actor, restaurantId, id string) error {
+	promotion, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return err
+	}
+
+	err = u.audit(actor, aggregates.AuditActionDelete, promotion, nil)
+	if err != nil {
+		return err
+	}
+
+	return u.promotionRepository.Delete(promotion)
+}
+
+// validate confere as regras do payload, normaliza o código do cupom e garante
+// que os produtos citados são do restaurante
+func (u *promotionUseCase) validate(restaurantId, promotionId string, payload *PromotionPayload) error {
+	payload.Name = strings.TrimSpace(payload.Name)
+	payload.Code = strings.ToUpper(strings.TrimSpace(payload.Code))
+
+	switch {
+	case payload.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidPromotion)
+	case !payload.DiscountType.IsValid():
+		return fmt.Errorf("%w: unknown discount type", ErrInvalidPromotion)
+	case payload.Value <= 0:
+		return fmt.Errorf("%w: value must be greater than zero", ErrInvalidPromotion)
+	case payload.DiscountType == discounttype.PERCENTAGE && payload.Value > 100:
+		return fmt.Errorf("%w: percentage must not exceed 100", ErrInvalidPromotion)
+	case payload.MinOrderValue < 0 || payload.MaxUses < 0 || payload.MaxUsesPerCustomer < 0:
+		return fmt.Errorf("%w: limits must not be negative", ErrInvalidPromotion)
+	case payload.Code != "" && !couponCodeRegex.MatchString(payload.Code):
+		return fmt.Errorf("%w: coupon code must have 3 to 32 letters, digits, - or _", ErrInvalidPromotion)
+	case len(payload.ProductIds) > 0 && len(payload.Combo) > 0:
+		return fmt.Errorf("%w: use either product_ids or combo", ErrInvalidPromotion)
+	case payload.StartsAt != nil && payload.EndsAt != nil && !payload.EndsAt.After(*payload.StartsAt):
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidPromotion)
+	}
+
+	for _, weekday := range payload.Weekdays {
+		if weekday < time.Sunday || weekday > time.Saturday {
+			return fmt.Errorf("%w: weekdays go from 0 (sunday) to 6 (saturday)", ErrInvalidPromotion)
+		}
+	}
+
+	productIds := slices.Clone(payload.ProductIds)
+	for _, requirement := range payload.Combo {
+		if requirement.Quantity <= 0 || slices.Contains(productIds, requirement.ProductId) {
+			return fmt.Errorf("%w: combo products must be distinct with positive quantities", ErrInvalidPromotion)
+		}
+		productIds = append(productIds, requirement.ProductId)
+	}
+
+	for _, productId := range productIds {
+		product, err := u.productRepository.FindById(productId)
+		if err != nil {
+			return err
+		}
+		if product == nil || product.Restaurant.Id != restaurantId {
+			return fmt.Errorf("%w: %s", ErrProductNotFound, productId)
+		}
+	}
+
+	if payload.Code == "" {
+		return nil
+	}
+
+	existing, err := u.promotionRepository.FindByCode(restaurantId, payload.Code)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Id != promotionId {
+		return ErrPromotionCodeAlreadyExists
+	}
+
+	return nil
+}
+
+func (u *promotionUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.Promotion) error {
+	promotion := after
+	if promotion == nil {
+		promotion = before
+	}
+
+	return recordAudit(
+		promotion,
+		actor,
+		promotion.Restaurant.Id,
+		aggregates.PromotionAggregateType,
+		action,
+		before,
+		after,
+	)
+}
+
+func applyPromotion(promotion *aggregates.Promotion, payload *PromotionPayload) {
+	promotion.Name = payload.Name
+	promotion.Description = payload.Description
+	promotion.Code = payload.Code
+	promotion.DiscountType = payload.DiscountType
+	promotion.Value = payload.Value
+	promotion.MinOrderValue = payload.MinOrderValue
+	promotion.ProductIds = orEmpty(payload.ProductIds)
+	promotion.Combo = orEmpty(payload.Combo)
+	promotion.Weekdays = orEmpty(payload.Weekdays)
+	promotion.StartsAt = payload.StartsAt
+	promotion.EndsAt = payload.EndsAt
+	promotion.MaxUses = payload.MaxUses
+	promotion.MaxUsesPerCustomer = payload.MaxUsesPerCustomer
+	promotion.FirstOrderOnly = payload.FirstOrderOnly
+	promotion.Active = payload.Active
+	promotion.UpdatedAt = time.Now()
+}
+
+func orEmpty[T any](values []T) []T {
+	if values == nil {
+		return make([]T, 0)
+	}
+	return values
+}
@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	// os relatórios não podem depender da base de fusos instalada no servidor
+	_ "time/tzdata"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+var (
+	ErrInvalidReportPeriod = errors.New("invalid report period")
+	ErrInvalidTimeZone     = errors.New("invalid time zone")
+)
+
+const (
+	defaultReportTimeZone = "America/Sao_Paulo"
+	defaultReportDays     = 30
+	maxReportDays         = 366
+	defaultTopCustomers   = 10
+	maxTopCustomers       = 100
+)
+
+type (
+	// ReportPeriodPayload traz datas YYYY-MM-DD inclusivas no fuso informado.
+	// Sem datas o período é o dos últimos 30 dias, até hoje.
+	ReportPeriodPayload struct {
+		From     string
+		To       string
+		TimeZone string
+	}
+
+	IReportUseCase interface {
+		Summary(restaurantId string, payload ReportPeriodPayload) (*dtos.SalesSummaryDto, error)
+		SalesByDay(restaurantId string, payload ReportPeriodPayload) ([]dtos.DailySalesDto, error)
+		SalesByHour(restaurantId string, payload ReportPeriodPayload) ([]dtos.HourlySalesDto, error)
+		SalesByProduct(restaurantId string, payload ReportPeriodPayload) ([]dtos.ProductSalesDto, error)
+		SalesByCategory(restaurantId string, payload ReportPeriodPayload) ([]dtos.CategorySalesDto, error)
+		SalesByPaymentMethod(restaurantId string, payload ReportPeriodPayload) ([]dtos.PaymentMethodSalesDto, error)
+		TopCustomers(restaurantId string, payload ReportPeriodPayload, limit int) ([]dtos.CustomerSalesDto, error)
+	}
+
+	reportUseCase struct {
+		reportRepository     ports.IReportRepository
+		restaurantRepository ports.IRestaurantRepository
+	}
+
+	reportPeriod struct {
+		query    ports.ReportQuery
+		location *time.Location
+		firstDay time.Time
+		lastDay  time.Time
+	}
+)
+
+func NewReportUseCase(
+	reportRepository ports.IReportRepository,
+	restaurantRepository ports.IRestaurantRepository,
+) IReportUseCase {
+	return &reportUseCase{
+		reportRepository:     reportRepository,
+		restaurantRepository: restaurantRepository,
+	}
+}
+
+func (u *reportUseCase) Summary(restaurantId string, payload ReportPeriodPayload) (*dtos.SalesSummaryDto, error) {
+	period, err := resolveReportPeriod(u.restaurantRepository, restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := u.reportRepository.Totals(period.query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.SalesSummaryDto{
+		From:                    period.firstDay.Format(time.DateOnly),
+		To:                      period.lastDay.Format(time.DateOnly),
+		TimeZone:                period.location.String(),
+		Orders:                  totals.Orders,
+		Cancelled:               totals.Cancelled,
+		CancellationRatePercent: percentOf(float64(totals.Cancelled), float64(totals.Orders+totals.Cancelled)),
+		Gross:                   roundMoney(totals.Gross),
+		Discount:                roundMoney(totals.Discount),
+		Net:                     roundMoney(totals.Net),
+		AverageTicket:           averageOf(totals.Net, totals.Orders),
+		Deliveries:              totals.Deliveries,
+		Pickups:                 totals.Orders - totals.Deliveries,
+		DeliverySharePercent:    percentOf(float64(totals.Deliveries), float64(totals.Orders)),
+	}, nil
+}
+
+// SalesByDay devolve todos os dias do período, inclusive os sem venda
+func (u *reportUseCase) SalesByDay(restaurantId string, payload ReportPeriodPayload) ([]dtos.DailySalesDto, error) {
+	period, err := resolveReportPeriod(u.restaurantRepository, restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := u.reportRepository.SalesByHour(period.query)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]dtos.DailySalesDto, 0, defaultReportDays)
+	index := make(map[string]int)
+	for day := period.firstDay; !day.After(period.lastDay); day = day.AddDate(0, 0, 1) {
+		index[day.Format(time.DateOnly)] = len(days)
+		days = append(days, dtos.DailySalesDto{Date: day.Format(time.DateOnly)})
+	}
+
+	// as horas vêm no fuso do banco e são reagrupadas no fuso do relatório
+	for _, bucket := range buckets {
+		i, ok := index[bucket.Start.In(period.location).Format(time.DateOnly)]
+		if !ok {
+			continue
+		}
+		days[i].Orders += bucket.Orders
+		days[i].Gross += bucket.Gross
+		days[i].Discount += bucket.Discount
+		days[i].Net += bucket.Net
+	}
+
+	for i := range days {
+		days[i].Gross = roundMoney(days[i].Gross)
+		days[i].Discount = roundMoney(days[i].Discount)
+		days[i].Net = roundMoney(days[i].Net)
+		days[i].AverageTicket = averageOf(days[i].Net, days[i].Orders)
+	}
+
+	return days, nil
+}
+
+// SalesByHour soma o período por hora do dia, de 0 a 23, para achar os horários de pico
+func (u *reportUseCase) SalesByHour(restaurantId string, payload ReportPeriodPayload) ([]dtos.HourlySalesDto, error) {
+	period, err := resolveReportPeriod(u.restaurantRepository, restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := u.reportRepository.SalesByHour(period.query)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := make([]dtos.HourlySalesDto, 24)
+	for hour := range hours {
+		hours[hour].Hour = hour
+	}
+
+	for _, bucket := range buckets {
+		hour := bucket.Start.In(period.location).Hour()
+		hours[hour].Orders += bucket.Orders
+		hours[hour].Gross += bucket.Gross
+		hours[hour].Discount += bucket.Discount
+		hours[hour].Net += bucket.Net
+	}
+
+	for i := range hours {
+		hours[i].Gross = roundMoney(hours[i].Gross)
+		hours[i].Discount = roundMoney(hours[i].Discount)
+		hours[i].Net = roundMoney(hours[i].Net)
+		hours[i].AverageTicket = averageOf(hours[i].Net, hours[i].Orders)
+	}
+
+	return hours, nil
+}
+
+func (u *reportUseCase) SalesByProduct(restaurantId string, payload ReportPeriodPayload) ([]dtos.ProductSalesDto, error) {
+	period, err := resolveReportPeriod(u.restaurantRepository, restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := u.reportRepository.SalesByProduct(period.query)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	for _, row := range rows {
+		total += row.Revenue
+	}
+
+	products := make([]dtos.ProductSalesDto, 0, len(rows))
+	for _, row := range rows {
+		products = append(products, dtos.ProductSalesDto{
+			ProductId:    row.ProductId,
+			ProductName:  row.ProductName,
+			Quantity:     row.Quantity,
+			Revenue:      roundMoney(row.Revenue),
+			SharePercent: percentOf(row.Revenue, total),
+		})
+	}
+
+	return products, nil
+}
+
+func (u *reportUseCase) SalesByCategory(restaurantId string, payload ReportPeriodPayload) ([]dtos.CategorySalesDto, error) {
+	period, err := resolveReportPeriod(u.restaurantRepository, restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := u.reportRepository.SalesByCategory(period.query)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	for _, row := range rows {
+		total += row.Revenue
+	}
+
+	categories := make([]dtos.CategorySalesDto, 0, len(rows))
+	for _, row := range rows {
+		categories = append(categories, dtos.CategorySalesDto{
+			CategoryId:   row.CategoryId,
+			CategoryName: row.CategoryName,
+			Quantity:     row.Quantity,
+			Revenue:      roundMoney(row.Revenue),
+			SharePercent: percentOf(row.Revenue, total),
+		})
+	}
+
+	return categories, nil
+}
+
+func (u *reportUseCase) SalesByPaymentMethod(restaurantId string, payload ReportPeriodPayload) ([]dtos.PaymentMethodSalesDto, error) {
+	period, err := resolveReportPeriod(u.restaurantRepository, restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := u.reportRepository.SalesByPaymentMethod(period.query)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	for _, row := range rows {
+		total += row.Amount
+	}
+
+	methods := make([]dtos.PaymentMethodSalesDto, 0, len(rows))
+	for _, row := range rows {
+		methods = append(methods, dtos.PaymentMethodSalesDto{
+			PaymentMethod: row.PaymentMethod,
+			Payments:      row.Payments,
+			Amount:        roundMoney(row.Amount),
+			SharePercent:  percentOf(row.Amount, total),
+		})
+	}
+
+	return methods, nil
+}
+
+func (u *reportUseCase) TopCustomers(restaurantId string, payload ReportPeriodPayload, limit int) ([]dtos.CustomerSalesDto, error) {
+	if limit <= 0 {
+		limit = defaultTopCustomers
+	}
+	limit = min(limit, maxTopCustomers)
+
+	period, err := resolveReportPeriod(u.restaurantRepository, restaurantId, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := u.reportRepository.TopCustomers(period.query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	customers := make([]dtos.CustomerSalesDto, 0, len(rows))
+	for _, row := range rows {
+		customers = append(customers, dtos.CustomerSalesDto{
+			CustomerId:    row.CustomerId,
+			Name:          strings.TrimSpace(row.FirstName + " " + row.LastName),
+			Orders:        row.Orders,
+			Spent:         roundMoney(row.Spent),
+			AverageTicket: averageOf(row.Spent, row.Orders),
+		})
+	}
+
+	return customers, nil
+}
+
+// resolveReportPeriod converte as datas do fuso do relatório no intervalo [from, to) em instantes absolutos
+func resolveReportPeriod(restaurantRepository ports.IRestaurantRepository, restaurantId string, payload ReportPeriodPayload) (*reportPeriod, error) {
+	restaurant, err := restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	timeZone := payload.TimeZone
+	if timeZone == "" {
+		timeZone = defaultReportTimeZone
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimeZone, timeZone)
+	}
+
+	now := time.Now().In(location)
+	lastDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	if payload.To != "" {
+		lastDay, err = time.ParseInLocation(time.DateOnly, payload.To, location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: to must be YYYY-MM-DD", ErrInvalidReportPeriod)
+		}
+	}
+
+	firstDay := lastDay.AddDate(0, 0, 1-defaultReportDays)
+	if payload.From != "" {
+		firstDay, err = time.ParseInLocation(time.DateOnly, payload.From, location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: from must be YYYY-MM-DD", ErrInvalidReportPeriod)
+		}
+	}
+
+	if lastDay.Before(firstDay) {
+		return nil, fmt.Errorf("%w: to must not be before from", ErrInvalidReportPeriod)
+	}
+	if firstDay.AddDate(0, 0, maxReportDays-1).Before(lastDay) {
+		return nil, fmt.Errorf("%w: period must not exceed %d days", ErrInvalidReportPeriod, maxReportDays)
+	}
+
+	return &reportPeriod{
+		query: ports.ReportQuery{
+			RestaurantId: restaurant.Id,
+			From:         firstDay,
+			To:           lastDay.AddDate(0, 0, 1),
+		},
+		location: location,
+		firstDay: firstDay,
+		lastDay:  lastDay,
+	}, nil
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func averageOf(total float64, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return roundMoney(total / float64(count))
+}
+
+func percentOf(part, whole float64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return roundMoney(part / whole * 100)
+}
@@ -0,0 +1,103 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/usecase"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReportRepository struct {
+	ports.IReportRepository
+	buckets []ports.SalesBucket
+	query   ports.ReportQuery
+}
+
+func (r *fakeReportRepository) SalesByHour(query ports.ReportQuery) ([]ports.SalesBucket, error) {
+	r.query = query
+	return r.buckets, nil
+}
+
+func newReportUseCase(buckets ...ports.SalesBucket) (usecase.IReportUseCase, *fakeReportRepository) {
+	restaurant := &aggregates.Restaurant{}
+	restaurant.Id = "restaurant"
+
+	reportRepository := &fakeReportRepository{buckets: buckets}
+	return usecase.NewReportUseCase(reportRepository, &fakeRestaurantRepository{restaurant: restaurant}), reportRepository
+}
+
+func TestSalesByDayRegroupsHoursAcrossMidnightInReportTimeZone(t *testing.T) {
+	// 02:00 UTC do dia 15 ainda é 23:00 do dia 14 em São Paulo
+	lateNight := ports.SalesBucket{Start: time.Date(2026, 10, 15, 2, 0, 0, 0, time.UTC), Orders: 2, Gross: 50, Net: 50}
+	lunch := ports.SalesBucket{Start: time.Date(2026, 10, 15, 15, 0, 0, 0, time.UTC), Orders: 1, Gross: 30, Discount: 5, Net: 25}
+
+	tests := []struct {
+		name     string
+		timeZone string
+		expected map[string]int
+	}{
+		{name: "fuso de São Paulo", timeZone: "America/Sao_Paulo", expected: map[string]int{"2026-10-14": 2, "2026-10-15": 1, "2026-10-16": 0}},
+		{name: "fuso UTC", timeZone: "UTC", expected: map[string]int{"2026-10-14": 0, "2026-10-15": 3, "2026-10-16": 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange
+			useCase, _ := newReportUseCase(lateNight, lunch)
+
+			// act
+			days, err := useCase.SalesByDay("restaurant", usecase.ReportPeriodPayload{From: "2026-10-14", To: "2026-10-16", TimeZone: test.timeZone})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Len(t, days, 3, "todos os dias do período, inclusive os sem venda")
+			for _, day := range days {
+				assert.Equal(t, test.expected[day.Date], day.Orders, "pedidos do dia %s", day.Date)
+			}
+		})
+	}
+}
+
+func TestSalesByDayQueriesWholeLocalDays(t *testing.T) {
+	// arrange
+	useCase, reportRepository := newReportUseCase()
+
+	// act
+	days, err := useCase.SalesByDay("restaurant", usecase.ReportPeriodPayload{From: "2026-10-14", To: "2026-10-14", TimeZone: "America/Sao_Paulo"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC), reportRepository.query.From.UTC(), "início do dia local")
+	assert.Equal(t, time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC), reportRepository.query.To.UTC(), "fim exclusivo do dia local")
+	assert.Len(t, days, 1)
+	assert.Zero(t, days[0].Orders, "dia sem venda aparece zerado")
+	assert.Zero(t, days[0].AverageTicket, "ticket médio sem pedidos é zero")
+}
+
+func TestSalesByHourUsesLocalHourAndKeepsEmptyHours(t *testing.T) {
+	// arrange
+	useCase, _ := newReportUseCase(
+		ports.SalesBucket{Start: time.Date(2026, 10, 15, 2, 0, 0, 0, time.UTC), Orders: 2, Gross: 50, Net: 50},
+		ports.SalesBucket{Start: time.Date(2026, 10, 15, 15, 0, 0, 0, time.UTC), Orders: 1, Gross: 30, Discount: 5, Net: 25},
+		ports.SalesBucket{Start: time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC), Orders: 3, Gross: 75, Net: 75},
+	)
+
+	// act
+	hours, err := useCase.SalesByHour("restaurant", usecase.ReportPeriodPayload{From: "2026-10-14", To: "2026-10-16", TimeZone: "America/Sao_Paulo"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, hours, 24, "todas as horas do dia, inclusive as sem venda")
+	for hour, sales := range hours {
+		assert.Equal(t, hour, sales.Hour)
+	}
+	assert.Equal(t, 2, hours[23].Orders, "02:00 UTC é 23:00 em São Paulo")
+	assert.Equal(t, 4, hours[12].Orders, "15:00 UTC de dias diferentes soma às 12:00 locais")
+	assert.Equal(t, 100.0, hours[12].Net)
+	assert.Equal(t, 25.0, hours[12].AverageTicket)
+	assert.Zero(t, hours[2].Orders, "a hora em UTC não recebe vendas")
+	assert.Zero(t, hours[0].Net, "hora sem venda aparece zerada")
+}
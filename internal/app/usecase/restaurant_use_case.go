@@ -14,31 +14,37 @@ const bucket = "restaurants"
 
 type (
 	IRestaurantUseCase interface {
-		Create(input *dtos.RestaurantPayload) (*dtos.RestaurantDto, error)
-		Update(id string, input *dtos.RestaurantPayload) (*dtos.RestaurantDto, error)
-		SetImages(id string, payload *dtos.SetRestaurantImagesPayload) (*dtos.RestaurantDto, error)
+		Create(actor types.Actor, input *dtos.RestaurantPayload) (*dtos.RestaurantDto, error)
+		Update(actor types.Actor, id string, input *dtos.RestaurantPayload) (*dtos.RestaurantDto, error)
+		SetImages(actor types.Actor, id string, payload *dtos.SetRestaurantImagesPayload) (*dtos.RestaurantDto, error)
 		GetById(id string) (*dtos.RestaurantDto, error)
 		GetAll(request types.FindArgs) (*types.PagedSlice[dtos.RestaurantDto], error)
-		Delete(id string) error
+		Delete(actor types.Actor, id string) error
 	}
 
 	restaurantUseCase struct {
 		restaurantRepository ports.IRestaurantRepository
 		fileStorage          ports.IBlockStorage
+		addressLookup        ports.IAddressLookup
+		geocoder             ports.IGeocoder
 	}
 )
 
 func NewRestaurantUseCase(
 	restaurantRepository ports.IRestaurantRepository,
 	fileStorage ports.IBlockStorage,
+	addressLookup ports.IAddressLookup,
+	geocoder ports.IGeocoder,
 ) IRestaurantUseCase {
 	return &restaurantUseCase{
 		restaurantRepository: restaurantRepository,
 		fileStorage:          fileStorage,
+		addressLookup:        addressLookup,
+		geocoder:             geocoder,
 	}
 }
 
-func (r *restaurantUseCase) Create(input *dtos.RestaurantPayload) (*dtos.RestaurantDto, error) {
+func (r *restaurantUseCase) Create(actor types.Actor, input *dtos.RestaurantPayload) (*dtos.RestaurantDto, error) {
 	exists, err := r.RestaurantExists(input.Slug, input.Cnpj)
 	if err != nil {
 		return nil, err
@@ -47,6 +53,12 @@ func (r *restaurantUseCase) Create(input *dtos.RestaurantPayload) (*dtos.Restaur
 		return nil, fmt.Errorf("restaurant with slug %s or cnpj %s already exists", input.Slug, input.Cnpj)
 	}
 
+	err = normalizeAddress(r.addressLookup, &input.Address)
+	if err != nil {
+		return nil, err
+	}
+	geocodeAddress(r.geocoder, &input.Address, nil)
+
 	restaurant := aggregates.NewRestaurant(
 		input.TradeName,
 		input.LegalName,
@@ -59,6 +71,11 @@ func (r *restaurantUseCase) Create(input *dtos.RestaurantPayload) (*dtos.Restaur
 		input.Settings,
 	)
 
+	err = r.audit(actor, aggregates.AuditActionCreate, nil, restaurant)
+	if err != nil {
+		return nil, err
+	}
+
 	err = r.restaurantRepository.Create(restaurant)
 	if err != nil {
 		return nil, err
@@ -68,7 +85,7 @@ func (r *restaurantUseCase) Create(input *dtos.RestaurantPayload) (*dtos.Restaur
 	return restaurantDto, nil
 }
 
-func (r *restaurantUseCase) Update(id string, input *dtos.RestaurantPayload) (*dtos.RestaurantDto, error) {
+func (r *restaurantUseCase) Update(actor types.Actor, id string, input *dtos.RestaurantPayload) (*dtos.RestaurantDto, error) {
 	exists, err := r.RestaurantExists(input.Slug, input.Cnpj)
 	if err != nil {
 		return nil, err
@@ -85,6 +102,18 @@ func (r *restaurantUseCase) Update(id string, input *dtos.RestaurantPayload) (*d
 		return nil, fmt.Errorf("restaurant with id %s not found", id)
 	}
 
+	err = checkExpectedVersion(aggregates.RestaurantAggregateType, restaurant.Id, restaurant.Version, input.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = normalizeAddress(r.addressLookup, &input.Address)
+	if err != nil {
+		return nil, err
+	}
+	geocodeAddress(r.geocoder, &input.Address, &restaurant.Address)
+
+	before := *restaurant
 	restaurant.TradeName = input.TradeName
 	restaurant.LegalName = input.LegalName
 	restaurant.CNPJ = input.Cnpj
@@ -95,6 +124,11 @@ func (r *restaurantUseCase) Update(id string, input *dtos.RestaurantPayload) (*d
 	restaurant.Address = input.Address
 	restaurant.Settings = input.Settings
 	restaurant.UpdatedAt = time.Now()
+	err = r.audit(actor, aggregates.AuditActionUpdate, &before, restaurant)
+	if err != nil {
+		return nil, err
+	}
+
 	err = r.restaurantRepository.Update(restaurant)
 	if err != nil {
 		return nil, err
@@ -104,7 +138,7 @@ func (r *restaurantUseCase) Update(id string, input *dtos.RestaurantPayload) (*d
 	return restaurantDto, nil
 }
 
-func (r *restaurantUseCase) SetImages(id string, payload *dtos.SetRestaurantImagesPayload) (*dtos.RestaurantDto, error) {
+func (r *restaurantUseCase) SetImages(actor types.Actor, id string, payload *dtos.SetRestaurantImagesPayload) (*dtos.RestaurantDto, error) {
 	restaurant, err := r.restaurantRepository.FindById(id)
 	if err != nil {
 		return nil, err
@@ -114,10 +148,14 @@ func (r *restaurantUseCase) SetImages(id string, payload *dtos.SetRestaurantImag
 		return nil, fmt.Errorf("restaurant with id %s not found", id)
 	}
 
-	if payload.Logo != nil {
-		key := fmt.Sprintf("restaurants_%s_logo", restaurant.Id)
+	err = checkExpectedVersion(aggregates.RestaurantAggregateType, restaurant.Id, restaurant.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
 
-		url, err := r.fileStorage.Save(key, dishBucket, payload.Logo.Content)
+	before := *restaurant
+	if payload.Logo != nil {
+		url, err := r.fileStorage.Save(restaurantLogoKey(restaurant.Id), dishBucket, payload.Logo.Content)
 		if err != nil {
 			return nil, err
 		}
@@ -134,6 +172,11 @@ func (r *restaurantUseCase) SetImages(id string, payload *dtos.SetRestaurantImag
 		restaurant.BannerUrl = url
 	}
 
+	err = r.audit(actor, aggregates.AuditActionUpdate, &before, restaurant)
+	if err != nil {
+		return nil, err
+	}
+
 	err = r.restaurantRepository.Update(restaurant)
 	if err != nil {
 		return nil, err
@@ -173,7 +216,7 @@ func (r *restaurantUseCase) GetAll(args types.FindArgs) (*types.PagedSlice[dtos.
 	return &mapped, nil
 }
 
-func (r *restaurantUseCase) Delete(id string) error {
+func (r *restaurantUseCase) Delete(actor types.Actor, id string) error {
 	restaurant, err := r.restaurantRepository.FindById(id)
 	if err != nil {
 		return err
@@ -183,8 +226,11 @@ func (r *restaurantUseCase) Delete(id string) error {
 		return fmt.Errorf("restaurant with id %s not found", id)
 	}
 
-	r.restaurantRepository.Delete(id)
-	return nil
+	if err := r.audit(actor, aggregates.AuditActionDelete, restaurant, nil); err != nil {
+		return err
+	}
+
+	return r.restaurantRepository.Delete(restaurant)
 }
 
 func (r *restaurantUseCase) RestaurantExists(slug, cnpj string) (bool, error) {
@@ -205,3 +251,24 @@ func (r *restaurantUseCase) RestaurantExists(slug, cnpj string) (bool, error) {
 
 	return true, nil
 }
+
+func (r *restaurantUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.Restaurant) error {
+	restaurant := after
+	if restaurant == nil {
+		restaurant = before
+	}
+
+	return recordAudit(
+		restaurant,
+		actor,
+		restaurant.Id,
+		aggregates.RestaurantAggregateType,
+		action,
+		before,
+		after,
+	)
+}
+
+func restaurantLogoKey(restaurantId string) string {
+	return fmt.Sprintf("restaurants_%s_logo", restaurantId)
+}
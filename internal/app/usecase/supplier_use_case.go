@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrSupplierNotFound              = errors.New("supplier not found")
+	ErrInvalidSupplier               = errors.New("invalid supplier")
+	ErrSupplierDocumentAlreadyExists = errors.New("supplier document already exists in this restaurant")
+)
+
+type (
+	SupplierPayload struct {
+		Name            string `json:"name"`
+		Document        string `json:"document"`
+		Email           string `json:"email"`
+		Phone           string `json:"phone"`
+		Note            string `json:"note"`
+		ExpectedVersion int    `json:"-"`
+	}
+
+	ISupplierUseCase interface {
+		FindByRestaurantId(restaurantId string) ([]aggregates.Supplier, error)
+		FindById(restaurantId, id string) (*aggregates.Supplier, error)
+		Create(actor types.Actor, restaurantId string, payload *SupplierPayload) (*aggregates.Supplier, error)
+		Update(actor types.Actor, restaurantId, id string, payload *SupplierPayload) (*aggregates.Supplier, error)
+		Delete(actor types.Actor, restaurantId, id string) error
+	}
+
+	supplierUseCase struct {
+		supplierRepository   ports.ISupplierRepository
+		restaurantRepository ports.IRestaurantRepository
+	}
+)
+
+func NewSupplierUseCase(
+	supplierRepository ports.ISupplierRepository,
+	restaurantRepository ports.IRestaurantRepository,
+) ISupplierUseCase {
+	return &supplierUseCase{
+		supplierRepository:   supplierRepository,
+		restaurantRepository: restaurantRepository,
+	}
+}
+
+func (u *supplierUseCase) FindByRestaurantId(restaurantId string) ([]aggregates.Supplier, error) {
+	return u.supplierRepository.FindByRestaurantId(restaurantId)
+}
+
+func (u *supplierUseCase) FindById(restaurantId, id string) (*aggregates.Supplier, error) {
+	supplier, err := u.supplierRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil || supplier.Restaurant.Id != restaurantId {
+		return nil, ErrSupplierNotFound
+	}
+
+	return supplier, nil
+}
+
+func (u *supplierUseCase) Create(actor types.Actor, restaurantId string, payload *SupplierPayload) (*aggregates.Supplier, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	if err := u.validate(restaurantId, "", payload); err != nil {
+		return nil, err
+	}
+
+	supplier := aggregates.NewSupplier(restaurantId, payload.Name)
+	applySupplier(supplier, payload)
+
+	err = u.audit(actor, aggregates.AuditActionCreate, nil, supplier)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.supplierRepository.Create(supplier)
+	if err != nil {
+		return nil, err
+	}
+
+	return supplier, nil
+}
+
+func (u *supplierUseCase) Update(actor types.Actor, restaurantId, id string, payload *SupplierPayload) (*aggregates.Supplier, error) {
+	supplier, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.SupplierAggregateType, supplier.Id, supplier.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.validate(restaurantId, supplier.Id, payload); err != nil {
+		return nil, err
+	}
+
+	before := *supplier
+	applySupplier(supplier, payload)
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, supplier)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.supplierRepository.Update(supplier)
+	if err != nil {
+		return nil, err
+	}
+
+	return supplier, nil
+}
+
+func (u *supplierUseCase) Delete(actor types.Actor, restaurantId, id string) error {
+	supplier, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return err
+	}
+
+	err = u.audit(actor, aggregates.AuditActionDelete, supplier, nil)
+	if err != nil {
+		return err
+	}
+
+	return u.supplierRepository.Delete(supplier)
+}
+
+// validate normaliza o documento para só dígitos e garante que ele não se repete no restaurante
+func (u *supplierUseCase) validate(restaurantId, supplierId string, payload *SupplierPayload) error {
+	payload.Name = strings.TrimSpace(payload.Name)
+	payload.Email = strings.TrimSpace(payload.Email)
+	payload.Document = strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, payload.Document)
+
+	switch {
+	case payload.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidSupplier)
+	case payload.Document != "" && len(payload.Document) != 11 && len(payload.Document) != 14:
+		return fmt.Errorf("%w: document must be a CPF (11 digits) or a CNPJ (14 digits)", ErrInvalidSupplier)
+	case payload.Email != "" && !strings.Contains(payload.Email, "@"):
+		return fmt.Errorf("%w: invalid email", ErrInvalidSupplier)
+	}
+
+	if payload.Document == "" {
+		return nil
+	}
+
+	existing, err := u.supplierRepository.FindByDocument(restaurantId, payload.Document)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Id != supplierId {
+		return ErrSupplierDocumentAlreadyExists
+	}
+
+	return nil
+}
+
+func (u *supplierUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.Supplier) error {
+	supplier := after
+	if supplier == nil {
+		supplier = before
+	}
+
+	return recordAudit(
+		supplier,
+		actor,
+		supplier.Restaurant.Id,
+		aggregates.SupplierAggregateType,
+		action,
+		before,
+		after,
+	)
+}
+
+func applySupplier(supplier *aggregates.Supplier, payload *SupplierPayload) {
+	supplier.Name = payload.Name
+	supplier.Document = payload.Document
+	supplier.Email = payload.Email
+	supplier.Phone = strings.TrimSpace(payload.Phone)
+	supplier.Note = strings.TrimSpace(payload.Note)
+	supplier.UpdatedAt = time.Now()
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"log"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/escpos"
+)
+
+type (
+	ITicketUseCase interface {
+		KitchenTicket(restaurantId, orderId string, paper escpos.PaperWidth) (*escpos.Document, error)
+		Receipt(restaurantId, orderId string, paper escpos.PaperWidth) (*escpos.Document, error)
+		ReceiptPdf(restaurantId, orderId string) ([]byte, error)
+	}
+
+	ticketUseCase struct {
+		orderRepository      ports.IOrderRepository
+		restaurantRepository ports.IRestaurantRepository
+		ticketRenderer       ports.ITicketRenderer
+		receiptPdfRenderer   ports.IReceiptPdfRenderer
+		blockStorage         ports.IBlockStorage
+	}
+)
+
+func NewTicketUseCase(
+	orderRepository ports.IOrderRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	ticketRenderer ports.ITicketRenderer,
+	receiptPdfRenderer ports.IReceiptPdfRenderer,
+	blockStorage ports.IBlockStorage,
+) ITicketUseCase {
+	return &ticketUseCase{
+		orderRepository:      orderRepository,
+		restaurantRepository: restaurantRepository,
+		ticketRenderer:       ticketRenderer,
+		receiptPdfRenderer:   receiptPdfRenderer,
+		blockStorage:         blockStorage,
+	}
+}
+
+func (u *ticketUseCase) KitchenTicket(restaurantId, orderId string, paper escpos.PaperWidth) (*escpos.Document, error) {
+	restaurant, order, err := u.load(restaurantId, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.ticketRenderer.KitchenTicket(restaurant, order, paper), nil
+}
+
+func (u *ticketUseCase) Receipt(restaurantId, orderId string, paper escpos.PaperWidth) (*escpos.Document, error) {
+	restaurant, order, err := u.load(restaurantId, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.ticketRenderer.Receipt(restaurant, order, paper), nil
+}
+
+func (u *ticketUseCase) ReceiptPdf(restaurantId, orderId string) ([]byte, error) {
+	restaurant, order, err := u.load(restaurantId, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	// o logo é só identidade visual: sem ele o recibo continua válido
+	var logo []byte
+	if restaurant.LogoUrl != "" {
+		logo, err = u.blockStorage.Get(restaurantLogoKey(restaurant.Id), dishBucket)
+		if err != nil {
+			log.Printf("⚠️ failed to load logo of restaurant %s for receipt: %v", restaurant.Id, err)
+			logo = nil
+		}
+	}
+
+	return u.receiptPdfRenderer.Render(restaurant, order, logo)
+}
+
+func (u *ticketUseCase) load(restaurantId, orderId string) (*aggregates.Restaurant, *aggregates.Order, error) {
+	order, err := u.orderRepository.FindById(orderId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order == nil || order.Restaurant.Id != restaurantId {
+		return nil, nil, ErrOrderNotFound
+	}
+
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if restaurant == nil {
+		return nil, nil, ErrRestaurantNotFound
+	}
+
+	return restaurant, order, nil
+}
@@ -0,0 +1,566 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/app/dtos"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	webhookdeliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/webhook_delivery_status"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrInvalidWebhookSubscription  = errors.New("invalid webhook subscription")
+	ErrWebhookDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrWebhookDeliveryNotDead      = errors.New("only dead webhook deliveries can be redelivered")
+)
+
+const (
+	// entregas com tentativa vencida são varridas nesse intervalo; eventos novos acordam o despacho na hora
+	webhookDispatchInterval = 15 * time.Second
+	webhookDispatchBatch    = 50
+	webhookMinSecretLength  = 16
+)
+
+type (
+	// WebhookSubscriptionPayload cria ou substitui a assinatura; Secret vazio gera um segredo
+	// na criação e mantém o atual na alteração
+	WebhookSubscriptionPayload struct {
+		Url             string                   `json:"url"`
+		Secret          string                   `json:"secret"`
+		Events          []abstractions.EventName `json:"events"`
+		Description     string                   `json:"description"`
+		Active          *bool                    `json:"active"`
+		ExpectedVersion int                      `json:"-"`
+	}
+
+	WebhookDeliveryFilterPayload struct {
+		SubscriptionId string
+		Status         webhookdeliverystatus.WebhookDeliveryStatus
+		Limit          int
+	}
+
+	IWebhookUseCase interface {
+		Find(restaurantId string) ([]aggregates.WebhookSubscription, error)
+		FindById(restaurantId, id string) (*aggregates.WebhookSubscription, error)
+		Create(actor types.Actor, restaurantId string, payload *WebhookSubscriptionPayload) (*aggregates.WebhookSubscription, error)
+		Update(actor types.Actor, restaurantId, id string, payload *WebhookSubscriptionPayload) (*aggregates.WebhookSubscription, error)
+		Delete(actor types.Actor, restaurantId, id string) error
+		// FindDeliveries traz o histórico de entregas; com status dead é a lista de mortas
+		FindDeliveries(restaurantId string, filter WebhookDeliveryFilterPayload) ([]aggregates.WebhookDelivery, error)
+		// SendTest envia um webhook.test na hora e devolve a entrega com o resultado
+		SendTest(restaurantId, id string) (*aggregates.WebhookDelivery, error)
+		Redeliver(actor types.Actor, restaurantId, deliveryId string) (*aggregates.WebhookDelivery, error)
+		// Listen enfileira os eventos do barramento e despacha as entregas pendentes; a função devolvida encerra os dois
+		Listen() func()
+	}
+
+	webhookUseCase struct {
+		webhookSubscriptionRepository ports.IWebhookSubscriptionRepository
+		webhookDeliveryRepository     ports.IWebhookDeliveryRepository
+		orderRepository               ports.IOrderRepository
+		productRepository             ports.IProductRepository
+		stockItemRepository           ports.IStockItemRepository
+		cashSessionRepository         ports.ICashSessionRepository
+		restaurantRepository          ports.IRestaurantRepository
+		sender                        ports.IWebhookSender
+		eventBus                      ports.IEventBus
+		wake                          chan struct{}
+	}
+)
+
+func NewWebhookUseCase(
+	webhookSubscriptionRepository ports.IWebhookSubscriptionRepository,
+	webhookDeliveryRepository ports.IWebhookDeliveryRepository,
+	orderRepository ports.IOrderRepository,
+	productRepository ports.IProductRepository,
+	stockItemRepository ports.IStockItemRepository,
+	cashSessionRepository ports.ICashSessionRepository,
+	restaurantRepository ports.IRestaurantRepository,
+	sender ports.IWebhookSender,
+	eventBus ports.IEventBus,
+) IWebhookUseCase {
+	return &webhookUseCase{
+		webhookSubscriptionRepository: webhookSubscriptionRepository,
+		webhookDeliveryRepository:     webhookDeliveryRepository,
+		orderRepository:               orderRepository,
+		productRepository:             productRepository,
+		stockItemRepository:           stockItemRepository,
+		cashSessionRepository:         cashSessionRepository,
+		restaurantRepository:          restaurantRepository,
+		sender:                        sender,
+		eventBus:                      eventBus,
+		wake:                          make(chan struct{}, 1),
+	}
+}
+
+func (u *webhookUseCase) Find(restaurantId string) ([]aggregates.WebhookSubscription, error) {
+	return u.webhookSubscriptionRepository.FindByRestaurantId(restaurantId)
+}
+
+func (u *webhookUseCase) FindById(restaurantId, id string) (*aggregates.WebhookSubscription, error) {
+	subscription, err := u.webhookSubscriptionRepository.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil || subscription.Restaurant.Id != restaurantId {
+		return nil, ErrWebhookSubscriptionNotFound
+	}
+
+	return subscription, nil
+}
+
+func (u *webhookUseCase) Create(actor types.Actor, restaurantId string, payload *WebhookSubscriptionPayload) (*aggregates.WebhookSubscription, error) {
+	restaurant, err := u.restaurantRepository.FindById(restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	if restaurant == nil {
+		return nil, ErrRestaurantNotFound
+	}
+
+	if err := validateWebhookSubscription(payload); err != nil {
+		return nil, err
+	}
+
+	secret := payload.Secret
+	if secret == "" {
+		secret, err = newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	subscription := aggregates.NewWebhookSubscription(restaurant.Id, payload.Url, secret, payload.Events)
+	subscription.Description = payload.Description
+	if payload.Active != nil {
+		subscription.Active = *payload.Active
+	}
+
+	err = u.audit(actor, aggregates.AuditActionCreate, nil, subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.webhookSubscriptionRepository.Create(subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (u *webhookUseCase) Update(actor types.Actor, restaurantId, id string, payload *WebhookSubscriptionPayload) (*aggregates.WebhookSubscription, error) {
+	subscription, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkExpectedVersion(aggregates.WebhookSubscriptionAggregateType, subscription.Id, subscription.Version, payload.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateWebhookSubscription(payload); err != nil {
+		return nil, err
+	}
+
+	before := *subscription
+	subscription.Url = payload.Url
+	subscription.Events = payload.Events
+	subscription.Description = payload.Description
+	if payload.Secret != "" {
+		subscription.Secret = payload.Secret
+	}
+	if payload.Active != nil {
+		subscription.Active = *payload.Active
+	}
+	subscription.UpdatedAt = time.Now()
+
+	err = u.audit(actor, aggregates.AuditActionUpdate, &before, subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.webhookSubscriptionRepository.Update(subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// Delete apaga a assinatura junto com o histórico de entregas
+func (u *webhookUseCase) Delete(actor types.Actor, restaurantId, id string) error {
+	subscription, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return err
+	}
+
+	err = u.audit(actor, aggregates.AuditActionDelete, subscription, nil)
+	if err != nil {
+		return err
+	}
+
+	return u.webhookSubscriptionRepository.Delete(subscription)
+}
+
+func (u *webhookUseCase) FindDeliveries(restaurantId string, filter WebhookDeliveryFilterPayload) ([]aggregates.WebhookDelivery, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, fmt.Errorf("%w: status must be pending, delivered or dead", ErrInvalidWebhookSubscription)
+	}
+
+	if filter.SubscriptionId != "" {
+		if _, err := u.FindById(restaurantId, filter.SubscriptionId); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.webhookDeliveryRepository.Find(ports.WebhookDeliveryQuery{
+		RestaurantId:   restaurantId,
+		SubscriptionId: filter.SubscriptionId,
+		Status:         filter.Status,
+		Limit:          filter.Limit,
+	})
+}
+
+// SendTest vale também para assinaturas desativadas, para conferir a URL antes de ligá-las;
+// se falhar, a entrega segue para as novas tentativas como qualquer outra
+func (u *webhookUseCase) SendTest(restaurantId, id string) (*aggregates.WebhookDelivery, error) {
+	subscription, err := u.FindById(restaurantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	eventId := uuid.NewString()
+	payload, err := json.Marshal(dtos.WebhookEventDto{
+		Id:           eventId,
+		Event:        string(aggregates.WebhookTestEvent),
+		OccurredAt:   time.Now(),
+		RestaurantId: subscription.Restaurant.Id,
+		Data:         map[string]string{"message": "Evento de teste do Marmitech"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := aggregates.NewWebhookDelivery(subscription, eventId, aggregates.WebhookTestEvent, string(payload))
+	err = u.webhookDeliveryRepository.Create(delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.send(subscription, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (u *webhookUseCase) Redeliver(actor types.Actor, restaurantId, deliveryId string) (*aggregates.WebhookDelivery, error) {
+	delivery, err := u.webhookDeliveryRepository.FindById(deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil || delivery.RestaurantId != restaurantId {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	before := *delivery
+	if !delivery.Redeliver(time.Now()) {
+		return nil, ErrWebhookDeliveryNotDead
+	}
+
+	err = recordAudit(delivery, actor, restaurantId, aggregates.WebhookDeliveryAggregateType, aggregates.AuditActionUpdate, &before, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.webhookDeliveryRepository.Update(delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	u.wakeDispatcher()
+	return delivery, nil
+}
+
+// Listen cria as entregas a partir da outbox: um evento que falhar ao entrar na fila volta
+// na próxima leitura, em vez de se perder antes de virar uma entrega
+func (u *webhookUseCase) Listen() func() {
+	stopConsuming := u.eventBus.Consume("webhooks", func(event abstractions.DomainEvent) error {
+		if !aggregates.IsWebhookEvent(event.Name) {
+			return nil
+		}
+
+		if err := u.enqueue(event); err != nil {
+			return err
+		}
+		u.wakeDispatcher()
+		return nil
+	})
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(webhookDispatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			case <-u.wake:
+			}
+			u.dispatchDue()
+		}
+	}()
+
+	return func() {
+		close(done)
+		stopConsuming()
+	}
+}
+
+// enqueue cria uma entrega por assinatura interessada; o corpo é montado uma vez só, com o
+// id do evento da outbox, que também impede entregas em dobro quando o evento chega de novo
+func (u *webhookUseCase) enqueue(event abstractions.DomainEvent) error {
+	restaurantId, data, err := u.resolve(event)
+	if err != nil {
+		return err
+	}
+	if restaurantId == "" {
+		return nil
+	}
+
+	subscriptions, err := u.webhookSubscriptionRepository.FindByRestaurantId(restaurantId)
+	if err != nil {
+		return err
+	}
+	subscriptions = slices.DeleteFunc(subscriptions, func(subscription aggregates.WebhookSubscription) bool {
+		return !subscription.Subscribes(event.Name)
+	})
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	eventId := event.Id
+	payload, err := json.Marshal(dtos.WebhookEventDto{
+		Id:           eventId,
+		Event:        string(event.Name),
+		OccurredAt:   event.OccuredAt,
+		RestaurantId: restaurantId,
+		Data:         data,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range subscriptions {
+		delivery := aggregates.NewWebhookDelivery(&subscriptions[i], eventId, event.Name, string(payload))
+		if err := u.webhookDeliveryRepository.Create(delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolve relê o agregado do evento, que só carrega o id; restaurantId vazio quando ele não existe mais
+func (u *webhookUseCase) resolve(event abstractions.DomainEvent) (string, any, error) {
+	switch {
+	case strings.HasPrefix(string(event.Name), "order."):
+		order, err := u.orderRepository.FindById(event.AggregateId)
+		if err != nil || order == nil {
+			return "", nil, err
+		}
+		return order.Restaurant.Id, order, nil
+	case strings.HasPrefix(string(event.Name), "product."):
+		product, err := u.productRepository.FindById(event.AggregateId)
+		if err != nil || product == nil {
+			return "", nil, err
+		}
+		return product.Restaurant.Id, product, nil
+	case event.Name == aggregates.StockItemLowEvent:
+		item, err := u.stockItemRepository.FindById(event.AggregateId)
+		if err != nil || item == nil {
+			return "", nil, err
+		}
+		return item.Restaurant.Id, item, nil
+	case event.Name == aggregates.CashSessionClosedEvent:
+		session, err := u.cashSessionRepository.FindById(event.AggregateId)
+		if err != nil || session == nil {
+			return "", nil, err
+		}
+		return session.Restaurant.Id, session, nil
+	}
+
+	return "", nil, nil
+}
+
+// dispatchDue envia as entregas vencidas em paralelo, lote a lote, até esvaziar a fila
+func (u *webhookUseCase) dispatchDue() {
+	for {
+		deliveries, err := u.webhookDeliveryRepository.FindDue(time.Now(), webhookDispatchBatch)
+		if err != nil {
+			log.Printf("⚠️ failed to load pending webhook deliveries: %v", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery *aggregates.WebhookDelivery) {
+				defer wg.Done()
+				if err := u.attempt(delivery); err != nil {
+					log.Printf("⚠️ failed to dispatch webhook delivery %s: %v", delivery.Id, err)
+				}
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		if len(deliveries) < webhookDispatchBatch {
+			return
+		}
+	}
+}
+
+func (u *webhookUseCase) attempt(delivery *aggregates.WebhookDelivery) error {
+	subscription, err := u.webhookSubscriptionRepository.FindById(delivery.SubscriptionId)
+	if err != nil {
+		return err
+	}
+	if subscription == nil {
+		return nil
+	}
+
+	// assinatura desligada não recebe; a entrega vai direto para as mortas e pode ser reenviada depois
+	if !subscription.Active && delivery.Event != aggregates.WebhookTestEvent {
+		delivery.Abandon("subscription is inactive")
+		return u.record(delivery)
+	}
+
+	return u.send(subscription, delivery)
+}
+
+func (u *webhookUseCase) send(subscription *aggregates.WebhookSubscription, delivery *aggregates.WebhookDelivery) error {
+	statusCode, err := u.sender.Send(ports.WebhookRequest{
+		Url:        subscription.Url,
+		Secret:     subscription.Secret,
+		DeliveryId: delivery.Id,
+		Event:      delivery.Event,
+		Payload:    []byte(delivery.Payload),
+	})
+	delivery.RecordAttempt(statusCode, err, time.Now())
+
+	if delivery.Status == webhookdeliverystatus.DEAD {
+		log.Printf("⚠️ webhook delivery %s to %s is dead after %d attempts: %s", delivery.Id, subscription.Url, delivery.Attempts, delivery.LastError)
+	}
+
+	return u.record(delivery)
+}
+
+// record grava o resultado; conflito de versão quer dizer que outra instância já tratou a entrega
+func (u *webhookUseCase) record(delivery *aggregates.WebhookDelivery) error {
+	err := u.webhookDeliveryRepository.Update(delivery)
+
+	var conflict *ports.ConcurrencyConflictError
+	if errors.As(err, &conflict) {
+		return nil
+	}
+	return err
+}
+
+func (u *webhookUseCase) wakeDispatcher() {
+	select {
+	case u.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (u *webhookUseCase) audit(actor types.Actor, action aggregates.AuditAction, before, after *aggregates.WebhookSubscription) error {
+	subscription := after
+	if subscription == nil {
+		subscription = before
+	}
+
+	return recordAudit(
+		subscription,
+		actor,
+		subscription.Restaurant.Id,
+		aggregates.WebhookSubscriptionAggregateType,
+		action,
+		withoutSecret(before),
+		withoutSecret(after),
+	)
+}
+
+// withoutSecret mascara o segredo para que ele não fique no log de auditoria; a troca
+// de segredo continua aparecendo no diff
+func withoutSecret(subscription *aggregates.WebhookSubscription) *aggregates.WebhookSubscription {
+	if subscription == nil {
+		return nil
+	}
+
+	masked := *subscription
+	masked.Secret = fmt.Sprintf("%x", sha256.Sum256([]byte(subscription.Secret)))[:12]
+	return &masked
+}
+
+// validateWebhookSubscription normaliza o payload e remove eventos repetidos
+func validateWebhookSubscription(payload *WebhookSubscriptionPayload) error {
+	payload.Url = strings.TrimSpace(payload.Url)
+	payload.Description = strings.TrimSpace(payload.Description)
+
+	target, err := url.Parse(payload.Url)
+	if err != nil || target.Scheme != "https" || target.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute https url", ErrInvalidWebhookSubscription)
+	}
+	// endereços resolvidos por DNS são conferidos de novo pelo sender na hora da conexão
+	if ip := net.ParseIP(target.Hostname()); strings.EqualFold(target.Hostname(), "localhost") ||
+		(ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified())) {
+		return fmt.Errorf("%w: url must point to a public address", ErrInvalidWebhookSubscription)
+	}
+
+	if payload.Secret != "" && len(payload.Secret) < webhookMinSecretLength {
+		return fmt.Errorf("%w: secret must have at least %d characters", ErrInvalidWebhookSubscription, webhookMinSecretLength)
+	}
+
+	if len(payload.Events) == 0 {
+		return fmt.Errorf("%w: at least one event is required", ErrInvalidWebhookSubscription)
+	}
+	for _, event := range payload.Events {
+		if !aggregates.IsWebhookEvent(event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidWebhookSubscription, event)
+		}
+	}
+	slices.Sort(payload.Events)
+	payload.Events = slices.Compact(payload.Events)
+
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -27,6 +27,36 @@ type environtment struct {
 	JwtIssuer string `env:"JWT_ISSUER"`
 	JwtAudience string `env:"JWT_AUDIENCE"`
 	JwtExpirationMinutes int `env:"JWT_EXPIRATION_MINUTES"`
+	IdempotencyTtlMinutes int `env:"IDEMPOTENCY_TTL_MINUTES" default:"1440"`
+	// chave AES-256 em base64 (openssl rand -base64 32) que cifra segredos como a senha do certificado A1
+	SecretsEncryptionKey string `env:"SECRETS_ENCRYPTION_KEY"`
+	// por padrão aponta para o cmd/sefaz-stand-in, nunca para a SEFAZ real
+	SefazNfceAuthorizationUrl string `env:"SEFAZ_NFCE_AUTHORIZATION_URL" default:"http://localhost:8089"`
+	SefazNfceQrCodeUrl        string `env:"SEFAZ_NFCE_QRCODE_URL" default:"https://www.homologacao.nfce.fazenda.sp.gov.br/qrcode"`
+	SefazNfceConsultUrl       string `env:"SEFAZ_NFCE_CONSULT_URL" default:"https://www.homologacao.nfce.fazenda.sp.gov.br/consulta"`
+	// canais de notificação sem configuração ficam desligados; o fake só registra as mensagens no log
+	NotificationsFakeSink string `env:"NOTIFICATIONS_FAKE_SINK"`
+	SmtpHost              string `env:"SMTP_HOST"`
+	SmtpPort              string `env:"SMTP_PORT" default:"587"`
+	SmtpUser              string `env:"SMTP_USER"`
+	SmtpPass              string `env:"SMTP_PASS"`
+	SmtpFrom              string `env:"SMTP_FROM"`
+	WhatsAppApiUrl        string `env:"WHATSAPP_API_URL" default:"https://graph.facebook.com/v19.0"`
+	WhatsAppPhoneNumberId string `env:"WHATSAPP_PHONE_NUMBER_ID"`
+	WhatsAppAccessToken   string `env:"WHATSAPP_ACCESS_TOKEN"`
+	SmsApiUrl             string `env:"SMS_API_URL"`
+	SmsApiToken           string `env:"SMS_API_TOKEN"`
+	SmsSender             string `env:"SMS_SENDER"`
+	// sem client id a integração com o iFood fica desligada; em desenvolvimento a URL aponta para o cmd/ifood-stand-in
+	IfoodApiUrl       string `env:"IFOOD_API_URL" default:"https://merchant-api.ifood.com.br"`
+	IfoodClientId     string `env:"IFOOD_CLIENT_ID"`
+	IfoodClientSecret string `env:"IFOOD_CLIENT_SECRET"`
+	// vazio deixa a consulta de CEP só com a faixa de CEP por UF, sem rede
+	ViaCepUrl string `env:"VIACEP_URL" default:"https://viacep.com.br"`
+	// nominatim, google ou fake; sem URL é usado o serviço público do provedor
+	GeocoderProvider string `env:"GEOCODER_PROVIDER" default:"nominatim"`
+	GeocoderUrl      string `env:"GEOCODER_URL"`
+	GeocoderApiKey   string `env:"GEOCODER_API_KEY"`
 }
 
 var Env environtment
@@ -39,6 +69,10 @@ func (e *environtment) IsAuthEnabled() bool {
 	return e.AuthEnabled == "true"
 }
 
+func (e *environtment) IsNotificationsFakeSink() bool {
+	return e.NotificationsFakeSink == "true"
+}
+
 func LoadEnvs() {
 	goEnv := os.Getenv("ENV")
 	if goEnv == "" {
@@ -4,24 +4,36 @@ type (
 	IAggreagateRoot interface {
 		IEntity
 
+		GetVersion() int
 		DomainEvents() []DomainEvent
 		ClearDomainEvents()
 		RaiseDomainEvent(event DomainEvent)
+		AuditRecords() []AuditRecord
+		ClearAuditRecords()
+		RecordAudit(record AuditRecord)
 	}
 
 	AggregateRoot struct {
 		Entity
 
-		Events []DomainEvent
+		// Version é incrementada a cada atualização persistida (controle de concorrência otimista)
+		Version int           `json:"version"`
+		Events  []DomainEvent `json:"-"`
+		Audits  []AuditRecord `json:"-"`
 	}
 )
 
 func NewAggregateRoot() AggregateRoot {
 	return AggregateRoot{
-		Entity: NewEntity(),
+		Entity:  NewEntity(),
+		Version: 1,
 	}
 }
 
+func (a *AggregateRoot) GetVersion() int {
+	return a.Version
+}
+
 func (a *AggregateRoot) DomainEvents() []DomainEvent {
 	return a.Events
 }
@@ -33,3 +45,15 @@ func (a *AggregateRoot) ClearDomainEvents() {
 func (a *AggregateRoot) RaiseDomainEvent(event DomainEvent) {
 	a.Events = append(a.Events, event)
 }
+
+func (a *AggregateRoot) AuditRecords() []AuditRecord {
+	return a.Audits
+}
+
+func (a *AggregateRoot) ClearAuditRecords() {
+	a.Audits = nil
+}
+
+func (a *AggregateRoot) RecordAudit(record AuditRecord) {
+	a.Audits = append(a.Audits, record)
+}
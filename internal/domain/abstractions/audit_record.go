@@ -0,0 +1,13 @@
+package abstractions
+
+import "github.com/PedroNetto404/marmitech-backend/pkg/types"
+
+// AuditRecord é uma alteração do agregado que o repositório grava no audit_log
+// na mesma transação que persiste o agregado, como os eventos de domínio
+type AuditRecord struct {
+	RestaurantId  string
+	Actor         types.Actor
+	AggregateType string
+	Action        string
+	Diff          types.JsonDiff
+}
@@ -1,10 +1,16 @@
 package abstractions
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type EventName string
 
 type DomainEvent struct {
+	// Id identifica o evento na outbox; consumidores usam para não processar o mesmo evento duas vezes
+	Id          string    `json:"id"`
 	Name        EventName `json:"event_type"`   
 	AggregateId string    `json:"aggregate_id"` 
 	OccuredAt   time.Time `json:"occurred_at"`  
@@ -13,6 +19,7 @@ type DomainEvent struct {
 
 func NewDomainEvent(name EventName, aggregateId string) DomainEvent {
 	return DomainEvent{
+		Id:          uuid.NewString(),
 		Name:        name,
 		AggregateId: aggregateId,
 		OccuredAt:   time.Now(),
@@ -0,0 +1,81 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+const (
+	RestaurantAggregateType              = "restaurant"
+	CategoryAggregateType                = "category"
+	ProductAggregateType                 = "product"
+	DishAggregateType                    = "dish"
+	CustomerAggregateType                = "customer"
+	UserAggregateType                    = "user"
+	OrderAggregateType                   = "order"
+	CustomerTabAggregateType             = "customer_tab"
+	FiscalProfileAggregateType           = "fiscal_profile"
+	FiscalDocumentAggregateType          = "fiscal_document"
+	PromotionAggregateType               = "promotion"
+	LoyaltyProgramAggregateType          = "loyalty_program"
+	LoyaltyAccountAggregateType          = "loyalty_account"
+	StockItemAggregateType               = "stock_item"
+	RecipeAggregateType                  = "recipe"
+	MenuAggregateType                    = "menu"
+	CashSessionAggregateType             = "cash_session"
+	SupplierAggregateType                = "supplier"
+	BillAggregateType                    = "bill"
+	NotificationPreferencesAggregateType = "notification_preferences"
+	NotificationAggregateType            = "notification"
+	WebhookSubscriptionAggregateType     = "webhook_subscription"
+	WebhookDeliveryAggregateType         = "webhook_delivery"
+	MarketplaceIntegrationAggregateType  = "marketplace_integration"
+	MarketplaceMappingAggregateType      = "marketplace_mapping"
+	MarketplaceOrderAggregateType        = "marketplace_order"
+	MarketplacePushAggregateType         = "marketplace_push"
+	DeliveryZoneAggregateType            = "delivery_zone"
+	DriverAggregateType                  = "driver"
+	DeliveryRouteAggregateType           = "delivery_route"
+)
+
+type AuditLog struct {
+	abstractions.AggregateRoot
+
+	RestaurantId  string         `json:"restaurant_id"`
+	Actor         types.Actor    `json:"actor"`
+	AggregateType string         `json:"aggregate_type"`
+	AggregateId   string         `json:"aggregate_id"`
+	Action        AuditAction    `json:"action"`
+	Diff          types.JsonDiff `json:"diff"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+func NewAuditLog(
+	restaurantId string,
+	actor types.Actor,
+	aggregateType string,
+	aggregateId string,
+	action AuditAction,
+	diff types.JsonDiff,
+) *AuditLog {
+	return &AuditLog{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		RestaurantId:  restaurantId,
+		Actor:         actor,
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		Action:        action,
+		Diff:          diff,
+		CreatedAt:     time.Now(),
+	}
+}
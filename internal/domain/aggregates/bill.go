@@ -0,0 +1,143 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	billcategory "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_category"
+	billrecurrence "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_recurrence"
+	billstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_status"
+	paymentmethod "github.com/PedroNetto404/marmitech-backend/pkg/enums/payment_method"
+	"github.com/google/uuid"
+)
+
+type (
+	BillPayment struct {
+		Id            string                      `json:"id"`
+		BillId        string                      `json:"bill_id"`
+		Amount        float64                     `json:"amount"`
+		PaymentMethod paymentmethod.PaymentMethod `json:"payment_method"`
+		PaidAt        time.Time                   `json:"paid_at"`
+		CreatedBy     string                      `json:"created_by"`
+	}
+
+	// Bill é uma conta a pagar. DueDate é uma data de calendário, sem hora.
+	// As ocorrências de uma conta recorrente compartilham SeriesId, e a próxima só é
+	// gerada quando a atual é quitada; por isso a conta em aberto é sempre a última da série.
+	Bill struct {
+		abstractions.AggregateRoot
+		Restaurant  PartialRestaurant             `json:"restaurant"`
+		Supplier    *PartialSupplier              `json:"supplier,omitempty"`
+		Description string                        `json:"description"`
+		Category    billcategory.BillCategory     `json:"category"`
+		Amount      float64                       `json:"amount"`
+		PaidAmount  float64                       `json:"paid_amount"`
+		DueDate     time.Time                     `json:"due_date"`
+		Recurrence  billrecurrence.BillRecurrence `json:"recurrence"`
+		// dia de vencimento original da série, para o mensal não encurtar depois de fevereiro
+		DueDay    int                   `json:"due_day"`
+		SeriesId  string                `json:"series_id"`
+		Status    billstatus.BillStatus `json:"status"`
+		Payments  []BillPayment         `json:"payments"`
+		Note      string                `json:"note,omitempty"`
+		CreatedAt time.Time             `json:"created_at"`
+		UpdatedAt time.Time             `json:"updated_at"`
+	}
+)
+
+func NewBill(
+	restaurantId string,
+	description string,
+	category billcategory.BillCategory,
+	amount float64,
+	dueDate time.Time,
+	recurrence billrecurrence.BillRecurrence,
+) *Bill {
+	now := time.Now()
+	bill := &Bill{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Description:   description,
+		Category:      category,
+		Amount:        roundCents(amount),
+		DueDate:       dueDate,
+		Recurrence:    recurrence,
+		DueDay:        dueDate.Day(),
+		Status:        billstatus.OPEN,
+		Payments:      make([]BillPayment, 0),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	bill.SeriesId = bill.Id
+	return bill
+}
+
+// Balance é o que falta pagar
+func (b *Bill) Balance() float64 {
+	return roundCents(max(b.Amount-b.PaidAmount, 0))
+}
+
+func (b *Bill) IsOverdue(today time.Time) bool {
+	return b.Status == billstatus.OPEN && b.DueDate.Before(today)
+}
+
+// Pay registra um pagamento, parcial ou total; nil se a conta não está em aberto
+// ou o valor passa do saldo
+func (b *Bill) Pay(amount float64, method paymentmethod.PaymentMethod, paidAt time.Time, createdBy string) *BillPayment {
+	amount = roundCents(amount)
+	if b.Status != billstatus.OPEN || amount <= 0 || amount > b.Balance() {
+		return nil
+	}
+
+	b.Payments = append(b.Payments, BillPayment{
+		Id:            uuid.NewString(),
+		BillId:        b.Id,
+		Amount:        amount,
+		PaymentMethod: method,
+		PaidAt:        paidAt,
+		CreatedBy:     createdBy,
+	})
+	b.PaidAmount = roundCents(b.PaidAmount + amount)
+	if b.Balance() == 0 {
+		b.Status = billstatus.PAID
+	}
+	b.UpdatedAt = time.Now()
+	return &b.Payments[len(b.Payments)-1]
+}
+
+func (b *Bill) Cancel() bool {
+	if b.Status != billstatus.OPEN {
+		return false
+	}
+
+	b.Status = billstatus.CANCELLED
+	b.UpdatedAt = time.Now()
+	return true
+}
+
+// Next gera a próxima ocorrência da série; nil quando a conta não se repete
+func (b *Bill) Next() *Bill {
+	if b.Recurrence == billrecurrence.NONE {
+		return nil
+	}
+
+	next := NewBill(b.Restaurant.Id, b.Description, b.Category, b.Amount, b.Recurrence.Next(b.DueDate, b.DueDay), b.Recurrence)
+	next.Supplier = b.Supplier
+	next.Note = b.Note
+	next.DueDay = b.DueDay
+	next.SeriesId = b.SeriesId
+	return next
+}
+
+// UpcomingDueDates projeta os vencimentos das ocorrências ainda não geradas até until, inclusive
+func (b *Bill) UpcomingDueDates(until time.Time) []time.Time {
+	dates := make([]time.Time, 0)
+	if b.Status != billstatus.OPEN || b.Recurrence == billrecurrence.NONE {
+		return dates
+	}
+
+	for date := b.Recurrence.Next(b.DueDate, b.DueDay); !date.After(until); date = b.Recurrence.Next(date, b.DueDay) {
+		dates = append(dates, date)
+	}
+	return dates
+}
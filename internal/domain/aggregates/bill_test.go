@@ -0,0 +1,40 @@
+package aggregates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	billcategory "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_category"
+	billrecurrence "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_recurrence"
+	billstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_status"
+	paymentmethod "github.com/PedroNetto404/marmitech-backend/pkg/enums/payment_method"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayingRecurringBillGeneratesNextOccurrence(t *testing.T) {
+	// arrange
+	dueDate := time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)
+	bill := aggregates.NewBill("restaurant", "Aluguel", billcategory.RENT, 3000, dueDate, billrecurrence.MONTHLY)
+
+	// act
+	partial := bill.Pay(1000, paymentmethod.PIX, dueDate, "gerente@marmitech.com")
+	exceeding := bill.Pay(2500, paymentmethod.PIX, dueDate, "gerente@marmitech.com")
+	bill.Pay(2000, paymentmethod.PIX, dueDate, "gerente@marmitech.com")
+	next := bill.Next()
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NotNil(partial)
+	assert.Nil(exceeding, "passa do saldo da conta")
+	assert.Equal(billstatus.PAID, bill.Status)
+	assert.Equal(time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC), next.DueDate, "limitado ao fim de fevereiro")
+	assert.Equal(bill.SeriesId, next.SeriesId)
+	assert.Equal(billstatus.OPEN, next.Status)
+	assert.Equal(
+		[]time.Time{time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		next.UpcomingDueDates(time.Date(2026, time.April, 15, 0, 0, 0, 0, time.UTC)),
+		"volta ao dia 31 em março",
+	)
+}
@@ -0,0 +1,161 @@
+package aggregates
+
+import (
+	"sort"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	cashsessionstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/cash_session_status"
+	paymentmethod "github.com/PedroNetto404/marmitech-backend/pkg/enums/payment_method"
+	"github.com/google/uuid"
+)
+
+const CashSessionClosedEvent abstractions.EventName = "cash_session.closed"
+
+type CashMovementType string
+
+const (
+	// sangria: dinheiro retirado da gaveta
+	CashWithdrawal CashMovementType = "withdrawal"
+	// suprimento: troco colocado na gaveta
+	CashDeposit CashMovementType = "deposit"
+)
+
+func (t CashMovementType) IsValid() bool {
+	return t == CashWithdrawal || t == CashDeposit
+}
+
+type (
+	CashMovement struct {
+		Id        string           `json:"id"`
+		SessionId string           `json:"session_id"`
+		Type      CashMovementType `json:"type"`
+		Amount    float64          `json:"amount"`
+		Reason    string           `json:"reason"`
+		CreatedBy string           `json:"created_by"`
+		CreatedAt time.Time        `json:"created_at"`
+	}
+
+	// CashCount confronta, no fechamento, o que o sistema esperava com o que o operador contou
+	CashCount struct {
+		PaymentMethod paymentmethod.PaymentMethod `json:"payment_method"`
+		Expected      float64                     `json:"expected"`
+		Counted       float64                     `json:"counted"`
+		Difference    float64                     `json:"difference"`
+	}
+
+	// CashSession é o turno de um operador no caixa, da abertura com o fundo de troco ao fechamento
+	CashSession struct {
+		abstractions.AggregateRoot
+		Restaurant   PartialRestaurant                   `json:"restaurant"`
+		Operator     string                              `json:"operator"`
+		Status       cashsessionstatus.CashSessionStatus `json:"status"`
+		OpeningFloat float64                             `json:"opening_float"`
+		Movements    []CashMovement                      `json:"movements"`
+		Counts       []CashCount                         `json:"counts"`
+		Note         string                              `json:"note,omitempty"`
+		OpenedAt     time.Time                           `json:"opened_at"`
+		ClosedAt     *time.Time                          `json:"closed_at,omitempty"`
+		ClosedBy     string                              `json:"closed_by,omitempty"`
+	}
+)
+
+func NewCashSession(restaurantId, operator string, openingFloat float64, at time.Time) *CashSession {
+	return &CashSession{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Operator:      operator,
+		Status:        cashsessionstatus.OPEN,
+		OpeningFloat:  roundCents(openingFloat),
+		Movements:     make([]CashMovement, 0),
+		Counts:        make([]CashCount, 0),
+		OpenedAt:      at,
+	}
+}
+
+func (s *CashSession) IsOpen() bool {
+	return s.Status == cashsessionstatus.OPEN
+}
+
+// Move lança uma sangria ou um suprimento; nil com a sessão fechada
+func (s *CashSession) Move(movementType CashMovementType, amount float64, reason, createdBy string, at time.Time) *CashMovement {
+	if !s.IsOpen() || amount <= 0 {
+		return nil
+	}
+
+	s.Movements = append(s.Movements, CashMovement{
+		Id:        uuid.NewString(),
+		SessionId: s.Id,
+		Type:      movementType,
+		Amount:    roundCents(amount),
+		Reason:    reason,
+		CreatedBy: createdBy,
+		CreatedAt: at,
+	})
+	return &s.Movements[len(s.Movements)-1]
+}
+
+// Close fecha o caixa às cegas: o operador informa o que contou sem ver o esperado, que só
+// aparece na conferência. sales são as vendas da sessão por forma de pagamento; o dinheiro
+// esperado soma ainda o fundo de troco e os suprimentos e desconta as sangrias.
+func (s *CashSession) Close(
+	sales map[paymentmethod.PaymentMethod]float64,
+	counted map[paymentmethod.PaymentMethod]float64,
+	closedBy string,
+	note string,
+	at time.Time,
+) bool {
+	if !s.IsOpen() {
+		return false
+	}
+
+	expected := make(map[paymentmethod.PaymentMethod]float64, len(sales)+1)
+	for method, amount := range sales {
+		expected[method] = amount
+	}
+	expected[paymentmethod.CASH] += s.OpeningFloat
+	for _, movement := range s.Movements {
+		if movement.Type == CashDeposit {
+			expected[paymentmethod.CASH] += movement.Amount
+		} else {
+			expected[paymentmethod.CASH] -= movement.Amount
+		}
+	}
+
+	methods := make([]paymentmethod.PaymentMethod, 0, len(expected))
+	for method := range expected {
+		methods = append(methods, method)
+	}
+	for method := range counted {
+		if _, ok := expected[method]; !ok {
+			methods = append(methods, method)
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i] < methods[j] })
+
+	s.Counts = make([]CashCount, 0, len(methods))
+	for _, method := range methods {
+		s.Counts = append(s.Counts, CashCount{
+			PaymentMethod: method,
+			Expected:      roundCents(expected[method]),
+			Counted:       roundCents(counted[method]),
+			Difference:    roundCents(counted[method] - expected[method]),
+		})
+	}
+
+	s.Status = cashsessionstatus.CLOSED
+	s.ClosedAt = &at
+	s.ClosedBy = closedBy
+	s.Note = note
+	s.RaiseDomainEvent(abstractions.NewDomainEvent(CashSessionClosedEvent, s.Id))
+	return true
+}
+
+// Difference é a quebra do caixa: positiva quando sobrou, negativa quando faltou
+func (s *CashSession) Difference() float64 {
+	difference := 0.0
+	for _, count := range s.Counts {
+		difference += count.Difference
+	}
+	return roundCents(difference)
+}
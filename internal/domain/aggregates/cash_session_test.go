@@ -0,0 +1,61 @@
+package aggregates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	cashsessionstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/cash_session_status"
+	paymentmethod "github.com/PedroNetto404/marmitech-backend/pkg/enums/payment_method"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlindCloseComparesCountedWithExpected(t *testing.T) {
+	// arrange
+	now := time.Now()
+	session := aggregates.NewCashSession("restaurant", "caixa@marmitech.com", 100, now)
+	session.Move(aggregates.CashWithdrawal, 150, "sangria para o cofre", "caixa@marmitech.com", now)
+	session.Move(aggregates.CashDeposit, 20, "troco", "caixa@marmitech.com", now)
+	sales := map[paymentmethod.PaymentMethod]float64{paymentmethod.CASH: 230.5, paymentmethod.PIX: 80}
+	counted := map[paymentmethod.PaymentMethod]float64{paymentmethod.CASH: 195, paymentmethod.PIX: 80}
+
+	// act
+	closed := session.Close(sales, counted, "caixa@marmitech.com", "", now)
+	movedAfterClose := session.Move(aggregates.CashDeposit, 10, "troco", "caixa@marmitech.com", now)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.True(closed)
+	assert.Equal(cashsessionstatus.CLOSED, session.Status)
+	assert.Nil(movedAfterClose, "caixa fechado não recebe movimentação")
+	assert.Len(session.Counts, 2)
+	assert.Equal(paymentmethod.CASH, session.Counts[0].PaymentMethod)
+	assert.Equal(200.5, session.Counts[0].Expected, "fundo de troco + vendas + suprimento - sangria")
+	assert.Equal(-5.5, session.Counts[0].Difference)
+	assert.Equal(0.0, session.Counts[1].Difference)
+	assert.Equal(-5.5, session.Difference())
+	assert.Len(session.DomainEvents(), 1)
+}
+
+func TestRegisterPaymentDoesNotExceedBalance(t *testing.T) {
+	// arrange
+	item := aggregates.NewOrderItem(aggregates.PartialProduct{Id: "p1", Name: "Marmita M"}, 22, 1, "", nil)
+	order := aggregates.NewOrder("restaurant", aggregates.PartialCustomer{}, []aggregates.OrderItem{item}, nil, "")
+	order.ClearDomainEvents()
+
+	// act
+	first := order.RegisterPayment("cash", 10, "", "session", time.Now())
+	exceeding := order.RegisterPayment("pix", 15, "", "session", time.Now())
+	second := order.RegisterPayment("pix", 12, "", "session", time.Now())
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NotNil(first)
+	assert.Nil(exceeding, "passa do que falta pagar")
+	assert.NotNil(second)
+	assert.Equal("session", second.CashSessionId)
+	assert.Equal(0.0, order.Balance())
+	assert.Len(order.DomainEvents(), 3, "duas atualizações e o pedido pago")
+}
@@ -20,7 +20,6 @@ type Category struct {
 func Newcategory(
 	name string,
 	restaurantId string,
-	priority int,
 ) *Category {
 	return &Category{
 		AggregateRoot: abstractions.NewAggregateRoot(),
@@ -28,7 +27,6 @@ func Newcategory(
 		Restaurant: PartialRestaurant{
 			Id: restaurantId,
 		},
-		Priority: priority,
-		Active:   true,
+		Active: true,
 	}
 }
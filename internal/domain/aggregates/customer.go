@@ -3,22 +3,44 @@ package aggregates
 import (
 	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
 	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/google/uuid"
 )
 
 type (
 	PartialCustomer struct {
-		Id        string
-		FirstName string
-		LastName  string
-		Email     string
+		Id        string `json:"id"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Email     string `json:"email"`
 	}
 
 	Customer struct {
 		abstractions.AggregateRoot
-		FirstName    string
-		LastName     string
-		ContactEmail string
-		ContactPhone string
-		Address      types.Address
+		FirstName    string        `json:"first_name"`
+		LastName     string        `json:"last_name"`
+		ContactEmail string        `json:"contact_email"`
+		ContactPhone string        `json:"contact_phone"`
+		Address      types.Address `json:"address"`
 	}
 )
+
+func NewCustomer(
+	firstName string,
+	lastName string,
+	contactEmail string,
+	contactPhone string,
+	address types.Address,
+) *Customer {
+	if address.Id == "" {
+		address.Id = uuid.NewString()
+	}
+
+	return &Customer{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		FirstName:     firstName,
+		LastName:      lastName,
+		ContactEmail:  contactEmail,
+		ContactPhone:  contactPhone,
+		Address:       address,
+	}
+}
@@ -0,0 +1,191 @@
+package aggregates
+
+import (
+	"slices"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/google/uuid"
+)
+
+type TabEntryType string
+
+const (
+	TabEntryDebit  TabEntryType = "debit"
+	TabEntryCredit TabEntryType = "credit"
+)
+
+type (
+	// TabEntry é um lançamento do fiado; valores sempre positivos, em centavos
+	TabEntry struct {
+		Id            string       `json:"id"`
+		TabId         string       `json:"tab_id"`
+		Type          TabEntryType `json:"type"`
+		Amount        int          `json:"amount"`
+		OrderId       string       `json:"order_id,omitempty"`
+		PaymentMethod string       `json:"payment_method,omitempty"`
+		Description   string       `json:"description"`
+		DueDate       *time.Time   `json:"due_date,omitempty"`
+		CreatedBy     string       `json:"created_by"`
+		CreatedAt     time.Time    `json:"created_at"`
+	}
+
+	// CustomerTab é a conta corrente (fiado) de um cliente em um restaurante.
+	// Balance é o saldo devedor e CreditLimit o teto desse saldo, ambos em centavos; limite zero não restringe.
+	CustomerTab struct {
+		abstractions.AggregateRoot
+		Restaurant  PartialRestaurant `json:"restaurant"`
+		Customer    PartialCustomer   `json:"customer"`
+		CreditLimit int               `json:"credit_limit"`
+		Balance     int               `json:"balance"`
+		// dia do mês em que vencem as compras do mês anterior
+		DueDay int `json:"due_day"`
+		// calculado na leitura: parte do saldo com vencimento já passado
+		OverdueAmount int       `json:"overdue_amount"`
+		CreatedAt     time.Time `json:"created_at"`
+		UpdatedAt     time.Time `json:"updated_at"`
+	}
+
+	TabStatement struct {
+		TabId          string     `json:"tab_id"`
+		Period         string     `json:"period"`
+		DueDate        time.Time  `json:"due_date"`
+		OpeningBalance int        `json:"opening_balance"`
+		TotalDebits    int        `json:"total_debits"`
+		TotalCredits   int        `json:"total_credits"`
+		ClosingBalance int        `json:"closing_balance"`
+		Entries        []TabEntry `json:"entries"`
+	}
+)
+
+func NewCustomerTab(
+	restaurantId string,
+	customerId string,
+	creditLimit int,
+	dueDay int,
+) *CustomerTab {
+	now := time.Now()
+	return &CustomerTab{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant: PartialRestaurant{
+			Id: restaurantId,
+		},
+		Customer: PartialCustomer{
+			Id: customerId,
+		},
+		CreditLimit: creditLimit,
+		DueDay:      dueDay,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func (t *CustomerTab) AvailableCredit() int {
+	return t.CreditLimit - t.Balance
+}
+
+func (t *CustomerTab) CanDebit(amount int) bool {
+	return t.CreditLimit == 0 || t.Balance+amount <= t.CreditLimit
+}
+
+func (t *CustomerTab) IsOverdue() bool {
+	return t.OverdueAmount > 0
+}
+
+// DueDateFor devolve o vencimento de uma compra feita em at: o DueDay do mês seguinte
+func (t *CustomerTab) DueDateFor(at time.Time) time.Time {
+	year, month, _ := at.Date()
+	return time.Date(year, month+1, t.DueDay, 0, 0, 0, 0, at.Location())
+}
+
+func (t *CustomerTab) Debit(amount int, orderId, description, createdBy string, at time.Time) *TabEntry {
+	dueDate := t.DueDateFor(at)
+	entry := t.newEntry(TabEntryDebit, amount, description, createdBy, at)
+	entry.OrderId = orderId
+	entry.DueDate = &dueDate
+
+	t.Balance += amount
+	return entry
+}
+
+// Credit abate o saldo; os pagamentos quitam primeiro os débitos mais antigos, então reduzem antes o que está vencido
+func (t *CustomerTab) Credit(amount int, paymentMethod, description, createdBy string, at time.Time) *TabEntry {
+	entry := t.newEntry(TabEntryCredit, amount, description, createdBy, at)
+	entry.PaymentMethod = paymentMethod
+
+	t.Balance -= amount
+	t.OverdueAmount = max(t.OverdueAmount-amount, 0)
+	return entry
+}
+
+func (t *CustomerTab) newEntry(entryType TabEntryType, amount int, description, createdBy string, at time.Time) *TabEntry {
+	t.UpdatedAt = at
+	return &TabEntry{
+		Id:          uuid.NewString(),
+		TabId:       t.Id,
+		Type:        entryType,
+		Amount:      amount,
+		Description: description,
+		CreatedBy:   createdBy,
+		CreatedAt:   at,
+	}
+}
+
+// OutstandingDebits devolve os débitos ainda não pagos, com Amount reduzido ao que resta de cada um.
+// Como os pagamentos quitam primeiro os débitos mais antigos, o saldo fica nos mais recentes.
+func (t *CustomerTab) OutstandingDebits(entries []TabEntry) []TabEntry {
+	debits := make([]TabEntry, 0)
+	remaining := t.Balance
+	for i := len(entries) - 1; i >= 0 && remaining > 0; i-- {
+		if entries[i].Type != TabEntryDebit {
+			continue
+		}
+
+		debit := entries[i]
+		debit.Amount = min(debit.Amount, remaining)
+		remaining -= debit.Amount
+		debits = append(debits, debit)
+	}
+
+	slices.Reverse(debits)
+	return debits
+}
+
+// NewTabStatement monta o extrato do mês a partir dos lançamentos da conta até o fim do período
+func NewTabStatement(tab *CustomerTab, entries []TabEntry, year int, month time.Month) *TabStatement {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 1, 0)
+
+	statement := &TabStatement{
+		TabId:   tab.Id,
+		Period:  start.Format("2006-01"),
+		DueDate: tab.DueDateFor(start),
+		Entries: make([]TabEntry, 0),
+	}
+
+	for _, entry := range entries {
+		if !entry.CreatedAt.Before(end) {
+			continue
+		}
+
+		amount := entry.Amount
+		if entry.Type == TabEntryCredit {
+			amount = -amount
+		}
+
+		if entry.CreatedAt.Before(start) {
+			statement.OpeningBalance += amount
+			continue
+		}
+
+		if entry.Type == TabEntryDebit {
+			statement.TotalDebits += entry.Amount
+		} else {
+			statement.TotalCredits += entry.Amount
+		}
+		statement.Entries = append(statement.Entries, entry)
+	}
+
+	statement.ClosingBalance = statement.OpeningBalance + statement.TotalDebits - statement.TotalCredits
+	return statement
+}
@@ -0,0 +1,75 @@
+package aggregates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomerTabDebitDueNextMonth(t *testing.T) {
+	// arrange
+	tab := aggregates.NewCustomerTab("restaurant", "customer", 50000, 5)
+	at := time.Date(2025, time.December, 20, 12, 0, 0, 0, time.Local)
+
+	// act
+	entry := tab.Debit(2500, "order", "Marmita P", "caixa@marmitech.com", at)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(2500, tab.Balance)
+	assert.Equal(time.Date(2026, time.January, 5, 0, 0, 0, 0, time.Local), *entry.DueDate, "vence no dia 5 do mês seguinte")
+	assert.False(tab.CanDebit(48000), "ultrapassaria o limite de crédito")
+}
+
+func TestCustomerTabStatement(t *testing.T) {
+	// arrange
+	tab := aggregates.NewCustomerTab("restaurant", "customer", 0, 10)
+	september := time.Date(2025, time.September, 15, 12, 0, 0, 0, time.Local)
+	october := time.Date(2025, time.October, 3, 12, 0, 0, 0, time.Local)
+	november := time.Date(2025, time.November, 1, 12, 0, 0, 0, time.Local)
+
+	entries := []aggregates.TabEntry{
+		*tab.Debit(3000, "", "Marmita G", "", september),
+		*tab.Credit(1000, "pix", "", "", october),
+		*tab.Debit(2000, "", "Marmita P", "", october),
+		*tab.Debit(1500, "", "Marmita P", "", november),
+	}
+
+	// act
+	statement := aggregates.NewTabStatement(tab, entries, 2025, time.October)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal("2025-10", statement.Period)
+	assert.Equal(3000, statement.OpeningBalance)
+	assert.Equal(2000, statement.TotalDebits)
+	assert.Equal(1000, statement.TotalCredits)
+	assert.Equal(4000, statement.ClosingBalance)
+	assert.Len(statement.Entries, 2, "apenas os lançamentos de outubro")
+}
+
+func TestCustomerTabOutstandingDebitsKeepNewestDebits(t *testing.T) {
+	// arrange
+	tab := aggregates.NewCustomerTab("restaurant", "customer", 0, 10)
+	at := time.Date(2025, time.September, 15, 12, 0, 0, 0, time.Local)
+
+	entries := []aggregates.TabEntry{
+		*tab.Debit(3000, "", "Marmita G", "", at),
+		*tab.Debit(2000, "", "Marmita P", "", at.AddDate(0, 1, 0)),
+		*tab.Credit(4000, "pix", "", "", at.AddDate(0, 1, 5)),
+	}
+
+	// act
+	debits := tab.OutstandingDebits(entries)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Len(debits, 1, "o pagamento quitou o débito mais antigo")
+	assert.Equal(1000, debits[0].Amount)
+	assert.Equal("Marmita P", debits[0].Description)
+}
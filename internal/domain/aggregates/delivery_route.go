@@ -0,0 +1,157 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	deliveryroutestatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_route_status"
+	deliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_status"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/google/uuid"
+)
+
+type (
+	// DeliveryStop é a entrega de um pedido dentro da rota; a prova é a foto, o código do cliente ou os dois
+	DeliveryStop struct {
+		Id            string                        `json:"id"`
+		Sequence      int                           `json:"sequence"`
+		OrderId       string                        `json:"order_id"`
+		DeliveryId    string                        `json:"delivery_id"`
+		Address       types.Address                 `json:"address"`
+		Fee           float64                       `json:"fee"`
+		Status        deliverystatus.DeliveryStatus `json:"status"`
+		ProofPhotoUrl string                        `json:"proof_photo_url,omitempty"`
+		CodeConfirmed bool                          `json:"code_confirmed"`
+		DeliveredAt   *time.Time                    `json:"delivered_at,omitempty"`
+	}
+
+	// DeliveryRoute agrupa os pedidos que um entregador leva na mesma saída, na ordem das paradas
+	DeliveryRoute struct {
+		abstractions.AggregateRoot
+		Restaurant  PartialRestaurant                       `json:"restaurant"`
+		Driver      PartialDriver                           `json:"driver"`
+		Status      deliveryroutestatus.DeliveryRouteStatus `json:"status"`
+		Stops       []DeliveryStop                          `json:"stops"`
+		AssignedBy  string                                  `json:"assigned_by"`
+		CreatedAt   time.Time                               `json:"created_at"`
+		AcceptedAt  *time.Time                              `json:"accepted_at,omitempty"`
+		PickedUpAt  *time.Time                              `json:"picked_up_at,omitempty"`
+		CompletedAt *time.Time                              `json:"completed_at,omitempty"`
+		UpdatedAt   time.Time                               `json:"updated_at"`
+	}
+)
+
+func NewDeliveryRoute(restaurantId string, driver PartialDriver, assignedBy string) *DeliveryRoute {
+	now := time.Now()
+	return &DeliveryRoute{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Driver:        driver,
+		Status:        deliveryroutestatus.ASSIGNED,
+		Stops:         make([]DeliveryStop, 0),
+		AssignedBy:    assignedBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// AddStop inclui o pedido como próxima parada; falso para pedidos sem entrega, cancelados,
+// concluídos ou já incluídos na rota
+func (r *DeliveryRoute) AddStop(order *Order) bool {
+	if order.Delivery == nil || !order.Status.IsInKitchen() || r.FindStopByOrder(order.Id) != nil {
+		return false
+	}
+
+	r.Stops = append(r.Stops, DeliveryStop{
+		Id:         uuid.NewString(),
+		Sequence:   len(r.Stops) + 1,
+		OrderId:    order.Id,
+		DeliveryId: order.Delivery.Id,
+		Address:    order.Delivery.Address,
+		Fee:        order.Delivery.Fee,
+		Status:     deliverystatus.ASSIGNED,
+	})
+	return true
+}
+
+func (r *DeliveryRoute) FindStop(stopId string) *DeliveryStop {
+	for i := range r.Stops {
+		if r.Stops[i].Id == stopId {
+			return &r.Stops[i]
+		}
+	}
+	return nil
+}
+
+func (r *DeliveryRoute) FindStopByOrder(orderId string) *DeliveryStop {
+	for i := range r.Stops {
+		if r.Stops[i].OrderId == orderId {
+			return &r.Stops[i]
+		}
+	}
+	return nil
+}
+
+func (r *DeliveryRoute) Accept(at time.Time) bool {
+	if r.Status != deliveryroutestatus.ASSIGNED {
+		return false
+	}
+
+	r.Status = deliveryroutestatus.ACCEPTED
+	r.AcceptedAt = &at
+	r.UpdatedAt = at
+	return true
+}
+
+// PickUp registra a saída do entregador com todos os pedidos da rota
+func (r *DeliveryRoute) PickUp(at time.Time) bool {
+	if r.Status != deliveryroutestatus.ACCEPTED {
+		return false
+	}
+
+	r.Status = deliveryroutestatus.IN_TRANSIT
+	r.PickedUpAt = &at
+	for i := range r.Stops {
+		r.Stops[i].Status = deliverystatus.IN_TRANSIT
+	}
+	r.UpdatedAt = at
+	return true
+}
+
+// Deliver conclui a parada com a prova de entrega; a rota termina junto com a última parada.
+// Devolve nil quando a parada não existe ou não está a caminho
+func (r *DeliveryRoute) Deliver(stopId, proofPhotoUrl string, codeConfirmed bool, at time.Time) *DeliveryStop {
+	stop := r.FindStop(stopId)
+	if r.Status != deliveryroutestatus.IN_TRANSIT || stop == nil || stop.Status != deliverystatus.IN_TRANSIT {
+		return nil
+	}
+
+	stop.Status = deliverystatus.DELIVERED
+	stop.ProofPhotoUrl = proofPhotoUrl
+	stop.CodeConfirmed = codeConfirmed
+	stop.DeliveredAt = &at
+	r.UpdatedAt = at
+
+	for _, other := range r.Stops {
+		if other.Status != deliverystatus.DELIVERED {
+			return stop
+		}
+	}
+	r.Status = deliveryroutestatus.COMPLETED
+	r.CompletedAt = &at
+	return stop
+}
+
+// Cancel desfaz a rota antes da retirada; depois dela cada parada precisa ser entregue
+func (r *DeliveryRoute) Cancel(at time.Time) bool {
+	if r.Status != deliveryroutestatus.ASSIGNED && r.Status != deliveryroutestatus.ACCEPTED {
+		return false
+	}
+
+	r.Status = deliveryroutestatus.CANCELLED
+	for i := range r.Stops {
+		r.Stops[i].Status = deliverystatus.PENDING
+	}
+	r.UpdatedAt = at
+	return true
+}
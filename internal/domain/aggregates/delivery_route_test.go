@@ -0,0 +1,78 @@
+package aggregates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	deliveryroutestatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_route_status"
+	deliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_status"
+	"github.com/stretchr/testify/assert"
+)
+
+func deliveryOrder(fee float64) *aggregates.Order {
+	delivery := &aggregates.OrderDelivery{Id: "entrega", Fee: fee, Status: deliverystatus.PENDING}
+	items := []aggregates.OrderItem{aggregates.NewOrderItem(aggregates.PartialProduct{Id: "marmita"}, 20, 1, "", nil)}
+	return aggregates.NewOrder("restaurante-1", aggregates.PartialCustomer{}, items, delivery, "")
+}
+
+func TestDeliveryRouteCompletesWithLastStop(t *testing.T) {
+	// arrange
+	first, second := deliveryOrder(5), deliveryOrder(7.5)
+	balcao := aggregates.NewOrder("restaurante-1", aggregates.PartialCustomer{}, first.Items, nil, "")
+	route := aggregates.NewDeliveryRoute("restaurante-1", aggregates.PartialDriver{Id: "entregador-1"}, "gerente@marmitech.com")
+	now := time.Now()
+
+	// act
+	addedFirst := route.AddStop(first)
+	addedSecond := route.AddStop(second)
+	addedTwice := route.AddStop(first)
+	addedCounter := route.AddStop(balcao)
+	deliveredEarly := route.Deliver(route.Stops[0].Id, "", true, now)
+	accepted := route.Accept(now)
+	pickedUp := route.PickUp(now)
+	firstStop := route.Deliver(route.Stops[0].Id, "foto.jpg", false, now)
+	statusAfterFirst := route.Status
+	secondStop := route.Deliver(route.Stops[1].Id, "", true, now)
+	cancelled := route.Cancel(now)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.True(addedFirst)
+	assert.True(addedSecond)
+	assert.False(addedTwice, "o mesmo pedido não entra duas vezes na rota")
+	assert.False(addedCounter, "pedido sem entrega não vira parada")
+	assert.Equal(2, route.Stops[1].Sequence)
+	assert.Equal(7.5, route.Stops[1].Fee)
+	assert.Nil(deliveredEarly, "não entrega antes da retirada")
+	assert.True(accepted)
+	assert.True(pickedUp)
+	assert.NotNil(firstStop)
+	assert.Equal("foto.jpg", firstStop.ProofPhotoUrl)
+	assert.Equal(deliveryroutestatus.IN_TRANSIT, statusAfterFirst)
+	assert.NotNil(secondStop)
+	assert.True(secondStop.CodeConfirmed)
+	assert.Equal(deliveryroutestatus.COMPLETED, route.Status)
+	assert.NotNil(route.CompletedAt)
+	assert.False(cancelled, "rota concluída não é cancelada")
+}
+
+func TestDeliveryRouteCancelReleasesStops(t *testing.T) {
+	// arrange
+	route := aggregates.NewDeliveryRoute("restaurante-1", aggregates.PartialDriver{Id: "entregador-1"}, "gerente@marmitech.com")
+	route.AddStop(deliveryOrder(5))
+	now := time.Now()
+
+	// act
+	cancelled := route.Cancel(now)
+	acceptedAfter := route.Accept(now)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.True(cancelled)
+	assert.False(acceptedAfter)
+	assert.Equal(deliveryroutestatus.CANCELLED, route.Status)
+	assert.Equal(deliverystatus.PENDING, route.Stops[0].Status, "a parada volta para a fila de entregas")
+}
@@ -0,0 +1,122 @@
+package aggregates
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	deliveryzonekind "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_zone_kind"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+type (
+	// DeliveryZone é uma área de entrega com preço próprio. Zonas podem se sobrepor (um bairro
+	// mais caro dentro de um polígono maior): vale a de menor Priority
+	DeliveryZone struct {
+		abstractions.AggregateRoot
+		Restaurant PartialRestaurant                 `json:"restaurant"`
+		Name       string                            `json:"name"`
+		Kind       deliveryzonekind.DeliveryZoneKind `json:"kind"`
+		Polygon    *types.GeoPolygon                 `json:"polygon,omitempty"`
+		// Neighborhoods só valem na City da zona; City vazia aceita o bairro em qualquer cidade
+		Neighborhoods        []string  `json:"neighborhoods,omitempty"`
+		City                 string    `json:"city,omitempty"`
+		Fee                  float64   `json:"fee"`
+		MinimumOrderValue    float64   `json:"minimum_order_value"`
+		EstimatedTimeMinutes int       `json:"estimated_time_minutes"`
+		Priority             int       `json:"priority"`
+		Active               bool      `json:"active"`
+		CreatedAt            time.Time `json:"created_at"`
+		UpdatedAt            time.Time `json:"updated_at"`
+	}
+
+	// DeliveryQuote é o preço e o prazo de entrega em um endereço; ZoneId vazio indica a regra por km
+	DeliveryQuote struct {
+		ZoneId               string  `json:"zone_id,omitempty"`
+		ZoneName             string  `json:"zone_name,omitempty"`
+		Fee                  float64 `json:"fee"`
+		MinimumOrderValue    float64 `json:"minimum_order_value"`
+		EstimatedTimeMinutes int     `json:"estimated_time_minutes"`
+		DistanceKm           float64 `json:"distance_km"`
+	}
+)
+
+func NewDeliveryZone(restaurantId, name string, kind deliveryzonekind.DeliveryZoneKind) *DeliveryZone {
+	now := time.Now()
+	return &DeliveryZone{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Name:          name,
+		Kind:          kind,
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Covers confere o polígono pelas coordenadas e a lista de bairros pelo nome, sem acentos nem caixa
+func (z *DeliveryZone) Covers(address types.Address) bool {
+	switch z.Kind {
+	case deliveryzonekind.POLYGON:
+		return z.Polygon != nil && address.HasCoordinates() && z.Polygon.Contains(address.Point())
+	case deliveryzonekind.NEIGHBORHOODS:
+		if z.City != "" && types.FoldName(z.City) != types.FoldName(address.City) {
+			return false
+		}
+		neighborhood := types.FoldName(address.Neighborhood)
+		return neighborhood != "" && slices.ContainsFunc(z.Neighborhoods, func(candidate string) bool {
+			return types.FoldName(candidate) == neighborhood
+		})
+	default:
+		return false
+	}
+}
+
+func (z *DeliveryZone) Quote(distanceKm float64) DeliveryQuote {
+	return DeliveryQuote{
+		ZoneId:               z.Id,
+		ZoneName:             z.Name,
+		Fee:                  z.Fee,
+		MinimumOrderValue:    z.MinimumOrderValue,
+		EstimatedTimeMinutes: z.EstimatedTimeMinutes,
+		DistanceKm:           distanceKm,
+	}
+}
+
+// MatchDeliveryZone devolve a zona ativa de menor prioridade que cobre o endereço, ou nil
+func MatchDeliveryZone(zones []DeliveryZone, address types.Address) *DeliveryZone {
+	candidates := make([]*DeliveryZone, 0, len(zones))
+	for i := range zones {
+		if zones[i].Active && zones[i].Covers(address) {
+			candidates = append(candidates, &zones[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Priority < candidates[j].Priority
+	})
+	return candidates[0]
+}
+
+// UsesDistance indica que o restaurante cobra por km ou limita o raio, e precisa das coordenadas
+func (c DeliveryConfig) UsesDistance() bool {
+	return c.FeePerKm > 0 || c.MaxRadiusKm > 0
+}
+
+// Quote aplica a regra por km; falso quando o endereço fica além do raio máximo
+func (c DeliveryConfig) Quote(distanceKm float64) (DeliveryQuote, bool) {
+	if c.MaxRadiusKm > 0 && distanceKm > float64(c.MaxRadiusKm) {
+		return DeliveryQuote{}, false
+	}
+
+	return DeliveryQuote{
+		Fee:                  roundCents(distanceKm * float64(c.FeePerKm)),
+		MinimumOrderValue:    float64(c.MinimumOrderValue),
+		EstimatedTimeMinutes: c.AverageTimeMinutes,
+		DistanceKm:           distanceKm,
+	}, true
+}
@@ -0,0 +1,101 @@
+package aggregates_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	deliveryzonekind "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_zone_kind"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// um quadrado em volta do centro de Campinas com um buraco no meio, como sai de um editor de mapa
+const campinasZone = `{
+	"type": "Feature",
+	"properties": {},
+	"geometry": {
+		"type": "Polygon",
+		"coordinates": [
+			[[-47.08, -22.88], [-47.04, -22.88], [-47.04, -22.92], [-47.08, -22.92]],
+			[[-47.065, -22.895], [-47.055, -22.895], [-47.055, -22.905], [-47.065, -22.905], [-47.065, -22.895]]
+		]
+	}
+}`
+
+func TestDeliveryZoneCoversPolygonAndNeighborhoods(t *testing.T) {
+	// arrange
+	polygon := aggregates.NewDeliveryZone("restaurante-1", "Centro", deliveryzonekind.POLYGON)
+	parseErr := json.Unmarshal([]byte(campinasZone), &polygon.Polygon)
+
+	neighborhoods := aggregates.NewDeliveryZone("restaurante-1", "Bairros nobres", deliveryzonekind.NEIGHBORHOODS)
+	neighborhoods.City = "Campinas"
+	neighborhoods.Neighborhoods = []string{"Cambuí", "Taquaral"}
+
+	inside := types.Address{Lat: -22.89, Lng: -47.07}
+	inHole := types.Address{Lat: -22.90, Lng: -47.06}
+	outside := types.Address{Lat: -22.95, Lng: -47.07}
+	notGeocoded := types.Address{Neighborhood: "CAMBUI", City: "campinas"}
+
+	// act / assert
+	assert := assert.New(t)
+
+	assert.NoError(parseErr)
+	assert.True(polygon.Covers(inside))
+	assert.False(polygon.Covers(inHole), "o buraco do polígono fica fora da zona")
+	assert.False(polygon.Covers(outside))
+	assert.False(polygon.Covers(notGeocoded), "sem coordenadas o polígono não tem como conferir")
+	assert.True(neighborhoods.Covers(notGeocoded), "bairro confere sem acento e sem caixa")
+	assert.False(neighborhoods.Covers(types.Address{Neighborhood: "Cambuí", City: "Valinhos"}), "bairro de mesmo nome em outra cidade")
+}
+
+func TestMatchDeliveryZonePrefersLowestPriority(t *testing.T) {
+	// arrange
+	wide := aggregates.NewDeliveryZone("restaurante-1", "Cidade toda", deliveryzonekind.NEIGHBORHOODS)
+	wide.Neighborhoods = []string{"Centro", "Cambuí"}
+	wide.Fee = 5
+	wide.Priority = 10
+
+	premium := aggregates.NewDeliveryZone("restaurante-1", "Cambuí", deliveryzonekind.NEIGHBORHOODS)
+	premium.Neighborhoods = []string{"Cambuí"}
+	premium.Fee = 9
+	premium.Priority = 1
+
+	inactive := aggregates.NewDeliveryZone("restaurante-1", "Promoção", deliveryzonekind.NEIGHBORHOODS)
+	inactive.Neighborhoods = []string{"Cambuí"}
+	inactive.Active = false
+
+	zones := []aggregates.DeliveryZone{*wide, *premium, *inactive}
+
+	// act
+	cambui := aggregates.MatchDeliveryZone(zones, types.Address{Neighborhood: "Cambuí"})
+	centro := aggregates.MatchDeliveryZone(zones, types.Address{Neighborhood: "Centro"})
+	none := aggregates.MatchDeliveryZone(zones, types.Address{Neighborhood: "Barão Geraldo"})
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(premium.Id, cambui.Id)
+	assert.Equal(wide.Id, centro.Id)
+	assert.Nil(none)
+}
+
+func TestDeliveryConfigQuotesByDistance(t *testing.T) {
+	// arrange
+	config := aggregates.DeliveryConfig{FeePerKm: 2, MaxRadiusKm: 5, MinimumOrderValue: 30, AverageTimeMinutes: 40}
+
+	// act
+	quote, ok := config.Quote(3.25)
+	_, far := config.Quote(5.5)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.True(ok)
+	assert.Equal(6.5, quote.Fee)
+	assert.Equal(30.0, quote.MinimumOrderValue)
+	assert.Equal(40, quote.EstimatedTimeMinutes)
+	assert.False(far, "além do raio máximo não entrega")
+	assert.True(config.UsesDistance())
+	assert.False(aggregates.DeliveryConfig{}.UsesDistance())
+}
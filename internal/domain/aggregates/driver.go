@@ -0,0 +1,55 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	driverkind "github.com/PedroNetto404/marmitech-backend/pkg/enums/driver_kind"
+)
+
+type (
+	PartialDriver struct {
+		Id   string `json:"id"`
+		Name string `json:"name,omitempty"`
+	}
+
+	// Driver é um entregador do restaurante. Email é o login com que ele usa o app de entregas
+	Driver struct {
+		abstractions.AggregateRoot
+		Restaurant PartialRestaurant     `json:"restaurant"`
+		Name       string                `json:"name"`
+		Kind       driverkind.DriverKind `json:"kind"`
+		Phone      string                `json:"phone,omitempty"`
+		Email      string                `json:"email,omitempty"`
+		Vehicle    string                `json:"vehicle,omitempty"`
+		Active     bool                  `json:"active"`
+		CreatedAt  time.Time             `json:"created_at"`
+		UpdatedAt  time.Time             `json:"updated_at"`
+	}
+
+	// DriverLocation é uma posição enviada pelo app do entregador; RouteId vazio fora de rota
+	DriverLocation struct {
+		DriverId   string    `json:"driver_id"`
+		RouteId    string    `json:"route_id,omitempty"`
+		Lat        float64   `json:"lat"`
+		Lng        float64   `json:"lng"`
+		RecordedAt time.Time `json:"recorded_at"`
+	}
+)
+
+func NewDriver(restaurantId, name string, kind driverkind.DriverKind) *Driver {
+	now := time.Now()
+	return &Driver{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Name:          name,
+		Kind:          kind,
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (d *Driver) Partial() PartialDriver {
+	return PartialDriver{Id: d.Id, Name: d.Name}
+}
@@ -0,0 +1,83 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+)
+
+type FiscalDocumentStatus string
+
+const (
+	// número reservado, aguardando retorno da SEFAZ
+	FiscalDocumentPending    FiscalDocumentStatus = "pending"
+	FiscalDocumentAuthorized FiscalDocumentStatus = "authorized"
+	FiscalDocumentRejected   FiscalDocumentStatus = "rejected"
+)
+
+// modelo 65 é a NFC-e
+const NfceModel = "65"
+
+type (
+	// FiscalDocument é a NFC-e de um pedido; Xml guarda o nfeProc autorizado
+	FiscalDocument struct {
+		abstractions.AggregateRoot
+		Restaurant   PartialRestaurant    `json:"restaurant"`
+		OrderId      string               `json:"order_id"`
+		Model        string               `json:"model"`
+		Series       int                  `json:"series"`
+		Number       int                  `json:"number"`
+		Environment  FiscalEnvironment    `json:"environment"`
+		AccessKey    string               `json:"access_key"`
+		Status       FiscalDocumentStatus `json:"status"`
+		StatusCode   string               `json:"status_code"`
+		StatusReason string               `json:"status_reason"`
+		Protocol     string               `json:"protocol"`
+		QrCodeUrl    string               `json:"qr_code_url"`
+		Xml          string               `json:"-"`
+		IssuedAt     time.Time            `json:"issued_at"`
+		AuthorizedAt *time.Time           `json:"authorized_at,omitempty"`
+		UpdatedAt    time.Time            `json:"updated_at"`
+	}
+)
+
+func NewFiscalDocument(restaurantId, orderId string, series, number int, environment FiscalEnvironment) *FiscalDocument {
+	now := time.Now()
+	return &FiscalDocument{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		OrderId:       orderId,
+		Model:         NfceModel,
+		Series:        series,
+		Number:        number,
+		Environment:   environment,
+		Status:        FiscalDocumentPending,
+		IssuedAt:      now,
+		UpdatedAt:     now,
+	}
+}
+
+func (d *FiscalDocument) IsAuthorized() bool {
+	return d.Status == FiscalDocumentAuthorized
+}
+
+func (d *FiscalDocument) Authorize(accessKey, protocol, statusCode, reason, xml, qrCodeUrl string, authorizedAt time.Time) {
+	d.AccessKey = accessKey
+	d.Protocol = protocol
+	d.StatusCode = statusCode
+	d.StatusReason = reason
+	d.Xml = xml
+	d.QrCodeUrl = qrCodeUrl
+	d.Status = FiscalDocumentAuthorized
+	d.AuthorizedAt = &authorizedAt
+	d.UpdatedAt = time.Now()
+}
+
+// Reject mantém o número: nota rejeitada não consome a numeração e pode ser reenviada
+func (d *FiscalDocument) Reject(accessKey, statusCode, reason string) {
+	d.AccessKey = accessKey
+	d.StatusCode = statusCode
+	d.StatusReason = reason
+	d.Status = FiscalDocumentRejected
+	d.UpdatedAt = time.Now()
+}
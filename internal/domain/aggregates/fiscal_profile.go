@@ -0,0 +1,66 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	taxregime "github.com/PedroNetto404/marmitech-backend/pkg/enums/tax_regime"
+)
+
+type FiscalEnvironment int
+
+const (
+	FiscalEnvironmentProduction   FiscalEnvironment = 1
+	FiscalEnvironmentHomologation FiscalEnvironment = 2
+)
+
+type (
+	// FiscalProfile reúne os dados do restaurante como emitente de NFC-e.
+	// O certificado A1 fica no block storage; aqui só a senha e a validade.
+	FiscalProfile struct {
+		abstractions.AggregateRoot
+		Restaurant        PartialRestaurant   `json:"restaurant"`
+		TaxRegime         taxregime.TaxRegime `json:"tax_regime"`
+		StateRegistration string              `json:"state_registration"`
+		// código IBGE do município do emitente
+		CityCode    string            `json:"city_code"`
+		Environment FiscalEnvironment `json:"environment"`
+		Series      int               `json:"series"`
+		// próximo número a ser usado na série
+		NextNumber int `json:"next_number"`
+		// identificador e token do Código de Segurança do Contribuinte, usados no QR Code
+		CscId                string     `json:"csc_id"`
+		CscToken             string     `json:"-"`
+		CertificatePassword  string     `json:"-"`
+		CertificateSubject   string     `json:"certificate_subject"`
+		CertificateExpiresAt *time.Time `json:"certificate_expires_at"`
+		CreatedAt            time.Time  `json:"created_at"`
+		UpdatedAt            time.Time  `json:"updated_at"`
+	}
+)
+
+func NewFiscalProfile(restaurantId string) *FiscalProfile {
+	now := time.Now()
+	return &FiscalProfile{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		TaxRegime:     taxregime.SIMPLES_NACIONAL,
+		Environment:   FiscalEnvironmentHomologation,
+		Series:        1,
+		NextNumber:    1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (p *FiscalProfile) HasCertificate() bool {
+	return p.CertificateExpiresAt != nil
+}
+
+// ReserveNumber consome o próximo número da série
+func (p *FiscalProfile) ReserveNumber() int {
+	number := p.NextNumber
+	p.NextNumber++
+	p.UpdatedAt = time.Now()
+	return number
+}
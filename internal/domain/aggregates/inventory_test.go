@@ -0,0 +1,59 @@
+package aggregates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	stockitemkind "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_item_kind"
+	stockmovementtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_movement_type"
+	stockunit "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_unit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumptionOfSumsProductAndDishRecipes(t *testing.T) {
+	// arrange
+	box := aggregates.NewRecipe("restaurant", "marmita-m", "")
+	box.Items = []aggregates.RecipeItem{{StockItemId: "embalagem", Quantity: 1}}
+	rice := aggregates.NewRecipe("restaurant", "", "arroz")
+	rice.Items = []aggregates.RecipeItem{{StockItemId: "arroz-cru", Quantity: 0.08}}
+
+	lunchbox := &aggregates.LunchboxComposition{
+		Selections: []aggregates.LunchboxSelection{{DishId: "arroz", Quantity: 2}, {DishId: "feijao", Quantity: 1}},
+	}
+	items := []aggregates.OrderItem{
+		aggregates.NewOrderItem(aggregates.PartialProduct{Id: "marmita-m"}, 25, 3, "", lunchbox),
+	}
+
+	// act
+	consumption := aggregates.ConsumptionOf(items, []aggregates.Recipe{*box, *rice})
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(3.0, consumption["embalagem"], "uma embalagem por marmita")
+	assert.Equal(0.48, consumption["arroz-cru"], "duas porções de arroz em cada uma das três marmitas")
+	assert.Len(consumption, 2, "prato sem ficha técnica não consome estoque")
+}
+
+func TestStockItemMoveAveragesCostAndRaisesLowOnce(t *testing.T) {
+	// arrange
+	item := aggregates.NewStockItem("restaurant", "Arroz", stockitemkind.INGREDIENT, stockunit.KILOGRAM)
+	item.MinimumQuantity = 5
+	now := time.Now()
+
+	// act
+	item.Move(stockmovementtype.PURCHASE, 10, 4, "", "", "user", now)
+	item.Move(stockmovementtype.PURCHASE, 10, 6, "", "", "user", now)
+	item.Move(stockmovementtype.CONSUMPTION, 16, 0, "order", "", "", now)
+	movement := item.Move(stockmovementtype.LOSS, 1, 0, "", "", "user", now)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(5.0, item.UnitCost, "custo médio ponderado das compras")
+	assert.Equal(3.0, item.Quantity)
+	assert.Equal(-1.0, movement.Quantity, "perda sai com sinal negativo")
+	assert.True(item.IsLow())
+	assert.Len(item.DomainEvents(), 1, "alerta só ao cruzar o mínimo")
+}
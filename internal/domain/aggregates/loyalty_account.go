@@ -0,0 +1,125 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/google/uuid"
+)
+
+type LoyaltyEntryType string
+
+const (
+	LoyaltyEntryEarn   LoyaltyEntryType = "earn"
+	LoyaltyEntryRedeem LoyaltyEntryType = "redeem"
+	LoyaltyEntryExpire LoyaltyEntryType = "expire"
+	// Refund devolve os pontos resgatados em um pedido cancelado
+	LoyaltyEntryRefund LoyaltyEntryType = "refund"
+)
+
+type (
+	// LoyaltyEntry é um lançamento do cartão fidelidade; pontos sempre positivos
+	LoyaltyEntry struct {
+		Id          string           `json:"id"`
+		AccountId   string           `json:"account_id"`
+		Type        LoyaltyEntryType `json:"type"`
+		Points      int              `json:"points"`
+		OrderId     string           `json:"order_id,omitempty"`
+		Description string           `json:"description"`
+		ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+		CreatedAt   time.Time        `json:"created_at"`
+	}
+
+	// LoyaltyAccount é o saldo de pontos (ou selos) de um cliente no programa do restaurante
+	LoyaltyAccount struct {
+		abstractions.AggregateRoot
+		Restaurant PartialRestaurant `json:"restaurant"`
+		Customer   PartialCustomer   `json:"customer"`
+		Balance    int               `json:"balance"`
+		CreatedAt  time.Time         `json:"created_at"`
+		UpdatedAt  time.Time         `json:"updated_at"`
+	}
+)
+
+func NewLoyaltyAccount(restaurantId, customerId string) *LoyaltyAccount {
+	now := time.Now()
+	return &LoyaltyAccount{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Customer:      PartialCustomer{Id: customerId},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (a *LoyaltyAccount) CanRedeem(points int) bool {
+	return points > 0 && a.Balance >= points
+}
+
+func (a *LoyaltyAccount) Earn(points int, orderId, description string, expiresAt *time.Time, at time.Time) *LoyaltyEntry {
+	entry := a.newEntry(LoyaltyEntryEarn, points, orderId, description, at)
+	entry.ExpiresAt = expiresAt
+
+	a.Balance += points
+	return entry
+}
+
+func (a *LoyaltyAccount) Redeem(points int, orderId, description string, at time.Time) *LoyaltyEntry {
+	a.Balance -= points
+	return a.newEntry(LoyaltyEntryRedeem, points, orderId, description, at)
+}
+
+func (a *LoyaltyAccount) Refund(points int, orderId, description string, at time.Time) *LoyaltyEntry {
+	a.Balance += points
+	return a.newEntry(LoyaltyEntryRefund, points, orderId, description, at)
+}
+
+// Expire lança a expiração dos pontos vencidos até at, ou devolve nil se não há o que expirar.
+// Os resgates consomem primeiro os pontos mais antigos, então expira o que venceu
+// menos tudo o que já saiu da conta.
+func (a *LoyaltyAccount) Expire(entries []LoyaltyEntry, at time.Time) *LoyaltyEntry {
+	expired, spent := 0, 0
+	for _, entry := range entries {
+		switch entry.Type {
+		case LoyaltyEntryEarn:
+			if entry.ExpiresAt != nil && !entry.ExpiresAt.After(at) {
+				expired += entry.Points
+			}
+		case LoyaltyEntryRedeem, LoyaltyEntryExpire:
+			spent += entry.Points
+		case LoyaltyEntryRefund:
+			spent -= entry.Points
+		}
+	}
+
+	points := min(expired-spent, a.Balance)
+	if points <= 0 {
+		return nil
+	}
+
+	a.Balance -= points
+	return a.newEntry(LoyaltyEntryExpire, points, "", "Pontos expirados", at)
+}
+
+func (a *LoyaltyAccount) newEntry(entryType LoyaltyEntryType, points int, orderId, description string, at time.Time) *LoyaltyEntry {
+	a.UpdatedAt = at
+	return &LoyaltyEntry{
+		Id:          uuid.NewString(),
+		AccountId:   a.Id,
+		Type:        entryType,
+		Points:      points,
+		OrderId:     orderId,
+		Description: description,
+		CreatedAt:   at,
+	}
+}
+
+// FindLoyaltyEntry procura o lançamento de um tipo feito para o pedido
+func FindLoyaltyEntry(entries []LoyaltyEntry, entryType LoyaltyEntryType, orderId string) *LoyaltyEntry {
+	for i := range entries {
+		if entries[i].Type == entryType && entries[i].OrderId == orderId {
+			return &entries[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package aggregates
+
+import (
+	"math"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	loyaltymode "github.com/PedroNetto404/marmitech-backend/pkg/enums/loyalty_mode"
+)
+
+type (
+	// LoyaltyEarnRule pontua um produto ou, sem ProductId, todos os produtos de uma categoria
+	LoyaltyEarnRule struct {
+		ProductId  string `json:"product_id,omitempty"`
+		CategoryId string `json:"category_id,omitempty"`
+		Points     int    `json:"points"`
+	}
+
+	// LoyaltyProgram é o cartão fidelidade do restaurante, no máximo um por restaurante.
+	// Com RewardThreshold pontos o cliente troca por um desconto no pedido: uma unidade
+	// de RewardProductId de graça ou, sem produto, RewardValue reais sobre o pedido.
+	LoyaltyProgram struct {
+		abstractions.AggregateRoot
+		Restaurant      PartialRestaurant       `json:"restaurant"`
+		Name            string                  `json:"name"`
+		Mode            loyaltymode.LoyaltyMode `json:"mode"`
+		EarnRules       []LoyaltyEarnRule       `json:"earn_rules"`
+		RewardThreshold int                     `json:"reward_threshold"`
+		RewardProductId string                  `json:"reward_product_id,omitempty"`
+		// com produto de recompensa limita o desconto da unidade; zero não limita
+		RewardValue float64 `json:"reward_value"`
+		// dias até os pontos de um pedido expirarem; zero não expira
+		ExpirationDays int       `json:"expiration_days"`
+		Active         bool      `json:"active"`
+		CreatedAt      time.Time `json:"created_at"`
+		UpdatedAt      time.Time `json:"updated_at"`
+	}
+)
+
+func NewLoyaltyProgram(restaurantId, name string, mode loyaltymode.LoyaltyMode) *LoyaltyProgram {
+	now := time.Now()
+	return &LoyaltyProgram{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Name:          name,
+		Mode:          mode,
+		EarnRules:     make([]LoyaltyEarnRule, 0),
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// PointsFor calcula o que o pedido rende; categories liga cada produto do pedido à sua categoria.
+// Selos contam as unidades pagas e pontos contam os reais pagos, já descontadas as promoções.
+func (p *LoyaltyProgram) PointsFor(order *Order, categories map[string]string) int {
+	points := 0
+	for _, item := range order.Items {
+		rule, ok := p.ruleFor(item.Product.Id, categories[item.Product.Id])
+		net := item.Total - item.Discount
+		if !ok || net <= 0 {
+			continue
+		}
+
+		if p.Mode == loyaltymode.STAMPS {
+			// a unidade dada de graça, inclusive a recompensa do próprio cartão, não rende selo
+			units := int(math.Round(net * float64(item.Quantity) / item.Total))
+			points += rule.Points * units
+			continue
+		}
+
+		points += int(math.Floor(roundCents(net * float64(rule.Points))))
+	}
+	return points
+}
+
+// ruleFor prefere a regra do produto à da categoria
+func (p *LoyaltyProgram) ruleFor(productId, categoryId string) (LoyaltyEarnRule, bool) {
+	var byCategory *LoyaltyEarnRule
+	for i, rule := range p.EarnRules {
+		if rule.ProductId != "" {
+			if rule.ProductId == productId {
+				return rule, true
+			}
+			continue
+		}
+		if categoryId != "" && rule.CategoryId == categoryId && byCategory == nil {
+			byCategory = &p.EarnRules[i]
+		}
+	}
+
+	if byCategory == nil {
+		return LoyaltyEarnRule{}, false
+	}
+	return *byCategory, true
+}
+
+func (p *LoyaltyProgram) ExpirationFor(earnedAt time.Time) *time.Time {
+	if p.ExpirationDays == 0 {
+		return nil
+	}
+
+	expiresAt := earnedAt.AddDate(0, 0, p.ExpirationDays)
+	return &expiresAt
+}
+
+// rewardDiscount devolve o item e o valor que a recompensa abate do pedido
+func (p *LoyaltyProgram) rewardDiscount(order *Order) (string, float64) {
+	if p.RewardProductId == "" {
+		net := order.Subtotal - order.Discount
+		return "", roundCents(math.Max(math.Min(p.RewardValue, net), 0))
+	}
+
+	for _, item := range order.Items {
+		net := item.Total - item.Discount
+		if item.Product.Id != p.RewardProductId || net <= 0 {
+			continue
+		}
+
+		amount := math.Min(item.Total/float64(item.Quantity), net)
+		if p.RewardValue > 0 {
+			amount = math.Min(amount, p.RewardValue)
+		}
+		return item.Id, roundCents(amount)
+	}
+
+	return "", 0
+}
@@ -0,0 +1,60 @@
+package aggregates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	loyaltymode "github.com/PedroNetto404/marmitech-backend/pkg/enums/loyalty_mode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStampProgramRewardsFreeLunchboxWithoutStampingIt(t *testing.T) {
+	// arrange
+	program := aggregates.NewLoyaltyProgram("restaurant", "Cartão marmita", loyaltymode.STAMPS)
+	program.EarnRules = []aggregates.LoyaltyEarnRule{{CategoryId: "marmitas", Points: 1}}
+	program.RewardThreshold = 10
+	program.RewardProductId = "marmita-m"
+
+	lunchbox := aggregates.NewOrderItem(aggregates.PartialProduct{Id: "marmita-m", Name: "Marmita M"}, 25, 3, "", nil)
+	drink := aggregates.NewOrderItem(aggregates.PartialProduct{Id: "suco", Name: "Suco"}, 8, 1, "", nil)
+	order := aggregates.NewOrder("restaurant", aggregates.PartialCustomer{Id: "customer"}, []aggregates.OrderItem{lunchbox, drink}, nil, "")
+	categories := map[string]string{"marmita-m": "marmitas", "suco": "bebidas"}
+
+	// act
+	discount := order.ApplyLoyaltyReward(program)
+	stamps := program.PointsFor(order, categories)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(25.0, discount, "uma marmita de graça")
+	assert.Equal(25.0, order.Items[0].Discount)
+	assert.Equal(58.0, order.Total)
+	assert.Equal(2, stamps, "só as marmitas pagas rendem selo; a bebida não tem regra")
+}
+
+func TestLoyaltyAccountExpiresOnlyPointsNotYetSpent(t *testing.T) {
+	// arrange
+	account := aggregates.NewLoyaltyAccount("restaurant", "customer")
+	january := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	expiresAt := january.AddDate(0, 0, 90)
+
+	entries := []aggregates.LoyaltyEntry{
+		*account.Earn(10, "order-1", "", &expiresAt, january),
+		*account.Redeem(6, "order-2", "", january.AddDate(0, 0, 30)),
+		*account.Earn(5, "order-3", "", nil, january.AddDate(0, 0, 40)),
+	}
+
+	// act
+	beforeExpiration := account.Expire(entries, expiresAt.Add(-time.Hour))
+	expired := account.Expire(entries, expiresAt)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Nil(beforeExpiration)
+	assert.NotNil(expired)
+	assert.Equal(4, expired.Points, "o resgate consumiu 6 dos 10 pontos vencidos")
+	assert.Equal(5, account.Balance)
+}
@@ -0,0 +1,192 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	marketplaceorderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/marketplace_order_status"
+	marketplacepushstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/marketplace_push_status"
+	orderchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_channel"
+)
+
+const (
+	marketplacePushMaxAttempts = 6
+	// a espera dobra a cada falha: 15s, 30s, 1min, 2min, 4min
+	marketplacePushRetryBase = 15 * time.Second
+)
+
+type (
+	// MarketplaceIntegration liga o restaurante à loja dele no marketplace; as credenciais
+	// do aplicativo são da plataforma, cada restaurante só informa o id da loja
+	MarketplaceIntegration struct {
+		abstractions.AggregateRoot
+		RestaurantId string                    `json:"restaurant_id"`
+		Channel      orderchannel.OrderChannel `json:"channel"`
+		MerchantId   string                    `json:"merchant_id"`
+		Active       bool                      `json:"active"`
+		CreatedAt    time.Time                 `json:"created_at"`
+		UpdatedAt    time.Time                 `json:"updated_at"`
+	}
+
+	// MarketplaceMapping diz qual produto do restaurante corresponde ao item vendido no marketplace,
+	// identificado pelo código externo cadastrado no cardápio de lá
+	MarketplaceMapping struct {
+		abstractions.AggregateRoot
+		RestaurantId string                    `json:"restaurant_id"`
+		Channel      orderchannel.OrderChannel `json:"channel"`
+		ExternalCode string                    `json:"external_code"`
+		Product      PartialProduct            `json:"product"`
+		CreatedAt    time.Time                 `json:"created_at"`
+		UpdatedAt    time.Time                 `json:"updated_at"`
+	}
+
+	// MarketplaceOrder registra cada pedido recebido do marketplace e o que foi feito com ele;
+	// é o que impede o mesmo pedido de entrar duas vezes na cozinha
+	MarketplaceOrder struct {
+		abstractions.AggregateRoot
+		RestaurantId string                                        `json:"restaurant_id"`
+		Channel      orderchannel.OrderChannel                     `json:"channel"`
+		ExternalId   string                                        `json:"external_id"`
+		ExternalCode string                                        `json:"external_code"`
+		OrderId      string                                        `json:"order_id,omitempty"`
+		Status       marketplaceorderstatus.MarketplaceOrderStatus `json:"status"`
+		// códigos externos dos itens sem vínculo, que impediram a importação
+		UnmappedItems []string  `json:"unmapped_items"`
+		LastError     string    `json:"last_error,omitempty"`
+		ReceivedAt    time.Time `json:"received_at"`
+		UpdatedAt     time.Time `json:"updated_at"`
+	}
+
+	// MarketplacePush é uma mudança de status a caminho do marketplace; Action é a ação do
+	// provedor (ports.MarketplaceAction) e as do mesmo pedido saem na ordem em que foram criadas
+	MarketplacePush struct {
+		abstractions.AggregateRoot
+		RestaurantId  string                                      `json:"restaurant_id"`
+		Channel       orderchannel.OrderChannel                   `json:"channel"`
+		OrderId       string                                      `json:"order_id"`
+		ExternalId    string                                      `json:"external_id"`
+		Action        string                                      `json:"action"`
+		Reason        string                                      `json:"reason,omitempty"`
+		Status        marketplacepushstatus.MarketplacePushStatus `json:"status"`
+		Attempts      int                                         `json:"attempts"`
+		NextAttemptAt *time.Time                                  `json:"next_attempt_at,omitempty"`
+		LastError     string                                      `json:"last_error,omitempty"`
+		CreatedAt     time.Time                                   `json:"created_at"`
+		SentAt        *time.Time                                  `json:"sent_at,omitempty"`
+	}
+)
+
+func NewMarketplaceIntegration(restaurantId string, channel orderchannel.OrderChannel, merchantId string) *MarketplaceIntegration {
+	now := time.Now()
+	return &MarketplaceIntegration{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		RestaurantId:  restaurantId,
+		Channel:       channel,
+		MerchantId:    merchantId,
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func NewMarketplaceMapping(restaurantId string, channel orderchannel.OrderChannel, externalCode string, product PartialProduct) *MarketplaceMapping {
+	now := time.Now()
+	return &MarketplaceMapping{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		RestaurantId:  restaurantId,
+		Channel:       channel,
+		ExternalCode:  externalCode,
+		Product:       product,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func NewMarketplaceOrder(restaurantId string, channel orderchannel.OrderChannel, externalId, externalCode string) *MarketplaceOrder {
+	now := time.Now()
+	return &MarketplaceOrder{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		RestaurantId:  restaurantId,
+		Channel:       channel,
+		ExternalId:    externalId,
+		ExternalCode:  externalCode,
+		Status:        marketplaceorderstatus.FAILED,
+		UnmappedItems: make([]string, 0),
+		ReceivedAt:    now,
+		UpdatedAt:     now,
+	}
+}
+
+func (m *MarketplaceOrder) MarkImported(orderId string) {
+	m.Status = marketplaceorderstatus.IMPORTED
+	m.OrderId = orderId
+	m.UnmappedItems = make([]string, 0)
+	m.LastError = ""
+	m.UpdatedAt = time.Now()
+}
+
+// Fail guarda o motivo e os itens sem vínculo; depois de vincular os itens o pedido pode ser reimportado
+func (m *MarketplaceOrder) Fail(reason string, unmappedItems []string) {
+	m.Status = marketplaceorderstatus.FAILED
+	m.LastError = reason
+	m.UnmappedItems = unmappedItems
+	if m.UnmappedItems == nil {
+		m.UnmappedItems = make([]string, 0)
+	}
+	m.UpdatedAt = time.Now()
+}
+
+// MarkCancelled registra que o cancelamento veio do marketplace, para que ele não seja devolvido para lá
+func (m *MarketplaceOrder) MarkCancelled() bool {
+	if m.Status == marketplaceorderstatus.CANCELLED {
+		return false
+	}
+
+	m.Status = marketplaceorderstatus.CANCELLED
+	m.UpdatedAt = time.Now()
+	return true
+}
+
+func (m *MarketplaceOrder) CanRetry() bool {
+	return m.Status == marketplaceorderstatus.FAILED
+}
+
+func NewMarketplacePush(order *Order, action string, reason string) *MarketplacePush {
+	now := time.Now()
+	return &MarketplacePush{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		RestaurantId:  order.Restaurant.Id,
+		Channel:       order.Channel,
+		OrderId:       order.Id,
+		ExternalId:    order.ExternalId,
+		Action:        action,
+		Reason:        reason,
+		Status:        marketplacepushstatus.PENDING,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}
+}
+
+// RecordAttempt registra o resultado de um envio; recusa do marketplace (permanent) e a última
+// tentativa encerram o envio como falha, as demais falhas agendam a próxima tentativa
+func (p *MarketplacePush) RecordAttempt(err error, permanent bool, at time.Time) {
+	p.Attempts++
+
+	if err == nil {
+		p.Status = marketplacepushstatus.SENT
+		p.SentAt = &at
+		p.NextAttemptAt = nil
+		p.LastError = ""
+		return
+	}
+
+	p.LastError = err.Error()
+	if permanent || p.Attempts >= marketplacePushMaxAttempts {
+		p.Status = marketplacepushstatus.FAILED
+		p.NextAttemptAt = nil
+		return
+	}
+
+	next := at.Add(marketplacePushRetryBase << (p.Attempts - 1))
+	p.NextAttemptAt = &next
+}
@@ -0,0 +1,40 @@
+package aggregates_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	marketplacepushstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/marketplace_push_status"
+	orderchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_channel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarketplacePushBacksOffUntilFailed(t *testing.T) {
+	// arrange
+	order := &aggregates.Order{AggregateRoot: abstractions.NewAggregateRoot(), Channel: orderchannel.IFOOD, ExternalId: "ifood-123"}
+	push := aggregates.NewMarketplacePush(order, "confirm", "")
+	at := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	unavailable := errors.New("marketplace unavailable")
+
+	// act
+	push.RecordAttempt(unavailable, false, at)
+	firstRetry := *push.NextAttemptAt
+	push.RecordAttempt(unavailable, false, at)
+	secondRetry := *push.NextAttemptAt
+	for push.Status == marketplacepushstatus.PENDING {
+		push.RecordAttempt(unavailable, false, at)
+	}
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(at.Add(15*time.Second), firstRetry)
+	assert.Equal(at.Add(30*time.Second), secondRetry, "a espera dobra")
+	assert.Equal(marketplacepushstatus.FAILED, push.Status)
+	assert.Equal(6, push.Attempts)
+	assert.Nil(push.NextAttemptAt)
+	assert.Equal("ifood-123", push.ExternalId)
+}
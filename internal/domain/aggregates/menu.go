@@ -1 +1,71 @@
 package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+)
+
+type (
+	MenuItem struct {
+		Id              string      `json:"id"`
+		Dish            PartialDish `json:"dish"`
+		DishPictureUrl  string      `json:"dish_picture_url"`
+		AdditionalPrice float64     `json:"additional_price"`
+		// AvailablePortions é o que resta das porções do dia; nil quando o item não controla quantidade
+		AvailablePortions     *int `json:"available_portions"`
+		Enabled               bool `json:"enabled"`
+		CanBeUsedAsAdditional bool `json:"can_be_used_as_additional"`
+	}
+
+	// Menu é o cardápio do dia: os pratos disponíveis para compor as marmitas em OfferDate
+	Menu struct {
+		abstractions.AggregateRoot
+		Restaurant PartialRestaurant `json:"restaurant"`
+		PictureUrl string            `json:"picture_url"`
+		OfferDate  time.Time         `json:"offer_date"`
+		Items      []MenuItem        `json:"items"`
+	}
+)
+
+// SoldOut indica que as porções do dia acabaram
+func (i *MenuItem) SoldOut() bool {
+	return i.AvailablePortions != nil && *i.AvailablePortions <= 0
+}
+
+func (i *MenuItem) IsAvailable() bool {
+	return i.Enabled && !i.SoldOut()
+}
+
+func (m *Menu) EnabledItems() []MenuItem {
+	items := make([]MenuItem, 0, len(m.Items))
+	for _, item := range m.Items {
+		if item.Enabled {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (m *Menu) FindItem(id string) *MenuItem {
+	for i := range m.Items {
+		if m.Items[i].Id == id {
+			return &m.Items[i]
+		}
+	}
+	return nil
+}
+
+// MenuPortionsOf soma as porções que os itens do pedido tiram de cada item do cardápio
+func MenuPortionsOf(items []OrderItem) map[string]int {
+	portions := make(map[string]int)
+	for _, item := range items {
+		if item.Lunchbox == nil {
+			continue
+		}
+		for _, selection := range item.Lunchbox.Selections {
+			portions[selection.MenuItemId] += selection.Quantity * item.Quantity
+		}
+	}
+	return portions
+}
@@ -0,0 +1,35 @@
+package aggregates_test
+
+import (
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMenuPortionsOfCountsEveryLunchboxSelection(t *testing.T) {
+	// arrange
+	lunchbox := &aggregates.LunchboxComposition{
+		Selections: []aggregates.LunchboxSelection{
+			{MenuItemId: "feijoada", Quantity: 1},
+			{MenuItemId: "farofa", Quantity: 1, IsAdditional: true},
+		},
+	}
+	items := []aggregates.OrderItem{
+		aggregates.NewOrderItem(aggregates.PartialProduct{Id: "marmita-g"}, 30, 2, "", lunchbox),
+		aggregates.NewOrderItem(aggregates.PartialProduct{Id: "suco"}, 8, 1, "", nil),
+	}
+	zero := 0
+	soldOut := aggregates.MenuItem{Enabled: true, AvailablePortions: &zero}
+
+	// act
+	portions := aggregates.MenuPortionsOf(items)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(map[string]int{"feijoada": 2, "farofa": 2}, portions, "adicionais também consomem porções")
+	assert.True(soldOut.SoldOut())
+	assert.False(soldOut.IsAvailable(), "item esgotado não entra em novos pedidos")
+	assert.True((&aggregates.MenuItem{Enabled: true}).IsAvailable(), "sem controle de porções o item não esgota")
+}
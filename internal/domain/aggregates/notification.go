@@ -0,0 +1,165 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	notificationchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_channel"
+	notificationstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_status"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+)
+
+// NotificationTemplate identifica a mensagem enviada ao cliente; cada uma é enviada
+// no máximo uma vez por pedido e canal
+type NotificationTemplate string
+
+const (
+	OrderReceivedTemplate       NotificationTemplate = "order_received"
+	OrderReadyTemplate          NotificationTemplate = "order_ready"
+	OrderOutForDeliveryTemplate NotificationTemplate = "order_out_for_delivery"
+	OrderDeliveredTemplate      NotificationTemplate = "order_delivered"
+	OrderCancelledTemplate      NotificationTemplate = "order_cancelled"
+	PaymentConfirmedTemplate    NotificationTemplate = "payment_confirmed"
+)
+
+const (
+	notificationMaxAttempts = 5
+	// a espera dobra a cada falha: 30s, 1min, 2min, 4min; depois disso o aviso já perdeu a hora
+	notificationRetryBase = 30 * time.Second
+)
+
+type (
+	// NotificationPreferences guarda os canais que o cliente recusou; sem registro, todos valem
+	NotificationPreferences struct {
+		abstractions.AggregateRoot
+		CustomerId string    `json:"customer_id"`
+		Email      bool      `json:"email"`
+		WhatsApp   bool      `json:"whatsapp"`
+		Sms        bool      `json:"sms"`
+		UpdatedAt  time.Time `json:"updated_at"`
+	}
+
+	// Notification registra cada mensagem enviada (ou tentada) ao cliente; Subject e Body são
+	// gravados na reserva para que as novas tentativas enviem o mesmo texto
+	Notification struct {
+		abstractions.AggregateRoot
+		RestaurantId  string                                  `json:"restaurant_id"`
+		OrderId       string                                  `json:"order_id"`
+		CustomerId    string                                  `json:"customer_id"`
+		Template      NotificationTemplate                    `json:"template"`
+		Channel       notificationchannel.NotificationChannel `json:"channel"`
+		Recipient     string                                  `json:"recipient"`
+		Subject       string                                  `json:"-"`
+		Body          string                                  `json:"-"`
+		Status        notificationstatus.NotificationStatus   `json:"status"`
+		Attempts      int                                     `json:"attempts"`
+		NextAttemptAt *time.Time                              `json:"next_attempt_at,omitempty"`
+		LastError     string                                  `json:"last_error,omitempty"`
+		CreatedAt     time.Time                               `json:"created_at"`
+		SentAt        *time.Time                              `json:"sent_at,omitempty"`
+	}
+)
+
+func NewNotificationPreferences(customerId string) *NotificationPreferences {
+	preferences := &NotificationPreferences{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		CustomerId:    customerId,
+		Email:         true,
+		WhatsApp:      true,
+		Sms:           true,
+		UpdatedAt:     time.Now(),
+	}
+	preferences.Id = customerId
+
+	return preferences
+}
+
+func (p *NotificationPreferences) Allows(channel notificationchannel.NotificationChannel) bool {
+	switch channel {
+	case notificationchannel.EMAIL:
+		return p.Email
+	case notificationchannel.WHATSAPP:
+		return p.WhatsApp
+	case notificationchannel.SMS:
+		return p.Sms
+	}
+	return false
+}
+
+func NewNotification(
+	order *Order,
+	template NotificationTemplate,
+	channel notificationchannel.NotificationChannel,
+	recipient string,
+	subject string,
+	body string,
+) *Notification {
+	now := time.Now()
+	return &Notification{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		RestaurantId:  order.Restaurant.Id,
+		OrderId:       order.Id,
+		CustomerId:    order.Customer.Id,
+		Template:      template,
+		Channel:       channel,
+		Recipient:     recipient,
+		Subject:       subject,
+		Body:          body,
+		Status:        notificationstatus.PENDING,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}
+}
+
+// RecordAttempt registra o resultado de um envio; a recusa do provedor (permanent) e a última
+// tentativa encerram a notificação como falha, as demais falhas agendam a próxima tentativa
+func (n *Notification) RecordAttempt(err error, permanent bool, at time.Time) {
+	n.Attempts++
+
+	if err == nil {
+		n.Status = notificationstatus.SENT
+		n.SentAt = &at
+		n.NextAttemptAt = nil
+		n.LastError = ""
+		return
+	}
+
+	n.LastError = err.Error()
+	if permanent || n.Attempts >= notificationMaxAttempts {
+		n.Status = notificationstatus.FAILED
+		n.NextAttemptAt = nil
+		return
+	}
+
+	next := at.Add(notificationRetryBase << (n.Attempts - 1))
+	n.NextAttemptAt = &next
+}
+
+// NotificationTemplateFor diz qual mensagem o evento do pedido gera. O order.updated é levantado
+// a cada mudança de item, por isso só o pedido inteiro pronto vira mensagem: a entrega pronta
+// sai com o entregador, a retirada espera no balcão
+func NotificationTemplateFor(event abstractions.EventName, order *Order) (NotificationTemplate, bool) {
+	switch event {
+	case OrderCreatedEvent:
+		return OrderReceivedTemplate, true
+	case OrderUpdatedEvent:
+		if order.Status != orderstatus.READY {
+			return "", false
+		}
+		if order.Delivery != nil {
+			return OrderOutForDeliveryTemplate, true
+		}
+		return OrderReadyTemplate, true
+	case OrderCompletedEvent:
+		// a retirada já foi avisada quando ficou pronta
+		if order.Delivery == nil {
+			return "", false
+		}
+		return OrderDeliveredTemplate, true
+	case OrderCancelledEvent:
+		return OrderCancelledTemplate, true
+	case OrderPaidEvent:
+		return PaymentConfirmedTemplate, true
+	}
+	return "", false
+}
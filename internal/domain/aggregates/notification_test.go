@@ -0,0 +1,87 @@
+package aggregates_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	notificationchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_channel"
+	notificationstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_status"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationTemplateFollowsOrderStatus(t *testing.T) {
+	// arrange
+	pickup := &aggregates.Order{AggregateRoot: abstractions.NewAggregateRoot(), Status: orderstatus.PREPARING}
+	delivery := &aggregates.Order{AggregateRoot: abstractions.NewAggregateRoot(), Status: orderstatus.READY, Delivery: &aggregates.OrderDelivery{}}
+
+	// act
+	_, preparing := aggregates.NotificationTemplateFor(aggregates.OrderUpdatedEvent, pickup)
+	pickup.Status = orderstatus.READY
+	ready, _ := aggregates.NotificationTemplateFor(aggregates.OrderUpdatedEvent, pickup)
+	_, pickedUp := aggregates.NotificationTemplateFor(aggregates.OrderCompletedEvent, pickup)
+	outForDelivery, _ := aggregates.NotificationTemplateFor(aggregates.OrderUpdatedEvent, delivery)
+	delivered, _ := aggregates.NotificationTemplateFor(aggregates.OrderCompletedEvent, delivery)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.False(preparing, "mudança de item na cozinha não avisa o cliente")
+	assert.Equal(aggregates.OrderReadyTemplate, ready)
+	assert.False(pickedUp, "a retirada já foi avisada quando ficou pronta")
+	assert.Equal(aggregates.OrderOutForDeliveryTemplate, outForDelivery)
+	assert.Equal(aggregates.OrderDeliveredTemplate, delivered)
+}
+
+func TestNotificationBacksOffUntilFailed(t *testing.T) {
+	// arrange
+	order := &aggregates.Order{AggregateRoot: abstractions.NewAggregateRoot()}
+	notification := aggregates.NewNotification(order, aggregates.OrderReadyTemplate, notificationchannel.EMAIL, "cliente@example.com", "Pedido pronto", "Seu pedido está pronto")
+	at := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	unavailable := errors.New("provider unavailable")
+
+	// act
+	notification.RecordAttempt(unavailable, false, at)
+	firstRetry := *notification.NextAttemptAt
+	notification.RecordAttempt(unavailable, false, at)
+	secondRetry := *notification.NextAttemptAt
+	for notification.Status == notificationstatus.PENDING {
+		notification.RecordAttempt(unavailable, false, at)
+	}
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(at.Add(30*time.Second), firstRetry)
+	assert.Equal(at.Add(time.Minute), secondRetry, "a espera dobra")
+	assert.Equal(notificationstatus.FAILED, notification.Status)
+	assert.Equal(5, notification.Attempts)
+	assert.Nil(notification.NextAttemptAt)
+	assert.Equal("provider unavailable", notification.LastError)
+}
+
+func TestNotificationStopsOnRejectionOrSuccess(t *testing.T) {
+	// arrange
+	order := &aggregates.Order{AggregateRoot: abstractions.NewAggregateRoot()}
+	rejected := aggregates.NewNotification(order, aggregates.OrderReadyTemplate, notificationchannel.SMS, "+5511999999999", "", "Seu pedido está pronto")
+	sent := aggregates.NewNotification(order, aggregates.OrderReadyTemplate, notificationchannel.EMAIL, "cliente@example.com", "Pedido pronto", "Seu pedido está pronto")
+	at := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	// act
+	rejected.RecordAttempt(errors.New("invalid number"), true, at)
+	sent.RecordAttempt(errors.New("timeout"), false, at)
+	sent.RecordAttempt(nil, false, at.Add(30*time.Second))
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(notificationstatus.FAILED, rejected.Status, "recusa do provedor não é reenviada")
+	assert.Equal(1, rejected.Attempts)
+	assert.Equal(notificationstatus.SENT, sent.Status)
+	assert.Equal(2, sent.Attempts)
+	assert.Nil(sent.NextAttemptAt)
+	assert.Empty(sent.LastError)
+}
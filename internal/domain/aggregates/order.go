@@ -1,72 +1,404 @@
 package aggregates
 
 import (
+	"slices"
 	"time"
 
 	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	deliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_status"
+	dishtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/dish_type"
+	orderchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_channel"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/google/uuid"
+)
+
+const (
+	OrderCreatedEvent   abstractions.EventName = "order.created"
+	OrderUpdatedEvent   abstractions.EventName = "order.updated"
+	OrderCancelledEvent abstractions.EventName = "order.cancelled"
+	// OrderCompletedEvent acompanha o order.updated da entrega; só pedido concluído pontua no cartão fidelidade
+	OrderCompletedEvent abstractions.EventName = "order.completed"
+	// OrderPaidEvent é levantado quando os pagamentos passam a cobrir o total do pedido
+	OrderPaidEvent abstractions.EventName = "order.paid"
+	// OrderStatusChangedEvent só sai quando o status do pedido muda, não a cada item movido na cozinha
+	OrderStatusChangedEvent abstractions.EventName = "order.status_changed"
 )
 
 type (
 	PartialProduct struct {
-		Id   string
-		Name string
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// LunchboxSelection é um prato do cardápio do dia escolhido para compor a marmita
+	LunchboxSelection struct {
+		Id           string            `json:"id"`
+		MenuItemId   string            `json:"menu_item_id"`
+		DishId       string            `json:"dish_id"`
+		DishName     string            `json:"dish_name"`
+		DishType     dishtype.DishType `json:"dish_type"`
+		Quantity     int               `json:"quantity"`
+		IsAdditional bool              `json:"is_additional"`
+		Price        float64           `json:"price"`
+	}
+
+	LunchboxComposition struct {
+		Id            string              `json:"id"`
+		WantsFlatware bool                `json:"wants_flatware"`
+		Selections    []LunchboxSelection `json:"selections"`
 	}
 
 	OrderItem struct {
-		ID          string
-		OrderID     string
-		ProductID   string
-		Quantity    int
-		UnitPrice   float64
-		TotalPrice  float64
-		Observation string
-		CreatedAt   time.Time
-		UpdatedAt   time.Time
-		DeletedAt   *time.Time
+		Id          string                  `json:"id"`
+		Product     PartialProduct          `json:"product"`
+		UnitPrice   float64                 `json:"unit_price"`
+		Quantity    int                     `json:"quantity"`
+		Discount    float64                 `json:"discount"`
+		Total       float64                 `json:"total"`
+		Observation string                  `json:"observation"`
+		Status      orderstatus.OrderStatus `json:"status"`
+		Lunchbox    *LunchboxComposition    `json:"lunchbox,omitempty"`
 	}
 
 	OrderDelivery struct {
-		ID                 string
-		OrderID            string
-		Address            string
-		Fee                float64
-		Distance           float64
-		AverageTimeMinutes int
-		Status             string
-		CreatedAt          time.Time
-		UpdatedAt          time.Time
-		DeletedAt          *time.Time
+		Id                 string                        `json:"id"`
+		Address            types.Address                 `json:"address"`
+		Fee                float64                       `json:"fee"`
+		Distance           float64                       `json:"distance"`
+		AverageTimeMinutes int                           `json:"average_time_minutes"`
+		Status             deliverystatus.DeliveryStatus `json:"status"`
+		// Code é o código que o cliente informa ao entregador para confirmar o recebimento
+		Code string `json:"code,omitempty"`
 	}
 
 	OrderPayment struct {
-		ID        string
-		OrderID   string
-		Amount    float64
-		Method    string
-		Status    string
-		CreatedAt time.Time
-		UpdatedAt time.Time
-		DeletedAt *time.Time
+		Id            string    `json:"id"`
+		PaymentMethod string    `json:"payment_method"`
+		Amount        float64   `json:"amount"`
+		Status        string    `json:"status"`
+		PaidAt        time.Time `json:"paid_at"`
+		// PixKey é a chave do restaurante usada quando o pagamento foi via Pix
+		PixKey string `json:"pix_key,omitempty"`
+		// CashSessionId é o caixa que recebeu o pagamento no balcão
+		CashSessionId string `json:"cash_session_id,omitempty"`
 	}
 
 	Order struct {
 		abstractions.AggregateRoot
-		CustomerID    string
-		RestaurantID  string
-		Status        string
-		Total         float64
-		TotalDiscount float64
-		Discount      float64
-		Observation   string
-		CreatedAt     time.Time
-		UpdatedAt     time.Time
-		DeletedAt     *time.Time
-		Delivery      *OrderDelivery
-		Items         []OrderItem
-		Payments      []OrderPayment
+		Restaurant   PartialRestaurant       `json:"restaurant"`
+		Customer     PartialCustomer         `json:"customer"`
+		Status       orderstatus.OrderStatus `json:"status"`
+		Subtotal     float64                 `json:"subtotal"`
+		Discount     float64                 `json:"discount"`
+		Total        float64                 `json:"total"`
+		Observation  string                  `json:"observation"`
+		CancelReason string                  `json:"cancel_reason,omitempty"`
+		Delivery     *OrderDelivery          `json:"delivery,omitempty"`
+		Items        []OrderItem             `json:"items"`
+		Payments     []OrderPayment          `json:"payments"`
+		// por onde o pedido chegou; ExternalId e ExternalCode o identificam no marketplace,
+		// o código é o que o entregador informa no balcão
+		Channel      orderchannel.OrderChannel `json:"channel"`
+		ExternalId   string                    `json:"external_id,omitempty"`
+		ExternalCode string                    `json:"external_code,omitempty"`
+		// de onde vieram os descontos dos itens e do pedido
+		Promotions []OrderPromotion `json:"promotions"`
+		CreatedAt  time.Time        `json:"created_at"`
+		UpdatedAt  time.Time        `json:"updated_at"`
 	}
 )
 
+func NewOrderItem(product PartialProduct, unitPrice float64, quantity int, observation string, lunchbox *LunchboxComposition) OrderItem {
+	item := OrderItem{
+		Id:          uuid.NewString(),
+		Product:     product,
+		UnitPrice:   unitPrice,
+		Quantity:    quantity,
+		Observation: observation,
+		Status:      orderstatus.PENDING,
+		Lunchbox:    lunchbox,
+	}
+
+	price := unitPrice
+	if lunchbox != nil {
+		lunchbox.Id = uuid.NewString()
+		for i := range lunchbox.Selections {
+			lunchbox.Selections[i].Id = uuid.NewString()
+			if lunchbox.Selections[i].IsAdditional {
+				price += lunchbox.Selections[i].Price * float64(lunchbox.Selections[i].Quantity)
+			}
+		}
+	}
+	item.Total = price * float64(quantity)
+
+	return item
+}
+
+func NewOrder(
+	restaurantId string,
+	customer PartialCustomer,
+	items []OrderItem,
+	delivery *OrderDelivery,
+	observation string,
+) *Order {
+	now := time.Now()
+	order := &Order{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant: PartialRestaurant{
+			Id: restaurantId,
+		},
+		Customer:    customer,
+		Status:      orderstatus.PENDING,
+		Channel:     orderchannel.DIRECT,
+		Observation: observation,
+		Delivery:    delivery,
+		Items:       items,
+		Payments:    make([]OrderPayment, 0),
+		Promotions:  make([]OrderPromotion, 0),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	order.recalculate()
+	order.RaiseDomainEvent(abstractions.NewDomainEvent(OrderCreatedEvent, order.Id))
+
+	return order
+}
+
+// NewOrderFromMarketplace cria o pedido vendido por um marketplace com os valores cobrados por ele;
+// o desconto é o que o marketplace concedeu e não passa pelas promoções do restaurante
+func NewOrderFromMarketplace(
+	restaurantId string,
+	channel orderchannel.OrderChannel,
+	externalId string,
+	externalCode string,
+	items []OrderItem,
+	delivery *OrderDelivery,
+	discount float64,
+	observation string,
+) *Order {
+	order := NewOrder(restaurantId, PartialCustomer{}, items, delivery, observation)
+	order.Channel = channel
+	order.ExternalId = externalId
+	order.ExternalCode = externalCode
+	order.Discount = roundCents(discount)
+	order.recalculate()
+
+	return order
+}
+
+func (o *Order) recalculate() {
+	o.Subtotal = 0
+	for _, item := range o.Items {
+		o.Subtotal += item.Total - item.Discount
+	}
+
+	o.Total = o.Subtotal - o.Discount
+	if o.Delivery != nil {
+		o.Total += o.Delivery.Fee
+	}
+}
+
+func (o *Order) FindItem(itemId string) *OrderItem {
+	for i := range o.Items {
+		if o.Items[i].Id == itemId {
+			return &o.Items[i]
+		}
+	}
+	return nil
+}
+
+// ApplyPromotion abate os descontos da promoção nos itens ou no pedido;
+// devolve falso quando ela não se aplica ao pedido
+func (o *Order) ApplyPromotion(promotion *Promotion) bool {
+	allocations := promotion.discounts(o)
+	if len(allocations) == 0 {
+		return false
+	}
+
+	for _, allocation := range allocations {
+		if item := o.FindItem(allocation.OrderItemId); item != nil {
+			item.Discount = roundCents(item.Discount + allocation.Amount)
+		} else {
+			o.Discount = roundCents(o.Discount + allocation.Amount)
+		}
+	}
+
+	o.Promotions = append(o.Promotions, allocations...)
+	o.recalculate()
+	return true
+}
+
+// ApplyLoyaltyReward abate a recompensa do cartão fidelidade e devolve o valor descontado;
+// zero quando o pedido não tem como receber a recompensa
+func (o *Order) ApplyLoyaltyReward(program *LoyaltyProgram) float64 {
+	itemId, amount := program.rewardDiscount(o)
+	if amount <= 0 {
+		return 0
+	}
+
+	if item := o.FindItem(itemId); item != nil {
+		item.Discount = roundCents(item.Discount + amount)
+	} else {
+		o.Discount = roundCents(o.Discount + amount)
+	}
+
+	o.recalculate()
+	return amount
+}
+
+// ClearDiscounts desfaz promoções e recompensa para o pedido ser precificado de novo
+func (o *Order) ClearDiscounts() {
+	for i := range o.Items {
+		o.Items[i].Discount = 0
+	}
+	o.Discount = 0
+	o.Promotions = make([]OrderPromotion, 0)
+	o.recalculate()
+}
+
+// PromotionIds lista as promoções aplicadas, sem repetição
+func (o *Order) PromotionIds() []string {
+	ids := make([]string, 0, len(o.Promotions))
+	for _, promotion := range o.Promotions {
+		if !slices.Contains(ids, promotion.PromotionId) {
+			ids = append(ids, promotion.PromotionId)
+		}
+	}
+	return ids
+}
+
+// SetItemStatus move um item na cozinha e recalcula o status do pedido:
+// qualquer item em preparo coloca o pedido em preparo; todos prontos deixam o pedido pronto
+func (o *Order) SetItemStatus(itemId string, status orderstatus.OrderStatus) bool {
+	item := o.FindItem(itemId)
+	if item == nil || !o.Status.IsInKitchen() {
+		return false
+	}
+
+	item.Status = status
+	o.syncStatusWithItems()
+	return true
+}
+
+// SetAllItemsStatus move o pedido inteiro de uma vez, sem regredir itens já prontos
+func (o *Order) SetAllItemsStatus(status orderstatus.OrderStatus) bool {
+	if !o.Status.IsInKitchen() {
+		return false
+	}
+
+	for i := range o.Items {
+		if o.Items[i].Status != orderstatus.READY {
+			o.Items[i].Status = status
+		}
+	}
+	o.syncStatusWithItems()
+	return true
+}
+
+func (o *Order) syncStatusWithItems() {
+	previous := o.Status
+	ready, preparing := 0, 0
+	for _, item := range o.Items {
+		switch item.Status {
+		case orderstatus.READY:
+			ready++
+		case orderstatus.PREPARING:
+			preparing++
+		}
+	}
+
+	switch {
+	case len(o.Items) > 0 && ready == len(o.Items):
+		o.Status = orderstatus.READY
+	case preparing > 0 || ready > 0:
+		o.Status = orderstatus.PREPARING
+	default:
+		o.Status = orderstatus.PENDING
+	}
+
+	o.touch(OrderUpdatedEvent)
+	if o.Status != previous {
+		o.RaiseDomainEvent(abstractions.NewDomainEvent(OrderStatusChangedEvent, o.Id))
+	}
+}
+
+func (o *Order) Complete() bool {
+	if o.Status != orderstatus.READY {
+		return false
+	}
+
+	o.Status = orderstatus.COMPLETED
+	o.touch(OrderUpdatedEvent)
+	o.RaiseDomainEvent(abstractions.NewDomainEvent(OrderStatusChangedEvent, o.Id))
+	o.RaiseDomainEvent(abstractions.NewDomainEvent(OrderCompletedEvent, o.Id))
+	return true
+}
+
+func (o *Order) Cancel(reason string) bool {
+	if o.Status == orderstatus.COMPLETED || o.Status == orderstatus.CANCELLED {
+		return false
+	}
+
+	o.Status = orderstatus.CANCELLED
+	o.CancelReason = reason
+	o.touch(OrderCancelledEvent)
+	o.RaiseDomainEvent(abstractions.NewDomainEvent(OrderStatusChangedEvent, o.Id))
+	return true
+}
+
+// SetDeliveryStatus acompanha a rota do entregador; falso para pedidos sem entrega ou cancelados
+func (o *Order) SetDeliveryStatus(status deliverystatus.DeliveryStatus) bool {
+	if o.Delivery == nil || o.Status == orderstatus.CANCELLED {
+		return false
+	}
+
+	o.Delivery.Status = status
+	o.touch(OrderUpdatedEvent)
+	return true
+}
+
+func (o *Order) touch(event abstractions.EventName) {
+	o.UpdatedAt = time.Now()
+	o.RaiseDomainEvent(abstractions.NewDomainEvent(event, o.Id))
+}
+
+// RegisterPayment lança um pagamento recebido; nil quando o pedido foi cancelado
+// ou o valor passa do que falta pagar
+func (o *Order) RegisterPayment(method string, amount float64, pixKey, cashSessionId string, at time.Time) *OrderPayment {
+	amount = roundCents(amount)
+	if o.Status == orderstatus.CANCELLED || amount <= 0 || amount > o.Balance() {
+		return nil
+	}
+
+	o.Payments = append(o.Payments, OrderPayment{
+		Id:            uuid.NewString(),
+		PaymentMethod: method,
+		Amount:        amount,
+		Status:        "PAID",
+		PaidAt:        at,
+		PixKey:        pixKey,
+		CashSessionId: cashSessionId,
+	})
+
+	o.touch(OrderUpdatedEvent)
+	if o.Balance() == 0 {
+		o.RaiseDomainEvent(abstractions.NewDomainEvent(OrderPaidEvent, o.Id))
+	}
+	return &o.Payments[len(o.Payments)-1]
+}
+
+// Balance é o que ainda falta pagar
+func (o *Order) Balance() float64 {
+	paid := 0.0
+	for _, payment := range o.Payments {
+		if payment.Status == "PAID" {
+			paid += payment.Amount
+		}
+	}
+	return roundCents(max(o.Total-paid, 0))
+}
+
 func (o *Order) HasBeenFullyPaidVirtual() bool {
 	totalPaid := 0.0
 	for _, payment := range o.Payments {
@@ -0,0 +1,49 @@
+package aggregates_test
+
+import (
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderStatusFollowsKitchenItems(t *testing.T) {
+	// arrange
+	lunchbox := aggregates.NewOrderItem(aggregates.PartialProduct{Id: "p1", Name: "Marmita P"}, 20, 2, "", nil)
+	drink := aggregates.NewOrderItem(aggregates.PartialProduct{Id: "p2", Name: "Suco"}, 7.5, 1, "", nil)
+	order := aggregates.NewOrder("restaurant", aggregates.PartialCustomer{}, []aggregates.OrderItem{lunchbox, drink}, nil, "")
+
+	// act
+	order.SetItemStatus(lunchbox.Id, orderstatus.PREPARING)
+	preparing := order.Status
+	order.SetItemStatus(lunchbox.Id, orderstatus.READY)
+	partiallyReady := order.Status
+	order.SetItemStatus(drink.Id, orderstatus.READY)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(47.5, order.Total)
+	assert.Equal(orderstatus.PREPARING, preparing)
+	assert.Equal(orderstatus.PREPARING, partiallyReady, "ainda falta um item")
+	assert.Equal(orderstatus.READY, order.Status)
+	assert.Len(order.DomainEvents(), 6, "criação, três atualizações e duas mudanças de status")
+}
+
+func TestCancelledOrderLeavesKitchen(t *testing.T) {
+	// arrange
+	item := aggregates.NewOrderItem(aggregates.PartialProduct{Id: "p1", Name: "Marmita G"}, 25, 1, "", nil)
+	order := aggregates.NewOrder("restaurant", aggregates.PartialCustomer{}, []aggregates.OrderItem{item}, nil, "")
+
+	// act
+	cancelled := order.Cancel("cliente desistiu")
+	bumped := order.SetItemStatus(item.Id, orderstatus.READY)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.True(cancelled)
+	assert.False(bumped, "pedido cancelado não volta para a cozinha")
+	assert.Equal(orderstatus.CANCELLED, order.Status)
+}
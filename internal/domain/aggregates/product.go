@@ -1,13 +1,29 @@
 package aggregates
 
 import (
+	"regexp"
+
 	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
 	dishtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/dish_type"
 )
 
+const (
+	ProductCreatedEvent abstractions.EventName = "product.created"
+	ProductUpdatedEvent abstractions.EventName = "product.updated"
+)
+
 type (
 	DishTypeMap map[dishtype.DishType]int
 
+	// ProductFiscalData é a classificação usada na emissão da NFC-e
+	ProductFiscalData struct {
+		Ncm   string `json:"ncm"`
+		Cfop  string `json:"cfop"`
+		Csosn string `json:"csosn"`
+		// origem da mercadoria, 0 = nacional
+		Origin int `json:"origin"`
+	}
+
 	Product struct {
 		abstractions.AggregateRoot
 
@@ -20,6 +36,8 @@ type (
 		// marmita p: 2 carnes, 1 guarnição e 2 acompanhamentos
 		DishTypeMap DishTypeMap `json:"dish_type_map"`
 		Active      bool   `json:"active"`
+		Priority    int    `json:"priority"`
+		Fiscal      ProductFiscalData `json:"fiscal"`
 		Category PartialCategory `json:"category"`
 		Restaurant PartialRestaurant `json:"restaurant"`
 	}
@@ -48,4 +66,22 @@ func NewProduct(
 			Id: restaurantId,	
 		},
 	}
-}
\ No newline at end of file
+}
+
+var (
+	ncmPattern   = regexp.MustCompile(`^\d{8}$`)
+	cfopPattern  = regexp.MustCompile(`^[5-7]\d{3}$`)
+	csosnPattern = regexp.MustCompile(`^\d{3}$`)
+)
+
+func (f ProductFiscalData) IsEmpty() bool {
+	return f == ProductFiscalData{}
+}
+
+// IsValid confere só o formato dos códigos; a validade fiscal fica com a SEFAZ
+func (f ProductFiscalData) IsValid() bool {
+	return ncmPattern.MatchString(f.Ncm) &&
+		cfopPattern.MatchString(f.Cfop) &&
+		csosnPattern.MatchString(f.Csosn) &&
+		f.Origin >= 0 && f.Origin <= 8
+}
@@ -0,0 +1,57 @@
+package aggregates
+
+import (
+	"math"
+	"strconv"
+)
+
+// ProductMargin compara o preço de venda com o custo de uma unidade
+type ProductMargin struct {
+	SalesPrice    float64 `json:"sales_price"`
+	UnitCost      float64 `json:"unit_cost"`
+	Margin        float64 `json:"margin"`
+	MarginPercent float64 `json:"margin_percent"`
+	BelowCost     bool    `json:"below_cost"`
+}
+
+func NewProductMargin(salesPrice, unitCost float64) ProductMargin {
+	margin := ProductMargin{
+		SalesPrice: roundCents(salesPrice),
+		UnitCost:   roundCents(unitCost),
+		Margin:     roundCents(salesPrice - unitCost),
+		BelowCost:  salesPrice < unitCost,
+	}
+	if salesPrice > 0 {
+		margin.MarginPercent = math.Round((salesPrice-unitCost)/salesPrice*10000) / 100
+	}
+	return margin
+}
+
+// Cost valoriza a ficha técnica pelo custo médio atual de cada item de estoque
+func (r *Recipe) Cost(unitCosts map[string]float64) float64 {
+	cost := 0.0
+	for _, item := range r.Items {
+		cost += item.Quantity * unitCosts[item.StockItemId]
+	}
+	return cost
+}
+
+// BaseUnitCost é o custo de uma unidade sem contar os pratos da marmita: a ficha técnica do produto,
+// quando existe, ou o cost_price cadastrado
+func (p *Product) BaseUnitCost(recipe *Recipe, unitCosts map[string]float64) (float64, error) {
+	if recipe != nil && len(recipe.Items) > 0 {
+		return recipe.Cost(unitCosts), nil
+	}
+	return parsePrice(p.CostPrice)
+}
+
+func (p *Product) SalesPriceValue() (float64, error) {
+	return parsePrice(p.SalesPrice)
+}
+
+func parsePrice(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
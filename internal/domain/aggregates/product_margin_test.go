@@ -0,0 +1,31 @@
+package aggregates_test
+
+import (
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseUnitCostPrefersRecipeOverCostPrice(t *testing.T) {
+	// arrange
+	product := aggregates.NewProduct("Suco de laranja", "", "8.00", "2.50", nil, "bebidas", "restaurant")
+	recipe := aggregates.NewRecipe("restaurant", product.Id, "")
+	recipe.Items = []aggregates.RecipeItem{{StockItemId: "laranja", Quantity: 0.6}, {StockItemId: "copo", Quantity: 1}}
+	unitCosts := map[string]float64{"laranja": 5, "copo": 0.4}
+
+	// act
+	withRecipe, err := product.BaseUnitCost(recipe, unitCosts)
+	withoutRecipe, _ := product.BaseUnitCost(nil, unitCosts)
+	margin := aggregates.NewProductMargin(8, 10)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(err)
+	assert.InDelta(3.4, withRecipe, 0.0001, "0,6 kg de laranja a 5,00 mais o copo")
+	assert.Equal(2.5, withoutRecipe, "sem ficha técnica vale o cost_price")
+	assert.True(margin.BelowCost)
+	assert.Equal(-2.0, margin.Margin)
+	assert.Equal(-25.0, margin.MarginPercent)
+}
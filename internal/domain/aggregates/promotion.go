@@ -0,0 +1,222 @@
+package aggregates
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	discounttype "github.com/PedroNetto404/marmitech-backend/pkg/enums/discount_type"
+)
+
+type (
+	PromotionRequirement struct {
+		ProductId string `json:"product_id"`
+		Quantity  int    `json:"quantity"`
+	}
+
+	// Promotion é um cupom (com Code) ou uma regra automática do restaurante.
+	// O alvo do desconto depende do que estiver preenchido: Combo exige todos os produtos
+	// no pedido, ProductIds restringe a alguns produtos e, sem nenhum dos dois, o desconto
+	// vale sobre o pedido inteiro.
+	Promotion struct {
+		abstractions.AggregateRoot
+		Restaurant    PartialRestaurant         `json:"restaurant"`
+		Name          string                    `json:"name"`
+		Description   string                    `json:"description"`
+		Code          string                    `json:"code,omitempty"`
+		DiscountType  discounttype.DiscountType `json:"discount_type"`
+		Value         float64                   `json:"value"`
+		MinOrderValue float64                   `json:"min_order_value"`
+		ProductIds    []string                  `json:"product_ids"`
+		// o desconto vale uma vez por combo completo no pedido
+		Combo []PromotionRequirement `json:"combo"`
+		// dias da semana em que vale; vazio vale todos os dias
+		Weekdays []time.Weekday `json:"weekdays"`
+		StartsAt *time.Time     `json:"starts_at,omitempty"`
+		EndsAt   *time.Time     `json:"ends_at,omitempty"`
+		// limites de uso; zero não limita
+		MaxUses            int       `json:"max_uses"`
+		MaxUsesPerCustomer int       `json:"max_uses_per_customer"`
+		UsedCount          int       `json:"used_count"`
+		FirstOrderOnly     bool      `json:"first_order_only"`
+		Active             bool      `json:"active"`
+		CreatedAt          time.Time `json:"created_at"`
+		UpdatedAt          time.Time `json:"updated_at"`
+	}
+
+	// OrderPromotion registra quanto uma promoção abateu; sem OrderItemId o desconto é do pedido
+	OrderPromotion struct {
+		PromotionId string  `json:"promotion_id"`
+		Name        string  `json:"name"`
+		Code        string  `json:"code,omitempty"`
+		OrderItemId string  `json:"order_item_id,omitempty"`
+		Amount      float64 `json:"amount"`
+	}
+)
+
+func NewPromotion(restaurantId, name string, discountType discounttype.DiscountType, value float64) *Promotion {
+	now := time.Now()
+	return &Promotion{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Name:          name,
+		DiscountType:  discountType,
+		Value:         value,
+		ProductIds:    make([]string, 0),
+		Combo:         make([]PromotionRequirement, 0),
+		Weekdays:      make([]time.Weekday, 0),
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (p *Promotion) IsCoupon() bool {
+	return p.Code != ""
+}
+
+// TargetsItems indica promoções de item, avaliadas antes das que descontam o pedido
+func (p *Promotion) TargetsItems() bool {
+	return len(p.Combo) > 0 || len(p.ProductIds) > 0
+}
+
+func (p *Promotion) IsAvailableAt(moment time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && moment.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !moment.Before(*p.EndsAt) {
+		return false
+	}
+
+	return len(p.Weekdays) == 0 || slices.Contains(p.Weekdays, moment.Weekday())
+}
+
+func (p *Promotion) HasUsesLeft() bool {
+	return p.MaxUses == 0 || p.UsedCount < p.MaxUses
+}
+
+// discounts calcula os abatimentos sobre o que ainda não foi descontado do pedido,
+// de modo que promoções acumuladas nunca deixam um item ou o pedido negativo
+func (p *Promotion) discounts(order *Order) []OrderPromotion {
+	net := order.Subtotal - order.Discount
+	if p.MinOrderValue > 0 && net < p.MinOrderValue {
+		return nil
+	}
+
+	switch {
+	case len(p.Combo) > 0:
+		return p.comboDiscounts(order)
+	case len(p.ProductIds) > 0:
+		return p.productDiscounts(order)
+	}
+
+	amount := p.amountOf(net, 1)
+	if amount <= 0 {
+		return nil
+	}
+	return []OrderPromotion{p.allocation("", amount)}
+}
+
+func (p *Promotion) productDiscounts(order *Order) []OrderPromotion {
+	allocations := make([]OrderPromotion, 0, len(order.Items))
+	for _, item := range order.Items {
+		if !slices.Contains(p.ProductIds, item.Product.Id) {
+			continue
+		}
+
+		amount := p.amountOf(item.Total-item.Discount, item.Quantity)
+		if amount > 0 {
+			allocations = append(allocations, p.allocation(item.Id, amount))
+		}
+	}
+	return allocations
+}
+
+// comboDiscounts separa, na ordem do pedido, as unidades que formam os combos completos
+// e rateia o desconto entre os itens proporcionalmente ao valor dessas unidades
+func (p *Promotion) comboDiscounts(order *Order) []OrderPromotion {
+	quantities := make(map[string]int)
+	for _, item := range order.Items {
+		quantities[item.Product.Id] += item.Quantity
+	}
+
+	sets := -1
+	for _, requirement := range p.Combo {
+		complete := quantities[requirement.ProductId] / requirement.Quantity
+		if sets < 0 || complete < sets {
+			sets = complete
+		}
+	}
+	if sets <= 0 {
+		return nil
+	}
+
+	needed := make(map[string]int, len(p.Combo))
+	for _, requirement := range p.Combo {
+		needed[requirement.ProductId] += sets * requirement.Quantity
+	}
+
+	type share struct {
+		itemId string
+		value  float64
+	}
+	shares := make([]share, 0, len(p.Combo))
+	base := 0.0
+	for _, item := range order.Items {
+		units := min(needed[item.Product.Id], item.Quantity)
+		if units == 0 {
+			continue
+		}
+		needed[item.Product.Id] -= units
+
+		value := (item.Total - item.Discount) * float64(units) / float64(item.Quantity)
+		shares = append(shares, share{itemId: item.Id, value: value})
+		base += value
+	}
+	if base <= 0 {
+		return nil
+	}
+
+	amount := p.amountOf(base, sets)
+	allocations := make([]OrderPromotion, 0, len(shares))
+	distributed := 0.0
+	for i, share := range shares {
+		part := roundCents(amount * share.value / base)
+		if i == len(shares)-1 {
+			part = roundCents(amount - distributed)
+		}
+		distributed += part
+
+		if part > 0 {
+			allocations = append(allocations, p.allocation(share.itemId, part))
+		}
+	}
+	return allocations
+}
+
+// amountOf aplica o desconto sobre base; o valor fixo vale por unidade e nunca passa da base
+func (p *Promotion) amountOf(base float64, units int) float64 {
+	amount := p.Value * float64(units)
+	if p.DiscountType == discounttype.PERCENTAGE {
+		amount = base * p.Value / 100
+	}
+	return roundCents(math.Min(amount, base))
+}
+
+func (p *Promotion) allocation(orderItemId string, amount float64) OrderPromotion {
+	return OrderPromotion{
+		PromotionId: p.Id,
+		Name:        p.Name,
+		Code:        p.Code,
+		OrderItemId: orderItemId,
+		Amount:      amount,
+	}
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,94 @@
+package aggregates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	discounttype "github.com/PedroNetto404/marmitech-backend/pkg/enums/discount_type"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComboPromotionDiscountsOnlyCompleteCombos(t *testing.T) {
+	// arrange
+	lunchbox := aggregates.NewOrderItem(aggregates.PartialProduct{Id: "marmita-g", Name: "Marmita G"}, 30, 2, "", nil)
+	drink := aggregates.NewOrderItem(aggregates.PartialProduct{Id: "suco", Name: "Suco"}, 10, 1, "", nil)
+	order := aggregates.NewOrder("restaurant", aggregates.PartialCustomer{}, []aggregates.OrderItem{lunchbox, drink}, nil, "")
+
+	combo := aggregates.NewPromotion("restaurant", "Marmita G + bebida", discounttype.FIXED, 5)
+	combo.Combo = []aggregates.PromotionRequirement{{ProductId: "marmita-g", Quantity: 1}, {ProductId: "suco", Quantity: 1}}
+
+	// act
+	applied := combo.IsAvailableAt(time.Now()) && order.ApplyPromotion(combo)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.True(applied)
+	assert.Len(order.Promotions, 2, "desconto rateado entre a marmita e a bebida do combo")
+	assert.Equal(3.75, order.Items[0].Discount, "só uma das duas marmitas entra no combo")
+	assert.Equal(1.25, order.Items[1].Discount)
+	assert.Equal(65.0, order.Total)
+}
+
+func TestCouponStacksOverItemDiscountsAndRespectsMinimum(t *testing.T) {
+	// arrange
+	item := aggregates.NewOrderItem(aggregates.PartialProduct{Id: "marmita-m", Name: "Marmita M"}, 25, 2, "", nil)
+	order := aggregates.NewOrder("restaurant", aggregates.PartialCustomer{}, []aggregates.OrderItem{item}, nil, "")
+
+	weekday := aggregates.NewPromotion("restaurant", "Quarta da marmita", discounttype.FIXED, 2)
+	weekday.ProductIds = []string{"marmita-m"}
+	weekday.Weekdays = []time.Weekday{time.Wednesday}
+
+	coupon := aggregates.NewPromotion("restaurant", "Dez por cento", discounttype.PERCENTAGE, 10)
+	coupon.Code = "MARMITA10"
+	coupon.MinOrderValue = 40
+
+	highMinimum := aggregates.NewPromotion("restaurant", "Pedido grande", discounttype.FIXED, 10)
+	highMinimum.MinOrderValue = 100
+
+	wednesday := time.Date(2026, 10, 21, 12, 0, 0, 0, time.Local)
+
+	// act
+	weekdayApplied := weekday.IsAvailableAt(wednesday) && order.ApplyPromotion(weekday)
+	couponApplied := order.ApplyPromotion(coupon)
+	highMinimumApplied := order.ApplyPromotion(highMinimum)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.True(weekdayApplied)
+	assert.False(weekday.IsAvailableAt(wednesday.AddDate(0, 0, 1)), "só vale às quartas")
+	assert.True(couponApplied)
+	assert.False(highMinimumApplied, "pedido abaixo do mínimo")
+	assert.Equal(4.0, order.Items[0].Discount, "R$ 2 por unidade")
+	assert.Equal(4.6, order.Discount, "10% sobre o valor já descontado")
+	assert.Equal(41.4, order.Total)
+	assert.Equal([]string{weekday.Id, coupon.Id}, order.PromotionIds())
+}
+
+func TestClearDiscountsLetsOrderBeRepricedWithoutAPromotion(t *testing.T) {
+	// arrange
+	item := aggregates.NewOrderItem(aggregates.PartialProduct{Id: "marmita-m", Name: "Marmita M"}, 25, 2, "", nil)
+	order := aggregates.NewOrder("restaurant", aggregates.PartialCustomer{}, []aggregates.OrderItem{item}, nil, "")
+
+	exhausted := aggregates.NewPromotion("restaurant", "Marmita com desconto", discounttype.FIXED, 5)
+	exhausted.ProductIds = []string{"marmita-m"}
+	coupon := aggregates.NewPromotion("restaurant", "Dez por cento", discounttype.PERCENTAGE, 10)
+	coupon.Code = "MARMITA10"
+
+	order.ApplyPromotion(exhausted)
+	order.ApplyPromotion(coupon)
+
+	// act
+	order.ClearDiscounts()
+	repriced := order.ApplyPromotion(coupon)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.True(repriced)
+	assert.Equal([]string{coupon.Id}, order.PromotionIds(), "só o cupom continua no pedido")
+	assert.Equal(0.0, order.Items[0].Discount, "desconto da promoção esgotada sai do item")
+	assert.Equal(45.0, order.Total, "cupom recalculado sobre o valor cheio")
+}
@@ -0,0 +1,72 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+)
+
+type (
+	RecipeItem struct {
+		StockItemId string  `json:"stock_item_id"`
+		Quantity    float64 `json:"quantity"`
+	}
+
+	// Recipe é a ficha técnica de um produto, por unidade vendida, ou de um prato, por porção
+	// servida na marmita. Só um dos dois alvos é preenchido.
+	Recipe struct {
+		abstractions.AggregateRoot
+		Restaurant PartialRestaurant `json:"restaurant"`
+		ProductId  string            `json:"product_id,omitempty"`
+		DishId     string            `json:"dish_id,omitempty"`
+		Items      []RecipeItem      `json:"items"`
+		UpdatedAt  time.Time         `json:"updated_at"`
+	}
+)
+
+func NewRecipe(restaurantId, productId, dishId string) *Recipe {
+	return &Recipe{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		ProductId:     productId,
+		DishId:        dishId,
+		Items:         make([]RecipeItem, 0),
+		UpdatedAt:     time.Now(),
+	}
+}
+
+// ConsumptionOf soma o que os itens do pedido consomem de cada item de estoque:
+// a receita do produto por unidade e, nas marmitas, a receita de cada prato por porção
+func ConsumptionOf(items []OrderItem, recipes []Recipe) map[string]float64 {
+	byProduct := make(map[string]*Recipe)
+	byDish := make(map[string]*Recipe)
+	for i := range recipes {
+		if recipes[i].ProductId != "" {
+			byProduct[recipes[i].ProductId] = &recipes[i]
+		} else {
+			byDish[recipes[i].DishId] = &recipes[i]
+		}
+	}
+
+	consumption := make(map[string]float64)
+	add := func(recipe *Recipe, portions int) {
+		if recipe == nil {
+			return
+		}
+		for _, item := range recipe.Items {
+			consumption[item.StockItemId] = roundQuantity(consumption[item.StockItemId] + item.Quantity*float64(portions))
+		}
+	}
+
+	for _, item := range items {
+		add(byProduct[item.Product.Id], item.Quantity)
+		if item.Lunchbox == nil {
+			continue
+		}
+		for _, selection := range item.Lunchbox.Selections {
+			add(byDish[selection.DishId], selection.Quantity*item.Quantity)
+		}
+	}
+
+	return consumption
+}
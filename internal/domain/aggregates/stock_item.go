@@ -0,0 +1,117 @@
+package aggregates
+
+import (
+	"math"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	stockitemkind "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_item_kind"
+	stockmovementtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_movement_type"
+	stockunit "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_unit"
+	"github.com/google/uuid"
+)
+
+// StockItemLowEvent é levantado quando o item cruza o estoque mínimo para baixo
+const StockItemLowEvent abstractions.EventName = "stock_item.low"
+
+type (
+	// StockMovement é uma entrada ou saída de estoque; Quantity é negativa nas saídas
+	StockMovement struct {
+		Id          string                              `json:"id"`
+		StockItemId string                              `json:"stock_item_id"`
+		Type        stockmovementtype.StockMovementType `json:"type"`
+		Quantity    float64                             `json:"quantity"`
+		// custo unitário pago, só nas compras
+		UnitCost  float64   `json:"unit_cost,omitempty"`
+		OrderId   string    `json:"order_id,omitempty"`
+		Note      string    `json:"note"`
+		CreatedBy string    `json:"created_by,omitempty"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// StockItem é um ingrediente ou embalagem controlado na unidade de medida Unit.
+	// O saldo pode ficar negativo: a venda não é barrada por falta de lançamento de compra.
+	StockItem struct {
+		abstractions.AggregateRoot
+		Restaurant PartialRestaurant           `json:"restaurant"`
+		Name       string                      `json:"name"`
+		Kind       stockitemkind.StockItemKind `json:"kind"`
+		Unit       stockunit.StockUnit         `json:"unit"`
+		Quantity   float64                     `json:"quantity"`
+		// no mínimo ou abaixo dele o item entra nos alertas; zero não alerta
+		MinimumQuantity float64 `json:"minimum_quantity"`
+		// custo médio ponderado das compras, por unidade de medida
+		UnitCost  float64   `json:"unit_cost"`
+		Active    bool      `json:"active"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
+
+func NewStockItem(restaurantId, name string, kind stockitemkind.StockItemKind, unit stockunit.StockUnit) *StockItem {
+	now := time.Now()
+	return &StockItem{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Name:          name,
+		Kind:          kind,
+		Unit:          unit,
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (i *StockItem) IsLow() bool {
+	return i.MinimumQuantity > 0 && i.Quantity <= i.MinimumQuantity
+}
+
+// Move lança a movimentação e atualiza o saldo. quantity é sempre positiva, exceto no ajuste,
+// que leva o sinal da correção; compras recalculam o custo médio.
+func (i *StockItem) Move(
+	movementType stockmovementtype.StockMovementType,
+	quantity float64,
+	unitCost float64,
+	orderId string,
+	note string,
+	createdBy string,
+	at time.Time,
+) *StockMovement {
+	delta := quantity
+	switch movementType {
+	case stockmovementtype.CONSUMPTION, stockmovementtype.LOSS:
+		delta = -quantity
+	case stockmovementtype.PURCHASE:
+		// saldo negativo não entra na média: ele é consumo ainda sem compra lançada
+		current := math.Max(i.Quantity, 0)
+		i.UnitCost = (current*i.UnitCost + quantity*unitCost) / (current + quantity)
+	}
+
+	wasLow := i.IsLow()
+	i.Quantity = roundQuantity(i.Quantity + delta)
+	i.UpdatedAt = at
+	if !wasLow && i.IsLow() {
+		i.RaiseDomainEvent(abstractions.NewDomainEvent(StockItemLowEvent, i.Id))
+	}
+
+	if movementType != stockmovementtype.PURCHASE {
+		unitCost = 0
+	}
+
+	return &StockMovement{
+		Id:          uuid.NewString(),
+		StockItemId: i.Id,
+		Type:        movementType,
+		Quantity:    roundQuantity(delta),
+		UnitCost:    unitCost,
+		OrderId:     orderId,
+		Note:        note,
+		CreatedBy:   createdBy,
+		CreatedAt:   at,
+	}
+}
+
+// as quantidades de estoque guardam três casas: gramas e mililitros
+func roundQuantity(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -0,0 +1,38 @@
+package aggregates
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+)
+
+type (
+	PartialSupplier struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// Supplier é um fornecedor do restaurante; Document é o CNPJ ou CPF, só dígitos
+	Supplier struct {
+		abstractions.AggregateRoot
+		Restaurant PartialRestaurant `json:"restaurant"`
+		Name       string            `json:"name"`
+		Document   string            `json:"document,omitempty"`
+		Email      string            `json:"email,omitempty"`
+		Phone      string            `json:"phone,omitempty"`
+		Note       string            `json:"note,omitempty"`
+		CreatedAt  time.Time         `json:"created_at"`
+		UpdatedAt  time.Time         `json:"updated_at"`
+	}
+)
+
+func NewSupplier(restaurantId, name string) *Supplier {
+	now := time.Now()
+	return &Supplier{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Name:          name,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
@@ -0,0 +1,152 @@
+package aggregates
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	webhookdeliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/webhook_delivery_status"
+)
+
+// WebhookTestEvent é o evento enviado pelo botão de teste; não vem do barramento
+const WebhookTestEvent abstractions.EventName = "webhook.test"
+
+const (
+	webhookMaxAttempts = 8
+	// a espera dobra a cada falha: 30s, 1min, 2min... a última tentativa sai cerca de uma hora depois da primeira
+	webhookRetryBase = 30 * time.Second
+)
+
+// WebhookEvents são os eventos de domínio que podem ser assinados
+var WebhookEvents = []abstractions.EventName{
+	OrderCreatedEvent,
+	OrderUpdatedEvent,
+	OrderStatusChangedEvent,
+	OrderCancelledEvent,
+	OrderCompletedEvent,
+	OrderPaidEvent,
+	ProductCreatedEvent,
+	ProductUpdatedEvent,
+	StockItemLowEvent,
+	CashSessionClosedEvent,
+}
+
+type (
+	// WebhookSubscription envia os eventos escolhidos para a URL do integrador, assinados com Secret
+	WebhookSubscription struct {
+		abstractions.AggregateRoot
+		Restaurant  PartialRestaurant        `json:"restaurant"`
+		Url         string                   `json:"url"`
+		Secret      string                   `json:"secret"`
+		Events      []abstractions.EventName `json:"events"`
+		Description string                   `json:"description,omitempty"`
+		Active      bool                     `json:"active"`
+		CreatedAt   time.Time                `json:"created_at"`
+		UpdatedAt   time.Time                `json:"updated_at"`
+	}
+
+	// WebhookDelivery é um evento a caminho de uma assinatura; Payload é o corpo exato enviado,
+	// para que as novas tentativas tenham a mesma assinatura HMAC
+	WebhookDelivery struct {
+		abstractions.AggregateRoot
+		SubscriptionId string                                      `json:"subscription_id"`
+		RestaurantId   string                                      `json:"restaurant_id"`
+		EventId        string                                      `json:"event_id"`
+		Event          abstractions.EventName                      `json:"event"`
+		Payload        string                                      `json:"payload"`
+		Status         webhookdeliverystatus.WebhookDeliveryStatus `json:"status"`
+		Attempts       int                                         `json:"attempts"`
+		NextAttemptAt  *time.Time                                  `json:"next_attempt_at,omitempty"`
+		LastStatusCode int                                         `json:"last_status_code,omitempty"`
+		LastError      string                                      `json:"last_error,omitempty"`
+		CreatedAt      time.Time                                   `json:"created_at"`
+		DeliveredAt    *time.Time                                  `json:"delivered_at,omitempty"`
+	}
+)
+
+func IsWebhookEvent(name abstractions.EventName) bool {
+	return slices.Contains(WebhookEvents, name)
+}
+
+func NewWebhookSubscription(restaurantId, url, secret string, events []abstractions.EventName) *WebhookSubscription {
+	now := time.Now()
+	return &WebhookSubscription{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Restaurant:    PartialRestaurant{Id: restaurantId},
+		Url:           url,
+		Secret:        secret,
+		Events:        events,
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (s *WebhookSubscription) Subscribes(event abstractions.EventName) bool {
+	return s.Active && slices.Contains(s.Events, event)
+}
+
+func NewWebhookDelivery(subscription *WebhookSubscription, eventId string, event abstractions.EventName, payload string) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		AggregateRoot:  abstractions.NewAggregateRoot(),
+		SubscriptionId: subscription.Id,
+		RestaurantId:   subscription.Restaurant.Id,
+		EventId:        eventId,
+		Event:          event,
+		Payload:        payload,
+		Status:         webhookdeliverystatus.PENDING,
+		NextAttemptAt:  &now,
+		CreatedAt:      now,
+	}
+}
+
+// RecordAttempt registra o resultado de um envio; só 2xx conta como entregue. Depois da última
+// tentativa a entrega vai para a lista de mortas
+func (d *WebhookDelivery) RecordAttempt(statusCode int, err error, at time.Time) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+
+	if err == nil && statusCode >= 200 && statusCode < 300 {
+		d.Status = webhookdeliverystatus.DELIVERED
+		d.DeliveredAt = &at
+		d.NextAttemptAt = nil
+		d.LastError = ""
+		return
+	}
+
+	if err != nil {
+		d.LastError = err.Error()
+	} else {
+		d.LastError = fmt.Sprintf("unexpected status %d", statusCode)
+	}
+
+	if d.Attempts >= webhookMaxAttempts {
+		d.Status = webhookdeliverystatus.DEAD
+		d.NextAttemptAt = nil
+		return
+	}
+
+	next := at.Add(webhookRetryBase << (d.Attempts - 1))
+	d.NextAttemptAt = &next
+}
+
+// Abandon manda a entrega direto para as mortas, sem tentar enviar
+func (d *WebhookDelivery) Abandon(reason string) {
+	d.Status = webhookdeliverystatus.DEAD
+	d.NextAttemptAt = nil
+	d.LastError = reason
+}
+
+// Redeliver devolve uma entrega morta para a fila, com as tentativas zeradas
+func (d *WebhookDelivery) Redeliver(at time.Time) bool {
+	if d.Status != webhookdeliverystatus.DEAD {
+		return false
+	}
+
+	d.Status = webhookdeliverystatus.PENDING
+	d.Attempts = 0
+	d.NextAttemptAt = &at
+	return true
+}
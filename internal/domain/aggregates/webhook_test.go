@@ -0,0 +1,42 @@
+package aggregates_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	webhookdeliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/webhook_delivery_status"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDeliveryBacksOffUntilDead(t *testing.T) {
+	// arrange
+	subscription := aggregates.NewWebhookSubscription("restaurant", "https://erp.example.com/hooks", "segredo-de-teste-123", nil)
+	delivery := aggregates.NewWebhookDelivery(subscription, "event", aggregates.OrderCreatedEvent, `{}`)
+	at := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	// act
+	delivery.RecordAttempt(503, nil, at)
+	firstRetry := *delivery.NextAttemptAt
+	delivery.RecordAttempt(0, errors.New("connection refused"), at)
+	secondRetry := *delivery.NextAttemptAt
+	redeliveredBeforeDead := delivery.Redeliver(at)
+	for delivery.Status == webhookdeliverystatus.PENDING {
+		delivery.RecordAttempt(500, nil, at)
+	}
+	dead := delivery.Status
+	redelivered := delivery.Redeliver(at)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(at.Add(30*time.Second), firstRetry)
+	assert.Equal(at.Add(time.Minute), secondRetry, "a espera dobra")
+	assert.False(redeliveredBeforeDead, "só entrega morta volta para a fila")
+	assert.Equal(webhookdeliverystatus.DEAD, dead)
+	assert.Equal("unexpected status 500", delivery.LastError)
+	assert.True(redelivered)
+	assert.Equal(webhookdeliverystatus.PENDING, delivery.Status)
+	assert.Zero(delivery.Attempts)
+}
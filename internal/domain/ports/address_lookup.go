@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"errors"
+
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrInvalidCep  = errors.New("cep must have 8 digits")
+	ErrCepNotFound = errors.New("cep not found")
+)
+
+// IAddressLookup completa o endereço a partir do CEP. Sem o serviço externo a resposta vem só com
+// CEP, UF e país, e City vazio indica que a cidade não pôde ser conferida
+type IAddressLookup interface {
+	LookupCep(cep string) (*types.Address, error)
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "github.com/PedroNetto404/marmitech-backend/pkg/types"
+
+type IAddressRepository interface {
+	// FindWithoutCoordinates pagina pelo id os endereços de restaurantes e clientes ainda em 0,0
+	FindWithoutCoordinates(afterId string, limit int) ([]types.Address, error)
+	UpdateCoordinates(id string, point types.GeoPoint) error
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+type (
+	AuditLogQuery struct {
+		RestaurantId  string
+		AggregateType string
+		AggregateId   string
+		ActorEmail    string
+		Action        aggregates.AuditAction
+		From          *time.Time
+		To            *time.Time
+		Limit         int
+		Offset        int
+	}
+
+	IAuditLogRepository interface {
+		Find(query AuditLogQuery) (*types.PagedSlice[aggregates.AuditLog], error)
+	}
+)
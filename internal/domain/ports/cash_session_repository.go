@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"errors"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	paymentmethod "github.com/PedroNetto404/marmitech-backend/pkg/enums/payment_method"
+)
+
+// ErrCashSessionAlreadyOpen indica que o operador já tem um caixa aberto no restaurante
+var ErrCashSessionAlreadyOpen = errors.New("operator already has an open cash session")
+
+type (
+	// CashSessionQuery filtra as sessões abertas em [From, To); Operator vazio traz todos
+	CashSessionQuery struct {
+		RestaurantId string
+		Operator     string
+		From         time.Time
+		To           time.Time
+	}
+
+	ICashSessionRepository interface {
+		FindById(id string) (*aggregates.CashSession, error)
+		FindOpen(restaurantId, operator string) (*aggregates.CashSession, error)
+		Find(query CashSessionQuery) ([]aggregates.CashSession, error)
+		Create(session *aggregates.CashSession) error
+		AddMovement(session *aggregates.CashSession, movement *aggregates.CashMovement) error
+		// SumPayments soma os pagamentos recebidos na sessão por forma de pagamento
+		SumPayments(sessionId string) (map[paymentmethod.PaymentMethod]float64, error)
+		// Close grava o fechamento e a contagem; compare-and-swap pela versão
+		Close(session *aggregates.CashSession) error
+	}
+)
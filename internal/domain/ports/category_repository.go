@@ -9,4 +9,6 @@ type ICategoryRepository interface {
 		name string,
 	) (bool, error)
 	FindByRestaurantId(restaurantId string) ([]aggregates.Category, error)
+	// Reorder grava as categorias na ordem recebida, com prioridades 1..n, em uma única transação
+	Reorder(restaurantId string, categories []aggregates.Category) error
 }
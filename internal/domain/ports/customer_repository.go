@@ -3,5 +3,7 @@ package ports
 import "github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
 
 type ICustomerRepository interface {
-	Create(customer *aggregates.Customer) error
-}
\ No newline at end of file
+	IRepository[aggregates.Customer]
+	FindByEmail(email string) (*aggregates.Customer, error)
+	Exists(email string) (bool, error)
+}
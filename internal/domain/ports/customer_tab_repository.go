@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"errors"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+)
+
+// ErrOrderAlreadyOnTab indica que o pedido já foi lançado no fiado
+var ErrOrderAlreadyOnTab = errors.New("order is already on the customer tab")
+
+type ICustomerTabRepository interface {
+	FindById(id string) (*aggregates.CustomerTab, error)
+	FindByCustomerId(restaurantId, customerId string) (*aggregates.CustomerTab, error)
+	// FindOpen lista as contas com saldo devedor, as vencidas primeiro
+	FindOpen(restaurantId string, onlyOverdue bool) ([]aggregates.CustomerTab, error)
+	Create(tab *aggregates.CustomerTab) error
+	Update(tab *aggregates.CustomerTab) error
+	// AddEntry grava o lançamento e o novo saldo da conta na mesma transação; devolve
+	// ErrOrderAlreadyOnTab se o débito repete um pedido da conta
+	AddEntry(tab *aggregates.CustomerTab, entry *aggregates.TabEntry) error
+	FindEntries(tabId string, from, to *time.Time) ([]aggregates.TabEntry, error)
+}
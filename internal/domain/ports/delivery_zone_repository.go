@@ -0,0 +1,12 @@
+package ports
+
+import "github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+
+type IDeliveryZoneRepository interface {
+	FindById(id string) (*aggregates.DeliveryZone, error)
+	// FindByRestaurantId lista as zonas por prioridade e nome, inclusive as inativas
+	FindByRestaurantId(restaurantId string) ([]aggregates.DeliveryZone, error)
+	Create(zone *aggregates.DeliveryZone) error
+	Update(zone *aggregates.DeliveryZone) error
+	Delete(zone *aggregates.DeliveryZone) error
+}
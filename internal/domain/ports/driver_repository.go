@@ -0,0 +1,58 @@
+package ports
+
+import (
+	"errors"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	deliveryroutestatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_route_status"
+)
+
+// ErrOrderInActiveRoute indica que outro despacho levou o pedido entre a validação e a gravação da rota
+var ErrOrderInActiveRoute = errors.New("order is already in an active delivery route")
+
+type (
+	// DeliveryRouteQuery lista as rotas mais recentes primeiro; campos vazios não filtram
+	DeliveryRouteQuery struct {
+		RestaurantId string
+		DriverId     string
+		Statuses     []deliveryroutestatus.DeliveryRouteStatus
+		Limit        int
+	}
+
+	// DriverSettlement soma as paradas entregues pelo entregador em [From, To) da ReportQuery
+	DriverSettlement struct {
+		DriverId   string
+		DriverName string
+		Deliveries int
+		Fees       float64
+	}
+
+	IDriverRepository interface {
+		FindById(id string) (*aggregates.Driver, error)
+		FindByRestaurantId(restaurantId string) ([]aggregates.Driver, error)
+		FindByEmail(restaurantId, email string) (*aggregates.Driver, error)
+		Create(driver *aggregates.Driver) error
+		Update(driver *aggregates.Driver) error
+		Delete(driver *aggregates.Driver) error
+	}
+
+	IDeliveryRouteRepository interface {
+		FindById(id string) (*aggregates.DeliveryRoute, error)
+		Find(query DeliveryRouteQuery) ([]aggregates.DeliveryRoute, error)
+		// FindActiveByOrderIds devolve as rotas em andamento que já levam algum dos pedidos
+		FindActiveByOrderIds(orderIds []string) ([]aggregates.DeliveryRoute, error)
+		// Create e Update gravam junto, na mesma transação, os pedidos cujo status de entrega a rota mudou;
+		// Create trava os pedidos e devolve ErrOrderInActiveRoute se algum já estiver em rota em andamento
+		Create(route *aggregates.DeliveryRoute, orders []*aggregates.Order) error
+		Update(route *aggregates.DeliveryRoute, orders []*aggregates.Order) error
+		Settlement(query ReportQuery) ([]DriverSettlement, error)
+	}
+
+	IDriverLocationRepository interface {
+		Save(location *aggregates.DriverLocation) error
+		FindLatest(driverId string) (*aggregates.DriverLocation, error)
+		// FindByRoute devolve o trajeto da rota em ordem cronológica, a partir de since
+		FindByRoute(routeId string, since time.Time) ([]aggregates.DriverLocation, error)
+	}
+)
@@ -0,0 +1,21 @@
+package ports
+
+import "github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+
+type (
+	IEventPublisher interface {
+		Publish(events ...abstractions.DomainEvent)
+	}
+
+	// IEventBus entrega os eventos de domínio publicados aos assinantes.
+	// Subscribe é ao vivo: só recebe o que for publicado enquanto a assinatura existir,
+	// e a função devolvida encerra a assinatura e fecha o canal.
+	// Consume registra um consumidor durável pelo nome: cada evento chega ao menos uma vez
+	// e em ordem, e um erro devolvido pelo handler faz o mesmo evento ser entregue de novo,
+	// por isso o handler precisa ser idempotente.
+	IEventBus interface {
+		IEventPublisher
+		Subscribe() (<-chan abstractions.DomainEvent, func())
+		Consume(consumer string, handle func(event abstractions.DomainEvent) error) func()
+	}
+)
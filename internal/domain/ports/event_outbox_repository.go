@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+)
+
+type (
+	// OutboxEvent é um evento de domínio gravado na mesma transação do agregado que o levantou.
+	// Sequence cresce na ordem de gravação, mas pode ter lacunas e confirmar fora de ordem.
+	OutboxEvent struct {
+		Sequence   int64
+		RecordedAt time.Time
+		Event      abstractions.DomainEvent
+	}
+
+	IEventOutboxRepository interface {
+		FindAfter(sequence int64, limit int) ([]OutboxEvent, error)
+		LastSequence() (int64, error)
+		// FindCursor devolve false quando o consumidor ainda não tem posição gravada
+		FindCursor(consumer string) (int64, bool, error)
+		// SaveCursor também descarta as entregas até a posição, que deixam de ser consultadas
+		SaveCursor(consumer string, sequence int64) error
+		// FindDelivered lista os eventos acima da posição que o consumidor já entregou
+		FindDelivered(consumer string, after int64) ([]int64, error)
+		MarkDelivered(consumer string, sequence int64) error
+	}
+)
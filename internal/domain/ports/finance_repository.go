@@ -0,0 +1,41 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	billstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/bill_status"
+)
+
+type (
+	// BillQuery filtra as contas pelo vencimento em [DueFrom, DueTo]; campos vazios não filtram
+	BillQuery struct {
+		RestaurantId string
+		SupplierId   string
+		Status       billstatus.BillStatus
+		DueFrom      *time.Time
+		DueTo        *time.Time
+	}
+
+	ISupplierRepository interface {
+		FindById(id string) (*aggregates.Supplier, error)
+		FindByRestaurantId(restaurantId string) ([]aggregates.Supplier, error)
+		FindByDocument(restaurantId, document string) (*aggregates.Supplier, error)
+		Create(supplier *aggregates.Supplier) error
+		Update(supplier *aggregates.Supplier) error
+		// Delete mantém as contas do fornecedor, que ficam sem fornecedor
+		Delete(supplier *aggregates.Supplier) error
+	}
+
+	IBillRepository interface {
+		FindById(id string) (*aggregates.Bill, error)
+		// Find ordena pelo vencimento, as mais antigas primeiro
+		Find(query BillQuery) ([]aggregates.Bill, error)
+		Create(bill *aggregates.Bill) error
+		// Update grava o cadastro e o status; pagamentos só entram por AddPayment
+		Update(bill *aggregates.Bill) error
+		// AddPayment grava o pagamento e o novo saldo da conta na mesma transação;
+		// next é a próxima ocorrência da série, criada junto quando a conta é quitada
+		AddPayment(bill *aggregates.Bill, payment *aggregates.BillPayment, next *aggregates.Bill) error
+	}
+)
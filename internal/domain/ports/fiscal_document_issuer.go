@@ -0,0 +1,50 @@
+package ports
+
+import (
+	"errors"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+)
+
+var (
+	// dados do emitente ou dos produtos que o emissor não sabe representar na nota
+	ErrUnsupportedFiscalData = errors.New("unsupported fiscal data")
+	// falha de comunicação com a SEFAZ; a nota continua pendente e pode ser reenviada
+	ErrSefazUnavailable = errors.New("sefaz unavailable")
+)
+
+type (
+	// FiscalIssueRequest traz tudo o que a nota precisa; Products é indexado pelo id do produto
+	FiscalIssueRequest struct {
+		Restaurant  *aggregates.Restaurant
+		Profile     *aggregates.FiscalProfile
+		Order       *aggregates.Order
+		Products    map[string]aggregates.Product
+		Document    *aggregates.FiscalDocument
+		Certificate []byte
+	}
+
+	// FiscalAuthorization é o retorno da SEFAZ; Authorized falso indica rejeição
+	FiscalAuthorization struct {
+		Authorized   bool
+		AccessKey    string
+		Protocol     string
+		StatusCode   string
+		StatusReason string
+		Xml          string
+		QrCodeUrl    string
+		AuthorizedAt time.Time
+	}
+
+	FiscalCertificate struct {
+		Subject  string
+		NotAfter time.Time
+	}
+
+	// IFiscalDocumentIssuer gera, assina e transmite o documento fiscal para a SEFAZ
+	IFiscalDocumentIssuer interface {
+		Issue(request FiscalIssueRequest) (*FiscalAuthorization, error)
+		InspectCertificate(pfx []byte, password string) (*FiscalCertificate, error)
+	}
+)
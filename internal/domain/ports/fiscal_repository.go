@@ -0,0 +1,20 @@
+package ports
+
+import "github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+
+type (
+	IFiscalProfileRepository interface {
+		FindByRestaurantId(restaurantId string) (*aggregates.FiscalProfile, error)
+		Create(profile *aggregates.FiscalProfile) error
+		Update(profile *aggregates.FiscalProfile) error
+		// EncryptLegacySecrets cifra a senha do certificado e o token CSC dos perfis gravados
+		// antes da cifra existir
+		EncryptLegacySecrets() (int, error)
+	}
+
+	IFiscalDocumentRepository interface {
+		FindByOrderId(orderId string) (*aggregates.FiscalDocument, error)
+		Create(document *aggregates.FiscalDocument) error
+		Update(document *aggregates.FiscalDocument) error
+	}
+)
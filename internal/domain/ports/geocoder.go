@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"errors"
+
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+// o provedor respondeu, mas não encontrou o endereço
+var ErrAddressNotGeocoded = errors.New("address could not be geocoded")
+
+type (
+	IGeocoder interface {
+		Geocode(address types.Address) (types.GeoPoint, error)
+	}
+
+	// IGeocodeCacheRepository guarda as coordenadas já resolvidas pela chave normalizada do endereço
+	IGeocodeCacheRepository interface {
+		Find(key string) (*types.GeoPoint, error)
+		Save(key string, point types.GeoPoint) error
+	}
+)
@@ -0,0 +1,34 @@
+package ports
+
+import "time"
+
+type (
+	// IdempotencyScope identifica a chave: a mesma Idempotency-Key enviada por outro usuário
+	// ou para outro restaurante é outra chave
+	IdempotencyScope struct {
+		RestaurantId string
+		Principal    string
+		Key          string
+	}
+
+	// IdempotencyRecord guarda a resposta original de uma requisição identificada por Idempotency-Key.
+	// StatusCode igual a zero indica que a requisição original ainda está em processamento.
+	IdempotencyRecord struct {
+		Scope       IdempotencyScope
+		Fingerprint string
+		StatusCode  int
+		ContentType string
+		Body        []byte
+		CreatedAt   time.Time
+		ExpiresAt   time.Time
+	}
+
+	IIdempotencyRepository interface {
+		// Reserve retorna false quando a chave já existe
+		Reserve(record *IdempotencyRecord) (bool, error)
+		Find(scope IdempotencyScope) (*IdempotencyRecord, error)
+		Complete(record *IdempotencyRecord) error
+		Delete(scope IdempotencyScope) error
+		DeleteExpired(now time.Time) (int64, error)
+	}
+)
@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	stockmovementtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_movement_type"
+)
+
+type (
+	IStockItemRepository interface {
+		FindById(id string) (*aggregates.StockItem, error)
+		// FindByRestaurantId lista o estoque atual; onlyLow traz só os itens no mínimo ou abaixo dele
+		FindByRestaurantId(restaurantId string, onlyLow bool) ([]aggregates.StockItem, error)
+		Create(item *aggregates.StockItem) error
+		// Update grava o cadastro; saldo e custo só mudam pelas movimentações
+		Update(item *aggregates.StockItem) error
+		// AddMovement grava a movimentação com o novo saldo e custo do item na mesma transação
+		AddMovement(item *aggregates.StockItem, movement *aggregates.StockMovement) error
+		// AddOrderMovements soma as movimentações de um pedido ao saldo atual dos itens, sem
+		// compare-and-swap: pedidos simultâneos não disputam a versão dos mesmos ingredientes.
+		// Os eventos levantados pelos itens (estoque baixo) vão para a outbox na mesma transação.
+		AddOrderMovements(items []*aggregates.StockItem, movements []*aggregates.StockMovement) error
+		HasOrderMovements(orderId string, movementType stockmovementtype.StockMovementType) (bool, error)
+		// FindOrdersPendingConsumption lista os pedidos não cancelados criados no intervalo que têm
+		// ficha técnica e nenhuma baixa de consumo lançada
+		FindOrdersPendingConsumption(from, to time.Time, afterId string, limit int) ([]string, error)
+		FindMovements(stockItemId string, from, to *time.Time) ([]aggregates.StockMovement, error)
+	}
+
+	IRecipeRepository interface {
+		FindByProductId(productId string) (*aggregates.Recipe, error)
+		FindByDishId(dishId string) (*aggregates.Recipe, error)
+		FindByRestaurantId(restaurantId string) ([]aggregates.Recipe, error)
+		Create(recipe *aggregates.Recipe) error
+		Update(recipe *aggregates.Recipe) error
+	}
+)
@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+)
+
+type (
+	ILoyaltyProgramRepository interface {
+		FindByRestaurantId(restaurantId string) (*aggregates.LoyaltyProgram, error)
+		Create(program *aggregates.LoyaltyProgram) error
+		Update(program *aggregates.LoyaltyProgram) error
+	}
+
+	ILoyaltyAccountRepository interface {
+		FindByCustomerId(restaurantId, customerId string) (*aggregates.LoyaltyAccount, error)
+		Create(account *aggregates.LoyaltyAccount) error
+		// AddEntries grava os lançamentos e o novo saldo da conta na mesma transação
+		AddEntries(account *aggregates.LoyaltyAccount, entries ...*aggregates.LoyaltyEntry) error
+		// FindEntries lista os lançamentos da conta, os mais antigos primeiro
+		FindEntries(accountId string) ([]aggregates.LoyaltyEntry, error)
+		// FindOrdersPendingEarn lista os pedidos concluídos, criados no intervalo, de clientes
+		// identificados em restaurantes com programa ativo que ainda não têm lançamento de pontos
+		FindOrdersPendingEarn(from, to time.Time, afterId string, limit int) ([]string, error)
+	}
+)
@@ -0,0 +1,83 @@
+package ports
+
+import (
+	"errors"
+	"time"
+
+	orderchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_channel"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+var (
+	ErrInvalidMarketplaceSignature = errors.New("invalid marketplace signature")
+	// o marketplace recusou a chamada, em geral porque o pedido já está em outra etapa lá; não adianta repetir
+	ErrMarketplaceRejected = errors.New("marketplace rejected the request")
+)
+
+// MarketplaceEventCode é o que aconteceu com o pedido no marketplace; códigos que o
+// restaurante não trata chegam como vieram e só são reconhecidos
+type MarketplaceEventCode string
+
+const (
+	MarketplaceOrderPlaced    MarketplaceEventCode = "placed"
+	MarketplaceOrderCancelled MarketplaceEventCode = "cancelled"
+)
+
+// MarketplaceAction é a mudança de status devolvida ao marketplace
+type MarketplaceAction string
+
+const (
+	MarketplaceConfirm          MarketplaceAction = "confirm"
+	MarketplaceStartPreparation MarketplaceAction = "start_preparation"
+	MarketplaceReadyForPickup   MarketplaceAction = "ready_for_pickup"
+	MarketplaceDispatch         MarketplaceAction = "dispatch"
+	MarketplaceCancel           MarketplaceAction = "cancel"
+)
+
+type (
+	MarketplaceEvent struct {
+		Id         string
+		Code       MarketplaceEventCode
+		OrderId    string
+		MerchantId string
+		CreatedAt  time.Time
+	}
+
+	// MarketplaceOrderItem traz o preço cobrado pelo marketplace, já com os complementos escolhidos
+	MarketplaceOrderItem struct {
+		ExternalCode string
+		Name         string
+		Quantity     int
+		UnitPrice    float64
+		Observation  string
+	}
+
+	// MarketplaceOrderDetails é o pedido como o marketplace o vendeu; Delivery é nil na retirada
+	MarketplaceOrderDetails struct {
+		Id          string
+		Code        string
+		MerchantId  string
+		Items       []MarketplaceOrderItem
+		Delivery    *types.Address
+		DeliveryFee float64
+		Discount    float64
+		// Prepaid é o que o cliente já pagou online; o restante é cobrado na entrega ou na retirada
+		Prepaid     float64
+		Observation string
+		CreatedAt   time.Time
+	}
+
+	// IMarketplaceProvider conversa com a API de um marketplace; os pedidos chegam pela busca
+	// periódica ou pelo webhook do marketplace e as mudanças de status voltam por SendAction
+	IMarketplaceProvider interface {
+		Channel() orderchannel.OrderChannel
+		// Poll busca os eventos ainda não reconhecidos das lojas informadas
+		Poll(merchantIds []string) ([]MarketplaceEvent, error)
+		// Acknowledge confirma o recebimento; eventos não reconhecidos voltam na próxima busca
+		Acknowledge(events []MarketplaceEvent) error
+		FetchOrder(orderId string) (*MarketplaceOrderDetails, error)
+		SendAction(orderId string, action MarketplaceAction, reason string) error
+		// ParseWebhook confere a assinatura do envio e devolve os eventos
+		ParseWebhook(body []byte, signature string) ([]MarketplaceEvent, error)
+	}
+)
@@ -0,0 +1,58 @@
+package ports
+
+import (
+	"errors"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	marketplaceorderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/marketplace_order_status"
+	orderchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_channel"
+)
+
+var (
+	// a loja do marketplace já está ligada a outro restaurante
+	ErrMarketplaceMerchantTaken = errors.New("marketplace merchant is already linked")
+	// o código externo já está vinculado a um produto
+	ErrMarketplaceMappingExists = errors.New("marketplace item is already mapped")
+)
+
+type (
+	IMarketplaceIntegrationRepository interface {
+		FindById(id string) (*aggregates.MarketplaceIntegration, error)
+		FindByRestaurantId(restaurantId string) ([]aggregates.MarketplaceIntegration, error)
+		FindByMerchantId(channel orderchannel.OrderChannel, merchantId string) (*aggregates.MarketplaceIntegration, error)
+		FindActive(channel orderchannel.OrderChannel) ([]aggregates.MarketplaceIntegration, error)
+		Create(integration *aggregates.MarketplaceIntegration) error
+		Update(integration *aggregates.MarketplaceIntegration) error
+		Delete(integration *aggregates.MarketplaceIntegration) error
+	}
+
+	IMarketplaceMappingRepository interface {
+		FindById(id string) (*aggregates.MarketplaceMapping, error)
+		FindByRestaurantId(restaurantId string, channel orderchannel.OrderChannel) ([]aggregates.MarketplaceMapping, error)
+		// FindByExternalCodes devolve os vínculos encontrados indexados pelo código externo
+		FindByExternalCodes(restaurantId string, channel orderchannel.OrderChannel, externalCodes []string) (map[string]aggregates.MarketplaceMapping, error)
+		Create(mapping *aggregates.MarketplaceMapping) error
+		Update(mapping *aggregates.MarketplaceMapping) error
+		Delete(mapping *aggregates.MarketplaceMapping) error
+	}
+
+	IMarketplaceOrderRepository interface {
+		FindById(id string) (*aggregates.MarketplaceOrder, error)
+		FindByExternalId(channel orderchannel.OrderChannel, externalId string) (*aggregates.MarketplaceOrder, error)
+		FindByOrderId(orderId string) (*aggregates.MarketplaceOrder, error)
+		// FindByRestaurantId lista os mais recentes primeiro; status vazio não filtra
+		FindByRestaurantId(restaurantId string, status marketplaceorderstatus.MarketplaceOrderStatus, limit int) ([]aggregates.MarketplaceOrder, error)
+		// Reserve grava o pedido recebido; falso quando ele já tinha chegado antes
+		Reserve(order *aggregates.MarketplaceOrder) (bool, error)
+		Update(order *aggregates.MarketplaceOrder) error
+	}
+
+	IMarketplacePushRepository interface {
+		Create(push *aggregates.MarketplacePush) error
+		Update(push *aggregates.MarketplacePush) error
+		// FindDue lista os envios pendentes já vencidos, dos mais antigos para os mais novos; um envio
+		// fica de fora enquanto houver outro pendente, criado antes, para o mesmo pedido
+		FindDue(now time.Time, limit int) ([]aggregates.MarketplacePush, error)
+	}
+)
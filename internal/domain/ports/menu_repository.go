@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"errors"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+)
+
+// ErrMenuItemSoldOut indica que o item do cardápio esgotou ou foi desabilitado entre a avaliação e a gravação do pedido
+var ErrMenuItemSoldOut = errors.New("menu item is sold out")
+
+type IMenuRepository interface {
+	FindByOfferDate(restaurantId string, offerDate time.Time) (*aggregates.Menu, error)
+	FindByItemId(menuItemId string) (*aggregates.Menu, error)
+	// UpdateItem grava o item junto com a auditoria anexada ao cardápio
+	UpdateItem(menu *aggregates.Menu, menuItem *aggregates.MenuItem) error
+}
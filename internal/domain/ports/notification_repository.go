@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+)
+
+type (
+	INotificationPreferencesRepository interface {
+		// FindByCustomerId devolve nil quando o cliente nunca mudou as preferências
+		FindByCustomerId(customerId string) (*aggregates.NotificationPreferences, error)
+		Create(preferences *aggregates.NotificationPreferences) error
+		Update(preferences *aggregates.NotificationPreferences) error
+	}
+
+	INotificationRepository interface {
+		// Reserve grava a notificação pendente; devolve false quando o mesmo modelo já foi
+		// reservado para o pedido naquele canal
+		Reserve(notification *aggregates.Notification) (bool, error)
+		Update(notification *aggregates.Notification) error
+		// FindDue lista as notificações pendentes cuja próxima tentativa já venceu, das mais antigas para as mais novas
+		FindDue(now time.Time, limit int) ([]aggregates.Notification, error)
+		FindByOrderId(orderId string) ([]aggregates.Notification, error)
+	}
+)
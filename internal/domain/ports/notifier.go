@@ -0,0 +1,43 @@
+package ports
+
+import (
+	"errors"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	notificationchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_channel"
+)
+
+var (
+	// o provedor recusou a mensagem (destinatário inválido, credencial errada); não adianta reenviar
+	ErrNotificationRejected        = errors.New("notification rejected by provider")
+	ErrUnknownNotificationTemplate = errors.New("unknown notification template")
+)
+
+type (
+	// NotificationMessage é a mensagem já renderizada; Subject só é usado no e-mail
+	NotificationMessage struct {
+		Channel   notificationchannel.NotificationChannel
+		Recipient string
+		Subject   string
+		Body      string
+	}
+
+	// NotificationData é o que os modelos de mensagem podem usar
+	NotificationData struct {
+		Restaurant *aggregates.Restaurant
+		Order      *aggregates.Order
+		Customer   *aggregates.Customer
+	}
+
+	// INotifier entrega mensagens por um canal; erros que não são ErrNotificationRejected
+	// são tratados como falhas temporárias e reenviados
+	INotifier interface {
+		Channel() notificationchannel.NotificationChannel
+		Send(message NotificationMessage) error
+	}
+
+	// INotificationRenderer monta o assunto e o texto da mensagem a partir do modelo
+	INotificationRenderer interface {
+		Render(template aggregates.NotificationTemplate, data NotificationData) (subject string, body string, err error)
+	}
+)
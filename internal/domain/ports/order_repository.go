@@ -1,7 +1,61 @@
 package ports
 
-import "github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+// OrderBalance é o que falta receber de um pedido
+type OrderBalance struct {
+	OrderId   string
+	CreatedAt time.Time
+	Balance   float64
+}
+
+// OrderCancellation é o que o pedido cancelado devolve; é gravado na mesma transação do cancelamento
+type OrderCancellation struct {
+	// um uso de cada promoção, inclusive o limite do cupom
+	PromotionIds []string
+	// porções por item do cardápio das marmitas que a cozinha ainda não montou
+	MenuPortions map[string]int
+	// estorno dos pontos resgatados no pedido; nil quando não há o que estornar
+	LoyaltyAccount *aggregates.LoyaltyAccount
+	LoyaltyRefund  *aggregates.LoyaltyEntry
+}
+
+// OrderRedemption é o resgate de pontos do pedido; é gravado na mesma transação da criação
+type OrderRedemption struct {
+	// nil quando o pedido não resgata recompensa
+	LoyaltyAccount *aggregates.LoyaltyAccount
+	LoyaltyRedeem  *aggregates.LoyaltyEntry
+}
+
+// OrderTabDebit é o lançamento no fiado do pagamento pós-pago; é gravado na mesma transação do pagamento
+type OrderTabDebit struct {
+	// nil quando o pagamento não vai para o fiado
+	Tab   *aggregates.CustomerTab
+	Entry *aggregates.TabEntry
+}
 
 type IOrderRepository interface {
-	IRepository[aggregates.Order]
+	Find(args types.FindArgs) (*types.PagedSlice[aggregates.Order], error)
+	FindById(id string) (*aggregates.Order, error)
+	// Create grava o pedido junto com o resgate de pontos, se houver
+	Create(order *aggregates.Order, redemption OrderRedemption) error
+	Update(order *aggregates.Order) error
+	Delete(order *aggregates.Order) error
+	// FindInKitchen lista os pedidos ainda na fila da cozinha, do mais antigo para o mais novo
+	FindInKitchen(restaurantId string) ([]aggregates.Order, error)
+	// CountByCustomerId conta os pedidos não cancelados do cliente no restaurante
+	CountByCustomerId(restaurantId, customerId string) (int, error)
+	// AddPayment grava o pagamento e sobe a versão do pedido, para que dois pagamentos
+	// simultâneos não passem do total; o pagamento pós-pago entra no fiado na mesma transação
+	AddPayment(order *aggregates.Order, payment *aggregates.OrderPayment, tabDebit OrderTabDebit) error
+	// Cancel grava o pedido cancelado junto com tudo o que ele devolve
+	Cancel(order *aggregates.Order, cancellation OrderCancellation) error
+	// FindOutstandingBalances lista os pedidos não cancelados criados desde since que ainda têm saldo
+	// a receber; pedidos lançados no fiado ficam de fora, porque já são cobrados pela conta do cliente
+	FindOutstandingBalances(restaurantId string, since time.Time) ([]OrderBalance, error)
 }
@@ -5,4 +5,7 @@ import "github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
 type IProductRepository interface {
 	IRepository[aggregates.Product]
 	Exists(name, restaurantId string) (bool, error)
+	FindByRestaurantId(restaurantId string) ([]aggregates.Product, error)
+	FindByCategoryId(categoryId string) ([]aggregates.Product, error)
+	Reorder(categoryId string, products []aggregates.Product) error
 }
@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+)
+
+// ErrPromotionExhausted indica que o limite global de usos acabou entre a avaliação e a gravação do pedido
+var ErrPromotionExhausted = errors.New("promotion usage limit reached")
+
+// PromotionExhaustedError diz qual promoção esgotou; errors.Is com ErrPromotionExhausted continua valendo
+type PromotionExhaustedError struct {
+	PromotionId string
+}
+
+func (e *PromotionExhaustedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPromotionExhausted, e.PromotionId)
+}
+
+func (e *PromotionExhaustedError) Unwrap() error {
+	return ErrPromotionExhausted
+}
+
+type IPromotionRepository interface {
+	FindById(id string) (*aggregates.Promotion, error)
+	FindByRestaurantId(restaurantId string) ([]aggregates.Promotion, error)
+	FindByCode(restaurantId, code string) (*aggregates.Promotion, error)
+	// FindAutomatic lista as regras ativas sem código de cupom
+	FindAutomatic(restaurantId string) ([]aggregates.Promotion, error)
+	// CountRedemptions conta os pedidos não cancelados do cliente que usaram a promoção
+	CountRedemptions(promotionId, customerId string) (int, error)
+	Create(promotion *aggregates.Promotion) error
+	Update(promotion *aggregates.Promotion) error
+	Delete(promotion *aggregates.Promotion) error
+}
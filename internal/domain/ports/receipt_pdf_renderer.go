@@ -0,0 +1,11 @@
+package ports
+
+import "github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+
+type (
+	// IReceiptPdfRenderer gera o recibo do pedido enviado ao cliente por WhatsApp ou e-mail;
+	// logo é opcional e pode vir vazio
+	IReceiptPdfRenderer interface {
+		Render(restaurant *aggregates.Restaurant, order *aggregates.Order, logo []byte) ([]byte, error)
+	}
+)
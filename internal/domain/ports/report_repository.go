@@ -0,0 +1,77 @@
+package ports
+
+import "time"
+
+type (
+	// ReportQuery filtra os pedidos do restaurante criados em [From, To)
+	ReportQuery struct {
+		RestaurantId string
+		From         time.Time
+		To           time.Time
+	}
+
+	// SalesBucket agrega os pedidos não cancelados de uma hora; Start está no fuso do banco
+	SalesBucket struct {
+		Start    time.Time
+		Orders   int
+		Gross    float64
+		Discount float64
+		Net      float64
+	}
+
+	OrderTotals struct {
+		Orders     int
+		Cancelled  int
+		Deliveries int
+		Gross      float64
+		Discount   float64
+		Net        float64
+	}
+
+	ProductSales struct {
+		ProductId   string
+		ProductName string
+		Quantity    int
+		Revenue     float64
+	}
+
+	CategorySales struct {
+		CategoryId   string
+		CategoryName string
+		Quantity     int
+		Revenue      float64
+	}
+
+	PaymentMethodSales struct {
+		PaymentMethod string
+		Payments      int
+		Amount        float64
+	}
+
+	CustomerSales struct {
+		CustomerId string
+		FirstName  string
+		LastName   string
+		Orders     int
+		Spent      float64
+	}
+
+	// DishPortions são as porções de um prato servidas nas marmitas de um produto
+	DishPortions struct {
+		ProductId string
+		DishId    string
+		Portions  int
+	}
+
+	// IReportRepository lê os agregados de vendas direto das tabelas de pedido.
+	// Pedidos cancelados só entram em OrderTotals, para a taxa de cancelamento.
+	IReportRepository interface {
+		Totals(query ReportQuery) (*OrderTotals, error)
+		SalesByHour(query ReportQuery) ([]SalesBucket, error)
+		SalesByProduct(query ReportQuery) ([]ProductSales, error)
+		SalesByCategory(query ReportQuery) ([]CategorySales, error)
+		SalesByPaymentMethod(query ReportQuery) ([]PaymentMethodSales, error)
+		TopCustomers(query ReportQuery, limit int) ([]CustomerSales, error)
+		SoldDishPortions(query ReportQuery) ([]DishPortions, error)
+	}
+)
@@ -1,6 +1,8 @@
 package ports
 
 import (
+	"fmt"
+
 	"github.com/PedroNetto404/marmitech-backend/pkg/types"
 )
 
@@ -9,5 +11,15 @@ type IRepository[T any] interface {
 	FindById(id string) (*T, error)
 	Create(record *T) error
 	Update(record *T) error
-	Delete(id string) error
+	Delete(record *T) error
+}
+
+type ConcurrencyConflictError struct {
+	AggregateType string
+	AggregateId   string
+	Version       int
+}
+
+func (e *ConcurrencyConflictError) Error() string {
+	return fmt.Sprintf("%s %s was modified concurrently (expected version %d)", e.AggregateType, e.AggregateId, e.Version)
 }
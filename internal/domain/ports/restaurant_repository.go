@@ -6,4 +6,5 @@ import "github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
 type IRestaurantRepository interface {
 	IRepository[aggregates.Restaurant]
 	FindByDocument(cnpj string) (*aggregates.Restaurant, error)
+	FindBySlug(slug string) (*aggregates.Restaurant, error)
 }
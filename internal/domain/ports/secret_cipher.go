@@ -0,0 +1,7 @@
+package ports
+
+// ISecretCipher cifra segredos de terceiros antes de irem para o banco
+type ISecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
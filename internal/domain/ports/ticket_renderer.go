@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/pkg/escpos"
+)
+
+type (
+	// ITicketRenderer monta os tickets impressos nas impressoras térmicas do restaurante
+	ITicketRenderer interface {
+		KitchenTicket(restaurant *aggregates.Restaurant, order *aggregates.Order, paper escpos.PaperWidth) *escpos.Document
+		Receipt(restaurant *aggregates.Restaurant, order *aggregates.Order, paper escpos.PaperWidth) *escpos.Document
+	}
+)
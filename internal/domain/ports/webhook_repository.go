@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	webhookdeliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/webhook_delivery_status"
+)
+
+type (
+	// WebhookDeliveryQuery lista as entregas mais recentes primeiro; campos vazios não filtram
+	WebhookDeliveryQuery struct {
+		RestaurantId   string
+		SubscriptionId string
+		Status         webhookdeliverystatus.WebhookDeliveryStatus
+		Limit          int
+	}
+
+	IWebhookSubscriptionRepository interface {
+		FindById(id string) (*aggregates.WebhookSubscription, error)
+		FindByRestaurantId(restaurantId string) ([]aggregates.WebhookSubscription, error)
+		Create(subscription *aggregates.WebhookSubscription) error
+		Update(subscription *aggregates.WebhookSubscription) error
+		Delete(subscription *aggregates.WebhookSubscription) error
+	}
+
+	IWebhookDeliveryRepository interface {
+		FindById(id string) (*aggregates.WebhookDelivery, error)
+		Find(query WebhookDeliveryQuery) ([]aggregates.WebhookDelivery, error)
+		// FindDue lista as entregas pendentes cuja próxima tentativa já venceu, das mais antigas para as mais novas
+		FindDue(now time.Time, limit int) ([]aggregates.WebhookDelivery, error)
+		// Create ignora a entrega quando a assinatura já tem uma para o mesmo evento
+		Create(delivery *aggregates.WebhookDelivery) error
+		Update(delivery *aggregates.WebhookDelivery) error
+	}
+)
@@ -0,0 +1,19 @@
+package ports
+
+import "github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+
+type (
+	WebhookRequest struct {
+		Url        string
+		Secret     string
+		DeliveryId string
+		Event      abstractions.EventName
+		Payload    []byte
+	}
+
+	// IWebhookSender assina e envia o corpo; devolve o status HTTP da resposta, ou erro quando
+	// não houve resposta
+	IWebhookSender interface {
+		Send(request WebhookRequest) (int, error)
+	}
+)
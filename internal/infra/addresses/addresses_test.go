@@ -0,0 +1,66 @@
+package addresses_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/addresses"
+	"github.com/PedroNetto404/marmitech-backend/pkg/enums/uf"
+	"github.com/stretchr/testify/assert"
+)
+
+func newViaCep(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ws/01001000/json/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"cep":"01001-000","logradouro":"Praça da Sé","bairro":"Sé","localidade":"São Paulo","uf":"SP"}`))
+	})
+	mux.HandleFunc("GET /ws/99999999/json/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"erro":"true"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestViaCepLookupCompletesAddress(t *testing.T) {
+	// arrange
+	lookup := addresses.NewViaCepLookup(newViaCep(t).URL)
+
+	// act
+	address, err := lookup.LookupCep("01001-000")
+	_, missingErr := lookup.LookupCep("99999-999")
+	_, invalidErr := lookup.LookupCep("0100-100")
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(err)
+	assert.Equal("01001000", address.ZipCode, "o CEP volta só com os dígitos")
+	assert.Equal("Praça da Sé", address.Street)
+	assert.Equal("São Paulo", address.City)
+	assert.Equal(uf.SP, address.State)
+	assert.ErrorIs(missingErr, ports.ErrCepNotFound)
+	assert.ErrorIs(invalidErr, ports.ErrInvalidCep)
+}
+
+func TestFallbackLookupResolvesStateOffline(t *testing.T) {
+	// arrange
+	server := newViaCep(t)
+	lookup := addresses.NewFallbackAddressLookup(addresses.NewViaCepLookup(server.URL), addresses.NewOfflineCepLookup())
+	server.Close()
+
+	// act
+	address, err := lookup.LookupCep("30130-010")
+	_, invalidErr := lookup.LookupCep("abc")
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(err, "sem o serviço o CEP ainda é resolvido pela faixa")
+	assert.Equal(uf.MG, address.State)
+	assert.Empty(address.City, "a faixa do CEP não diz a cidade")
+	assert.ErrorIs(invalidErr, ports.ErrInvalidCep)
+}
@@ -0,0 +1,72 @@
+package addresses
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/enums/uf"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+// cepRanges são as faixas de CEP dos Correios por UF, pelos 5 primeiros dígitos
+var cepRanges = []struct {
+	from, to int
+	state    uf.UF
+}{
+	{1000, 19999, uf.SP}, {20000, 28999, uf.RJ}, {29000, 29999, uf.ES},
+	{30000, 39999, uf.MG}, {40000, 48999, uf.BA}, {49000, 49999, uf.SE},
+	{50000, 56999, uf.PE}, {57000, 57999, uf.AL}, {58000, 58999, uf.PB},
+	{59000, 59999, uf.RN}, {60000, 63999, uf.CE}, {64000, 64999, uf.PI},
+	{65000, 65999, uf.MA}, {66000, 68899, uf.PA}, {68900, 68999, uf.AP},
+	{69000, 69299, uf.AM}, {69300, 69399, uf.RR}, {69400, 69899, uf.AM},
+	{69900, 69999, uf.AC}, {70000, 72799, uf.DF}, {72800, 72999, uf.GO},
+	{73000, 73699, uf.DF}, {73700, 76799, uf.GO}, {76800, 76999, uf.RO},
+	{77000, 77999, uf.TO}, {78000, 78899, uf.MT}, {79000, 79999, uf.MS},
+	{80000, 87999, uf.PR}, {88000, 89999, uf.SC}, {90000, 99999, uf.RS},
+}
+
+type offlineCepLookup struct{}
+
+// NewOfflineCepLookup resolve só a UF pela faixa do CEP, sem rede
+func NewOfflineCepLookup() ports.IAddressLookup {
+	return offlineCepLookup{}
+}
+
+func (offlineCepLookup) LookupCep(cep string) (*types.Address, error) {
+	cep, err := normalizeCep(cep)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, _ := strconv.Atoi(cep[:5])
+	for _, candidate := range cepRanges {
+		if prefix >= candidate.from && prefix <= candidate.to {
+			return &types.Address{State: candidate.state, Country: brazil, ZipCode: cep}, nil
+		}
+	}
+
+	return nil, ports.ErrCepNotFound
+}
+
+type fallbackAddressLookup struct {
+	primary  ports.IAddressLookup
+	fallback ports.IAddressLookup
+}
+
+// NewFallbackAddressLookup usa o fallback quando o primário falha por indisponibilidade;
+// CEP inválido ou inexistente é resposta do primário e não cai no fallback
+func NewFallbackAddressLookup(primary, fallback ports.IAddressLookup) ports.IAddressLookup {
+	return &fallbackAddressLookup{primary: primary, fallback: fallback}
+}
+
+func (l *fallbackAddressLookup) LookupCep(cep string) (*types.Address, error) {
+	address, err := l.primary.LookupCep(cep)
+	if err == nil || errors.Is(err, ports.ErrInvalidCep) || errors.Is(err, ports.ErrCepNotFound) {
+		return address, err
+	}
+
+	log.Printf("⚠️ cep lookup failed, using fallback: %v", err)
+	return l.fallback.LookupCep(cep)
+}
@@ -0,0 +1,105 @@
+package addresses
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/enums/uf"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+const (
+	viaCepTimeout = 5 * time.Second
+	// país gravado nos endereços completados pelo CEP
+	brazil = "Brasil"
+)
+
+type viaCepLookup struct {
+	apiUrl string
+	client *http.Client
+}
+
+// NewViaCepLookup consulta um serviço no formato do ViaCEP (GET /ws/{cep}/json/)
+func NewViaCepLookup(apiUrl string) ports.IAddressLookup {
+	return &viaCepLookup{
+		apiUrl: strings.TrimSuffix(apiUrl, "/"),
+		client: &http.Client{Timeout: viaCepTimeout},
+	}
+}
+
+type viaCepAddress struct {
+	Cep        string `json:"cep"`
+	Logradouro string `json:"logradouro"`
+	Bairro     string `json:"bairro"`
+	Localidade string `json:"localidade"`
+	Uf         string `json:"uf"`
+	// o ViaCEP já respondeu com true e com "true" para CEP inexistente
+	Erro any `json:"erro"`
+}
+
+func (l *viaCepLookup) LookupCep(cep string) (*types.Address, error) {
+	cep, err := normalizeCep(cep)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := l.client.Get(l.apiUrl + "/ws/" + cep + "/json/")
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusBadRequest {
+		return nil, ports.ErrInvalidCep
+	}
+	if response.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return nil, fmt.Errorf("viacep returned status %d: %s", response.StatusCode, detail)
+	}
+
+	var found viaCepAddress
+	if err := json.NewDecoder(response.Body).Decode(&found); err != nil {
+		return nil, err
+	}
+	if found.Erro != nil && found.Erro != false {
+		return nil, ports.ErrCepNotFound
+	}
+
+	state, ok := uf.Parse(found.Uf)
+	if !ok {
+		return nil, fmt.Errorf("viacep returned unknown state %q for cep %s", found.Uf, cep)
+	}
+
+	return &types.Address{
+		Street:       found.Logradouro,
+		Neighborhood: found.Bairro,
+		City:         found.Localidade,
+		State:        state,
+		Country:      brazil,
+		ZipCode:      cep,
+	}, nil
+}
+
+// normalizeCep tira pontuação e espaços; o CEP é gravado só com os 8 dígitos
+func normalizeCep(cep string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == '-' || r == '.' || r == ' ':
+			return -1
+		default:
+			return 'x'
+		}
+	}, cep)
+
+	if len(digits) != 8 || strings.Contains(digits, "x") {
+		return "", ports.ErrInvalidCep
+	}
+	return digits, nil
+}
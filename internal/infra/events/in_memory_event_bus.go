@@ -0,0 +1,77 @@
+package events
+
+import (
+	"log"
+	"sync"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+const subscriberBufferSize = 64
+
+type inMemoryEventBus struct {
+	mu sync.RWMutex
+	// o valor é fechado ao cancelar a assinatura, para liberar um Publish bloqueado nela
+	subscribers map[chan abstractions.DomainEvent]chan struct{}
+}
+
+// NewInMemoryEventBus distribui eventos apenas dentro do processo; com mais de uma
+// instância da API cada uma só enxerga os eventos que ela mesma publicou.
+func NewInMemoryEventBus() ports.IEventBus {
+	return &inMemoryEventBus{
+		subscribers: make(map[chan abstractions.DomainEvent]chan struct{}),
+	}
+}
+
+// Publish espera o assinante ter espaço no buffer em vez de descartar o evento
+func (b *inMemoryEventBus) Publish(events ...abstractions.DomainEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, event := range events {
+		for subscriber, done := range b.subscribers {
+			select {
+			case subscriber <- event:
+			case <-done:
+			}
+		}
+	}
+}
+
+func (b *inMemoryEventBus) Subscribe() (<-chan abstractions.DomainEvent, func()) {
+	subscriber := make(chan abstractions.DomainEvent, subscriberBufferSize)
+	done := make(chan struct{})
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = done
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			close(done)
+			b.mu.Lock()
+			delete(b.subscribers, subscriber)
+			b.mu.Unlock()
+			close(subscriber)
+		})
+	}
+
+	return subscriber, unsubscribe
+}
+
+// Consume aqui não é durável: sem outbox, o que estiver no buffer se perde quando o processo cai
+func (b *inMemoryEventBus) Consume(consumer string, handle func(event abstractions.DomainEvent) error) func() {
+	events, unsubscribe := b.Subscribe()
+
+	go func() {
+		for event := range events {
+			if err := handle(event); err != nil {
+				log.Printf("⚠️ consumer %s failed to handle %s %s: %v", consumer, event.Name, event.AggregateId, err)
+			}
+		}
+	}()
+
+	return unsubscribe
+}
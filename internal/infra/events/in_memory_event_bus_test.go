@@ -0,0 +1,56 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryEventBusDeliversToSubscribers(t *testing.T) {
+	// arrange
+	bus := events.NewInMemoryEventBus()
+	first, unsubscribeFirst := bus.Subscribe()
+	second, unsubscribeSecond := bus.Subscribe()
+	defer unsubscribeSecond()
+
+	// act
+	bus.Publish(abstractions.NewDomainEvent("order.created", "order-1"))
+	unsubscribeFirst()
+	bus.Publish(abstractions.NewDomainEvent("order.updated", "order-1"))
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(abstractions.EventName("order.created"), (<-first).Name)
+	_, open := <-first
+	assert.False(open, "canal fechado após cancelar a assinatura")
+
+	assert.Equal(abstractions.EventName("order.created"), (<-second).Name)
+	assert.Equal(abstractions.EventName("order.updated"), (<-second).Name)
+}
+
+func TestInMemoryEventBusWaitsForSlowSubscriber(t *testing.T) {
+	// arrange
+	bus := events.NewInMemoryEventBus()
+	subscription, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+	total := 200
+
+	// act
+	go func() {
+		for i := 0; i < total; i++ {
+			bus.Publish(abstractions.NewDomainEvent("order.updated", "order-1"))
+		}
+	}()
+
+	received := 0
+	for received < total {
+		<-subscription
+		received++
+	}
+
+	// assert
+	assert.Equal(t, total, received, "nenhum evento descartado com o buffer cheio")
+}
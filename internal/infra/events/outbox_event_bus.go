@@ -0,0 +1,231 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+const (
+	outboxPollInterval = time.Second
+	outboxBatchSize    = 100
+	// ids do AUTO_INCREMENT ficam com lacunas (rollback, transação ainda aberta), então cada consumidor
+	// guarda quais eventos já entregou; a posição só avança sobre eventos gravados há mais tempo que
+	// isso, quando nenhuma transação que reservou um id menor pode mais confirmar
+	outboxSettleAfter = 10 * time.Minute
+)
+
+type (
+	outboxEventBus struct {
+		outboxRepository ports.IEventOutboxRepository
+		live             ports.IEventBus
+		liveOnce         sync.Once
+
+		mu    sync.Mutex
+		wakes map[chan struct{}]struct{}
+	}
+
+	outboxConsumer struct {
+		name string
+		// consumidores duráveis gravam a posição; o repasse ao vivo recomeça do fim da outbox
+		durable bool
+		handle  func(event abstractions.DomainEvent) error
+		// cursor é a posição assentada: tudo até ela foi entregue; acima dela vale delivered
+		cursor    int64
+		delivered map[int64]struct{}
+		started   bool
+	}
+)
+
+// NewOutboxEventBus lê os eventos que os repositórios gravaram na outbox junto com os agregados.
+// Consumidores duráveis guardam no banco a posição e os eventos entregues acima dela e retomam
+// de onde pararam depois de um erro ou de um restart; Subscribe repassa os mesmos eventos ao vivo
+// pelo barramento em memória.
+func NewOutboxEventBus(outboxRepository ports.IEventOutboxRepository, live ports.IEventBus) ports.IEventBus {
+	return &outboxEventBus{
+		outboxRepository: outboxRepository,
+		live:             live,
+		wakes:            make(map[chan struct{}]struct{}),
+	}
+}
+
+// Publish não grava nada: os eventos já estão na outbox, aqui só acordamos os consumidores
+func (b *outboxEventBus) Publish(events ...abstractions.DomainEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for wake := range b.wakes {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *outboxEventBus) Subscribe() (<-chan abstractions.DomainEvent, func()) {
+	b.liveOnce.Do(func() {
+		b.run(&outboxConsumer{
+			name: "live",
+			handle: func(event abstractions.DomainEvent) error {
+				b.live.Publish(event)
+				return nil
+			},
+			delivered: make(map[int64]struct{}),
+		})
+	})
+
+	return b.live.Subscribe()
+}
+
+func (b *outboxEventBus) Consume(consumer string, handle func(event abstractions.DomainEvent) error) func() {
+	return b.run(&outboxConsumer{
+		name:      consumer,
+		durable:   true,
+		handle:    handle,
+		delivered: make(map[int64]struct{}),
+	})
+}
+
+func (b *outboxEventBus) run(consumer *outboxConsumer) func() {
+	wake := make(chan struct{}, 1)
+	done := make(chan struct{})
+
+	b.mu.Lock()
+	b.wakes[wake] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+
+		for {
+			if consumer.started || b.start(consumer) {
+				b.drain(consumer)
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			case <-wake:
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.wakes, wake)
+			b.mu.Unlock()
+			close(done)
+		})
+	}
+}
+
+// start carrega a posição do consumidor; um consumidor novo começa do fim da outbox
+// para não receber o histórico inteiro
+func (b *outboxEventBus) start(consumer *outboxConsumer) bool {
+	if consumer.durable {
+		cursor, found, err := b.outboxRepository.FindCursor(consumer.name)
+		if err != nil {
+			log.Printf("⚠️ failed to load outbox position of %s: %v", consumer.name, err)
+			return false
+		}
+		if found {
+			delivered, err := b.outboxRepository.FindDelivered(consumer.name, cursor)
+			if err != nil {
+				log.Printf("⚠️ failed to load outbox deliveries of %s: %v", consumer.name, err)
+				return false
+			}
+			for _, sequence := range delivered {
+				consumer.delivered[sequence] = struct{}{}
+			}
+			consumer.cursor, consumer.started = cursor, true
+			return true
+		}
+	}
+
+	last, err := b.outboxRepository.LastSequence()
+	if err != nil {
+		log.Printf("⚠️ failed to load outbox position of %s: %v", consumer.name, err)
+		return false
+	}
+
+	if consumer.durable {
+		if err := b.outboxRepository.SaveCursor(consumer.name, last); err != nil {
+			log.Printf("⚠️ failed to save outbox position of %s: %v", consumer.name, err)
+			return false
+		}
+	}
+
+	consumer.cursor, consumer.started = last, true
+	return true
+}
+
+// drain entrega o que ainda falta e avança a posição sobre o trecho já assentado
+func (b *outboxEventBus) drain(consumer *outboxConsumer) {
+	settled := b.deliver(consumer)
+	if settled == consumer.cursor {
+		return
+	}
+
+	if consumer.durable {
+		if err := b.outboxRepository.SaveCursor(consumer.name, settled); err != nil {
+			log.Printf("⚠️ failed to save outbox position of %s: %v", consumer.name, err)
+			return
+		}
+	}
+
+	for sequence := range consumer.delivered {
+		if sequence <= settled {
+			delete(consumer.delivered, sequence)
+		}
+	}
+	consumer.cursor = settled
+}
+
+// deliver entrega em ordem os eventos acima da posição que ainda não foram entregues e para no
+// primeiro erro, que é entregue de novo na próxima rodada. Um evento que confirma depois de outros
+// com id maior é entregue quando aparecer. Devolve até onde tudo foi entregue e é antigo o bastante
+// para que nenhuma lacuna abaixo ainda seja preenchida.
+func (b *outboxEventBus) deliver(consumer *outboxConsumer) int64 {
+	settleBefore := time.Now().Add(-outboxSettleAfter)
+	after, settled := consumer.cursor, consumer.cursor
+
+	for {
+		events, err := b.outboxRepository.FindAfter(after, outboxBatchSize)
+		if err != nil {
+			log.Printf("⚠️ failed to read outbox for %s: %v", consumer.name, err)
+			return settled
+		}
+
+		for _, event := range events {
+			if _, delivered := consumer.delivered[event.Sequence]; !delivered {
+				if err := consumer.handle(event.Event); err != nil {
+					log.Printf("⚠️ consumer %s failed to handle %s %s, will retry: %v", consumer.name, event.Event.Name, event.Event.AggregateId, err)
+					return settled
+				}
+				if consumer.durable {
+					if err := b.outboxRepository.MarkDelivered(consumer.name, event.Sequence); err != nil {
+						log.Printf("⚠️ failed to save outbox delivery of %s: %v", consumer.name, err)
+						return settled
+					}
+				}
+				consumer.delivered[event.Sequence] = struct{}{}
+			}
+
+			after = event.Sequence
+			if event.RecordedAt.Before(settleBefore) {
+				settled = event.Sequence
+			}
+		}
+
+		if len(events) < outboxBatchSize {
+			return settled
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package events_test
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeOutboxRepository struct {
+	mu        sync.Mutex
+	events    []ports.OutboxEvent
+	cursors   map[string]int64
+	delivered map[string]map[int64]bool
+}
+
+func newFakeOutboxRepository() *fakeOutboxRepository {
+	return &fakeOutboxRepository{
+		cursors:   make(map[string]int64),
+		delivered: make(map[string]map[int64]bool),
+	}
+}
+
+func (r *fakeOutboxRepository) record(sequence int64, aggregateId string) {
+	r.recordAt(sequence, aggregateId, time.Now())
+}
+
+func (r *fakeOutboxRepository) recordAt(sequence int64, aggregateId string, recordedAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, ports.OutboxEvent{
+		Sequence:   sequence,
+		RecordedAt: recordedAt,
+		Event:      abstractions.NewDomainEvent("order.created", aggregateId),
+	})
+	slices.SortFunc(r.events, func(a, b ports.OutboxEvent) int {
+		return int(a.Sequence - b.Sequence)
+	})
+}
+
+func (r *fakeOutboxRepository) cursor(consumer string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cursors[consumer]
+}
+
+func (r *fakeOutboxRepository) FindAfter(sequence int64, limit int) ([]ports.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := make([]ports.OutboxEvent, 0)
+	for _, event := range r.events {
+		if event.Sequence > sequence && len(found) < limit {
+			found = append(found, event)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeOutboxRepository) LastSequence() (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := int64(0)
+	for _, event := range r.events {
+		last = max(last, event.Sequence)
+	}
+	return last, nil
+}
+
+func (r *fakeOutboxRepository) FindCursor(consumer string) (int64, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cursor, found := r.cursors[consumer]
+	return cursor, found, nil
+}
+
+func (r *fakeOutboxRepository) SaveCursor(consumer string, sequence int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cursors[consumer] = sequence
+	for delivered := range r.delivered[consumer] {
+		if delivered <= sequence {
+			delete(r.delivered[consumer], delivered)
+		}
+	}
+	return nil
+}
+
+func (r *fakeOutboxRepository) FindDelivered(consumer string, after int64) ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := make([]int64, 0)
+	for delivered := range r.delivered[consumer] {
+		if delivered > after {
+			found = append(found, delivered)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeOutboxRepository) MarkDelivered(consumer string, sequence int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.delivered[consumer] == nil {
+		r.delivered[consumer] = make(map[int64]bool)
+	}
+	r.delivered[consumer][sequence] = true
+	return nil
+}
+
+type handledEvents struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (h *handledEvents) add(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ids = append(h.ids, id)
+}
+
+func (h *handledEvents) list() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.ids...)
+}
+
+func TestOutboxEventBusRedeliversAfterHandlerError(t *testing.T) {
+	// arrange
+	repository := newFakeOutboxRepository()
+	repository.cursors["inventory"] = 0
+	recordedAt := time.Now().Add(-time.Hour)
+	repository.recordAt(1, "order-1", recordedAt)
+	repository.recordAt(2, "order-2", recordedAt)
+	repository.recordAt(3, "order-3", recordedAt)
+
+	bus := events.NewOutboxEventBus(repository, events.NewInMemoryEventBus())
+	handled := &handledEvents{}
+	failed := false
+
+	// act
+	stop := bus.Consume("inventory", func(event abstractions.DomainEvent) error {
+		handled.add(event.AggregateId)
+		if event.AggregateId == "order-2" && !failed {
+			failed = true
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+	defer stop()
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Eventually(func() bool { return repository.cursor("inventory") == 3 }, 5*time.Second, 10*time.Millisecond,
+		"posição avança sobre eventos antigos já entregues")
+	assert.Equal([]string{"order-1", "order-2", "order-2", "order-3"}, handled.list(),
+		"evento com erro é entregue de novo antes dos seguintes")
+}
+
+func TestOutboxEventBusStartsNewConsumerAtTheEnd(t *testing.T) {
+	// arrange
+	repository := newFakeOutboxRepository()
+	repository.record(1, "order-1")
+
+	bus := events.NewOutboxEventBus(repository, events.NewInMemoryEventBus())
+	handled := &handledEvents{}
+
+	// act
+	stop := bus.Consume("webhooks", func(event abstractions.DomainEvent) error {
+		handled.add(event.AggregateId)
+		return nil
+	})
+	defer stop()
+
+	assert.Eventually(t, func() bool {
+		_, found, _ := repository.FindCursor("webhooks")
+		return found
+	}, 5*time.Second, 10*time.Millisecond)
+	repository.record(2, "order-2")
+	bus.Publish()
+
+	// assert
+	assert.Eventually(t, func() bool { return len(handled.list()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"order-2"}, handled.list(), "histórico anterior ao consumidor não é entregue")
+}
+
+func TestOutboxEventBusDeliversPastGapAndLateCommits(t *testing.T) {
+	// arrange
+	repository := newFakeOutboxRepository()
+	repository.cursors["loyalty"] = 0
+	repository.record(1, "order-1")
+	repository.record(3, "order-3")
+
+	bus := events.NewOutboxEventBus(repository, events.NewInMemoryEventBus())
+	handled := &handledEvents{}
+
+	// act
+	stop := bus.Consume("loyalty", func(event abstractions.DomainEvent) error {
+		handled.add(event.AggregateId)
+		return nil
+	})
+	defer stop()
+
+	assert.Eventually(t, func() bool { return len(handled.list()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	beforeLateCommit := handled.list()
+
+	repository.record(2, "order-2")
+	bus.Publish()
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal([]string{"order-1", "order-3"}, beforeLateCommit, "lacuna não segura os eventos seguintes")
+	assert.Eventually(func() bool { return len(handled.list()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal([]string{"order-1", "order-3", "order-2"}, handled.list(), "transação que confirma depois ainda é entregue")
+	assert.Zero(repository.cursor("loyalty"), "posição não passa de eventos recentes")
+}
+
+func TestOutboxEventBusResumesWithoutRedeliveringAfterRestart(t *testing.T) {
+	// arrange
+	repository := newFakeOutboxRepository()
+	repository.cursors["notifications"] = 0
+	repository.record(1, "order-1")
+	repository.record(3, "order-3")
+
+	handled := &handledEvents{}
+	handle := func(event abstractions.DomainEvent) error {
+		handled.add(event.AggregateId)
+		return nil
+	}
+
+	stop := events.NewOutboxEventBus(repository, events.NewInMemoryEventBus()).Consume("notifications", handle)
+	assert.Eventually(t, func() bool {
+		delivered, _ := repository.FindDelivered("notifications", 0)
+		return len(delivered) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	stop()
+
+	// act
+	repository.record(2, "order-2")
+	stop = events.NewOutboxEventBus(repository, events.NewInMemoryEventBus()).Consume("notifications", handle)
+	defer stop()
+
+	// assert
+	assert.Eventually(t, func() bool { return len(handled.list()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, []string{"order-1", "order-3", "order-2"}, handled.list(), "eventos já entregues não são repetidos")
+}
+
+func TestOutboxEventBusRelaysToLiveSubscribers(t *testing.T) {
+	// arrange
+	repository := newFakeOutboxRepository()
+	repository.record(1, "order-1")
+
+	bus := events.NewOutboxEventBus(repository, events.NewInMemoryEventBus())
+	subscription, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	// act
+	time.Sleep(50 * time.Millisecond)
+	repository.record(2, "order-2")
+	bus.Publish()
+
+	// assert
+	select {
+	case event := <-subscription:
+		assert.Equal(t, "order-2", event.AggregateId, "assinante ao vivo só recebe o que chegou depois dele")
+	case <-time.After(5 * time.Second):
+		t.Fatal("evento não chegou ao assinante ao vivo")
+	}
+}
@@ -0,0 +1,71 @@
+package fiscal
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+)
+
+// emissão normal; contingência offline ainda não é suportada
+const emissionTypeNormal = 1
+
+// accessKey monta a chave de 44 dígitos:
+// cUF + AAMM + CNPJ + modelo + série + número + tipo de emissão + código numérico + DV
+func accessKey(stateCode string, issuedAt time.Time, cnpj string, series, number int, numericCode string) string {
+	base := fmt.Sprintf(
+		"%s%s%s%s%03d%09d%d%s",
+		stateCode,
+		issuedAt.Format("0601"),
+		cnpj,
+		aggregates.NfceModel,
+		series,
+		number,
+		emissionTypeNormal,
+		numericCode,
+	)
+	return base + strconv.Itoa(checkDigit(base))
+}
+
+// checkDigit é o módulo 11 com pesos de 2 a 9 aplicados da direita para a esquerda
+func checkDigit(digits string) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	remainder := sum % 11
+	if remainder < 2 {
+		return 0
+	}
+	return 11 - remainder
+}
+
+// numericCode deriva o cNF do pedido, para que um reenvio gere a mesma chave;
+// o leiaute proíbe que ele repita o número da nota
+func numericCode(orderId string, number int) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(orderId))
+
+	code := int(hash.Sum32() % 100000000)
+	if code == number {
+		code = (code + 1) % 100000000
+	}
+	return fmt.Sprintf("%08d", code)
+}
+
+func onlyDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
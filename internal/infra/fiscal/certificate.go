@@ -0,0 +1,116 @@
+package fiscal
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+const (
+	nfeNamespace  = "http://www.portalfiscal.inf.br/nfe"
+	dsigNamespace = "http://www.w3.org/2000/09/xmldsig#"
+	c14nAlgorithm = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	envelopedSig  = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	rsaSha1       = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	sha1Algorithm = "http://www.w3.org/2000/09/xmldsig#sha1"
+)
+
+var ErrInvalidCertificate = errors.New("invalid A1 certificate or password")
+
+// a1Certificate é o e-CNPJ do emitente (PKCS#12), usado para assinar a nota
+// e para a autenticação TLS mútua com a SEFAZ
+type a1Certificate struct {
+	key  *rsa.PrivateKey
+	leaf *x509.Certificate
+}
+
+func loadCertificate(pfx []byte, password string) (*a1Certificate, error) {
+	blocks, err := pkcs12.ToPEM(pfx, password)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+
+	var key *rsa.PrivateKey
+	certificates := make([]*x509.Certificate, 0, len(blocks))
+	for _, block := range blocks {
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+			}
+		case "CERTIFICATE":
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+			}
+			certificates = append(certificates, certificate)
+		}
+	}
+
+	if key == nil {
+		return nil, fmt.Errorf("%w: private key not found", ErrInvalidCertificate)
+	}
+
+	// o arquivo pode trazer a cadeia da AC; o certificado do emitente é o da chave privada
+	for _, certificate := range certificates {
+		if publicKey, ok := certificate.PublicKey.(*rsa.PublicKey); ok && publicKey.Equal(&key.PublicKey) {
+			return &a1Certificate{key: key, leaf: certificate}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: certificate for the private key not found", ErrInvalidCertificate)
+}
+
+func (c *a1Certificate) tlsCertificate() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{c.leaf.Raw},
+		PrivateKey:  c.key,
+		Leaf:        c.leaf,
+	}
+}
+
+// sign gera a assinatura XMLDSig envelopada exigida pela SEFAZ (RSA-SHA1, C14N)
+// sobre o elemento referenciado por id, já serializado na forma canônica
+func (c *a1Certificate) sign(referenceId string, canonical string) (*node, error) {
+	digest := sha1.Sum([]byte(canonical))
+
+	signedInfo := func() *node {
+		return el("SignedInfo",
+			el("CanonicalizationMethod").attr("Algorithm", c14nAlgorithm),
+			el("SignatureMethod").attr("Algorithm", rsaSha1),
+			el("Reference",
+				el("Transforms",
+					el("Transform").attr("Algorithm", envelopedSig),
+					el("Transform").attr("Algorithm", c14nAlgorithm),
+				),
+				el("DigestMethod").attr("Algorithm", sha1Algorithm),
+				leaf("DigestValue", base64.StdEncoding.EncodeToString(digest[:])),
+			).attr("URI", "#"+referenceId),
+		)
+	}
+
+	// na forma canônica o SignedInfo herda o namespace declarado em Signature
+	hashed := sha1.Sum([]byte(signedInfo().attr("xmlns", dsigNamespace).String()))
+	signature, err := rsa.SignPKCS1v15(nil, c.key, crypto.SHA1, hashed[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return el("Signature",
+		signedInfo(),
+		leaf("SignatureValue", base64.StdEncoding.EncodeToString(signature)),
+		el("KeyInfo",
+			el("X509Data",
+				leaf("X509Certificate", base64.StdEncoding.EncodeToString(c.leaf.Raw)),
+			),
+		),
+	).attr("xmlns", dsigNamespace), nil
+}
@@ -0,0 +1,370 @@
+package fiscal
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+const (
+	layoutVersion = "4.00"
+	qrCodeVersion = "2"
+	// a SEFAZ exige esta descrição no primeiro item das notas de homologação
+	homologationDescription = "NOTA FISCAL EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL"
+)
+
+// CSOSNs do grupo ICMSSN102: tributação pelo simples sem crédito, isenção, imunidade e não tributada
+var supportedCsosn = map[string]bool{"102": true, "103": true, "300": true, "400": true}
+
+// códigos tPag do leiaute para os meios de pagamento usados no sistema
+var paymentCodes = map[string]string{
+	"cash":         "01",
+	"money":        "01",
+	"dinheiro":     "01",
+	"credit_card":  "03",
+	"credit":       "03",
+	"debit_card":   "04",
+	"debit":        "04",
+	"tab":          "05",
+	"post_paid":    "05",
+	"meal_voucher": "11",
+	"food_voucher": "10",
+	"pix":          "17",
+}
+
+type nfce struct {
+	accessKey string
+	infNFe    func(withNamespace bool) *node
+	supl      *node
+	qrCodeUrl string
+}
+
+func buildNfce(request ports.FiscalIssueRequest, endpoints SefazEndpoints) (*nfce, error) {
+	restaurant, profile, order, document := request.Restaurant, request.Profile, request.Order, request.Document
+
+	if !profile.TaxRegime.IsSimples() {
+		return nil, fmt.Errorf("%w: only simples nacional issuers are supported", ports.ErrUnsupportedFiscalData)
+	}
+
+	state := restaurant.Address.State
+	if !state.IsValid() {
+		return nil, fmt.Errorf("%w: unknown issuer state %q", ports.ErrUnsupportedFiscalData, restaurant.Address.State)
+	}
+
+	cnpj := onlyDigits(restaurant.CNPJ)
+	if len(cnpj) != 14 {
+		return nil, fmt.Errorf("%w: issuer CNPJ must have 14 digits", ports.ErrUnsupportedFiscalData)
+	}
+
+	code := numericCode(order.Id, document.Number)
+	key := accessKey(state.IBGECode(), document.IssuedAt, cnpj, document.Series, document.Number, code)
+	environment := strconv.Itoa(int(document.Environment))
+
+	details, totals, err := buildDetails(request)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, change, err := buildPayments(order)
+	if err != nil {
+		return nil, err
+	}
+
+	freightMode := "9"
+	if totals.freight > 0 {
+		freightMode = "0"
+	}
+
+	body := func() []*node {
+		ide := el("ide",
+			leaf("cUF", state.IBGECode()),
+			leaf("cNF", code),
+			leaf("natOp", "VENDA"),
+			leaf("mod", aggregates.NfceModel),
+			leaf("serie", strconv.Itoa(document.Series)),
+			leaf("nNF", strconv.Itoa(document.Number)),
+			leaf("dhEmi", document.IssuedAt.Format("2006-01-02T15:04:05-07:00")),
+			leaf("tpNF", "1"),
+			leaf("idDest", "1"),
+			leaf("cMunFG", profile.CityCode),
+			leaf("tpImp", "4"),
+			leaf("tpEmis", strconv.Itoa(emissionTypeNormal)),
+			leaf("cDV", key[len(key)-1:]),
+			leaf("tpAmb", environment),
+			leaf("finNFe", "1"),
+			leaf("indFinal", "1"),
+			leaf("indPres", "1"),
+			leaf("procEmi", "0"),
+			leaf("verProc", "marmitech"),
+		)
+
+		address := restaurant.Address
+		issuerAddress := el("enderEmit",
+			leaf("xLgr", truncate(address.Street, 60)),
+			leaf("nro", truncate(orDefault(address.Number, "S/N"), 60)),
+		)
+		if address.Complement != "" {
+			issuerAddress.add(leaf("xCpl", truncate(address.Complement, 60)))
+		}
+		issuerAddress.add(
+			leaf("xBairro", truncate(address.Neighborhood, 60)),
+			leaf("cMun", profile.CityCode),
+			leaf("xMun", truncate(address.City, 60)),
+			leaf("UF", string(state)),
+			leaf("CEP", onlyDigits(address.ZipCode)),
+			leaf("cPais", "1058"),
+			leaf("xPais", "BRASIL"),
+		)
+		if phone := onlyDigits(restaurant.ContactPhone); phone != "" {
+			issuerAddress.add(leaf("fone", phone))
+		}
+
+		issuer := el("emit",
+			leaf("CNPJ", cnpj),
+			leaf("xNome", truncate(orDefault(restaurant.LegalName, restaurant.TradeName), 60)),
+		)
+		if restaurant.TradeName != "" {
+			issuer.add(leaf("xFant", truncate(restaurant.TradeName, 60)))
+		}
+		issuer.add(
+			issuerAddress,
+			leaf("IE", onlyDigits(profile.StateRegistration)),
+			leaf("CRT", strconv.Itoa(int(profile.TaxRegime))),
+		)
+
+		total := el("total", el("ICMSTot",
+			leaf("vBC", "0.00"),
+			leaf("vICMS", "0.00"),
+			leaf("vICMSDeson", "0.00"),
+			leaf("vFCP", "0.00"),
+			leaf("vBCST", "0.00"),
+			leaf("vST", "0.00"),
+			leaf("vFCPST", "0.00"),
+			leaf("vFCPSTRet", "0.00"),
+			leaf("vProd", money(totals.products)),
+			leaf("vFrete", money(totals.freight)),
+			leaf("vSeg", "0.00"),
+			leaf("vDesc", money(totals.discount)),
+			leaf("vII", "0.00"),
+			leaf("vIPI", "0.00"),
+			leaf("vIPIDevol", "0.00"),
+			leaf("vPIS", "0.00"),
+			leaf("vCOFINS", "0.00"),
+			leaf("vOutro", "0.00"),
+			leaf("vNF", money(totals.products+totals.freight-totals.discount)),
+		))
+
+		payment := el("pag", payments...)
+		if change > 0 {
+			payment.add(leaf("vTroco", money(change)))
+		}
+
+		nodes := []*node{ide, issuer}
+		nodes = append(nodes, details...)
+		nodes = append(nodes,
+			total,
+			el("transp", leaf("modFrete", freightMode)),
+			payment,
+			el("infAdic", leaf("infCpl", "Pedido "+strings.ToUpper(order.Id[:min(8, len(order.Id))]))),
+		)
+		return nodes
+	}
+
+	qrCodeUrl := qrCode(endpoints.QrCodeUrl, key, environment, profile.CscId, profile.CscToken)
+
+	return &nfce{
+		accessKey: key,
+		infNFe: func(withNamespace bool) *node {
+			infNFe := el("infNFe", body()...)
+			if withNamespace {
+				infNFe.attr("xmlns", nfeNamespace)
+			}
+			return infNFe.attr("Id", "NFe"+key).attr("versao", layoutVersion)
+		},
+		supl: el("infNFeSupl",
+			leaf("qrCode", qrCodeUrl),
+			leaf("urlChave", endpoints.ConsultUrl),
+		),
+		qrCodeUrl: qrCodeUrl,
+	}, nil
+}
+
+type nfceTotals struct {
+	products int64
+	freight  int64
+	discount int64
+}
+
+// buildDetails gera um det por item; frete e desconto do pedido são rateados
+// entre os itens proporcionalmente ao valor, com o resíduo no último item
+func buildDetails(request ports.FiscalIssueRequest) ([]*node, nfceTotals, error) {
+	order := request.Order
+
+	var totals nfceTotals
+	itemTotals := make([]int64, len(order.Items))
+	for i, item := range order.Items {
+		itemTotals[i] = cents(item.Total)
+		totals.products += itemTotals[i]
+	}
+
+	freight := int64(0)
+	if order.Delivery != nil {
+		freight = cents(order.Delivery.Fee)
+	}
+	freights := apportion(freight, itemTotals)
+	discounts := apportion(cents(order.Discount), itemTotals)
+
+	details := make([]*node, 0, len(order.Items))
+	for i, item := range order.Items {
+		product, ok := request.Products[item.Product.Id]
+		if !ok || !product.Fiscal.IsValid() {
+			return nil, totals, fmt.Errorf("%w: product %s has incomplete fiscal data", ports.ErrUnsupportedFiscalData, item.Product.Name)
+		}
+		if !supportedCsosn[product.Fiscal.Csosn] {
+			return nil, totals, fmt.Errorf("%w: CSOSN %s", ports.ErrUnsupportedFiscalData, product.Fiscal.Csosn)
+		}
+
+		description := item.Product.Name
+		if i == 0 && request.Document.Environment == aggregates.FiscalEnvironmentHomologation {
+			description = homologationDescription
+		}
+
+		quantity := fmt.Sprintf("%d.0000", item.Quantity)
+		unitPrice := money(int64(math.Round(float64(itemTotals[i]) / float64(item.Quantity))))
+		discount := cents(item.Discount) + discounts[i]
+
+		prod := el("prod",
+			leaf("cProd", item.Product.Id),
+			leaf("cEAN", "SEM GTIN"),
+			leaf("xProd", truncate(description, 120)),
+			leaf("NCM", product.Fiscal.Ncm),
+			leaf("CFOP", product.Fiscal.Cfop),
+			leaf("uCom", "UN"),
+			leaf("qCom", quantity),
+			leaf("vUnCom", unitPrice),
+			leaf("vProd", money(itemTotals[i])),
+			leaf("cEANTrib", "SEM GTIN"),
+			leaf("uTrib", "UN"),
+			leaf("qTrib", quantity),
+			leaf("vUnTrib", unitPrice),
+		)
+		if freights[i] > 0 {
+			prod.add(leaf("vFrete", money(freights[i])))
+		}
+		if discount > 0 {
+			prod.add(leaf("vDesc", money(discount)))
+		}
+		prod.add(leaf("indTot", "1"))
+
+		tax := el("imposto", el("ICMS", el("ICMSSN102",
+			leaf("orig", strconv.Itoa(product.Fiscal.Origin)),
+			leaf("CSOSN", product.Fiscal.Csosn),
+		)))
+
+		details = append(details, el("det", prod, tax).attr("nItem", strconv.Itoa(i+1)))
+		totals.discount += discount
+	}
+	totals.freight = freight
+
+	return details, totals, nil
+}
+
+func buildPayments(order *aggregates.Order) ([]*node, int64, error) {
+	details := make([]*node, 0, len(order.Payments))
+	paid := int64(0)
+
+	for _, payment := range order.Payments {
+		if payment.Status != "PAID" {
+			continue
+		}
+
+		amount := cents(payment.Amount)
+		paid += amount
+
+		method := strings.ToLower(payment.PaymentMethod)
+		code, ok := paymentCodes[method]
+
+		detail := el("detPag")
+		if ok {
+			detail.add(leaf("tPag", code))
+		} else {
+			detail.add(leaf("tPag", "99"), leaf("xPag", truncate(payment.PaymentMethod, 60)))
+		}
+		detail.add(leaf("vPag", money(amount)))
+
+		// cartões sem integração com a maquininha (tpIntegra 2)
+		if code == "03" || code == "04" {
+			detail.add(el("card", leaf("tpIntegra", "2")))
+		}
+		details = append(details, detail)
+	}
+
+	if len(details) == 0 {
+		return nil, 0, fmt.Errorf("%w: order has no confirmed payments", ports.ErrUnsupportedFiscalData)
+	}
+
+	return details, max(paid-cents(order.Total), 0), nil
+}
+
+// qrCode segue a versão 2 online: chave|versão|ambiente|id do CSC|SHA-1 dos parâmetros com o token do CSC
+func qrCode(baseUrl, key, environment, cscId, cscToken string) string {
+	id := strings.TrimLeft(cscId, "0")
+	parameters := strings.Join([]string{key, qrCodeVersion, environment, id}, "|")
+
+	hash := sha1.Sum([]byte(parameters + cscToken))
+	return baseUrl + "?p=" + parameters + "|" + strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+func apportion(amount int64, weights []int64) []int64 {
+	parts := make([]int64, len(weights))
+	if amount == 0 || len(weights) == 0 {
+		return parts
+	}
+
+	total := int64(0)
+	for _, weight := range weights {
+		total += weight
+	}
+
+	distributed := int64(0)
+	for i, weight := range weights {
+		if i == len(weights)-1 {
+			parts[i] = amount - distributed
+			break
+		}
+		if total > 0 {
+			parts[i] = amount * weight / total
+		}
+		distributed += parts[i]
+	}
+	return parts
+}
+
+func cents(value float64) int64 {
+	return int64(math.Round(value * 100))
+}
+
+func money(value int64) string {
+	return fmt.Sprintf("%d.%02d", value/100, value%100)
+}
+
+func truncate(value string, size int) string {
+	runes := []rune(strings.TrimSpace(value))
+	if len(runes) > size {
+		return string(runes[:size])
+	}
+	return string(runes)
+}
+
+func orDefault(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
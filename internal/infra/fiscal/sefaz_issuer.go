@@ -0,0 +1,198 @@
+package fiscal
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+const (
+	soapNamespace          = "http://www.w3.org/2003/05/soap-envelope"
+	authorizationNamespace = "http://www.portalfiscal.inf.br/nfe/wsdl/NFeAutorizacao4"
+	soapContentType        = "application/soap+xml; charset=utf-8"
+	sefazTimeout           = 30 * time.Second
+)
+
+// SefazEndpoints são as URLs da UF do emitente; em testes apontam para o NewStandInSefaz
+type SefazEndpoints struct {
+	AuthorizationUrl string
+	QrCodeUrl        string
+	ConsultUrl       string
+}
+
+type sefazFiscalDocumentIssuer struct {
+	endpoints SefazEndpoints
+}
+
+func NewSefazFiscalDocumentIssuer(endpoints SefazEndpoints) ports.IFiscalDocumentIssuer {
+	return &sefazFiscalDocumentIssuer{endpoints: endpoints}
+}
+
+func (i *sefazFiscalDocumentIssuer) InspectCertificate(pfx []byte, password string) (*ports.FiscalCertificate, error) {
+	certificate, err := loadCertificate(pfx, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ports.FiscalCertificate{
+		Subject:  certificate.leaf.Subject.CommonName,
+		NotAfter: certificate.leaf.NotAfter,
+	}, nil
+}
+
+func (i *sefazFiscalDocumentIssuer) Issue(request ports.FiscalIssueRequest) (*ports.FiscalAuthorization, error) {
+	certificate, err := loadCertificate(request.Certificate, request.Profile.CertificatePassword)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := buildNfce(request, i.endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := certificate.sign("NFe"+document.accessKey, document.infNFe(true).String())
+	if err != nil {
+		return nil, err
+	}
+
+	nfe := el("NFe", document.infNFe(false), document.supl, signature).attr("xmlns", nfeNamespace)
+
+	response, err := i.send(certificate, nfe)
+	if err != nil {
+		return nil, err
+	}
+
+	authorization := &ports.FiscalAuthorization{
+		AccessKey:    document.accessKey,
+		StatusCode:   response.StatusCode,
+		StatusReason: response.Reason,
+		QrCodeUrl:    document.qrCodeUrl,
+	}
+
+	// sem protNFe a rejeição foi do lote inteiro
+	if response.Protocol == nil {
+		return authorization, nil
+	}
+
+	info := response.Protocol.Info
+	authorization.StatusCode = info.StatusCode
+	authorization.StatusReason = info.Reason
+
+	// 100 autorizado; 150 autorizado fora do prazo
+	if info.StatusCode != "100" && info.StatusCode != "150" {
+		return authorization, nil
+	}
+
+	authorizedAt, err := time.Parse(time.RFC3339, info.ReceivedAt)
+	if err != nil {
+		authorizedAt = time.Now()
+	}
+
+	authorization.Authorized = true
+	authorization.Protocol = info.Protocol
+	authorization.AuthorizedAt = authorizedAt
+	authorization.Xml = `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<nfeProc xmlns="` + nfeNamespace + `" versao="` + layoutVersion + `">` +
+		nfe.String() +
+		`<protNFe versao="` + layoutVersion + `">` + response.Protocol.Inner + `</protNFe>` +
+		`</nfeProc>`
+
+	return authorization, nil
+}
+
+type (
+	authorizationResult struct {
+		StatusCode string          `xml:"cStat"`
+		Reason     string          `xml:"xMotivo"`
+		Protocol   *protocolResult `xml:"protNFe"`
+	}
+
+	protocolResult struct {
+		Inner string `xml:",innerxml"`
+		Info  struct {
+			AccessKey  string `xml:"chNFe"`
+			ReceivedAt string `xml:"dhRecbto"`
+			Protocol   string `xml:"nProt"`
+			StatusCode string `xml:"cStat"`
+			Reason     string `xml:"xMotivo"`
+		} `xml:"infProt"`
+	}
+)
+
+// send transmite o lote síncrono (indSinc 1) com uma única nota
+func (i *sefazFiscalDocumentIssuer) send(certificate *a1Certificate, nfe *node) (*authorizationResult, error) {
+	batch := el("enviNFe",
+		leaf("idLote", strconv.FormatInt(time.Now().UnixNano()%1e15, 10)),
+		leaf("indSinc", "1"),
+		nfe,
+	).attr("xmlns", nfeNamespace).attr("versao", layoutVersion)
+
+	envelope := el("soap12:Envelope",
+		el("soap12:Body",
+			el("nfeDadosMsg", batch).attr("xmlns", authorizationNamespace),
+		),
+	).attr("xmlns:soap12", soapNamespace)
+
+	client := &http.Client{
+		Timeout: sefazTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				Certificates: []tls.Certificate{certificate.tlsCertificate()},
+				MinVersion:   tls.VersionTLS12,
+			},
+		},
+	}
+
+	body := `<?xml version="1.0" encoding="UTF-8"?>` + envelope.String()
+	httpResponse, err := client.Post(i.endpoints.AuthorizationUrl, soapContentType, strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ports.ErrSefazUnavailable, err)
+	}
+	defer httpResponse.Body.Close()
+
+	payload, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ports.ErrSefazUnavailable, err)
+	}
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ports.ErrSefazUnavailable, httpResponse.StatusCode)
+	}
+
+	result, err := decodeElement[authorizationResult](payload, "retEnviNFe")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ports.ErrSefazUnavailable, err)
+	}
+	return result, nil
+}
+
+// decodeElement procura o elemento pelo nome local, ignorando o envelope SOAP
+func decodeElement[T any](payload []byte, name string) (*T, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(payload))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("element %s not found", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == name {
+			var result T
+			if err := decoder.DecodeElement(&result, &start); err != nil {
+				return nil, err
+			}
+			return &result, nil
+		}
+	}
+}
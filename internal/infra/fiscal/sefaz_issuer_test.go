@@ -0,0 +1,130 @@
+package fiscal_test
+
+import (
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/fiscal"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func issueRequest(t *testing.T) ports.FiscalIssueRequest {
+	certificate, err := os.ReadFile("testdata/certificate.pfx")
+	require.NoError(t, err)
+
+	restaurant := &aggregates.Restaurant{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		TradeName:     "Marmitaria da Vó",
+		LegalName:     "VO COZINHA CASEIRA LTDA",
+		CNPJ:          "12.345.678/0001-95",
+		ContactPhone:  "(11) 3333-4444",
+		Address: types.Address{
+			Street:       "Rua das Flores",
+			Number:       "123",
+			Neighborhood: "Centro",
+			City:         "São Paulo",
+			State:        "SP",
+			ZipCode:      "01001-000",
+		},
+	}
+
+	profile := aggregates.NewFiscalProfile(restaurant.Id)
+	profile.StateRegistration = "123.456.789.110"
+	profile.CityCode = "3550308"
+	profile.CscId = "000001"
+	profile.CscToken = "CSC-TESTE"
+	profile.CertificatePassword = "1234"
+
+	order := &aggregates.Order{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Discount:      2,
+		Total:         48,
+		Delivery:      &aggregates.OrderDelivery{Fee: 5},
+		Items: []aggregates.OrderItem{
+			{Product: aggregates.PartialProduct{Id: "marmita", Name: "Marmita média"}, UnitPrice: 20, Quantity: 2, Total: 40},
+			{Product: aggregates.PartialProduct{Id: "suco", Name: "Suco de laranja"}, UnitPrice: 5, Quantity: 1, Total: 5},
+		},
+		Payments: []aggregates.OrderPayment{
+			{PaymentMethod: "pix", Amount: 30, Status: "PAID"},
+			{PaymentMethod: "cash", Amount: 20, Status: "PAID"},
+		},
+	}
+
+	fiscalData := aggregates.ProductFiscalData{Ncm: "21069090", Cfop: "5102", Csosn: "102"}
+	products := map[string]aggregates.Product{
+		"marmita": {Fiscal: fiscalData},
+		"suco":    {Fiscal: fiscalData},
+	}
+
+	document := aggregates.NewFiscalDocument(restaurant.Id, order.Id, profile.Series, 42, profile.Environment)
+	document.IssuedAt = time.Date(2026, 3, 10, 12, 30, 0, 0, time.FixedZone("BRT", -3*60*60))
+
+	return ports.FiscalIssueRequest{
+		Restaurant:  restaurant,
+		Profile:     profile,
+		Order:       order,
+		Products:    products,
+		Document:    document,
+		Certificate: certificate,
+	}
+}
+
+func TestIssueIsAuthorizedByStandInSefaz(t *testing.T) {
+	// arrange
+	sefaz := httptest.NewServer(fiscal.NewStandInSefaz())
+	defer sefaz.Close()
+
+	issuer := fiscal.NewSefazFiscalDocumentIssuer(fiscal.SefazEndpoints{
+		AuthorizationUrl: sefaz.URL,
+		QrCodeUrl:        "https://sefaz.test/qrcode",
+		ConsultUrl:       "https://sefaz.test/consulta",
+	})
+
+	// act
+	authorization, err := issuer.Issue(issueRequest(t))
+
+	// assert
+	require.NoError(t, err)
+
+	assert := assert.New(t)
+	assert.True(authorization.Authorized, authorization.StatusReason)
+	assert.Equal("100", authorization.StatusCode)
+	assert.Len(authorization.AccessKey, 44)
+	assert.True(strings.HasPrefix(authorization.AccessKey, "352603123456780001956500100000004"), "chave com UF, AAMM, CNPJ, modelo, série e número")
+	assert.NotEmpty(authorization.Protocol)
+	assert.Contains(authorization.QrCodeUrl, "https://sefaz.test/qrcode?p="+authorization.AccessKey+"|2|2|1|")
+	assert.Contains(authorization.Xml, "<nfeProc")
+	assert.Contains(authorization.Xml, "<nProt>"+authorization.Protocol+"</nProt>")
+	assert.Contains(authorization.Xml, "<vFrete>5.00</vFrete><vSeg>0.00</vSeg><vDesc>2.00</vDesc>", "frete e desconto totalizados")
+	assert.Contains(authorization.Xml, "<vNF>48.00</vNF>")
+	assert.Contains(authorization.Xml, "<vTroco>2.00</vTroco>")
+}
+
+func TestIssueSameDocumentTwiceIsRejectedAsDuplicated(t *testing.T) {
+	// arrange
+	sefaz := httptest.NewServer(fiscal.NewStandInSefaz())
+	defer sefaz.Close()
+
+	issuer := fiscal.NewSefazFiscalDocumentIssuer(fiscal.SefazEndpoints{AuthorizationUrl: sefaz.URL})
+	request := issueRequest(t)
+
+	// act
+	first, err := issuer.Issue(request)
+	require.NoError(t, err)
+	second, err := issuer.Issue(request)
+
+	// assert
+	require.NoError(t, err)
+	assert.True(t, first.Authorized)
+	assert.False(t, second.Authorized, "mesma chave não pode ser autorizada duas vezes")
+	assert.Equal(t, "204", second.StatusCode)
+	assert.Equal(t, first.AccessKey, second.AccessKey)
+}
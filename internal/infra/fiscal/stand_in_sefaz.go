@@ -0,0 +1,200 @@
+package fiscal
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// standInSefaz imita o web service NFeAutorizacao4 para desenvolvimento e testes:
+// confere chave, digest e assinatura e autoriza cada chave uma única vez
+type standInSefaz struct {
+	mutex      sync.Mutex
+	authorized map[string]string
+	sequence   int
+}
+
+func NewStandInSefaz() http.Handler {
+	return &standInSefaz{authorized: map[string]string{}}
+}
+
+type standInNfe struct {
+	Id  string `xml:"Id,attr"`
+	Ide struct {
+		StateCode   string `xml:"cUF"`
+		Environment string `xml:"tpAmb"`
+	} `xml:"ide"`
+	Items []struct {
+		Description string `xml:"prod>xProd"`
+	} `xml:"det"`
+}
+
+func (s *standInSefaz) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	infNFe, ok := between(string(payload), "<infNFe ", "</infNFe>")
+	if !ok {
+		s.respondBatch(w, "", "2", "215", "Rejeição: Falha no schema XML")
+		return
+	}
+
+	nfe, err := decodeElement[standInNfe]([]byte(infNFe), "infNFe")
+	if err != nil {
+		s.respondBatch(w, "", "2", "215", "Rejeição: Falha no schema XML")
+		return
+	}
+
+	key := strings.TrimPrefix(nfe.Id, "NFe")
+	state, environment := nfe.Ide.StateCode, nfe.Ide.Environment
+
+	if len(key) != 44 || strconv.Itoa(checkDigit(key[:43])) != key[43:] {
+		s.respondProtocol(w, state, environment, key, "", "502", "Rejeição: Erro na Chave de Acesso - Campo Id não corresponde à concatenação dos campos correspondentes")
+		return
+	}
+
+	if !verifySignature(string(payload), infNFe) {
+		s.respondProtocol(w, state, environment, key, "", "297", "Rejeição: Assinatura difere do calculado")
+		return
+	}
+
+	if environment == "2" && (len(nfe.Items) == 0 || nfe.Items[0].Description != homologationDescription) {
+		s.respondProtocol(w, state, environment, key, "", "373", "Rejeição: Descrição do primeiro item diferente de "+homologationDescription)
+		return
+	}
+
+	s.mutex.Lock()
+	protocol, duplicated := s.authorized[key]
+	if !duplicated {
+		s.sequence++
+		protocol = fmt.Sprintf("%s%s%s%09d", state, environment, time.Now().Format("06"), s.sequence)
+		s.authorized[key] = protocol
+	}
+	s.mutex.Unlock()
+
+	if duplicated {
+		s.respondProtocol(w, state, environment, key, "", "204", "Rejeição: Duplicidade de NF-e [nRec:"+protocol+"]")
+		return
+	}
+
+	s.respondProtocol(w, state, environment, key, protocol, "100", "Autorizado o uso da NF-e")
+}
+
+// verifySignature refaz a forma canônica dos trechos assinados, que no envio
+// herdam o namespace dos elementos pai
+func verifySignature(payload, infNFe string) bool {
+	signedInfo, ok := between(payload, "<SignedInfo>", "</SignedInfo>")
+	if !ok {
+		return false
+	}
+	digestValue, _ := between(signedInfo, "<DigestValue>", "</DigestValue>")
+	signatureValue, _ := between(payload, "<SignatureValue>", "</SignatureValue>")
+	rawCertificate, _ := between(payload, "<X509Certificate>", "</X509Certificate>")
+
+	canonical := strings.Replace(infNFe, "<infNFe ", `<infNFe xmlns="`+nfeNamespace+`" `, 1)
+	digest := sha1.Sum([]byte(canonical))
+	if base64.StdEncoding.EncodeToString(digest[:]) != stripTags(digestValue, "DigestValue") {
+		return false
+	}
+
+	der, err := base64.StdEncoding.DecodeString(stripTags(rawCertificate, "X509Certificate"))
+	if err != nil {
+		return false
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return false
+	}
+	publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return false
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(stripTags(signatureValue, "SignatureValue"))
+	if err != nil {
+		return false
+	}
+
+	canonicalSignedInfo := strings.Replace(signedInfo, "<SignedInfo>", `<SignedInfo xmlns="`+dsigNamespace+`">`, 1)
+	hashed := sha1.Sum([]byte(canonicalSignedInfo))
+	return rsa.VerifyPKCS1v15(publicKey, crypto.SHA1, hashed[:], signature) == nil
+}
+
+func (s *standInSefaz) respondProtocol(w http.ResponseWriter, state, environment, key, protocol, statusCode, reason string) {
+	info := el("infProt",
+		leaf("tpAmb", environment),
+		leaf("verAplic", "STAND-IN"),
+		leaf("chNFe", key),
+		leaf("dhRecbto", time.Now().Format(time.RFC3339)),
+	)
+	if protocol != "" {
+		info.add(leaf("nProt", protocol))
+	}
+	info.add(leaf("cStat", statusCode), leaf("xMotivo", reason))
+
+	s.write(w, s.batch(state, environment, "104", "Lote processado").add(
+		el("protNFe", info).attr("versao", layoutVersion),
+	))
+}
+
+func (s *standInSefaz) respondBatch(w http.ResponseWriter, state, environment, statusCode, reason string) {
+	s.write(w, s.batch(state, environment, statusCode, reason))
+}
+
+func (s *standInSefaz) batch(state, environment, statusCode, reason string) *node {
+	return el("retEnviNFe",
+		leaf("tpAmb", environment),
+		leaf("verAplic", "STAND-IN"),
+		leaf("cStat", statusCode),
+		leaf("xMotivo", reason),
+		leaf("cUF", state),
+		leaf("dhRecbto", time.Now().Format(time.RFC3339)),
+	).attr("xmlns", nfeNamespace).attr("versao", layoutVersion)
+}
+
+func (s *standInSefaz) write(w http.ResponseWriter, result *node) {
+	envelope := el("soap12:Envelope",
+		el("soap12:Body",
+			el("nfeResultMsg", result).attr("xmlns", authorizationNamespace),
+		),
+	).attr("xmlns:soap12", soapNamespace)
+
+	w.Header().Set("Content-Type", soapContentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, xml.Header+envelope.String())
+}
+
+// between devolve o trecho que começa em start e termina em end, inclusive
+func between(value, start, end string) (string, bool) {
+	from := strings.Index(value, start)
+	if from < 0 {
+		return "", false
+	}
+	to := strings.Index(value[from:], end)
+	if to < 0 {
+		return "", false
+	}
+	return value[from : from+to+len(end)], true
+}
+
+func stripTags(value, name string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(value, "<"+name+">"), "</"+name+">")
+}
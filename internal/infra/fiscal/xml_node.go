@@ -0,0 +1,70 @@
+package fiscal
+
+import "strings"
+
+// node é um elemento XML mínimo que já serializa na forma canônica (C14N 1.0):
+// sem declaração, sem tags auto-fechadas e com atributos na ordem em que foram
+// adicionados. Assim o digest da assinatura é calculado direto sobre a saída.
+type node struct {
+	name     string
+	attrs    [][2]string
+	text     string
+	children []*node
+}
+
+func el(name string, children ...*node) *node {
+	return &node{name: name, children: children}
+}
+
+func leaf(name, text string) *node {
+	return &node{name: name, text: text}
+}
+
+// attr deve ser chamado com xmlns primeiro e os demais em ordem alfabética
+func (n *node) attr(name, value string) *node {
+	n.attrs = append(n.attrs, [2]string{name, value})
+	return n
+}
+
+// add ignora filhos nulos, o que simplifica grupos opcionais
+func (n *node) add(children ...*node) *node {
+	for _, child := range children {
+		if child != nil {
+			n.children = append(n.children, child)
+		}
+	}
+	return n
+}
+
+func (n *node) String() string {
+	var builder strings.Builder
+	n.write(&builder)
+	return builder.String()
+}
+
+func (n *node) write(builder *strings.Builder) {
+	builder.WriteByte('<')
+	builder.WriteString(n.name)
+	for _, attr := range n.attrs {
+		builder.WriteByte(' ')
+		builder.WriteString(attr[0])
+		builder.WriteString(`="`)
+		builder.WriteString(attributeEscaper.Replace(attr[1]))
+		builder.WriteByte('"')
+	}
+	builder.WriteByte('>')
+
+	builder.WriteString(textEscaper.Replace(n.text))
+	for _, child := range n.children {
+		child.write(builder)
+	}
+
+	builder.WriteString("</")
+	builder.WriteString(n.name)
+	builder.WriteByte('>')
+}
+
+var (
+	textEscaper      = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attributeEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
@@ -0,0 +1,55 @@
+package geocoding
+
+import (
+	"log"
+	"strings"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+type cachedGeocoder struct {
+	geocoder        ports.IGeocoder
+	cacheRepository ports.IGeocodeCacheRepository
+}
+
+// NewCachedGeocoder só chama o provedor para endereços que ainda não estão no geocode_cache;
+// endereços não encontrados não são guardados, para serem tentados de novo
+func NewCachedGeocoder(geocoder ports.IGeocoder, cacheRepository ports.IGeocodeCacheRepository) ports.IGeocoder {
+	return &cachedGeocoder{geocoder: geocoder, cacheRepository: cacheRepository}
+}
+
+func (g *cachedGeocoder) Geocode(address types.Address) (types.GeoPoint, error) {
+	key := AddressKey(address)
+
+	cached, err := g.cacheRepository.Find(key)
+	if err != nil {
+		return types.GeoPoint{}, err
+	}
+	if cached != nil {
+		return *cached, nil
+	}
+
+	point, err := g.geocoder.Geocode(address)
+	if err != nil {
+		return types.GeoPoint{}, err
+	}
+
+	if err := g.cacheRepository.Save(key, point); err != nil {
+		log.Printf("⚠️ failed to cache geocoded address %q: %v", key, err)
+	}
+
+	return point, nil
+}
+
+// AddressKey é a chave do cache, com os campos que localizam o endereço já normalizados;
+// bairro e complemento ficam de fora porque variam muito na digitação
+func AddressKey(address types.Address) string {
+	return strings.Join([]string{
+		types.FoldName(address.Street),
+		types.FoldName(address.Number),
+		types.FoldName(address.City),
+		string(address.State),
+		address.ZipCode,
+	}, "|")
+}
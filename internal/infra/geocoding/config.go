@@ -0,0 +1,47 @@
+package geocoding
+
+import (
+	"fmt"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+const (
+	NominatimProvider = "nominatim"
+	GoogleProvider    = "google"
+	// FakeProvider não encontra nenhum endereço; deixa o sistema rodar sem rede
+	FakeProvider = "fake"
+
+	nominatimPublicUrl = "https://nominatim.openstreetmap.org"
+	googlePublicUrl    = "https://maps.googleapis.com"
+)
+
+// Config escolhe o provedor; sem ApiUrl é usado o serviço público do provedor
+type Config struct {
+	Provider string
+	ApiUrl   string
+	ApiKey   string
+}
+
+func New(config Config) (ports.IGeocoder, error) {
+	switch config.Provider {
+	case NominatimProvider:
+		return NewNominatimGeocoder(orDefault(config.ApiUrl, nominatimPublicUrl)), nil
+	case GoogleProvider:
+		if config.ApiKey == "" {
+			return nil, fmt.Errorf("google geocoder requires an api key")
+		}
+		return NewGoogleGeocoder(orDefault(config.ApiUrl, googlePublicUrl), config.ApiKey), nil
+	case FakeProvider:
+		return NewFakeGeocoder(), nil
+	default:
+		return nil, fmt.Errorf("unknown geocoder provider %q", config.Provider)
+	}
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
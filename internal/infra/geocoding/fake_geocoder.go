@@ -0,0 +1,52 @@
+package geocoding
+
+import (
+	"sync"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+// FakeGeocoder responde com as coordenadas cadastradas por Set; serve para os testes e para
+// desenvolvimento local sem acesso ao provedor
+type FakeGeocoder struct {
+	mu     sync.Mutex
+	points map[string]types.GeoPoint
+	calls  int
+	// Err, quando preenchido, é devolvido por Geocode no lugar da resposta
+	Err error
+}
+
+func NewFakeGeocoder() *FakeGeocoder {
+	return &FakeGeocoder{points: map[string]types.GeoPoint{}}
+}
+
+func (g *FakeGeocoder) Set(address types.Address, point types.GeoPoint) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.points[AddressKey(address)] = point
+}
+
+func (g *FakeGeocoder) Geocode(address types.Address) (types.GeoPoint, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.calls++
+	if g.Err != nil {
+		return types.GeoPoint{}, g.Err
+	}
+
+	point, ok := g.points[AddressKey(address)]
+	if !ok {
+		return types.GeoPoint{}, ports.ErrAddressNotGeocoded
+	}
+	return point, nil
+}
+
+func (g *FakeGeocoder) Calls() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.calls
+}
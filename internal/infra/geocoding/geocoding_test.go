@@ -0,0 +1,107 @@
+package geocoding_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/geocoding"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var pracaDaSe = types.Address{Street: "Praça da Sé", Number: "100", City: "São Paulo", State: "SP", ZipCode: "01001000"}
+
+type memoryGeocodeCache map[string]types.GeoPoint
+
+func (c memoryGeocodeCache) Find(key string) (*types.GeoPoint, error) {
+	point, ok := c[key]
+	if !ok {
+		return nil, nil
+	}
+	return &point, nil
+}
+
+func (c memoryGeocodeCache) Save(key string, point types.GeoPoint) error {
+	c[key] = point
+	return nil
+}
+
+func TestNominatimGeocoderUsesStructuredSearch(t *testing.T) {
+	// arrange
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("city") == "São Paulo" {
+			query = map[string]string{
+				"street": r.URL.Query().Get("street"),
+				"state":  r.URL.Query().Get("state"),
+				"agent":  r.UserAgent(),
+			}
+			w.Write([]byte(`[{"lat":"-23.5503","lon":"-46.6339"}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	geocoder := geocoding.NewNominatimGeocoder(server.URL)
+
+	// act
+	point, err := geocoder.Geocode(pracaDaSe)
+	_, missingErr := geocoder.Geocode(types.Address{Street: "Rua Inexistente", City: "Lugar Nenhum"})
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(err)
+	assert.Equal(types.GeoPoint{Lat: -23.5503, Lng: -46.6339}, point)
+	assert.ErrorIs(missingErr, ports.ErrAddressNotGeocoded)
+	assert.Equal("100 Praça da Sé", query["street"])
+	assert.Equal("São Paulo", query["state"], "o estado vai por extenso")
+	assert.NotEmpty(query["agent"], "o Nominatim exige User-Agent")
+}
+
+func TestGoogleGeocoderMapsZeroResults(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "chave" {
+			w.Write([]byte(`{"status":"REQUEST_DENIED","error_message":"invalid key"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ZERO_RESULTS","results":[]}`))
+	}))
+	defer server.Close()
+
+	// act
+	_, missingErr := geocoding.NewGoogleGeocoder(server.URL, "chave").Geocode(pracaDaSe)
+	_, deniedErr := geocoding.NewGoogleGeocoder(server.URL, "errada").Geocode(pracaDaSe)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.ErrorIs(missingErr, ports.ErrAddressNotGeocoded)
+	assert.Error(deniedErr)
+	assert.NotErrorIs(deniedErr, ports.ErrAddressNotGeocoded, "chave recusada não é endereço inexistente")
+}
+
+func TestCachedGeocoderReusesNormalizedAddress(t *testing.T) {
+	// arrange
+	fake := geocoding.NewFakeGeocoder()
+	fake.Set(pracaDaSe, types.GeoPoint{Lat: -23.5503, Lng: -46.6339})
+	geocoder := geocoding.NewCachedGeocoder(fake, memoryGeocodeCache{})
+	typed := pracaDaSe
+	typed.Street = "  praca  da se "
+	typed.City = "SAO PAULO"
+
+	// act
+	first, firstErr := geocoder.Geocode(pracaDaSe)
+	second, secondErr := geocoder.Geocode(typed)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(firstErr)
+	assert.NoError(secondErr)
+	assert.Equal(first, second)
+	assert.Equal(1, fake.Calls(), "o mesmo endereço com outra grafia sai do cache")
+}
@@ -0,0 +1,89 @@
+package geocoding
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+type googleGeocoder struct {
+	apiUrl string
+	apiKey string
+	client *http.Client
+}
+
+// NewGoogleGeocoder usa a Geocoding API do Google Maps, ou um serviço com o mesmo formato
+func NewGoogleGeocoder(apiUrl, apiKey string) ports.IGeocoder {
+	return &googleGeocoder{
+		apiUrl: strings.TrimSuffix(apiUrl, "/"),
+		apiKey: apiKey,
+		client: &http.Client{Timeout: geocoderTimeout},
+	}
+}
+
+type googleGeocodeResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Results      []struct {
+		Geometry struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+	} `json:"results"`
+}
+
+func (g *googleGeocoder) Geocode(address types.Address) (types.GeoPoint, error) {
+	parts := make([]string, 0, 5)
+	for _, part := range []string{
+		strings.TrimSpace(address.Street + " " + address.Number),
+		address.Neighborhood,
+		address.City,
+		string(address.State),
+		address.ZipCode,
+	} {
+		if strings.TrimSpace(part) != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	query := url.Values{}
+	query.Set("address", strings.Join(parts, ", "))
+	query.Set("components", "country:BR")
+	query.Set("key", g.apiKey)
+
+	response, err := g.client.Get(g.apiUrl + "/maps/api/geocode/json?" + query.Encode())
+	if err != nil {
+		return types.GeoPoint{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return types.GeoPoint{}, fmt.Errorf("google geocoding returned status %d", response.StatusCode)
+	}
+
+	var body googleGeocodeResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return types.GeoPoint{}, err
+	}
+
+	switch body.Status {
+	case "OK":
+	case "ZERO_RESULTS":
+		return types.GeoPoint{}, ports.ErrAddressNotGeocoded
+	default:
+		return types.GeoPoint{}, fmt.Errorf("google geocoding returned %s: %s", body.Status, body.ErrorMessage)
+	}
+	if len(body.Results) == 0 {
+		return types.GeoPoint{}, ports.ErrAddressNotGeocoded
+	}
+
+	location := body.Results[0].Geometry.Location
+	return types.GeoPoint{Lat: location.Lat, Lng: location.Lng}, nil
+}
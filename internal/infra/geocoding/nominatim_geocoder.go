@@ -0,0 +1,94 @@
+package geocoding
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+const (
+	geocoderTimeout = 10 * time.Second
+	// a política de uso do Nominatim exige um User-Agent que identifique a aplicação
+	nominatimUserAgent = "marmitech-backend"
+)
+
+type nominatimGeocoder struct {
+	apiUrl string
+	client *http.Client
+}
+
+// NewNominatimGeocoder usa a busca estruturada do Nominatim (OpenStreetMap) ou de uma instância própria
+func NewNominatimGeocoder(apiUrl string) ports.IGeocoder {
+	return &nominatimGeocoder{
+		apiUrl: strings.TrimSuffix(apiUrl, "/"),
+		client: &http.Client{Timeout: geocoderTimeout},
+	}
+}
+
+type nominatimPlace struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+func (g *nominatimGeocoder) Geocode(address types.Address) (types.GeoPoint, error) {
+	query := url.Values{}
+	query.Set("format", "jsonv2")
+	query.Set("limit", "1")
+	query.Set("countrycodes", "br")
+	if address.Street != "" {
+		query.Set("street", strings.TrimSpace(address.Number+" "+address.Street))
+	}
+	if address.City != "" {
+		query.Set("city", address.City)
+	}
+	if address.State.IsValid() {
+		query.Set("state", address.State.Name())
+	}
+	if address.ZipCode != "" {
+		query.Set("postalcode", address.ZipCode)
+	}
+
+	request, err := http.NewRequest(http.MethodGet, g.apiUrl+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return types.GeoPoint{}, err
+	}
+	request.Header.Set("User-Agent", nominatimUserAgent)
+
+	response, err := g.client.Do(request)
+	if err != nil {
+		return types.GeoPoint{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return types.GeoPoint{}, fmt.Errorf("nominatim returned status %d: %s", response.StatusCode, detail)
+	}
+
+	var places []nominatimPlace
+	if err := json.NewDecoder(response.Body).Decode(&places); err != nil {
+		return types.GeoPoint{}, err
+	}
+	if len(places) == 0 {
+		return types.GeoPoint{}, ports.ErrAddressNotGeocoded
+	}
+
+	lat, err := strconv.ParseFloat(places[0].Lat, 64)
+	if err != nil {
+		return types.GeoPoint{}, err
+	}
+	lng, err := strconv.ParseFloat(places[0].Lon, 64)
+	if err != nil {
+		return types.GeoPoint{}, err
+	}
+
+	return types.GeoPoint{Lat: lat, Lng: lng}, nil
+}
@@ -0,0 +1,364 @@
+package marketplace
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	orderchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_channel"
+	"github.com/PedroNetto404/marmitech-backend/pkg/enums/uf"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+const (
+	ifoodTimeout = 10 * time.Second
+	// o token é renovado um pouco antes de expirar, para não vencer no meio de uma chamada
+	ifoodTokenMargin = time.Minute
+	// o iFood limita a quantidade de lojas por busca
+	ifoodPollingMerchantsLimit = 100
+	// motivo de cancelamento "problemas de sistema", o único que não depende do que aconteceu na loja
+	ifoodCancellationCode = "501"
+)
+
+// IfoodConfig são as credenciais do aplicativo da plataforma no portal do desenvolvedor do iFood
+type IfoodConfig struct {
+	ApiUrl       string
+	ClientId     string
+	ClientSecret string
+}
+
+type ifoodProvider struct {
+	config IfoodConfig
+	client *http.Client
+
+	mutex          sync.Mutex
+	token          string
+	tokenExpiresAt time.Time
+}
+
+func NewIfoodProvider(config IfoodConfig) ports.IMarketplaceProvider {
+	config.ApiUrl = strings.TrimSuffix(config.ApiUrl, "/")
+	return &ifoodProvider{
+		config: config,
+		client: &http.Client{Timeout: ifoodTimeout},
+	}
+}
+
+type (
+	ifoodToken struct {
+		AccessToken string `json:"accessToken"`
+		ExpiresIn   int    `json:"expiresIn"`
+	}
+
+	ifoodEvent struct {
+		Id         string    `json:"id"`
+		Code       string    `json:"code"`
+		FullCode   string    `json:"fullCode"`
+		OrderId    string    `json:"orderId"`
+		MerchantId string    `json:"merchantId"`
+		CreatedAt  time.Time `json:"createdAt"`
+	}
+
+	ifoodOrder struct {
+		Id        string    `json:"id"`
+		DisplayId string    `json:"displayId"`
+		OrderType string    `json:"orderType"`
+		CreatedAt time.Time `json:"createdAt"`
+		Merchant  struct {
+			Id string `json:"id"`
+		} `json:"merchant"`
+		Items []struct {
+			ExternalCode string  `json:"externalCode"`
+			Name         string  `json:"name"`
+			Quantity     int     `json:"quantity"`
+			TotalPrice   float64 `json:"totalPrice"`
+			Observations string  `json:"observations"`
+		} `json:"items"`
+		Total struct {
+			DeliveryFee float64 `json:"deliveryFee"`
+			Benefits    float64 `json:"benefits"`
+		} `json:"total"`
+		Payments struct {
+			Prepaid float64 `json:"prepaid"`
+		} `json:"payments"`
+		Delivery *struct {
+			DeliveryAddress ifoodAddress `json:"deliveryAddress"`
+		} `json:"delivery"`
+		ExtraInfo string `json:"extraInfo"`
+	}
+
+	ifoodAddress struct {
+		StreetName   string `json:"streetName"`
+		StreetNumber string `json:"streetNumber"`
+		Complement   string `json:"complement"`
+		Neighborhood string `json:"neighborhood"`
+		City         string `json:"city"`
+		State        string `json:"state"`
+		Country      string `json:"country"`
+		PostalCode   string `json:"postalCode"`
+		Coordinates  struct {
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"coordinates"`
+	}
+)
+
+// ifoodEventCodes traduz os códigos de evento que o restaurante trata
+var ifoodEventCodes = map[string]ports.MarketplaceEventCode{
+	"PLC": ports.MarketplaceOrderPlaced,
+	"CAN": ports.MarketplaceOrderCancelled,
+}
+
+var ifoodActionPaths = map[ports.MarketplaceAction]string{
+	ports.MarketplaceConfirm:          "confirm",
+	ports.MarketplaceStartPreparation: "startPreparation",
+	ports.MarketplaceReadyForPickup:   "readyToPickup",
+	ports.MarketplaceDispatch:         "dispatch",
+	ports.MarketplaceCancel:           "requestCancellation",
+}
+
+func (p *ifoodProvider) Channel() orderchannel.OrderChannel {
+	return orderchannel.IFOOD
+}
+
+func (p *ifoodProvider) Poll(merchantIds []string) ([]ports.MarketplaceEvent, error) {
+	events := make([]ports.MarketplaceEvent, 0)
+
+	for start := 0; start < len(merchantIds); start += ifoodPollingMerchantsLimit {
+		end := min(start+ifoodPollingMerchantsLimit, len(merchantIds))
+
+		var batch []ifoodEvent
+		err := p.do(http.MethodGet, "/events/v1.0/events:polling", nil, &batch, func(request *http.Request) {
+			request.Header.Set("x-polling-merchants", strings.Join(merchantIds[start:end], ","))
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range batch {
+			events = append(events, mapIfoodEvent(event))
+		}
+	}
+
+	return events, nil
+}
+
+func (p *ifoodProvider) Acknowledge(events []ports.MarketplaceEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]map[string]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, map[string]string{"id": event.Id})
+	}
+
+	return p.do(http.MethodPost, "/events/v1.0/events/acknowledgment", ids, nil, nil)
+}
+
+func (p *ifoodProvider) FetchOrder(orderId string) (*ports.MarketplaceOrderDetails, error) {
+	var order ifoodOrder
+	err := p.do(http.MethodGet, "/order/v1.0/orders/"+url.PathEscape(orderId), nil, &order, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &ports.MarketplaceOrderDetails{
+		Id:          order.Id,
+		Code:        order.DisplayId,
+		MerchantId:  order.Merchant.Id,
+		Items:       make([]ports.MarketplaceOrderItem, 0, len(order.Items)),
+		DeliveryFee: order.Total.DeliveryFee,
+		Discount:    order.Total.Benefits,
+		Prepaid:     order.Payments.Prepaid,
+		Observation: order.ExtraInfo,
+		CreatedAt:   order.CreatedAt,
+	}
+
+	for _, item := range order.Items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("ifood order %s has an item with quantity %d", order.Id, item.Quantity)
+		}
+		// o preço total já inclui os complementos escolhidos
+		details.Items = append(details.Items, ports.MarketplaceOrderItem{
+			ExternalCode: item.ExternalCode,
+			Name:         item.Name,
+			Quantity:     item.Quantity,
+			UnitPrice:    item.TotalPrice / float64(item.Quantity),
+			Observation:  item.Observations,
+		})
+	}
+
+	// o pedido de entrega própria do iFood também chega com endereço; só a retirada não tem
+	if order.OrderType == "DELIVERY" && order.Delivery != nil {
+		address := order.Delivery.DeliveryAddress
+		state, _ := uf.Parse(address.State)
+		details.Delivery = &types.Address{
+			Street:       address.StreetName,
+			Number:       address.StreetNumber,
+			Complement:   address.Complement,
+			Neighborhood: address.Neighborhood,
+			City:         address.City,
+			State:        state,
+			Country:      address.Country,
+			ZipCode:      address.PostalCode,
+			Lat:          address.Coordinates.Latitude,
+			Lng:          address.Coordinates.Longitude,
+		}
+	}
+
+	return details, nil
+}
+
+func (p *ifoodProvider) SendAction(orderId string, action ports.MarketplaceAction, reason string) error {
+	path, ok := ifoodActionPaths[action]
+	if !ok {
+		return fmt.Errorf("unknown marketplace action %q", action)
+	}
+
+	var body any
+	if action == ports.MarketplaceCancel {
+		body = map[string]string{
+			"reason":           reason,
+			"cancellationCode": ifoodCancellationCode,
+		}
+	}
+
+	return p.do(http.MethodPost, "/order/v1.0/orders/"+url.PathEscape(orderId)+"/"+path, body, nil, nil)
+}
+
+// ParseWebhook aceita o evento único que o iFood envia por webhook, assinado com o client secret
+func (p *ifoodProvider) ParseWebhook(body []byte, signature string) ([]ports.MarketplaceEvent, error) {
+	mac := hmac.New(sha256.New, []byte(p.config.ClientSecret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return nil, ports.ErrInvalidMarketplaceSignature
+	}
+
+	var event ifoodEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+
+	return []ports.MarketplaceEvent{mapIfoodEvent(event)}, nil
+}
+
+// do chama a API com o token do aplicativo; 204 é resposta vazia, 4xx é recusa do iFood
+func (p *ifoodProvider) do(method, path string, body any, result any, prepare func(request *http.Request)) error {
+	token, err := p.accessToken()
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	request, err := http.NewRequest(method, p.config.ApiUrl+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if prepare != nil {
+		prepare(request)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized {
+		p.resetToken()
+	}
+
+	if response.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		if response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusUnauthorized && response.StatusCode != http.StatusTooManyRequests {
+			return fmt.Errorf("%w: %s %s returned status %d: %s", ports.ErrMarketplaceRejected, method, path, response.StatusCode, detail)
+		}
+		return fmt.Errorf("ifood %s %s returned status %d: %s", method, path, response.StatusCode, detail)
+	}
+
+	if result == nil || response.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+func (p *ifoodProvider) accessToken() (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.token != "" && time.Now().Before(p.tokenExpiresAt) {
+		return p.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("grantType", "client_credentials")
+	form.Set("clientId", p.config.ClientId)
+	form.Set("clientSecret", p.config.ClientSecret)
+
+	response, err := p.client.PostForm(p.config.ApiUrl+"/authentication/v1.0/oauth/token", form)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return "", fmt.Errorf("ifood authentication returned status %d: %s", response.StatusCode, detail)
+	}
+
+	var token ifoodToken
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return "", err
+	}
+
+	p.token = token.AccessToken
+	p.tokenExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - ifoodTokenMargin)
+	return p.token, nil
+}
+
+func (p *ifoodProvider) resetToken() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.token = ""
+}
+
+func mapIfoodEvent(event ifoodEvent) ports.MarketplaceEvent {
+	code, ok := ifoodEventCodes[event.Code]
+	if !ok {
+		code = ports.MarketplaceEventCode(strings.ToLower(event.FullCode))
+	}
+
+	return ports.MarketplaceEvent{
+		Id:         event.Id,
+		Code:       code,
+		OrderId:    event.OrderId,
+		MerchantId: event.MerchantId,
+		CreatedAt:  event.CreatedAt,
+	}
+}
@@ -0,0 +1,140 @@
+package marketplace_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/marketplace"
+	"github.com/stretchr/testify/assert"
+)
+
+const standInOrder = `{
+	"id": "pedido-ifood-1",
+	"displayId": "4521",
+	"orderType": "DELIVERY",
+	"merchant": {"id": "loja-1"},
+	"items": [
+		{"externalCode": "MARMITA-G", "name": "Marmita grande", "quantity": 2, "unitPrice": 25, "totalPrice": 54, "observations": "sem cebola"}
+	],
+	"total": {"subTotal": 54, "deliveryFee": 7, "benefits": 5, "orderAmount": 56},
+	"payments": {"prepaid": 56, "pending": 0},
+	"delivery": {"deliveryAddress": {"streetName": "Rua das Flores", "streetNumber": "10", "neighborhood": "Centro", "city": "Campinas", "state": "SP", "postalCode": "13010000"}}
+}`
+
+func newStandIn(t *testing.T) (*httptest.Server, ports.IMarketplaceProvider) {
+	server := httptest.NewServer(marketplace.NewStandInIfood("cliente", "segredo"))
+	t.Cleanup(server.Close)
+
+	provider := marketplace.NewIfoodProvider(marketplace.IfoodConfig{
+		ApiUrl:       server.URL,
+		ClientId:     "cliente",
+		ClientSecret: "segredo",
+	})
+
+	response, err := http.Post(server.URL+"/stand-in/orders", "application/json", strings.NewReader(standInOrder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	return server, provider
+}
+
+func standInStatus(t *testing.T, server *httptest.Server, orderId string) (string, []string) {
+	response, err := http.Get(server.URL + "/stand-in/orders/" + orderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	var body struct {
+		Status  string   `json:"status"`
+		Actions []string `json:"actions"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	return body.Status, body.Actions
+}
+
+func TestIfoodProviderPollsAndFetchesOrder(t *testing.T) {
+	// arrange
+	_, provider := newStandIn(t)
+
+	// act
+	events, pollErr := provider.Poll([]string{"loja-1"})
+	order, fetchErr := provider.FetchOrder("pedido-ifood-1")
+	ackErr := provider.Acknowledge(events)
+	remaining, _ := provider.Poll([]string{"loja-1"})
+	otherMerchant, _ := provider.Poll([]string{"loja-2"})
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(pollErr)
+	assert.NoError(fetchErr)
+	assert.NoError(ackErr)
+	assert.Len(events, 1)
+	assert.Equal(ports.MarketplaceOrderPlaced, events[0].Code)
+	assert.Equal("pedido-ifood-1", events[0].OrderId)
+	assert.Empty(remaining, "evento reconhecido não volta na próxima busca")
+	assert.Empty(otherMerchant, "cada loja só recebe os próprios eventos")
+
+	assert.Equal("4521", order.Code)
+	assert.Equal("loja-1", order.MerchantId)
+	assert.Len(order.Items, 1)
+	assert.Equal("MARMITA-G", order.Items[0].ExternalCode)
+	assert.Equal(27.0, order.Items[0].UnitPrice, "o preço unitário inclui os complementos")
+	assert.Equal(7.0, order.DeliveryFee)
+	assert.Equal(5.0, order.Discount)
+	assert.Equal(56.0, order.Prepaid)
+	assert.NotNil(order.Delivery)
+	assert.Equal("Rua das Flores", order.Delivery.Street)
+}
+
+func TestIfoodProviderPushesStatusChanges(t *testing.T) {
+	// arrange
+	server, provider := newStandIn(t)
+
+	// act
+	confirmErr := provider.SendAction("pedido-ifood-1", ports.MarketplaceConfirm, "")
+	dispatchErr := provider.SendAction("pedido-ifood-1", ports.MarketplaceDispatch, "")
+	lateErr := provider.SendAction("pedido-ifood-1", ports.MarketplaceStartPreparation, "")
+	status, actions := standInStatus(t, server, "pedido-ifood-1")
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(confirmErr)
+	assert.NoError(dispatchErr)
+	assert.ErrorIs(lateErr, ports.ErrMarketplaceRejected, "pedido despachado não volta para o preparo")
+	assert.Equal("DISPATCHED", status)
+	assert.Equal([]string{"confirm", "dispatch"}, actions)
+}
+
+func TestIfoodProviderChecksWebhookSignature(t *testing.T) {
+	// arrange
+	provider := marketplace.NewIfoodProvider(marketplace.IfoodConfig{ClientSecret: "segredo"})
+	body := []byte(`{"id":"evento-1","code":"CAN","fullCode":"CANCELLED","orderId":"pedido-ifood-1","merchantId":"loja-1"}`)
+	mac := hmac.New(sha256.New, []byte("segredo"))
+	mac.Write(body)
+
+	// act
+	events, err := provider.ParseWebhook(body, hex.EncodeToString(mac.Sum(nil)))
+	_, forgedErr := provider.ParseWebhook(body, "00")
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(err)
+	assert.Len(events, 1)
+	assert.Equal(ports.MarketplaceOrderCancelled, events[0].Code)
+	assert.ErrorIs(forgedErr, ports.ErrInvalidMarketplaceSignature)
+}
@@ -0,0 +1,272 @@
+package marketplace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// standInIfood imita a Merchant API do iFood para desenvolvimento e testes: autenticação,
+// busca e reconhecimento de eventos, detalhes do pedido e mudanças de status. As rotas em
+// /stand-in fazem o papel do cliente do iFood, colocando e cancelando pedidos
+type standInIfood struct {
+	clientId     string
+	clientSecret string
+
+	mutex  sync.Mutex
+	tokens map[string]bool
+	orders map[string]*standInIfoodOrder
+	events []standInIfoodEvent
+}
+
+type (
+	standInIfoodOrder struct {
+		Order   map[string]any
+		Status  string
+		Actions []string
+	}
+
+	standInIfoodEvent struct {
+		ifoodEvent
+		acknowledged bool
+	}
+)
+
+// ações aceitas e de quais status elas partem
+var standInIfoodTransitions = map[string]struct {
+	from []string
+	to   string
+}{
+	"confirm":             {from: []string{"PLACED"}, to: "CONFIRMED"},
+	"startPreparation":    {from: []string{"CONFIRMED"}, to: "PREPARATION_STARTED"},
+	"readyToPickup":       {from: []string{"CONFIRMED", "PREPARATION_STARTED"}, to: "READY_TO_PICKUP"},
+	"dispatch":            {from: []string{"CONFIRMED", "PREPARATION_STARTED"}, to: "DISPATCHED"},
+	"requestCancellation": {from: []string{"PLACED", "CONFIRMED", "PREPARATION_STARTED", "READY_TO_PICKUP"}, to: "CANCELLED"},
+}
+
+func NewStandInIfood(clientId, clientSecret string) http.Handler {
+	s := &standInIfood{
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		tokens:       map[string]bool{},
+		orders:       map[string]*standInIfoodOrder{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /authentication/v1.0/oauth/token", s.token)
+	mux.HandleFunc("GET /events/v1.0/events:polling", s.authorized(s.poll))
+	mux.HandleFunc("POST /events/v1.0/events/acknowledgment", s.authorized(s.acknowledge))
+	mux.HandleFunc("GET /order/v1.0/orders/{id}", s.authorized(s.order))
+	mux.HandleFunc("POST /order/v1.0/orders/{id}/{action}", s.authorized(s.action))
+	mux.HandleFunc("POST /stand-in/orders", s.place)
+	mux.HandleFunc("POST /stand-in/orders/{id}/cancel", s.cancel)
+	mux.HandleFunc("GET /stand-in/orders/{id}", s.status)
+
+	return mux
+}
+
+func (s *standInIfood) token(w http.ResponseWriter, r *http.Request) {
+	if r.PostFormValue("grantType") != "client_credentials" ||
+		r.PostFormValue("clientId") != s.clientId ||
+		r.PostFormValue("clientSecret") != s.clientSecret {
+		writeStandInJson(w, http.StatusUnauthorized, map[string]string{"message": "invalid client credentials"})
+		return
+	}
+
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+	token := hex.EncodeToString(random)
+
+	s.mutex.Lock()
+	s.tokens[token] = true
+	s.mutex.Unlock()
+
+	writeStandInJson(w, http.StatusOK, map[string]any{"accessToken": token, "type": "bearer", "expiresIn": 21600})
+}
+
+func (s *standInIfood) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		valid := s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+		s.mutex.Unlock()
+
+		if !valid {
+			writeStandInJson(w, http.StatusUnauthorized, map[string]string{"message": "invalid token"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *standInIfood) poll(w http.ResponseWriter, r *http.Request) {
+	merchants := strings.Split(r.Header.Get("x-polling-merchants"), ",")
+
+	s.mutex.Lock()
+	pending := make([]ifoodEvent, 0)
+	for _, event := range s.events {
+		if !event.acknowledged && slices.Contains(merchants, event.MerchantId) {
+			pending = append(pending, event.ifoodEvent)
+		}
+	}
+	s.mutex.Unlock()
+
+	if len(pending) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeStandInJson(w, http.StatusOK, pending)
+}
+
+func (s *standInIfood) acknowledge(w http.ResponseWriter, r *http.Request) {
+	var ids []struct {
+		Id string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+		writeStandInJson(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+
+	s.mutex.Lock()
+	for _, id := range ids {
+		for i := range s.events {
+			if s.events[i].Id == id.Id {
+				s.events[i].acknowledged = true
+			}
+		}
+	}
+	s.mutex.Unlock()
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *standInIfood) order(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	order, ok := s.orders[r.PathValue("id")]
+	s.mutex.Unlock()
+
+	if !ok {
+		writeStandInJson(w, http.StatusNotFound, map[string]string{"message": "order not found"})
+		return
+	}
+	writeStandInJson(w, http.StatusOK, order.Order)
+}
+
+func (s *standInIfood) action(w http.ResponseWriter, r *http.Request) {
+	action := r.PathValue("action")
+	transition, known := standInIfoodTransitions[action]
+	if !known {
+		writeStandInJson(w, http.StatusNotFound, map[string]string{"message": "unknown action"})
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	order, ok := s.orders[r.PathValue("id")]
+	if !ok {
+		writeStandInJson(w, http.StatusNotFound, map[string]string{"message": "order not found"})
+		return
+	}
+
+	if !slices.Contains(transition.from, order.Status) {
+		writeStandInJson(w, http.StatusBadRequest, map[string]string{
+			"message": fmt.Sprintf("cannot %s an order in status %s", action, order.Status),
+		})
+		return
+	}
+
+	order.Status = transition.to
+	order.Actions = append(order.Actions, action)
+	if transition.to == "CANCELLED" {
+		s.raise(order, "CAN", "CANCELLED")
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// place recebe o pedido no formato da API de pedidos; id, displayId e createdAt são gerados quando faltam
+func (s *standInIfood) place(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeStandInJson(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+
+	if id, _ := body["id"].(string); id == "" {
+		body["id"] = uuid.NewString()
+	}
+	if displayId, _ := body["displayId"].(string); displayId == "" {
+		body["displayId"] = strings.ToUpper(body["id"].(string)[:4])
+	}
+	if _, ok := body["createdAt"]; !ok {
+		body["createdAt"] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	s.mutex.Lock()
+	order := &standInIfoodOrder{Order: body, Status: "PLACED", Actions: make([]string, 0)}
+	s.orders[body["id"].(string)] = order
+	s.raise(order, "PLC", "PLACED")
+	s.mutex.Unlock()
+
+	writeStandInJson(w, http.StatusCreated, body)
+}
+
+// cancel é o cliente desistindo do pedido pelo aplicativo
+func (s *standInIfood) cancel(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	order, ok := s.orders[r.PathValue("id")]
+	if !ok {
+		writeStandInJson(w, http.StatusNotFound, map[string]string{"message": "order not found"})
+		return
+	}
+
+	order.Status = "CANCELLED"
+	s.raise(order, "CAN", "CANCELLED")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *standInIfood) status(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	order, ok := s.orders[r.PathValue("id")]
+	if !ok {
+		writeStandInJson(w, http.StatusNotFound, map[string]string{"message": "order not found"})
+		return
+	}
+
+	writeStandInJson(w, http.StatusOK, map[string]any{"status": order.Status, "actions": order.Actions})
+}
+
+// raise precisa ser chamado com o mutex travado
+func (s *standInIfood) raise(order *standInIfoodOrder, code, fullCode string) {
+	merchantId := ""
+	if merchant, ok := order.Order["merchant"].(map[string]any); ok {
+		merchantId, _ = merchant["id"].(string)
+	}
+
+	s.events = append(s.events, standInIfoodEvent{ifoodEvent: ifoodEvent{
+		Id:         uuid.NewString(),
+		Code:       code,
+		FullCode:   fullCode,
+		OrderId:    order.Order["id"].(string),
+		MerchantId: merchantId,
+		CreatedAt:  time.Now().UTC(),
+	}})
+}
+
+func writeStandInJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
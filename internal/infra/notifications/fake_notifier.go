@@ -0,0 +1,47 @@
+package notifications
+
+import (
+	"log"
+	"sync"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	notificationchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_channel"
+)
+
+// FakeNotifier guarda as mensagens em memória em vez de enviá-las; serve para os testes
+// e para desenvolvimento local sem credenciais dos provedores
+type FakeNotifier struct {
+	channel  notificationchannel.NotificationChannel
+	mu       sync.Mutex
+	messages []ports.NotificationMessage
+	// Err, quando preenchido, é devolvido por Send no lugar do envio
+	Err error
+}
+
+func NewFakeNotifier(channel notificationchannel.NotificationChannel) *FakeNotifier {
+	return &FakeNotifier{channel: channel}
+}
+
+func (n *FakeNotifier) Channel() notificationchannel.NotificationChannel {
+	return n.channel
+}
+
+func (n *FakeNotifier) Send(message ports.NotificationMessage) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.Err != nil {
+		return n.Err
+	}
+
+	n.messages = append(n.messages, message)
+	log.Printf("📨 [%s] %s: %s", n.channel, message.Recipient, message.Body)
+	return nil
+}
+
+func (n *FakeNotifier) Messages() []ports.NotificationMessage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]ports.NotificationMessage(nil), n.messages...)
+}
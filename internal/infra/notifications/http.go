@@ -0,0 +1,46 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+const providerTimeout = 10 * time.Second
+
+// postJson envia o corpo autenticado por token; 4xx é recusa do provedor, o resto é falha temporária
+func postJson(client *http.Client, url, token string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 300 {
+		return nil
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+	// 429 é limite de envio, vale tentar de novo
+	if response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: status %d: %s", ports.ErrNotificationRejected, response.StatusCode, detail)
+	}
+	return fmt.Errorf("provider returned status %d: %s", response.StatusCode, detail)
+}
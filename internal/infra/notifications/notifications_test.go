@@ -0,0 +1,84 @@
+package notifications_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/notifications"
+	notificationchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_channel"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderOutForDeliveryMessage(t *testing.T) {
+	// arrange
+	renderer := notifications.NewTemplateRenderer()
+	order := &aggregates.Order{
+		AggregateRoot: abstractions.NewAggregateRoot(),
+		Total:         1234.5,
+		Delivery:      &aggregates.OrderDelivery{Address: types.Address{Street: "Rua das Flores", Number: "123"}},
+	}
+	data := ports.NotificationData{
+		Restaurant: &aggregates.Restaurant{TradeName: "Marmitaria da Vó", WhatsAppPhone: "(11) 98888-7777"},
+		Order:      order,
+		Customer:   &aggregates.Customer{FirstName: "Ana"},
+	}
+
+	// act
+	subject, body, err := renderer.Render(aggregates.OrderOutForDeliveryTemplate, data)
+	_, _, unknownErr := renderer.Render("order_lost", data)
+
+	// assert
+	assert := assert.New(t)
+
+	require.NoError(t, err)
+	assert.Equal("Seu pedido saiu para entrega - Marmitaria da Vó", subject)
+	assert.Contains(body, "Olá, Ana! Seu pedido saiu para entrega e logo chega em Rua das Flores, 123.")
+	assert.Contains(body, "Valor a pagar na entrega: R$ 1.234,50", "sem pagamento registrado, cobra na entrega")
+	assert.Contains(body, "WhatsApp (11) 98888-7777")
+	assert.ErrorIs(unknownErr, ports.ErrUnknownNotificationTemplate)
+}
+
+func TestWhatsAppNotifierSendsTextMessage(t *testing.T) {
+	// arrange
+	var path, authorization string
+	var message map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		if message["to"] == "551100000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := notifications.NewWhatsAppNotifier(notifications.WhatsAppConfig{
+		ApiUrl:        server.URL,
+		PhoneNumberId: "1234",
+		AccessToken:   "token",
+	})
+
+	// act
+	err := notifier.Send(ports.NotificationMessage{Channel: notificationchannel.WHATSAPP, Recipient: "(11) 98888-7777", Body: "Seu pedido saiu para entrega"})
+	rejected := notifier.Send(ports.NotificationMessage{Channel: notificationchannel.WHATSAPP, Recipient: "(11) 0000-0000", Body: "oi"})
+	invalid := notifier.Send(ports.NotificationMessage{Channel: notificationchannel.WHATSAPP, Recipient: "123", Body: "oi"})
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(err)
+	assert.Equal("/1234/messages", path)
+	assert.Equal("Bearer token", authorization)
+	assert.Equal("whatsapp", message["messaging_product"])
+	assert.ErrorIs(rejected, ports.ErrNotificationRejected, "4xx não é reenviado")
+	assert.ErrorIs(invalid, ports.ErrNotificationRejected, "telefone sem DDD")
+}
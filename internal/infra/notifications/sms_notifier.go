@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"fmt"
+	"net/http"
+	"unicode"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	notificationchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_channel"
+)
+
+// SmsConfig serve para provedores que aceitam um POST JSON com destino, remetente e texto
+type SmsConfig struct {
+	ApiUrl   string
+	ApiToken string
+	Sender   string
+}
+
+type smsNotifier struct {
+	config SmsConfig
+	client *http.Client
+}
+
+func NewSmsNotifier(config SmsConfig) ports.INotifier {
+	return &smsNotifier{
+		config: config,
+		client: &http.Client{Timeout: providerTimeout},
+	}
+}
+
+func (n *smsNotifier) Channel() notificationchannel.NotificationChannel {
+	return notificationchannel.SMS
+}
+
+type smsMessage struct {
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+	Text string `json:"text"`
+}
+
+func (n *smsNotifier) Send(message ports.NotificationMessage) error {
+	phone, err := internationalPhone(message.Recipient)
+	if err != nil {
+		return err
+	}
+
+	return postJson(n.client, n.config.ApiUrl, n.config.ApiToken, smsMessage{
+		To:   "+" + phone,
+		From: n.config.Sender,
+		Text: message.Body,
+	})
+}
+
+// internationalPhone deixa só os dígitos e inclui o 55 nos números nacionais com DDD
+func internationalPhone(phone string) (string, error) {
+	digits := make([]rune, 0, len(phone))
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+		}
+	}
+
+	switch len(digits) {
+	case 10, 11:
+		return "55" + string(digits), nil
+	case 12, 13:
+		return string(digits), nil
+	}
+	return "", fmt.Errorf("%w: invalid phone %q", ports.ErrNotificationRejected, phone)
+}
@@ -0,0 +1,69 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	notificationchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_channel"
+)
+
+type SmtpConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	From     string
+}
+
+type smtpNotifier struct {
+	config SmtpConfig
+	// send é o smtp.SendMail; trocado nos testes
+	send func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSmtpNotifier(config SmtpConfig) ports.INotifier {
+	return &smtpNotifier{config: config, send: smtp.SendMail}
+}
+
+func (n *smtpNotifier) Channel() notificationchannel.NotificationChannel {
+	return notificationchannel.EMAIL
+}
+
+func (n *smtpNotifier) Send(message ports.NotificationMessage) error {
+	if strings.ContainsAny(message.Recipient, "\r\n") || !strings.Contains(message.Recipient, "@") {
+		return fmt.Errorf("%w: invalid e-mail %q", ports.ErrNotificationRejected, message.Recipient)
+	}
+
+	var auth smtp.Auth
+	if n.config.User != "" {
+		auth = smtp.PlainAuth("", n.config.User, n.config.Password, n.config.Host)
+	}
+
+	err := n.send(net.JoinHostPort(n.config.Host, n.config.Port), auth, n.config.From, []string{message.Recipient}, n.compose(message))
+
+	// 5xx é recusa definitiva do servidor (caixa inexistente, remetente bloqueado)
+	var protocolErr *textproto.Error
+	if errors.As(err, &protocolErr) && protocolErr.Code >= 500 {
+		return fmt.Errorf("%w: %v", ports.ErrNotificationRejected, err)
+	}
+	return err
+}
+
+func (n *smtpNotifier) compose(message ports.NotificationMessage) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + n.config.From + "\r\n")
+	b.WriteString("To: " + message.Recipient + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var templateFuncs = template.FuncMap{
+	"money":   money,
+	"shortId": shortId,
+}
+
+type templateRenderer struct {
+	templates map[aggregates.NotificationTemplate]*template.Template
+}
+
+// NewTemplateRenderer carrega um modelo por mensagem; cada arquivo define "subject" e "body"
+// e pode usar o bloco "contact" do contact.tmpl
+func NewTemplateRenderer() ports.INotificationRenderer {
+	names := []aggregates.NotificationTemplate{
+		aggregates.OrderReceivedTemplate,
+		aggregates.OrderReadyTemplate,
+		aggregates.OrderOutForDeliveryTemplate,
+		aggregates.OrderDeliveredTemplate,
+		aggregates.OrderCancelledTemplate,
+		aggregates.PaymentConfirmedTemplate,
+	}
+
+	renderer := &templateRenderer{templates: make(map[aggregates.NotificationTemplate]*template.Template, len(names))}
+	for _, name := range names {
+		renderer.templates[name] = template.Must(
+			template.New(string(name)).Funcs(templateFuncs).ParseFS(templateFiles, "templates/contact.tmpl", "templates/"+string(name)+".tmpl"),
+		)
+	}
+
+	return renderer
+}
+
+func (r *templateRenderer) Render(name aggregates.NotificationTemplate, data ports.NotificationData) (string, string, error) {
+	tmpl, ok := r.templates[name]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ports.ErrUnknownNotificationTemplate, name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+
+	return strings.TrimSpace(subject.String()), strings.TrimSpace(body.String()), nil
+}
+
+// money formata no padrão brasileiro, R$ 1.234,50
+func money(value float64) string {
+	cents := int64(math.Round(value * 100))
+	integer := fmt.Sprintf("%d", cents/100)
+
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("R$ %s,%02d", grouped.String(), cents%100)
+}
+
+// shortId é o mesmo número impresso no ticket do pedido
+func shortId(id string) string {
+	if len(id) < 8 {
+		return "#" + strings.ToUpper(id)
+	}
+	return "#" + strings.ToUpper(id[:8])
+}
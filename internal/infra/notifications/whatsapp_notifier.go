@@ -0,0 +1,63 @@
+package notifications
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	notificationchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_channel"
+)
+
+// WhatsAppConfig aponta para a WhatsApp Business Cloud API; PhoneNumberId é o número
+// remetente cadastrado no Meta Business
+type WhatsAppConfig struct {
+	ApiUrl        string
+	PhoneNumberId string
+	AccessToken   string
+}
+
+type whatsAppNotifier struct {
+	config WhatsAppConfig
+	client *http.Client
+}
+
+func NewWhatsAppNotifier(config WhatsAppConfig) ports.INotifier {
+	return &whatsAppNotifier{
+		config: config,
+		client: &http.Client{Timeout: providerTimeout},
+	}
+}
+
+func (n *whatsAppNotifier) Channel() notificationchannel.NotificationChannel {
+	return notificationchannel.WHATSAPP
+}
+
+type (
+	whatsAppText struct {
+		Body string `json:"body"`
+	}
+
+	whatsAppMessage struct {
+		MessagingProduct string       `json:"messaging_product"`
+		To               string       `json:"to"`
+		Type             string       `json:"type"`
+		Text             whatsAppText `json:"text"`
+	}
+)
+
+// Send manda mensagem de texto livre, que a Meta só entrega dentro da janela de 24 horas
+// da última mensagem do cliente; fora dela a API devolve erro e a notificação fica como falha
+func (n *whatsAppNotifier) Send(message ports.NotificationMessage) error {
+	phone, err := internationalPhone(message.Recipient)
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimRight(n.config.ApiUrl, "/") + "/" + n.config.PhoneNumberId + "/messages"
+	return postJson(n.client, url, n.config.AccessToken, whatsAppMessage{
+		MessagingProduct: "whatsapp",
+		To:               phone,
+		Type:             "text",
+		Text:             whatsAppText{Body: message.Body},
+	})
+}
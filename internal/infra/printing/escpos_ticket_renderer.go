@@ -0,0 +1,254 @@
+package printing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/escpos"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+const dateTimeLayout = "02/01/2006 15:04"
+
+type escPosTicketRenderer struct{}
+
+func NewEscPosTicketRenderer() ports.ITicketRenderer {
+	return &escPosTicketRenderer{}
+}
+
+func (r *escPosTicketRenderer) KitchenTicket(restaurant *aggregates.Restaurant, order *aggregates.Order, paper escpos.PaperWidth) *escpos.Document {
+	doc := escpos.NewDocument(paper)
+
+	doc.Align(escpos.AlignCenter).
+		Bold(true).
+		DoubleSize(true).
+		Line("COZINHA").
+		DoubleSize(false).
+		Line("PEDIDO #" + shortId(order.Id)).
+		Bold(false).
+		Line(order.CreatedAt.Format(dateTimeLayout))
+
+	doc.Align(escpos.AlignLeft).Separator('=')
+	if name := customerName(order.Customer); name != "" {
+		doc.Line("Cliente: " + name)
+	}
+	doc.Bold(true)
+	if order.Delivery != nil {
+		doc.Line("ENTREGA")
+	} else {
+		doc.Line("RETIRADA NO BALCÃO")
+	}
+	doc.Bold(false).Separator('-')
+
+	for _, item := range order.Items {
+		doc.Bold(true).Line(fmt.Sprintf("%dx %s", item.Quantity, item.Product.Name)).Bold(false)
+
+		if item.Lunchbox != nil {
+			for _, selection := range item.Lunchbox.Selections {
+				if selection.IsAdditional {
+					doc.Line(fmt.Sprintf("   + %dx %s (adicional)", selection.Quantity, selection.DishName))
+				} else {
+					doc.Line(fmt.Sprintf("   - %dx %s", selection.Quantity, selection.DishName))
+				}
+			}
+			doc.Line("   " + flatwareLabel(item.Lunchbox.WantsFlatware))
+		}
+
+		if item.Observation != "" {
+			doc.Bold(true).Line("   OBS: " + item.Observation).Bold(false)
+		}
+		doc.Separator('-')
+	}
+
+	if order.Observation != "" {
+		doc.Bold(true).Line("OBS DO PEDIDO: " + order.Observation).Bold(false)
+	}
+
+	if order.Delivery != nil {
+		doc.Line("Entregar em:")
+		for _, line := range addressLines(order.Delivery.Address) {
+			doc.Line("  " + line)
+		}
+	}
+
+	return doc.Feed(3).Cut()
+}
+
+func (r *escPosTicketRenderer) Receipt(restaurant *aggregates.Restaurant, order *aggregates.Order, paper escpos.PaperWidth) *escpos.Document {
+	doc := escpos.NewDocument(paper)
+
+	doc.Align(escpos.AlignCenter).Bold(true).Line(restaurant.TradeName).Bold(false)
+	if restaurant.LegalName != "" {
+		doc.Line(restaurant.LegalName)
+	}
+	if restaurant.Settings.ShowCnpjInReceipt && restaurant.CNPJ != "" {
+		doc.Line("CNPJ: " + formatCnpj(restaurant.CNPJ))
+	}
+	for _, line := range addressLines(restaurant.Address) {
+		doc.Line(line)
+	}
+	if restaurant.ContactPhone != "" {
+		doc.Line("Tel: " + restaurant.ContactPhone)
+	}
+
+	doc.Align(escpos.AlignLeft).Separator('-')
+	doc.Justify("PEDIDO #"+shortId(order.Id), order.CreatedAt.Format(dateTimeLayout))
+	if name := customerName(order.Customer); name != "" {
+		doc.Line("Cliente: " + name)
+	}
+	doc.Separator('-')
+
+	for _, item := range order.Items {
+		doc.Justify(fmt.Sprintf("%dx %s", item.Quantity, item.Product.Name), formatMoney(item.Total))
+		if item.Quantity > 1 {
+			doc.Line(fmt.Sprintf("   %s cada", formatMoney(item.Total/float64(item.Quantity))))
+		}
+
+		if item.Lunchbox != nil {
+			for _, selection := range item.Lunchbox.Selections {
+				if selection.IsAdditional {
+					doc.Justify(
+						fmt.Sprintf("   + %dx %s", selection.Quantity, selection.DishName),
+						formatMoney(selection.Price*float64(selection.Quantity)),
+					)
+				} else {
+					doc.Line(fmt.Sprintf("   - %dx %s", selection.Quantity, selection.DishName))
+				}
+			}
+		}
+
+		if item.Discount > 0 {
+			doc.Justify("   Desconto", "-"+formatMoney(item.Discount))
+		}
+		if item.Observation != "" {
+			doc.Line("   Obs: " + item.Observation)
+		}
+	}
+
+	doc.Separator('-')
+	doc.Justify("Subtotal", formatMoney(order.Subtotal))
+	if order.Discount > 0 {
+		doc.Justify("Desconto", "-"+formatMoney(order.Discount))
+	}
+	if order.Delivery != nil {
+		doc.Justify("Taxa de entrega", formatMoney(order.Delivery.Fee))
+	}
+	doc.Bold(true).Justify("TOTAL", formatMoney(order.Total)).Bold(false)
+
+	if len(order.Payments) > 0 {
+		doc.Separator('-')
+		for _, payment := range order.Payments {
+			doc.Justify(strings.ToUpper(payment.PaymentMethod), formatMoney(payment.Amount))
+		}
+	}
+
+	if order.Observation != "" {
+		doc.Separator('-').Line("Obs: " + order.Observation)
+	}
+
+	if order.Delivery != nil {
+		doc.Separator('-').Line("Entrega:")
+		for _, line := range addressLines(order.Delivery.Address) {
+			doc.Line("  " + line)
+		}
+	}
+
+	doc.Separator('-').
+		Align(escpos.AlignCenter).
+		Line("Obrigado pela preferência!").
+		Line("NÃO É DOCUMENTO FISCAL")
+
+	return doc.Feed(3).Cut()
+}
+
+// shortId usa o começo do uuid como número do pedido, suficiente para a conferência no balcão
+func shortId(id string) string {
+	if len(id) < 8 {
+		return strings.ToUpper(id)
+	}
+	return strings.ToUpper(id[:8])
+}
+
+func customerName(customer aggregates.PartialCustomer) string {
+	return strings.TrimSpace(customer.FirstName + " " + customer.LastName)
+}
+
+func flatwareLabel(wantsFlatware bool) string {
+	if wantsFlatware {
+		return "* COM talheres"
+	}
+	return "* SEM talheres"
+}
+
+func addressLines(address types.Address) []string {
+	lines := make([]string, 0, 3)
+
+	street := strings.TrimSpace(strings.Join(nonEmpty(address.Street, address.Number), ", "))
+	if address.Complement != "" {
+		street += " - " + address.Complement
+	}
+	if street != "" {
+		lines = append(lines, street)
+	}
+
+	city := strings.Join(nonEmpty(address.City, string(address.State)), "/")
+	if district := strings.Join(nonEmpty(address.Neighborhood, city), " - "); district != "" {
+		lines = append(lines, district)
+	}
+
+	if address.ZipCode != "" {
+		lines = append(lines, "CEP "+address.ZipCode)
+	}
+
+	return lines
+}
+
+func nonEmpty(values ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func formatCnpj(cnpj string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, cnpj)
+
+	if len(digits) != 14 {
+		return cnpj
+	}
+
+	return fmt.Sprintf("%s.%s.%s/%s-%s", digits[:2], digits[2:5], digits[5:8], digits[8:12], digits[12:])
+}
+
+// formatMoney formata no padrão brasileiro: R$ 1.234,56
+func formatMoney(value float64) string {
+	cents := int64(math.Round(value * 100))
+
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	units := fmt.Sprintf("%d", cents/100)
+	var grouped strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%sR$ %s,%02d", sign, grouped.String(), cents%100)
+}
@@ -0,0 +1,129 @@
+package printing_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/printing"
+	dishtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/dish_type"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	"github.com/PedroNetto404/marmitech-backend/pkg/escpos"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test ./internal/infra/printing -update regrava os arquivos de referência
+var update = flag.Bool("update", false, "update golden files")
+
+func fixture() (*aggregates.Restaurant, *aggregates.Order) {
+	address := types.Address{
+		Street:       "Rua das Flores",
+		Number:       "120",
+		Complement:   "Loja 2",
+		Neighborhood: "Centro",
+		City:         "Belo Horizonte",
+		State:        "MG",
+		ZipCode:      "30110-000",
+	}
+
+	restaurant := &aggregates.Restaurant{
+		TradeName:    "Marmitaria da Vó",
+		LegalName:    "Vó Cozinha Caseira LTDA",
+		CNPJ:         "12345678000195",
+		ContactPhone: "(31) 3333-4444",
+		Address:      address,
+		Settings:     aggregates.RestaurantSettings{ShowCnpjInReceipt: true},
+	}
+	restaurant.Id = "3f0c2a9e-restaurant"
+
+	order := &aggregates.Order{
+		Restaurant:  aggregates.PartialRestaurant{Id: "3f0c2a9e-restaurant"},
+		Customer:    aggregates.PartialCustomer{FirstName: "João", LastName: "Silva"},
+		Status:      orderstatus.PENDING,
+		Subtotal:    56,
+		Total:       61,
+		Observation: "Interfone quebrado, ligar ao chegar",
+		Delivery: &aggregates.OrderDelivery{
+			Address: types.Address{Street: "Av. Afonso Pena", Number: "1500", Neighborhood: "Funcionários", City: "Belo Horizonte", State: "MG"},
+			Fee:     5,
+		},
+		Items: []aggregates.OrderItem{
+			{
+				Product:     aggregates.PartialProduct{Name: "Marmita Grande"},
+				UnitPrice:   22,
+				Quantity:    2,
+				Total:       48,
+				Observation: "sem cebola",
+				Lunchbox: &aggregates.LunchboxComposition{
+					WantsFlatware: true,
+					Selections: []aggregates.LunchboxSelection{
+						{DishName: "Arroz branco", DishType: dishtype.ACCOMPANIMENT, Quantity: 1},
+						{DishName: "Feijão tropeiro", DishType: dishtype.ACCOMPANIMENT, Quantity: 1},
+						{DishName: "Frango à parmegiana", DishType: dishtype.MEAT, Quantity: 1},
+						{DishName: "Ovo frito", DishType: dishtype.OTHER, Quantity: 1, IsAdditional: true, Price: 2},
+					},
+				},
+			},
+			{
+				Product:   aggregates.PartialProduct{Name: "Suco de laranja 500ml"},
+				UnitPrice: 8,
+				Quantity:  1,
+				Total:     8,
+			},
+		},
+		Payments:  []aggregates.OrderPayment{{PaymentMethod: "pix", Amount: 61, PixKey: "pix@marmitariadavo.com.br"}},
+		CreatedAt: time.Date(2026, time.March, 14, 12, 5, 0, 0, time.UTC),
+	}
+	order.Id = "a1b2c3d4-e5f6-4711-8899-aabbccddeeff"
+
+	return restaurant, order
+}
+
+func assertGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual, "saída diferente de %s", path)
+}
+
+func TestKitchenTicketMatchesGolden(t *testing.T) {
+	// arrange
+	restaurant, order := fixture()
+	renderer := printing.NewEscPosTicketRenderer()
+
+	// act
+	ticket := renderer.KitchenTicket(restaurant, order, escpos.Paper58mm)
+
+	// assert
+	assertGolden(t, "kitchen_58mm.bin", ticket.Bytes())
+	assertGolden(t, "kitchen_58mm.txt", []byte(ticket.Text()))
+}
+
+func TestReceiptMatchesGoldenAndHonorsCnpjSetting(t *testing.T) {
+	// arrange
+	restaurant, order := fixture()
+	renderer := printing.NewEscPosTicketRenderer()
+
+	// act
+	receipt := renderer.Receipt(restaurant, order, escpos.Paper80mm)
+	restaurant.Settings.ShowCnpjInReceipt = false
+	withoutCnpj := renderer.Receipt(restaurant, order, escpos.Paper80mm)
+
+	// assert
+	assertGolden(t, "receipt_80mm.bin", receipt.Bytes())
+	assertGolden(t, "receipt_80mm.txt", []byte(receipt.Text()))
+
+	assert.Contains(t, receipt.Text(), "CNPJ: 12.345.678/0001-95")
+	assert.NotContains(t, withoutCnpj.Text(), "CNPJ", "CNPJ omitido quando desabilitado nas configurações")
+}
@@ -0,0 +1,257 @@
+package printing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/pdf"
+)
+
+const (
+	pdfMargin     = 48.0
+	pdfLogoHeight = 64.0
+	pdfBodySize   = 10.0
+	pdfSmallSize  = 8.5
+)
+
+type (
+	pdfReceiptRenderer struct{}
+
+	// pdfCursor acompanha a posição vertical e abre uma nova página quando o conteúdo não cabe
+	pdfCursor struct {
+		doc  *pdf.Document
+		page *pdf.Page
+		y    float64
+	}
+)
+
+func NewPdfReceiptRenderer() ports.IReceiptPdfRenderer {
+	return &pdfReceiptRenderer{}
+}
+
+func (r *pdfReceiptRenderer) Render(restaurant *aggregates.Restaurant, order *aggregates.Order, logo []byte) ([]byte, error) {
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	cursor := &pdfCursor{doc: doc, page: doc.AddPage(), y: pdfMargin}
+
+	err := r.header(cursor, restaurant, logo)
+	if err != nil {
+		return nil, err
+	}
+
+	r.summary(cursor, order)
+	r.items(cursor, order)
+	r.totals(cursor, order)
+	r.payments(cursor, order)
+
+	if order.Delivery != nil {
+		cursor.section("Entrega")
+		for _, line := range addressLines(order.Delivery.Address) {
+			cursor.text(pdfMargin, pdf.Helvetica, pdfBodySize, line)
+		}
+	}
+
+	if order.Observation != "" {
+		cursor.section("Observações")
+		cursor.paragraph(pdfMargin, pdf.Helvetica, pdfBodySize, order.Observation)
+	}
+
+	cursor.skip(24)
+	cursor.centered(pdf.HelveticaBold, pdfBodySize, "Obrigado pela preferência!")
+	cursor.centered(pdf.Helvetica, pdfSmallSize, "Este recibo não é documento fiscal.")
+
+	return doc.Bytes(), nil
+}
+
+func (r *pdfReceiptRenderer) header(cursor *pdfCursor, restaurant *aggregates.Restaurant, logo []byte) error {
+	textX := pdfMargin
+	top := cursor.y
+
+	if len(logo) > 0 {
+		img, err := cursor.doc.AddImage(logo)
+		if err != nil {
+			return fmt.Errorf("invalid restaurant logo: %w", err)
+		}
+
+		width, height := img.Size()
+		logoWidth := pdfLogoHeight * float64(width) / float64(height)
+		cursor.page.Image(img, pdfMargin, top, logoWidth, pdfLogoHeight)
+		textX += logoWidth + 16
+	}
+
+	cursor.text(textX, pdf.HelveticaBold, 16, restaurant.TradeName)
+	if restaurant.LegalName != "" {
+		cursor.text(textX, pdf.Helvetica, pdfSmallSize, restaurant.LegalName)
+	}
+	if restaurant.Settings.ShowCnpjInReceipt && restaurant.CNPJ != "" {
+		cursor.text(textX, pdf.Helvetica, pdfSmallSize, "CNPJ: "+formatCnpj(restaurant.CNPJ))
+	}
+	for _, line := range addressLines(restaurant.Address) {
+		cursor.text(textX, pdf.Helvetica, pdfSmallSize, line)
+	}
+
+	contacts := nonEmpty(restaurant.ContactPhone, restaurant.WhatsAppPhone, restaurant.Email)
+	if len(contacts) > 0 {
+		cursor.text(textX, pdf.Helvetica, pdfSmallSize, strings.Join(contacts, "  ·  "))
+	}
+
+	cursor.y = max(cursor.y, top+pdfLogoHeight) + 12
+	cursor.rule(1)
+	return nil
+}
+
+func (r *pdfReceiptRenderer) summary(cursor *pdfCursor, order *aggregates.Order) {
+	cursor.skip(8)
+	cursor.justify(pdf.HelveticaBold, 13, "Recibo do pedido #"+shortId(order.Id), order.CreatedAt.Format(dateTimeLayout))
+	if name := customerName(order.Customer); name != "" {
+		cursor.text(pdfMargin, pdf.Helvetica, pdfBodySize, "Cliente: "+name)
+	}
+}
+
+func (r *pdfReceiptRenderer) items(cursor *pdfCursor, order *aggregates.Order) {
+	const (
+		quantityX = 360.0
+		unitX     = 460.0
+	)
+	totalX := pdf.A4Width - pdfMargin
+
+	cursor.skip(12)
+	cursor.ensure(2 * 14)
+	cursor.page.FillRect(pdfMargin, cursor.y, pdf.A4Width-2*pdfMargin, 18, 0.92)
+	cursor.y += 13
+	cursor.page.Text(pdfMargin+4, cursor.y, pdf.HelveticaBold, pdfBodySize, "Item")
+	cursor.rightText(quantityX, pdf.HelveticaBold, pdfBodySize, "Qtd")
+	cursor.rightText(unitX, pdf.HelveticaBold, pdfBodySize, "Unitário")
+	cursor.rightText(totalX-4, pdf.HelveticaBold, pdfBodySize, "Total")
+	cursor.y += 8
+
+	for _, item := range order.Items {
+		cursor.skip(6)
+		lines := pdf.WrapText(pdf.HelveticaBold, pdfBodySize, item.Product.Name, quantityX-pdfMargin-40)
+		cursor.ensure(float64(len(lines)) * 14)
+
+		cursor.y += 14
+		cursor.page.Text(pdfMargin+4, cursor.y, pdf.HelveticaBold, pdfBodySize, lines[0])
+		cursor.rightText(quantityX, pdf.Helvetica, pdfBodySize, fmt.Sprintf("%d", item.Quantity))
+		cursor.rightText(unitX, pdf.Helvetica, pdfBodySize, formatMoney(item.Total/float64(item.Quantity)))
+		cursor.rightText(totalX-4, pdf.Helvetica, pdfBodySize, formatMoney(item.Total))
+		for _, line := range lines[1:] {
+			cursor.text(pdfMargin+4, pdf.HelveticaBold, pdfBodySize, line)
+		}
+
+		if item.Lunchbox != nil {
+			for _, selection := range item.Lunchbox.Selections {
+				if selection.IsAdditional {
+					cursor.text(pdfMargin+16, pdf.Helvetica, pdfSmallSize, fmt.Sprintf("+ %dx %s", selection.Quantity, selection.DishName))
+					cursor.rightText(unitX, pdf.Helvetica, pdfSmallSize, formatMoney(selection.Price*float64(selection.Quantity)))
+				} else {
+					cursor.text(pdfMargin+16, pdf.Helvetica, pdfSmallSize, fmt.Sprintf("%dx %s", selection.Quantity, selection.DishName))
+				}
+			}
+			if item.Lunchbox.WantsFlatware {
+				cursor.text(pdfMargin+16, pdf.Helvetica, pdfSmallSize, "Com talheres")
+			}
+		}
+
+		if item.Discount > 0 {
+			cursor.text(pdfMargin+16, pdf.Helvetica, pdfSmallSize, "Desconto no item")
+			cursor.rightText(totalX-4, pdf.Helvetica, pdfSmallSize, "-"+formatMoney(item.Discount))
+		}
+		if item.Observation != "" {
+			cursor.paragraph(pdfMargin+16, pdf.Helvetica, pdfSmallSize, "Obs: "+item.Observation)
+		}
+	}
+
+	cursor.skip(8)
+	cursor.rule(0.5)
+}
+
+func (r *pdfReceiptRenderer) totals(cursor *pdfCursor, order *aggregates.Order) {
+	cursor.skip(4)
+	cursor.amount(pdf.Helvetica, "Subtotal", formatMoney(order.Subtotal))
+	if order.Discount > 0 {
+		cursor.amount(pdf.Helvetica, "Desconto", "-"+formatMoney(order.Discount))
+	}
+	if order.Delivery != nil {
+		cursor.amount(pdf.Helvetica, "Taxa de entrega", formatMoney(order.Delivery.Fee))
+	}
+	cursor.skip(2)
+	cursor.amount(pdf.HelveticaBold, "Total", formatMoney(order.Total))
+}
+
+func (r *pdfReceiptRenderer) payments(cursor *pdfCursor, order *aggregates.Order) {
+	if len(order.Payments) == 0 {
+		return
+	}
+
+	cursor.section("Pagamentos")
+	for _, payment := range order.Payments {
+		cursor.justify(pdf.Helvetica, pdfBodySize, strings.ToUpper(payment.PaymentMethod), formatMoney(payment.Amount))
+		if payment.PixKey != "" {
+			cursor.text(pdfMargin+16, pdf.Helvetica, pdfSmallSize, "Chave Pix: "+payment.PixKey)
+		}
+	}
+}
+
+// ensure quebra a página quando não há espaço para height pontos
+func (c *pdfCursor) ensure(height float64) {
+	if c.y+height <= pdf.A4Height-pdfMargin {
+		return
+	}
+
+	c.page = c.doc.AddPage()
+	c.y = pdfMargin
+}
+
+func (c *pdfCursor) skip(height float64) {
+	c.ensure(height)
+	c.y += height
+}
+
+func (c *pdfCursor) lineHeight(size float64) float64 {
+	return size * 1.4
+}
+
+func (c *pdfCursor) text(x float64, font pdf.Font, size float64, text string) {
+	c.skip(c.lineHeight(size))
+	c.page.Text(x, c.y, font, size, text)
+}
+
+func (c *pdfCursor) paragraph(x float64, font pdf.Font, size float64, text string) {
+	for _, line := range pdf.WrapText(font, size, text, pdf.A4Width-pdfMargin-x) {
+		c.text(x, font, size, line)
+	}
+}
+
+// rightText alinha o texto à direita de x na linha atual
+func (c *pdfCursor) rightText(x float64, font pdf.Font, size float64, text string) {
+	c.page.Text(x-pdf.TextWidth(font, size, text), c.y, font, size, text)
+}
+
+func (c *pdfCursor) centered(font pdf.Font, size float64, text string) {
+	c.text((pdf.A4Width-pdf.TextWidth(font, size, text))/2, font, size, text)
+}
+
+func (c *pdfCursor) justify(font pdf.Font, size float64, left, right string) {
+	c.text(pdfMargin, font, size, left)
+	c.rightText(pdf.A4Width-pdfMargin, font, size, right)
+}
+
+// amount escreve as linhas de totais alinhadas na coluna da direita
+func (c *pdfCursor) amount(font pdf.Font, label, value string) {
+	c.skip(c.lineHeight(11))
+	c.rightText(400, font, 11, label)
+	c.rightText(pdf.A4Width-pdfMargin-4, font, 11, value)
+}
+
+func (c *pdfCursor) section(title string) {
+	c.skip(12)
+	c.text(pdfMargin, pdf.HelveticaBold, 11, title)
+	c.y += 4
+	c.rule(0.5)
+}
+
+func (c *pdfCursor) rule(width float64) {
+	c.page.Line(pdfMargin, c.y, pdf.A4Width-pdfMargin, c.y, width)
+}
@@ -0,0 +1,42 @@
+package printing_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/printing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPdfReceiptContainsLogoCnpjAndPixKey(t *testing.T) {
+	// arrange
+	restaurant, order := fixture()
+	renderer := printing.NewPdfReceiptRenderer()
+
+	logo := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	logo.Set(0, 0, color.RGBA{R: 200, A: 255})
+	var encoded bytes.Buffer
+	require.NoError(t, png.Encode(&encoded, logo))
+
+	// act
+	receipt, err := renderer.Render(restaurant, order, encoded.Bytes())
+	restaurant.Settings.ShowCnpjInReceipt = false
+	withoutCnpj, errWithoutCnpj := renderer.Render(restaurant, order, nil)
+
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, errWithoutCnpj)
+
+	assert := assert.New(t)
+	assert.True(bytes.HasPrefix(receipt, []byte("%PDF-1.4")))
+	assert.True(bytes.HasSuffix(receipt, []byte("%%EOF\n")))
+	assert.Contains(string(receipt), "/Subtype /Image /Width 4 /Height 2", "logo embutido no PDF")
+	assert.Contains(string(receipt), "(CNPJ: 12.345.678/0001-95)")
+	assert.Contains(string(receipt), "(Chave Pix: pix@marmitariadavo.com.br)")
+	assert.NotContains(string(withoutCnpj), "CNPJ", "CNPJ omitido quando desabilitado nas configurações")
+	assert.NotContains(string(withoutCnpj), "/XObject", "sem logo não há imagem")
+}
@@ -0,0 +1,74 @@
+package respositories
+
+import (
+	"database/sql"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+type addressRepository struct {
+	db *database.Db
+}
+
+func NewAddressRepository(db *database.Db) ports.IAddressRepository {
+	return &addressRepository{
+		db: db,
+	}
+}
+
+func (r *addressRepository) FindWithoutCoordinates(afterId string, limit int) ([]types.Address, error) {
+	query := `
+		SELECT ` + AddressFields + `
+		FROM addresses a
+		WHERE a.id > ?
+			AND COALESCE(a.lat, 0) = 0 AND COALESCE(a.lng, 0) = 0
+			AND a.id IN (
+				SELECT address_id FROM restaurants WHERE deleted_at IS NULL
+				UNION
+				SELECT address_id FROM customers WHERE deleted_at IS NULL
+			)
+		ORDER BY a.id
+		LIMIT ?`
+
+	rows, err := r.db.Instance.Query(query, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := make([]types.Address, 0)
+	for rows.Next() {
+		var address types.Address
+		// só chegam aqui coordenadas zeradas ou nulas, então não precisam ser lidas
+		var lat, lng sql.NullFloat64
+		err := rows.Scan(
+			&address.Id,
+			&address.Alias,
+			&address.Street,
+			&address.Number,
+			&address.Complement,
+			&address.Neighborhood,
+			&address.City,
+			&address.State,
+			&address.Country,
+			&address.ZipCode,
+			&lat,
+			&lng,
+		)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+
+	return addresses, rows.Err()
+}
+
+func (r *addressRepository) UpdateCoordinates(id string, point types.GeoPoint) error {
+	query := `UPDATE addresses SET lat = ?, lng = ? WHERE id = ?`
+
+	_, err := r.db.Instance.Exec(query, point.Lat, point.Lng, id)
+	return err
+}
@@ -0,0 +1,171 @@
+package respositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+type auditLogRepository struct {
+	db *database.Db
+}
+
+func NewAuditLogRepository(db *database.Db) ports.IAuditLogRepository {
+	return &auditLogRepository{
+		db: db,
+	}
+}
+
+const (
+	auditLogBaseFields = `
+		al.id,
+		al.restaurant_id,
+		al.actor_email,
+		al.actor_role,
+		al.ip,
+		al.aggregate_type,
+		al.aggregate_id,
+		al.action,
+		al.diff,
+		al.created_at`
+)
+
+// insertAuditRecords grava no audit_log, dentro da transação do agregado, as alterações anexadas a ele;
+// quem chama limpa os registros depois do commit
+func insertAuditRecords(tx *sql.Tx, aggregate abstractions.IAggreagateRoot) error {
+	query := `
+		INSERT INTO audit_log (
+			id, restaurant_id, actor_email, actor_role, ip,
+			aggregate_type, aggregate_id, action, diff, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for _, audit := range aggregate.AuditRecords() {
+		record := aggregates.NewAuditLog(
+			audit.RestaurantId,
+			audit.Actor,
+			audit.AggregateType,
+			aggregate.GetId(),
+			aggregates.AuditAction(audit.Action),
+			audit.Diff,
+		)
+
+		diffJSON, err := json.Marshal(record.Diff)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			query,
+			record.Id,
+			record.RestaurantId,
+			record.Actor.Email,
+			record.Actor.Role,
+			record.Actor.Ip,
+			record.AggregateType,
+			record.AggregateId,
+			record.Action,
+			diffJSON,
+			record.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *auditLogRepository) Find(query ports.AuditLogQuery) (*types.PagedSlice[aggregates.AuditLog], error) {
+	conditions := []string{"al.restaurant_id = ?"}
+	params := []any{query.RestaurantId}
+
+	if query.AggregateType != "" {
+		conditions = append(conditions, "al.aggregate_type = ?")
+		params = append(params, query.AggregateType)
+	}
+	if query.AggregateId != "" {
+		conditions = append(conditions, "al.aggregate_id = ?")
+		params = append(params, query.AggregateId)
+	}
+	if query.ActorEmail != "" {
+		conditions = append(conditions, "al.actor_email = ?")
+		params = append(params, query.ActorEmail)
+	}
+	if query.Action != "" {
+		conditions = append(conditions, "al.action = ?")
+		params = append(params, query.Action)
+	}
+	if query.From != nil {
+		conditions = append(conditions, "al.created_at >= ?")
+		params = append(params, *query.From)
+	}
+	if query.To != nil {
+		conditions = append(conditions, "al.created_at <= ?")
+		params = append(params, *query.To)
+	}
+
+	where := strings.Join(conditions, " AND ")
+
+	var count int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM audit_log al
+		WHERE ` + where
+	if err := r.db.Instance.QueryRow(countQuery, params...).Scan(&count); err != nil {
+		return nil, err
+	}
+
+	selectQuery := `
+		SELECT
+			` + auditLogBaseFields + `
+		FROM audit_log al
+		WHERE ` + where + `
+		ORDER BY al.created_at DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := r.db.Instance.Query(selectQuery, append(params, query.Limit, query.Offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := make([]aggregates.AuditLog, 0, 10)
+	for rows.Next() {
+		var log aggregates.AuditLog
+		var diffJSON []byte
+		err := rows.Scan(
+			&log.Id,
+			&log.RestaurantId,
+			&log.Actor.Email,
+			&log.Actor.Role,
+			&log.Actor.Ip,
+			&log.AggregateType,
+			&log.AggregateId,
+			&log.Action,
+			&diffJSON,
+			&log.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(diffJSON, &log.Diff); err != nil {
+			return nil, err
+		}
+
+		logs = append(logs, log)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pagedSlice := types.NewPagedSlice(query.Limit, query.Offset, count, logs)
+	return &pagedSlice, nil
+}
@@ -0,0 +1,368 @@
+package respositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+type billRepository struct {
+	db *database.Db
+}
+
+func NewBillRepository(db *database.Db) ports.IBillRepository {
+	return &billRepository{
+		db: db,
+	}
+}
+
+const (
+	billBaseFields = `
+		b.id,
+		b.restaurant_id,
+		b.supplier_id,
+		s.name,
+		b.series_id,
+		b.description,
+		b.category,
+		b.amount,
+		b.paid_amount,
+		b.due_date,
+		b.due_day,
+		b.recurrence,
+		b.status,
+		b.note,
+		b.created_at,
+		b.updated_at,
+		b.version`
+)
+
+func (r *billRepository) FindById(id string) (*aggregates.Bill, error) {
+	query := `
+		SELECT
+			` + billBaseFields + `
+		FROM bills b
+		LEFT JOIN suppliers s ON b.supplier_id = s.id
+		WHERE b.id = ?`
+
+	bill, err := scanBill(r.db.Instance.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := r.loadPayments(bill); err != nil {
+		return nil, err
+	}
+
+	return bill, nil
+}
+
+func (r *billRepository) Find(query ports.BillQuery) ([]aggregates.Bill, error) {
+	statement := `
+		SELECT
+			` + billBaseFields + `
+		FROM bills b
+		LEFT JOIN suppliers s ON b.supplier_id = s.id
+		WHERE b.restaurant_id = ?`
+	params := []any{query.RestaurantId}
+
+	if query.SupplierId != "" {
+		statement += ` AND b.supplier_id = ?`
+		params = append(params, query.SupplierId)
+	}
+	if query.Status != "" {
+		statement += ` AND b.status = ?`
+		params = append(params, query.Status)
+	}
+	if query.DueFrom != nil {
+		statement += ` AND b.due_date >= ?`
+		params = append(params, query.DueFrom.Format(time.DateOnly))
+	}
+	if query.DueTo != nil {
+		statement += ` AND b.due_date <= ?`
+		params = append(params, query.DueTo.Format(time.DateOnly))
+	}
+	statement += ` ORDER BY b.due_date ASC, b.created_at ASC`
+
+	rows, err := r.db.Instance.Query(statement, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bills := make([]aggregates.Bill, 0, 10)
+	for rows.Next() {
+		bill, err := scanBill(rows)
+		if err != nil {
+			return nil, err
+		}
+		bills = append(bills, *bill)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range bills {
+		if err := r.loadPayments(&bills[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return bills, nil
+}
+
+func (r *billRepository) Create(bill *aggregates.Bill) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createBill(tx, bill); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, bill); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	bill.ClearAuditRecords()
+	return nil
+}
+
+func (r *billRepository) Update(bill *aggregates.Bill) error {
+	query := `
+		UPDATE bills SET
+			supplier_id = ?,
+			description = ?,
+			category = ?,
+			amount = ?,
+			due_date = ?,
+			due_day = ?,
+			recurrence = ?,
+			status = ?,
+			note = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		nullString(billSupplierId(bill)),
+		bill.Description,
+		bill.Category,
+		bill.Amount,
+		bill.DueDate.Format(time.DateOnly),
+		bill.DueDay,
+		bill.Recurrence,
+		bill.Status,
+		nullString(bill.Note),
+		bill.UpdatedAt,
+		bill.Id,
+		bill.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.BillAggregateType, bill.Id, bill.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, bill); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	bill.Version++
+	bill.ClearAuditRecords()
+	return nil
+}
+
+func (r *billRepository) AddPayment(bill *aggregates.Bill, payment *aggregates.BillPayment, next *aggregates.Bill) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`UPDATE bills SET paid_amount = ?, status = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ?`,
+		bill.PaidAmount,
+		bill.Status,
+		bill.UpdatedAt,
+		bill.Id,
+		bill.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.BillAggregateType, bill.Id, bill.Version); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO bill_payments (
+			id, bill_id, amount, payment_method, paid_at, created_by
+		) VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(
+		query,
+		payment.Id,
+		bill.Id,
+		payment.Amount,
+		payment.PaymentMethod,
+		payment.PaidAt,
+		payment.CreatedBy,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, bill); err != nil {
+		return err
+	}
+
+	if next != nil {
+		if err := createBill(tx, next); err != nil {
+			return err
+		}
+		if err := insertAuditRecords(tx, next); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	bill.Version++
+	bill.ClearAuditRecords()
+	if next != nil {
+		next.ClearAuditRecords()
+	}
+	return nil
+}
+
+func (r *billRepository) loadPayments(bill *aggregates.Bill) error {
+	query := `
+		SELECT id, bill_id, amount, payment_method, paid_at, created_by
+		FROM bill_payments
+		WHERE bill_id = ?
+		ORDER BY paid_at ASC`
+
+	rows, err := r.db.Instance.Query(query, bill.Id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	bill.Payments = make([]aggregates.BillPayment, 0)
+	for rows.Next() {
+		var payment aggregates.BillPayment
+		err := rows.Scan(
+			&payment.Id,
+			&payment.BillId,
+			&payment.Amount,
+			&payment.PaymentMethod,
+			&payment.PaidAt,
+			&payment.CreatedBy,
+		)
+		if err != nil {
+			return err
+		}
+		bill.Payments = append(bill.Payments, payment)
+	}
+
+	return rows.Err()
+}
+
+func createBill(db execer, bill *aggregates.Bill) error {
+	query := `
+		INSERT INTO bills (
+			id, restaurant_id, supplier_id, series_id, description, category, amount, paid_amount,
+			due_date, due_day, recurrence, status, note, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := db.Exec(
+		query,
+		bill.Id,
+		bill.Restaurant.Id,
+		nullString(billSupplierId(bill)),
+		bill.SeriesId,
+		bill.Description,
+		bill.Category,
+		bill.Amount,
+		bill.PaidAmount,
+		bill.DueDate.Format(time.DateOnly),
+		bill.DueDay,
+		bill.Recurrence,
+		bill.Status,
+		nullString(bill.Note),
+		bill.CreatedAt,
+		bill.UpdatedAt,
+	)
+	return err
+}
+
+func billSupplierId(bill *aggregates.Bill) string {
+	if bill.Supplier == nil {
+		return ""
+	}
+	return bill.Supplier.Id
+}
+
+func scanBill(row rowScanner) (*aggregates.Bill, error) {
+	var bill aggregates.Bill
+	var supplierId, supplierName, note sql.NullString
+	err := row.Scan(
+		&bill.Id,
+		&bill.Restaurant.Id,
+		&supplierId,
+		&supplierName,
+		&bill.SeriesId,
+		&bill.Description,
+		&bill.Category,
+		&bill.Amount,
+		&bill.PaidAmount,
+		&bill.DueDate,
+		&bill.DueDay,
+		&bill.Recurrence,
+		&bill.Status,
+		&note,
+		&bill.CreatedAt,
+		&bill.UpdatedAt,
+		&bill.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if supplierId.Valid {
+		bill.Supplier = &aggregates.PartialSupplier{Id: supplierId.String, Name: supplierName.String}
+	}
+	bill.Note = note.String
+
+	return &bill, nil
+}
@@ -0,0 +1,374 @@
+package respositories
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	cashsessionstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/cash_session_status"
+	paymentmethod "github.com/PedroNetto404/marmitech-backend/pkg/enums/payment_method"
+	"github.com/go-sql-driver/mysql"
+)
+
+type cashSessionRepository struct {
+	db *database.Db
+}
+
+func NewCashSessionRepository(db *database.Db) ports.ICashSessionRepository {
+	return &cashSessionRepository{
+		db: db,
+	}
+}
+
+const (
+	cashSessionBaseFields = `
+		cs.id,
+		cs.restaurant_id,
+		cs.operator,
+		cs.status,
+		cs.opening_float,
+		cs.note,
+		cs.opened_at,
+		cs.closed_at,
+		cs.closed_by,
+		cs.version`
+)
+
+func (r *cashSessionRepository) FindById(id string) (*aggregates.CashSession, error) {
+	query := `
+		SELECT
+			` + cashSessionBaseFields + `
+		FROM cash_sessions cs
+		WHERE cs.id = ?`
+
+	return r.findOne(query, id)
+}
+
+func (r *cashSessionRepository) FindOpen(restaurantId, operator string) (*aggregates.CashSession, error) {
+	query := `
+		SELECT
+			` + cashSessionBaseFields + `
+		FROM cash_sessions cs
+		WHERE cs.restaurant_id = ? AND cs.open_operator = ?`
+
+	return r.findOne(query, restaurantId, operator)
+}
+
+func (r *cashSessionRepository) Find(query ports.CashSessionQuery) ([]aggregates.CashSession, error) {
+	statement := `
+		SELECT
+			` + cashSessionBaseFields + `
+		FROM cash_sessions cs
+		WHERE cs.restaurant_id = ?
+		AND cs.opened_at >= ?
+		AND cs.opened_at < ?`
+	params := []any{query.RestaurantId, query.From, query.To}
+
+	if query.Operator != "" {
+		statement += ` AND cs.operator = ?`
+		params = append(params, query.Operator)
+	}
+	statement += ` ORDER BY cs.opened_at ASC`
+
+	rows, err := r.db.Instance.Query(statement, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]aggregates.CashSession, 0, 10)
+	for rows.Next() {
+		session, err := scanCashSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		if err := r.loadChildren(&sessions[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return sessions, nil
+}
+
+func (r *cashSessionRepository) Create(session *aggregates.CashSession) error {
+	query := `
+		INSERT INTO cash_sessions (
+			id, restaurant_id, operator, open_operator, status, opening_float, opened_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		session.Id,
+		session.Restaurant.Id,
+		session.Operator,
+		session.Operator,
+		session.Status,
+		session.OpeningFloat,
+		session.OpenedAt,
+	)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return ports.ErrCashSessionAlreadyOpen
+		}
+		return err
+	}
+
+	if err := insertAuditRecords(tx, session); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	session.ClearAuditRecords()
+	return nil
+}
+
+func (r *cashSessionRepository) AddMovement(session *aggregates.CashSession, movement *aggregates.CashMovement) error {
+	query := `
+		INSERT INTO cash_movements (
+			id, session_id, type, amount, reason, created_by, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		movement.Id,
+		session.Id,
+		movement.Type,
+		movement.Amount,
+		movement.Reason,
+		movement.CreatedBy,
+		movement.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, session); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	session.ClearAuditRecords()
+	return nil
+}
+
+func (r *cashSessionRepository) SumPayments(sessionId string) (map[paymentmethod.PaymentMethod]float64, error) {
+	query := `
+		SELECT op.payment_method, SUM(op.amount)
+		FROM order_payments op
+		WHERE op.cash_session_id = ? AND op.status = 'PAID'
+		GROUP BY op.payment_method`
+
+	rows, err := r.db.Instance.Query(query, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make(map[paymentmethod.PaymentMethod]float64)
+	for rows.Next() {
+		var method paymentmethod.PaymentMethod
+		var amount float64
+		if err := rows.Scan(&method, &amount); err != nil {
+			return nil, err
+		}
+		sales[method] = amount
+	}
+
+	return sales, rows.Err()
+}
+
+// Close libera open_operator junto com o status, para o operador poder abrir um novo caixa
+func (r *cashSessionRepository) Close(session *aggregates.CashSession) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE cash_sessions SET
+			status = ?,
+			open_operator = NULL,
+			note = ?,
+			closed_at = ?,
+			closed_by = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND status = ?`
+
+	result, err := tx.Exec(
+		query,
+		session.Status,
+		nullString(session.Note),
+		session.ClosedAt,
+		session.ClosedBy,
+		session.Id,
+		session.Version,
+		cashsessionstatus.OPEN,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.CashSessionAggregateType, session.Id, session.Version); err != nil {
+		return err
+	}
+
+	countQuery := `
+		INSERT INTO cash_session_counts (
+			session_id, payment_method, expected, counted
+		) VALUES (?, ?, ?, ?)`
+
+	for _, count := range session.Counts {
+		_, err := tx.Exec(countQuery, session.Id, count.PaymentMethod, count.Expected, count.Counted)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := insertDomainEvents(tx, session); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, session); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	session.Version++
+	session.ClearAuditRecords()
+	return nil
+}
+
+func (r *cashSessionRepository) findOne(query string, params ...any) (*aggregates.CashSession, error) {
+	session, err := scanCashSession(r.db.Instance.QueryRow(query, params...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := r.loadChildren(session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *cashSessionRepository) loadChildren(session *aggregates.CashSession) error {
+	query := `
+		SELECT id, session_id, type, amount, reason, created_by, created_at
+		FROM cash_movements
+		WHERE session_id = ?
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.Instance.Query(query, session.Id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	session.Movements = make([]aggregates.CashMovement, 0)
+	for rows.Next() {
+		var movement aggregates.CashMovement
+		err := rows.Scan(
+			&movement.Id,
+			&movement.SessionId,
+			&movement.Type,
+			&movement.Amount,
+			&movement.Reason,
+			&movement.CreatedBy,
+			&movement.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		session.Movements = append(session.Movements, movement)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	countRows, err := r.db.Instance.Query(
+		`SELECT payment_method, expected, counted FROM cash_session_counts WHERE session_id = ? ORDER BY payment_method`,
+		session.Id,
+	)
+	if err != nil {
+		return err
+	}
+	defer countRows.Close()
+
+	session.Counts = make([]aggregates.CashCount, 0)
+	for countRows.Next() {
+		var count aggregates.CashCount
+		if err := countRows.Scan(&count.PaymentMethod, &count.Expected, &count.Counted); err != nil {
+			return err
+		}
+		count.Difference = math.Round((count.Counted-count.Expected)*100) / 100
+		session.Counts = append(session.Counts, count)
+	}
+
+	return countRows.Err()
+}
+
+func scanCashSession(row rowScanner) (*aggregates.CashSession, error) {
+	var session aggregates.CashSession
+	var note, closedBy sql.NullString
+	var closedAt sql.NullTime
+	err := row.Scan(
+		&session.Id,
+		&session.Restaurant.Id,
+		&session.Operator,
+		&session.Status,
+		&session.OpeningFloat,
+		&note,
+		&session.OpenedAt,
+		&closedAt,
+		&closedBy,
+		&session.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Note = note.String
+	session.ClosedBy = closedBy.String
+	if closedAt.Valid {
+		session.ClosedAt = &closedAt.Time
+	}
+
+	return &session, nil
+}
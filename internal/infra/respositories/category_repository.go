@@ -26,7 +26,8 @@ const (
 		c.picture_url,
 		c.priority,
 		c.active,
-		c.restaurant_id`
+		c.restaurant_id,
+		c.version`
 )
 
 func (r *categoryRepository) Find(args types.FindArgs) (*types.PagedSlice[aggregates.Category], error) {
@@ -59,6 +60,7 @@ func (r *categoryRepository) Find(args types.FindArgs) (*types.PagedSlice[aggreg
 			&category.Priority,
 			&category.Active,
 			&category.Restaurant.Id,
+			&category.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -89,6 +91,7 @@ func (r *categoryRepository) FindById(id string) (*aggregates.Category, error) {
 		&category.Priority,
 		&category.Active,
 		&category.Restaurant.Id,
+		&category.Version,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -106,16 +109,41 @@ func (r *categoryRepository) Create(category *aggregates.Category) error {
 			id, name, picture_url, priority, active, restaurant_id
 		) VALUES (?, ?, ?, ?, ?, ?)`
 
-	_, err := r.database.Instance.Exec(
+	tx, err := r.database.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	priority, err := nextPriority(tx, "categories", "restaurant_id", category.Restaurant.Id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
 		query,
 		category.Id,
 		category.Name,
 		category.PictureUrl,
-		category.Priority,
+		priority,
 		category.Active,
 		category.Restaurant.Id,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, category); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	category.Priority = priority
+	category.ClearAuditRecords()
+	return nil
 }
 
 func (r *categoryRepository) Update(category *aggregates.Category) error {
@@ -123,28 +151,72 @@ func (r *categoryRepository) Update(category *aggregates.Category) error {
 		UPDATE categories SET
 			name = ?,
 			picture_url = ?,
-			priority = ?,
-			active = ?
-		WHERE id = ? AND deleted_at IS NULL`
+			active = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL`
 
-	_, err := r.database.Instance.Exec(
+	tx, err := r.database.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
 		query,
 		category.Name,
 		category.PictureUrl,
-		category.Priority,
 		category.Active,
 		category.Id,
+		category.Version,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.CategoryAggregateType, category.Id, category.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, category); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	category.Version++
+	category.ClearAuditRecords()
+	return nil
 }
 
-func (r *categoryRepository) Delete(id string) error {
+func (r *categoryRepository) Delete(category *aggregates.Category) error {
 	query := `
 		UPDATE categories 
 		SET deleted_at = NOW() 
 		WHERE id = ? AND deleted_at IS NULL`
-	_, err := r.database.Instance.Exec(query, id)
-	return err
+
+	tx, err := r.database.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query, category.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, category); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	category.ClearAuditRecords()
+	return nil
 }
 
 func (r *categoryRepository) FindByRestaurantId(restaurantId string) ([]aggregates.Category, error) {
@@ -172,6 +244,7 @@ func (r *categoryRepository) FindByRestaurantId(restaurantId string) ([]aggregat
 			&category.Priority,
 			&category.Active,
 			&category.Restaurant.Id,
+			&category.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -202,3 +275,38 @@ func (r *categoryRepository) Exists(name, restaurantId string) (bool, error) {
 
 	return count > 0, nil
 }
+
+func (r *categoryRepository) Reorder(restaurantId string, categories []aggregates.Category) error {
+	entries := make([]priorityEntry, 0, len(categories))
+	for _, category := range categories {
+		entries = append(entries, priorityEntry{id: category.Id, version: category.Version})
+	}
+
+	tx, err := r.database.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = updatePriorities(tx, "categories", "restaurant_id", restaurantId, aggregates.CategoryAggregateType, entries)
+	if err != nil {
+		return err
+	}
+
+	for i := range categories {
+		if err := insertAuditRecords(tx, &categories[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for i := range categories {
+		categories[i].Priority = i + 1
+		categories[i].Version++
+		categories[i].ClearAuditRecords()
+	}
+	return nil
+}
@@ -26,7 +26,8 @@ const (
 		c.last_name,
 		c.contact_email,
 		c.contact_phone,
-		c.deleted_at`
+		c.deleted_at,
+		c.version`
 )
 
 func (r *customerRepository) Find(args types.FindArgs) (*types.PagedSlice[aggregates.Customer], error) {
@@ -62,6 +63,7 @@ func (r *customerRepository) Find(args types.FindArgs) (*types.PagedSlice[aggreg
 			&customer.ContactEmail,
 			&customer.ContactPhone,
 			&deletedAt,
+			&customer.Version,
 			&customer.Address.Id,
 			&customer.Address.Alias,
 			&customer.Address.Street,
@@ -107,6 +109,7 @@ func (r *customerRepository) FindById(id string) (*aggregates.Customer, error) {
 		&customer.ContactEmail,
 		&customer.ContactPhone,
 		&deletedAt,
+		&customer.Version,
 		&customer.Address.Id,
 		&customer.Address.Alias,
 		&customer.Address.Street,
@@ -183,7 +186,16 @@ func (r *customerRepository) Create(customer *aggregates.Customer) error {
 		return err
 	}
 
-	return tx.Commit()
+	if err := insertAuditRecords(tx, customer); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	customer.ClearAuditRecords()
+	return nil
 }
 
 func (r *customerRepository) Update(customer *aggregates.Customer) error {
@@ -234,30 +246,46 @@ func (r *customerRepository) Update(customer *aggregates.Customer) error {
 			first_name = ?,
 			last_name = ?,
 			contact_email = ?,
-			contact_phone = ?
-		WHERE id = ? AND deleted_at IS NULL`
+			contact_phone = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL`
 
-	_, err = tx.Exec(
+	result, err := tx.Exec(
 		customerQuery,
 		customer.FirstName,
 		customer.LastName,
 		customer.ContactEmail,
 		customer.ContactPhone,
 		customer.Id,
+		customer.Version,
 	)
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	if err := checkVersionedUpdate(result, aggregates.CustomerAggregateType, customer.Id, customer.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, customer); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	customer.Version++
+	customer.ClearAuditRecords()
+	return nil
 }
 
-func (r *customerRepository) Delete(id string) error {
+func (r *customerRepository) Delete(customer *aggregates.Customer) error {
 	query := `
 		UPDATE customers 
 		SET deleted_at = NOW() 
 		WHERE id = ? AND deleted_at IS NULL`
-	_, err := r.database.Instance.Exec(query, id)
+	_, err := r.database.Instance.Exec(query, customer.Id)
 	return err
 }
 
@@ -279,6 +307,7 @@ func (r *customerRepository) FindByEmail(email string) (*aggregates.Customer, er
 		&customer.ContactEmail,
 		&customer.ContactPhone,
 		&deletedAt,
+		&customer.Version,
 		&customer.Address.Id,
 		&customer.Address.Alias,
 		&customer.Address.Street,
@@ -0,0 +1,384 @@
+package respositories
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	"github.com/go-sql-driver/mysql"
+)
+
+type customerTabRepository struct {
+	db *database.Db
+}
+
+func NewCustomerTabRepository(db *database.Db) ports.ICustomerTabRepository {
+	return &customerTabRepository{
+		db: db,
+	}
+}
+
+const (
+	// pagamentos quitam os débitos mais antigos primeiro, então o vencido é
+	// o total de débitos com vencimento passado menos tudo o que já foi pago
+	customerTabOverdueAmount = `
+		GREATEST(0,
+			COALESCE((
+				SELECT SUM(e.amount) FROM customer_tab_entries e
+				WHERE e.tab_id = t.id AND e.type = 'debit' AND e.due_date < CURDATE()
+			), 0) -
+			COALESCE((
+				SELECT SUM(e.amount) FROM customer_tab_entries e
+				WHERE e.tab_id = t.id AND e.type = 'credit'
+			), 0)
+		)`
+
+	customerTabBaseFields = `
+		t.id,
+		t.restaurant_id,
+		t.customer_id,
+		c.first_name,
+		c.last_name,
+		c.contact_email,
+		t.credit_limit,
+		t.balance,
+		t.due_day,
+		` + customerTabOverdueAmount + ` AS overdue_amount,
+		t.created_at,
+		t.updated_at,
+		t.version`
+
+	customerTabEntryBaseFields = `
+		e.id,
+		e.tab_id,
+		e.type,
+		e.amount,
+		e.order_id,
+		e.payment_method,
+		e.description,
+		e.due_date,
+		e.created_by,
+		e.created_at`
+)
+
+func (r *customerTabRepository) FindById(id string) (*aggregates.CustomerTab, error) {
+	query := `
+		SELECT
+			` + customerTabBaseFields + `
+		FROM customer_tabs t
+		JOIN customers c ON t.customer_id = c.id
+		WHERE t.id = ?`
+
+	return r.findOne(query, id)
+}
+
+func (r *customerTabRepository) FindByCustomerId(restaurantId, customerId string) (*aggregates.CustomerTab, error) {
+	query := `
+		SELECT
+			` + customerTabBaseFields + `
+		FROM customer_tabs t
+		JOIN customers c ON t.customer_id = c.id
+		WHERE t.restaurant_id = ? AND t.customer_id = ?`
+
+	return r.findOne(query, restaurantId, customerId)
+}
+
+func (r *customerTabRepository) FindOpen(restaurantId string, onlyOverdue bool) ([]aggregates.CustomerTab, error) {
+	query := `
+		SELECT
+			` + customerTabBaseFields + `
+		FROM customer_tabs t
+		JOIN customers c ON t.customer_id = c.id
+		WHERE t.restaurant_id = ? AND t.balance > 0`
+
+	if onlyOverdue {
+		query += `
+		HAVING overdue_amount > 0`
+	}
+	query += `
+		ORDER BY overdue_amount DESC, t.balance DESC`
+
+	rows, err := r.db.Instance.Query(query, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tabs := make([]aggregates.CustomerTab, 0, 10)
+	for rows.Next() {
+		tab, err := scanCustomerTab(rows)
+		if err != nil {
+			return nil, err
+		}
+		tabs = append(tabs, *tab)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tabs, nil
+}
+
+func (r *customerTabRepository) Create(tab *aggregates.CustomerTab) error {
+	query := `
+		INSERT INTO customer_tabs (
+			id, restaurant_id, customer_id, credit_limit, balance, due_day, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		tab.Id,
+		tab.Restaurant.Id,
+		tab.Customer.Id,
+		tab.CreditLimit,
+		tab.Balance,
+		tab.DueDay,
+		tab.CreatedAt,
+		tab.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, tab); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	tab.ClearAuditRecords()
+	return nil
+}
+
+func (r *customerTabRepository) Update(tab *aggregates.CustomerTab) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateCustomerTab(tx, tab); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, tab); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	tab.Version++
+	tab.ClearAuditRecords()
+	return nil
+}
+
+func (r *customerTabRepository) AddEntry(tab *aggregates.CustomerTab, entry *aggregates.TabEntry) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := addTabEntry(tx, tab, entry); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, tab); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	tab.Version++
+	tab.ClearAuditRecords()
+	return nil
+}
+
+// addTabEntry é o AddEntry sem a transação, para quem lança no fiado junto com outro agregado;
+// a versão só deve ser incrementada depois do commit
+func addTabEntry(tx *sql.Tx, tab *aggregates.CustomerTab, entry *aggregates.TabEntry) error {
+	query := `
+		INSERT INTO customer_tab_entries (
+			id, tab_id, type, amount, order_id, payment_method, description, due_date, created_by, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(
+		query,
+		entry.Id,
+		entry.TabId,
+		entry.Type,
+		entry.Amount,
+		nullString(entry.OrderId),
+		nullString(entry.PaymentMethod),
+		entry.Description,
+		entry.DueDate,
+		entry.CreatedBy,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return ports.ErrOrderAlreadyOnTab
+		}
+		return err
+	}
+
+	// o saldo é gravado com compare-and-swap: dois lançamentos simultâneos não se sobrescrevem
+	return updateCustomerTab(tx, tab)
+}
+
+func (r *customerTabRepository) FindEntries(tabId string, from, to *time.Time) ([]aggregates.TabEntry, error) {
+	conditions := []string{"e.tab_id = ?"}
+	params := []any{tabId}
+
+	if from != nil {
+		conditions = append(conditions, "e.created_at >= ?")
+		params = append(params, *from)
+	}
+	if to != nil {
+		conditions = append(conditions, "e.created_at < ?")
+		params = append(params, *to)
+	}
+
+	query := `
+		SELECT
+			` + customerTabEntryBaseFields + `
+		FROM customer_tab_entries e
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY e.created_at ASC`
+
+	rows, err := r.db.Instance.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]aggregates.TabEntry, 0, 10)
+	for rows.Next() {
+		var entry aggregates.TabEntry
+		var orderId, paymentMethod sql.NullString
+		var dueDate sql.NullTime
+		err := rows.Scan(
+			&entry.Id,
+			&entry.TabId,
+			&entry.Type,
+			&entry.Amount,
+			&orderId,
+			&paymentMethod,
+			&entry.Description,
+			&dueDate,
+			&entry.CreatedBy,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.OrderId = orderId.String
+		entry.PaymentMethod = paymentMethod.String
+		if dueDate.Valid {
+			entry.DueDate = &dueDate.Time
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func updateCustomerTab(db execer, tab *aggregates.CustomerTab) error {
+	query := `
+		UPDATE customer_tabs SET
+			credit_limit = ?,
+			balance = ?,
+			due_day = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	result, err := db.Exec(
+		query,
+		tab.CreditLimit,
+		tab.Balance,
+		tab.DueDay,
+		tab.UpdatedAt,
+		tab.Id,
+		tab.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkVersionedUpdate(result, aggregates.CustomerTabAggregateType, tab.Id, tab.Version)
+}
+
+func (r *customerTabRepository) findOne(query string, params ...any) (*aggregates.CustomerTab, error) {
+	tab, err := scanCustomerTab(r.db.Instance.QueryRow(query, params...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return tab, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCustomerTab(row rowScanner) (*aggregates.CustomerTab, error) {
+	var tab aggregates.CustomerTab
+	var email sql.NullString
+	err := row.Scan(
+		&tab.Id,
+		&tab.Restaurant.Id,
+		&tab.Customer.Id,
+		&tab.Customer.FirstName,
+		&tab.Customer.LastName,
+		&email,
+		&tab.CreditLimit,
+		&tab.Balance,
+		&tab.DueDay,
+		&tab.OverdueAmount,
+		&tab.CreatedAt,
+		&tab.UpdatedAt,
+		&tab.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tab.Customer.Email = email.String
+
+	return &tab, nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
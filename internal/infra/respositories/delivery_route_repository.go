@@ -0,0 +1,466 @@
+package respositories
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	deliveryroutestatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_route_status"
+	deliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/delivery_status"
+	"github.com/PedroNetto404/marmitech-backend/pkg/enums/uf"
+)
+
+const defaultDeliveryRouteQueryLimit = 50
+
+type deliveryRouteRepository struct {
+	db *database.Db
+}
+
+func NewDeliveryRouteRepository(db *database.Db) ports.IDeliveryRouteRepository {
+	return &deliveryRouteRepository{
+		db: db,
+	}
+}
+
+const (
+	deliveryRouteBaseFields = `
+		dr.id,
+		dr.restaurant_id,
+		dr.driver_id,
+		d.name,
+		dr.status,
+		dr.assigned_by,
+		dr.created_at,
+		dr.accepted_at,
+		dr.picked_up_at,
+		dr.completed_at,
+		dr.updated_at,
+		dr.version`
+)
+
+func (r *deliveryRouteRepository) FindById(id string) (*aggregates.DeliveryRoute, error) {
+	query := `
+		SELECT ` + deliveryRouteBaseFields + `
+		FROM delivery_routes dr
+		JOIN drivers d ON d.id = dr.driver_id
+		WHERE dr.id = ?`
+
+	route, err := scanDeliveryRoute(r.db.Instance.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := r.loadStops(route); err != nil {
+		return nil, err
+	}
+
+	return route, nil
+}
+
+func (r *deliveryRouteRepository) Find(query ports.DeliveryRouteQuery) ([]aggregates.DeliveryRoute, error) {
+	statement := `
+		SELECT ` + deliveryRouteBaseFields + `
+		FROM delivery_routes dr
+		JOIN drivers d ON d.id = dr.driver_id
+		WHERE dr.restaurant_id = ?`
+	params := []any{query.RestaurantId}
+
+	if query.DriverId != "" {
+		statement += ` AND dr.driver_id = ?`
+		params = append(params, query.DriverId)
+	}
+	if len(query.Statuses) > 0 {
+		statement += ` AND dr.status IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(query.Statuses)), ", ") + `)`
+		for _, status := range query.Statuses {
+			params = append(params, status)
+		}
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultDeliveryRouteQueryLimit
+	}
+	statement += ` ORDER BY dr.created_at DESC LIMIT ?`
+	params = append(params, limit)
+
+	return r.query(statement, params...)
+}
+
+func (r *deliveryRouteRepository) FindActiveByOrderIds(orderIds []string) ([]aggregates.DeliveryRoute, error) {
+	if len(orderIds) == 0 {
+		return []aggregates.DeliveryRoute{}, nil
+	}
+
+	statement := `
+		SELECT DISTINCT ` + deliveryRouteBaseFields + `
+		FROM delivery_routes dr
+		JOIN drivers d ON d.id = dr.driver_id
+		JOIN delivery_route_stops s ON s.route_id = dr.id
+		WHERE dr.status IN (?, ?, ?)
+		AND s.order_id IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(orderIds)), ", ") + `)`
+	params := []any{deliveryroutestatus.ASSIGNED, deliveryroutestatus.ACCEPTED, deliveryroutestatus.IN_TRANSIT}
+	for _, orderId := range orderIds {
+		params = append(params, orderId)
+	}
+
+	return r.query(statement, params...)
+}
+
+func (r *deliveryRouteRepository) Create(route *aggregates.DeliveryRoute, orders []*aggregates.Order) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockUndispatchedOrders(tx, route); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO delivery_routes (
+			id, restaurant_id, driver_id, status, assigned_by, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(
+		query,
+		route.Id,
+		route.Restaurant.Id,
+		route.Driver.Id,
+		route.Status,
+		route.AssignedBy,
+		route.CreatedAt,
+		route.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	stopQuery := `
+		INSERT INTO delivery_route_stops (
+			id, route_id, sequence, order_id, delivery_id, fee, status
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	for _, stop := range route.Stops {
+		_, err := tx.Exec(stopQuery, stop.Id, route.Id, stop.Sequence, stop.OrderId, stop.DeliveryId, stop.Fee, stop.Status)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := insertAuditRecords(tx, route); err != nil {
+		return err
+	}
+
+	if err := commitWithOrders(tx, orders); err != nil {
+		return err
+	}
+
+	route.ClearAuditRecords()
+	return nil
+}
+
+func (r *deliveryRouteRepository) Update(route *aggregates.DeliveryRoute, orders []*aggregates.Order) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE delivery_routes SET
+			status = ?,
+			accepted_at = ?,
+			picked_up_at = ?,
+			completed_at = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	result, err := tx.Exec(
+		query,
+		route.Status,
+		route.AcceptedAt,
+		route.PickedUpAt,
+		route.CompletedAt,
+		route.UpdatedAt,
+		route.Id,
+		route.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.DeliveryRouteAggregateType, route.Id, route.Version); err != nil {
+		return err
+	}
+
+	stopQuery := `
+		UPDATE delivery_route_stops SET
+			status = ?,
+			proof_photo_url = ?,
+			code_confirmed = ?,
+			delivered_at = ?
+		WHERE id = ? AND route_id = ?`
+
+	for _, stop := range route.Stops {
+		_, err := tx.Exec(
+			stopQuery,
+			stop.Status,
+			nullString(stop.ProofPhotoUrl),
+			stop.CodeConfirmed,
+			stop.DeliveredAt,
+			stop.Id,
+			route.Id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := insertAuditRecords(tx, route); err != nil {
+		return err
+	}
+
+	if err := commitWithOrders(tx, orders); err != nil {
+		return err
+	}
+
+	route.Version++
+	route.ClearAuditRecords()
+	return nil
+}
+
+// lockUndispatchedOrders trava os pedidos da rota até o commit e confere, já com a trava,
+// que nenhum deles entrou em outra rota em andamento desde a validação do caso de uso
+func lockUndispatchedOrders(tx *sql.Tx, route *aggregates.DeliveryRoute) error {
+	if len(route.Stops) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(route.Stops)), ", ")
+	orderIds := make([]any, 0, len(route.Stops))
+	for _, stop := range route.Stops {
+		orderIds = append(orderIds, stop.OrderId)
+	}
+
+	rows, err := tx.Query(`SELECT id FROM orders WHERE id IN (`+placeholders+`) FOR UPDATE`, orderIds...)
+	if err != nil {
+		return err
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	query := `
+		SELECT COUNT(*)
+		FROM delivery_route_stops s
+		JOIN delivery_routes dr ON dr.id = s.route_id
+		WHERE dr.status IN (?, ?, ?)
+		AND s.order_id IN (` + placeholders + `)`
+	params := append([]any{deliveryroutestatus.ASSIGNED, deliveryroutestatus.ACCEPTED, deliveryroutestatus.IN_TRANSIT}, orderIds...)
+
+	var dispatched int
+	if err := tx.QueryRow(query, params...).Scan(&dispatched); err != nil {
+		return err
+	}
+	if dispatched > 0 {
+		return ports.ErrOrderInActiveRoute
+	}
+
+	return nil
+}
+
+// commitWithOrders grava os pedidos tocados pela rota e confirma tudo junto
+func commitWithOrders(tx *sql.Tx, orders []*aggregates.Order) error {
+	for _, order := range orders {
+		if err := updateOrder(tx, order); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		order.Version++
+		order.ClearAuditRecords()
+	}
+	return nil
+}
+
+func (r *deliveryRouteRepository) Settlement(query ports.ReportQuery) ([]ports.DriverSettlement, error) {
+	statement := `
+		SELECT d.id, d.name, COUNT(s.id), COALESCE(SUM(s.fee), 0)
+		FROM delivery_route_stops s
+		JOIN delivery_routes dr ON dr.id = s.route_id
+		JOIN drivers d ON d.id = dr.driver_id
+		WHERE dr.restaurant_id = ?
+		AND s.status = ?
+		AND s.delivered_at >= ? AND s.delivered_at < ?
+		GROUP BY d.id, d.name
+		ORDER BY d.name ASC`
+
+	rows, err := r.db.Instance.Query(statement, query.RestaurantId, deliverystatus.DELIVERED, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settlements := make([]ports.DriverSettlement, 0)
+	for rows.Next() {
+		var settlement ports.DriverSettlement
+		if err := rows.Scan(&settlement.DriverId, &settlement.DriverName, &settlement.Deliveries, &settlement.Fees); err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	return settlements, rows.Err()
+}
+
+func (r *deliveryRouteRepository) query(statement string, params ...any) ([]aggregates.DeliveryRoute, error) {
+	rows, err := r.db.Instance.Query(statement, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	routes := make([]aggregates.DeliveryRoute, 0)
+	for rows.Next() {
+		route, err := scanDeliveryRoute(rows)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, *route)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range routes {
+		if err := r.loadStops(&routes[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return routes, nil
+}
+
+func (r *deliveryRouteRepository) loadStops(route *aggregates.DeliveryRoute) error {
+	query := `
+		SELECT
+			s.id,
+			s.sequence,
+			s.order_id,
+			s.delivery_id,
+			s.fee,
+			s.status,
+			s.proof_photo_url,
+			s.code_confirmed,
+			s.delivered_at,
+			` + AddressFields + `
+		FROM delivery_route_stops s
+		JOIN order_deliveries od ON od.id = s.delivery_id
+		JOIN addresses a ON a.id = od.address_id
+		WHERE s.route_id = ?
+		ORDER BY s.sequence ASC`
+
+	rows, err := r.db.Instance.Query(query, route.Id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	route.Stops = make([]aggregates.DeliveryStop, 0)
+	for rows.Next() {
+		var stop aggregates.DeliveryStop
+		var proofPhotoUrl, alias, complement, neighborhood, city, state, country, zipCode sql.NullString
+		var deliveredAt sql.NullTime
+		var lat, lng sql.NullFloat64
+		err := rows.Scan(
+			&stop.Id,
+			&stop.Sequence,
+			&stop.OrderId,
+			&stop.DeliveryId,
+			&stop.Fee,
+			&stop.Status,
+			&proofPhotoUrl,
+			&stop.CodeConfirmed,
+			&deliveredAt,
+			&stop.Address.Id,
+			&alias,
+			&stop.Address.Street,
+			&stop.Address.Number,
+			&complement,
+			&neighborhood,
+			&city,
+			&state,
+			&country,
+			&zipCode,
+			&lat,
+			&lng,
+		)
+		if err != nil {
+			return err
+		}
+
+		stop.ProofPhotoUrl = proofPhotoUrl.String
+		if deliveredAt.Valid {
+			stop.DeliveredAt = &deliveredAt.Time
+		}
+		stop.Address.Alias = alias.String
+		stop.Address.Complement = complement.String
+		stop.Address.Neighborhood = neighborhood.String
+		stop.Address.City = city.String
+		stop.Address.State = uf.UF(state.String)
+		stop.Address.Country = country.String
+		stop.Address.ZipCode = zipCode.String
+		stop.Address.Lat = lat.Float64
+		stop.Address.Lng = lng.Float64
+
+		route.Stops = append(route.Stops, stop)
+	}
+
+	return rows.Err()
+}
+
+func scanDeliveryRoute(row rowScanner) (*aggregates.DeliveryRoute, error) {
+	var route aggregates.DeliveryRoute
+	var acceptedAt, pickedUpAt, completedAt sql.NullTime
+	err := row.Scan(
+		&route.Id,
+		&route.Restaurant.Id,
+		&route.Driver.Id,
+		&route.Driver.Name,
+		&route.Status,
+		&route.AssignedBy,
+		&route.CreatedAt,
+		&acceptedAt,
+		&pickedUpAt,
+		&completedAt,
+		&route.UpdatedAt,
+		&route.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if acceptedAt.Valid {
+		route.AcceptedAt = &acceptedAt.Time
+	}
+	if pickedUpAt.Valid {
+		route.PickedUpAt = &pickedUpAt.Time
+	}
+	if completedAt.Valid {
+		route.CompletedAt = &completedAt.Time
+	}
+
+	return &route, nil
+}
@@ -0,0 +1,275 @@
+package respositories
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+type deliveryZoneRepository struct {
+	db *database.Db
+}
+
+func NewDeliveryZoneRepository(db *database.Db) ports.IDeliveryZoneRepository {
+	return &deliveryZoneRepository{
+		db: db,
+	}
+}
+
+const (
+	deliveryZoneBaseFields = `
+		id,
+		restaurant_id,
+		name,
+		kind,
+		polygon,
+		neighborhoods,
+		city,
+		fee,
+		minimum_order_value,
+		estimated_time_minutes,
+		priority,
+		active,
+		created_at,
+		updated_at,
+		version`
+)
+
+func (r *deliveryZoneRepository) FindById(id string) (*aggregates.DeliveryZone, error) {
+	query := `SELECT ` + deliveryZoneBaseFields + ` FROM delivery_zones WHERE id = ?`
+
+	zone, err := scanDeliveryZone(r.db.Instance.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+func (r *deliveryZoneRepository) FindByRestaurantId(restaurantId string) ([]aggregates.DeliveryZone, error) {
+	query := `
+		SELECT ` + deliveryZoneBaseFields + `
+		FROM delivery_zones
+		WHERE restaurant_id = ?
+		ORDER BY priority ASC, name ASC`
+
+	rows, err := r.db.Instance.Query(query, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	zones := make([]aggregates.DeliveryZone, 0)
+	for rows.Next() {
+		zone, err := scanDeliveryZone(rows)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, *zone)
+	}
+
+	return zones, rows.Err()
+}
+
+func (r *deliveryZoneRepository) Create(zone *aggregates.DeliveryZone) error {
+	polygon, neighborhoods, err := marshalDeliveryZoneArea(zone)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO delivery_zones (
+			id, restaurant_id, name, kind, polygon, neighborhoods, city, fee, minimum_order_value,
+			estimated_time_minutes, priority, active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		zone.Id,
+		zone.Restaurant.Id,
+		zone.Name,
+		zone.Kind,
+		polygon,
+		neighborhoods,
+		nullString(zone.City),
+		zone.Fee,
+		zone.MinimumOrderValue,
+		zone.EstimatedTimeMinutes,
+		zone.Priority,
+		zone.Active,
+		zone.CreatedAt,
+		zone.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, zone); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	zone.ClearAuditRecords()
+	return nil
+}
+
+func (r *deliveryZoneRepository) Update(zone *aggregates.DeliveryZone) error {
+	polygon, neighborhoods, err := marshalDeliveryZoneArea(zone)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE delivery_zones SET
+			name = ?,
+			kind = ?,
+			polygon = ?,
+			neighborhoods = ?,
+			city = ?,
+			fee = ?,
+			minimum_order_value = ?,
+			estimated_time_minutes = ?,
+			priority = ?,
+			active = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		zone.Name,
+		zone.Kind,
+		polygon,
+		neighborhoods,
+		nullString(zone.City),
+		zone.Fee,
+		zone.MinimumOrderValue,
+		zone.EstimatedTimeMinutes,
+		zone.Priority,
+		zone.Active,
+		zone.UpdatedAt,
+		zone.Id,
+		zone.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.DeliveryZoneAggregateType, zone.Id, zone.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, zone); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	zone.Version++
+	zone.ClearAuditRecords()
+	return nil
+}
+
+func (r *deliveryZoneRepository) Delete(zone *aggregates.DeliveryZone) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM delivery_zones WHERE id = ?`, zone.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, zone); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	zone.ClearAuditRecords()
+	return nil
+}
+
+// marshalDeliveryZoneArea grava NULL na coluna que o tipo da zona não usa
+func marshalDeliveryZoneArea(zone *aggregates.DeliveryZone) (polygon, neighborhoods []byte, err error) {
+	if zone.Polygon != nil {
+		polygon, err = json.Marshal(zone.Polygon)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(zone.Neighborhoods) > 0 {
+		neighborhoods, err = json.Marshal(zone.Neighborhoods)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return polygon, neighborhoods, nil
+}
+
+func scanDeliveryZone(row rowScanner) (*aggregates.DeliveryZone, error) {
+	var zone aggregates.DeliveryZone
+	var polygon, neighborhoods []byte
+	var city sql.NullString
+	err := row.Scan(
+		&zone.Id,
+		&zone.Restaurant.Id,
+		&zone.Name,
+		&zone.Kind,
+		&polygon,
+		&neighborhoods,
+		&city,
+		&zone.Fee,
+		&zone.MinimumOrderValue,
+		&zone.EstimatedTimeMinutes,
+		&zone.Priority,
+		&zone.Active,
+		&zone.CreatedAt,
+		&zone.UpdatedAt,
+		&zone.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(polygon) > 0 {
+		if err := json.Unmarshal(polygon, &zone.Polygon); err != nil {
+			return nil, err
+		}
+	}
+	if len(neighborhoods) > 0 {
+		if err := json.Unmarshal(neighborhoods, &zone.Neighborhoods); err != nil {
+			return nil, err
+		}
+	}
+	zone.City = city.String
+
+	return &zone, nil
+}
@@ -24,7 +24,8 @@ const (
 		d.name,
 		d.type,
 		d.picture_url,
-		d.restaurant_id`
+		d.restaurant_id,
+		d.version`
 )
 
 func (r *dishRepository) Find(args types.FindArgs) (*types.PagedSlice[aggregates.Dish], error) {
@@ -56,6 +57,7 @@ func (r *dishRepository) Find(args types.FindArgs) (*types.PagedSlice[aggregates
 			&dish.Type,
 			&dish.PictureUrl,
 			&dish.Restaurant.Id,
+			&dish.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -85,6 +87,7 @@ func (r *dishRepository) FindById(id string) (*aggregates.Dish, error) {
 		&dish.Type,
 		&dish.PictureUrl,
 		&dish.Restaurant.Id,
+		&dish.Version,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -102,7 +105,13 @@ func (r *dishRepository) Create(dish *aggregates.Dish) error {
 			id, name, type, picture_url, restaurant_id
 		) VALUES (?, ?, ?, ?, ?)`
 
-	_, err := r.db.Instance.Exec(
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
 		query,
 		dish.Id,
 		dish.Name,
@@ -110,7 +119,20 @@ func (r *dishRepository) Create(dish *aggregates.Dish) error {
 		dish.PictureUrl,
 		dish.Restaurant.Id,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, dish); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	dish.ClearAuditRecords()
+	return nil
 }
 
 func (r *dishRepository) Update(dish *aggregates.Dish) error {
@@ -118,26 +140,72 @@ func (r *dishRepository) Update(dish *aggregates.Dish) error {
 		UPDATE dishes SET
 			name = ?,
 			type = ?,
-			picture_url = ?
-		WHERE id = ? AND deleted_at IS NULL`
+			picture_url = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	_, err := r.db.Instance.Exec(
+	result, err := tx.Exec(
 		query,
 		dish.Name,
 		dish.Type,
 		dish.PictureUrl,
 		dish.Id,
+		dish.Version,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.DishAggregateType, dish.Id, dish.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, dish); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	dish.Version++
+	dish.ClearAuditRecords()
+	return nil
 }
 
-func (r *dishRepository) Delete(id string) error {
+func (r *dishRepository) Delete(dish *aggregates.Dish) error {
 	query := `
 		UPDATE dishes 
 		SET deleted_at = NOW() 
 		WHERE id = ? AND deleted_at IS NULL`
-	_, err := r.db.Instance.Exec(query, id)
-	return err
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query, dish.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, dish); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	dish.ClearAuditRecords()
+	return nil
 }
 
 func (r *dishRepository) FindByRestaurantId(restaurantId string) ([]aggregates.Dish, error) {
@@ -162,6 +230,7 @@ func (r *dishRepository) FindByRestaurantId(restaurantId string) ([]aggregates.D
 			&dish.Type,
 			&dish.PictureUrl,
 			&dish.Restaurant.Id,
+			&dish.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,317 @@
+package respositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+type driverRepository struct {
+	db *database.Db
+}
+
+func NewDriverRepository(db *database.Db) ports.IDriverRepository {
+	return &driverRepository{
+		db: db,
+	}
+}
+
+const (
+	driverBaseFields = `
+		id,
+		restaurant_id,
+		name,
+		kind,
+		phone,
+		email,
+		vehicle,
+		active,
+		created_at,
+		updated_at,
+		version`
+)
+
+func (r *driverRepository) FindById(id string) (*aggregates.Driver, error) {
+	query := `SELECT ` + driverBaseFields + ` FROM drivers WHERE id = ?`
+
+	return r.findOne(query, id)
+}
+
+func (r *driverRepository) FindByEmail(restaurantId, email string) (*aggregates.Driver, error) {
+	query := `SELECT ` + driverBaseFields + ` FROM drivers WHERE restaurant_id = ? AND email = ?`
+
+	return r.findOne(query, restaurantId, email)
+}
+
+func (r *driverRepository) FindByRestaurantId(restaurantId string) ([]aggregates.Driver, error) {
+	query := `
+		SELECT ` + driverBaseFields + `
+		FROM drivers
+		WHERE restaurant_id = ?
+		ORDER BY name ASC`
+
+	rows, err := r.db.Instance.Query(query, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drivers := make([]aggregates.Driver, 0)
+	for rows.Next() {
+		driver, err := scanDriver(rows)
+		if err != nil {
+			return nil, err
+		}
+		drivers = append(drivers, *driver)
+	}
+
+	return drivers, rows.Err()
+}
+
+func (r *driverRepository) Create(driver *aggregates.Driver) error {
+	query := `
+		INSERT INTO drivers (
+			id, restaurant_id, name, kind, phone, email, vehicle, active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		driver.Id,
+		driver.Restaurant.Id,
+		driver.Name,
+		driver.Kind,
+		nullString(driver.Phone),
+		nullString(driver.Email),
+		nullString(driver.Vehicle),
+		driver.Active,
+		driver.CreatedAt,
+		driver.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, driver); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	driver.ClearAuditRecords()
+	return nil
+}
+
+func (r *driverRepository) Update(driver *aggregates.Driver) error {
+	query := `
+		UPDATE drivers SET
+			name = ?,
+			kind = ?,
+			phone = ?,
+			email = ?,
+			vehicle = ?,
+			active = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		driver.Name,
+		driver.Kind,
+		nullString(driver.Phone),
+		nullString(driver.Email),
+		nullString(driver.Vehicle),
+		driver.Active,
+		driver.UpdatedAt,
+		driver.Id,
+		driver.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.DriverAggregateType, driver.Id, driver.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, driver); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	driver.Version++
+	driver.ClearAuditRecords()
+	return nil
+}
+
+func (r *driverRepository) Delete(driver *aggregates.Driver) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM drivers WHERE id = ?`, driver.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, driver); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	driver.ClearAuditRecords()
+	return nil
+}
+
+func (r *driverRepository) findOne(query string, params ...any) (*aggregates.Driver, error) {
+	driver, err := scanDriver(r.db.Instance.QueryRow(query, params...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return driver, nil
+}
+
+func scanDriver(row rowScanner) (*aggregates.Driver, error) {
+	var driver aggregates.Driver
+	var phone, email, vehicle sql.NullString
+	err := row.Scan(
+		&driver.Id,
+		&driver.Restaurant.Id,
+		&driver.Name,
+		&driver.Kind,
+		&phone,
+		&email,
+		&vehicle,
+		&driver.Active,
+		&driver.CreatedAt,
+		&driver.UpdatedAt,
+		&driver.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	driver.Phone = phone.String
+	driver.Email = email.String
+	driver.Vehicle = vehicle.String
+
+	return &driver, nil
+}
+
+type driverLocationRepository struct {
+	db *database.Db
+}
+
+func NewDriverLocationRepository(db *database.Db) ports.IDriverLocationRepository {
+	return &driverLocationRepository{
+		db: db,
+	}
+}
+
+func (r *driverLocationRepository) Save(location *aggregates.DriverLocation) error {
+	query := `
+		INSERT INTO driver_locations (
+			driver_id, route_id, lat, lng, recorded_at
+		) VALUES (?, ?, ?, ?, ?)`
+
+	_, err := r.db.Instance.Exec(
+		query,
+		location.DriverId,
+		nullString(location.RouteId),
+		location.Lat,
+		location.Lng,
+		location.RecordedAt,
+	)
+	return err
+}
+
+func (r *driverLocationRepository) FindLatest(driverId string) (*aggregates.DriverLocation, error) {
+	query := `
+		SELECT driver_id, route_id, lat, lng, recorded_at
+		FROM driver_locations
+		WHERE driver_id = ?
+		ORDER BY recorded_at DESC
+		LIMIT 1`
+
+	location, err := scanDriverLocation(r.db.Instance.QueryRow(query, driverId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return location, nil
+}
+
+func (r *driverLocationRepository) FindByRoute(routeId string, since time.Time) ([]aggregates.DriverLocation, error) {
+	query := `
+		SELECT driver_id, route_id, lat, lng, recorded_at
+		FROM driver_locations
+		WHERE route_id = ? AND recorded_at >= ?
+		ORDER BY recorded_at ASC`
+
+	rows, err := r.db.Instance.Query(query, routeId, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := make([]aggregates.DriverLocation, 0)
+	for rows.Next() {
+		location, err := scanDriverLocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, *location)
+	}
+
+	return locations, rows.Err()
+}
+
+func scanDriverLocation(row rowScanner) (*aggregates.DriverLocation, error) {
+	var location aggregates.DriverLocation
+	var routeId sql.NullString
+	err := row.Scan(
+		&location.DriverId,
+		&routeId,
+		&location.Lat,
+		&location.Lng,
+		&location.RecordedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	location.RouteId = routeId.String
+	return &location, nil
+}
@@ -0,0 +1,143 @@
+package respositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/abstractions"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+type eventOutboxRepository struct {
+	db *database.Db
+}
+
+func NewEventOutboxRepository(db *database.Db) ports.IEventOutboxRepository {
+	return &eventOutboxRepository{
+		db: db,
+	}
+}
+
+func (r *eventOutboxRepository) FindAfter(sequence int64, limit int) ([]ports.OutboxEvent, error) {
+	query := `
+		SELECT id, event_id, name, aggregate_id, occurred_at, recorded_at
+		FROM event_outbox
+		WHERE id > ?
+		ORDER BY id ASC
+		LIMIT ?`
+
+	rows, err := r.db.Instance.Query(query, sequence, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]ports.OutboxEvent, 0)
+	for rows.Next() {
+		var event ports.OutboxEvent
+		err := rows.Scan(
+			&event.Sequence,
+			&event.Event.Id,
+			&event.Event.Name,
+			&event.Event.AggregateId,
+			&event.Event.OccuredAt,
+			&event.RecordedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *eventOutboxRepository) LastSequence() (int64, error) {
+	var sequence int64
+	err := r.db.Instance.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM event_outbox`).Scan(&sequence)
+	return sequence, err
+}
+
+func (r *eventOutboxRepository) FindCursor(consumer string) (int64, bool, error) {
+	var sequence int64
+	err := r.db.Instance.QueryRow(`SELECT last_event_id FROM event_consumers WHERE name = ?`, consumer).Scan(&sequence)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	return sequence, true, nil
+}
+
+func (r *eventOutboxRepository) SaveCursor(consumer string, sequence int64) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO event_consumers (name, last_event_id, updated_at)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE last_event_id = VALUES(last_event_id), updated_at = VALUES(updated_at)`
+
+	_, err = tx.Exec(query, consumer, sequence, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM event_deliveries WHERE consumer = ? AND event_id <= ?`, consumer, sequence)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *eventOutboxRepository) FindDelivered(consumer string, after int64) ([]int64, error) {
+	rows, err := r.db.Instance.Query(`SELECT event_id FROM event_deliveries WHERE consumer = ? AND event_id > ?`, consumer, after)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	delivered := make([]int64, 0)
+	for rows.Next() {
+		var sequence int64
+		if err := rows.Scan(&sequence); err != nil {
+			return nil, err
+		}
+		delivered = append(delivered, sequence)
+	}
+
+	return delivered, rows.Err()
+}
+
+func (r *eventOutboxRepository) MarkDelivered(consumer string, sequence int64) error {
+	query := `
+		INSERT INTO event_deliveries (consumer, event_id, delivered_at)
+		VALUES (?, ?, ?)`
+
+	_, err := r.db.Instance.Exec(query, consumer, sequence, time.Now())
+	return err
+}
+
+// insertDomainEvents grava na outbox, dentro da transação do agregado, os eventos que ele levantou
+func insertDomainEvents(tx *sql.Tx, aggregate abstractions.IAggreagateRoot) error {
+	query := `
+		INSERT INTO event_outbox (event_id, name, aggregate_id, occurred_at, recorded_at)
+		VALUES (?, ?, ?, ?, ?)`
+
+	now := time.Now()
+	for _, event := range aggregate.DomainEvents() {
+		_, err := tx.Exec(query, event.Id, event.Name, event.AggregateId, event.OccuredAt, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package respositories
+
+import (
+	"database/sql"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+type fiscalDocumentRepository struct {
+	db *database.Db
+}
+
+func NewFiscalDocumentRepository(db *database.Db) ports.IFiscalDocumentRepository {
+	return &fiscalDocumentRepository{
+		db: db,
+	}
+}
+
+const (
+	fiscalDocumentBaseFields = `
+		fd.id,
+		fd.restaurant_id,
+		fd.order_id,
+		fd.model,
+		fd.series,
+		fd.number,
+		fd.environment,
+		fd.access_key,
+		fd.status,
+		fd.status_code,
+		fd.status_reason,
+		fd.protocol,
+		fd.qr_code_url,
+		fd.xml,
+		fd.issued_at,
+		fd.authorized_at,
+		fd.updated_at,
+		fd.version`
+)
+
+func (r *fiscalDocumentRepository) FindByOrderId(orderId string) (*aggregates.FiscalDocument, error) {
+	query := `
+		SELECT
+			` + fiscalDocumentBaseFields + `
+		FROM fiscal_documents fd
+		WHERE fd.order_id = ?`
+
+	var document aggregates.FiscalDocument
+	var accessKey, statusCode, statusReason, protocol, qrCodeUrl, xml sql.NullString
+	var authorizedAt sql.NullTime
+	err := r.db.Instance.QueryRow(query, orderId).Scan(
+		&document.Id,
+		&document.Restaurant.Id,
+		&document.OrderId,
+		&document.Model,
+		&document.Series,
+		&document.Number,
+		&document.Environment,
+		&accessKey,
+		&document.Status,
+		&statusCode,
+		&statusReason,
+		&protocol,
+		&qrCodeUrl,
+		&xml,
+		&document.IssuedAt,
+		&authorizedAt,
+		&document.UpdatedAt,
+		&document.Version,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	document.AccessKey = accessKey.String
+	document.StatusCode = statusCode.String
+	document.StatusReason = statusReason.String
+	document.Protocol = protocol.String
+	document.QrCodeUrl = qrCodeUrl.String
+	document.Xml = xml.String
+	if authorizedAt.Valid {
+		document.AuthorizedAt = &authorizedAt.Time
+	}
+
+	return &document, nil
+}
+
+func (r *fiscalDocumentRepository) Create(document *aggregates.FiscalDocument) error {
+	query := `
+		INSERT INTO fiscal_documents (
+			id, restaurant_id, order_id, model, series, number, environment, status, issued_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Instance.Exec(
+		query,
+		document.Id,
+		document.Restaurant.Id,
+		document.OrderId,
+		document.Model,
+		document.Series,
+		document.Number,
+		document.Environment,
+		document.Status,
+		document.IssuedAt,
+		document.UpdatedAt,
+	)
+	return err
+}
+
+func (r *fiscalDocumentRepository) Update(document *aggregates.FiscalDocument) error {
+	query := `
+		UPDATE fiscal_documents SET
+			access_key = ?,
+			status = ?,
+			status_code = ?,
+			status_reason = ?,
+			protocol = ?,
+			qr_code_url = ?,
+			xml = ?,
+			authorized_at = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		nullString(document.AccessKey),
+		document.Status,
+		nullString(document.StatusCode),
+		nullString(document.StatusReason),
+		nullString(document.Protocol),
+		nullString(document.QrCodeUrl),
+		nullString(document.Xml),
+		document.AuthorizedAt,
+		document.UpdatedAt,
+		document.Id,
+		document.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.FiscalDocumentAggregateType, document.Id, document.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, document); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	document.Version++
+	document.ClearAuditRecords()
+	return nil
+}
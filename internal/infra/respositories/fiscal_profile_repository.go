@@ -0,0 +1,280 @@
+package respositories
+
+import (
+	"database/sql"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+type fiscalProfileRepository struct {
+	db     *database.Db
+	cipher ports.ISecretCipher
+}
+
+// a senha do certificado A1 e o token CSC só chegam ao banco cifrados
+func NewFiscalProfileRepository(db *database.Db, cipher ports.ISecretCipher) ports.IFiscalProfileRepository {
+	return &fiscalProfileRepository{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+const (
+	fiscalProfileBaseFields = `
+		fp.id,
+		fp.restaurant_id,
+		fp.tax_regime,
+		fp.state_registration,
+		fp.city_code,
+		fp.environment,
+		fp.series,
+		fp.next_number,
+		fp.csc_id,
+		fp.csc_token,
+		fp.certificate_password,
+		fp.certificate_subject,
+		fp.certificate_expires_at,
+		fp.created_at,
+		fp.updated_at,
+		fp.version`
+)
+
+func (r *fiscalProfileRepository) FindByRestaurantId(restaurantId string) (*aggregates.FiscalProfile, error) {
+	query := `
+		SELECT
+			` + fiscalProfileBaseFields + `
+		FROM fiscal_profiles fp
+		WHERE fp.restaurant_id = ?`
+
+	var profile aggregates.FiscalProfile
+	var password, subject sql.NullString
+	var expiresAt sql.NullTime
+	err := r.db.Instance.QueryRow(query, restaurantId).Scan(
+		&profile.Id,
+		&profile.Restaurant.Id,
+		&profile.TaxRegime,
+		&profile.StateRegistration,
+		&profile.CityCode,
+		&profile.Environment,
+		&profile.Series,
+		&profile.NextNumber,
+		&profile.CscId,
+		&profile.CscToken,
+		&password,
+		&subject,
+		&expiresAt,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+		&profile.Version,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	profile.CertificatePassword, err = r.cipher.Decrypt(password.String)
+	if err != nil {
+		return nil, err
+	}
+	profile.CscToken, err = r.cipher.Decrypt(profile.CscToken)
+	if err != nil {
+		return nil, err
+	}
+	profile.CertificateSubject = subject.String
+	if expiresAt.Valid {
+		profile.CertificateExpiresAt = &expiresAt.Time
+	}
+
+	return &profile, nil
+}
+
+func (r *fiscalProfileRepository) Create(profile *aggregates.FiscalProfile) error {
+	query := `
+		INSERT INTO fiscal_profiles (
+			id, restaurant_id, tax_regime, state_registration, city_code, environment, series, next_number,
+			csc_id, csc_token, certificate_password, certificate_subject, certificate_expires_at, secrets_encrypted,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, TRUE, ?, ?)`
+
+	cscToken, password, err := r.encryptSecrets(profile)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		profile.Id,
+		profile.Restaurant.Id,
+		profile.TaxRegime,
+		profile.StateRegistration,
+		profile.CityCode,
+		profile.Environment,
+		profile.Series,
+		profile.NextNumber,
+		profile.CscId,
+		cscToken,
+		nullString(password),
+		nullString(profile.CertificateSubject),
+		profile.CertificateExpiresAt,
+		profile.CreatedAt,
+		profile.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, profile); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	profile.ClearAuditRecords()
+	return nil
+}
+
+func (r *fiscalProfileRepository) Update(profile *aggregates.FiscalProfile) error {
+	query := `
+		UPDATE fiscal_profiles SET
+			tax_regime = ?,
+			state_registration = ?,
+			city_code = ?,
+			environment = ?,
+			series = ?,
+			next_number = ?,
+			csc_id = ?,
+			csc_token = ?,
+			certificate_password = ?,
+			certificate_subject = ?,
+			certificate_expires_at = ?,
+			secrets_encrypted = TRUE,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	cscToken, password, err := r.encryptSecrets(profile)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		profile.TaxRegime,
+		profile.StateRegistration,
+		profile.CityCode,
+		profile.Environment,
+		profile.Series,
+		profile.NextNumber,
+		profile.CscId,
+		cscToken,
+		nullString(password),
+		nullString(profile.CertificateSubject),
+		profile.CertificateExpiresAt,
+		profile.UpdatedAt,
+		profile.Id,
+		profile.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.FiscalProfileAggregateType, profile.Id, profile.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, profile); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	profile.Version++
+	profile.ClearAuditRecords()
+	return nil
+}
+
+func (r *fiscalProfileRepository) encryptSecrets(profile *aggregates.FiscalProfile) (string, string, error) {
+	cscToken, err := r.cipher.Encrypt(profile.CscToken)
+	if err != nil {
+		return "", "", err
+	}
+
+	password, err := r.cipher.Encrypt(profile.CertificatePassword)
+	if err != nil {
+		return "", "", err
+	}
+
+	return cscToken, password, nil
+}
+
+// EncryptLegacySecrets cifra os perfis ainda marcados com secrets_encrypted = FALSE; a versão
+// não muda porque o valor lido continua o mesmo
+func (r *fiscalProfileRepository) EncryptLegacySecrets() (int, error) {
+	rows, err := r.db.Instance.Query(`
+		SELECT id, csc_token, certificate_password
+		FROM fiscal_profiles
+		WHERE secrets_encrypted = FALSE`)
+	if err != nil {
+		return 0, err
+	}
+
+	var legacy []aggregates.FiscalProfile
+	for rows.Next() {
+		var profile aggregates.FiscalProfile
+		var password sql.NullString
+		if err := rows.Scan(&profile.Id, &profile.CscToken, &password); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		profile.CertificatePassword = password.String
+		legacy = append(legacy, profile)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	encrypted := 0
+	for _, profile := range legacy {
+		cscToken, password, err := r.encryptSecrets(&profile)
+		if err != nil {
+			return encrypted, err
+		}
+
+		// a marca na condição impede cifrar duas vezes se o comando rodar de novo em paralelo
+		result, err := r.db.Instance.Exec(
+			`UPDATE fiscal_profiles
+			SET csc_token = ?, certificate_password = ?, secrets_encrypted = TRUE
+			WHERE id = ? AND secrets_encrypted = FALSE`,
+			cscToken, nullString(password), profile.Id,
+		)
+		if err != nil {
+			return encrypted, err
+		}
+		if affected, _ := result.RowsAffected(); affected > 0 {
+			encrypted++
+		}
+	}
+
+	return encrypted, nil
+}
@@ -0,0 +1,47 @@
+package respositories
+
+import (
+	"database/sql"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+)
+
+type geocodeCacheRepository struct {
+	db *database.Db
+}
+
+func NewGeocodeCacheRepository(db *database.Db) ports.IGeocodeCacheRepository {
+	return &geocodeCacheRepository{
+		db: db,
+	}
+}
+
+func (r *geocodeCacheRepository) Find(key string) (*types.GeoPoint, error) {
+	query := `
+		SELECT lat, lng
+		FROM geocode_cache
+		WHERE address_hash = SHA2(?, 256)`
+
+	var point types.GeoPoint
+	err := r.db.Instance.QueryRow(query, key).Scan(&point.Lat, &point.Lng)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &point, nil
+}
+
+func (r *geocodeCacheRepository) Save(key string, point types.GeoPoint) error {
+	query := `
+		INSERT INTO geocode_cache (address_hash, address_key, lat, lng)
+		VALUES (SHA2(?, 256), ?, ?, ?)
+		ON DUPLICATE KEY UPDATE lat = VALUES(lat), lng = VALUES(lng)`
+
+	_, err := r.db.Instance.Exec(query, key, key, point.Lat, point.Lng)
+	return err
+}
@@ -0,0 +1,121 @@
+package respositories
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	"github.com/go-sql-driver/mysql"
+)
+
+const mysqlDuplicateEntry = 1062
+
+type idempotencyRepository struct {
+	db *database.Db
+}
+
+func NewIdempotencyRepository(db *database.Db) ports.IIdempotencyRepository {
+	return &idempotencyRepository{
+		db: db,
+	}
+}
+
+func (r *idempotencyRepository) Reserve(record *ports.IdempotencyRecord) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (
+			restaurant_id, principal, idempotency_key, fingerprint, status_code, content_type, body, created_at, expires_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Instance.Exec(
+		query,
+		record.Scope.RestaurantId,
+		record.Scope.Principal,
+		record.Scope.Key,
+		record.Fingerprint,
+		record.StatusCode,
+		record.ContentType,
+		record.Body,
+		record.CreatedAt,
+		record.ExpiresAt,
+	)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *idempotencyRepository) Find(scope ports.IdempotencyScope) (*ports.IdempotencyRecord, error) {
+	query := `
+		SELECT restaurant_id, principal, idempotency_key, fingerprint, status_code, content_type, body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE restaurant_id = ? AND principal = ? AND idempotency_key = ?`
+
+	var record ports.IdempotencyRecord
+	err := r.db.Instance.QueryRow(query, scope.RestaurantId, scope.Principal, scope.Key).Scan(
+		&record.Scope.RestaurantId,
+		&record.Scope.Principal,
+		&record.Scope.Key,
+		&record.Fingerprint,
+		&record.StatusCode,
+		&record.ContentType,
+		&record.Body,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *idempotencyRepository) Complete(record *ports.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys SET
+			status_code = ?,
+			content_type = ?,
+			body = ?
+		WHERE restaurant_id = ? AND principal = ? AND idempotency_key = ?`
+
+	_, err := r.db.Instance.Exec(
+		query,
+		record.StatusCode,
+		record.ContentType,
+		record.Body,
+		record.Scope.RestaurantId,
+		record.Scope.Principal,
+		record.Scope.Key,
+	)
+	return err
+}
+
+func (r *idempotencyRepository) Delete(scope ports.IdempotencyScope) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE restaurant_id = ? AND principal = ? AND idempotency_key = ?`
+	_, err := r.db.Instance.Exec(query, scope.RestaurantId, scope.Principal, scope.Key)
+	return err
+}
+
+func (r *idempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE expires_at < ?`
+
+	result, err := r.db.Instance.Exec(query, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
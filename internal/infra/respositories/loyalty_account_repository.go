@@ -0,0 +1,234 @@
+package respositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+)
+
+type loyaltyAccountRepository struct {
+	db *database.Db
+}
+
+func NewLoyaltyAccountRepository(db *database.Db) ports.ILoyaltyAccountRepository {
+	return &loyaltyAccountRepository{
+		db: db,
+	}
+}
+
+func (r *loyaltyAccountRepository) FindByCustomerId(restaurantId, customerId string) (*aggregates.LoyaltyAccount, error) {
+	query := `
+		SELECT
+			la.id,
+			la.restaurant_id,
+			la.customer_id,
+			c.first_name,
+			c.last_name,
+			c.contact_email,
+			la.balance,
+			la.created_at,
+			la.updated_at,
+			la.version
+		FROM loyalty_accounts la
+		JOIN customers c ON la.customer_id = c.id
+		WHERE la.restaurant_id = ? AND la.customer_id = ?`
+
+	var account aggregates.LoyaltyAccount
+	var email sql.NullString
+	err := r.db.Instance.QueryRow(query, restaurantId, customerId).Scan(
+		&account.Id,
+		&account.Restaurant.Id,
+		&account.Customer.Id,
+		&account.Customer.FirstName,
+		&account.Customer.LastName,
+		&email,
+		&account.Balance,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+		&account.Version,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	account.Customer.Email = email.String
+
+	return &account, nil
+}
+
+func (r *loyaltyAccountRepository) Create(account *aggregates.LoyaltyAccount) error {
+	query := `
+		INSERT INTO loyalty_accounts (
+			id, restaurant_id, customer_id, balance, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Instance.Exec(
+		query,
+		account.Id,
+		account.Restaurant.Id,
+		account.Customer.Id,
+		account.Balance,
+		account.CreatedAt,
+		account.UpdatedAt,
+	)
+	return err
+}
+
+func (r *loyaltyAccountRepository) AddEntries(account *aggregates.LoyaltyAccount, entries ...*aggregates.LoyaltyEntry) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := addLoyaltyEntries(tx, account, entries...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	account.Version++
+	return nil
+}
+
+// addLoyaltyEntries é o AddEntries sem a transação, para o cancelamento do pedido estornar os pontos
+// junto; a versão só deve ser incrementada depois do commit
+func addLoyaltyEntries(tx *sql.Tx, account *aggregates.LoyaltyAccount, entries ...*aggregates.LoyaltyEntry) error {
+	query := `
+		INSERT INTO loyalty_entries (
+			id, account_id, type, points, order_id, description, expires_at, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for _, entry := range entries {
+		_, err := tx.Exec(
+			query,
+			entry.Id,
+			entry.AccountId,
+			entry.Type,
+			entry.Points,
+			nullString(entry.OrderId),
+			entry.Description,
+			entry.ExpiresAt,
+			entry.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// o saldo é gravado com compare-and-swap, como no fiado
+	result, err := tx.Exec(
+		`UPDATE loyalty_accounts SET balance = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ?`,
+		account.Balance,
+		account.UpdatedAt,
+		account.Id,
+		account.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkVersionedUpdate(result, aggregates.LoyaltyAccountAggregateType, account.Id, account.Version)
+}
+
+func (r *loyaltyAccountRepository) FindEntries(accountId string) ([]aggregates.LoyaltyEntry, error) {
+	query := `
+		SELECT
+			e.id,
+			e.account_id,
+			e.type,
+			e.points,
+			e.order_id,
+			e.description,
+			e.expires_at,
+			e.created_at
+		FROM loyalty_entries e
+		WHERE e.account_id = ?
+		ORDER BY e.created_at ASC`
+
+	rows, err := r.db.Instance.Query(query, accountId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]aggregates.LoyaltyEntry, 0, 10)
+	for rows.Next() {
+		var entry aggregates.LoyaltyEntry
+		var orderId sql.NullString
+		var expiresAt sql.NullTime
+		err := rows.Scan(
+			&entry.Id,
+			&entry.AccountId,
+			&entry.Type,
+			&entry.Points,
+			&orderId,
+			&entry.Description,
+			&expiresAt,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.OrderId = orderId.String
+		if expiresAt.Valid {
+			entry.ExpiresAt = &expiresAt.Time
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *loyaltyAccountRepository) FindOrdersPendingEarn(from, to time.Time, afterId string, limit int) ([]string, error) {
+	query := `
+		SELECT o.id
+		FROM orders o
+		JOIN loyalty_programs lp ON lp.restaurant_id = o.restaurant_id AND lp.active = TRUE
+		WHERE o.created_at >= ? AND o.created_at < ?
+		AND o.id > ?
+		AND o.status = ?
+		AND o.customer_id IS NOT NULL
+		AND o.deleted_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1
+			FROM loyalty_entries le
+			JOIN loyalty_accounts la ON la.id = le.account_id
+			WHERE la.restaurant_id = o.restaurant_id
+			AND le.order_id = o.id
+			AND le.type = ?
+		)
+		ORDER BY o.id ASC
+		LIMIT ?`
+
+	rows, err := r.db.Instance.Query(query, from, to, afterId, orderstatus.COMPLETED, aggregates.LoyaltyEntryEarn, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orderIds := make([]string, 0)
+	for rows.Next() {
+		var orderId string
+		if err := rows.Scan(&orderId); err != nil {
+			return nil, err
+		}
+		orderIds = append(orderIds, orderId)
+	}
+
+	return orderIds, rows.Err()
+}
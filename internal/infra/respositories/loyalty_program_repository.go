@@ -0,0 +1,218 @@
+package respositories
+
+import (
+	"database/sql"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	"github.com/google/uuid"
+)
+
+type loyaltyProgramRepository struct {
+	db *database.Db
+}
+
+func NewLoyaltyProgramRepository(db *database.Db) ports.ILoyaltyProgramRepository {
+	return &loyaltyProgramRepository{
+		db: db,
+	}
+}
+
+func (r *loyaltyProgramRepository) FindByRestaurantId(restaurantId string) (*aggregates.LoyaltyProgram, error) {
+	query := `
+		SELECT
+			lp.id,
+			lp.restaurant_id,
+			lp.name,
+			lp.mode,
+			lp.reward_threshold,
+			lp.reward_product_id,
+			lp.reward_value,
+			lp.expiration_days,
+			lp.active,
+			lp.created_at,
+			lp.updated_at,
+			lp.version
+		FROM loyalty_programs lp
+		WHERE lp.restaurant_id = ?`
+
+	var program aggregates.LoyaltyProgram
+	var rewardProductId sql.NullString
+	err := r.db.Instance.QueryRow(query, restaurantId).Scan(
+		&program.Id,
+		&program.Restaurant.Id,
+		&program.Name,
+		&program.Mode,
+		&program.RewardThreshold,
+		&rewardProductId,
+		&program.RewardValue,
+		&program.ExpirationDays,
+		&program.Active,
+		&program.CreatedAt,
+		&program.UpdatedAt,
+		&program.Version,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	program.RewardProductId = rewardProductId.String
+
+	rows, err := r.db.Instance.Query(`SELECT product_id, category_id, points FROM loyalty_earn_rules WHERE program_id = ?`, program.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	program.EarnRules = make([]aggregates.LoyaltyEarnRule, 0)
+	for rows.Next() {
+		var rule aggregates.LoyaltyEarnRule
+		var productId, categoryId sql.NullString
+		if err := rows.Scan(&productId, &categoryId, &rule.Points); err != nil {
+			return nil, err
+		}
+		rule.ProductId = productId.String
+		rule.CategoryId = categoryId.String
+		program.EarnRules = append(program.EarnRules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &program, nil
+}
+
+func (r *loyaltyProgramRepository) Create(program *aggregates.LoyaltyProgram) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO loyalty_programs (
+			id, restaurant_id, name, mode, reward_threshold, reward_product_id, reward_value,
+			expiration_days, active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(
+		query,
+		program.Id,
+		program.Restaurant.Id,
+		program.Name,
+		program.Mode,
+		program.RewardThreshold,
+		nullString(program.RewardProductId),
+		program.RewardValue,
+		program.ExpirationDays,
+		program.Active,
+		program.CreatedAt,
+		program.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := createLoyaltyEarnRules(tx, program); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, program); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	program.ClearAuditRecords()
+	return nil
+}
+
+func (r *loyaltyProgramRepository) Update(program *aggregates.LoyaltyProgram) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE loyalty_programs SET
+			name = ?,
+			mode = ?,
+			reward_threshold = ?,
+			reward_product_id = ?,
+			reward_value = ?,
+			expiration_days = ?,
+			active = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	result, err := tx.Exec(
+		query,
+		program.Name,
+		program.Mode,
+		program.RewardThreshold,
+		nullString(program.RewardProductId),
+		program.RewardValue,
+		program.ExpirationDays,
+		program.Active,
+		program.UpdatedAt,
+		program.Id,
+		program.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.LoyaltyProgramAggregateType, program.Id, program.Version); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM loyalty_earn_rules WHERE program_id = ?`, program.Id); err != nil {
+		return err
+	}
+
+	if err := createLoyaltyEarnRules(tx, program); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, program); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	program.Version++
+	program.ClearAuditRecords()
+	return nil
+}
+
+func createLoyaltyEarnRules(tx *sql.Tx, program *aggregates.LoyaltyProgram) error {
+	query := `
+		INSERT INTO loyalty_earn_rules (id, program_id, product_id, category_id, points)
+		VALUES (?, ?, ?, ?, ?)`
+
+	for _, rule := range program.EarnRules {
+		_, err := tx.Exec(
+			query,
+			uuid.NewString(),
+			program.Id,
+			nullString(rule.ProductId),
+			nullString(rule.CategoryId),
+			rule.Points,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,821 @@
+package respositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	marketplaceorderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/marketplace_order_status"
+	marketplacepushstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/marketplace_push_status"
+	orderchannel "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_channel"
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// a resposta do marketplace pode ser longa; a coluna guarda só o começo
+	marketplaceErrorMaxLength    = 512
+	defaultMarketplaceQueryLimit = 50
+)
+
+type marketplaceIntegrationRepository struct {
+	db *database.Db
+}
+
+func NewMarketplaceIntegrationRepository(db *database.Db) ports.IMarketplaceIntegrationRepository {
+	return &marketplaceIntegrationRepository{
+		db: db,
+	}
+}
+
+const (
+	marketplaceIntegrationBaseFields = `
+		id,
+		restaurant_id,
+		channel,
+		merchant_id,
+		active,
+		created_at,
+		updated_at,
+		version`
+)
+
+func (r *marketplaceIntegrationRepository) FindById(id string) (*aggregates.MarketplaceIntegration, error) {
+	query := `SELECT ` + marketplaceIntegrationBaseFields + ` FROM marketplace_integrations WHERE id = ?`
+	return r.findOne(query, id)
+}
+
+func (r *marketplaceIntegrationRepository) FindByMerchantId(channel orderchannel.OrderChannel, merchantId string) (*aggregates.MarketplaceIntegration, error) {
+	query := `SELECT ` + marketplaceIntegrationBaseFields + ` FROM marketplace_integrations WHERE channel = ? AND merchant_id = ?`
+	return r.findOne(query, channel, merchantId)
+}
+
+func (r *marketplaceIntegrationRepository) FindByRestaurantId(restaurantId string) ([]aggregates.MarketplaceIntegration, error) {
+	query := `
+		SELECT ` + marketplaceIntegrationBaseFields + `
+		FROM marketplace_integrations
+		WHERE restaurant_id = ?
+		ORDER BY channel ASC`
+
+	return r.findMany(query, restaurantId)
+}
+
+func (r *marketplaceIntegrationRepository) FindActive(channel orderchannel.OrderChannel) ([]aggregates.MarketplaceIntegration, error) {
+	query := `
+		SELECT ` + marketplaceIntegrationBaseFields + `
+		FROM marketplace_integrations
+		WHERE channel = ? AND active = TRUE
+		ORDER BY created_at ASC`
+
+	return r.findMany(query, channel)
+}
+
+func (r *marketplaceIntegrationRepository) Create(integration *aggregates.MarketplaceIntegration) error {
+	query := `
+		INSERT INTO marketplace_integrations (
+			id, restaurant_id, channel, merchant_id, active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		integration.Id,
+		integration.RestaurantId,
+		integration.Channel,
+		integration.MerchantId,
+		integration.Active,
+		integration.CreatedAt,
+		integration.UpdatedAt,
+	)
+	if err != nil {
+		return marketplaceDuplicate(err, ports.ErrMarketplaceMerchantTaken)
+	}
+
+	if err := insertAuditRecords(tx, integration); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	integration.ClearAuditRecords()
+	return nil
+}
+
+func (r *marketplaceIntegrationRepository) Update(integration *aggregates.MarketplaceIntegration) error {
+	query := `
+		UPDATE marketplace_integrations SET
+			merchant_id = ?,
+			active = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		integration.MerchantId,
+		integration.Active,
+		integration.UpdatedAt,
+		integration.Id,
+		integration.Version,
+	)
+	if err != nil {
+		return marketplaceDuplicate(err, ports.ErrMarketplaceMerchantTaken)
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.MarketplaceIntegrationAggregateType, integration.Id, integration.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, integration); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	integration.Version++
+	integration.ClearAuditRecords()
+	return nil
+}
+
+func (r *marketplaceIntegrationRepository) Delete(integration *aggregates.MarketplaceIntegration) error {
+	_, err := r.db.Instance.Exec(`DELETE FROM marketplace_integrations WHERE id = ?`, integration.Id)
+	return err
+}
+
+func (r *marketplaceIntegrationRepository) findOne(query string, params ...any) (*aggregates.MarketplaceIntegration, error) {
+	integration, err := scanMarketplaceIntegration(r.db.Instance.QueryRow(query, params...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return integration, nil
+}
+
+func (r *marketplaceIntegrationRepository) findMany(query string, params ...any) ([]aggregates.MarketplaceIntegration, error) {
+	rows, err := r.db.Instance.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	integrations := make([]aggregates.MarketplaceIntegration, 0)
+	for rows.Next() {
+		integration, err := scanMarketplaceIntegration(rows)
+		if err != nil {
+			return nil, err
+		}
+		integrations = append(integrations, *integration)
+	}
+
+	return integrations, rows.Err()
+}
+
+func scanMarketplaceIntegration(row rowScanner) (*aggregates.MarketplaceIntegration, error) {
+	var integration aggregates.MarketplaceIntegration
+	err := row.Scan(
+		&integration.Id,
+		&integration.RestaurantId,
+		&integration.Channel,
+		&integration.MerchantId,
+		&integration.Active,
+		&integration.CreatedAt,
+		&integration.UpdatedAt,
+		&integration.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &integration, nil
+}
+
+type marketplaceMappingRepository struct {
+	db *database.Db
+}
+
+func NewMarketplaceMappingRepository(db *database.Db) ports.IMarketplaceMappingRepository {
+	return &marketplaceMappingRepository{
+		db: db,
+	}
+}
+
+const (
+	marketplaceMappingBaseFields = `
+		mm.id,
+		mm.restaurant_id,
+		mm.channel,
+		mm.external_code,
+		mm.product_id,
+		p.name,
+		mm.created_at,
+		mm.updated_at,
+		mm.version`
+)
+
+func (r *marketplaceMappingRepository) FindById(id string) (*aggregates.MarketplaceMapping, error) {
+	query := `
+		SELECT ` + marketplaceMappingBaseFields + `
+		FROM marketplace_mappings mm
+		JOIN products p ON mm.product_id = p.id
+		WHERE mm.id = ?`
+
+	mapping, err := scanMarketplaceMapping(r.db.Instance.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+func (r *marketplaceMappingRepository) FindByRestaurantId(restaurantId string, channel orderchannel.OrderChannel) ([]aggregates.MarketplaceMapping, error) {
+	query := `
+		SELECT ` + marketplaceMappingBaseFields + `
+		FROM marketplace_mappings mm
+		JOIN products p ON mm.product_id = p.id
+		WHERE mm.restaurant_id = ? AND mm.channel = ?
+		ORDER BY mm.external_code ASC`
+
+	return r.findMany(query, restaurantId, channel)
+}
+
+func (r *marketplaceMappingRepository) FindByExternalCodes(
+	restaurantId string,
+	channel orderchannel.OrderChannel,
+	externalCodes []string,
+) (map[string]aggregates.MarketplaceMapping, error) {
+	byCode := make(map[string]aggregates.MarketplaceMapping, len(externalCodes))
+	if len(externalCodes) == 0 {
+		return byCode, nil
+	}
+
+	query := `
+		SELECT ` + marketplaceMappingBaseFields + `
+		FROM marketplace_mappings mm
+		JOIN products p ON mm.product_id = p.id
+		WHERE mm.restaurant_id = ? AND mm.channel = ?
+		AND mm.external_code IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(externalCodes)), ", ") + `)`
+
+	params := make([]any, 0, len(externalCodes)+2)
+	params = append(params, restaurantId, channel)
+	for _, code := range externalCodes {
+		params = append(params, code)
+	}
+
+	mappings, err := r.findMany(query, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mapping := range mappings {
+		byCode[mapping.ExternalCode] = mapping
+	}
+
+	return byCode, nil
+}
+
+func (r *marketplaceMappingRepository) Create(mapping *aggregates.MarketplaceMapping) error {
+	query := `
+		INSERT INTO marketplace_mappings (
+			id, restaurant_id, channel, external_code, product_id, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		mapping.Id,
+		mapping.RestaurantId,
+		mapping.Channel,
+		mapping.ExternalCode,
+		mapping.Product.Id,
+		mapping.CreatedAt,
+		mapping.UpdatedAt,
+	)
+	if err != nil {
+		return marketplaceDuplicate(err, ports.ErrMarketplaceMappingExists)
+	}
+
+	if err := insertAuditRecords(tx, mapping); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	mapping.ClearAuditRecords()
+	return nil
+}
+
+func (r *marketplaceMappingRepository) Update(mapping *aggregates.MarketplaceMapping) error {
+	query := `
+		UPDATE marketplace_mappings SET
+			external_code = ?,
+			product_id = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		mapping.ExternalCode,
+		mapping.Product.Id,
+		mapping.UpdatedAt,
+		mapping.Id,
+		mapping.Version,
+	)
+	if err != nil {
+		return marketplaceDuplicate(err, ports.ErrMarketplaceMappingExists)
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.MarketplaceMappingAggregateType, mapping.Id, mapping.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, mapping); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	mapping.Version++
+	mapping.ClearAuditRecords()
+	return nil
+}
+
+func (r *marketplaceMappingRepository) Delete(mapping *aggregates.MarketplaceMapping) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM marketplace_mappings WHERE id = ?`, mapping.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, mapping); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	mapping.ClearAuditRecords()
+	return nil
+}
+
+func (r *marketplaceMappingRepository) findMany(query string, params ...any) ([]aggregates.MarketplaceMapping, error) {
+	rows, err := r.db.Instance.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mappings := make([]aggregates.MarketplaceMapping, 0)
+	for rows.Next() {
+		mapping, err := scanMarketplaceMapping(rows)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, *mapping)
+	}
+
+	return mappings, rows.Err()
+}
+
+func scanMarketplaceMapping(row rowScanner) (*aggregates.MarketplaceMapping, error) {
+	var mapping aggregates.MarketplaceMapping
+	err := row.Scan(
+		&mapping.Id,
+		&mapping.RestaurantId,
+		&mapping.Channel,
+		&mapping.ExternalCode,
+		&mapping.Product.Id,
+		&mapping.Product.Name,
+		&mapping.CreatedAt,
+		&mapping.UpdatedAt,
+		&mapping.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mapping, nil
+}
+
+type marketplaceOrderRepository struct {
+	db *database.Db
+}
+
+func NewMarketplaceOrderRepository(db *database.Db) ports.IMarketplaceOrderRepository {
+	return &marketplaceOrderRepository{
+		db: db,
+	}
+}
+
+const (
+	marketplaceOrderBaseFields = `
+		id,
+		restaurant_id,
+		channel,
+		external_id,
+		external_code,
+		order_id,
+		status,
+		unmapped_items,
+		last_error,
+		received_at,
+		updated_at,
+		version`
+)
+
+func (r *marketplaceOrderRepository) FindById(id string) (*aggregates.MarketplaceOrder, error) {
+	query := `SELECT ` + marketplaceOrderBaseFields + ` FROM marketplace_orders WHERE id = ?`
+	return r.findOne(query, id)
+}
+
+func (r *marketplaceOrderRepository) FindByExternalId(channel orderchannel.OrderChannel, externalId string) (*aggregates.MarketplaceOrder, error) {
+	query := `SELECT ` + marketplaceOrderBaseFields + ` FROM marketplace_orders WHERE channel = ? AND external_id = ?`
+	return r.findOne(query, channel, externalId)
+}
+
+func (r *marketplaceOrderRepository) FindByOrderId(orderId string) (*aggregates.MarketplaceOrder, error) {
+	query := `SELECT ` + marketplaceOrderBaseFields + ` FROM marketplace_orders WHERE order_id = ?`
+	return r.findOne(query, orderId)
+}
+
+func (r *marketplaceOrderRepository) FindByRestaurantId(
+	restaurantId string,
+	status marketplaceorderstatus.MarketplaceOrderStatus,
+	limit int,
+) ([]aggregates.MarketplaceOrder, error) {
+	if limit <= 0 {
+		limit = defaultMarketplaceQueryLimit
+	}
+
+	query := `
+		SELECT ` + marketplaceOrderBaseFields + `
+		FROM marketplace_orders
+		WHERE restaurant_id = ?`
+	params := []any{restaurantId}
+
+	if status != "" {
+		query += ` AND status = ?`
+		params = append(params, status)
+	}
+
+	query += ` ORDER BY received_at DESC LIMIT ?`
+	params = append(params, limit)
+
+	rows, err := r.db.Instance.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]aggregates.MarketplaceOrder, 0)
+	for rows.Next() {
+		order, err := scanMarketplaceOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *order)
+	}
+
+	return orders, rows.Err()
+}
+
+func (r *marketplaceOrderRepository) Reserve(order *aggregates.MarketplaceOrder) (bool, error) {
+	unmappedItems, err := json.Marshal(order.UnmappedItems)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO marketplace_orders (
+			id, restaurant_id, channel, external_id, external_code, order_id,
+			status, unmapped_items, last_error, received_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.Instance.Exec(
+		query,
+		order.Id,
+		order.RestaurantId,
+		order.Channel,
+		order.ExternalId,
+		order.ExternalCode,
+		nullString(order.OrderId),
+		order.Status,
+		unmappedItems,
+		nullString(truncateMarketplaceError(order.LastError)),
+		order.ReceivedAt,
+		order.UpdatedAt,
+	)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *marketplaceOrderRepository) Update(order *aggregates.MarketplaceOrder) error {
+	unmappedItems, err := json.Marshal(order.UnmappedItems)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE marketplace_orders SET
+			order_id = ?,
+			status = ?,
+			unmapped_items = ?,
+			last_error = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		nullString(order.OrderId),
+		order.Status,
+		unmappedItems,
+		nullString(truncateMarketplaceError(order.LastError)),
+		order.UpdatedAt,
+		order.Id,
+		order.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.MarketplaceOrderAggregateType, order.Id, order.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	order.Version++
+	order.ClearAuditRecords()
+	return nil
+}
+
+func (r *marketplaceOrderRepository) findOne(query string, params ...any) (*aggregates.MarketplaceOrder, error) {
+	order, err := scanMarketplaceOrder(r.db.Instance.QueryRow(query, params...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func scanMarketplaceOrder(row rowScanner) (*aggregates.MarketplaceOrder, error) {
+	var order aggregates.MarketplaceOrder
+	var orderId, lastError sql.NullString
+	var unmappedItems []byte
+	err := row.Scan(
+		&order.Id,
+		&order.RestaurantId,
+		&order.Channel,
+		&order.ExternalId,
+		&order.ExternalCode,
+		&orderId,
+		&order.Status,
+		&unmappedItems,
+		&lastError,
+		&order.ReceivedAt,
+		&order.UpdatedAt,
+		&order.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(unmappedItems, &order.UnmappedItems); err != nil {
+		return nil, err
+	}
+	order.OrderId = orderId.String
+	order.LastError = lastError.String
+
+	return &order, nil
+}
+
+type marketplacePushRepository struct {
+	db *database.Db
+}
+
+func NewMarketplacePushRepository(db *database.Db) ports.IMarketplacePushRepository {
+	return &marketplacePushRepository{
+		db: db,
+	}
+}
+
+const (
+	marketplacePushBaseFields = `
+		p.id,
+		p.restaurant_id,
+		p.channel,
+		p.order_id,
+		p.external_id,
+		p.action,
+		p.reason,
+		p.status,
+		p.attempts,
+		p.next_attempt_at,
+		p.last_error,
+		p.created_at,
+		p.sent_at,
+		p.version`
+)
+
+func (r *marketplacePushRepository) Create(push *aggregates.MarketplacePush) error {
+	query := `
+		INSERT INTO marketplace_pushes (
+			id, restaurant_id, channel, order_id, external_id, action, reason,
+			status, attempts, next_attempt_at, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Instance.Exec(
+		query,
+		push.Id,
+		push.RestaurantId,
+		push.Channel,
+		push.OrderId,
+		push.ExternalId,
+		push.Action,
+		nullString(push.Reason),
+		push.Status,
+		push.Attempts,
+		push.NextAttemptAt,
+		push.CreatedAt,
+	)
+	return err
+}
+
+func (r *marketplacePushRepository) Update(push *aggregates.MarketplacePush) error {
+	query := `
+		UPDATE marketplace_pushes SET
+			status = ?,
+			attempts = ?,
+			next_attempt_at = ?,
+			last_error = ?,
+			sent_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	result, err := r.db.Instance.Exec(
+		query,
+		push.Status,
+		push.Attempts,
+		push.NextAttemptAt,
+		nullString(truncateMarketplaceError(push.LastError)),
+		push.SentAt,
+		push.Id,
+		push.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.MarketplacePushAggregateType, push.Id, push.Version); err != nil {
+		return err
+	}
+
+	push.Version++
+	return nil
+}
+
+// FindDue segura o envio enquanto um anterior do mesmo pedido está pendente, para o marketplace
+// não receber a conclusão antes da confirmação que ainda espera nova tentativa
+func (r *marketplacePushRepository) FindDue(now time.Time, limit int) ([]aggregates.MarketplacePush, error) {
+	query := `
+		SELECT ` + marketplacePushBaseFields + `
+		FROM marketplace_pushes p
+		WHERE p.status = ? AND p.next_attempt_at <= ?
+		AND NOT EXISTS (
+			SELECT 1 FROM marketplace_pushes earlier
+			WHERE earlier.order_id = p.order_id
+			AND earlier.status = ?
+			AND earlier.created_at < p.created_at
+		)
+		ORDER BY p.next_attempt_at ASC
+		LIMIT ?`
+
+	rows, err := r.db.Instance.Query(query, marketplacepushstatus.PENDING, now, marketplacepushstatus.PENDING, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pushes := make([]aggregates.MarketplacePush, 0)
+	for rows.Next() {
+		var push aggregates.MarketplacePush
+		var reason, lastError sql.NullString
+		var nextAttemptAt, sentAt sql.NullTime
+		err := rows.Scan(
+			&push.Id,
+			&push.RestaurantId,
+			&push.Channel,
+			&push.OrderId,
+			&push.ExternalId,
+			&push.Action,
+			&reason,
+			&push.Status,
+			&push.Attempts,
+			&nextAttemptAt,
+			&lastError,
+			&push.CreatedAt,
+			&sentAt,
+			&push.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		push.Reason = reason.String
+		push.LastError = lastError.String
+		if nextAttemptAt.Valid {
+			push.NextAttemptAt = &nextAttemptAt.Time
+		}
+		if sentAt.Valid {
+			push.SentAt = &sentAt.Time
+		}
+		pushes = append(pushes, push)
+	}
+
+	return pushes, rows.Err()
+}
+
+func truncateMarketplaceError(message string) string {
+	if len(message) > marketplaceErrorMaxLength {
+		return message[:marketplaceErrorMaxLength]
+	}
+	return message
+}
+
+// marketplaceDuplicate troca a violação de chave única pelo erro do domínio
+func marketplaceDuplicate(err error, duplicate error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return duplicate
+	}
+	return err
+}
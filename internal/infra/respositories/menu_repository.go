@@ -0,0 +1,227 @@
+package respositories
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+type menuRepository struct {
+	db *database.Db
+}
+
+func NewMenuRepository(db *database.Db) ports.IMenuRepository {
+	return &menuRepository{
+		db: db,
+	}
+}
+
+const (
+	menuBaseFields = `
+		m.id,
+		m.picture_url,
+		m.restaurant_id,
+		m.offer_date`
+
+	menuItemBaseFields = `
+		mi.id,
+		mi.dish_id,
+		d.name,
+		d.type,
+		d.picture_url,
+		mi.additional_price,
+		mi.available_portions,
+		mi.enabled,
+		mi.can_be_used_as_additional`
+)
+
+func (r *menuRepository) FindByOfferDate(restaurantId string, offerDate time.Time) (*aggregates.Menu, error) {
+	query := `
+		SELECT 
+			` + menuBaseFields + `
+		FROM menus m
+		WHERE m.restaurant_id = ? AND m.offer_date = ?`
+
+	return r.findOne(query, restaurantId, offerDate.Format(time.DateOnly))
+}
+
+func (r *menuRepository) FindByItemId(menuItemId string) (*aggregates.Menu, error) {
+	query := `
+		SELECT 
+			` + menuBaseFields + `
+		FROM menus m
+		JOIN menu_items mi ON mi.menu_id = m.id
+		WHERE mi.id = ?`
+
+	return r.findOne(query, menuItemId)
+}
+
+func (r *menuRepository) UpdateItem(menu *aggregates.Menu, menuItem *aggregates.MenuItem) error {
+	query := `
+		UPDATE menu_items SET
+			available_portions = ?,
+			enabled = ?
+		WHERE id = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query, nullPortions(menuItem.AvailablePortions), menuItem.Enabled, menuItem.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, menu); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	menu.ClearAuditRecords()
+	return nil
+}
+
+func (r *menuRepository) findOne(query string, params ...any) (*aggregates.Menu, error) {
+	var menu aggregates.Menu
+	var pictureUrl sql.NullString
+	err := r.db.Instance.QueryRow(query, params...).Scan(
+		&menu.Id,
+		&pictureUrl,
+		&menu.Restaurant.Id,
+		&menu.OfferDate,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	menu.PictureUrl = pictureUrl.String
+
+	items, err := r.findItems(menu.Id)
+	if err != nil {
+		return nil, err
+	}
+	menu.Items = items
+
+	return &menu, nil
+}
+
+func (r *menuRepository) findItems(menuId string) ([]aggregates.MenuItem, error) {
+	query := `
+		SELECT 
+			` + menuItemBaseFields + `
+		FROM menu_items mi
+		JOIN dishes d ON mi.dish_id = d.id
+		WHERE mi.menu_id = ? AND d.deleted_at IS NULL
+		ORDER BY d.type, d.name`
+
+	rows, err := r.db.Instance.Query(query, menuId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]aggregates.MenuItem, 0, 10)
+	for rows.Next() {
+		var item aggregates.MenuItem
+		var dishPictureUrl sql.NullString
+		var availablePortions sql.NullInt64
+		err := rows.Scan(
+			&item.Id,
+			&item.Dish.Id,
+			&item.Dish.Name,
+			&item.Dish.Type,
+			&dishPictureUrl,
+			&item.AdditionalPrice,
+			&availablePortions,
+			&item.Enabled,
+			&item.CanBeUsedAsAdditional,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.DishPictureUrl = dishPictureUrl.String
+		if availablePortions.Valid {
+			portions := int(availablePortions.Int64)
+			item.AvailablePortions = &portions
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// reserveMenuPortions tira do cardápio as porções do pedido dentro da transação que o grava.
+// As linhas são travadas em ordem de id para que pedidos simultâneos não se bloqueiem em ciclo.
+func reserveMenuPortions(tx *sql.Tx, order *aggregates.Order) error {
+	portions := aggregates.MenuPortionsOf(order.Items)
+
+	for _, menuItemId := range sortedKeys(portions) {
+		var enabled bool
+		var available sql.NullInt64
+		err := tx.QueryRow(`SELECT enabled, available_portions FROM menu_items WHERE id = ? FOR UPDATE`, menuItemId).Scan(&enabled, &available)
+		if err != nil {
+			return err
+		}
+
+		if !enabled || (available.Valid && available.Int64 < int64(portions[menuItemId])) {
+			return ports.ErrMenuItemSoldOut
+		}
+		if !available.Valid {
+			continue
+		}
+
+		_, err = tx.Exec(`UPDATE menu_items SET available_portions = available_portions - ? WHERE id = ?`, portions[menuItemId], menuItemId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseMenuPortions devolve as porções de um pedido cancelado aos itens que controlam quantidade;
+// segue a mesma ordem de reserveMenuPortions para as travas não se cruzarem
+func releaseMenuPortions(tx *sql.Tx, portions map[string]int) error {
+	query := `
+		UPDATE menu_items
+		SET available_portions = available_portions + ?
+		WHERE id = ? AND available_portions IS NOT NULL`
+
+	for _, menuItemId := range sortedKeys(portions) {
+		if _, err := tx.Exec(query, portions[menuItemId], menuItemId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func nullPortions(portions *int) sql.NullInt64 {
+	if portions == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*portions), Valid: true}
+}
+
+func sortedKeys(values map[string]int) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,290 @@
+package respositories
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	notificationstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/notification_status"
+	"github.com/go-sql-driver/mysql"
+)
+
+// o provedor pode devolver textos longos; a coluna guarda só o começo
+const notificationErrorMaxLength = 512
+
+const notificationBaseFields = `
+	id, restaurant_id, order_id, customer_id, template, channel, recipient,
+	subject, body, status, attempts, next_attempt_at, last_error, created_at, sent_at, version`
+
+type notificationPreferencesRepository struct {
+	db *database.Db
+}
+
+func NewNotificationPreferencesRepository(db *database.Db) ports.INotificationPreferencesRepository {
+	return &notificationPreferencesRepository{
+		db: db,
+	}
+}
+
+func (r *notificationPreferencesRepository) FindByCustomerId(customerId string) (*aggregates.NotificationPreferences, error) {
+	query := `
+		SELECT customer_id, email, whatsapp, sms, updated_at, version
+		FROM customer_notification_preferences
+		WHERE customer_id = ?`
+
+	var preferences aggregates.NotificationPreferences
+	err := r.db.Instance.QueryRow(query, customerId).Scan(
+		&preferences.CustomerId,
+		&preferences.Email,
+		&preferences.WhatsApp,
+		&preferences.Sms,
+		&preferences.UpdatedAt,
+		&preferences.Version,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	preferences.Id = preferences.CustomerId
+
+	return &preferences, nil
+}
+
+func (r *notificationPreferencesRepository) Create(preferences *aggregates.NotificationPreferences) error {
+	query := `
+		INSERT INTO customer_notification_preferences (
+			customer_id, email, whatsapp, sms, updated_at
+		) VALUES (?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		preferences.CustomerId,
+		preferences.Email,
+		preferences.WhatsApp,
+		preferences.Sms,
+		preferences.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, preferences); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	preferences.ClearAuditRecords()
+	return nil
+}
+
+func (r *notificationPreferencesRepository) Update(preferences *aggregates.NotificationPreferences) error {
+	query := `
+		UPDATE customer_notification_preferences SET
+			email = ?,
+			whatsapp = ?,
+			sms = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE customer_id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		preferences.Email,
+		preferences.WhatsApp,
+		preferences.Sms,
+		preferences.UpdatedAt,
+		preferences.CustomerId,
+		preferences.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.NotificationPreferencesAggregateType, preferences.CustomerId, preferences.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, preferences); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	preferences.Version++
+	preferences.ClearAuditRecords()
+	return nil
+}
+
+type notificationRepository struct {
+	db *database.Db
+}
+
+func NewNotificationRepository(db *database.Db) ports.INotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (r *notificationRepository) Reserve(notification *aggregates.Notification) (bool, error) {
+	query := `
+		INSERT INTO notifications (
+			id, restaurant_id, order_id, customer_id, template, channel, recipient,
+			subject, body, status, attempts, next_attempt_at, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Instance.Exec(
+		query,
+		notification.Id,
+		notification.RestaurantId,
+		notification.OrderId,
+		notification.CustomerId,
+		notification.Template,
+		notification.Channel,
+		notification.Recipient,
+		nullString(notification.Subject),
+		notification.Body,
+		notification.Status,
+		notification.Attempts,
+		notification.NextAttemptAt,
+		notification.CreatedAt,
+	)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *notificationRepository) Update(notification *aggregates.Notification) error {
+	lastError := notification.LastError
+	if len(lastError) > notificationErrorMaxLength {
+		lastError = lastError[:notificationErrorMaxLength]
+	}
+
+	query := `
+		UPDATE notifications SET
+			status = ?,
+			attempts = ?,
+			next_attempt_at = ?,
+			last_error = ?,
+			sent_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	result, err := r.db.Instance.Exec(
+		query,
+		notification.Status,
+		notification.Attempts,
+		notification.NextAttemptAt,
+		nullString(lastError),
+		notification.SentAt,
+		notification.Id,
+		notification.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.NotificationAggregateType, notification.Id, notification.Version); err != nil {
+		return err
+	}
+
+	notification.Version++
+	return nil
+}
+
+func (r *notificationRepository) FindDue(now time.Time, limit int) ([]aggregates.Notification, error) {
+	query := `
+		SELECT ` + notificationBaseFields + `
+		FROM notifications
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC
+		LIMIT ?`
+
+	return r.query(query, notificationstatus.PENDING, now, limit)
+}
+
+func (r *notificationRepository) FindByOrderId(orderId string) ([]aggregates.Notification, error) {
+	query := `
+		SELECT ` + notificationBaseFields + `
+		FROM notifications
+		WHERE order_id = ?
+		ORDER BY created_at ASC`
+
+	return r.query(query, orderId)
+}
+
+func (r *notificationRepository) query(statement string, params ...any) ([]aggregates.Notification, error) {
+	rows, err := r.db.Instance.Query(statement, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]aggregates.Notification, 0)
+	for rows.Next() {
+		var notification aggregates.Notification
+		var subject, body, lastError sql.NullString
+		var nextAttemptAt, sentAt sql.NullTime
+		err := rows.Scan(
+			&notification.Id,
+			&notification.RestaurantId,
+			&notification.OrderId,
+			&notification.CustomerId,
+			&notification.Template,
+			&notification.Channel,
+			&notification.Recipient,
+			&subject,
+			&body,
+			&notification.Status,
+			&notification.Attempts,
+			&nextAttemptAt,
+			&lastError,
+			&notification.CreatedAt,
+			&sentAt,
+			&notification.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		notification.Subject = subject.String
+		notification.Body = body.String
+		notification.LastError = lastError.String
+		if nextAttemptAt.Valid {
+			notification.NextAttemptAt = &nextAttemptAt.Time
+		}
+		if sentAt.Valid {
+			notification.SentAt = &sentAt.Time
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
@@ -6,681 +6,908 @@ import (
 
 	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
 	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	dishtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/dish_type"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	"github.com/PedroNetto404/marmitech-backend/pkg/enums/uf"
 	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/google/uuid"
 )
 
 type orderRepository struct {
-	db *sql.DB
+	db *database.Db
 }
 
-func NewOrderRepository(db *sql.DB) ports.IOrderRepository {
-	return &orderRepository{db: db}
+func NewOrderRepository(db *database.Db) ports.IOrderRepository {
+	return &orderRepository{
+		db: db,
+	}
 }
 
 const (
 	orderBaseFields = `
-		id,
-		restaurant_id,
-		customer_id,
-		status,
-		total,
-		discount,
-		observation,
-		deleted_at
-	`
-
-	orderDeliveryFields = `
-		id,
-		order_id,
-		address_alias,
-		address_street,
-		address_number,
-		address_complement,
-		address_neighborhood,
-		address_city,
-		address_state,
-		address_country,
-		address_zip_code,
-		address_lat,
-		address_lng,
-		fee,
-		distance,
-		average_time_minutes,
-		status
-	`
-
-	orderItemFields = `
-		id,
-		order_id,
-		product_id,
-		product_name,
-		product_unit_price,
-		quantity,
-		observation,
-		discount,
-		item_total
-	`
-
-	orderPaymentFields = `
-		id,
-		order_id,
-		payment_method,
-		amount,
-		status
-	`
+		o.id,
+		o.restaurant_id,
+		o.customer_id,
+		c.first_name,
+		c.last_name,
+		c.contact_email,
+		o.status,
+		o.subtotal,
+		o.discount,
+		o.total,
+		o.observation,
+		o.cancel_reason,
+		o.channel,
+		o.external_id,
+		o.external_code,
+		o.created_at,
+		o.updated_at,
+		o.version`
+
+	orderItemBaseFields = `
+		oi.id,
+		oi.product_id,
+		oi.product_name,
+		oi.unit_price,
+		oi.quantity,
+		oi.discount,
+		oi.total,
+		oi.observation,
+		oi.status`
+
+	lunchboxSelectionBaseFields = `
+		loi.order_item_id,
+		loi.id,
+		loi.wants_flatware,
+		lsmi.id,
+		lsmi.menu_item_id,
+		lsmi.dish_id,
+		lsmi.dish_name,
+		lsmi.dish_type,
+		lsmi.quantity,
+		lsmi.is_additional,
+		lsmi.price`
+
+	orderPaymentBaseFields = `
+		op.id,
+		op.payment_method,
+		op.amount,
+		op.status,
+		op.paid_at,
+		op.pix_key,
+		op.cash_session_id`
+
+	orderPromotionBaseFields = `
+		opr.promotion_id,
+		opr.order_item_id,
+		opr.name,
+		opr.code,
+		opr.amount`
 )
 
 func (r *orderRepository) Find(args types.FindArgs) (*types.PagedSlice[aggregates.Order], error) {
-	offset := (args.Offset - 1) * args.Limit
+	baseQuery := `
+		SELECT
+			` + orderBaseFields + `
+		FROM orders o
+		LEFT JOIN customers c ON o.customer_id = c.id
+		WHERE o.deleted_at IS NULL`
 
-	// Count total records
-	var total int
 	countQuery := `
 		SELECT COUNT(*)
-		FROM orders
-		WHERE deleted_at IS NULL
-	`
-	err := r.db.QueryRow(countQuery).Scan(&total)
+		FROM orders o
+		WHERE o.deleted_at IS NULL`
+
+	query, count, params := r.db.ConstructFindQuery(baseQuery, countQuery, args)
+
+	orders, err := r.findMany(query, params...)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get orders
+	pagedSlice := types.NewPagedSlice(args.Limit, args.Offset, count, orders)
+	return &pagedSlice, nil
+}
+
+func (r *orderRepository) FindById(id string) (*aggregates.Order, error) {
 	query := `
-		SELECT ` + orderBaseFields + `
-		FROM orders
-		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
-
-	rows, err := r.db.Query(query, args.Limit, offset)
+		SELECT
+			` + orderBaseFields + `
+		FROM orders o
+		LEFT JOIN customers c ON o.customer_id = c.id
+		WHERE o.deleted_at IS NULL AND o.id = ?`
+
+	order, err := scanOrder(r.db.Instance.QueryRow(query, id))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
 		return nil, err
 	}
-	defer rows.Close()
 
-	var orders []aggregates.Order
-	for rows.Next() {
-		var order aggregates.Order
-		var deletedAt sql.NullTime
-		err := rows.Scan(
-			&order.Id,
-			&order.RestaurantID,
-			&order.CustomerID,
-			&order.Status,
-			&order.Total,
-			&order.Discount,
-			&order.Observation,
-			&deletedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
+	if err := r.loadChildren(order); err != nil {
+		return nil, err
+	}
 
-		// Get delivery
-		delivery, err := r.getOrderDelivery(order.Id)
-		if err != nil {
-			return nil, err
-		}
-		order.Delivery = delivery
+	return order, nil
+}
 
-		// Get items
-		items, err := r.getOrderItems(order.Id)
-		if err != nil {
-			return nil, err
-		}
-		order.Items = items
+func (r *orderRepository) FindInKitchen(restaurantId string) ([]aggregates.Order, error) {
+	query := `
+		SELECT
+			` + orderBaseFields + `
+		FROM orders o
+		LEFT JOIN customers c ON o.customer_id = c.id
+		WHERE o.deleted_at IS NULL
+		AND o.restaurant_id = ?
+		AND o.status IN (?, ?, ?)
+		ORDER BY o.created_at ASC`
+
+	return r.findMany(
+		query,
+		restaurantId,
+		orderstatus.PENDING,
+		orderstatus.PREPARING,
+		orderstatus.READY,
+	)
+}
 
-		// Get payments
-		payments, err := r.getOrderPayments(order.Id)
-		if err != nil {
-			return nil, err
-		}
-		order.Payments = payments
+func (r *orderRepository) CountByCustomerId(restaurantId, customerId string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM orders o
+		WHERE o.deleted_at IS NULL
+		AND o.restaurant_id = ?
+		AND o.customer_id = ?
+		AND o.status <> ?`
+
+	var count int
+	err := r.db.Instance.QueryRow(query, restaurantId, customerId, orderstatus.CANCELLED).Scan(&count)
+	return count, err
+}
 
-		orders = append(orders, order)
+func (r *orderRepository) Create(order *aggregates.Order, redemption ports.OrderRedemption) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	pagedSlice := types.NewPagedSlice(args.Limit, args.Offset, total, orders)
-	return &pagedSlice, nil
-}
-
-func (r *orderRepository) FindById(id string) (*aggregates.Order, error) {
 	query := `
-		SELECT ` + orderBaseFields + `
-		FROM orders
-		WHERE id = $1 AND deleted_at IS NULL
-	`
+		INSERT INTO orders (
+			id, restaurant_id, customer_id, status, subtotal, discount,
+			total, observation, channel, external_id, external_code, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	var order aggregates.Order
-	var deletedAt sql.NullTime
-	err := r.db.QueryRow(query, id).Scan(
-		&order.Id,
-		&order.CustomerID,
-		&order.RestaurantID,
-		&order.Total,
-		&order.TotalDiscount,
-		&order.Discount,
-		&order.Observation,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-		&deletedAt,
+	_, err = tx.Exec(
+		query,
+		order.Id,
+		order.Restaurant.Id,
+		nullString(order.Customer.Id),
+		order.Status,
+		order.Subtotal,
+		order.Discount,
+		order.Total,
+		order.Observation,
+		order.Channel,
+		nullString(order.ExternalId),
+		nullString(order.ExternalCode),
+		order.CreatedAt,
+		order.UpdatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
+		return err
+	}
+
+	for i := range order.Items {
+		if err := createOrderItem(tx, order.Id, i, &order.Items[i]); err != nil {
+			return err
 		}
-		return nil, err
 	}
 
-	// Get delivery
-	delivery, err := r.getOrderDelivery(order.Id)
-	if err != nil {
-		return nil, err
+	if order.Delivery != nil {
+		if err := createOrderDelivery(tx, order.Id, order.Delivery); err != nil {
+			return err
+		}
 	}
-	order.Delivery = delivery
 
-	// Get items
-	items, err := r.getOrderItems(order.Id)
-	if err != nil {
-		return nil, err
+	for i := range order.Payments {
+		if err := createOrderPayment(tx, order.Id, &order.Payments[i]); err != nil {
+			return err
+		}
 	}
-	order.Items = items
 
-	// Get payments
-	payments, err := r.getOrderPayments(order.Id)
-	if err != nil {
-		return nil, err
+	if err := createOrderPromotions(tx, order); err != nil {
+		return err
 	}
-	order.Payments = payments
 
-	return &order, nil
+	if err := reserveMenuPortions(tx, order); err != nil {
+		return err
+	}
+
+	if redemption.LoyaltyRedeem != nil {
+		if err := addLoyaltyEntries(tx, redemption.LoyaltyAccount, redemption.LoyaltyRedeem); err != nil {
+			return err
+		}
+	}
+
+	if err := insertDomainEvents(tx, order); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	order.ClearAuditRecords()
+	if redemption.LoyaltyRedeem != nil {
+		redemption.LoyaltyAccount.Version++
+	}
+	return nil
 }
 
-func (r *orderRepository) Create(order *aggregates.Order) error {
-	tx, err := r.db.Begin()
+// Update grava o status do pedido e dos itens; itens e valores não mudam depois da criação
+func (r *orderRepository) Update(order *aggregates.Order) error {
+	tx, err := r.db.Instance.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Create order
-	_, err = tx.Exec(`
-		INSERT INTO orders (
-			id,
-			restaurant_id,
-			customer_id,
-			status,
-			total,
-			discount,
-			observation
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`, order.Id, order.RestaurantID, order.CustomerID, order.Status, order.Total, order.TotalDiscount, order.Observation)
-	if err != nil {
+	if err := updateOrder(tx, order); err != nil {
 		return err
 	}
 
-	// Create delivery
-	_, err = tx.Exec(`
-		INSERT INTO order_deliveries (
-			id,
-			order_id,
-			address_alias,
-			address_street,
-			address_number,
-			address_complement,
-			address_neighborhood,
-			address_city,
-			address_state,
-			address_country,
-			address_zip_code,
-			address_lat,
-			address_lng,
-			fee,
-			distance,
-			average_time_minutes,
-			status
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
-	`, order.Delivery.Id, order.Id, order.Delivery.Address.Alias, order.Delivery.Address.Street, order.Delivery.Address.Number,
-		order.Delivery.Address.Complement, order.Delivery.Address.Neighborhood, order.Delivery.Address.City,
-		order.Delivery.Address.State, order.Delivery.Address.Country, order.Delivery.Address.ZipCode,
-		order.Delivery.Address.Lat, order.Delivery.Address.Lng, order.Delivery.Fee, order.Delivery.Distance,
-		order.Delivery.AverageTimeMinutes, order.Delivery.Status)
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	order.Version++
+	order.ClearAuditRecords()
+	return nil
+}
+
+// updateOrder é o Update sem a transação, para quem grava o pedido junto com outro agregado;
+// a versão só deve ser incrementada e a auditoria limpa depois do commit
+func updateOrder(tx *sql.Tx, order *aggregates.Order) error {
+	query := `
+		UPDATE orders SET
+			status = ?,
+			subtotal = ?,
+			discount = ?,
+			total = ?,
+			observation = ?,
+			cancel_reason = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL`
+
+	result, err := tx.Exec(
+		query,
+		order.Status,
+		order.Subtotal,
+		order.Discount,
+		order.Total,
+		order.Observation,
+		nullString(order.CancelReason),
+		order.UpdatedAt,
+		order.Id,
+		order.Version,
+	)
 	if err != nil {
 		return err
 	}
 
-	// Create items
+	if err := checkVersionedUpdate(result, aggregates.OrderAggregateType, order.Id, order.Version); err != nil {
+		return err
+	}
+
+	itemQuery := `
+		UPDATE order_items SET
+			status = ?
+		WHERE id = ? AND order_id = ?`
+
 	for _, item := range order.Items {
-		_, err = tx.Exec(`
-			INSERT INTO order_items (
-				id,
-				order_id,
-				product_id,
-				product_name,
-				product_unit_price,
-				quantity,
-				observation,
-				discount,
-				item_total
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		`, item.Id, order.Id, item.Product.Id, item.ProductName, item.ProductUnitPrice, item.Quantity,
-			item.Observation, item.Discount, item.ItemTotal)
+		_, err := tx.Exec(itemQuery, item.Status, item.Id, order.Id)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Create payments
-	for _, payment := range order.Payments {
-		_, err = tx.Exec(`
-			INSERT INTO order_payments (
-				id,
-				order_id,
-				payment_method,
-				amount,
-				status
-			) VALUES ($1, $2, $3, $4, $5)
-		`, payment.Id, order.Id, payment.PaymentMethod, payment.Amount, payment.Status)
+	if order.Delivery != nil {
+		_, err := tx.Exec(
+			`UPDATE order_deliveries SET status = ? WHERE id = ? AND order_id = ?`,
+			order.Delivery.Status,
+			order.Delivery.Id,
+			order.Id,
+		)
 		if err != nil {
 			return err
 		}
 	}
 
-	return tx.Commit()
+	if err := insertDomainEvents(tx, order); err != nil {
+		return err
+	}
+
+	return insertAuditRecords(tx, order)
 }
 
-func (r *orderRepository) Update(order *aggregates.Order) error {
-	tx, err := r.db.Begin()
+func (r *orderRepository) AddPayment(order *aggregates.Order, payment *aggregates.OrderPayment, tabDebit ports.OrderTabDebit) error {
+	tx, err := r.db.Instance.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Update order
-	query := `
-		UPDATE orders
-		SET total = $1,
-			total_discount = $2,
-			has_been_fully_paid_virtual = $3,
-			updated_at = $4
-		WHERE id = $5 AND deleted_at IS NULL
-	`
-
-	_, err = tx.Exec(
-		query,
-		order.Total,
-		order.TotalDiscount,
-		order.HasBeenFullyPaidVirtual,
+	result, err := tx.Exec(
+		`UPDATE orders SET updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL`,
 		order.UpdatedAt,
 		order.Id,
+		order.Version,
 	)
 	if err != nil {
 		return err
 	}
 
-	// Update delivery
-	err = r.updateOrderDelivery(tx, order.Id, &order.Delivery)
-	if err != nil {
+	if err := checkVersionedUpdate(result, aggregates.OrderAggregateType, order.Id, order.Version); err != nil {
 		return err
 	}
 
-	// Delete existing items and payments
-	err = r.deleteOrderItems(tx, order.Id)
-	if err != nil {
+	if err := createOrderPayment(tx, order.Id, payment); err != nil {
+		return err
+	}
+
+	if tabDebit.Entry != nil {
+		if err := addTabEntry(tx, tabDebit.Tab, tabDebit.Entry); err != nil {
+			return err
+		}
+
+		if err := insertAuditRecords(tx, tabDebit.Tab); err != nil {
+			return err
+		}
+	}
+
+	if err := insertDomainEvents(tx, order); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return err
 	}
 
-	err = r.deleteOrderPayments(tx, order.Id)
+	order.Version++
+	order.ClearAuditRecords()
+	if tabDebit.Entry != nil {
+		tabDebit.Tab.Version++
+		tabDebit.Tab.ClearAuditRecords()
+	}
+	return nil
+}
+
+func (r *orderRepository) Cancel(order *aggregates.Order, cancellation ports.OrderCancellation) error {
+	tx, err := r.db.Instance.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	// Create new items and payments
-	for _, item := range order.Items {
-		err = r.createOrderItem(tx, order.Id, &item)
-		if err != nil {
-			return err
-		}
+	if err := updateOrder(tx, order); err != nil {
+		return err
 	}
 
-	for _, payment := range order.Payments {
-		err = r.createOrderPayment(tx, order.Id, &payment)
-		if err != nil {
+	if err := releasePromotions(tx, cancellation.PromotionIds); err != nil {
+		return err
+	}
+
+	if err := releaseMenuPortions(tx, cancellation.MenuPortions); err != nil {
+		return err
+	}
+
+	if cancellation.LoyaltyRefund != nil {
+		if err := addLoyaltyEntries(tx, cancellation.LoyaltyAccount, cancellation.LoyaltyRefund); err != nil {
 			return err
 		}
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	order.Version++
+	order.ClearAuditRecords()
+	if cancellation.LoyaltyRefund != nil {
+		cancellation.LoyaltyAccount.Version++
+	}
+	return nil
 }
 
-func (r *orderRepository) Delete(id string) error {
+func (r *orderRepository) FindOutstandingBalances(restaurantId string, since time.Time) ([]ports.OrderBalance, error) {
 	query := `
-		UPDATE orders
-		SET deleted_at = $1
-		WHERE id = $2 AND deleted_at IS NULL
-	`
+		SELECT o.id, o.created_at, o.total - COALESCE(SUM(op.amount), 0) AS balance
+		FROM orders o
+		LEFT JOIN order_payments op ON op.order_id = o.id AND op.status = 'PAID'
+		WHERE o.restaurant_id = ?
+		AND o.status <> ?
+		AND o.created_at >= ?
+		AND o.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM customer_tab_entries e WHERE e.order_id = o.id)
+		GROUP BY o.id, o.created_at, o.total
+		HAVING balance > 0
+		ORDER BY o.created_at ASC`
+
+	rows, err := r.db.Instance.Query(query, restaurantId, orderstatus.CANCELLED, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	_, err := r.db.Exec(query, time.Now(), id)
-	return err
+	balances := make([]ports.OrderBalance, 0, 10)
+	for rows.Next() {
+		var balance ports.OrderBalance
+		if err := rows.Scan(&balance.OrderId, &balance.CreatedAt, &balance.Balance); err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+
+	return balances, rows.Err()
 }
 
-func (r *orderRepository) FindByCustomerId(customerId string) ([]aggregates.Order, error) {
+func (r *orderRepository) Delete(order *aggregates.Order) error {
 	query := `
-		SELECT ` + orderBaseFields + `
-		FROM orders
-		WHERE customer_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
-	`
+		UPDATE orders
+		SET deleted_at = NOW()
+		WHERE id = ? AND deleted_at IS NULL`
+	_, err := r.db.Instance.Exec(query, order.Id)
+	return err
+}
 
-	rows, err := r.db.Query(query, customerId)
+func (r *orderRepository) findMany(query string, params ...any) ([]aggregates.Order, error) {
+	rows, err := r.db.Instance.Query(query, params...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var orders []aggregates.Order
+	orders := make([]aggregates.Order, 0, 10)
 	for rows.Next() {
-		var order aggregates.Order
-		var deletedAt sql.NullTime
-		err := rows.Scan(
-			&order.Id,
-			&order.CustomerID,
-			&order.RestaurantID,
-			&order.Total,
-			&order.TotalDiscount,
-			&order.Discount,
-			&order.Observation,
-			&deletedAt,
-		)
+		order, err := scanOrder(rows)
 		if err != nil {
 			return nil, err
 		}
+		orders = append(orders, *order)
+	}
 
-		// Get delivery
-		delivery, err := r.getOrderDelivery(order.Id)
-		if err != nil {
-			return nil, err
-		}
-		order.Delivery = delivery
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
 
-		// Get items
-		items, err := r.getOrderItems(order.Id)
-		if err != nil {
+	for i := range orders {
+		if err := r.loadChildren(&orders[i]); err != nil {
 			return nil, err
 		}
-		order.Items = items
+	}
 
-		// Get payments
-		payments, err := r.getOrderPayments(order.Id)
-		if err != nil {
-			return nil, err
-		}
-		order.Payments = payments
+	return orders, nil
+}
 
-		orders = append(orders, order)
+func (r *orderRepository) loadChildren(order *aggregates.Order) error {
+	items, err := r.findItems(order.Id)
+	if err != nil {
+		return err
 	}
+	order.Items = items
 
-	return orders, nil
+	delivery, err := r.findDelivery(order.Id)
+	if err != nil {
+		return err
+	}
+	order.Delivery = delivery
+
+	payments, err := r.findPayments(order.Id)
+	if err != nil {
+		return err
+	}
+	order.Payments = payments
+
+	promotions, err := r.findPromotions(order.Id)
+	if err != nil {
+		return err
+	}
+	order.Promotions = promotions
+
+	return nil
 }
 
-func (r *orderRepository) FindByRestaurantId(restaurantId string) ([]aggregates.Order, error) {
+func (r *orderRepository) findItems(orderId string) ([]aggregates.OrderItem, error) {
 	query := `
-		SELECT ` + orderBaseFields + `
-		FROM orders
-		WHERE restaurant_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
-	`
+		SELECT
+			` + orderItemBaseFields + `
+		FROM order_items oi
+		WHERE oi.order_id = ?
+		ORDER BY oi.position ASC`
 
-	rows, err := r.db.Query(query, restaurantId)
+	rows, err := r.db.Instance.Query(query, orderId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var orders []aggregates.Order
+	items := make([]aggregates.OrderItem, 0, 5)
 	for rows.Next() {
-		var order aggregates.Order
-		var deletedAt sql.NullTime
+		var item aggregates.OrderItem
+		var observation sql.NullString
 		err := rows.Scan(
-			&order.Id,
-			&order.CustomerID,
-			&order.RestaurantID,
-			&order.Total,
-			&order.TotalDiscount,
-			&order.Discount,
-			&order.Observation,
-			&deletedAt,
+			&item.Id,
+			&item.Product.Id,
+			&item.Product.Name,
+			&item.UnitPrice,
+			&item.Quantity,
+			&item.Discount,
+			&item.Total,
+			&observation,
+			&item.Status,
 		)
 		if err != nil {
 			return nil, err
 		}
+		item.Observation = observation.String
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	lunchboxes, err := r.findLunchboxes(orderId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Lunchbox = lunchboxes[items[i].Id]
+	}
+
+	return items, nil
+}
 
-		// Get delivery
-		delivery, err := r.getOrderDelivery(order.Id)
+// findLunchboxes carrega a composição de todas as marmitas do pedido em uma consulta, indexada pelo item
+func (r *orderRepository) findLunchboxes(orderId string) (map[string]*aggregates.LunchboxComposition, error) {
+	query := `
+		SELECT
+			` + lunchboxSelectionBaseFields + `
+		FROM lunchbox_order_items loi
+		JOIN order_items oi ON loi.order_item_id = oi.id
+		LEFT JOIN lunchbox_selected_menu_items lsmi ON lsmi.lunchbox_order_item_id = loi.id
+		WHERE oi.order_id = ?
+		ORDER BY lsmi.is_additional ASC, lsmi.dish_type ASC`
+
+	rows, err := r.db.Instance.Query(query, orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lunchboxes := make(map[string]*aggregates.LunchboxComposition)
+	for rows.Next() {
+		var orderItemId string
+		var lunchbox aggregates.LunchboxComposition
+		var selectionId, menuItemId, dishId, dishName, dishType sql.NullString
+		var quantity sql.NullInt64
+		var isAdditional sql.NullBool
+		var price sql.NullFloat64
+		err := rows.Scan(
+			&orderItemId,
+			&lunchbox.Id,
+			&lunchbox.WantsFlatware,
+			&selectionId,
+			&menuItemId,
+			&dishId,
+			&dishName,
+			&dishType,
+			&quantity,
+			&isAdditional,
+			&price,
+		)
 		if err != nil {
 			return nil, err
 		}
-		order.Delivery = delivery
 
-		// Get items
-		items, err := r.getOrderItems(order.Id)
-		if err != nil {
-			return nil, err
+		current, ok := lunchboxes[orderItemId]
+		if !ok {
+			lunchbox.Selections = make([]aggregates.LunchboxSelection, 0, 5)
+			current = &lunchbox
+			lunchboxes[orderItemId] = current
 		}
-		order.Items = items
 
-		// Get payments
-		payments, err := r.getOrderPayments(order.Id)
-		if err != nil {
-			return nil, err
+		if selectionId.Valid {
+			current.Selections = append(current.Selections, aggregates.LunchboxSelection{
+				Id:           selectionId.String,
+				MenuItemId:   menuItemId.String,
+				DishId:       dishId.String,
+				DishName:     dishName.String,
+				DishType:     dishtype.DishType(dishType.String),
+				Quantity:     int(quantity.Int64),
+				IsAdditional: isAdditional.Bool,
+				Price:        price.Float64,
+			})
 		}
-		order.Payments = payments
+	}
 
-		orders = append(orders, order)
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return orders, nil
+	return lunchboxes, nil
 }
 
-// Helper methods
-
-func (r *orderRepository) getOrderDelivery(orderId string) (aggregates.OrderDelivery, error) {
+func (r *orderRepository) findDelivery(orderId string) (*aggregates.OrderDelivery, error) {
 	query := `
-		SELECT ` + orderDeliveryFields + `
-		FROM order_deliveries
-		WHERE order_id = $1 AND deleted_at IS NULL
-	`
+		SELECT
+			od.id,
+			od.fee,
+			od.distance,
+			od.average_time_minutes,
+			od.status,
+			od.code,
+			` + AddressFields + `
+		FROM order_deliveries od
+		JOIN addresses a ON od.address_id = a.id
+		WHERE od.order_id = ?`
 
 	var delivery aggregates.OrderDelivery
-	var addressId string
-	var deletedAt sql.NullTime
-	err := r.db.QueryRow(query, orderId).Scan(
+	var code, alias, complement, neighborhood, city, state, country, zipCode sql.NullString
+	var lat, lng sql.NullFloat64
+	err := r.db.Instance.QueryRow(query, orderId).Scan(
 		&delivery.Id,
-		&orderId,
-		&addressId,
 		&delivery.Fee,
 		&delivery.Distance,
 		&delivery.AverageTimeMinutes,
 		&delivery.Status,
-		&delivery.DeliveryDate,
-		&delivery.CreatedAt,
-		&delivery.UpdatedAt,
-		&deletedAt,
+		&code,
+		&delivery.Address.Id,
+		&alias,
+		&delivery.Address.Street,
+		&delivery.Address.Number,
+		&complement,
+		&neighborhood,
+		&city,
+		&state,
+		&country,
+		&zipCode,
+		&lat,
+		&lng,
 	)
 	if err != nil {
-		return aggregates.OrderDelivery{}, err
-	}
-
-	// Get address
-	address, err := r.getAddress(addressId)
-	if err != nil {
-		return aggregates.OrderDelivery{}, err
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
 	}
-	delivery.Address = address
 
-	return delivery, nil
+	delivery.Code = code.String
+	delivery.Address.Alias = alias.String
+	delivery.Address.Complement = complement.String
+	delivery.Address.Neighborhood = neighborhood.String
+	delivery.Address.City = city.String
+	delivery.Address.State = uf.UF(state.String)
+	delivery.Address.Country = country.String
+	delivery.Address.ZipCode = zipCode.String
+	delivery.Address.Lat = lat.Float64
+	delivery.Address.Lng = lng.Float64
+
+	return &delivery, nil
 }
 
-func (r *orderRepository) getOrderItems(orderId string) ([]aggregates.OrderItem, error) {
+func (r *orderRepository) findPayments(orderId string) ([]aggregates.OrderPayment, error) {
 	query := `
-		SELECT ` + orderItemFields + `
-		FROM order_items
-		WHERE order_id = $1 AND deleted_at IS NULL
-	`
+		SELECT
+			` + orderPaymentBaseFields + `
+		FROM order_payments op
+		WHERE op.order_id = ?
+		ORDER BY op.paid_at ASC`
 
-	rows, err := r.db.Query(query, orderId)
+	rows, err := r.db.Instance.Query(query, orderId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var items []aggregates.OrderItem
+	payments := make([]aggregates.OrderPayment, 0, 2)
 	for rows.Next() {
-		var item aggregates.OrderItem
-		var deletedAt sql.NullTime
+		var payment aggregates.OrderPayment
+		var pixKey, cashSessionId sql.NullString
 		err := rows.Scan(
-			&item.Id,
-			&orderId,
-			&item.Product.Id,
-			&item.ProductName,
-			&item.ProductUnitPrice,
-			&item.Quantity,
-			&item.Observation,
-			&item.Discount,
-			&item.ItemTotal,
+			&payment.Id,
+			&payment.PaymentMethod,
+			&payment.Amount,
+			&payment.Status,
+			&payment.PaidAt,
+			&pixKey,
+			&cashSessionId,
 		)
 		if err != nil {
 			return nil, err
 		}
+		payment.PixKey = pixKey.String
+		payment.CashSessionId = cashSessionId.String
+		payments = append(payments, payment)
+	}
 
-		// Get product name
-		product, err := r.getProduct(item.Product.Id)
-		if err != nil {
-			return nil, err
-		}
-		item.Product.Name = product.Name
-
-		items = append(items, item)
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return items, nil
+	return payments, nil
 }
 
-func (r *orderRepository) getOrderPayments(orderId string) ([]aggregates.OrderPayment, error) {
+func (r *orderRepository) findPromotions(orderId string) ([]aggregates.OrderPromotion, error) {
 	query := `
-		SELECT ` + orderPaymentFields + `
-		FROM order_payments
-		WHERE order_id = $1 AND deleted_at IS NULL
-	`
+		SELECT
+			` + orderPromotionBaseFields + `
+		FROM order_promotions opr
+		WHERE opr.order_id = ?`
 
-	rows, err := r.db.Query(query, orderId)
+	rows, err := r.db.Instance.Query(query, orderId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var payments []aggregates.OrderPayment
+	promotions := make([]aggregates.OrderPromotion, 0)
 	for rows.Next() {
-		var payment aggregates.OrderPayment
-		var deletedAt sql.NullTime
+		var promotion aggregates.OrderPromotion
+		var orderItemId, code sql.NullString
 		err := rows.Scan(
-			&payment.Id,
-			&orderId,
-			&payment.PaymentMethod,
-			&payment.Amount,
-			&payment.Status,
+			&promotion.PromotionId,
+			&orderItemId,
+			&promotion.Name,
+			&code,
+			&promotion.Amount,
 		)
 		if err != nil {
 			return nil, err
 		}
+		promotion.OrderItemId = orderItemId.String
+		promotion.Code = code.String
+		promotions = append(promotions, promotion)
+	}
 
-		payments = append(payments, payment)
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return payments, nil
+	return promotions, nil
 }
 
-func (r *orderRepository) createOrderDelivery(tx *sql.Tx, orderId string, delivery *aggregates.OrderDelivery) error {
+func createOrderItem(tx *sql.Tx, orderId string, position int, item *aggregates.OrderItem) error {
 	query := `
-		INSERT INTO order_deliveries (
-			id,
-			order_id,
-			address_id,
-			fee,
-			distance,
-			average_time_minutes,
-			status,
-			delivery_date,
-			created_at,
-			updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-	`
+		INSERT INTO order_items (
+			id, order_id, position, product_id, product_name, unit_price,
+			quantity, discount, total, observation, status
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := tx.Exec(
 		query,
-		delivery.Id,
+		item.Id,
 		orderId,
-		delivery.Address.Id,
-		delivery.Fee,
-		delivery.Distance,
-		delivery.AverageTimeMinutes,
-		delivery.Status,
-		delivery.DeliveryDate,
-		delivery.CreatedAt,
-		delivery.UpdatedAt,
+		position,
+		item.Product.Id,
+		item.Product.Name,
+		item.UnitPrice,
+		item.Quantity,
+		item.Discount,
+		item.Total,
+		item.Observation,
+		item.Status,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if item.Lunchbox == nil {
+		return nil
+	}
+
+	lunchboxQuery := `
+		INSERT INTO lunchbox_order_items (
+			id, order_item_id, wants_flatware
+		) VALUES (?, ?, ?)`
+
+	_, err = tx.Exec(lunchboxQuery, item.Lunchbox.Id, item.Id, item.Lunchbox.WantsFlatware)
+	if err != nil {
+		return err
+	}
+
+	selectionQuery := `
+		INSERT INTO lunchbox_selected_menu_items (
+			id, lunchbox_order_item_id, menu_item_id, dish_id, dish_name, dish_type,
+			quantity, is_additional, price
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for _, selection := range item.Lunchbox.Selections {
+		_, err = tx.Exec(
+			selectionQuery,
+			selection.Id,
+			item.Lunchbox.Id,
+			selection.MenuItemId,
+			nullString(selection.DishId),
+			selection.DishName,
+			selection.DishType,
+			selection.Quantity,
+			selection.IsAdditional,
+			selection.Price,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (r *orderRepository) createOrderItem(tx *sql.Tx, orderId string, item *aggregates.OrderItem) error {
-	query := `
-		INSERT INTO order_items (
-			id,
-			order_id,
-			product_id,
-			product_unit_price,
-			product_name,
-			quantity,
-			observation,
-			discount,
-			item_total,
-			created_at,
-			updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-	`
+// o endereço de entrega é copiado para o pedido, alterações no cadastro do cliente não o afetam
+func createOrderDelivery(tx *sql.Tx, orderId string, delivery *aggregates.OrderDelivery) error {
+	delivery.Address.Id = uuid.NewString()
+
+	addressQuery := `
+		INSERT INTO addresses (
+			id, alias, street, number, complement, neighborhood,
+			city, state, country, zip_code, lat, lng
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := tx.Exec(
-		query,
-		item.Id,
+		addressQuery,
+		delivery.Address.Id,
+		delivery.Address.Alias,
+		delivery.Address.Street,
+		delivery.Address.Number,
+		delivery.Address.Complement,
+		delivery.Address.Neighborhood,
+		delivery.Address.City,
+		delivery.Address.State,
+		delivery.Address.Country,
+		delivery.Address.ZipCode,
+		delivery.Address.Lat,
+		delivery.Address.Lng,
+	)
+	if err != nil {
+		return err
+	}
+
+	deliveryQuery := `
+		INSERT INTO order_deliveries (
+			id, order_id, address_id, fee, distance, average_time_minutes, status, code
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(
+		deliveryQuery,
+		delivery.Id,
 		orderId,
-		item.Product.Id,
-		item.ProductUnitPrice,
-		item.ProductName,
-		item.Quantity,
-		item.Observation,
-		item.Discount,
-		item.ItemTotal,
-		item.CreatedAt,
-		item.UpdatedAt,
+		delivery.Address.Id,
+		delivery.Fee,
+		delivery.Distance,
+		delivery.AverageTimeMinutes,
+		delivery.Status,
+		nullString(delivery.Code),
 	)
 	return err
 }
 
-func (r *orderRepository) createOrderPayment(tx *sql.Tx, orderId string, payment *aggregates.OrderPayment) error {
+func createOrderPayment(tx *sql.Tx, orderId string, payment *aggregates.OrderPayment) error {
 	query := `
 		INSERT INTO order_payments (
-			id,
-			order_id,
-			payment_method,
-			amount,
-			status,
-			payment_date,
-			created_at,
-			updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`
+			id, order_id, payment_method, amount, status, paid_at, pix_key, cash_session_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := tx.Exec(
 		query,
@@ -689,98 +916,112 @@ func (r *orderRepository) createOrderPayment(tx *sql.Tx, orderId string, payment
 		payment.PaymentMethod,
 		payment.Amount,
 		payment.Status,
-		payment.PaymentDate,
-		payment.CreatedAt,
-		payment.UpdatedAt,
+		payment.PaidAt,
+		nullString(payment.PixKey),
+		nullString(payment.CashSessionId),
 	)
 	return err
 }
 
-func (r *orderRepository) updateOrderDelivery(tx *sql.Tx, orderId string, delivery *aggregates.OrderDelivery) error {
+// createOrderPromotions grava o detalhamento dos descontos e consome um uso de cada promoção;
+// o limite global é conferido na mesma transação, então dois pedidos não passam do limite
+func createOrderPromotions(tx *sql.Tx, order *aggregates.Order) error {
 	query := `
-		UPDATE order_deliveries
-		SET address_id = $1,
-			fee = $2,
-			distance = $3,
-			average_time_minutes = $4,
-			status = $5,
-			delivery_date = $6,
-			updated_at = $7
-		WHERE order_id = $8 AND deleted_at IS NULL
-	`
+		INSERT INTO order_promotions (
+			id, order_id, promotion_id, order_item_id, name, code, amount
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	for _, promotion := range order.Promotions {
+		_, err := tx.Exec(
+			query,
+			uuid.NewString(),
+			order.Id,
+			promotion.PromotionId,
+			nullString(promotion.OrderItemId),
+			promotion.Name,
+			nullString(promotion.Code),
+			promotion.Amount,
+		)
+		if err != nil {
+			return err
+		}
+	}
 
-	_, err := tx.Exec(
-		query,
-		delivery.Address.Id,
-		delivery.Fee,
-		delivery.Distance,
-		delivery.AverageTimeMinutes,
-		delivery.Status,
-		delivery.DeliveryDate,
-		delivery.UpdatedAt,
-		orderId,
-	)
-	return err
-}
+	redeemQuery := `
+		UPDATE promotions
+		SET used_count = used_count + 1
+		WHERE id = ? AND (max_uses = 0 OR used_count < max_uses)`
 
-func (r *orderRepository) deleteOrderItems(tx *sql.Tx, orderId string) error {
-	query := `
-		UPDATE order_items
-		SET deleted_at = $1
-		WHERE order_id = $2 AND deleted_at IS NULL
-	`
+	for _, promotionId := range order.PromotionIds() {
+		result, err := tx.Exec(redeemQuery, promotionId)
+		if err != nil {
+			return err
+		}
 
-	_, err := tx.Exec(query, time.Now(), orderId)
-	return err
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return &ports.PromotionExhaustedError{PromotionId: promotionId}
+		}
+	}
+
+	return nil
 }
 
-func (r *orderRepository) deleteOrderPayments(tx *sql.Tx, orderId string) error {
+// releasePromotions devolve um uso de cada promoção do pedido cancelado
+func releasePromotions(tx *sql.Tx, promotionIds []string) error {
 	query := `
-		UPDATE order_payments
-		SET deleted_at = $1
-		WHERE order_id = $2 AND deleted_at IS NULL
-	`
+		UPDATE promotions
+		SET used_count = used_count - 1
+		WHERE id = ? AND used_count > 0`
 
-	_, err := tx.Exec(query, time.Now(), orderId)
-	return err
+	for _, id := range promotionIds {
+		if _, err := tx.Exec(query, id); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (r *orderRepository) getAddress(id string) (types.Address, error) {
-	query := `
-		SELECT id, alias, street, number, complement, neighborhood, city, state, country, zip_code, lat, lng
-		FROM addresses
-		WHERE id = $1 AND deleted_at IS NULL
-	`
-
-	var address types.Address
-	err := r.db.QueryRow(query, id).Scan(
-		&address.Id,
-		&address.Alias,
-		&address.Street,
-		&address.Number,
-		&address.Complement,
-		&address.Neighborhood,
-		&address.City,
-		&address.State,
-		&address.Country,
-		&address.ZipCode,
-		&address.Lat,
-		&address.Lng,
+func scanOrder(row rowScanner) (*aggregates.Order, error) {
+	var order aggregates.Order
+	var customerId, firstName, lastName, email, observation, cancelReason, externalId, externalCode sql.NullString
+	err := row.Scan(
+		&order.Id,
+		&order.Restaurant.Id,
+		&customerId,
+		&firstName,
+		&lastName,
+		&email,
+		&order.Status,
+		&order.Subtotal,
+		&order.Discount,
+		&order.Total,
+		&observation,
+		&cancelReason,
+		&order.Channel,
+		&externalId,
+		&externalCode,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+		&order.Version,
 	)
-	return address, err
-}
+	if err != nil {
+		return nil, err
+	}
 
-func (r *orderRepository) getProduct(id string) (aggregates.PartialProduct, error) {
-	query := `
-		SELECT id, name
-		FROM products
-		WHERE id = $1 AND deleted_at IS NULL
-	`
-
-	var product aggregates.PartialProduct
-	err := r.db.QueryRow(query, id).Scan(
-		&product.Id,
-		&product.Name,
-	)
-	return product, err
+	order.Customer = aggregates.PartialCustomer{
+		Id:        customerId.String,
+		FirstName: firstName.String,
+		LastName:  lastName.String,
+		Email:     email.String,
+	}
+	order.Observation = observation.String
+	order.CancelReason = cancelReason.String
+	order.ExternalId = externalId.String
+	order.ExternalCode = externalCode.String
+
+	return &order, nil
 }
@@ -0,0 +1,57 @@
+package respositories
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type priorityEntry struct {
+	id      string
+	version int
+}
+
+// updatePriorities grava a ordem recebida como prioridades densas (1..n) dentro da transação,
+// com compare-and-swap por versão; table e scopeColumn são sempre constantes dos repositórios.
+func updatePriorities(tx *sql.Tx, table, scopeColumn, scopeId, aggregateType string, entries []priorityEntry) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			priority = ?,
+			version = version + 1
+		WHERE id = ? AND %s = ? AND version = ? AND deleted_at IS NULL`, table, scopeColumn)
+
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, entry := range entries {
+		result, err := stmt.Exec(i+1, entry.id, scopeId, entry.version)
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersionedUpdate(result, aggregateType, entry.id, entry.version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextPriority devolve a prioridade que coloca o novo registro no fim da lista; o FOR UPDATE
+// trava o escopo até o commit, então dois cadastros simultâneos não ficam com o mesmo valor
+func nextPriority(tx *sql.Tx, table, scopeColumn, scopeId string) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT COALESCE(MAX(priority), 0) + 1
+		FROM %s
+		WHERE %s = ? AND deleted_at IS NULL
+		FOR UPDATE`, table, scopeColumn)
+
+	var priority int
+	if err := tx.QueryRow(query, scopeId).Scan(&priority); err != nil {
+		return 0, err
+	}
+
+	return priority, nil
+}
@@ -31,7 +31,13 @@ const (
 		p.dish_type_map,
 		p.active,
 		p.category_id,
-		p.restaurant_id`
+		p.restaurant_id,
+		p.priority,
+		p.ncm,
+		p.cfop,
+		p.csosn,
+		p.origin,
+		p.version`
 )
 
 func (r *productRepository) Find(args types.FindArgs) (*types.PagedSlice[aggregates.Product], error) {
@@ -69,6 +75,12 @@ func (r *productRepository) Find(args types.FindArgs) (*types.PagedSlice[aggrega
 			&product.Active,
 			&product.Category.Id,
 			&product.Restaurant.Id,
+			&product.Priority,
+			&product.Fiscal.Ncm,
+			&product.Fiscal.Cfop,
+			&product.Fiscal.Csosn,
+			&product.Fiscal.Origin,
+			&product.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -109,6 +121,12 @@ func (r *productRepository) FindById(id string) (*aggregates.Product, error) {
 		&product.Active,
 		&product.Category.Id,
 		&product.Restaurant.Id,
+		&product.Priority,
+		&product.Fiscal.Ncm,
+		&product.Fiscal.Cfop,
+		&product.Fiscal.Csosn,
+		&product.Fiscal.Origin,
+		&product.Version,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -133,10 +151,21 @@ func (r *productRepository) Create(product *aggregates.Product) error {
 	query := `
 		INSERT INTO products (
 			id, name, description, sales_price, cost_price, picture_url,
-			dish_type_map, active, category_id, restaurant_id
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			dish_type_map, active, category_id, restaurant_id, ncm, cfop, csosn, origin, priority
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err = r.database.Instance.Exec(
+	tx, err := r.database.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	priority, err := nextPriority(tx, "products", "category_id", product.Category.Id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
 		query,
 		product.Id,
 		product.Name,
@@ -148,8 +177,31 @@ func (r *productRepository) Create(product *aggregates.Product) error {
 		product.Active,
 		product.Category.Id,
 		product.Restaurant.Id,
+		product.Fiscal.Ncm,
+		product.Fiscal.Cfop,
+		product.Fiscal.Csosn,
+		product.Fiscal.Origin,
+		priority,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := insertDomainEvents(tx, product); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, product); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	product.Priority = priority
+	product.ClearAuditRecords()
+	return nil
 }
 
 func (r *productRepository) Update(product *aggregates.Product) error {
@@ -167,10 +219,21 @@ func (r *productRepository) Update(product *aggregates.Product) error {
 			picture_url = ?,
 			dish_type_map = ?,
 			active = ?,
-			category_id = ?
-		WHERE id = ? AND deleted_at IS NULL`
+			category_id = ?,
+			ncm = ?,
+			cfop = ?,
+			csosn = ?,
+			origin = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL`
+
+	tx, err := r.database.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	_, err = r.database.Instance.Exec(
+	result, err := tx.Exec(
 		query,
 		product.Name,
 		product.Description,
@@ -180,18 +243,65 @@ func (r *productRepository) Update(product *aggregates.Product) error {
 		dishTypeMapJSON,
 		product.Active,
 		product.Category.Id,
+		product.Fiscal.Ncm,
+		product.Fiscal.Cfop,
+		product.Fiscal.Csosn,
+		product.Fiscal.Origin,
 		product.Id,
+		product.Version,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.ProductAggregateType, product.Id, product.Version); err != nil {
+		return err
+	}
+
+	if err := insertDomainEvents(tx, product); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, product); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	product.Version++
+	product.ClearAuditRecords()
+	return nil
 }
 
-func (r *productRepository) Delete(id string) error {
+func (r *productRepository) Delete(product *aggregates.Product) error {
 	query := `
 		UPDATE products 
 		SET deleted_at = NOW() 
 		WHERE id = ? AND deleted_at IS NULL`
-	_, err := r.database.Instance.Exec(query, id)
-	return err
+
+	tx, err := r.database.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query, product.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, product); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	product.ClearAuditRecords()
+	return nil
 }
 
 func (r *productRepository) Exists(name, restaurantId string) (bool, error) {
@@ -210,3 +320,148 @@ func (r *productRepository) Exists(name, restaurantId string) (bool, error) {
 
 	return count > 0, nil
 }
+
+func (r *productRepository) FindByRestaurantId(restaurantId string) ([]aggregates.Product, error) {
+	query := `
+		SELECT 
+			` + productBaseFields + `
+		FROM products p
+		WHERE p.deleted_at IS NULL 
+		AND p.restaurant_id = ?
+		ORDER BY p.priority ASC, p.name ASC`
+
+	rows, err := r.database.Instance.Query(query, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]aggregates.Product, 0, 10)
+	for rows.Next() {
+		var product aggregates.Product
+		var dishTypeMapJSON []byte
+		err := rows.Scan(
+			&product.Id,
+			&product.Name,
+			&product.Description,
+			&product.SalesPrice,
+			&product.CostPrice,
+			&product.PictureUrl,
+			&dishTypeMapJSON,
+			&product.Active,
+			&product.Category.Id,
+			&product.Restaurant.Id,
+			&product.Priority,
+			&product.Fiscal.Ncm,
+			&product.Fiscal.Cfop,
+			&product.Fiscal.Csosn,
+			&product.Fiscal.Origin,
+			&product.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(dishTypeMapJSON, &product.DishTypeMap); err != nil {
+			return nil, err
+		}
+
+		products = append(products, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *productRepository) FindByCategoryId(categoryId string) ([]aggregates.Product, error) {
+	query := `
+		SELECT 
+			` + productBaseFields + `
+		FROM products p
+		WHERE p.deleted_at IS NULL 
+		AND p.category_id = ?
+		ORDER BY p.priority ASC, p.name ASC`
+
+	rows, err := r.database.Instance.Query(query, categoryId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]aggregates.Product, 0, 10)
+	for rows.Next() {
+		var product aggregates.Product
+		var dishTypeMapJSON []byte
+		err := rows.Scan(
+			&product.Id,
+			&product.Name,
+			&product.Description,
+			&product.SalesPrice,
+			&product.CostPrice,
+			&product.PictureUrl,
+			&dishTypeMapJSON,
+			&product.Active,
+			&product.Category.Id,
+			&product.Restaurant.Id,
+			&product.Priority,
+			&product.Fiscal.Ncm,
+			&product.Fiscal.Cfop,
+			&product.Fiscal.Csosn,
+			&product.Fiscal.Origin,
+			&product.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(dishTypeMapJSON, &product.DishTypeMap); err != nil {
+			return nil, err
+		}
+
+		products = append(products, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *productRepository) Reorder(categoryId string, products []aggregates.Product) error {
+	entries := make([]priorityEntry, 0, len(products))
+	for _, product := range products {
+		entries = append(entries, priorityEntry{id: product.Id, version: product.Version})
+	}
+
+	tx, err := r.database.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = updatePriorities(tx, "products", "category_id", categoryId, aggregates.ProductAggregateType, entries)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		if err := insertAuditRecords(tx, &products[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for i := range products {
+		products[i].Priority = i + 1
+		products[i].Version++
+		products[i].ClearAuditRecords()
+	}
+	return nil
+}
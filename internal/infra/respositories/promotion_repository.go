@@ -0,0 +1,426 @@
+package respositories
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+)
+
+type promotionRepository struct {
+	db *database.Db
+}
+
+func NewPromotionRepository(db *database.Db) ports.IPromotionRepository {
+	return &promotionRepository{
+		db: db,
+	}
+}
+
+const (
+	promotionBaseFields = `
+		pr.id,
+		pr.restaurant_id,
+		pr.name,
+		pr.description,
+		pr.code,
+		pr.discount_type,
+		pr.value,
+		pr.min_order_value,
+		pr.weekdays,
+		pr.starts_at,
+		pr.ends_at,
+		pr.max_uses,
+		pr.max_uses_per_customer,
+		pr.used_count,
+		pr.first_order_only,
+		pr.active,
+		pr.created_at,
+		pr.updated_at,
+		pr.version`
+)
+
+func (r *promotionRepository) FindById(id string) (*aggregates.Promotion, error) {
+	query := `
+		SELECT
+			` + promotionBaseFields + `
+		FROM promotions pr
+		WHERE pr.id = ?`
+
+	return r.findOne(query, id)
+}
+
+func (r *promotionRepository) FindByCode(restaurantId, code string) (*aggregates.Promotion, error) {
+	query := `
+		SELECT
+			` + promotionBaseFields + `
+		FROM promotions pr
+		WHERE pr.restaurant_id = ? AND pr.code = ?`
+
+	return r.findOne(query, restaurantId, code)
+}
+
+func (r *promotionRepository) FindByRestaurantId(restaurantId string) ([]aggregates.Promotion, error) {
+	query := `
+		SELECT
+			` + promotionBaseFields + `
+		FROM promotions pr
+		WHERE pr.restaurant_id = ?
+		ORDER BY pr.created_at DESC`
+
+	return r.findMany(query, restaurantId)
+}
+
+func (r *promotionRepository) FindAutomatic(restaurantId string) ([]aggregates.Promotion, error) {
+	query := `
+		SELECT
+			` + promotionBaseFields + `
+		FROM promotions pr
+		WHERE pr.restaurant_id = ? AND pr.code IS NULL AND pr.active = TRUE
+		ORDER BY pr.created_at ASC`
+
+	return r.findMany(query, restaurantId)
+}
+
+func (r *promotionRepository) CountRedemptions(promotionId, customerId string) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT op.order_id)
+		FROM order_promotions op
+		JOIN orders o ON op.order_id = o.id
+		WHERE op.promotion_id = ?
+		AND o.customer_id = ?
+		AND o.status <> ?
+		AND o.deleted_at IS NULL`
+
+	var count int
+	err := r.db.Instance.QueryRow(query, promotionId, customerId, orderstatus.CANCELLED).Scan(&count)
+	return count, err
+}
+
+func (r *promotionRepository) Create(promotion *aggregates.Promotion) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO promotions (
+			id, restaurant_id, name, description, code, discount_type, value, min_order_value,
+			weekdays, starts_at, ends_at, max_uses, max_uses_per_customer, used_count,
+			first_order_only, active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(
+		query,
+		promotion.Id,
+		promotion.Restaurant.Id,
+		promotion.Name,
+		promotion.Description,
+		nullString(promotion.Code),
+		promotion.DiscountType,
+		promotion.Value,
+		promotion.MinOrderValue,
+		formatWeekdays(promotion.Weekdays),
+		promotion.StartsAt,
+		promotion.EndsAt,
+		promotion.MaxUses,
+		promotion.MaxUsesPerCustomer,
+		promotion.UsedCount,
+		promotion.FirstOrderOnly,
+		promotion.Active,
+		promotion.CreatedAt,
+		promotion.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := createPromotionTargets(tx, promotion); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, promotion); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	promotion.ClearAuditRecords()
+	return nil
+}
+
+// Update não grava used_count, que só muda pelo pedido (Create) e por Release
+func (r *promotionRepository) Update(promotion *aggregates.Promotion) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE promotions SET
+			name = ?,
+			description = ?,
+			code = ?,
+			discount_type = ?,
+			value = ?,
+			min_order_value = ?,
+			weekdays = ?,
+			starts_at = ?,
+			ends_at = ?,
+			max_uses = ?,
+			max_uses_per_customer = ?,
+			first_order_only = ?,
+			active = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	result, err := tx.Exec(
+		query,
+		promotion.Name,
+		promotion.Description,
+		nullString(promotion.Code),
+		promotion.DiscountType,
+		promotion.Value,
+		promotion.MinOrderValue,
+		formatWeekdays(promotion.Weekdays),
+		promotion.StartsAt,
+		promotion.EndsAt,
+		promotion.MaxUses,
+		promotion.MaxUsesPerCustomer,
+		promotion.FirstOrderOnly,
+		promotion.Active,
+		promotion.UpdatedAt,
+		promotion.Id,
+		promotion.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.PromotionAggregateType, promotion.Id, promotion.Version); err != nil {
+		return err
+	}
+
+	for _, table := range []string{"promotion_products", "promotion_combo_items"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE promotion_id = ?`, promotion.Id); err != nil {
+			return err
+		}
+	}
+
+	if err := createPromotionTargets(tx, promotion); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, promotion); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	promotion.Version++
+	promotion.ClearAuditRecords()
+	return nil
+}
+
+func (r *promotionRepository) Delete(promotion *aggregates.Promotion) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM promotions WHERE id = ?`, promotion.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, promotion); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	promotion.ClearAuditRecords()
+	return nil
+}
+
+func (r *promotionRepository) findOne(query string, params ...any) (*aggregates.Promotion, error) {
+	promotion, err := scanPromotion(r.db.Instance.QueryRow(query, params...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := r.loadTargets(promotion); err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (r *promotionRepository) findMany(query string, params ...any) ([]aggregates.Promotion, error) {
+	rows, err := r.db.Instance.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := make([]aggregates.Promotion, 0, 10)
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *promotion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range promotions {
+		if err := r.loadTargets(&promotions[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return promotions, nil
+}
+
+func (r *promotionRepository) loadTargets(promotion *aggregates.Promotion) error {
+	rows, err := r.db.Instance.Query(`SELECT product_id FROM promotion_products WHERE promotion_id = ?`, promotion.Id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	promotion.ProductIds = make([]string, 0)
+	for rows.Next() {
+		var productId string
+		if err := rows.Scan(&productId); err != nil {
+			return err
+		}
+		promotion.ProductIds = append(promotion.ProductIds, productId)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	comboRows, err := r.db.Instance.Query(`SELECT product_id, quantity FROM promotion_combo_items WHERE promotion_id = ?`, promotion.Id)
+	if err != nil {
+		return err
+	}
+	defer comboRows.Close()
+
+	promotion.Combo = make([]aggregates.PromotionRequirement, 0)
+	for comboRows.Next() {
+		var requirement aggregates.PromotionRequirement
+		if err := comboRows.Scan(&requirement.ProductId, &requirement.Quantity); err != nil {
+			return err
+		}
+		promotion.Combo = append(promotion.Combo, requirement)
+	}
+
+	return comboRows.Err()
+}
+
+func createPromotionTargets(tx *sql.Tx, promotion *aggregates.Promotion) error {
+	for _, productId := range promotion.ProductIds {
+		_, err := tx.Exec(`INSERT INTO promotion_products (promotion_id, product_id) VALUES (?, ?)`, promotion.Id, productId)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, requirement := range promotion.Combo {
+		_, err := tx.Exec(
+			`INSERT INTO promotion_combo_items (promotion_id, product_id, quantity) VALUES (?, ?, ?)`,
+			promotion.Id,
+			requirement.ProductId,
+			requirement.Quantity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanPromotion(row rowScanner) (*aggregates.Promotion, error) {
+	var promotion aggregates.Promotion
+	var description, code sql.NullString
+	var weekdays string
+	var startsAt, endsAt sql.NullTime
+	err := row.Scan(
+		&promotion.Id,
+		&promotion.Restaurant.Id,
+		&promotion.Name,
+		&description,
+		&code,
+		&promotion.DiscountType,
+		&promotion.Value,
+		&promotion.MinOrderValue,
+		&weekdays,
+		&startsAt,
+		&endsAt,
+		&promotion.MaxUses,
+		&promotion.MaxUsesPerCustomer,
+		&promotion.UsedCount,
+		&promotion.FirstOrderOnly,
+		&promotion.Active,
+		&promotion.CreatedAt,
+		&promotion.UpdatedAt,
+		&promotion.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	promotion.Description = description.String
+	promotion.Code = code.String
+	promotion.Weekdays = parseWeekdays(weekdays)
+	if startsAt.Valid {
+		promotion.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		promotion.EndsAt = &endsAt.Time
+	}
+
+	return &promotion, nil
+}
+
+func formatWeekdays(weekdays []time.Weekday) string {
+	values := make([]string, 0, len(weekdays))
+	for _, weekday := range weekdays {
+		values = append(values, strconv.Itoa(int(weekday)))
+	}
+	return strings.Join(values, ",")
+}
+
+func parseWeekdays(value string) []time.Weekday {
+	weekdays := make([]time.Weekday, 0, 7)
+	for _, part := range strings.Split(value, ",") {
+		weekday, err := strconv.Atoi(part)
+		if err == nil {
+			weekdays = append(weekdays, time.Weekday(weekday))
+		}
+	}
+	return weekdays
+}
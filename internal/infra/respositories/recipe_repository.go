@@ -0,0 +1,236 @@
+package respositories
+
+import (
+	"database/sql"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+type recipeRepository struct {
+	db *database.Db
+}
+
+func NewRecipeRepository(db *database.Db) ports.IRecipeRepository {
+	return &recipeRepository{
+		db: db,
+	}
+}
+
+const (
+	recipeBaseFields = `
+		rc.id,
+		rc.restaurant_id,
+		rc.product_id,
+		rc.dish_id,
+		rc.updated_at,
+		rc.version`
+)
+
+func (r *recipeRepository) FindByProductId(productId string) (*aggregates.Recipe, error) {
+	query := `
+		SELECT
+			` + recipeBaseFields + `
+		FROM recipes rc
+		WHERE rc.product_id = ?`
+
+	return r.findOne(query, productId)
+}
+
+func (r *recipeRepository) FindByDishId(dishId string) (*aggregates.Recipe, error) {
+	query := `
+		SELECT
+			` + recipeBaseFields + `
+		FROM recipes rc
+		WHERE rc.dish_id = ?`
+
+	return r.findOne(query, dishId)
+}
+
+func (r *recipeRepository) FindByRestaurantId(restaurantId string) ([]aggregates.Recipe, error) {
+	query := `
+		SELECT
+			` + recipeBaseFields + `
+		FROM recipes rc
+		WHERE rc.restaurant_id = ?`
+
+	rows, err := r.db.Instance.Query(query, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := make([]aggregates.Recipe, 0, 10)
+	for rows.Next() {
+		recipe, err := scanRecipe(rows)
+		if err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, *recipe)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range recipes {
+		if err := r.loadItems(&recipes[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return recipes, nil
+}
+
+func (r *recipeRepository) Create(recipe *aggregates.Recipe) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO recipes (id, restaurant_id, product_id, dish_id, updated_at)
+		VALUES (?, ?, ?, ?, ?)`
+
+	_, err = tx.Exec(
+		query,
+		recipe.Id,
+		recipe.Restaurant.Id,
+		nullString(recipe.ProductId),
+		nullString(recipe.DishId),
+		recipe.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := createRecipeItems(tx, recipe); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, recipe); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	recipe.ClearAuditRecords()
+	return nil
+}
+
+func (r *recipeRepository) Update(recipe *aggregates.Recipe) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`UPDATE recipes SET updated_at = ?, version = version + 1 WHERE id = ? AND version = ?`,
+		recipe.UpdatedAt,
+		recipe.Id,
+		recipe.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.RecipeAggregateType, recipe.Id, recipe.Version); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM recipe_items WHERE recipe_id = ?`, recipe.Id); err != nil {
+		return err
+	}
+
+	if err := createRecipeItems(tx, recipe); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, recipe); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	recipe.Version++
+	recipe.ClearAuditRecords()
+	return nil
+}
+
+func (r *recipeRepository) findOne(query string, params ...any) (*aggregates.Recipe, error) {
+	recipe, err := scanRecipe(r.db.Instance.QueryRow(query, params...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := r.loadItems(recipe); err != nil {
+		return nil, err
+	}
+
+	return recipe, nil
+}
+
+func (r *recipeRepository) loadItems(recipe *aggregates.Recipe) error {
+	rows, err := r.db.Instance.Query(`SELECT stock_item_id, quantity FROM recipe_items WHERE recipe_id = ?`, recipe.Id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	recipe.Items = make([]aggregates.RecipeItem, 0)
+	for rows.Next() {
+		var item aggregates.RecipeItem
+		if err := rows.Scan(&item.StockItemId, &item.Quantity); err != nil {
+			return err
+		}
+		recipe.Items = append(recipe.Items, item)
+	}
+
+	return rows.Err()
+}
+
+func createRecipeItems(tx *sql.Tx, recipe *aggregates.Recipe) error {
+	for _, item := range recipe.Items {
+		_, err := tx.Exec(
+			`INSERT INTO recipe_items (recipe_id, stock_item_id, quantity) VALUES (?, ?, ?)`,
+			recipe.Id,
+			item.StockItemId,
+			item.Quantity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanRecipe(row rowScanner) (*aggregates.Recipe, error) {
+	var recipe aggregates.Recipe
+	var productId, dishId sql.NullString
+	err := row.Scan(
+		&recipe.Id,
+		&recipe.Restaurant.Id,
+		&productId,
+		&dishId,
+		&recipe.UpdatedAt,
+		&recipe.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe.ProductId = productId.String
+	recipe.DishId = dishId.String
+	return &recipe, nil
+}
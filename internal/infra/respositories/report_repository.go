@@ -0,0 +1,275 @@
+package respositories
+
+import (
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+)
+
+type reportRepository struct {
+	db *database.Db
+}
+
+func NewReportRepository(db *database.Db) ports.IReportRepository {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+const (
+	reportOrderFilter = `
+		o.restaurant_id = ?
+		AND o.deleted_at IS NULL
+		AND o.created_at >= ?
+		AND o.created_at < ?`
+
+	reportBucketLayout = "2006-01-02 15:04:05"
+)
+
+func (r *reportRepository) Totals(query ports.ReportQuery) (*ports.OrderTotals, error) {
+	sqlQuery := `
+		SELECT
+			COUNT(CASE WHEN o.status <> ? THEN 1 END),
+			COUNT(CASE WHEN o.status = ? THEN 1 END),
+			COUNT(CASE WHEN o.status <> ? AND od.id IS NOT NULL THEN 1 END),
+			COALESCE(SUM(CASE WHEN o.status <> ? THEN o.subtotal END), 0),
+			COALESCE(SUM(CASE WHEN o.status <> ? THEN o.discount END), 0),
+			COALESCE(SUM(CASE WHEN o.status <> ? THEN o.total END), 0)
+		FROM orders o
+		LEFT JOIN order_deliveries od ON od.order_id = o.id
+		WHERE ` + reportOrderFilter
+
+	var totals ports.OrderTotals
+	err := r.db.Instance.QueryRow(
+		sqlQuery,
+		orderstatus.CANCELLED,
+		orderstatus.CANCELLED,
+		orderstatus.CANCELLED,
+		orderstatus.CANCELLED,
+		orderstatus.CANCELLED,
+		orderstatus.CANCELLED,
+		query.RestaurantId,
+		query.From,
+		query.To,
+	).Scan(
+		&totals.Orders,
+		&totals.Cancelled,
+		&totals.Deliveries,
+		&totals.Gross,
+		&totals.Discount,
+		&totals.Net,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &totals, nil
+}
+
+func (r *reportRepository) SalesByHour(query ports.ReportQuery) ([]ports.SalesBucket, error) {
+	sqlQuery := `
+		SELECT
+			DATE_FORMAT(o.created_at, '%Y-%m-%d %H:00:00') AS bucket,
+			COUNT(*),
+			SUM(o.subtotal),
+			SUM(o.discount),
+			SUM(o.total)
+		FROM orders o
+		WHERE ` + reportOrderFilter + `
+		AND o.status <> ?
+		GROUP BY bucket
+		ORDER BY bucket`
+
+	rows, err := r.db.Instance.Query(sqlQuery, query.RestaurantId, query.From, query.To, orderstatus.CANCELLED)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := make([]ports.SalesBucket, 0, 24)
+	for rows.Next() {
+		var bucket ports.SalesBucket
+		var start string
+		if err := rows.Scan(&start, &bucket.Orders, &bucket.Gross, &bucket.Discount, &bucket.Net); err != nil {
+			return nil, err
+		}
+
+		// a conexão usa loc=Local, então o DATETIME gravado está no fuso do servidor
+		bucket.Start, err = time.ParseInLocation(reportBucketLayout, start, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, rows.Err()
+}
+
+func (r *reportRepository) SalesByProduct(query ports.ReportQuery) ([]ports.ProductSales, error) {
+	sqlQuery := `
+		SELECT
+			oi.product_id,
+			MAX(oi.product_name),
+			SUM(oi.quantity),
+			SUM(oi.total - oi.discount) AS revenue
+		FROM order_items oi
+		JOIN orders o ON oi.order_id = o.id
+		WHERE ` + reportOrderFilter + `
+		AND o.status <> ?
+		GROUP BY oi.product_id
+		ORDER BY revenue DESC`
+
+	rows, err := r.db.Instance.Query(sqlQuery, query.RestaurantId, query.From, query.To, orderstatus.CANCELLED)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]ports.ProductSales, 0, 10)
+	for rows.Next() {
+		var row ports.ProductSales
+		if err := rows.Scan(&row.ProductId, &row.ProductName, &row.Quantity, &row.Revenue); err != nil {
+			return nil, err
+		}
+		sales = append(sales, row)
+	}
+
+	return sales, rows.Err()
+}
+
+// SalesByCategory usa a categoria atual do produto, não a da época da venda
+func (r *reportRepository) SalesByCategory(query ports.ReportQuery) ([]ports.CategorySales, error) {
+	sqlQuery := `
+		SELECT
+			c.id,
+			c.name,
+			SUM(oi.quantity),
+			SUM(oi.total - oi.discount) AS revenue
+		FROM order_items oi
+		JOIN orders o ON oi.order_id = o.id
+		JOIN products p ON oi.product_id = p.id
+		JOIN categories c ON p.category_id = c.id
+		WHERE ` + reportOrderFilter + `
+		AND o.status <> ?
+		GROUP BY c.id, c.name
+		ORDER BY revenue DESC`
+
+	rows, err := r.db.Instance.Query(sqlQuery, query.RestaurantId, query.From, query.To, orderstatus.CANCELLED)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]ports.CategorySales, 0, 10)
+	for rows.Next() {
+		var row ports.CategorySales
+		if err := rows.Scan(&row.CategoryId, &row.CategoryName, &row.Quantity, &row.Revenue); err != nil {
+			return nil, err
+		}
+		sales = append(sales, row)
+	}
+
+	return sales, rows.Err()
+}
+
+func (r *reportRepository) SalesByPaymentMethod(query ports.ReportQuery) ([]ports.PaymentMethodSales, error) {
+	sqlQuery := `
+		SELECT
+			op.payment_method,
+			COUNT(*),
+			SUM(op.amount) AS amount
+		FROM order_payments op
+		JOIN orders o ON op.order_id = o.id
+		WHERE ` + reportOrderFilter + `
+		AND o.status <> ?
+		AND op.status = 'PAID'
+		GROUP BY op.payment_method
+		ORDER BY amount DESC`
+
+	rows, err := r.db.Instance.Query(sqlQuery, query.RestaurantId, query.From, query.To, orderstatus.CANCELLED)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sales := make([]ports.PaymentMethodSales, 0, 5)
+	for rows.Next() {
+		var row ports.PaymentMethodSales
+		if err := rows.Scan(&row.PaymentMethod, &row.Payments, &row.Amount); err != nil {
+			return nil, err
+		}
+		sales = append(sales, row)
+	}
+
+	return sales, rows.Err()
+}
+
+func (r *reportRepository) TopCustomers(query ports.ReportQuery, limit int) ([]ports.CustomerSales, error) {
+	sqlQuery := `
+		SELECT
+			c.id,
+			c.first_name,
+			c.last_name,
+			COUNT(*),
+			SUM(o.total) AS spent
+		FROM orders o
+		JOIN customers c ON o.customer_id = c.id
+		WHERE ` + reportOrderFilter + `
+		AND o.status <> ?
+		GROUP BY c.id, c.first_name, c.last_name
+		ORDER BY spent DESC
+		LIMIT ?`
+
+	rows, err := r.db.Instance.Query(sqlQuery, query.RestaurantId, query.From, query.To, orderstatus.CANCELLED, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := make([]ports.CustomerSales, 0, limit)
+	for rows.Next() {
+		var row ports.CustomerSales
+		if err := rows.Scan(&row.CustomerId, &row.FirstName, &row.LastName, &row.Orders, &row.Spent); err != nil {
+			return nil, err
+		}
+		customers = append(customers, row)
+	}
+
+	return customers, rows.Err()
+}
+
+// SoldDishPortions ignora as seleções gravadas antes de lunchbox_selected_menu_items ter dish_id
+func (r *reportRepository) SoldDishPortions(query ports.ReportQuery) ([]ports.DishPortions, error) {
+	sqlQuery := `
+		SELECT
+			oi.product_id,
+			lsmi.dish_id,
+			SUM(lsmi.quantity * oi.quantity)
+		FROM lunchbox_selected_menu_items lsmi
+		JOIN lunchbox_order_items loi ON lsmi.lunchbox_order_item_id = loi.id
+		JOIN order_items oi ON loi.order_item_id = oi.id
+		JOIN orders o ON oi.order_id = o.id
+		WHERE ` + reportOrderFilter + `
+		AND o.status <> ?
+		AND lsmi.dish_id IS NOT NULL
+		GROUP BY oi.product_id, lsmi.dish_id`
+
+	rows, err := r.db.Instance.Query(sqlQuery, query.RestaurantId, query.From, query.To, orderstatus.CANCELLED)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	portions := make([]ports.DishPortions, 0, 10)
+	for rows.Next() {
+		var row ports.DishPortions
+		if err := rows.Scan(&row.ProductId, &row.DishId, &row.Portions); err != nil {
+			return nil, err
+		}
+		portions = append(portions, row)
+	}
+
+	return portions, rows.Err()
+}
@@ -46,7 +46,8 @@ const (
 		r.banner_url,
 		r.created_at,
 		r.updated_at,
-		r.active`
+		r.active,
+		r.version`
 )
 
 func (r *restaurantRepository) Find(args types.FindArgs) (*types.PagedSlice[aggregates.Restaurant], error) {
@@ -102,6 +103,7 @@ func (r *restaurantRepository) Find(args types.FindArgs) (*types.PagedSlice[aggr
 			&restaurant.CreatedAt,
 			&restaurant.UpdatedAt,
 			&restaurant.Active,
+			&restaurant.Version,
 			&restaurant.Address.Id,
 			&restaurant.Address.Alias,
 			&restaurant.Address.Street,
@@ -167,6 +169,7 @@ func (repo *restaurantRepository) FindById(id string) (*aggregates.Restaurant, e
 		&r.CreatedAt,
 		&r.UpdatedAt,
 		&r.Active,
+		&r.Version,
 		&r.Address.Id,
 		&r.Address.Alias,
 		&r.Address.Street,
@@ -191,13 +194,19 @@ func (repo *restaurantRepository) FindById(id string) (*aggregates.Restaurant, e
 }
 
 func (r *restaurantRepository) Create(record *aggregates.Restaurant) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	// First create the address
 	addressQuery := `
 		INSERT INTO addresses (
 			id, alias, street, number, complement, neighborhood, city, state, country, zip_code, lat, lng
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err := r.db.Instance.Exec(
+	_, err = tx.Exec(
 		addressQuery,
 		record.Address.Id,
 		record.Address.Alias,
@@ -229,7 +238,7 @@ func (r *restaurantRepository) Create(record *aggregates.Restaurant) error {
 			logo_url, banner_url, created_at, updated_at, active
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err = r.db.Instance.Exec(
+	_, err = tx.Exec(
 		restaurantQuery,
 		record.Id,
 		record.TradeName,
@@ -260,10 +269,29 @@ func (r *restaurantRepository) Create(record *aggregates.Restaurant) error {
 		record.UpdatedAt,
 		record.Active,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, record); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	record.ClearAuditRecords()
+	return nil
 }
 
 func (r *restaurantRepository) Update(record *aggregates.Restaurant) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	// First update the address
 	addressQuery := `
 		UPDATE addresses SET
@@ -280,7 +308,7 @@ func (r *restaurantRepository) Update(record *aggregates.Restaurant) error {
 			lng = ?
 		WHERE id = ?`
 
-	_, err := r.db.Instance.Exec(
+	_, err = tx.Exec(
 		addressQuery,
 		record.Address.Alias,
 		record.Address.Street,
@@ -326,10 +354,11 @@ func (r *restaurantRepository) Update(record *aggregates.Restaurant) error {
 			logo_url = ?,
 			banner_url = ?,
 			updated_at = ?,
-			active = ?
-		WHERE id = ? AND deleted_at IS NULL`
+			active = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL`
 
-	_, err = r.db.Instance.Exec(
+	result, err := tx.Exec(
 		restaurantQuery,
 		record.TradeName,
 		record.LegalName,
@@ -357,17 +386,56 @@ func (r *restaurantRepository) Update(record *aggregates.Restaurant) error {
 		record.UpdatedAt,
 		record.Active,
 		record.Id,
+		record.Version,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.RestaurantAggregateType, record.Id, record.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, record); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	record.Version++
+	record.ClearAuditRecords()
+	return nil
 }
 
-func (r *restaurantRepository) Delete(id string) error {
+func (r *restaurantRepository) Delete(restaurant *aggregates.Restaurant) error {
 	query := `
 		UPDATE restaurants 
 		SET deleted_at = NOW() 
 		WHERE id = ? AND deleted_at IS NULL`
-	_, err := r.db.Instance.Exec(query, id)
-	return err
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query, restaurant.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, restaurant); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	restaurant.ClearAuditRecords()
+	return nil
 }
 
 func (repo *restaurantRepository) FindByDocument(cnpj string) (*aggregates.Restaurant, error) {
@@ -408,6 +476,69 @@ func (repo *restaurantRepository) FindByDocument(cnpj string) (*aggregates.Resta
 		&r.CreatedAt,
 		&r.UpdatedAt,
 		&r.Active,
+		&r.Version,
+		&r.Address.Id,
+		&r.Address.Alias,
+		&r.Address.Street,
+		&r.Address.Number,
+		&r.Address.Complement,
+		&r.Address.Neighborhood,
+		&r.Address.City,
+		&r.Address.State,
+		&r.Address.Country,
+		&r.Address.ZipCode,
+		&r.Address.Lat,
+		&r.Address.Lng,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (repo *restaurantRepository) FindBySlug(slug string) (*aggregates.Restaurant, error) {
+	query := `
+		SELECT 
+			` + restaurantBaseFields + `,
+			` + AddressFields + `
+		FROM restaurants r
+		JOIN addresses a ON r.address_id = a.id
+		WHERE r.deleted_at IS NULL AND r.active = 1 AND r.slug = ?`
+
+	var r aggregates.Restaurant
+	err := repo.db.Instance.QueryRow(query, slug).Scan(
+		&r.Id,
+		&r.TradeName,
+		&r.LegalName,
+		&r.CNPJ,
+		&r.ContactPhone,
+		&r.WhatsAppPhone,
+		&r.Email,
+		&r.Slug,
+		&r.Settings.ShowCnpjInReceipt,
+		&r.Settings.Delivery.Enabled,
+		&r.Settings.Delivery.FeePerKm,
+		&r.Settings.Delivery.MinimumOrderValue,
+		&r.Settings.Delivery.MaxRadiusKm,
+		&r.Settings.Delivery.AverageTimeMinutes,
+		&r.Settings.Ecommerce.Enabled,
+		&r.Settings.Ecommerce.MinimumOrderValue,
+		&r.Settings.CustomerPostPaidOrders.Enabled,
+		&r.Settings.CustomerPostPaidOrders.MinimumOrderValue,
+		&r.Settings.CustomerPostPaidOrders.AverageTimeMinutes,
+		&r.Settings.CustomerPostPaidOrders.DeliveryFeePerKm,
+		&r.Settings.CustomerPostPaidOrders.DeliveryMaxRadiusKm,
+		&r.Settings.CustomerPostPaidOrders.DeliveryAverageTimeMinutes,
+		&r.LogoUrl,
+		&r.BannerUrl,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+		&r.Active,
+		&r.Version,
 		&r.Address.Id,
 		&r.Address.Alias,
 		&r.Address.Street,
@@ -0,0 +1,440 @@
+package respositories
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	orderstatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/order_status"
+	stockmovementtype "github.com/PedroNetto404/marmitech-backend/pkg/enums/stock_movement_type"
+)
+
+type stockItemRepository struct {
+	db *database.Db
+}
+
+func NewStockItemRepository(db *database.Db) ports.IStockItemRepository {
+	return &stockItemRepository{
+		db: db,
+	}
+}
+
+const (
+	stockItemBaseFields = `
+		si.id,
+		si.restaurant_id,
+		si.name,
+		si.kind,
+		si.unit,
+		si.quantity,
+		si.minimum_quantity,
+		si.unit_cost,
+		si.active,
+		si.created_at,
+		si.updated_at,
+		si.version`
+
+	stockMovementBaseFields = `
+		sm.id,
+		sm.stock_item_id,
+		sm.type,
+		sm.quantity,
+		sm.unit_cost,
+		sm.order_id,
+		sm.note,
+		sm.created_by,
+		sm.created_at`
+)
+
+func (r *stockItemRepository) FindById(id string) (*aggregates.StockItem, error) {
+	query := `
+		SELECT
+			` + stockItemBaseFields + `
+		FROM stock_items si
+		WHERE si.id = ?`
+
+	item, err := scanStockItem(r.db.Instance.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (r *stockItemRepository) FindByRestaurantId(restaurantId string, onlyLow bool) ([]aggregates.StockItem, error) {
+	query := `
+		SELECT
+			` + stockItemBaseFields + `
+		FROM stock_items si
+		WHERE si.restaurant_id = ?`
+
+	if onlyLow {
+		query += `
+		AND si.active = TRUE
+		AND si.minimum_quantity > 0
+		AND si.quantity <= si.minimum_quantity`
+	}
+	query += `
+		ORDER BY si.kind ASC, si.name ASC`
+
+	rows, err := r.db.Instance.Query(query, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]aggregates.StockItem, 0, 10)
+	for rows.Next() {
+		item, err := scanStockItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *stockItemRepository) Create(item *aggregates.StockItem) error {
+	query := `
+		INSERT INTO stock_items (
+			id, restaurant_id, name, kind, unit, quantity, minimum_quantity,
+			unit_cost, active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		item.Id,
+		item.Restaurant.Id,
+		item.Name,
+		item.Kind,
+		item.Unit,
+		item.Quantity,
+		item.MinimumQuantity,
+		item.UnitCost,
+		item.Active,
+		item.CreatedAt,
+		item.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, item); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	item.ClearAuditRecords()
+	return nil
+}
+
+func (r *stockItemRepository) Update(item *aggregates.StockItem) error {
+	query := `
+		UPDATE stock_items SET
+			name = ?,
+			kind = ?,
+			unit = ?,
+			minimum_quantity = ?,
+			active = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		item.Name,
+		item.Kind,
+		item.Unit,
+		item.MinimumQuantity,
+		item.Active,
+		item.UpdatedAt,
+		item.Id,
+		item.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.StockItemAggregateType, item.Id, item.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, item); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	item.Version++
+	item.ClearAuditRecords()
+	return nil
+}
+
+func (r *stockItemRepository) AddMovement(item *aggregates.StockItem, movement *aggregates.StockMovement) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createStockMovement(tx, movement); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE stock_items SET
+			quantity = ?,
+			unit_cost = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	result, err := tx.Exec(query, item.Quantity, item.UnitCost, item.UpdatedAt, item.Id, item.Version)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.StockItemAggregateType, item.Id, item.Version); err != nil {
+		return err
+	}
+
+	if err := insertDomainEvents(tx, item); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, item); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	item.Version++
+	item.ClearAuditRecords()
+	return nil
+}
+
+func (r *stockItemRepository) AddOrderMovements(items []*aggregates.StockItem, movements []*aggregates.StockMovement) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE stock_items SET
+			quantity = quantity + ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ?`
+
+	for _, movement := range movements {
+		if err := createStockMovement(tx, movement); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(query, movement.Quantity, movement.CreatedAt, movement.StockItemId); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range items {
+		if err := insertDomainEvents(tx, item); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *stockItemRepository) HasOrderMovements(orderId string, movementType stockmovementtype.StockMovementType) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM stock_movements WHERE order_id = ? AND type = ?
+		)`
+
+	var exists bool
+	err := r.db.Instance.QueryRow(query, orderId, movementType).Scan(&exists)
+	return exists, err
+}
+
+func (r *stockItemRepository) FindOrdersPendingConsumption(from, to time.Time, afterId string, limit int) ([]string, error) {
+	query := `
+		SELECT o.id
+		FROM orders o
+		WHERE o.created_at >= ? AND o.created_at < ?
+		AND o.id > ?
+		AND o.status <> ?
+		AND o.deleted_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM stock_movements sm WHERE sm.order_id = o.id AND sm.type = ?
+		)
+		AND (
+			EXISTS (
+				SELECT 1
+				FROM order_items oi
+				JOIN recipes r ON r.product_id = oi.product_id
+				JOIN recipe_items ri ON ri.recipe_id = r.id
+				WHERE oi.order_id = o.id
+			)
+			OR EXISTS (
+				SELECT 1
+				FROM order_items oi
+				JOIN lunchbox_order_items loi ON loi.order_item_id = oi.id
+				JOIN lunchbox_selected_menu_items lsmi ON lsmi.lunchbox_order_item_id = loi.id
+				JOIN recipes r ON r.dish_id = lsmi.dish_id
+				JOIN recipe_items ri ON ri.recipe_id = r.id
+				WHERE oi.order_id = o.id
+			)
+		)
+		ORDER BY o.id ASC
+		LIMIT ?`
+
+	rows, err := r.db.Instance.Query(query, from, to, afterId, orderstatus.CANCELLED, stockmovementtype.CONSUMPTION, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orderIds := make([]string, 0)
+	for rows.Next() {
+		var orderId string
+		if err := rows.Scan(&orderId); err != nil {
+			return nil, err
+		}
+		orderIds = append(orderIds, orderId)
+	}
+
+	return orderIds, rows.Err()
+}
+
+func (r *stockItemRepository) FindMovements(stockItemId string, from, to *time.Time) ([]aggregates.StockMovement, error) {
+	conditions := []string{"sm.stock_item_id = ?"}
+	params := []any{stockItemId}
+
+	if from != nil {
+		conditions = append(conditions, "sm.created_at >= ?")
+		params = append(params, *from)
+	}
+	if to != nil {
+		conditions = append(conditions, "sm.created_at < ?")
+		params = append(params, *to)
+	}
+
+	query := `
+		SELECT
+			` + stockMovementBaseFields + `
+		FROM stock_movements sm
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY sm.created_at ASC`
+
+	rows, err := r.db.Instance.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := make([]aggregates.StockMovement, 0, 10)
+	for rows.Next() {
+		var movement aggregates.StockMovement
+		var orderId, note, createdBy sql.NullString
+		err := rows.Scan(
+			&movement.Id,
+			&movement.StockItemId,
+			&movement.Type,
+			&movement.Quantity,
+			&movement.UnitCost,
+			&orderId,
+			&note,
+			&createdBy,
+			&movement.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		movement.OrderId = orderId.String
+		movement.Note = note.String
+		movement.CreatedBy = createdBy.String
+		movements = append(movements, movement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func createStockMovement(tx *sql.Tx, movement *aggregates.StockMovement) error {
+	query := `
+		INSERT INTO stock_movements (
+			id, stock_item_id, type, quantity, unit_cost, order_id, note, created_by, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(
+		query,
+		movement.Id,
+		movement.StockItemId,
+		movement.Type,
+		movement.Quantity,
+		movement.UnitCost,
+		nullString(movement.OrderId),
+		movement.Note,
+		nullString(movement.CreatedBy),
+		movement.CreatedAt,
+	)
+	return err
+}
+
+func scanStockItem(row rowScanner) (*aggregates.StockItem, error) {
+	var item aggregates.StockItem
+	err := row.Scan(
+		&item.Id,
+		&item.Restaurant.Id,
+		&item.Name,
+		&item.Kind,
+		&item.Unit,
+		&item.Quantity,
+		&item.MinimumQuantity,
+		&item.UnitCost,
+		&item.Active,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+		&item.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
@@ -0,0 +1,236 @@
+package respositories
+
+import (
+	"database/sql"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+)
+
+type supplierRepository struct {
+	db *database.Db
+}
+
+func NewSupplierRepository(db *database.Db) ports.ISupplierRepository {
+	return &supplierRepository{
+		db: db,
+	}
+}
+
+const (
+	supplierBaseFields = `
+		s.id,
+		s.restaurant_id,
+		s.name,
+		s.document,
+		s.email,
+		s.phone,
+		s.note,
+		s.created_at,
+		s.updated_at,
+		s.version`
+)
+
+func (r *supplierRepository) FindById(id string) (*aggregates.Supplier, error) {
+	query := `
+		SELECT
+			` + supplierBaseFields + `
+		FROM suppliers s
+		WHERE s.id = ?`
+
+	return r.findOne(query, id)
+}
+
+func (r *supplierRepository) FindByDocument(restaurantId, document string) (*aggregates.Supplier, error) {
+	query := `
+		SELECT
+			` + supplierBaseFields + `
+		FROM suppliers s
+		WHERE s.restaurant_id = ? AND s.document = ?`
+
+	return r.findOne(query, restaurantId, document)
+}
+
+func (r *supplierRepository) FindByRestaurantId(restaurantId string) ([]aggregates.Supplier, error) {
+	query := `
+		SELECT
+			` + supplierBaseFields + `
+		FROM suppliers s
+		WHERE s.restaurant_id = ?
+		ORDER BY s.name ASC`
+
+	rows, err := r.db.Instance.Query(query, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := make([]aggregates.Supplier, 0, 10)
+	for rows.Next() {
+		supplier, err := scanSupplier(rows)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, *supplier)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suppliers, nil
+}
+
+func (r *supplierRepository) Create(supplier *aggregates.Supplier) error {
+	query := `
+		INSERT INTO suppliers (
+			id, restaurant_id, name, document, email, phone, note, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		supplier.Id,
+		supplier.Restaurant.Id,
+		supplier.Name,
+		nullString(supplier.Document),
+		nullString(supplier.Email),
+		nullString(supplier.Phone),
+		nullString(supplier.Note),
+		supplier.CreatedAt,
+		supplier.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, supplier); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	supplier.ClearAuditRecords()
+	return nil
+}
+
+func (r *supplierRepository) Update(supplier *aggregates.Supplier) error {
+	query := `
+		UPDATE suppliers SET
+			name = ?,
+			document = ?,
+			email = ?,
+			phone = ?,
+			note = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		supplier.Name,
+		nullString(supplier.Document),
+		nullString(supplier.Email),
+		nullString(supplier.Phone),
+		nullString(supplier.Note),
+		supplier.UpdatedAt,
+		supplier.Id,
+		supplier.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.SupplierAggregateType, supplier.Id, supplier.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, supplier); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	supplier.Version++
+	supplier.ClearAuditRecords()
+	return nil
+}
+
+func (r *supplierRepository) Delete(supplier *aggregates.Supplier) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM suppliers WHERE id = ?`, supplier.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, supplier); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	supplier.ClearAuditRecords()
+	return nil
+}
+
+func (r *supplierRepository) findOne(query string, params ...any) (*aggregates.Supplier, error) {
+	supplier, err := scanSupplier(r.db.Instance.QueryRow(query, params...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return supplier, nil
+}
+
+func scanSupplier(row rowScanner) (*aggregates.Supplier, error) {
+	var supplier aggregates.Supplier
+	var document, email, phone, note sql.NullString
+	err := row.Scan(
+		&supplier.Id,
+		&supplier.Restaurant.Id,
+		&supplier.Name,
+		&document,
+		&email,
+		&phone,
+		&note,
+		&supplier.CreatedAt,
+		&supplier.UpdatedAt,
+		&supplier.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	supplier.Document = document.String
+	supplier.Email = email.String
+	supplier.Phone = phone.String
+	supplier.Note = note.String
+
+	return &supplier, nil
+}
@@ -29,7 +29,8 @@ const (
 		u.permissions,
 		u.created_at,
 		u.updated_at,
-		u.deleted_at`
+		u.deleted_at,
+		u.version`
 )
 
 func (r *userRepository) Find(args types.FindArgs) (*types.PagedSlice[aggregates.User], error) {
@@ -66,6 +67,7 @@ func (r *userRepository) Find(args types.FindArgs) (*types.PagedSlice[aggregates
 			&user.CreatedAt,
 			&user.UpdatedAt,
 			&user.DeletedAt,
+			&user.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -105,6 +107,7 @@ func (r *userRepository) FindById(id string) (*aggregates.User, error) {
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.DeletedAt,
+		&user.Version,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -158,10 +161,11 @@ func (r *userRepository) Update(user *aggregates.User) error {
 			active = ?,
 			role = ?,
 			permissions = ?,
-			updated_at = ?
-		WHERE id = ? AND deleted_at IS NULL`
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ? AND deleted_at IS NULL`
 
-	_, err = r.db.Instance.Exec(
+	result, err := r.db.Instance.Exec(
 		query,
 		user.Email,
 		user.PwdHash,
@@ -170,16 +174,26 @@ func (r *userRepository) Update(user *aggregates.User) error {
 		permissionsJSON,
 		user.UpdatedAt,
 		user.Id,
+		user.Version,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.UserAggregateType, user.Id, user.Version); err != nil {
+		return err
+	}
+
+	user.Version++
+	return nil
 }
 
-func (r *userRepository) Delete(id string) error {
+func (r *userRepository) Delete(user *aggregates.User) error {
 	query := `
 		UPDATE users 
 		SET deleted_at = NOW() 
 		WHERE id = ? AND deleted_at IS NULL`
-	_, err := r.db.Instance.Exec(query, id)
+	_, err := r.db.Instance.Exec(query, user.Id)
 	return err
 }
 
@@ -216,6 +230,7 @@ func (r *userRepository) FindByEmail(email string) (*aggregates.User, error) {
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.DeletedAt,
+		&user.Version,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -0,0 +1,26 @@
+package respositories
+
+import (
+	"database/sql"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+// checkVersionedUpdate confirma o compare-and-swap de um UPDATE filtrado por version;
+// nenhuma linha afetada significa que outro processo alterou o registro antes.
+func checkVersionedUpdate(result sql.Result, aggregateType, id string, version int) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return &ports.ConcurrencyConflictError{
+			AggregateType: aggregateType,
+			AggregateId:   id,
+			Version:       version,
+		}
+	}
+
+	return nil
+}
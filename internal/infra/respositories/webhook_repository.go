@@ -0,0 +1,455 @@
+package respositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/database"
+	webhookdeliverystatus "github.com/PedroNetto404/marmitech-backend/pkg/enums/webhook_delivery_status"
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// a resposta do integrador pode ser longa; a coluna guarda só o começo
+	webhookErrorMaxLength    = 512
+	defaultWebhookQueryLimit = 50
+)
+
+type webhookSubscriptionRepository struct {
+	db *database.Db
+}
+
+func NewWebhookSubscriptionRepository(db *database.Db) ports.IWebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{
+		db: db,
+	}
+}
+
+const (
+	webhookSubscriptionBaseFields = `
+		id,
+		restaurant_id,
+		url,
+		secret,
+		events,
+		description,
+		active,
+		created_at,
+		updated_at,
+		version`
+)
+
+func (r *webhookSubscriptionRepository) FindById(id string) (*aggregates.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionBaseFields + ` FROM webhook_subscriptions WHERE id = ?`
+
+	subscription, err := scanWebhookSubscription(r.db.Instance.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *webhookSubscriptionRepository) FindByRestaurantId(restaurantId string) ([]aggregates.WebhookSubscription, error) {
+	query := `
+		SELECT ` + webhookSubscriptionBaseFields + `
+		FROM webhook_subscriptions
+		WHERE restaurant_id = ?
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.Instance.Query(query, restaurantId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := make([]aggregates.WebhookSubscription, 0)
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *webhookSubscriptionRepository) Create(subscription *aggregates.WebhookSubscription) error {
+	events, err := json.Marshal(subscription.Events)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO webhook_subscriptions (
+			id, restaurant_id, url, secret, events, description, active, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		query,
+		subscription.Id,
+		subscription.Restaurant.Id,
+		subscription.Url,
+		subscription.Secret,
+		events,
+		nullString(subscription.Description),
+		subscription.Active,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, subscription); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	subscription.ClearAuditRecords()
+	return nil
+}
+
+func (r *webhookSubscriptionRepository) Update(subscription *aggregates.WebhookSubscription) error {
+	events, err := json.Marshal(subscription.Events)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE webhook_subscriptions SET
+			url = ?,
+			secret = ?,
+			events = ?,
+			description = ?,
+			active = ?,
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		subscription.Url,
+		subscription.Secret,
+		events,
+		nullString(subscription.Description),
+		subscription.Active,
+		subscription.UpdatedAt,
+		subscription.Id,
+		subscription.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.WebhookSubscriptionAggregateType, subscription.Id, subscription.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, subscription); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	subscription.Version++
+	subscription.ClearAuditRecords()
+	return nil
+}
+
+func (r *webhookSubscriptionRepository) Delete(subscription *aggregates.WebhookSubscription) error {
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM webhook_subscriptions WHERE id = ?`, subscription.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, subscription); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	subscription.ClearAuditRecords()
+	return nil
+}
+
+func scanWebhookSubscription(row rowScanner) (*aggregates.WebhookSubscription, error) {
+	var subscription aggregates.WebhookSubscription
+	var events []byte
+	var description sql.NullString
+	err := row.Scan(
+		&subscription.Id,
+		&subscription.Restaurant.Id,
+		&subscription.Url,
+		&subscription.Secret,
+		&events,
+		&description,
+		&subscription.Active,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+		&subscription.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(events, &subscription.Events); err != nil {
+		return nil, err
+	}
+	subscription.Description = description.String
+
+	return &subscription, nil
+}
+
+type webhookDeliveryRepository struct {
+	db *database.Db
+}
+
+func NewWebhookDeliveryRepository(db *database.Db) ports.IWebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		db: db,
+	}
+}
+
+const (
+	webhookDeliveryBaseFields = `
+		id,
+		subscription_id,
+		restaurant_id,
+		event_id,
+		event,
+		payload,
+		status,
+		attempts,
+		next_attempt_at,
+		last_status_code,
+		last_error,
+		created_at,
+		delivered_at,
+		version`
+)
+
+func (r *webhookDeliveryRepository) FindById(id string) (*aggregates.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryBaseFields + ` FROM webhook_deliveries WHERE id = ?`
+
+	delivery, err := scanWebhookDelivery(r.db.Instance.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (r *webhookDeliveryRepository) Find(query ports.WebhookDeliveryQuery) ([]aggregates.WebhookDelivery, error) {
+	statement := `SELECT ` + webhookDeliveryBaseFields + ` FROM webhook_deliveries WHERE restaurant_id = ?`
+	params := []any{query.RestaurantId}
+
+	if query.SubscriptionId != "" {
+		statement += ` AND subscription_id = ?`
+		params = append(params, query.SubscriptionId)
+	}
+	if query.Status != "" {
+		statement += ` AND status = ?`
+		params = append(params, query.Status)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultWebhookQueryLimit
+	}
+	statement += ` ORDER BY created_at DESC LIMIT ?`
+	params = append(params, limit)
+
+	return r.query(statement, params...)
+}
+
+func (r *webhookDeliveryRepository) FindDue(now time.Time, limit int) ([]aggregates.WebhookDelivery, error) {
+	statement := `
+		SELECT ` + webhookDeliveryBaseFields + `
+		FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC
+		LIMIT ?`
+
+	return r.query(statement, webhookdeliverystatus.PENDING, now, limit)
+}
+
+func (r *webhookDeliveryRepository) Create(delivery *aggregates.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, restaurant_id, event_id, event, payload, status, attempts, next_attempt_at, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Instance.Exec(
+		query,
+		delivery.Id,
+		delivery.SubscriptionId,
+		delivery.RestaurantId,
+		delivery.EventId,
+		delivery.Event,
+		delivery.Payload,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.CreatedAt,
+	)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Update é versionado: com mais de uma instância da API, só uma grava o resultado da tentativa
+func (r *webhookDeliveryRepository) Update(delivery *aggregates.WebhookDelivery) error {
+	lastError := delivery.LastError
+	if len(lastError) > webhookErrorMaxLength {
+		lastError = lastError[:webhookErrorMaxLength]
+	}
+
+	lastStatusCode := sql.NullInt64{Int64: int64(delivery.LastStatusCode), Valid: delivery.LastStatusCode != 0}
+
+	query := `
+		UPDATE webhook_deliveries SET
+			status = ?,
+			attempts = ?,
+			next_attempt_at = ?,
+			last_status_code = ?,
+			last_error = ?,
+			delivered_at = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	tx, err := r.db.Instance.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		lastStatusCode,
+		nullString(lastError),
+		delivery.DeliveredAt,
+		delivery.Id,
+		delivery.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersionedUpdate(result, aggregates.WebhookDeliveryAggregateType, delivery.Id, delivery.Version); err != nil {
+		return err
+	}
+
+	if err := insertAuditRecords(tx, delivery); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	delivery.Version++
+	delivery.ClearAuditRecords()
+	return nil
+}
+
+func (r *webhookDeliveryRepository) query(statement string, params ...any) ([]aggregates.WebhookDelivery, error) {
+	rows, err := r.db.Instance.Query(statement, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]aggregates.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func scanWebhookDelivery(row rowScanner) (*aggregates.WebhookDelivery, error) {
+	var delivery aggregates.WebhookDelivery
+	var nextAttemptAt, deliveredAt sql.NullTime
+	var lastStatusCode sql.NullInt64
+	var lastError sql.NullString
+	err := row.Scan(
+		&delivery.Id,
+		&delivery.SubscriptionId,
+		&delivery.RestaurantId,
+		&delivery.EventId,
+		&delivery.Event,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&nextAttemptAt,
+		&lastStatusCode,
+		&lastError,
+		&delivery.CreatedAt,
+		&deliveredAt,
+		&delivery.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if nextAttemptAt.Valid {
+		delivery.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	delivery.LastStatusCode = int(lastStatusCode.Int64)
+	delivery.LastError = lastError.String
+
+	return &delivery, nil
+}
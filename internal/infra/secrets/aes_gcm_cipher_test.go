@@ -0,0 +1,68 @@
+package secrets_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/infra/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+func TestAesGcmCipherRoundTrip(t *testing.T) {
+	// arrange
+	cipher, err := secrets.NewAesGcmCipher(testKey)
+	require.NoError(t, err)
+
+	// act
+	encrypted, encryptErr := cipher.Encrypt("senha-do-certificado")
+	again, _ := cipher.Encrypt("senha-do-certificado")
+	decrypted, decryptErr := cipher.Decrypt(encrypted)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(encryptErr)
+	assert.NoError(decryptErr)
+	assert.NotContains(encrypted, "senha-do-certificado", "valor gravado não expõe o segredo")
+	assert.NotEqual(encrypted, again, "cada cifra usa um nonce novo")
+	assert.Equal("senha-do-certificado", decrypted)
+}
+
+func TestAesGcmCipherRejectsPlaintext(t *testing.T) {
+	// arrange
+	cipher, err := secrets.NewAesGcmCipher(testKey)
+	require.NoError(t, err)
+
+	// act
+	_, decryptErr := cipher.Decrypt("senha-antiga")
+
+	// assert
+	assert.ErrorIs(t, decryptErr, secrets.ErrInvalidCiphertext, "texto puro não é lido como segredo")
+}
+
+func TestAesGcmCipherRejectsTamperingAndWrongKey(t *testing.T) {
+	// arrange
+	cipher, err := secrets.NewAesGcmCipher(testKey)
+	require.NoError(t, err)
+	other, err := secrets.NewAesGcmCipher(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", 32))))
+	require.NoError(t, err)
+
+	encrypted, _ := cipher.Encrypt("senha-do-certificado")
+	tampered := encrypted[:len(encrypted)-2] + "AA"
+
+	// act
+	_, tamperedErr := cipher.Decrypt(tampered)
+	_, wrongKeyErr := other.Decrypt(encrypted)
+	_, shortKeyErr := secrets.NewAesGcmCipher(base64.StdEncoding.EncodeToString([]byte("curta")))
+
+	// assert
+	assert := assert.New(t)
+
+	assert.ErrorIs(tamperedErr, secrets.ErrInvalidCiphertext, "valor adulterado é recusado")
+	assert.ErrorIs(wrongKeyErr, secrets.ErrInvalidCiphertext, "chave errada é recusada")
+	assert.Error(shortKeyErr, "chave precisa ter 32 bytes")
+}
@@ -0,0 +1,115 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+)
+
+const (
+	EventHeader     = "X-Marmitech-Event"
+	DeliveryHeader  = "X-Marmitech-Delivery"
+	TimestampHeader = "X-Marmitech-Timestamp"
+	SignatureHeader = "X-Marmitech-Signature"
+
+	webhookTimeout = 10 * time.Second
+)
+
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+type httpWebhookSender struct {
+	client *http.Client
+}
+
+func NewHttpWebhookSender() ports.IWebhookSender {
+	return newHttpWebhookSender(publicAddressOnly)
+}
+
+func newHttpWebhookSender(control func(network, address string, conn syscall.RawConn) error) *httpWebhookSender {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: control,
+	}
+
+	return &httpWebhookSender{
+		client: &http.Client{
+			Timeout: webhookTimeout,
+			Transport: &http.Transport{
+				// sem proxy: o endereço conferido no dial precisa ser o do integrador
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: webhookTimeout,
+			},
+			// redirecionamento levaria o corpo assinado para uma URL que o restaurante não cadastrou
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+}
+
+// publicAddressOnly roda depois da resolução de DNS, então um domínio que aponta para a rede
+// interna também é recusado
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+
+	return nil
+}
+
+func (s *httpWebhookSender) Send(request ports.WebhookRequest) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	httpRequest, err := http.NewRequest(http.MethodPost, request.Url, bytes.NewReader(request.Payload))
+	if err != nil {
+		return 0, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("User-Agent", "Marmitech-Webhooks/1.0")
+	httpRequest.Header.Set(EventHeader, string(request.Event))
+	httpRequest.Header.Set(DeliveryHeader, request.DeliveryId)
+	httpRequest.Header.Set(TimestampHeader, timestamp)
+	httpRequest.Header.Set(SignatureHeader, "sha256="+Sign(request.Secret, timestamp, request.Payload))
+
+	response, err := s.client.Do(httpRequest)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	return response.StatusCode, nil
+}
+
+// Sign calcula o HMAC-SHA256 em hexadecimal de "timestamp.corpo"; o integrador refaz a conta
+// com o segredo da assinatura e recusa timestamps antigos para evitar replay
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
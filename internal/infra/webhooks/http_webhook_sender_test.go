@@ -0,0 +1,104 @@
+package webhooks
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/aggregates"
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSenderSignsPayload(t *testing.T) {
+	// arrange
+	var headers http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	payload := []byte(`{"event":"order.created"}`)
+
+	// act
+	allowAll := func(string, string, syscall.RawConn) error { return nil }
+	status, err := newHttpWebhookSender(allowAll).Send(ports.WebhookRequest{
+		Url:        server.URL,
+		Secret:     "segredo-de-teste-123",
+		DeliveryId: "delivery",
+		Event:      aggregates.OrderCreatedEvent,
+		Payload:    payload,
+	})
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(err)
+	assert.Equal(http.StatusAccepted, status)
+	assert.Equal(payload, body)
+	assert.Equal("order.created", headers.Get(EventHeader))
+	assert.Equal("delivery", headers.Get(DeliveryHeader))
+	assert.Equal(
+		"sha256="+Sign("segredo-de-teste-123", headers.Get(TimestampHeader), payload),
+		headers.Get(SignatureHeader),
+		"o integrador confere a assinatura com o timestamp recebido",
+	)
+}
+
+func TestSenderRefusesInternalAddresses(t *testing.T) {
+	// arrange
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// act
+	_, err := NewHttpWebhookSender().Send(ports.WebhookRequest{
+		Url:     server.URL,
+		Secret:  "segredo-de-teste-123",
+		Event:   aggregates.OrderCreatedEvent,
+		Payload: []byte(`{}`),
+	})
+
+	// assert
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.False(t, called, "a requisição não pode chegar à rede interna")
+}
+
+func TestPublicAddressOnly(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{address: "127.0.0.1:443"},
+		{address: "[::1]:443"},
+		{address: "10.0.0.5:443"},
+		{address: "192.168.1.10:443"},
+		{address: "172.16.0.1:443"},
+		{address: "169.254.169.254:80"},
+		{address: "[fe80::1]:443"},
+		{address: "0.0.0.0:443"},
+		{address: "203.0.113.10:443", allowed: true},
+		{address: "[2001:db8::1]:443", allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.address, func(t *testing.T) {
+			// act
+			err := publicAddressOnly("tcp", test.address, nil)
+
+			// assert
+			if test.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrForbiddenAddress)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+CREATE TABLE audit_log(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    actor_email VARCHAR(255),
+    actor_role VARCHAR(255),
+    ip VARCHAR(45),
+    aggregate_type VARCHAR(64) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    diff JSON NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_audit_log_restaurant_created_at ON audit_log(restaurant_id, created_at);
+CREATE INDEX idx_audit_log_aggregate ON audit_log(aggregate_type, aggregate_id);
@@ -0,0 +1,7 @@
+ALTER TABLE restaurants ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE products ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE dishes ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE customers ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE orders ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+ALTER TABLE products ADD COLUMN priority INT NOT NULL DEFAULT 0;
@@ -0,0 +1,32 @@
+CREATE TABLE customer_tabs(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    customer_id CHAR(36) NOT NULL,
+    credit_limit INT NOT NULL DEFAULT 0,
+    balance INT NOT NULL DEFAULT 0,
+    due_day INT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE KEY uq_customer_tabs_restaurant_customer (restaurant_id, customer_id),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE customer_tab_entries(
+    id CHAR(36) PRIMARY KEY,
+    tab_id CHAR(36) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    amount INT NOT NULL,
+    order_id CHAR(36) NULL,
+    payment_method VARCHAR(64) NULL,
+    description VARCHAR(255),
+    due_date DATE NULL,
+    created_by VARCHAR(255),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- um pedido entra uma vez só no fiado; créditos não têm pedido e não se repetem no índice
+    UNIQUE KEY uq_customer_tab_entries_tab_order (tab_id, order_id),
+    FOREIGN KEY (tab_id) REFERENCES customer_tabs(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_customer_tab_entries_tab_created ON customer_tab_entries(tab_id, created_at);
@@ -0,0 +1,85 @@
+-- as tabelas de pedido da 00002 não correspondiam ao agregado Order e nunca foram usadas;
+-- são recriadas com a composição das marmitas e o status de cozinha por item
+DROP TABLE IF EXISTS lunchbox_selected_menu_items;
+DROP TABLE IF EXISTS lunchbox_order_items;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS order_payments;
+DROP TABLE IF EXISTS order_deliveries;
+DROP TABLE IF EXISTS orders;
+
+CREATE TABLE orders(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    customer_id CHAR(36) NULL,
+    status VARCHAR(32) NOT NULL,
+    subtotal DECIMAL(10, 2) NOT NULL,
+    discount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    total DECIMAL(10, 2) NOT NULL,
+    observation VARCHAR(255),
+    cancel_reason VARCHAR(255),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME NULL,
+    version INT NOT NULL DEFAULT 1,
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE SET NULL ON UPDATE CASCADE,
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX idx_orders_restaurant_status ON orders(restaurant_id, status, created_at);
+
+CREATE TABLE order_items(
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    position INT NOT NULL,
+    product_id CHAR(36) NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    unit_price DECIMAL(10, 2) NOT NULL,
+    quantity INT NOT NULL,
+    discount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    total DECIMAL(10, 2) NOT NULL,
+    observation VARCHAR(255),
+    status VARCHAR(32) NOT NULL,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON UPDATE CASCADE
+);
+
+CREATE TABLE lunchbox_order_items(
+    id CHAR(36) PRIMARY KEY,
+    order_item_id CHAR(36) NOT NULL,
+    wants_flatware BOOLEAN NOT NULL,
+    FOREIGN KEY (order_item_id) REFERENCES order_items(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE lunchbox_selected_menu_items(
+    id CHAR(36) PRIMARY KEY,
+    lunchbox_order_item_id CHAR(36) NOT NULL,
+    menu_item_id CHAR(36) NOT NULL,
+    dish_name VARCHAR(255) NOT NULL,
+    dish_type VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    is_additional BOOLEAN NOT NULL DEFAULT FALSE,
+    price DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    FOREIGN KEY (lunchbox_order_item_id) REFERENCES lunchbox_order_items(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE order_deliveries(
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    address_id CHAR(36) NOT NULL,
+    fee DECIMAL(10, 2) NOT NULL,
+    distance DECIMAL(10, 2) NOT NULL,
+    average_time_minutes INT NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (address_id) REFERENCES addresses(id) ON UPDATE CASCADE
+);
+CREATE INDEX idx_order_deliveries_order_id ON order_deliveries(order_id);
+
+CREATE TABLE order_payments(
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    payment_method VARCHAR(255) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    paid_at DATETIME NOT NULL,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE order_payments ADD COLUMN pix_key VARCHAR(255) NULL;
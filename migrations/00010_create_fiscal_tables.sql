@@ -0,0 +1,49 @@
+ALTER TABLE products
+    ADD COLUMN ncm VARCHAR(8) NOT NULL DEFAULT '',
+    ADD COLUMN cfop VARCHAR(4) NOT NULL DEFAULT '',
+    ADD COLUMN csosn VARCHAR(3) NOT NULL DEFAULT '',
+    ADD COLUMN origin TINYINT NOT NULL DEFAULT 0;
+
+CREATE TABLE fiscal_profiles(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL UNIQUE,
+    tax_regime TINYINT NOT NULL,
+    state_registration VARCHAR(20) NOT NULL,
+    city_code CHAR(7) NOT NULL,
+    environment TINYINT NOT NULL,
+    series INT NOT NULL,
+    next_number INT NOT NULL,
+    csc_id VARCHAR(6) NOT NULL,
+    csc_token VARCHAR(64) NOT NULL,
+    certificate_password VARCHAR(255) NULL,
+    certificate_subject VARCHAR(255) NULL,
+    certificate_expires_at DATETIME NULL,
+    version INT NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE fiscal_documents(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    order_id CHAR(36) NOT NULL UNIQUE,
+    model CHAR(2) NOT NULL,
+    series INT NOT NULL,
+    number INT NOT NULL,
+    environment TINYINT NOT NULL,
+    access_key CHAR(44) NULL,
+    status VARCHAR(16) NOT NULL,
+    status_code VARCHAR(8) NULL,
+    status_reason VARCHAR(255) NULL,
+    protocol VARCHAR(32) NULL,
+    qr_code_url VARCHAR(1024) NULL,
+    xml MEDIUMTEXT NULL,
+    issued_at DATETIME NOT NULL,
+    authorized_at DATETIME NULL,
+    version INT NOT NULL DEFAULT 1,
+    updated_at DATETIME NOT NULL,
+    UNIQUE KEY uq_fiscal_documents_number (restaurant_id, model, series, number, environment),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,55 @@
+CREATE TABLE promotions(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description VARCHAR(255),
+    -- nulo para as regras automáticas
+    code VARCHAR(32) NULL,
+    discount_type VARCHAR(16) NOT NULL,
+    value DECIMAL(10, 2) NOT NULL,
+    min_order_value DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    -- dias da semana (0 = domingo) separados por vírgula; vazio vale todos os dias
+    weekdays VARCHAR(16) NOT NULL DEFAULT '',
+    starts_at DATETIME NULL,
+    ends_at DATETIME NULL,
+    max_uses INT NOT NULL DEFAULT 0,
+    max_uses_per_customer INT NOT NULL DEFAULT 0,
+    used_count INT NOT NULL DEFAULT 0,
+    first_order_only BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE KEY uq_promotions_restaurant_code (restaurant_id, code),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE promotion_products(
+    promotion_id CHAR(36) NOT NULL,
+    product_id CHAR(36) NOT NULL,
+    PRIMARY KEY (promotion_id, product_id),
+    FOREIGN KEY (promotion_id) REFERENCES promotions(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE promotion_combo_items(
+    promotion_id CHAR(36) NOT NULL,
+    product_id CHAR(36) NOT NULL,
+    quantity INT NOT NULL,
+    PRIMARY KEY (promotion_id, product_id),
+    FOREIGN KEY (promotion_id) REFERENCES promotions(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- nome e código são copiados para o pedido: o histórico sobrevive à exclusão da promoção
+CREATE TABLE order_promotions(
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    promotion_id CHAR(36) NOT NULL,
+    order_item_id CHAR(36) NULL,
+    name VARCHAR(255) NOT NULL,
+    code VARCHAR(32) NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX idx_order_promotions_promotion ON order_promotions(promotion_id);
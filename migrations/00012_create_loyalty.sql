@@ -0,0 +1,58 @@
+CREATE TABLE loyalty_programs(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    mode VARCHAR(16) NOT NULL,
+    reward_threshold INT NOT NULL,
+    reward_product_id CHAR(36) NULL,
+    reward_value DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    expiration_days INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE KEY uq_loyalty_programs_restaurant (restaurant_id),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (reward_product_id) REFERENCES products(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+-- product_id ou category_id preenchido, nunca os dois
+CREATE TABLE loyalty_earn_rules(
+    id CHAR(36) PRIMARY KEY,
+    program_id CHAR(36) NOT NULL,
+    product_id CHAR(36) NULL,
+    category_id CHAR(36) NULL,
+    points INT NOT NULL,
+    FOREIGN KEY (program_id) REFERENCES loyalty_programs(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE loyalty_accounts(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    customer_id CHAR(36) NOT NULL,
+    balance INT NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE KEY uq_loyalty_accounts_restaurant_customer (restaurant_id, customer_id),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- a chave única impede que o mesmo pedido pontue ou resgate duas vezes
+CREATE TABLE loyalty_entries(
+    id CHAR(36) PRIMARY KEY,
+    account_id CHAR(36) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    points INT NOT NULL,
+    order_id CHAR(36) NULL,
+    description VARCHAR(255),
+    expires_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_loyalty_entries_account_order_type (account_id, order_id, type),
+    FOREIGN KEY (account_id) REFERENCES loyalty_accounts(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_loyalty_entries_account_created ON loyalty_entries(account_id, created_at);
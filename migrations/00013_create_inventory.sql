@@ -0,0 +1,64 @@
+CREATE TABLE stock_items(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    unit VARCHAR(8) NOT NULL,
+    quantity DECIMAL(12, 3) NOT NULL DEFAULT 0,
+    minimum_quantity DECIMAL(12, 3) NOT NULL DEFAULT 0,
+    unit_cost DECIMAL(12, 4) NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE KEY uq_stock_items_restaurant_name (restaurant_id, name),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- a chave única impede que o mesmo pedido baixe ou estorne um item duas vezes
+CREATE TABLE stock_movements(
+    id CHAR(36) PRIMARY KEY,
+    stock_item_id CHAR(36) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    quantity DECIMAL(12, 3) NOT NULL,
+    unit_cost DECIMAL(12, 4) NOT NULL DEFAULT 0,
+    order_id CHAR(36) NULL,
+    note VARCHAR(255),
+    created_by VARCHAR(255),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_stock_movements_item_order_type (stock_item_id, order_id, type),
+    FOREIGN KEY (stock_item_id) REFERENCES stock_items(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX idx_stock_movements_item_created ON stock_movements(stock_item_id, created_at);
+CREATE INDEX idx_stock_movements_order ON stock_movements(order_id, type);
+
+-- ficha técnica: product_id ou dish_id preenchido, nunca os dois
+CREATE TABLE recipes(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    product_id CHAR(36) NULL,
+    dish_id CHAR(36) NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE KEY uq_recipes_product (product_id),
+    UNIQUE KEY uq_recipes_dish (dish_id),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (dish_id) REFERENCES dishes(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE recipe_items(
+    recipe_id CHAR(36) NOT NULL,
+    stock_item_id CHAR(36) NOT NULL,
+    quantity DECIMAL(12, 3) NOT NULL,
+    PRIMARY KEY (recipe_id, stock_item_id),
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (stock_item_id) REFERENCES stock_items(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- o prato passa a ser gravado na marmita para a baixa pela ficha técnica do prato
+ALTER TABLE lunchbox_selected_menu_items ADD COLUMN dish_id CHAR(36) NULL AFTER menu_item_id;
+
+UPDATE lunchbox_selected_menu_items lsmi
+JOIN menu_items mi ON lsmi.menu_item_id = mi.id
+SET lsmi.dish_id = mi.dish_id;
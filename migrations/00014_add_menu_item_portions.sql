@@ -0,0 +1,2 @@
+-- porções disponíveis no dia; NULL é item sem controle de quantidade
+ALTER TABLE menu_items ADD COLUMN available_portions INT NULL AFTER additional_price;
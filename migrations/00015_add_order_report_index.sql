@@ -0,0 +1,2 @@
+-- os relatórios filtram por restaurante e período sem olhar o status
+CREATE INDEX idx_orders_restaurant_created_at ON orders(restaurant_id, created_at);
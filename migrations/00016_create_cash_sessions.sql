@@ -0,0 +1,42 @@
+-- open_operator só é preenchido enquanto a sessão está aberta: a chave única garante
+-- um caixa aberto por operador sem impedir o histórico de sessões fechadas
+CREATE TABLE cash_sessions(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    operator VARCHAR(255) NOT NULL,
+    open_operator VARCHAR(255) NULL,
+    status VARCHAR(16) NOT NULL,
+    opening_float DECIMAL(10, 2) NOT NULL,
+    note VARCHAR(255) NULL,
+    opened_at DATETIME NOT NULL,
+    closed_at DATETIME NULL,
+    closed_by VARCHAR(255) NULL,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE KEY uq_cash_sessions_open_operator (restaurant_id, open_operator),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX idx_cash_sessions_restaurant_opened_at ON cash_sessions(restaurant_id, opened_at);
+
+CREATE TABLE cash_movements(
+    id CHAR(36) PRIMARY KEY,
+    session_id CHAR(36) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES cash_sessions(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE cash_session_counts(
+    session_id CHAR(36) NOT NULL,
+    payment_method VARCHAR(32) NOT NULL,
+    expected DECIMAL(10, 2) NOT NULL,
+    counted DECIMAL(10, 2) NOT NULL,
+    PRIMARY KEY (session_id, payment_method),
+    FOREIGN KEY (session_id) REFERENCES cash_sessions(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+ALTER TABLE order_payments ADD COLUMN cash_session_id CHAR(36) NULL;
+ALTER TABLE order_payments ADD CONSTRAINT fk_order_payments_cash_session
+    FOREIGN KEY (cash_session_id) REFERENCES cash_sessions(id) ON DELETE SET NULL ON UPDATE CASCADE;
@@ -0,0 +1,47 @@
+CREATE TABLE suppliers(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    document VARCHAR(14) NULL,
+    email VARCHAR(255) NULL,
+    phone VARCHAR(32) NULL,
+    note VARCHAR(255) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE KEY uq_suppliers_restaurant_document (restaurant_id, document),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- series_id agrupa as ocorrências de uma conta recorrente
+CREATE TABLE bills(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    supplier_id CHAR(36) NULL,
+    series_id CHAR(36) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    category VARCHAR(32) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    paid_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    due_date DATE NOT NULL,
+    due_day TINYINT NOT NULL,
+    recurrence VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    note VARCHAR(255) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version INT NOT NULL DEFAULT 1,
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+CREATE INDEX idx_bills_restaurant_status_due ON bills(restaurant_id, status, due_date);
+
+CREATE TABLE bill_payments(
+    id CHAR(36) PRIMARY KEY,
+    bill_id CHAR(36) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    payment_method VARCHAR(32) NOT NULL,
+    paid_at DATETIME NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    FOREIGN KEY (bill_id) REFERENCES bills(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
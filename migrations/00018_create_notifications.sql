@@ -0,0 +1,30 @@
+-- sem linha o cliente recebe por todos os canais; cada coluna falsa é um canal recusado
+CREATE TABLE customer_notification_preferences(
+    customer_id CHAR(36) PRIMARY KEY,
+    email BOOLEAN NOT NULL DEFAULT TRUE,
+    whatsapp BOOLEAN NOT NULL DEFAULT TRUE,
+    sms BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at DATETIME NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- a chave única impede que o mesmo aviso saia duas vezes: o order.updated se repete
+-- a cada item movido na cozinha
+CREATE TABLE notifications(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    order_id CHAR(36) NOT NULL,
+    customer_id CHAR(36) NOT NULL,
+    template VARCHAR(32) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error VARCHAR(512) NULL,
+    created_at DATETIME NOT NULL,
+    sent_at DATETIME NULL,
+    UNIQUE KEY uq_notifications_order_template_channel (order_id, template, channel),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
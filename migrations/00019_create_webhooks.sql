@@ -0,0 +1,34 @@
+CREATE TABLE webhook_subscriptions(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events JSON NOT NULL,
+    description VARCHAR(255) NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- entregas mortas (status dead) formam a lista de reenvio manual
+CREATE TABLE webhook_deliveries(
+    id CHAR(36) PRIMARY KEY,
+    subscription_id CHAR(36) NOT NULL,
+    restaurant_id CHAR(36) NOT NULL,
+    event_id CHAR(36) NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    payload MEDIUMTEXT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME NULL,
+    last_status_code INT NULL,
+    last_error VARCHAR(512) NULL,
+    created_at DATETIME NOT NULL,
+    delivered_at DATETIME NULL,
+    version INT NOT NULL DEFAULT 1,
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+CREATE INDEX idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at);
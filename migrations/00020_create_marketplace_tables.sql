@@ -0,0 +1,55 @@
+-- pedidos antigos foram todos lançados no próprio sistema
+ALTER TABLE orders
+    ADD COLUMN channel VARCHAR(16) NOT NULL DEFAULT 'direct',
+    ADD COLUMN external_id VARCHAR(64) NULL,
+    ADD COLUMN external_code VARCHAR(32) NULL;
+CREATE UNIQUE INDEX idx_orders_channel_external_id ON orders(channel, external_id);
+
+CREATE TABLE marketplace_integrations(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    merchant_id VARCHAR(64) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE (channel, merchant_id),
+    UNIQUE (restaurant_id, channel),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE marketplace_mappings(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    external_code VARCHAR(64) NOT NULL,
+    product_id CHAR(36) NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE (restaurant_id, channel, external_code),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- a chave única em (channel, external_id) impede o mesmo pedido de entrar duas vezes
+CREATE TABLE marketplace_orders(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    external_id VARCHAR(64) NOT NULL,
+    external_code VARCHAR(32) NOT NULL,
+    order_id CHAR(36) NULL,
+    status VARCHAR(16) NOT NULL,
+    unmapped_items JSON NOT NULL,
+    last_error VARCHAR(512) NULL,
+    received_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    UNIQUE (channel, external_id),
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+CREATE INDEX idx_marketplace_orders_restaurant ON marketplace_orders(restaurant_id, status, received_at);
+CREATE INDEX idx_marketplace_orders_order ON marketplace_orders(order_id);
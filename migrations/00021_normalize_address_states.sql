@@ -0,0 +1,36 @@
+-- endereços antigos guardavam o nome do estado por extenso; agora a coluna guarda a sigla da UF
+UPDATE addresses SET state = CASE TRIM(state)
+    WHEN 'Acre' THEN 'AC'
+    WHEN 'Alagoas' THEN 'AL'
+    WHEN 'Amapá' THEN 'AP'
+    WHEN 'Amazonas' THEN 'AM'
+    WHEN 'Bahia' THEN 'BA'
+    WHEN 'Ceará' THEN 'CE'
+    WHEN 'Distrito Federal' THEN 'DF'
+    WHEN 'Espírito Santo' THEN 'ES'
+    WHEN 'Goiás' THEN 'GO'
+    WHEN 'Maranhão' THEN 'MA'
+    WHEN 'Mato Grosso' THEN 'MT'
+    WHEN 'Mato Grosso do Sul' THEN 'MS'
+    WHEN 'Minas Gerais' THEN 'MG'
+    WHEN 'Pará' THEN 'PA'
+    WHEN 'Paraíba' THEN 'PB'
+    WHEN 'Paraná' THEN 'PR'
+    WHEN 'Pernambuco' THEN 'PE'
+    WHEN 'Piauí' THEN 'PI'
+    WHEN 'Rio de Janeiro' THEN 'RJ'
+    WHEN 'Rio Grande do Norte' THEN 'RN'
+    WHEN 'Rio Grande do Sul' THEN 'RS'
+    WHEN 'Rondônia' THEN 'RO'
+    WHEN 'Roraima' THEN 'RR'
+    WHEN 'Santa Catarina' THEN 'SC'
+    WHEN 'São Paulo' THEN 'SP'
+    WHEN 'Sergipe' THEN 'SE'
+    WHEN 'Tocantins' THEN 'TO'
+    ELSE UPPER(TRIM(state))
+END
+WHERE state IS NOT NULL;
+
+-- o CEP passa a ser gravado só com os dígitos
+UPDATE addresses SET zip_code = REPLACE(REPLACE(REPLACE(zip_code, '-', ''), '.', ''), ' ', '')
+WHERE zip_code IS NOT NULL;
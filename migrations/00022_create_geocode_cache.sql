@@ -0,0 +1,8 @@
+-- a chave é o endereço normalizado; o hash mantém o índice pequeno mesmo com ruas longas
+CREATE TABLE geocode_cache(
+    address_hash CHAR(64) PRIMARY KEY,
+    address_key VARCHAR(1024) NOT NULL,
+    lat DOUBLE NOT NULL,
+    lng DOUBLE NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
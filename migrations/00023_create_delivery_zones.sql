@@ -0,0 +1,20 @@
+-- polygon guarda o GeoJSON (coordenadas em [lng, lat]); neighborhoods a lista de bairros
+CREATE TABLE delivery_zones(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    polygon JSON NULL,
+    neighborhoods JSON NULL,
+    city VARCHAR(255) NULL,
+    fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+    minimum_order_value DECIMAL(10,2) NOT NULL DEFAULT 0,
+    estimated_time_minutes INT NOT NULL DEFAULT 0,
+    priority INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX idx_delivery_zones_restaurant ON delivery_zones(restaurant_id, priority);
@@ -0,0 +1,66 @@
+CREATE TABLE drivers(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    phone VARCHAR(32) NULL,
+    email VARCHAR(255) NULL,
+    vehicle VARCHAR(255) NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    UNIQUE KEY uq_drivers_email (restaurant_id, email)
+);
+
+CREATE TABLE delivery_routes(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    driver_id CHAR(36) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    assigned_by VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    accepted_at DATETIME NULL,
+    picked_up_at DATETIME NULL,
+    completed_at DATETIME NULL,
+    updated_at DATETIME NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (driver_id) REFERENCES drivers(id) ON UPDATE CASCADE
+);
+CREATE INDEX idx_delivery_routes_restaurant ON delivery_routes(restaurant_id, status, created_at);
+CREATE INDEX idx_delivery_routes_driver ON delivery_routes(driver_id, status);
+
+-- a taxa é copiada do pedido na atribuição; é ela que entra no acerto do entregador
+CREATE TABLE delivery_route_stops(
+    id CHAR(36) PRIMARY KEY,
+    route_id CHAR(36) NOT NULL,
+    sequence INT NOT NULL,
+    order_id CHAR(36) NOT NULL,
+    delivery_id CHAR(36) NOT NULL,
+    fee DECIMAL(10,2) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    proof_photo_url VARCHAR(2048) NULL,
+    code_confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+    delivered_at DATETIME NULL,
+    FOREIGN KEY (route_id) REFERENCES delivery_routes(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON UPDATE CASCADE
+);
+CREATE INDEX idx_delivery_route_stops_route ON delivery_route_stops(route_id, sequence);
+CREATE INDEX idx_delivery_route_stops_order ON delivery_route_stops(order_id);
+CREATE INDEX idx_delivery_route_stops_delivered ON delivery_route_stops(status, delivered_at);
+
+CREATE TABLE driver_locations(
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    driver_id CHAR(36) NOT NULL,
+    route_id CHAR(36) NULL,
+    lat DOUBLE NOT NULL,
+    lng DOUBLE NOT NULL,
+    recorded_at DATETIME(3) NOT NULL,
+    FOREIGN KEY (driver_id) REFERENCES drivers(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX idx_driver_locations_driver ON driver_locations(driver_id, recorded_at);
+CREATE INDEX idx_driver_locations_route ON driver_locations(route_id, recorded_at);
+
+ALTER TABLE order_deliveries ADD COLUMN code CHAR(4) NULL;
@@ -0,0 +1,26 @@
+-- eventos de domínio gravados na mesma transação do agregado que os levantou;
+-- os ids podem ter lacunas e confirmar fora de ordem, recorded_at diz quando uma lacuna não se preenche mais
+CREATE TABLE event_outbox(
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    event_id CHAR(36) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    occurred_at DATETIME(6) NOT NULL,
+    recorded_at DATETIME(6) NOT NULL,
+    UNIQUE KEY uq_event_outbox_event (event_id)
+);
+
+-- posição de cada consumidor durável na outbox: tudo até last_event_id já foi entregue
+CREATE TABLE event_consumers(
+    name VARCHAR(64) PRIMARY KEY,
+    last_event_id BIGINT NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- eventos entregues acima da posição de cada consumidor
+CREATE TABLE event_deliveries(
+    consumer VARCHAR(64) NOT NULL,
+    event_id BIGINT NOT NULL,
+    delivered_at DATETIME NOT NULL,
+    PRIMARY KEY (consumer, event_id)
+);
@@ -0,0 +1,2 @@
+-- a varredura de baixa de estoque percorre os pedidos recentes de todos os restaurantes
+CREATE INDEX idx_orders_created_at ON orders(created_at);
@@ -0,0 +1,2 @@
+-- o mesmo evento pode ser lido de novo da outbox; a assinatura recebe uma entrega só
+ALTER TABLE webhook_deliveries ADD UNIQUE KEY uq_webhook_deliveries_subscription_event (subscription_id, event_id);
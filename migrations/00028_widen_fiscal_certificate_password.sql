@@ -0,0 +1,2 @@
+-- a senha do certificado passa a ser gravada cifrada (nonce + tag + base64), maior que o texto puro
+ALTER TABLE fiscal_profiles MODIFY certificate_password VARCHAR(512) NULL;
@@ -0,0 +1,6 @@
+-- a chave passa a valer por restaurante e usuário; as antigas ficam no escopo vazio até expirarem
+ALTER TABLE idempotency_keys
+    ADD COLUMN restaurant_id VARCHAR(36) NOT NULL DEFAULT '' FIRST,
+    ADD COLUMN principal VARCHAR(255) NOT NULL DEFAULT '' AFTER restaurant_id,
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (restaurant_id, principal, idempotency_key);
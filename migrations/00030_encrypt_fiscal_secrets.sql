@@ -0,0 +1,5 @@
+-- o token CSC também passa a ser gravado cifrado; secrets_encrypted marca as linhas que o
+-- encrypt-fiscal-secrets ainda precisa cifrar, uma única vez
+ALTER TABLE fiscal_profiles
+    MODIFY csc_token VARCHAR(512) NOT NULL,
+    ADD COLUMN secrets_encrypted BOOLEAN NOT NULL DEFAULT FALSE AFTER certificate_expires_at;
@@ -0,0 +1,30 @@
+-- as novas tentativas de notificações e de status para os marketplaces passam a ser agendadas
+-- no banco, como as entregas de webhook, em vez de esperar dentro do processo
+ALTER TABLE notifications
+    ADD COLUMN subject VARCHAR(255) NULL AFTER recipient,
+    ADD COLUMN body TEXT NULL AFTER subject,
+    ADD COLUMN next_attempt_at DATETIME NULL AFTER attempts,
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
+CREATE INDEX idx_notifications_due ON notifications(status, next_attempt_at);
+
+-- os envios do mesmo pedido saem na ordem de criação: um só vai depois que o anterior terminou
+CREATE TABLE marketplace_pushes(
+    id CHAR(36) PRIMARY KEY,
+    restaurant_id CHAR(36) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    order_id CHAR(36) NOT NULL,
+    external_id VARCHAR(64) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    reason VARCHAR(255) NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME NULL,
+    last_error VARCHAR(512) NULL,
+    created_at DATETIME(6) NOT NULL,
+    sent_at DATETIME NULL,
+    version INT NOT NULL DEFAULT 1,
+    FOREIGN KEY (restaurant_id) REFERENCES restaurants(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX idx_marketplace_pushes_due ON marketplace_pushes(status, next_attempt_at);
+CREATE INDEX idx_marketplace_pushes_order ON marketplace_pushes(order_id, created_at);
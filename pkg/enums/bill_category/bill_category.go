@@ -0,0 +1,24 @@
+package billcategory
+
+type BillCategory string
+
+const (
+	INGREDIENTS BillCategory = "ingredients"
+	PACKAGING   BillCategory = "packaging"
+	RENT        BillCategory = "rent"
+	// água, luz, gás, internet
+	UTILITIES   BillCategory = "utilities"
+	PAYROLL     BillCategory = "payroll"
+	TAXES       BillCategory = "taxes"
+	MAINTENANCE BillCategory = "maintenance"
+	MARKETING   BillCategory = "marketing"
+	OTHER       BillCategory = "other"
+)
+
+func (c BillCategory) IsValid() bool {
+	switch c {
+	case INGREDIENTS, PACKAGING, RENT, UTILITIES, PAYROLL, TAXES, MAINTENANCE, MARKETING, OTHER:
+		return true
+	}
+	return false
+}
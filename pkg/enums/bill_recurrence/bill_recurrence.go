@@ -0,0 +1,37 @@
+package billrecurrence
+
+import "time"
+
+type BillRecurrence string
+
+const (
+	NONE    BillRecurrence = "none"
+	WEEKLY  BillRecurrence = "weekly"
+	MONTHLY BillRecurrence = "monthly"
+	YEARLY  BillRecurrence = "yearly"
+)
+
+func (r BillRecurrence) IsValid() bool {
+	return r == NONE || r == WEEKLY || r == MONTHLY || r == YEARLY
+}
+
+// Next devolve o vencimento seguinte. No mensal e no anual dueDay é o dia original da série,
+// limitado ao fim do mês: a conta do dia 31 vence em 28/02 e volta ao dia 31 em março.
+func (r BillRecurrence) Next(dueDate time.Time, dueDay int) time.Time {
+	switch r {
+	case WEEKLY:
+		return dueDate.AddDate(0, 0, 7)
+	case MONTHLY:
+		return addMonths(dueDate, 1, dueDay)
+	case YEARLY:
+		return addMonths(dueDate, 12, dueDay)
+	}
+	return dueDate
+}
+
+func addMonths(date time.Time, months int, day int) time.Time {
+	year, month, _ := date.Date()
+	month += time.Month(months)
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, date.Location()).Day()
+	return time.Date(year, month, min(day, lastDay), 0, 0, 0, 0, date.Location())
+}
@@ -0,0 +1,15 @@
+package billstatus
+
+type BillStatus string
+
+const (
+	// a pagar, inclusive as vencidas e as pagas em parte
+	OPEN BillStatus = "open"
+	PAID BillStatus = "paid"
+	// cancelada antes de quitada; interrompe a recorrência
+	CANCELLED BillStatus = "cancelled"
+)
+
+func (s BillStatus) IsValid() bool {
+	return s == OPEN || s == PAID || s == CANCELLED
+}
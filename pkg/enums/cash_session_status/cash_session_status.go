@@ -0,0 +1,9 @@
+package cashsessionstatus
+
+type CashSessionStatus string
+
+const (
+	OPEN CashSessionStatus = "open"
+	// fechado com a contagem do operador; não recebe mais vendas nem movimentações
+	CLOSED CashSessionStatus = "closed"
+)
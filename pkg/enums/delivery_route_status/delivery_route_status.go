@@ -0,0 +1,23 @@
+package deliveryroutestatus
+
+type DeliveryRouteStatus string
+
+const (
+	// atribuída, aguardando o entregador aceitar
+	ASSIGNED DeliveryRouteStatus = "assigned"
+	ACCEPTED DeliveryRouteStatus = "accepted"
+	// pedidos retirados no restaurante
+	IN_TRANSIT DeliveryRouteStatus = "in_transit"
+	// todas as paradas entregues
+	COMPLETED DeliveryRouteStatus = "completed"
+	CANCELLED DeliveryRouteStatus = "cancelled"
+)
+
+func (s DeliveryRouteStatus) IsValid() bool {
+	return s == ASSIGNED || s == ACCEPTED || s == IN_TRANSIT || s == COMPLETED || s == CANCELLED
+}
+
+// IsActive indica que a rota ainda ocupa o entregador e os pedidos dela
+func (s DeliveryRouteStatus) IsActive() bool {
+	return s == ASSIGNED || s == ACCEPTED || s == IN_TRANSIT
+}
@@ -0,0 +1,14 @@
+package deliverystatus
+
+// DeliveryStatus é a etapa da entrega de um pedido, independente do preparo na cozinha
+type DeliveryStatus string
+
+const (
+	// aguardando um entregador
+	PENDING DeliveryStatus = "pending"
+	// em uma rota, ainda no restaurante
+	ASSIGNED DeliveryStatus = "assigned"
+	// retirada pelo entregador, a caminho do cliente
+	IN_TRANSIT DeliveryStatus = "in_transit"
+	DELIVERED  DeliveryStatus = "delivered"
+)
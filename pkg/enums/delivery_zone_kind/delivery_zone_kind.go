@@ -0,0 +1,15 @@
+package deliveryzonekind
+
+// DeliveryZoneKind é como a área de entrega foi desenhada
+type DeliveryZoneKind string
+
+const (
+	// polígono GeoJSON, conferido pelas coordenadas do endereço
+	POLYGON DeliveryZoneKind = "polygon"
+	// lista de bairros, conferida pelo nome do bairro; não depende de geocodificação
+	NEIGHBORHOODS DeliveryZoneKind = "neighborhoods"
+)
+
+func (k DeliveryZoneKind) IsValid() bool {
+	return k == POLYGON || k == NEIGHBORHOODS
+}
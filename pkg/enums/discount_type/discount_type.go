@@ -0,0 +1,14 @@
+package discounttype
+
+type DiscountType string
+
+const (
+	// Value é o percentual aplicado, de 0 a 100
+	PERCENTAGE DiscountType = "percentage"
+	// Value é o valor em reais abatido
+	FIXED DiscountType = "fixed"
+)
+
+func (t DiscountType) IsValid() bool {
+	return t == PERCENTAGE || t == FIXED
+}
@@ -0,0 +1,14 @@
+package driverkind
+
+type DriverKind string
+
+const (
+	// funcionário do restaurante
+	STAFF DriverKind = "staff"
+	// autônomo, acertado pelas taxas das entregas feitas
+	FREELANCER DriverKind = "freelancer"
+)
+
+func (k DriverKind) IsValid() bool {
+	return k == STAFF || k == FREELANCER
+}
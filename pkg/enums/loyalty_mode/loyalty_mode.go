@@ -0,0 +1,14 @@
+package loyaltymode
+
+type LoyaltyMode string
+
+const (
+	// regras dão pontos por real gasto nos produtos elegíveis
+	POINTS LoyaltyMode = "points"
+	// regras dão selos por unidade comprada, o clássico "compre 10, ganhe 1"
+	STAMPS LoyaltyMode = "stamps"
+)
+
+func (m LoyaltyMode) IsValid() bool {
+	return m == POINTS || m == STAMPS
+}
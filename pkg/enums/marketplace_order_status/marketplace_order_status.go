@@ -0,0 +1,16 @@
+package marketplaceorderstatus
+
+type MarketplaceOrderStatus string
+
+const (
+	// virou pedido na fila da cozinha
+	IMPORTED MarketplaceOrderStatus = "imported"
+	// não virou pedido, em geral por item sem vínculo com um produto; pode ser reimportado
+	FAILED MarketplaceOrderStatus = "failed"
+	// cancelado pelo marketplace
+	CANCELLED MarketplaceOrderStatus = "cancelled"
+)
+
+func (s MarketplaceOrderStatus) IsValid() bool {
+	return s == IMPORTED || s == FAILED || s == CANCELLED
+}
@@ -0,0 +1,15 @@
+package marketplacepushstatus
+
+type MarketplacePushStatus string
+
+const (
+	// na fila, esperando a próxima tentativa
+	PENDING MarketplacePushStatus = "pending"
+	SENT    MarketplacePushStatus = "sent"
+	// o marketplace recusou ou as tentativas acabaram
+	FAILED MarketplacePushStatus = "failed"
+)
+
+func (s MarketplacePushStatus) IsValid() bool {
+	return s == PENDING || s == SENT || s == FAILED
+}
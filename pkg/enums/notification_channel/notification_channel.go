@@ -0,0 +1,13 @@
+package notificationchannel
+
+type NotificationChannel string
+
+const (
+	EMAIL    NotificationChannel = "email"
+	WHATSAPP NotificationChannel = "whatsapp"
+	SMS      NotificationChannel = "sms"
+)
+
+func (c NotificationChannel) IsValid() bool {
+	return c == EMAIL || c == WHATSAPP || c == SMS
+}
@@ -0,0 +1,15 @@
+package notificationstatus
+
+type NotificationStatus string
+
+const (
+	// reservada, ainda tentando enviar
+	PENDING NotificationStatus = "pending"
+	SENT    NotificationStatus = "sent"
+	// esgotou as tentativas ou o provedor recusou a mensagem
+	FAILED NotificationStatus = "failed"
+)
+
+func (s NotificationStatus) IsValid() bool {
+	return s == PENDING || s == SENT || s == FAILED
+}
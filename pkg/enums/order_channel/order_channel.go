@@ -0,0 +1,19 @@
+package orderchannel
+
+// OrderChannel é por onde o pedido chegou ao restaurante
+type OrderChannel string
+
+const (
+	// lançado no próprio sistema: balcão, salão ou catálogo do restaurante
+	DIRECT OrderChannel = "direct"
+	IFOOD  OrderChannel = "ifood"
+)
+
+func (c OrderChannel) IsValid() bool {
+	return c == DIRECT || c == IFOOD
+}
+
+// IsMarketplace diz se o pedido foi vendido por um marketplace, que recebe as mudanças de status
+func (c OrderChannel) IsMarketplace() bool {
+	return c == IFOOD
+}
@@ -0,0 +1,20 @@
+package orderstatus
+
+type OrderStatus string
+
+const (
+	// recebido, aguardando a cozinha
+	PENDING OrderStatus = "pending"
+	// em preparo
+	PREPARING OrderStatus = "preparing"
+	// pronto para retirada ou entrega
+	READY OrderStatus = "ready"
+	// entregue ao cliente
+	COMPLETED OrderStatus = "completed"
+	CANCELLED OrderStatus = "cancelled"
+)
+
+// IsInKitchen indica se o pedido ainda aparece na fila da cozinha
+func (s OrderStatus) IsInKitchen() bool {
+	return s == PENDING || s == PREPARING || s == READY
+}
@@ -0,0 +1,24 @@
+package paymentmethod
+
+type PaymentMethod string
+
+const (
+	CASH         PaymentMethod = "cash"
+	PIX          PaymentMethod = "pix"
+	CREDIT_CARD  PaymentMethod = "credit_card"
+	DEBIT_CARD   PaymentMethod = "debit_card"
+	MEAL_VOUCHER PaymentMethod = "meal_voucher"
+	FOOD_VOUCHER PaymentMethod = "food_voucher"
+	// pago online ao marketplace, que repassa o valor; não passa pelo caixa
+	MARKETPLACE PaymentMethod = "marketplace"
+	// lançado no fiado do cliente, que paga pela conta; não passa pelo caixa
+	POST_PAID PaymentMethod = "post_paid"
+)
+
+func (m PaymentMethod) IsValid() bool {
+	switch m {
+	case CASH, PIX, CREDIT_CARD, DEBIT_CARD, MEAL_VOUCHER, FOOD_VOUCHER, MARKETPLACE, POST_PAID:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package stockitemkind
+
+type StockItemKind string
+
+const (
+	INGREDIENT StockItemKind = "ingredient"
+	// embalagens, talheres e sacolas que saem com o pedido
+	PACKAGING StockItemKind = "packaging"
+)
+
+func (k StockItemKind) IsValid() bool {
+	return k == INGREDIENT || k == PACKAGING
+}
@@ -0,0 +1,15 @@
+package stockmovementtype
+
+type StockMovementType string
+
+const (
+	PURCHASE    StockMovementType = "purchase"
+	CONSUMPTION StockMovementType = "consumption"
+	LOSS        StockMovementType = "loss"
+	// correção de contagem, para mais ou para menos
+	ADJUSTMENT StockMovementType = "adjustment"
+)
+
+func (t StockMovementType) IsValid() bool {
+	return t == PURCHASE || t == CONSUMPTION || t == LOSS || t == ADJUSTMENT
+}
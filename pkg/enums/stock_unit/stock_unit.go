@@ -0,0 +1,14 @@
+package stockunit
+
+type StockUnit string
+
+const (
+	KILOGRAM StockUnit = "kg"
+	LITER    StockUnit = "l"
+	// unidades avulsas: embalagens, talheres, latas
+	UNIT StockUnit = "un"
+)
+
+func (u StockUnit) IsValid() bool {
+	return u == KILOGRAM || u == LITER || u == UNIT
+}
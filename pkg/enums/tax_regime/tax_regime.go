@@ -0,0 +1,21 @@
+package taxregime
+
+// TaxRegime segue o código de regime tributário (CRT) do layout da NF-e
+type TaxRegime int
+
+const (
+	SIMPLES_NACIONAL TaxRegime = 1
+	// simples nacional com receita acima do sublimite estadual
+	SIMPLES_NACIONAL_EXCESS TaxRegime = 2
+	NORMAL                  TaxRegime = 3
+	MEI                     TaxRegime = 4
+)
+
+func (r TaxRegime) IsValid() bool {
+	return r >= SIMPLES_NACIONAL && r <= MEI
+}
+
+// IsSimples indica os regimes que tributam o ICMS por CSOSN
+func (r TaxRegime) IsSimples() bool {
+	return r == SIMPLES_NACIONAL || r == SIMPLES_NACIONAL_EXCESS || r == MEI
+}
@@ -0,0 +1,100 @@
+package uf
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// UF é a sigla da unidade federativa, como aparece no CEP, na NF-e e nas telas
+type UF string
+
+const (
+	AC UF = "AC"
+	AL UF = "AL"
+	AP UF = "AP"
+	AM UF = "AM"
+	BA UF = "BA"
+	CE UF = "CE"
+	DF UF = "DF"
+	ES UF = "ES"
+	GO UF = "GO"
+	MA UF = "MA"
+	MT UF = "MT"
+	MS UF = "MS"
+	MG UF = "MG"
+	PA UF = "PA"
+	PB UF = "PB"
+	PR UF = "PR"
+	PE UF = "PE"
+	PI UF = "PI"
+	RJ UF = "RJ"
+	RN UF = "RN"
+	RS UF = "RS"
+	RO UF = "RO"
+	RR UF = "RR"
+	SC UF = "SC"
+	SP UF = "SP"
+	SE UF = "SE"
+	TO UF = "TO"
+)
+
+// states guarda o nome por extenso e o código IBGE de cada UF
+var states = map[UF]struct {
+	name string
+	code string
+}{
+	RO: {"Rondônia", "11"}, AC: {"Acre", "12"}, AM: {"Amazonas", "13"},
+	RR: {"Roraima", "14"}, PA: {"Pará", "15"}, AP: {"Amapá", "16"},
+	TO: {"Tocantins", "17"}, MA: {"Maranhão", "21"}, PI: {"Piauí", "22"},
+	CE: {"Ceará", "23"}, RN: {"Rio Grande do Norte", "24"}, PB: {"Paraíba", "25"},
+	PE: {"Pernambuco", "26"}, AL: {"Alagoas", "27"}, SE: {"Sergipe", "28"},
+	BA: {"Bahia", "29"}, MG: {"Minas Gerais", "31"}, ES: {"Espírito Santo", "32"},
+	RJ: {"Rio de Janeiro", "33"}, SP: {"São Paulo", "35"}, PR: {"Paraná", "41"},
+	SC: {"Santa Catarina", "42"}, RS: {"Rio Grande do Sul", "43"}, MS: {"Mato Grosso do Sul", "50"},
+	MT: {"Mato Grosso", "51"}, GO: {"Goiás", "52"}, DF: {"Distrito Federal", "53"},
+}
+
+func (u UF) IsValid() bool {
+	_, ok := states[u]
+	return ok
+}
+
+// Name devolve o nome por extenso, com acentos
+func (u UF) Name() string {
+	return states[u].name
+}
+
+// IBGECode é o código numérico da UF, usado na chave de acesso da NF-e
+func (u UF) IBGECode() string {
+	return states[u].code
+}
+
+// Parse aceita a sigla ou o nome do estado, sem diferenciar maiúsculas nem acentos
+func Parse(value string) (UF, bool) {
+	value = strings.TrimSpace(value)
+
+	if candidate := UF(strings.ToUpper(value)); candidate.IsValid() {
+		return candidate, true
+	}
+
+	folded := fold(value)
+	for candidate, state := range states {
+		if fold(state.name) == folded {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+func fold(value string) string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(value)) {
+		if !unicode.Is(unicode.Mn, r) {
+			builder.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
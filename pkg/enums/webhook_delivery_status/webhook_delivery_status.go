@@ -0,0 +1,15 @@
+package webhookdeliverystatus
+
+type WebhookDeliveryStatus string
+
+const (
+	// aguardando a primeira tentativa ou a próxima depois de uma falha
+	PENDING   WebhookDeliveryStatus = "pending"
+	DELIVERED WebhookDeliveryStatus = "delivered"
+	// esgotou as tentativas; só volta a ser enviada por reenvio manual
+	DEAD WebhookDeliveryStatus = "dead"
+)
+
+func (s WebhookDeliveryStatus) IsValid() bool {
+	return s == PENDING || s == DELIVERED || s == DEAD
+}
@@ -0,0 +1,245 @@
+package escpos
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+type (
+	PaperWidth int
+	Alignment  byte
+)
+
+const (
+	Paper58mm PaperWidth = 58
+	Paper80mm PaperWidth = 80
+)
+
+const (
+	AlignLeft   Alignment = 0
+	AlignCenter Alignment = 1
+	AlignRight  Alignment = 2
+)
+
+const (
+	esc byte = 0x1b
+	gs  byte = 0x1d
+
+	// tabela de caracteres PC860 (português), selecionada com ESC t 3
+	codePagePortuguese byte = 3
+)
+
+func ParsePaperWidth(value string) (PaperWidth, bool) {
+	width, err := strconv.Atoi(strings.TrimSuffix(value, "mm"))
+	if err != nil {
+		return 0, false
+	}
+
+	switch PaperWidth(width) {
+	case Paper58mm, Paper80mm:
+		return PaperWidth(width), true
+	default:
+		return 0, false
+	}
+}
+
+// Columns é a quantidade de caracteres por linha na fonte padrão (12x24)
+func (p PaperWidth) Columns() int {
+	if p == Paper58mm {
+		return 32
+	}
+	return 48
+}
+
+// Document monta ao mesmo tempo o fluxo ESC/POS enviado à impressora e
+// uma prévia em texto puro com o mesmo layout
+type Document struct {
+	paper      PaperWidth
+	align      Alignment
+	doubleSize bool
+	raw        bytes.Buffer
+	text       strings.Builder
+}
+
+func NewDocument(paper PaperWidth) *Document {
+	d := &Document{paper: paper}
+	d.raw.Write([]byte{esc, '@'})
+	d.raw.Write([]byte{esc, 't', codePagePortuguese})
+	return d
+}
+
+func (d *Document) Paper() PaperWidth {
+	return d.paper
+}
+
+// Width é a largura útil da linha considerando o tamanho de fonte atual
+func (d *Document) Width() int {
+	if d.doubleSize {
+		return d.paper.Columns() / 2
+	}
+	return d.paper.Columns()
+}
+
+func (d *Document) Align(alignment Alignment) *Document {
+	d.align = alignment
+	d.raw.Write([]byte{esc, 'a', byte(alignment)})
+	return d
+}
+
+func (d *Document) Bold(on bool) *Document {
+	d.raw.Write([]byte{esc, 'E', flag(on)})
+	return d
+}
+
+// DoubleSize dobra altura e largura dos caracteres
+func (d *Document) DoubleSize(on bool) *Document {
+	d.doubleSize = on
+
+	size := byte(0x00)
+	if on {
+		size = 0x11
+	}
+	d.raw.Write([]byte{gs, '!', size})
+	return d
+}
+
+// Line escreve o texto quebrando palavras na largura do papel
+func (d *Document) Line(text string) *Document {
+	for _, line := range wrap(text, d.Width()) {
+		d.writeLine(line)
+	}
+	return d
+}
+
+// Justify escreve left à esquerda e right encostado na margem direita,
+// quebrando left quando os dois não cabem na mesma linha
+func (d *Document) Justify(left, right string) *Document {
+	width := d.Width()
+	available := width - utf8.RuneCountInString(right) - 1
+	if available < 1 {
+		return d.Line(left).Line(right)
+	}
+
+	lines := wrap(left, available)
+	for _, line := range lines[:len(lines)-1] {
+		d.writeLine(line)
+	}
+
+	last := lines[len(lines)-1]
+	gap := width - utf8.RuneCountInString(last) - utf8.RuneCountInString(right)
+	d.writeLine(last + strings.Repeat(" ", gap) + right)
+	return d
+}
+
+func (d *Document) Separator(char rune) *Document {
+	d.writeLine(strings.Repeat(string(char), d.Width()))
+	return d
+}
+
+func (d *Document) Feed(lines int) *Document {
+	d.raw.Write([]byte{esc, 'd', byte(lines)})
+	d.text.WriteString(strings.Repeat("\n", lines))
+	return d
+}
+
+// Cut avança o papel e faz o corte parcial
+func (d *Document) Cut() *Document {
+	d.raw.Write([]byte{gs, 'V', 66, 3})
+	return d
+}
+
+func (d *Document) Bytes() []byte {
+	return d.raw.Bytes()
+}
+
+func (d *Document) Text() string {
+	return d.text.String()
+}
+
+func (d *Document) writeLine(line string) {
+	d.raw.Write(encode(line))
+	d.raw.WriteByte('\n')
+
+	length := utf8.RuneCountInString(line)
+	if d.doubleSize {
+		length *= 2
+	}
+
+	padding := 0
+	switch d.align {
+	case AlignCenter:
+		padding = (d.paper.Columns() - length) / 2
+	case AlignRight:
+		padding = d.paper.Columns() - length
+	}
+
+	d.text.WriteString(strings.Repeat(" ", max(padding, 0)))
+	d.text.WriteString(line)
+	d.text.WriteByte('\n')
+}
+
+func encode(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		b, ok := charmap.CodePage860.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		encoded = append(encoded, b)
+	}
+	return encoded
+}
+
+// wrap quebra o texto por palavras; a indentação inicial é repetida nas linhas seguintes
+func wrap(text string, width int) []string {
+	indent := text[:len(text)-len(strings.TrimLeft(text, " "))]
+	if len(indent) >= width {
+		indent = ""
+	}
+	width -= len(indent)
+
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	lines := make([]string, 0, 1)
+	current := make([]rune, 0, width)
+
+	for _, word := range words {
+		runes := []rune(word)
+
+		if len(current) > 0 && len(current)+1+len(runes) > width {
+			lines = append(lines, indent+string(current))
+			current = current[:0]
+		}
+
+		// palavras maiores que a linha são partidas
+		for len(runes) > width {
+			if len(current) > 0 {
+				lines = append(lines, indent+string(current))
+				current = current[:0]
+			}
+			lines = append(lines, indent+string(runes[:width]))
+			runes = runes[width:]
+		}
+
+		if len(current) > 0 {
+			current = append(current, ' ')
+		}
+		current = append(current, runes...)
+	}
+
+	return append(lines, indent+string(current))
+}
+
+func flag(on bool) byte {
+	if on {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// Idempotency guarda a resposta de requisições com Idempotency-Key e a reenvia em novas tentativas.
+// A chave vale por restaurante da rota e usuário autenticado. Requisições sem o cabeçalho seguem normalmente.
+func Idempotency(repository ports.IIdempotencyRepository, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key too long"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Unable to read body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := &ports.IdempotencyRecord{
+			Scope:       idempotencyScope(c, key),
+			Fingerprint: requestFingerprint(c.Request.Method, c.Request.URL.Path, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		reserved, err := repository.Reserve(record)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to reserve idempotency key"})
+			return
+		}
+
+		if !reserved {
+			existing, err := repository.Find(record.Scope)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to read idempotency key"})
+				return
+			}
+
+			// chave expirada: descarta o registro antigo e processa como nova requisição
+			if existing == nil || existing.ExpiresAt.Before(now) {
+				if existing != nil {
+					if err := repository.Delete(record.Scope); err != nil {
+						c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to release idempotency key"})
+						return
+					}
+				}
+
+				reserved, err = repository.Reserve(record)
+				if err != nil || !reserved {
+					c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Request with this Idempotency-Key is already being processed"})
+					return
+				}
+			} else {
+				replay(c, existing, record.Fingerprint)
+				return
+			}
+		}
+
+		// um panic no handler não pode deixar a chave presa como "em processamento";
+		// ela é liberada e o panic segue para o Recovery responder
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				releaseIdempotencyKey(repository, record.Scope)
+				panic(recovered)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+
+		c.Next()
+
+		status := recorder.Status()
+		// erros de servidor não são definitivos: libera a chave para que o cliente possa tentar de novo
+		if status >= http.StatusInternalServerError {
+			releaseIdempotencyKey(repository, record.Scope)
+			return
+		}
+
+		record.StatusCode = status
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.Body = recorder.body.Bytes()
+		if err := repository.Complete(record); err != nil {
+			// sem a resposta gravada a chave ficaria "em processamento" até expirar e toda nova tentativa
+			// receberia 409; liberada, a nova tentativa é processada outra vez
+			log.Printf("⚠️ failed to store idempotent response for key %s: %v", key, err)
+			releaseIdempotencyKey(repository, record.Scope)
+		}
+	}
+}
+
+// idempotencyScope usa o restaurante da rota e o e-mail do usuário autenticado; rotas sem restaurante
+// ou sem autenticação ficam com o campo vazio
+func idempotencyScope(c *gin.Context, key string) ports.IdempotencyScope {
+	scope := ports.IdempotencyScope{RestaurantId: c.Param("restaurantId"), Key: key}
+	if principal, ok := c.Get(PrincipalKey); ok {
+		if payload, ok := principal.(ports.AuthPayload); ok {
+			scope.Principal = payload.Email
+		}
+	}
+	return scope
+}
+
+func releaseIdempotencyKey(repository ports.IIdempotencyRepository, scope ports.IdempotencyScope) {
+	if err := repository.Delete(scope); err != nil {
+		log.Printf("⚠️ failed to release idempotency key %s: %v", scope.Key, err)
+	}
+}
+
+func replay(c *gin.Context, existing *ports.IdempotencyRecord, fingerprint string) {
+	if existing.Fingerprint != fingerprint {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Idempotency-Key already used with a different request"})
+		return
+	}
+
+	if existing.StatusCode == 0 {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Request with this Idempotency-Key is already being processed"})
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+	c.Abort()
+}
+
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
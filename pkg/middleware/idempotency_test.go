@@ -0,0 +1,191 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PedroNetto404/marmitech-backend/internal/domain/ports"
+	"github.com/PedroNetto404/marmitech-backend/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryIdempotencyRepository struct {
+	records      map[ports.IdempotencyScope]ports.IdempotencyRecord
+	failComplete bool
+}
+
+func newMemoryIdempotencyRepository() *memoryIdempotencyRepository {
+	return &memoryIdempotencyRepository{records: map[ports.IdempotencyScope]ports.IdempotencyRecord{}}
+}
+
+func (m *memoryIdempotencyRepository) Reserve(record *ports.IdempotencyRecord) (bool, error) {
+	if _, ok := m.records[record.Scope]; ok {
+		return false, nil
+	}
+	m.records[record.Scope] = *record
+	return true, nil
+}
+
+func (m *memoryIdempotencyRepository) Find(scope ports.IdempotencyScope) (*ports.IdempotencyRecord, error) {
+	record, ok := m.records[scope]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (m *memoryIdempotencyRepository) Complete(record *ports.IdempotencyRecord) error {
+	if m.failComplete {
+		return errors.New("database unavailable")
+	}
+	m.records[record.Scope] = *record
+	return nil
+}
+
+func (m *memoryIdempotencyRepository) Delete(scope ports.IdempotencyScope) error {
+	delete(m.records, scope)
+	return nil
+}
+
+func (m *memoryIdempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newIdempotentEngine(calls *int) *gin.Engine {
+	return newIdempotentEngineWith(newMemoryIdempotencyRepository(), calls)
+}
+
+func newIdempotentEngineWith(repository *memoryIdempotencyRepository, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(gin.CustomRecovery(func(c *gin.Context, err any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	engine.Use(func(c *gin.Context) {
+		if email := c.GetHeader("X-Test-User"); email != "" {
+			c.Set(middleware.PrincipalKey, ports.AuthPayload{Email: email})
+		}
+		c.Next()
+	})
+	handler := func(c *gin.Context) {
+		*calls++
+		if c.Query("panic") == "true" && *calls == 1 {
+			panic("handler failed")
+		}
+		c.JSON(http.StatusCreated, gin.H{"call": *calls})
+	}
+	engine.POST("/customers", middleware.Idempotency(repository, time.Hour), handler)
+	engine.POST("/restaurants/:restaurantId/customers", middleware.Idempotency(repository, time.Hour), handler)
+	return engine
+}
+
+func postTo(engine *gin.Engine, path, user, key, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request.Header.Set(middleware.IdempotencyKeyHeader, key)
+	if user != "" {
+		request.Header.Set("X-Test-User", user)
+	}
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func post(engine *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	return postTo(engine, "/customers", "", key, body)
+}
+
+func TestIdempotencyReplaysOriginalResponse(t *testing.T) {
+	// arrange
+	calls := 0
+	engine := newIdempotentEngine(&calls)
+
+	// act
+	first := post(engine, "abc", `{"name":"Maria"}`)
+	second := post(engine, "abc", `{"name":"Maria"}`)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(1, calls, "o handler deve executar uma única vez")
+	assert.Equal(http.StatusCreated, second.Code)
+	assert.Equal(first.Body.String(), second.Body.String(), "a resposta original deve ser reenviada")
+	assert.Equal("true", second.Header().Get(middleware.IdempotentReplayedHeader))
+}
+
+func TestIdempotencyRejectsDifferentBodyWithSameKey(t *testing.T) {
+	// arrange
+	calls := 0
+	engine := newIdempotentEngine(&calls)
+
+	// act
+	post(engine, "abc", `{"name":"Maria"}`)
+	second := post(engine, "abc", `{"name":"João"}`)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(1, calls)
+	assert.Equal(http.StatusConflict, second.Code, "mesma chave com corpo diferente deve retornar 409")
+}
+
+func TestIdempotencyKeyIsScopedByRestaurantAndUser(t *testing.T) {
+	// arrange
+	calls := 0
+	engine := newIdempotentEngine(&calls)
+
+	// act
+	postTo(engine, "/restaurants/r1/customers", "maria@marmitech.com", "abc", `{}`)
+	otherUser := postTo(engine, "/restaurants/r1/customers", "joao@marmitech.com", "abc", `{}`)
+	otherRestaurant := postTo(engine, "/restaurants/r2/customers", "maria@marmitech.com", "abc", `{}`)
+	sameScope := postTo(engine, "/restaurants/r1/customers", "maria@marmitech.com", "abc", `{}`)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(3, calls, "a mesma chave de outro usuário ou restaurante é outra requisição")
+	assert.Empty(otherUser.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Empty(otherRestaurant.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal("true", sameScope.Header().Get(middleware.IdempotentReplayedHeader), "mesmo escopo reenvia a resposta")
+}
+
+func TestIdempotencyReleasesKeyWhenHandlerPanics(t *testing.T) {
+	// arrange
+	calls := 0
+	engine := newIdempotentEngine(&calls)
+
+	// act
+	first := postTo(engine, "/customers?panic=true", "", "abc", `{}`)
+	retry := postTo(engine, "/customers?panic=true", "", "abc", `{}`)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(http.StatusInternalServerError, first.Code)
+	assert.Equal(http.StatusCreated, retry.Code, "a chave não fica presa como em processamento")
+	assert.Equal(2, calls)
+}
+
+func TestIdempotencyReleasesKeyWhenResponseCannotBeStored(t *testing.T) {
+	// arrange
+	calls := 0
+	repository := newMemoryIdempotencyRepository()
+	repository.failComplete = true
+	engine := newIdempotentEngineWith(repository, &calls)
+
+	// act
+	post(engine, "abc", `{}`)
+	repository.failComplete = false
+	retry := post(engine, "abc", `{}`)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.Equal(http.StatusCreated, retry.Code, "nova tentativa não recebe 409 por uma chave sem resposta")
+	assert.Equal(2, calls)
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,6 +11,12 @@ import (
 
 func RouteTimeout(timeout int) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// streams SSE ficam abertos enquanto o cliente estiver conectado
+		if strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(timeout)*time.Second)
 		defer cancel()
 
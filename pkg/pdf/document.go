@@ -0,0 +1,282 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	// decodificadores registrados para AddImage
+	_ "image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+// dimensões em pontos (1/72 de polegada)
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+var fontNames = map[Font]string{
+	Helvetica:     "Helvetica",
+	HelveticaBold: "Helvetica-Bold",
+}
+
+type (
+	// Image é uma imagem já convertida para RGB comprimido, pronta para ser embutida
+	Image struct {
+		name   string
+		width  int
+		height int
+		data   []byte
+	}
+
+	// Page recebe coordenadas a partir do canto superior esquerdo, como em telas;
+	// a conversão para o sistema do PDF (origem embaixo) é feita internamente
+	Page struct {
+		height  float64
+		content bytes.Buffer
+	}
+
+	// Document gera PDFs 1.4 só com as fontes padrão, sem dependências externas
+	Document struct {
+		width  float64
+		height float64
+		pages  []*Page
+		images []*Image
+	}
+)
+
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+func (d *Document) Width() float64 {
+	return d.width
+}
+
+func (d *Document) Height() float64 {
+	return d.height
+}
+
+func (d *Document) AddPage() *Page {
+	page := &Page{height: d.height}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// AddImage aceita PNG ou JPEG; a transparência é composta sobre fundo branco
+func (d *Document) AddImage(data []byte) (*Image, error) {
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := decoded.Bounds()
+	rgb := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := decoded.At(x, y).RGBA()
+			background := 0xffff - a
+			rgb = append(rgb, byte((r+background)>>8), byte((g+background)>>8), byte((b+background)>>8))
+		}
+	}
+
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	if _, err := writer.Write(rgb); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	img := &Image{
+		name:   fmt.Sprintf("Im%d", len(d.images)+1),
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		data:   compressed.Bytes(),
+	}
+	d.images = append(d.images, img)
+	return img, nil
+}
+
+func (i *Image) Size() (int, int) {
+	return i.width, i.height
+}
+
+// Text escreve uma linha com a linha de base em y
+func (p *Page) Text(x, y float64, font Font, size float64, text string) {
+	fmt.Fprintf(
+		&p.content,
+		"BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		font+1,
+		number(size),
+		number(x),
+		number(p.height-y),
+		escape(text),
+	)
+}
+
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(
+		&p.content,
+		"%s w %s %s m %s %s l S\n",
+		number(width),
+		number(x1),
+		number(p.height-y1),
+		number(x2),
+		number(p.height-y2),
+	)
+}
+
+// FillRect pinta um retângulo em tons de cinza (0 preto, 1 branco)
+func (p *Page) FillRect(x, y, width, height, gray float64) {
+	fmt.Fprintf(
+		&p.content,
+		"q %s g %s %s %s %s re f Q\n",
+		number(gray),
+		number(x),
+		number(p.height-y-height),
+		number(width),
+		number(height),
+	)
+}
+
+func (p *Page) Image(img *Image, x, y, width, height float64) {
+	fmt.Fprintf(
+		&p.content,
+		"q %s 0 0 %s %s %s cm /%s Do Q\n",
+		number(width),
+		number(height),
+		number(x),
+		number(p.height-y-height),
+		img.name,
+	)
+}
+
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	offsets := make([]int, 0, 4+len(d.images)+2*len(d.pages))
+
+	begin := func() int {
+		offsets = append(offsets, out.Len())
+		id := len(offsets)
+		fmt.Fprintf(&out, "%d 0 obj\n", id)
+		return id
+	}
+	end := func() {
+		out.WriteString("endobj\n")
+	}
+
+	// numeração: 1 catálogo, 2 páginas, 3-4 fontes, imagens e então página/conteúdo
+	firstPage := 5 + len(d.images)
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	begin()
+	out.WriteString("<< /Type /Catalog /Pages 2 0 R >>\n")
+	end()
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	begin()
+	fmt.Fprintf(&out, "<< /Type /Pages /Kids [%s] /Count %d >>\n", strings.Join(kids, " "), len(d.pages))
+	end()
+
+	for _, font := range []Font{Helvetica, HelveticaBold} {
+		begin()
+		fmt.Fprintf(&out, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\n", fontNames[font])
+		end()
+	}
+
+	xObjects := make([]string, len(d.images))
+	for i, img := range d.images {
+		id := begin()
+		fmt.Fprintf(
+			&out,
+			"<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n",
+			img.width,
+			img.height,
+			len(img.data),
+		)
+		out.Write(img.data)
+		out.WriteString("\nendstream\n")
+		end()
+		xObjects[i] = fmt.Sprintf("/%s %d 0 R", img.name, id)
+	}
+
+	resources := "/Font << /F1 3 0 R /F2 4 0 R >>"
+	if len(xObjects) > 0 {
+		resources += " /XObject << " + strings.Join(xObjects, " ") + " >>"
+	}
+
+	for _, page := range d.pages {
+		id := begin()
+		fmt.Fprintf(
+			&out,
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << %s >> /Contents %d 0 R >>\n",
+			number(d.width),
+			number(d.height),
+			resources,
+			id+1,
+		)
+		end()
+
+		begin()
+		fmt.Fprintf(&out, "<< /Length %d >>\nstream\n", page.content.Len())
+		out.Write(page.content.Bytes())
+		out.WriteString("endstream\n")
+		end()
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+func number(value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', 2, 64)
+	formatted = strings.TrimRight(formatted, "0")
+	return strings.TrimSuffix(formatted, ".")
+}
+
+// escape converte para WinAnsi (cp1252) e protege os delimitadores de string do PDF
+func escape(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		b, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+
+		switch b {
+		case '\\', '(', ')':
+			escaped.WriteByte('\\')
+			escaped.WriteByte(b)
+		case '\n', '\r':
+			escaped.WriteByte(' ')
+		default:
+			escaped.WriteByte(b)
+		}
+	}
+	return escaped.String()
+}
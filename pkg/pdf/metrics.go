@@ -0,0 +1,78 @@
+package pdf
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// larguras das fontes padrão (AFM da Adobe) para os caracteres ASCII 32..126,
+// em milésimos do tamanho da fonte
+var widths = map[Font][95]int{
+	Helvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	HelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+const defaultGlyphWidth = 556
+
+// TextWidth mede o texto em pontos; letras acentuadas têm a largura da letra base
+func TextWidth(font Font, size float64, text string) float64 {
+	table := widths[font]
+
+	total := 0
+	for _, r := range text {
+		total += glyphWidth(table, r)
+	}
+
+	return float64(total) * size / 1000
+}
+
+// WrapText quebra o texto por palavras para caber em maxWidth
+func WrapText(font Font, size float64, text string, maxWidth float64) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	lines := make([]string, 0, 1)
+	current := words[0]
+	for _, word := range words[1:] {
+		candidate := current + " " + word
+		if TextWidth(font, size, candidate) > maxWidth {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current = candidate
+	}
+
+	return append(lines, current)
+}
+
+func glyphWidth(table [95]int, r rune) int {
+	if r >= 32 && r <= 126 {
+		return table[r-32]
+	}
+
+	base := []rune(norm.NFD.String(string(r)))
+	if len(base) > 1 && base[0] >= 32 && base[0] <= 126 && unicode.Is(unicode.Mn, base[1]) {
+		return table[base[0]-32]
+	}
+
+	return defaultGlyphWidth
+}
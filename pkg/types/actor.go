@@ -0,0 +1,15 @@
+package types
+
+// Actor identifica quem executou uma operação: o usuário autenticado e a origem da requisição.
+type Actor struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	Ip    string `json:"ip"`
+}
+
+// ManagerRole é o papel de quem administra o restaurante e pode ver custos e margens
+const ManagerRole = "admin"
+
+func (a Actor) IsManager() bool {
+	return a.Role == ManagerRole
+}
@@ -1,6 +1,14 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/PedroNetto404/marmitech-backend/pkg/enums/uf"
+	"golang.org/x/text/unicode/norm"
+)
 
 type Address struct {
 	Id         string  `json:"id"`
@@ -10,44 +18,59 @@ type Address struct {
 	Complement string  `json:"complement"`
 	Neighborhood string  `json:"neighborhood"`
 	City         string  `json:"city"`
-	State        string  `json:"state"`
+	State        uf.UF   `json:"state"`
 	Country      string  `json:"country"`
 	ZipCode      string  `json:"zip_code"`
 	Lat          float64 `json:"lat"`
 	Lng          float64 `json:"lng"`
 }
 
-type State string
+// GeoPoint é uma coordenada em graus decimais (WGS 84)
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
 
-const (
-	Acre             State = "Acre"
-	Alagoas          State = "Alagoas"
-	Amapá            State = "Amapá"
-	Amazonas         State = "Amazonas"
-	Bahia            State = "Bahia"
-	Ceará            State = "Ceará"
-	DistritoFederal  State = "Distrito Federal"
-	EspíritoSanto    State = "Espírito Santo"
-	Goiás            State = "Goiás"
-	Maranhão         State = "Maranhão"
-	MatoGrosso       State = "Mato Grosso"
-	MatoGrossoDoSul  State = "Mato Grosso do Sul"
-	MinasGerais      State = "Minas Gerais"
-	Pará             State = "Pará"
-	Paraíba          State = "Paraíba"
-	Paraná           State = "Paraná"
-	Pernambuco       State = "Pernambuco"
-	Piauí            State = "Piauí"
-	RioDeJaneiro     State = "Rio de Janeiro"
-	RioGrandeDoNorte State = "Rio Grande do Norte"
-	RioGrandeDoSul   State = "Rio Grande do Sul"
-	Rondônia         State = "Rondônia"
-	Roraima          State = "Roraima"
-	SantaCatarina    State = "Santa Catarina"
-	SãoPaulo         State = "São Paulo"
-	Sergipe          State = "Sergipe"
-	Tocantins        State = "Tocantins"
-)
+const earthRadiusKm = 6371.0
+
+// DistanceKm é a distância em linha reta pela fórmula de haversine
+func (p GeoPoint) DistanceKm(other GeoPoint) float64 {
+	lat1, lat2 := p.Lat*math.Pi/180, other.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (other.Lng - p.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+func (a Address) Point() GeoPoint {
+	return GeoPoint{Lat: a.Lat, Lng: a.Lng}
+}
+
+// HasCoordinates trata 0,0 como endereço ainda não geocodificado
+func (a Address) HasCoordinates() bool {
+	return a.Lat != 0 || a.Lng != 0
+}
+
+// SameLocation compara só os campos que definem onde o endereço fica
+func (a Address) SameLocation(other Address) bool {
+	return FoldName(a.Street) == FoldName(other.Street) &&
+		FoldName(a.Number) == FoldName(other.Number) &&
+		FoldName(a.City) == FoldName(other.City) &&
+		a.State == other.State &&
+		a.ZipCode == other.ZipCode
+}
+
+// FoldName deixa nomes de lugar comparáveis: minúsculas, sem acentos e sem espaços repetidos
+func FoldName(name string) string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		if !unicode.Is(unicode.Mn, r) {
+			builder.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
 
 func (a Address) String() string {
 	return fmt.Sprintf(
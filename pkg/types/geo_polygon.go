@@ -0,0 +1,114 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidGeoPolygon = errors.New("invalid GeoJSON polygon")
+
+// GeoPolygon é um Polygon do GeoJSON: o primeiro anel é o contorno e os demais são buracos.
+// No JSON as posições seguem o GeoJSON, [longitude, latitude]
+type GeoPolygon struct {
+	Rings [][]GeoPoint
+}
+
+type geoJson struct {
+	Type        string          `json:"type"`
+	Coordinates [][][2]float64  `json:"coordinates,omitempty"`
+	Geometry    json.RawMessage `json:"geometry,omitempty"`
+}
+
+func (p GeoPolygon) MarshalJSON() ([]byte, error) {
+	coordinates := make([][][2]float64, 0, len(p.Rings))
+	for _, ring := range p.Rings {
+		positions := make([][2]float64, 0, len(ring))
+		for _, point := range ring {
+			positions = append(positions, [2]float64{point.Lng, point.Lat})
+		}
+		coordinates = append(coordinates, positions)
+	}
+
+	return json.Marshal(geoJson{Type: "Polygon", Coordinates: coordinates})
+}
+
+// UnmarshalJSON aceita o Polygon ou uma Feature com ele, que é o que os editores de mapa exportam;
+// anéis que não repetem a primeira posição no fim são fechados
+func (p *GeoPolygon) UnmarshalJSON(data []byte) error {
+	var value geoJson
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGeoPolygon, err)
+	}
+
+	if value.Type == "Feature" {
+		if err := json.Unmarshal(value.Geometry, &value); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidGeoPolygon, err)
+		}
+	}
+	if value.Type != "Polygon" {
+		return fmt.Errorf("%w: type must be Polygon, got %q", ErrInvalidGeoPolygon, value.Type)
+	}
+
+	p.Rings = make([][]GeoPoint, 0, len(value.Coordinates))
+	for _, positions := range value.Coordinates {
+		ring := make([]GeoPoint, 0, len(positions)+1)
+		for _, position := range positions {
+			ring = append(ring, GeoPoint{Lat: position[1], Lng: position[0]})
+		}
+		if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+			ring = append(ring, ring[0])
+		}
+		p.Rings = append(p.Rings, ring)
+	}
+
+	return p.Validate()
+}
+
+func (p GeoPolygon) Validate() error {
+	if len(p.Rings) == 0 {
+		return fmt.Errorf("%w: polygon has no rings", ErrInvalidGeoPolygon)
+	}
+
+	for _, ring := range p.Rings {
+		// três vértices mais a posição que fecha o anel
+		if len(ring) < 4 {
+			return fmt.Errorf("%w: rings need at least 3 distinct positions", ErrInvalidGeoPolygon)
+		}
+		for _, point := range ring {
+			if point.Lat < -90 || point.Lat > 90 || point.Lng < -180 || point.Lng > 180 {
+				return fmt.Errorf("%w: position [%g, %g] is out of range", ErrInvalidGeoPolygon, point.Lng, point.Lat)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Contains diz se o ponto está dentro do contorno e fora dos buracos
+func (p GeoPolygon) Contains(point GeoPoint) bool {
+	if len(p.Rings) == 0 || !ringContains(p.Rings[0], point) {
+		return false
+	}
+
+	for _, hole := range p.Rings[1:] {
+		if ringContains(hole, point) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains é o teste do raio: conta quantas arestas uma semirreta a partir do ponto cruza.
+// Nas distâncias de uma entrega tratar latitude e longitude como plano não faz diferença
+func ringContains(ring []GeoPoint, point GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > point.Lat) != (b.Lat > point.Lat) &&
+			point.Lng < (b.Lng-a.Lng)*(point.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
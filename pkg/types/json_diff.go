@@ -0,0 +1,61 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type JsonDiff map[string]FieldChange
+
+// NewJsonDiff compara a representação JSON de dois valores e retorna apenas os campos alterados.
+// before ou after podem ser nil (criação e remoção, respectivamente).
+func NewJsonDiff(before, after any) (JsonDiff, error) {
+	beforeFields, err := toJsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := toJsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(JsonDiff)
+	for key, beforeValue := range beforeFields {
+		afterValue, ok := afterFields[key]
+		if !ok || !reflect.DeepEqual(beforeValue, afterValue) {
+			diff[key] = FieldChange{Before: beforeValue, After: afterValue}
+		}
+	}
+
+	for key, afterValue := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			diff[key] = FieldChange{After: afterValue}
+		}
+	}
+
+	return diff, nil
+}
+
+func toJsonFields(value any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Pointer && reflect.ValueOf(value).IsNil()) {
+		return fields, nil
+	}
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
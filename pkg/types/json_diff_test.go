@@ -0,0 +1,46 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/PedroNetto404/marmitech-backend/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type priced struct {
+	Name       string `json:"name"`
+	SalesPrice string `json:"sales_price"`
+}
+
+func TestJsonDiffOnlyChangedFields(t *testing.T) {
+	// arrange
+	before := priced{Name: "Marmita P", SalesPrice: "18.00"}
+	after := priced{Name: "Marmita P", SalesPrice: "19.50"}
+
+	// act
+	diff, err := types.NewJsonDiff(before, after)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(err)
+	assert.Len(diff, 1, "apenas o preço mudou")
+	assert.Equal("18.00", diff["sales_price"].Before)
+	assert.Equal("19.50", diff["sales_price"].After)
+}
+
+func TestJsonDiffCreation(t *testing.T) {
+	// arrange
+	after := &priced{Name: "Marmita G", SalesPrice: "25.00"}
+
+	// act
+	diff, err := types.NewJsonDiff(nil, after)
+
+	// assert
+	assert := assert.New(t)
+
+	assert.NoError(err)
+	assert.Len(diff, 2, "todos os campos devem aparecer na criação")
+	assert.Nil(diff["name"].Before)
+	assert.Equal("Marmita G", diff["name"].After)
+}